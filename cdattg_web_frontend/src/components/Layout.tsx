@@ -8,6 +8,7 @@ import {
   UserGroupIcon,
   UsersIcon,
   ClipboardDocumentListIcon,
+  ClipboardDocumentCheckIcon,
   ChartBarIcon,
   CubeIcon,
   ShoppingCartIcon,
@@ -19,8 +20,10 @@ import {
   ShieldCheckIcon,
   ExclamationTriangleIcon,
   CalendarDaysIcon,
+  QrCodeIcon,
   BuildingOffice2Icon,
   EyeIcon,
+  EnvelopeIcon,
 } from '@heroicons/react/24/outline';
 import { AppBreadcrumb } from './navigation/AppBreadcrumb';
 import { useAuth } from '../context/AuthContext';
@@ -33,6 +36,7 @@ import { ChangePasswordModal } from './layout/ChangePasswordModal';
 import { LayoutBrandLink } from './layout/LayoutBrandLink';
 import { LayoutSidebar, sectionForPathname } from './layout/LayoutSidebar';
 import { LayoutUserMenu } from './layout/LayoutUserMenu';
+import { LayoutNotificacionesMenu } from './layout/LayoutNotificacionesMenu';
 
 interface LayoutProps {
   children: ReactNode;
@@ -56,8 +60,11 @@ const ICONS: Record<string, ReactNode> = {
   asistencia: <ClipboardDocumentListIcon className="w-5 h-5" />,
   'asistencia/historial': <CalendarDaysIcon className="w-5 h-5" />,
   'asistencia/mis-inasistencias': <CalendarDaysIcon className="w-5 h-5" />,
+  'asistencia/mi-asistencia': <QrCodeIcon className="w-5 h-5" />,
   'asistencia/dashboard': <ChartBarIcon className="w-5 h-5" />,
   'bienestar/casos': <ExclamationTriangleIcon className="w-5 h-5" />,
+  'bienestar/seguimiento': <ClipboardDocumentCheckIcon className="w-5 h-5" />,
+  'bienestar/riesgo': <ChartBarIcon className="w-5 h-5" />,
   'asistencia/tipos-observacion': <ClipboardDocumentListIcon className="w-5 h-5" />,
   inventario: <CubeIcon className="w-5 h-5" />,
   'inventario/dashboard': <CubeIcon className="w-5 h-5" />,
@@ -69,6 +76,7 @@ const ICONS: Record<string, ReactNode> = {
   'administracion/jornadas': <SunIcon className="w-5 h-5" />,
   'administracion/dias-sin-formacion': <CalendarDaysIcon className="w-5 h-5" />,
   'administracion/configuracion-asistencia': <ClipboardDocumentListIcon className="w-5 h-5" />,
+  'administracion/correos': <EnvelopeIcon className="w-5 h-5" />,
   'administracion/elecciones': <UserGroupIcon className="w-5 h-5" />,
   'eleccion/aprendiz': <UserGroupIcon className="w-5 h-5" />,
   'infraestructura/sedes': <BuildingOffice2Icon className="w-5 h-5" />,
@@ -79,11 +87,12 @@ const ICONS: Record<string, ReactNode> = {
 };
 
 export const Layout = ({ children }: LayoutProps) => {
-  const { user, logout, hasPermission, roles, permissions } = useAuth();
+  const { user, logout, hasPermission, roles, permissions, refreshUser } = useAuth();
   const { theme, toggleTheme } = useTheme();
   const navigate = useNavigate();
   const location = useLocation();
-  const changePassword = useChangePassword();
+  const changePassword = useChangePassword(refreshUser);
+  const cambioPasswordObligatorio = Boolean(user?.debe_cambiar_password);
   const [sidebarOpen, setSidebarOpen] = useState(false);
   const [sidebarHidden, setSidebarHidden] = useState(readSidebarHidden);
 
@@ -96,6 +105,11 @@ export const Layout = ({ children }: LayoutProps) => {
     sectionForPathname(location.pathname, visibleItems),
   );
 
+  const { open: changePasswordOpen, openModal: openChangePassword } = changePassword;
+  useEffect(() => {
+    if (cambioPasswordObligatorio && !changePasswordOpen) openChangePassword();
+  }, [cambioPasswordObligatorio, changePasswordOpen, openChangePassword]);
+
   useEffect(() => {
     globalThis.window.localStorage.setItem(SIDEBAR_HIDDEN_KEY, String(sidebarHidden));
   }, [sidebarHidden]);
@@ -195,6 +209,9 @@ export const Layout = ({ children }: LayoutProps) => {
                 )}
               </button>
             </li>
+            <li className="nav-item">
+              <LayoutNotificacionesMenu />
+            </li>
             <li className="nav-item">
               <LayoutUserMenu
                 userName={user?.full_name}
@@ -217,6 +234,7 @@ export const Layout = ({ children }: LayoutProps) => {
         error={changePassword.error}
         success={changePassword.success}
         loading={changePassword.loading}
+        obligatorio={cambioPasswordObligatorio}
         onClose={changePassword.closeModal}
         onSubmit={changePassword.handleSubmit}
         onPasswordActualChange={changePassword.setPasswordActual}
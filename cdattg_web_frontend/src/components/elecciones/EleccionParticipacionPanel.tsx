@@ -0,0 +1,257 @@
+import { useCallback, useEffect, useState } from 'react';
+import { BellAlertIcon, ChartBarIcon } from '@heroicons/react/24/outline';
+import { apiService } from '../../services/api';
+import type {
+  EleccionParticipacion,
+  EleccionParticipacionGrupo,
+  EleccionRecordatorioCanal,
+  EleccionRecordatorioResponse,
+} from '../../types/eleccion';
+import { formatFechaHoraVista } from '../../utils/formatFecha';
+import { axiosErrorMessage } from '../../utils/httpError';
+
+/** Cada cuánto se refresca la participación mientras la votación sigue abierta. */
+const REFRESCO_MS = 60_000;
+
+type Agrupacion = 'por_sede' | 'por_programa' | 'por_ficha';
+
+const AGRUPACION_LABEL: Record<Agrupacion, string> = {
+  por_sede: 'Sede',
+  por_programa: 'Programa',
+  por_ficha: 'Ficha',
+};
+
+const CANAL_LABEL: Record<EleccionRecordatorioCanal, string> = {
+  notificacion: 'Notificación en la aplicación',
+  correo: 'Correo electrónico',
+};
+
+function resumenRecordatorio(r: EleccionRecordatorioResponse): string {
+  if (r.pendientes === 0) return 'Todos los aprendices habilitados ya votaron.';
+  const partes = [`${r.pendientes} pendientes`, `${r.notificados} notificados en la aplicación`, `${r.correos} correos encolados`];
+  if (r.sin_correo > 0) partes.push(`${r.sin_correo} sin correo válido`);
+  const aviso = r.correo_habilitado ? '' : ' El envío de correos está deshabilitado en el servidor.';
+  return `Recordatorio enviado: ${partes.join(' · ')}.${aviso}`;
+}
+
+type GruposTablaProps = Readonly<{ grupos: EleccionParticipacionGrupo[]; etiqueta: string }>;
+
+function GruposTabla({ grupos, etiqueta }: GruposTablaProps) {
+  if (grupos.length === 0) {
+    return <p className="py-4 text-center text-sm text-gray-500">Sin aprendices habilitados.</p>;
+  }
+  return (
+    <div className="max-h-80 overflow-auto">
+      <table className="min-w-full divide-y divide-gray-100 text-sm dark:divide-gray-700">
+        <thead className="sticky top-0 bg-gray-50 text-left text-xs font-semibold uppercase text-gray-500 dark:bg-gray-900">
+          <tr>
+            <th className="px-3 py-2">{etiqueta}</th>
+            <th className="px-3 py-2 text-right">Votaron</th>
+            <th className="px-3 py-2 text-right">Elegibles</th>
+            <th className="w-1/3 px-3 py-2">Participación</th>
+          </tr>
+        </thead>
+        <tbody className="divide-y divide-gray-50 dark:divide-gray-700/80">
+          {grupos.map((g) => (
+            <tr key={g.id}>
+              <td className="px-3 py-2 text-gray-800 dark:text-gray-200">{g.nombre}</td>
+              <td className="px-3 py-2 text-right tabular-nums">{g.votaron}</td>
+              <td className="px-3 py-2 text-right tabular-nums">{g.elegibles}</td>
+              <td className="px-3 py-2">
+                <div className="flex items-center gap-2">
+                  <div className="h-2 flex-1 overflow-hidden rounded-full bg-gray-100 dark:bg-gray-700">
+                    <div className="h-full rounded-full bg-primary-500" style={{ width: `${g.participacion_pct}%` }} />
+                  </div>
+                  <span className="w-12 text-right text-xs tabular-nums text-gray-600 dark:text-gray-400">
+                    {g.participacion_pct.toFixed(1)}%
+                  </span>
+                </div>
+              </td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    </div>
+  );
+}
+
+type VotosPorHoraProps = Readonly<{ horas: EleccionParticipacion['por_hora'] }>;
+
+function VotosPorHora({ horas }: VotosPorHoraProps) {
+  if (horas.length === 0) {
+    return <p className="text-sm text-gray-500">Aún no hay votos registrados.</p>;
+  }
+  const max = Math.max(...horas.map((h) => h.votos), 1);
+  return (
+    <div className="flex h-32 items-end gap-1 overflow-x-auto" role="img" aria-label="Votos por hora">
+      {horas.map((h) => (
+        <div
+          key={h.hora}
+          className="flex min-w-[18px] flex-1 flex-col items-center justify-end"
+          title={`${formatFechaHoraVista(h.hora)}: ${h.votos} votos (acumulado ${h.acumulado})`}
+        >
+          <div className="w-full rounded-t bg-primary-400 dark:bg-primary-600" style={{ height: `${(h.votos / max) * 100}%` }} />
+          <span className="mt-1 text-[10px] text-gray-500">{new Date(h.hora).getHours()}h</span>
+        </div>
+      ))}
+    </div>
+  );
+}
+
+type EleccionParticipacionPanelProps = Readonly<{ procesoId: number; votacionAbierta: boolean }>;
+
+/** Participación en vivo del proceso y envío de recordatorios a quienes aún no votan. */
+export function EleccionParticipacionPanel({ procesoId, votacionAbierta }: EleccionParticipacionPanelProps) {
+  const [data, setData] = useState<EleccionParticipacion | null>(null);
+  const [error, setError] = useState('');
+  const [agrupacion, setAgrupacion] = useState<Agrupacion>('por_sede');
+  const [canales, setCanales] = useState<EleccionRecordatorioCanal[]>(['notificacion', 'correo']);
+  const [enviando, setEnviando] = useState(false);
+  const [mensaje, setMensaje] = useState('');
+
+  const load = useCallback(async () => {
+    try {
+      setData(await apiService.getEleccionParticipacion(procesoId));
+      setError('');
+    } catch (e) {
+      setError(axiosErrorMessage(e, 'No se pudo cargar la participación.'));
+    }
+  }, [procesoId]);
+
+  useEffect(() => {
+    void load();
+    if (!votacionAbierta) return;
+    const id = globalThis.setInterval(() => void load(), REFRESCO_MS);
+    return () => globalThis.clearInterval(id);
+  }, [load, votacionAbierta]);
+
+  const toggleCanal = (canal: EleccionRecordatorioCanal) =>
+    setCanales((prev) => (prev.includes(canal) ? prev.filter((c) => c !== canal) : [...prev, canal]));
+
+  const enviarRecordatorio = async () => {
+    if (!data || !globalThis.confirm(`¿Enviar el recordatorio a los ${data.pendientes} aprendices que aún no votan?`)) return;
+    setEnviando(true);
+    setMensaje('');
+    setError('');
+    try {
+      const res = await apiService.eleccionEnviarRecordatorioVoto(procesoId, canales);
+      setMensaje(resumenRecordatorio(res));
+      await load();
+    } catch (e) {
+      setError(axiosErrorMessage(e, 'No se pudo enviar el recordatorio.'));
+    } finally {
+      setEnviando(false);
+    }
+  };
+
+  return (
+    <section className="rounded-xl border border-gray-200 bg-white p-4 dark:border-gray-700 dark:bg-gray-800">
+      <div className="flex flex-wrap items-start justify-between gap-3">
+        <div className="flex items-center gap-2">
+          <ChartBarIcon className="h-5 w-5 text-primary-600 dark:text-primary-400" aria-hidden />
+          <h2 className="font-semibold text-gray-900 dark:text-white">Participación</h2>
+        </div>
+        {data ? (
+          <p className="text-xs text-gray-500">
+            Actualizado {formatFechaHoraVista(data.actualizado_at)}
+            {votacionAbierta ? ' · se actualiza cada minuto' : ''}
+          </p>
+        ) : null}
+      </div>
+      <p className="mt-1 text-xs text-gray-500 dark:text-gray-400">
+        Solo se cuenta quién votó; el voto es secreto y no se muestra por quién.
+      </p>
+
+      {error ? (
+        <p className="mt-3 rounded-lg bg-red-50 px-3 py-2 text-sm text-red-700 dark:bg-red-950/40 dark:text-red-300">{error}</p>
+      ) : null}
+
+      {data ? (
+        <>
+          <div className="mt-4 grid gap-3 sm:grid-cols-4">
+            <div>
+              <p className="text-xs text-gray-500">Votaron</p>
+              <p className="text-xl font-bold tabular-nums text-gray-900 dark:text-white">{data.votaron}</p>
+            </div>
+            <div>
+              <p className="text-xs text-gray-500">Elegibles</p>
+              <p className="text-xl font-bold tabular-nums text-gray-900 dark:text-white">{data.elegibles}</p>
+            </div>
+            <div>
+              <p className="text-xs text-gray-500">Pendientes</p>
+              <p className="text-xl font-bold tabular-nums text-gray-900 dark:text-white">{data.pendientes}</p>
+            </div>
+            <div>
+              <p className="text-xs text-gray-500">Participación</p>
+              <p className="text-xl font-bold tabular-nums text-gray-900 dark:text-white">
+                {data.participacion_pct.toFixed(1)}%
+              </p>
+            </div>
+          </div>
+          {data.votantes_fuera_de_padron > 0 ? (
+            <p className="mt-2 text-xs text-gray-500">
+              {data.votantes_fuera_de_padron} votante(s) ya no figuran como aprendices activos de la regional.
+            </p>
+          ) : null}
+
+          <h3 className="mt-5 text-sm font-semibold text-gray-900 dark:text-white">Votos por hora</h3>
+          <div className="mt-2">
+            <VotosPorHora horas={data.por_hora} />
+          </div>
+
+          <div className="mt-5 flex flex-wrap gap-2" role="tablist">
+            {(Object.keys(AGRUPACION_LABEL) as Agrupacion[]).map((a) => (
+              <button
+                key={a}
+                type="button"
+                role="tab"
+                aria-selected={agrupacion === a}
+                className={`rounded-full px-3 py-1 text-xs font-medium ${
+                  agrupacion === a
+                    ? 'bg-primary-100 text-primary-800 dark:bg-primary-900/50 dark:text-primary-200'
+                    : 'bg-gray-100 text-gray-600 dark:bg-gray-700 dark:text-gray-300'
+                }`}
+                onClick={() => setAgrupacion(a)}
+              >
+                Por {AGRUPACION_LABEL[a].toLowerCase()}
+              </button>
+            ))}
+          </div>
+          <div className="mt-2">
+            <GruposTabla grupos={data[agrupacion]} etiqueta={AGRUPACION_LABEL[agrupacion]} />
+          </div>
+
+          {votacionAbierta ? (
+            <div className="mt-5 rounded-lg border border-gray-100 p-3 dark:border-gray-700">
+              <div className="flex items-center gap-2">
+                <BellAlertIcon className="h-5 w-5 text-gray-500" aria-hidden />
+                <h3 className="text-sm font-semibold text-gray-900 dark:text-white">Recordar votar</h3>
+              </div>
+              <p className="mt-1 text-xs text-gray-500">
+                Se envía a los aprendices habilitados que aún no votan, como máximo una vez cada 6 horas.
+                {data.ultimo_recordatorio_at ? ` Último envío: ${formatFechaHoraVista(data.ultimo_recordatorio_at)}.` : ''}
+              </p>
+              <div className="mt-3 flex flex-wrap items-center gap-4">
+                {(Object.keys(CANAL_LABEL) as EleccionRecordatorioCanal[]).map((canal) => (
+                  <label key={canal} className="inline-flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                    <input type="checkbox" checked={canales.includes(canal)} onChange={() => toggleCanal(canal)} />
+                    {CANAL_LABEL[canal]}
+                  </label>
+                ))}
+                <button
+                  type="button"
+                  className="btn-secondary"
+                  disabled={enviando || canales.length === 0 || data.pendientes === 0}
+                  onClick={() => void enviarRecordatorio()}
+                >
+                  {enviando ? 'Enviando…' : 'Enviar recordatorio'}
+                </button>
+              </div>
+              {mensaje ? <p className="mt-2 text-sm text-green-700 dark:text-green-400">{mensaje}</p> : null}
+            </div>
+          ) : null}
+        </>
+      ) : null}
+    </section>
+  );
+}
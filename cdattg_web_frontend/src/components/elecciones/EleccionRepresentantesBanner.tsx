@@ -14,10 +14,13 @@ export function EleccionRepresentantesBanner() {
   }, []);
 
   const rep = data?.representantes_vigentes;
-  const proceso = data?.proceso;
-  if (!rep && !proceso) return null;
+  const voceros = data?.voceros_vigentes;
+  const proceso = [data?.proceso, ...(data?.otros_procesos ?? []).map((p) => p.proceso)].find(
+    (p) => p && p.estado !== 'cerrada',
+  );
+  if (!rep && !voceros && !data?.proceso) return null;
 
-  const procesoActivo = proceso && proceso.estado !== 'cerrada';
+  const procesoActivo = Boolean(proceso);
 
   return (
     <div className="rounded-xl border border-primary-200 bg-primary-50/60 p-4 dark:border-primary-800 dark:bg-primary-950/25">
@@ -34,7 +37,13 @@ export function EleccionRepresentantesBanner() {
             ) : (
               <p className="mt-1 text-sm text-gray-600 dark:text-gray-400">Sin representantes vigentes registrados.</p>
             )}
-            {procesoActivo ? (
+            {voceros ? (
+              <p className="text-sm text-gray-800 dark:text-gray-200">
+                {voceros.cargo_titular ?? 'Vocero'}: <strong>{voceros.titular.nombre}</strong> ·{' '}
+                {voceros.cargo_suplente ?? 'Vocero suplente'}: <strong>{voceros.suplente.nombre}</strong>
+              </p>
+            ) : null}
+            {proceso ? (
               <p className="mt-1 text-xs text-primary-800 dark:text-primary-300">
                 Proceso en curso: {proceso.nombre_ciclo} ({proceso.estado})
               </p>
@@ -0,0 +1,127 @@
+import type { ReactNode } from 'react';
+import { ArrowDownTrayIcon, ArrowPathIcon, ChartBarIcon } from '@heroicons/react/24/outline';
+import type { ImportacionTrabajo } from '../../types';
+
+type Props = Readonly<{
+  trabajo: ImportacionTrabajo;
+  /** Texto del contador de procesados (p. ej. "creados", "agregados a la ficha"). */
+  etiquetaProcesados?: string;
+  /** Unidad del avance: "Fila" o, en programas, "Programa". */
+  unidad?: string;
+  /** Datos propios de la importación que se muestran al terminar. */
+  detalles?: ReactNode;
+  reanudando?: boolean;
+  onReanudar: () => void;
+  onDescargarIncidencias: () => void;
+}>;
+
+/** Avance, resultado o error de una importación en segundo plano, con reanudar y reporte de incidencias. */
+export function ImportacionProgreso({
+  trabajo,
+  etiquetaProcesados = 'creados',
+  unidad = 'Fila',
+  detalles,
+  reanudando = false,
+  onReanudar,
+  onDescargarIncidencias,
+}: Props) {
+  const enCurso = trabajo.estado === 'pendiente' || trabajo.estado === 'procesando';
+  const contadores = (
+    <div className="mt-3 grid grid-cols-2 sm:grid-cols-4 gap-3 text-sm">
+      <div className="rounded-lg bg-green-100 dark:bg-green-900/40 px-3 py-2">
+        <span className="text-green-800 dark:text-green-200 font-semibold">{trabajo.procesados}</span>
+        <span className="text-green-700 dark:text-green-300 ml-1">{etiquetaProcesados}</span>
+      </div>
+      <div className="rounded-lg bg-amber-100 dark:bg-amber-900/40 px-3 py-2">
+        <span className="text-amber-800 dark:text-amber-200 font-semibold">{trabajo.duplicados}</span>
+        <span className="text-amber-700 dark:text-amber-300 ml-1">duplicados</span>
+      </div>
+      <div className="rounded-lg bg-red-100 dark:bg-red-900/40 px-3 py-2">
+        <span className="text-red-800 dark:text-red-200 font-semibold">{trabajo.errores}</span>
+        <span className="text-red-700 dark:text-red-300 ml-1">errores</span>
+      </div>
+      <div className="rounded-lg bg-gray-200 dark:bg-gray-600 px-3 py-2">
+        <span className="text-gray-800 dark:text-gray-200 font-semibold">{trabajo.fila_actual}</span>
+        <span className="text-gray-600 dark:text-gray-400 ml-1">revisados</span>
+      </div>
+    </div>
+  );
+  const botonIncidencias = !enCurso && trabajo.incidencias > 0 && (
+    <button
+      type="button"
+      onClick={onDescargarIncidencias}
+      className="mt-3 btn-secondary inline-flex items-center gap-2 text-sm"
+    >
+      <ArrowDownTrayIcon className="w-4 h-4" aria-hidden />
+      Descargar incidencias ({trabajo.incidencias})
+    </button>
+  );
+
+  if (enCurso) {
+    return (
+      <div
+        role="status"
+        aria-live="polite"
+        aria-busy="true"
+        className="mt-4 p-4 rounded-lg bg-gray-50 dark:bg-gray-700/50 border border-gray-200 dark:border-gray-600"
+      >
+        <div className="flex items-center gap-2 text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+          <ChartBarIcon className="w-5 h-5 text-primary-600" aria-hidden />
+          {trabajo.estado === 'pendiente' ? 'Importación en cola' : 'Progreso de la importación'}
+        </div>
+        <progress
+          className="w-full h-3 rounded-full overflow-hidden accent-primary-600"
+          max={100}
+          value={trabajo.porcentaje}
+          aria-label="Avance del procesamiento"
+        />
+        <p className="mt-2 text-xs text-gray-500 dark:text-gray-400">
+          {unidad} {trabajo.fila_actual} de {trabajo.total_filas} ({trabajo.porcentaje}%). Puede salir de esta página: la
+          importación continúa y recibirá una notificación al terminar.
+        </p>
+        {contadores}
+      </div>
+    );
+  }
+
+  if (trabajo.estado === 'fallido') {
+    return (
+      <div
+        role="alert"
+        className="mt-3 p-4 rounded-lg bg-red-50 dark:bg-red-900/30 border border-red-200 dark:border-red-800 text-sm"
+      >
+        <p className="font-medium text-red-800 dark:text-red-200">
+          La importación se detuvo en {unidad.toLowerCase()} {trabajo.fila_actual} de {trabajo.total_filas}
+        </p>
+        {trabajo.ultimo_error && <p className="mt-1 text-red-700 dark:text-red-300">{trabajo.ultimo_error}</p>}
+        {contadores}
+        <div className="flex flex-wrap gap-2">
+          {trabajo.reanudable && (
+            <button
+              type="button"
+              onClick={onReanudar}
+              disabled={reanudando}
+              className="mt-3 btn-primary inline-flex items-center gap-2 text-sm"
+            >
+              <ArrowPathIcon className={`w-4 h-4 ${reanudando ? 'animate-spin' : ''}`} aria-hidden />
+              {reanudando ? 'Reanudando...' : 'Reanudar desde donde quedó'}
+            </button>
+          )}
+          {botonIncidencias}
+        </div>
+      </div>
+    );
+  }
+
+  return (
+    <output
+      aria-live="polite"
+      className="mt-3 block w-full p-4 rounded-lg bg-green-50 dark:bg-green-900/30 border border-green-200 dark:border-green-800"
+    >
+      <p className="text-sm font-medium text-green-800 dark:text-green-200">Importación finalizada: {trabajo.filename}</p>
+      {contadores}
+      {detalles && <div className="mt-3 text-sm text-green-800 dark:text-green-200 space-y-1">{detalles}</div>}
+      {botonIncidencias}
+    </output>
+  );
+}
@@ -0,0 +1,102 @@
+import { useCallback, useEffect, useRef, useState } from 'react';
+import { useSearchParams } from 'react-router-dom';
+import { apiService } from '../../services/api';
+import { axiosErrorMessage } from '../../utils/httpError';
+import type { ImportacionTrabajo } from '../../types';
+
+const PARAM_TRABAJO = 'trabajo';
+
+/**
+ * Importación en segundo plano de la página. El trabajo seguido queda en ?trabajo=<id>, así el avance sobrevive a
+ * recargar la página y los enlaces de las notificaciones abren la importación correspondiente.
+ */
+export function useImportacionTrabajo(onTerminada?: (t: ImportacionTrabajo) => void) {
+  const [searchParams, setSearchParams] = useSearchParams();
+  const trabajoId = Number(searchParams.get(PARAM_TRABAJO)) || null;
+  const [trabajo, setTrabajo] = useState<ImportacionTrabajo | null>(null);
+  const [error, setError] = useState('');
+  const [reanudando, setReanudando] = useState(false);
+  const [seguimiento, setSeguimiento] = useState(0);
+  const onTerminadaRef = useRef(onTerminada);
+  onTerminadaRef.current = onTerminada;
+
+  useEffect(() => {
+    if (!trabajoId) return;
+    const ctrl = new AbortController();
+    setError('');
+    apiService
+      .seguirImportacion(trabajoId, setTrabajo, ctrl.signal)
+      .then((t) => {
+        setTrabajo(t);
+        onTerminadaRef.current?.(t);
+      })
+      .catch((err: unknown) => {
+        if (!ctrl.signal.aborted) setError(axiosErrorMessage(err, 'No se pudo consultar la importación.'));
+      });
+    return () => ctrl.abort();
+  }, [trabajoId, seguimiento]);
+
+  /** Empieza a seguir un trabajo recién encolado. */
+  const seguir = useCallback(
+    (t: ImportacionTrabajo) => {
+      setTrabajo(t);
+      setSearchParams(
+        (prev) => {
+          const next = new URLSearchParams(prev);
+          next.set(PARAM_TRABAJO, String(t.id));
+          return next;
+        },
+        { replace: true }
+      );
+    },
+    [setSearchParams]
+  );
+
+  const limpiar = useCallback(() => {
+    setTrabajo(null);
+    setError('');
+    setSearchParams(
+      (prev) => {
+        const next = new URLSearchParams(prev);
+        next.delete(PARAM_TRABAJO);
+        return next;
+      },
+      { replace: true }
+    );
+  }, [setSearchParams]);
+
+  const reanudar = useCallback(async () => {
+    if (!trabajo) return;
+    setReanudando(true);
+    setError('');
+    try {
+      setTrabajo(await apiService.reanudarImportacion(trabajo.id));
+      setSeguimiento((n) => n + 1);
+    } catch (err: unknown) {
+      setError(axiosErrorMessage(err, 'No se pudo reanudar la importación.'));
+    } finally {
+      setReanudando(false);
+    }
+  }, [trabajo]);
+
+  const descargarIncidencias = useCallback(async () => {
+    if (!trabajo) return;
+    try {
+      const blob = await apiService.downloadIncidenciasImportacion(trabajo.id);
+      const url = URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `incidencias_importacion_${trabajo.id}.xlsx`;
+      document.body.appendChild(a);
+      a.click();
+      a.remove();
+      URL.revokeObjectURL(url);
+    } catch (err: unknown) {
+      setError(axiosErrorMessage(err, 'No se pudo descargar el reporte de incidencias.'));
+    }
+  }, [trabajo]);
+
+  const enCurso = trabajo?.estado === 'pendiente' || trabajo?.estado === 'procesando';
+
+  return { trabajo, enCurso, error, reanudando, seguir, limpiar, reanudar, descargarIncidencias };
+}
@@ -0,0 +1,64 @@
+import type { ReactNode } from 'react';
+import { MoonIcon, SunIcon } from '@heroicons/react/24/outline';
+import LogoSena from '../../../logo-sena-verde-complementario-svg-2022.svg';
+import { useTheme } from '../../context/ThemeContext';
+
+type AuthPageShellProps = Readonly<{
+  subtitle?: string;
+  children: ReactNode;
+}>;
+
+/** Marco de las páginas públicas de acceso (login, recuperar contraseña, verificar correo). */
+export function AuthPageShell({ subtitle = 'Sistema de Gestión SENA', children }: AuthPageShellProps) {
+  const { theme, toggleTheme } = useTheme();
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gradient-to-br from-primary-50 to-primary-100 dark:from-gray-900 dark:to-gray-800 py-12 px-4 sm:px-6 lg:px-8 relative">
+      <button
+        type="button"
+        onClick={toggleTheme}
+        className="absolute top-4 right-4 p-2 rounded-lg bg-white dark:bg-gray-700 shadow hover:bg-gray-100 dark:hover:bg-gray-600 transition-colors"
+        title={theme === 'light' ? 'Modo oscuro' : 'Modo claro'}
+        aria-label={theme === 'light' ? 'Cambiar a modo oscuro' : 'Cambiar a modo claro'}
+      >
+        {theme === 'light' ? (
+          <MoonIcon className="w-5 h-5 text-gray-700" aria-hidden />
+        ) : (
+          <SunIcon className="w-5 h-5 text-yellow-300" aria-hidden />
+        )}
+      </button>
+      <div className="max-w-md w-full space-y-8">
+        <div className="text-center">
+          <div className="flex justify-center">
+            <img
+              src={LogoSena}
+              alt="Logo SENA"
+              className="w-20 h-20 rounded-2xl shadow-lg"
+            />
+          </div>
+          <h2 className="mt-6 text-3xl font-extrabold text-gray-900 dark:text-white">
+            CDATTG Web
+          </h2>
+          <p className="mt-2 text-sm text-gray-600 dark:text-gray-400">
+            {subtitle}
+          </p>
+        </div>
+        <div className="card">{children}</div>
+      </div>
+    </div>
+  );
+}
+
+type AuthAlertProps = Readonly<{ tipo: 'error' | 'ok'; children: ReactNode }>;
+
+export function AuthAlert({ tipo, children }: AuthAlertProps) {
+  const clases =
+    tipo === 'error'
+      ? 'bg-red-50 dark:bg-red-900/30 border-red-200 dark:border-red-800 text-red-700 dark:text-red-300'
+      : 'bg-green-50 dark:bg-green-900/30 border-green-200 dark:border-green-800 text-green-700 dark:text-green-300';
+  return (
+    <div role={tipo === 'error' ? 'alert' : 'status'} className={`border px-4 py-3 rounded-lg ${clases}`}>
+      {children}
+    </div>
+  );
+}
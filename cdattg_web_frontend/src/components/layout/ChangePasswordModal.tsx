@@ -9,6 +9,8 @@ type ChangePasswordModalProps = Readonly<{
   error: string;
   success: string;
   loading: boolean;
+  /** Cambio exigido por el sistema (contraseña por defecto o restablecida): no se puede cerrar sin guardar. */
+  obligatorio?: boolean;
   onClose: () => void;
   onSubmit: NonNullable<ComponentProps<'form'>['onSubmit']>;
   onPasswordActualChange: (value: string) => void;
@@ -24,6 +26,7 @@ export function ChangePasswordModal({
   error,
   success,
   loading,
+  obligatorio = false,
   onClose,
   onSubmit,
   onPasswordActualChange,
@@ -34,12 +37,16 @@ export function ChangePasswordModal({
 
   return (
     <div className="fixed inset-0 z-50 flex items-center justify-center p-4">
-      <button
-        type="button"
-        className="absolute inset-0 bg-black/50"
-        aria-label="Cerrar ventana de cambio de contraseña"
-        onClick={onClose}
-      />
+      {obligatorio ? (
+        <div className="absolute inset-0 bg-black/50" aria-hidden />
+      ) : (
+        <button
+          type="button"
+          className="absolute inset-0 bg-black/50"
+          aria-label="Cerrar ventana de cambio de contraseña"
+          onClick={onClose}
+        />
+      )}
       <dialog
         open
         className="relative z-10 m-0 flex w-full max-w-md flex-col rounded-lg border border-gray-200 bg-white p-6 shadow-xl dark:border-gray-600 dark:bg-gray-800"
@@ -53,15 +60,22 @@ export function ChangePasswordModal({
             <KeyIcon className="h-6 w-6" />
             Cambiar contraseña
           </h2>
-          <button
-            type="button"
-            onClick={onClose}
-            className="rounded p-1 text-gray-500 hover:text-gray-700 dark:hover:text-gray-300"
-            aria-label="Cerrar"
-          >
-            <XMarkIcon className="h-6 w-6" />
-          </button>
+          {obligatorio ? null : (
+            <button
+              type="button"
+              onClick={onClose}
+              className="rounded p-1 text-gray-500 hover:text-gray-700 dark:hover:text-gray-300"
+              aria-label="Cerrar"
+            >
+              <XMarkIcon className="h-6 w-6" />
+            </button>
+          )}
         </div>
+        {obligatorio ? (
+          <p className="mb-4 rounded-lg border border-amber-200 bg-amber-50 px-4 py-3 text-sm text-amber-800 dark:border-amber-800 dark:bg-amber-900/30 dark:text-amber-300">
+            Su cuenta tiene una contraseña asignada por el sistema (su número de documento). Debe cambiarla para continuar.
+          </p>
+        ) : null}
         <form onSubmit={onSubmit} className="space-y-4">
           {error ? (
             <div className="rounded-lg border border-red-200 bg-red-50 px-4 py-3 text-sm text-red-700 dark:border-red-800 dark:bg-red-900/30 dark:text-red-300">
@@ -121,9 +135,11 @@ export function ChangePasswordModal({
             />
           </div>
           <div className="flex justify-end gap-2 pt-2">
-            <button type="button" onClick={onClose} className="btn-secondary">
-              Cancelar
-            </button>
+            {obligatorio ? null : (
+              <button type="button" onClick={onClose} className="btn-secondary">
+                Cancelar
+              </button>
+            )}
             <button type="submit" className="btn-primary" disabled={loading}>
               {loading ? 'Guardando...' : 'Cambiar contraseña'}
             </button>
@@ -0,0 +1,130 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useNavigate } from 'react-router-dom';
+import { BellIcon } from '@heroicons/react/24/outline';
+import { PERFIL_PATH } from '../../routes/paths';
+import type { NotificacionUsuario } from '../../types';
+import { formatFechaHoraVista } from '../../utils/formatFecha';
+import { useNotificaciones } from './useNotificaciones';
+
+function badgeNoLeidas(n: number): string {
+  return n > 99 ? '99+' : String(n);
+}
+
+/** Campana del header con las últimas notificaciones del usuario. */
+export function LayoutNotificacionesMenu() {
+  const navigate = useNavigate();
+  const { items, noLeidas, loading, marcarLeida, marcarTodasLeidas } = useNotificaciones();
+  const [open, setOpen] = useState(false);
+  const rootRef = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    if (!open) return;
+    const onPointerDown = (event: MouseEvent) => {
+      if (rootRef.current?.contains(event.target as Node)) return;
+      setOpen(false);
+    };
+    const onKeyDown = (event: KeyboardEvent) => {
+      if (event.key === 'Escape') setOpen(false);
+    };
+    document.addEventListener('mousedown', onPointerDown);
+    document.addEventListener('keydown', onKeyDown);
+    return () => {
+      document.removeEventListener('mousedown', onPointerDown);
+      document.removeEventListener('keydown', onKeyDown);
+    };
+  }, [open]);
+
+  const abrir = async (n: NotificacionUsuario) => {
+    setOpen(false);
+    if (!n.leida_en) {
+      try {
+        await marcarLeida(n.id);
+      } catch {
+        // ya leída en otra pestaña: el evento en vivo actualiza el conteo
+      }
+    }
+    if (n.enlace) navigate(n.enlace);
+  };
+
+  return (
+    <div ref={rootRef} className="relative">
+      <button
+        type="button"
+        className="nav-link relative flex min-h-[44px] min-w-[44px] touch-manipulation items-center justify-center px-3 py-2 text-gray-600 transition-colors hover:text-gray-900 dark:text-gray-300 dark:hover:text-white md:min-h-0 md:min-w-0"
+        aria-expanded={open}
+        aria-haspopup="menu"
+        aria-label={noLeidas > 0 ? `Notificaciones (${noLeidas} sin leer)` : 'Notificaciones'}
+        onClick={() => setOpen((prev) => !prev)}
+      >
+        <BellIcon className="h-5 w-5" />
+        {noLeidas > 0 ? (
+          <span className="absolute right-1 top-1 flex h-4 min-w-[1rem] items-center justify-center rounded-full bg-red-600 px-1 text-[10px] font-semibold leading-none text-white">
+            {badgeNoLeidas(noLeidas)}
+          </span>
+        ) : null}
+      </button>
+
+      {open ? (
+        <div
+          role="menu"
+          className="absolute right-0 z-50 mt-2 w-80 overflow-hidden rounded-xl border border-gray-200 bg-white shadow-lg dark:border-gray-600 dark:bg-gray-800 sm:w-96"
+        >
+          <div className="flex items-center justify-between border-b border-gray-100 px-4 py-3 dark:border-gray-700">
+            <p className="text-sm font-semibold text-gray-900 dark:text-white">Notificaciones</p>
+            {noLeidas > 0 ? (
+              <button
+                type="button"
+                className="text-xs font-medium text-primary-600 hover:text-primary-700 dark:text-primary-400"
+                onClick={() => void marcarTodasLeidas().catch(() => undefined)}
+              >
+                Marcar todas como leídas
+              </button>
+            ) : null}
+          </div>
+
+          <ul className="max-h-96 divide-y divide-gray-100 overflow-y-auto dark:divide-gray-700">
+            {items.length === 0 ? (
+              <li className="px-4 py-6 text-center text-sm text-gray-500 dark:text-gray-400">
+                {loading ? 'Cargando…' : 'No tiene notificaciones.'}
+              </li>
+            ) : (
+              items.map((n) => (
+                <li key={n.id}>
+                  <button
+                    type="button"
+                    role="menuitem"
+                    className={`block w-full px-4 py-3 text-left hover:bg-gray-50 dark:hover:bg-gray-700/60 ${
+                      n.leida_en ? '' : 'bg-primary-50/60 dark:bg-primary-900/20'
+                    }`}
+                    onClick={() => void abrir(n)}
+                  >
+                    <p className="flex items-start gap-2 text-sm font-medium text-gray-900 dark:text-white">
+                      {n.leida_en ? null : (
+                        <span className="mt-1.5 h-2 w-2 shrink-0 rounded-full bg-primary-600" aria-label="Sin leer" />
+                      )}
+                      <span>{n.titulo}</span>
+                    </p>
+                    {n.mensaje ? (
+                      <p className="mt-0.5 line-clamp-3 text-xs text-gray-600 dark:text-gray-300">{n.mensaje}</p>
+                    ) : null}
+                    <p className="mt-1 text-[11px] text-gray-400">{formatFechaHoraVista(n.created_at)}</p>
+                  </button>
+                </li>
+              ))
+            )}
+          </ul>
+
+          <div className="border-t border-gray-100 px-4 py-2 text-right dark:border-gray-700">
+            <Link
+              to={PERFIL_PATH}
+              className="text-xs font-medium text-gray-600 hover:text-primary-600 dark:text-gray-300 dark:hover:text-primary-400"
+              onClick={() => setOpen(false)}
+            >
+              Preferencias de notificación
+            </Link>
+          </div>
+        </div>
+      ) : null}
+    </div>
+  );
+}
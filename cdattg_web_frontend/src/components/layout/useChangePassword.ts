@@ -1,7 +1,8 @@
 import { type ComponentProps, useState } from 'react';
 import { apiService } from '../../services/api';
 
-export function useChangePassword() {
+/** onChanged se llama tras guardar la nueva contraseña (p. ej. para refrescar el usuario). */
+export function useChangePassword(onChanged?: () => Promise<void> | void) {
   const [open, setOpen] = useState(false);
   const [passwordActual, setPasswordActual] = useState('');
   const [passwordNueva, setPasswordNueva] = useState('');
@@ -48,6 +49,7 @@ export function useChangePassword() {
       });
       setSuccess('Contraseña actualizada correctamente.');
       resetFields();
+      await Promise.resolve(onChanged?.()).catch(() => undefined);
       setTimeout(closeModal, 1500);
     } catch (err: unknown) {
       const msg =
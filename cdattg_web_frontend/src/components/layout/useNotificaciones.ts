@@ -0,0 +1,102 @@
+import { useCallback, useEffect, useRef, useState } from 'react';
+import { getNotificacionesWsUrl } from '../../config/api';
+import { useAuth } from '../../context/AuthContext';
+import { apiService } from '../../services/api';
+import type { NotificacionEvento, NotificacionUsuario } from '../../types';
+
+const RECONEXION_MS = 5000;
+const TAMANO_LISTA = 10;
+
+/** Centro de notificaciones del header: últimas notificaciones, conteo de no leídas y eventos en vivo. */
+export function useNotificaciones() {
+  const { token } = useAuth();
+  const [items, setItems] = useState<NotificacionUsuario[]>([]);
+  const [noLeidas, setNoLeidas] = useState(0);
+  const [loading, setLoading] = useState(false);
+  const montado = useRef(true);
+
+  const recargar = useCallback(async () => {
+    setLoading(true);
+    try {
+      const res = await apiService.getNotificaciones({ page: 1, page_size: TAMANO_LISTA });
+      if (!montado.current) return;
+      setItems(res.data ?? []);
+      setNoLeidas(res.no_leidas);
+    } catch {
+      // la campana queda con el último estado conocido
+    } finally {
+      if (montado.current) setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    montado.current = true;
+    return () => {
+      montado.current = false;
+    };
+  }, []);
+
+  useEffect(() => {
+    if (!token) return;
+    let ws: WebSocket | null = null;
+    let reconexion: ReturnType<typeof setTimeout> | undefined;
+    let cerrado = false;
+
+    const aplicar = (ev: NotificacionEvento) => {
+      setNoLeidas(ev.no_leidas);
+      if (ev.type === 'nueva') {
+        const nueva = ev.notificacion;
+        if (nueva) setItems((prev) => [nueva, ...prev.filter((n) => n.id !== nueva.id)].slice(0, TAMANO_LISTA));
+        else void recargar();
+        return;
+      }
+      const ahora = new Date().toISOString();
+      const ids = ev.ids ?? [];
+      setItems((prev) =>
+        prev.map((n) => (n.leida_en || (ids.length > 0 && !ids.includes(n.id)) ? n : { ...n, leida_en: ahora })),
+      );
+    };
+
+    const connect = () => {
+      ws = new WebSocket(getNotificacionesWsUrl(token));
+      // Al (re)conectar se recarga lo que haya llegado sin conexión.
+      ws.onopen = () => void recargar();
+      ws.onclose = () => {
+        ws = null;
+        if (!cerrado) reconexion = setTimeout(connect, RECONEXION_MS);
+      };
+      ws.onerror = () => {
+        ws?.close();
+      };
+      ws.onmessage = (event) => {
+        try {
+          aplicar(JSON.parse(event.data as string) as NotificacionEvento);
+        } catch {
+          // ignorar mensajes mal formados
+        }
+      };
+    };
+
+    connect();
+    return () => {
+      cerrado = true;
+      if (reconexion) clearTimeout(reconexion);
+      ws?.close();
+    };
+  }, [token, recargar]);
+
+  const marcarLeida = useCallback(async (id: number) => {
+    const restantes = await apiService.marcarNotificacionLeida(id);
+    setNoLeidas(restantes);
+    setItems((prev) => prev.map((n) => (n.id === id && !n.leida_en ? { ...n, leida_en: new Date().toISOString() } : n)));
+  }, []);
+
+  const marcarTodasLeidas = useCallback(async () => {
+    const restantes = await apiService.marcarTodasNotificacionesLeidas();
+    setNoLeidas(restantes);
+    const ahora = new Date().toISOString();
+    setItems((prev) => prev.map((n) => (n.leida_en ? n : { ...n, leida_en: ahora })));
+  }, []);
+
+  return { items, noLeidas, loading, recargar, marcarLeida, marcarTodasLeidas };
+}
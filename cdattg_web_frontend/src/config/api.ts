@@ -6,6 +6,18 @@ export function getAsistenciaDashboardWsUrl(token: string): string {
   return `${base}/asistencias/dashboard/ws?token=${encodeURIComponent(token)}`;
 }
 
+/** URL del WebSocket con la lista en vivo de una sesión de asistencia (solo su instructor). */
+export function getAsistenciaSesionWsUrl(asistenciaId: number, token: string): string {
+  const base = API_BASE_URL.replace(/^https:/, 'wss:').replace(/^http:/, 'ws:');
+  return `${base}/asistencias/${asistenciaId}/ws?token=${encodeURIComponent(token)}`;
+}
+
+/** URL del WebSocket del centro de notificaciones del usuario autenticado. */
+export function getNotificacionesWsUrl(token: string): string {
+  const base = API_BASE_URL.replace(/^https:/, 'wss:').replace(/^http:/, 'ws:');
+  return `${base}/notificaciones/ws?token=${encodeURIComponent(token)}`;
+}
+
 export const API_ENDPOINTS = {
   auth: {
     login: '/auth/login',
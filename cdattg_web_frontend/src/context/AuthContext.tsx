@@ -1,7 +1,7 @@
 import { createContext, useContext, useState, useEffect, useMemo, useCallback } from 'react';
 import type { ReactNode } from 'react';
 import type { UserResponse, LoginRequest } from '../types';
-import { apiService } from '../services/api';
+import { apiService, clearStoredSession } from '../services/api';
 import { getHomeRouteForUser } from '../utils/roles';
 
 const ROLES_KEY = 'user_roles';
@@ -15,6 +15,8 @@ interface AuthContextType {
   hasPermission: (permission: string) => boolean;
   login: (credentials: LoginRequest) => Promise<string>;
   logout: () => void;
+  /** Vuelve a leer /auth/me (p. ej. tras cambiar la contraseña obligatoria). */
+  refreshUser: () => Promise<void>;
   isAuthenticated: boolean;
   loading: boolean;
 }
@@ -33,25 +35,32 @@ export const AuthProvider = ({ children }: { children: ReactNode }) => {
     [permissions],
   );
 
-  const logout = useCallback(() => {
+  const clearSession = useCallback(() => {
     setToken(null);
     setUser(null);
     setRoles([]);
     setPermissions([]);
-    localStorage.removeItem('token');
-    localStorage.removeItem('user');
+    clearStoredSession();
     localStorage.removeItem(ROLES_KEY);
     localStorage.removeItem(PERMISSIONS_KEY);
   }, []);
 
+  // Revoca la sesión en el servidor (si aún es válida) y limpia el estado local.
+  const logout = useCallback(() => {
+    if (localStorage.getItem('token')) {
+      apiService.logout().catch(() => undefined);
+    }
+    clearSession();
+  }, [clearSession]);
+
   // Cerrar sesión cuando el interceptor de API recibe 401 (sin recargar la página).
   useEffect(() => {
     const handleSessionExpired = () => {
-      logout();
+      clearSession();
     };
     globalThis.addEventListener('auth:session-expired', handleSessionExpired);
     return () => globalThis.removeEventListener('auth:session-expired', handleSessionExpired);
-  }, [logout]);
+  }, [clearSession]);
 
   useEffect(() => {
     const storedToken = localStorage.getItem('token');
@@ -87,12 +96,19 @@ export const AuthProvider = ({ children }: { children: ReactNode }) => {
     setRoles(nextRoles);
     setPermissions(nextPermissions);
     localStorage.setItem('token', response.token);
+    localStorage.setItem('refresh_token', response.refresh_token);
     localStorage.setItem('user', JSON.stringify(response.user));
     localStorage.setItem(ROLES_KEY, JSON.stringify(nextRoles));
     localStorage.setItem(PERMISSIONS_KEY, JSON.stringify(nextPermissions));
     return getHomeRouteForUser(nextRoles, nextPermissions);
   }, []);
 
+  const refreshUser = useCallback(async () => {
+    const currentUser = await apiService.getCurrentUser();
+    setUser(currentUser);
+    localStorage.setItem('user', JSON.stringify(currentUser));
+  }, []);
+
   const contextValue = useMemo(
     () => ({
       user,
@@ -102,10 +118,11 @@ export const AuthProvider = ({ children }: { children: ReactNode }) => {
       hasPermission,
       login,
       logout,
+      refreshUser,
       isAuthenticated: !!token,
       loading,
     }),
-    [user, token, roles, permissions, hasPermission, login, logout, loading],
+    [user, token, roles, permissions, hasPermission, login, logout, refreshUser, loading],
   );
 
   return (
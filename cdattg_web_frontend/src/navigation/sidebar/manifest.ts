@@ -45,6 +45,15 @@ export const SIDEBAR_MANIFEST: SidebarManifestItem[] = [
     alsoVisibleForPermissions: ['VER MIS INASISTENCIAS'],
     iconKey: 'asistencia/mis-inasistencias',
   },
+  {
+    section: 'Inicio',
+    path: aprendizPaths.miAsistencia,
+    label: 'Registrar mi asistencia',
+    permission: null,
+    rolesRequired: ['APRENDIZ'],
+    alsoVisibleForPermissions: ['REGISTRAR MI ASISTENCIA'],
+    iconKey: 'asistencia/mi-asistencia',
+  },
   {
     section: 'Inicio',
     path: eleccionAprendizPaths.index,
@@ -152,6 +161,22 @@ export const SIDEBAR_MANIFEST: SidebarManifestItem[] = [
     rolesRequired: ['SUPER ADMINISTRADOR', 'BIENESTAR AL APRENDIZ'],
     iconKey: 'bienestar/casos',
   },
+  {
+    section: 'Bienestar',
+    path: bienestarPaths.seguimiento.index,
+    label: 'Seguimiento de casos',
+    permission: null,
+    rolesRequired: ['SUPER ADMINISTRADOR', 'BIENESTAR AL APRENDIZ'],
+    iconKey: 'bienestar/seguimiento',
+  },
+  {
+    section: 'Bienestar',
+    path: bienestarPaths.riesgo,
+    label: 'Riesgo de deserción',
+    permission: null,
+    rolesRequired: ['SUPER ADMINISTRADOR', 'BIENESTAR AL APRENDIZ'],
+    iconKey: 'bienestar/riesgo',
+  },
 
   // —— Infraestructura ——
   {
@@ -222,6 +247,14 @@ export const SIDEBAR_MANIFEST: SidebarManifestItem[] = [
     rolesRequired: ['SUPER ADMINISTRADOR', 'ADMINISTRADOR', 'COORDINADOR'],
     iconKey: 'administracion/configuracion-asistencia',
   },
+  {
+    section: 'Administración',
+    path: administracionPaths.correos,
+    label: 'Correos salientes',
+    permission: null,
+    rolesRequired: ['SUPER ADMINISTRADOR', 'ADMINISTRADOR'],
+    iconKey: 'administracion/correos',
+  },
   {
     section: 'Administración',
     path: administracionPaths.elecciones,
@@ -16,6 +16,7 @@ import { formatNumero } from '../utils/formatFecha';
 import { useAuth } from '../context/AuthContext';
 import { getAsistenciaDashboardWsUrl } from '../config/api';
 import type {
+  AsistenciaEvento,
   AsistenciaDashboardFichaSinSesion,
   AsistenciaDashboardPorFicha,
   AsistenciaDashboardResponse,
@@ -33,6 +34,8 @@ import {
 const DASH_SEARCH_ID = 'asistencia-dashboard-buscar-ficha';
 const DASH_JORNADA_ID = 'asistencia-dashboard-filtro-jornada';
 const PAGE_SIZE = 20;
+/** Espera tras el último evento del canal en vivo antes de recargar el dashboard. */
+const RECARGA_WS_DEBOUNCE_MS = 1500;
 
 function textoResumenCasosBienestar(count: number): string {
   if (count === 0) return 'Sin aprendices que cumplan el umbral configurado (≥3 inasistencias / 30 días).';
@@ -509,6 +512,7 @@ export const AsistenciaDashboard = () => {
   const [pageSinSesion, setPageSinSesion] = useState(1);
   const wsRef = useRef<WebSocket | null>(null);
   const reconnectRef = useRef<ReturnType<typeof setTimeout> | null>(null);
+  const refetchWsRef = useRef<ReturnType<typeof setTimeout> | null>(null);
 
   const canViewBienestar = canViewCasosBienestar(roles);
 
@@ -560,8 +564,11 @@ export const AsistenciaDashboard = () => {
       };
       ws.onmessage = (event) => {
         try {
-          const msg = JSON.parse(event.data as string);
-          if (msg?.type === 'refresh') fetchDashboard();
+          const msg = JSON.parse(event.data as string) as AsistenciaEvento;
+          // El servidor ya filtra por alcance; se agrupan ráfagas de eventos en una sola recarga.
+          if (!msg?.type) return;
+          if (refetchWsRef.current) clearTimeout(refetchWsRef.current);
+          refetchWsRef.current = setTimeout(() => void fetchDashboard(), RECARGA_WS_DEBOUNCE_MS);
         } catch {
           // ignorar
         }
@@ -571,6 +578,7 @@ export const AsistenciaDashboard = () => {
     connect();
     return () => {
       if (reconnectRef.current) clearTimeout(reconnectRef.current);
+      if (refetchWsRef.current) clearTimeout(refetchWsRef.current);
       wsRef.current?.close();
       wsRef.current = null;
       setWsConnected(false);
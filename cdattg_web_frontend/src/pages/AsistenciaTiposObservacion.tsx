@@ -15,14 +15,17 @@ export const AsistenciaTiposObservacion = () => {
   const [error, setError] = useState('');
   const [codigo, setCodigo] = useState('');
   const [nombre, setNombre] = useState('');
+  const [pesoRiesgo, setPesoRiesgo] = useState(0);
   const [editandoId, setEditandoId] = useState<number | null>(null);
   const [editCodigo, setEditCodigo] = useState('');
   const [editNombre, setEditNombre] = useState('');
+  const [editPesoRiesgo, setEditPesoRiesgo] = useState(0);
   const [deletingId, setDeletingId] = useState<number | null>(null);
 
   const canManageTiposObs = roles.includes('SUPER ADMINISTRADOR') || roles.includes('ADMINISTRADOR');
   const TIPO_OBS_CODIGO_ID = 'tipo-obs-codigo';
   const TIPO_OBS_NOMBRE_ID = 'tipo-obs-nombre';
+  const TIPO_OBS_PESO_ID = 'tipo-obs-peso-riesgo';
 
   const cargar = useCallback(async () => {
     try {
@@ -60,9 +63,11 @@ export const AsistenciaTiposObservacion = () => {
           codigo: codigo.trim().toUpperCase(),
           nombre: nombre.trim(),
           activo: true,
+          peso_riesgo: pesoRiesgo,
         });
         setCodigo('');
         setNombre('');
+        setPesoRiesgo(0);
         await cargar();
       } catch (e: unknown) {
         setError(axiosErrorMessage(e, 'Error al crear tipo de observación.'));
@@ -98,7 +103,7 @@ export const AsistenciaTiposObservacion = () => {
 
       <div className="card">
         <h2 className="text-lg font-semibold text-gray-900 dark:text-white mb-4">Crear nuevo tipo</h2>
-        <form onSubmit={crear} className="grid grid-cols-1 md:grid-cols-3 gap-4 items-end">
+        <form onSubmit={crear} className="grid grid-cols-1 md:grid-cols-4 gap-4 items-end">
           <div>
             <label htmlFor={TIPO_OBS_CODIGO_ID} className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">
               Código
@@ -127,7 +132,21 @@ export const AsistenciaTiposObservacion = () => {
               maxLength={255}
             />
           </div>
-          <div className="md:col-span-3">
+          <div>
+            <label htmlFor={TIPO_OBS_PESO_ID} className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">
+              Peso en riesgo de deserción (0-5)
+            </label>
+            <input
+              id={TIPO_OBS_PESO_ID}
+              type="number"
+              min={0}
+              max={5}
+              value={pesoRiesgo}
+              onChange={(e) => setPesoRiesgo(Math.min(5, Math.max(0, Number(e.target.value) || 0)))}
+              className="input-field w-full"
+            />
+          </div>
+          <div className="md:col-span-4">
             <button type="submit" disabled={saving || !canManageTiposObs} className="btn-primary inline-flex items-center gap-2 disabled:opacity-50">
               <PlusIcon className="w-5 h-5" aria-hidden />
               {saving ? 'Creando…' : 'Crear tipo de observación'}
@@ -154,6 +173,7 @@ export const AsistenciaTiposObservacion = () => {
                 <tr>
                   <th className="px-4 py-3 text-left text-xs font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wider">Código</th>
                   <th className="px-4 py-3 text-left text-xs font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wider">Nombre</th>
+                  <th className="px-4 py-3 text-right text-xs font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wider">Peso riesgo</th>
                   <th className="px-4 py-3 text-right text-xs font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wider">Acciones</th>
                 </tr>
               </thead>
@@ -182,6 +202,19 @@ export const AsistenciaTiposObservacion = () => {
                         />
                       ) : item.nombre}
                     </td>
+                    <td className="px-4 py-3 text-sm text-right tabular-nums text-gray-700 dark:text-gray-300">
+                      {editandoId === item.id ? (
+                        <input
+                          type="number"
+                          aria-label="Peso en riesgo de deserción"
+                          className="input-field w-20"
+                          min={0}
+                          max={5}
+                          value={editPesoRiesgo}
+                          onChange={(e) => setEditPesoRiesgo(Math.min(5, Math.max(0, Number(e.target.value) || 0)))}
+                        />
+                      ) : item.peso_riesgo}
+                    </td>
                     <td className="px-4 py-3 text-sm text-right whitespace-nowrap">
                       {editandoId === item.id ? (
                         <div className="inline-flex gap-2">
@@ -202,6 +235,7 @@ export const AsistenciaTiposObservacion = () => {
                                     codigo: editCodigo.trim().toUpperCase(),
                                     nombre: editNombre.trim(),
                                     activo: true,
+                                    peso_riesgo: editPesoRiesgo,
                                   });
                                   setEditandoId(null);
                                   await cargar();
@@ -237,6 +271,7 @@ export const AsistenciaTiposObservacion = () => {
                               setEditandoId(item.id);
                               setEditCodigo(item.codigo);
                               setEditNombre(item.nombre);
+                              setEditPesoRiesgo(item.peso_riesgo ?? 0);
                             }}
                           >
                             Editar
@@ -21,11 +21,13 @@ import { ModalAsignarFicha } from '../components/ModalAsignarFicha';
 import { FichaFormModal } from '../components/FichaFormModal';
 import { FichaCaracterizacionCard } from '../components/FichaCaracterizacionCard';
 import { FichasAdminTable } from './fichas/FichasAdminTable';
+import { ImportacionProgreso } from '../components/importaciones/ImportacionProgreso';
+import { useImportacionTrabajo } from '../components/importaciones/useImportacionTrabajo';
 import { mergeListAfterSave } from '../utils/fichaCaracterizacionForm';
 import { canProgramarInstructores } from '../utils/programacionPermissions';
 import type {
   FichaCaracterizacionResponse,
-  FichaImportResult,
+  ImportacionTrabajo,
   ProgramaFormacionResponse,
   DiaFormacionItem,
 } from '../types';
@@ -39,30 +41,6 @@ function esExtensionExcelImportValida(file: File): boolean {
   return EXT_EXCEL_IMPORT.has(ext);
 }
 
-function descargarExcelDesdeBase64(base64: string, nombreArchivo: string): void {
-  try {
-    const binary = atob(base64);
-    const len = binary.length;
-    const bytes = new Uint8Array(len);
-    for (let i = 0; i < len; i += 1) {
-      bytes[i] = binary.codePointAt(i) ?? 0;
-    }
-    const blob = new Blob([bytes], {
-      type: 'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet',
-    });
-    const url = URL.createObjectURL(blob);
-    const a = document.createElement('a');
-    a.href = url;
-    a.download = nombreArchivo;
-    document.body.appendChild(a);
-    a.click();
-    a.remove();
-    URL.revokeObjectURL(url);
-  } catch {
-    // Reintento manual posible vía nueva importación
-  }
-}
-
 function filtrarListaFichasInstructor(
   list: FichaCaracterizacionResponse[],
   esInstructor: boolean,
@@ -98,13 +76,12 @@ async function exportarBlobExcelFichas(setExportLoading: (v: boolean) => void):
 type ImportFichasParams = Readonly<{
   importFile: File | null;
   setImportError: (msg: string) => void;
-  setImportResult: (r: FichaImportResult | null) => void;
   setImportLoading: (v: boolean) => void;
-  fetchList: () => void | Promise<void>;
+  onEncolada: (t: ImportacionTrabajo) => void;
 }>;
 
 async function ejecutarImportFichasExcel(p: ImportFichasParams): Promise<void> {
-  const { importFile, setImportError, setImportResult, setImportLoading, fetchList } = p;
+  const { importFile, setImportError, setImportLoading, onEncolada } = p;
   if (!importFile) {
     setImportError('Seleccione un archivo Excel.');
     return;
@@ -114,12 +91,9 @@ async function ejecutarImportFichasExcel(p: ImportFichasParams): Promise<void> {
     return;
   }
   setImportError('');
-  setImportResult(null);
   setImportLoading(true);
   try {
-    const result = await apiService.uploadFichasImport(importFile);
-    setImportResult(result);
-    await fetchList();
+    onEncolada(await apiService.uploadFichasImport(importFile));
   } catch (err: unknown) {
     setImportError(axiosErrorMessage(err, 'Error al importar'));
   } finally {
@@ -254,7 +228,6 @@ export const FichasCaracterizacion = () => {
   const [importFile, setImportFile] = useState<File | null>(null);
   const [importLoading, setImportLoading] = useState(false);
   const [exportLoading, setExportLoading] = useState(false);
-  const [importResult, setImportResult] = useState<FichaImportResult | null>(null);
   const [importError, setImportError] = useState('');
   const [modalAsignar, setModalAsignar] = useState<{ ficha: FichaCaracterizacionResponse; tipo: 'instructores' | 'aprendices' } | null>(null);
 
@@ -293,6 +266,14 @@ export const FichasCaracterizacion = () => {
     }
   }, [page, pageSize, programaId, esInstructor, searchQuery]);
 
+  const importacion = useImportacionTrabajo(() => void fetchList());
+  const importacionId = importacion.trabajo?.id;
+
+  useEffect(() => {
+    // Enlace de la notificación (/fichas?trabajo=<id>): abre el modal con el avance.
+    if (importacionId) setIsImportModalOpen(true);
+  }, [importacionId]);
+
   useEffect(() => {
     fetchProgramas();
     void fetchDiasFormacionCat();
@@ -342,25 +323,26 @@ export const FichasCaracterizacion = () => {
 
   const openImportModal = () => {
     setImportFile(null);
-    setImportResult(null);
     setImportError('');
+    if (!importacion.enCurso) importacion.limpiar();
     setIsImportModalOpen(true);
   };
 
+  const closeImportModal = () => {
+    setIsImportModalOpen(false);
+    setImportError('');
+    // Si sigue en curso se deja el seguimiento: al terminar se recarga la lista y llega la notificación.
+    if (!importacion.enCurso) importacion.limpiar();
+  };
+
   const handleImportSubmit = () =>
     ejecutarImportFichasExcel({
       importFile,
       setImportError,
-      setImportResult,
       setImportLoading,
-      fetchList,
+      onEncolada: importacion.seguir,
     });
 
-  const handleDownloadIncidencias = () => {
-    if (!importResult?.incident_report_base64) return;
-    descargarExcelDesdeBase64(importResult.incident_report_base64, 'reporte_incidencias_ficha.xlsx');
-  };
-
   const handleExportAllFichas = () => exportarBlobExcelFichas(setExportLoading);
 
   const totalPages = Math.ceil(total / pageSize);
@@ -527,28 +509,26 @@ export const FichasCaracterizacion = () => {
             <p className="text-sm text-gray-600 dark:text-gray-400 mb-4">
               Suba un archivo Excel (XLSX o XLS) con el reporte de aprendices (ficha de caracterización). Debe contener la línea con código y nombre del programa, y las columnas: Tipo de Documento, Número de Documento, Nombre, Apellidos, Celular, Correo.
             </p>
-            {importError && (
+            {(importError || importacion.error) && (
               <div className="mb-4 bg-red-50 dark:bg-red-900/30 border border-red-200 dark:border-red-800 text-red-700 dark:text-red-300 px-4 py-3 rounded-lg text-sm">
-                {importError}
+                {importError || importacion.error}
               </div>
             )}
-            {importResult && (
-              <div className="mb-4 bg-green-50 dark:bg-green-900/30 border border-green-200 dark:border-green-800 text-green-800 dark:text-green-300 px-4 py-3 rounded-lg text-sm space-y-1">
-                <p><strong>Resultado:</strong> {importResult.status}</p>
-                <p>Aprendices agregados a la ficha: {importResult.processed_count}</p>
-                <p>Personas creadas: {importResult.created_count} · Actualizadas: {importResult.updated_count}</p>
-                {importResult.duplicates_count > 0 && <p>Registros no agregados por estar ya inscritos: {importResult.duplicates_count}</p>}
-                {importResult.error_count > 0 && <p>Errores al crear/actualizar personas: {importResult.error_count}</p>}
-                {importResult.ficha_created && <p>Se creó la ficha en esta importación.</p>}
-                {importResult.incident_report_base64 && (
-                  <button
-                    type="button"
-                    onClick={handleDownloadIncidencias}
-                    className="mt-2 inline-flex items-center rounded-md bg-white/90 px-3 py-1.5 text-xs font-medium text-primary-700 shadow-sm ring-1 ring-inset ring-primary-200 hover:bg-white"
-                  >
-                    Descargar reporte de incidencias (Excel)
-                  </button>
-                )}
+            {importacion.trabajo && (
+              <div className="mb-4">
+                <ImportacionProgreso
+                  trabajo={importacion.trabajo}
+                  etiquetaProcesados="agregados"
+                  detalles={
+                    <>
+                      <p>Personas creadas: {importacion.trabajo.creados} · Actualizadas: {importacion.trabajo.actualizados}</p>
+                      {importacion.trabajo.ficha_creada && <p>Se creó la ficha en esta importación.</p>}
+                    </>
+                  }
+                  reanudando={importacion.reanudando}
+                  onReanudar={() => void importacion.reanudar()}
+                  onDescargarIncidencias={() => void importacion.descargarIncidencias()}
+                />
               </div>
             )}
             <div className="mb-4">
@@ -561,17 +541,17 @@ export const FichasCaracterizacion = () => {
             </div>
             <div className="flex justify-end gap-3">
               <button
-                onClick={() => { setIsImportModalOpen(false); setImportResult(null); setImportError(''); }}
+                onClick={closeImportModal}
                 className="btn-secondary"
               >
                 Cerrar
               </button>
               <button
                 onClick={handleImportSubmit}
-                disabled={!importFile || importLoading}
+                disabled={!importFile || importLoading || importacion.enCurso}
                 className="btn-primary disabled:opacity-50"
               >
-                {importLoading ? 'Importando...' : 'Importar'}
+                {importLoading || importacion.enCurso ? 'Importando...' : 'Importar'}
               </button>
             </div>
           </div>
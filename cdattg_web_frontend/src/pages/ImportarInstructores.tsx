@@ -11,16 +11,17 @@ import { apiService } from '../services/api';
 import { axiosErrorMessage } from '../utils/httpError';
 import { formatFechaHoraVista } from '../utils/formatFecha';
 import { SelectSearch } from '../components/SelectSearch';
-import type { InstructorImportLogItem, InstructorImportResult, RegionalItem } from '../types';
+import { ImportacionProgreso } from '../components/importaciones/ImportacionProgreso';
+import { useImportacionTrabajo } from '../components/importaciones/useImportacionTrabajo';
+import type { InstructorImportLogItem, RegionalItem } from '../types';
 
 const ACCEPTED_FORMATS = '.xlsx,.xls';
 const EXTENSIONES_PERMITIDAS = new Set(['xlsx', 'xls']);
 
 export const ImportarInstructores = () => {
   const [file, setFile] = useState<File | null>(null);
-  const [importing, setImporting] = useState(false);
+  const [uploading, setUploading] = useState(false);
   const [importError, setImportError] = useState('');
-  const [lastResult, setLastResult] = useState<InstructorImportResult | null>(null);
   const [imports, setImports] = useState<InstructorImportLogItem[]>([]);
   const [loadingImports, setLoadingImports] = useState(true);
   const [regionales, setRegionales] = useState<RegionalItem[]>([]);
@@ -43,6 +44,9 @@ export const ImportarInstructores = () => {
     void fetchImports();
   }, [fetchImports]);
 
+  const importacion = useImportacionTrabajo(() => void fetchImports());
+  const importing = uploading || importacion.enCurso;
+
   useEffect(() => {
     apiService.getCatalogosRegionales().then(setRegionales).catch(() => {});
   }, []);
@@ -51,7 +55,7 @@ export const ImportarInstructores = () => {
     const f = e.target.files?.[0];
     setFile(f || null);
     setImportError('');
-    setLastResult(null);
+    if (!importacion.enCurso) importacion.limpiar();
   };
 
   const handleStartImport = async () => {
@@ -65,20 +69,17 @@ export const ImportarInstructores = () => {
       setImportError('Solo se permiten archivos XLSX o XLS.');
       return;
     }
-    setImporting(true);
+    setUploading(true);
     setImportError('');
-    setLastResult(null);
     try {
       const rid = regionalId === '' ? undefined : Number(regionalId);
-      const result = await apiService.uploadInstructoresImport(file, rid);
-      setLastResult(result);
+      importacion.seguir(await apiService.uploadInstructoresImport(file, rid));
       setFile(null);
       if (fileInputRef.current) fileInputRef.current.value = '';
-      fetchImports();
     } catch (err: unknown) {
       setImportError(axiosErrorMessage(err, 'Error al importar.'));
     } finally {
-      setImporting(false);
+      setUploading(false);
     }
   };
 
@@ -155,28 +156,22 @@ export const ImportarInstructores = () => {
                 </button>
               </div>
             </div>
-            {importError && (
+            {importacion.trabajo && (
+              <ImportacionProgreso
+                trabajo={importacion.trabajo}
+                reanudando={importacion.reanudando}
+                onReanudar={() => void importacion.reanudar()}
+                onDescargarIncidencias={() => void importacion.descargarIncidencias()}
+              />
+            )}
+            {(importError || importacion.error) && (
               <div
                 role="alert"
                 className="mt-3 p-3 rounded-lg bg-red-50 dark:bg-red-900/30 text-red-700 dark:text-red-300 text-sm"
               >
-                {importError}
+                {importError || importacion.error}
               </div>
             )}
-            {lastResult && (
-              <output
-                aria-live="polite"
-                className="mt-3 block w-full p-4 rounded-lg bg-green-50 dark:bg-green-900/30 border border-green-200 dark:border-green-800"
-              >
-                <p className="text-sm font-medium text-green-800 dark:text-green-200 mb-2">Importación finalizada</p>
-                <div className="grid grid-cols-2 sm:grid-cols-4 gap-2 text-sm">
-                  <div><span className="font-semibold text-green-800 dark:text-green-200">{lastResult.processed_count}</span> creados</div>
-                  <div><span className="font-semibold text-amber-700 dark:text-amber-300">{lastResult.duplicates_count}</span> duplicados</div>
-                  <div><span className="font-semibold text-red-700 dark:text-red-300">{lastResult.error_count}</span> errores</div>
-                  <div><span className="font-semibold text-green-700 dark:text-green-300">{lastResult.status}</span></div>
-                </div>
-              </output>
-            )}
           </div>
 
           <div className="card">
@@ -6,25 +6,24 @@ import {
   LightBulbIcon,
   ExclamationTriangleIcon,
   ArrowPathIcon,
-  ChartBarIcon,
 } from '@heroicons/react/24/outline';
 import { apiService } from '../services/api';
 import { axiosErrorMessage } from '../utils/httpError';
 import { formatFechaHoraVista } from '../utils/formatFecha';
-import type { PersonaImportLogItem, PersonaImportResult, PersonaImportProgress } from '../types';
+import { ImportacionProgreso } from '../components/importaciones/ImportacionProgreso';
+import { useImportacionTrabajo } from '../components/importaciones/useImportacionTrabajo';
+import type { PersonaImportLogItem } from '../types';
 
 const ACCEPTED_FORMATS = '.xlsx,.xls';
 const EXTENSIONES_PERMITIDAS = new Set(['xlsx', 'xls']);
 
 export const ImportarPersonas = () => {
   const [file, setFile] = useState<File | null>(null);
-  const [importing, setImporting] = useState(false);
+  const [uploading, setUploading] = useState(false);
   const [importError, setImportError] = useState('');
-  const [lastResult, setLastResult] = useState<PersonaImportResult | null>(null);
   const [imports, setImports] = useState<PersonaImportLogItem[]>([]);
   const [loadingImports, setLoadingImports] = useState(true);
   const [downloadingTemplate, setDownloadingTemplate] = useState(false);
-  const [progress, setProgress] = useState<PersonaImportProgress | null>(null);
   const fileInputRef = useRef<HTMLInputElement>(null);
 
   const fetchImports = useCallback(async () => {
@@ -43,12 +42,14 @@ export const ImportarPersonas = () => {
     void fetchImports();
   }, [fetchImports]);
 
+  const importacion = useImportacionTrabajo(() => void fetchImports());
+  const importing = uploading || importacion.enCurso;
+
   const handleFileChange = (e: ChangeEvent<HTMLInputElement>) => {
     const f = e.target.files?.[0];
     setFile(f || null);
     setImportError('');
-    setLastResult(null);
-    setProgress(null);
+    if (!importacion.enCurso) importacion.limpiar();
   };
 
   const handleStartImport = async () => {
@@ -62,22 +63,16 @@ export const ImportarPersonas = () => {
       setImportError('Solo se permiten archivos XLSX o XLS.');
       return;
     }
-    setImporting(true);
+    setUploading(true);
     setImportError('');
-    setLastResult(null);
-    setProgress(null);
     try {
-      const result = await apiService.uploadPersonasImportWithProgress(file, (p) => setProgress(p));
-      setLastResult(result);
-      setProgress(null);
+      importacion.seguir(await apiService.uploadPersonasImport(file));
       setFile(null);
       if (fileInputRef.current) fileInputRef.current.value = '';
-      fetchImports();
     } catch (err: unknown) {
       setImportError(axiosErrorMessage(err, 'Error al importar.'));
-      setProgress(null);
     } finally {
-      setImporting(false);
+      setUploading(false);
     }
   };
 
@@ -168,74 +163,23 @@ export const ImportarPersonas = () => {
                 </button>
               </div>
 
-              {/* Barra de progreso y estadísticas durante la importación */}
-              {importing && progress && (
-                <div
-                  role="status"
-                  aria-live="polite"
-                  aria-busy="true"
-                  className="mt-4 p-4 rounded-lg bg-gray-50 dark:bg-gray-700/50 border border-gray-200 dark:border-gray-600"
-                >
-                  <div className="flex items-center gap-2 text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
-                    <ChartBarIcon className="w-5 h-5 text-primary-600" aria-hidden />
-                    Progreso de la importación
-                  </div>
-                  <progress
-                    className="w-full h-3 rounded-full overflow-hidden accent-primary-600"
-                    max={100}
-                    value={
-                      progress.total > 0
-                        ? Math.min(100, (progress.current / progress.total) * 100)
-                        : 0
-                    }
-                    aria-label="Avance del procesamiento de filas"
-                  />
-                  <p className="mt-2 text-xs text-gray-500 dark:text-gray-400">
-                    Fila {progress.current} de {progress.total} {progress.total > 0 ? `(${Math.round((progress.current / progress.total) * 100)}%)` : ''}
-                  </p>
-                  <div className="mt-3 grid grid-cols-2 sm:grid-cols-4 gap-3 text-sm">
-                    <div className="rounded-lg bg-green-100 dark:bg-green-900/40 px-3 py-2">
-                      <span className="text-green-800 dark:text-green-200 font-semibold">{progress.processed}</span>
-                      <span className="text-green-700 dark:text-green-300 ml-1">creados</span>
-                    </div>
-                    <div className="rounded-lg bg-amber-100 dark:bg-amber-900/40 px-3 py-2">
-                      <span className="text-amber-800 dark:text-amber-200 font-semibold">{progress.duplicates}</span>
-                      <span className="text-amber-700 dark:text-amber-300 ml-1">duplicados</span>
-                    </div>
-                    <div className="rounded-lg bg-red-100 dark:bg-red-900/40 px-3 py-2">
-                      <span className="text-red-800 dark:text-red-200 font-semibold">{progress.errors}</span>
-                      <span className="text-red-700 dark:text-red-300 ml-1">errores</span>
-                    </div>
-                    <div className="rounded-lg bg-gray-200 dark:bg-gray-600 px-3 py-2">
-                      <span className="text-gray-800 dark:text-gray-200 font-semibold">{progress.current}</span>
-                      <span className="text-gray-600 dark:text-gray-400 ml-1">revisados</span>
-                    </div>
-                  </div>
-                </div>
+              {importacion.trabajo && (
+                <ImportacionProgreso
+                  trabajo={importacion.trabajo}
+                  reanudando={importacion.reanudando}
+                  onReanudar={() => void importacion.reanudar()}
+                  onDescargarIncidencias={() => void importacion.descargarIncidencias()}
+                />
               )}
             </div>
-            {importError && (
+            {(importError || importacion.error) && (
               <div
                 role="alert"
                 className="mt-3 p-3 rounded-lg bg-red-50 dark:bg-red-900/30 text-red-700 dark:text-red-300 text-sm"
               >
-                {importError}
+                {importError || importacion.error}
               </div>
             )}
-            {lastResult && (
-              <output
-                aria-live="polite"
-                className="mt-3 block w-full p-4 rounded-lg bg-green-50 dark:bg-green-900/30 border border-green-200 dark:border-green-800"
-              >
-                <p className="text-sm font-medium text-green-800 dark:text-green-200 mb-2">Importación finalizada</p>
-                <div className="grid grid-cols-2 sm:grid-cols-4 gap-2 text-sm">
-                  <div><span className="font-semibold text-green-800 dark:text-green-200">{lastResult.processed_count}</span> creados</div>
-                  <div><span className="font-semibold text-amber-700 dark:text-amber-300">{lastResult.duplicates_count}</span> duplicados</div>
-                  <div><span className="font-semibold text-red-700 dark:text-red-300">{lastResult.error_count}</span> errores</div>
-                  <div><span className="font-semibold text-green-700 dark:text-green-300">{lastResult.status}</span></div>
-                </div>
-              </output>
-            )}
           </div>
 
           <div className="card">
@@ -2,23 +2,25 @@ import { useState, useRef, type ChangeEvent } from 'react';
 import { ArrowUpTrayIcon, PlayIcon, LightBulbIcon } from '@heroicons/react/24/outline';
 import { apiService } from '../services/api';
 import { axiosErrorMessage } from '../utils/httpError';
-import type { ProgramaImportResult } from '../types';
+import { ImportacionProgreso } from '../components/importaciones/ImportacionProgreso';
+import { useImportacionTrabajo } from '../components/importaciones/useImportacionTrabajo';
 
 const ACCEPTED_FORMATS = '.xlsx,.xls';
 const EXTENSIONES_PERMITIDAS = new Set(['xlsx', 'xls']);
 
 export const ImportarProgramas = () => {
   const [file, setFile] = useState<File | null>(null);
-  const [importing, setImporting] = useState(false);
+  const [uploading, setUploading] = useState(false);
   const [importError, setImportError] = useState('');
-  const [lastResult, setLastResult] = useState<ProgramaImportResult | null>(null);
   const fileInputRef = useRef<HTMLInputElement>(null);
+  const importacion = useImportacionTrabajo();
+  const importing = uploading || importacion.enCurso;
 
   const handleFileChange = (e: ChangeEvent<HTMLInputElement>) => {
     const f = e.target.files?.[0];
     setFile(f || null);
     setImportError('');
-    setLastResult(null);
+    if (!importacion.enCurso) importacion.limpiar();
   };
 
   const handleStartImport = async () => {
@@ -32,18 +34,16 @@ export const ImportarProgramas = () => {
       setImportError('Solo se permiten archivos XLSX o XLS.');
       return;
     }
-    setImporting(true);
+    setUploading(true);
     setImportError('');
-    setLastResult(null);
     try {
-      const result = await apiService.uploadProgramasImport(file);
-      setLastResult(result);
+      importacion.seguir(await apiService.uploadProgramasImport(file));
       setFile(null);
       if (fileInputRef.current) fileInputRef.current.value = '';
     } catch (err: unknown) {
       setImportError(axiosErrorMessage(err, 'Error al importar.'));
     } finally {
-      setImporting(false);
+      setUploading(false);
     }
   };
 
@@ -103,28 +103,24 @@ export const ImportarProgramas = () => {
                 {importing ? 'Importando...' : 'Iniciar importación'}
               </button>
             </div>
-            {importError && (
+            {importacion.trabajo && (
+              <ImportacionProgreso
+                trabajo={importacion.trabajo}
+                unidad="Programa"
+                detalles={<p>{importacion.trabajo.redes_creadas} redes de conocimiento creadas</p>}
+                reanudando={importacion.reanudando}
+                onReanudar={() => void importacion.reanudar()}
+                onDescargarIncidencias={() => void importacion.descargarIncidencias()}
+              />
+            )}
+            {(importError || importacion.error) && (
               <div
                 role="alert"
                 className="mt-3 p-3 rounded-lg bg-red-50 dark:bg-red-900/30 text-red-700 dark:text-red-300 text-sm"
               >
-                {importError}
+                {importError || importacion.error}
               </div>
             )}
-            {lastResult && (
-              <output
-                aria-live="polite"
-                className="mt-3 block w-full p-4 rounded-lg bg-green-50 dark:bg-green-900/30 border border-green-200 dark:border-green-800"
-              >
-                <p className="text-sm font-medium text-green-800 dark:text-green-200 mb-2">Importación finalizada</p>
-                <div className="grid grid-cols-2 sm:grid-cols-4 gap-2 text-sm">
-                  <div><span className="font-semibold text-green-800 dark:text-green-200">{lastResult.processed_count}</span> creados</div>
-                  <div><span className="font-semibold text-amber-700 dark:text-amber-300">{lastResult.duplicates_count}</span> duplicados</div>
-                  <div><span className="font-semibold text-red-700 dark:text-red-300">{lastResult.error_count}</span> errores</div>
-                  <div><span className="font-semibold text-blue-700 dark:text-blue-300">{lastResult.redes_created}</span> redes creadas</div>
-                </div>
-              </output>
-            )}
           </div>
 
           <div className="card">
@@ -1,9 +1,8 @@
 import { useState, type ComponentProps } from 'react';
-import { useNavigate } from 'react-router-dom';
-import { MoonIcon, SunIcon } from '@heroicons/react/24/outline';
-import LogoSena from '../../logo-sena-verde-complementario-svg-2022.svg';
+import { Link, useNavigate } from 'react-router-dom';
 import { useAuth } from '../context/AuthContext';
-import { useTheme } from '../context/ThemeContext';
+import { AuthAlert, AuthPageShell } from '../components/layout/AuthPageShell';
+import { authPaths } from '../routes/paths';
 import { axiosErrorMessage } from '../utils/httpError';
 
 export const Login = () => {
@@ -12,7 +11,6 @@ export const Login = () => {
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
   const { login } = useAuth();
-  const { theme, toggleTheme } = useTheme();
   const navigate = useNavigate();
 
   const handleSubmit: NonNullable<ComponentProps<'form'>['onSubmit']> = (e) => {
@@ -32,90 +30,56 @@ export const Login = () => {
   };
 
   return (
-    <div className="min-h-screen flex items-center justify-center bg-gradient-to-br from-primary-50 to-primary-100 dark:from-gray-900 dark:to-gray-800 py-12 px-4 sm:px-6 lg:px-8 relative">
-      <button
-        type="button"
-        onClick={toggleTheme}
-        className="absolute top-4 right-4 p-2 rounded-lg bg-white dark:bg-gray-700 shadow hover:bg-gray-100 dark:hover:bg-gray-600 transition-colors"
-        title={theme === 'light' ? 'Modo oscuro' : 'Modo claro'}
-        aria-label={theme === 'light' ? 'Cambiar a modo oscuro' : 'Cambiar a modo claro'}
-      >
-        {theme === 'light' ? (
-          <MoonIcon className="w-5 h-5 text-gray-700" aria-hidden />
-        ) : (
-          <SunIcon className="w-5 h-5 text-yellow-300" aria-hidden />
-        )}
-      </button>
-      <div className="max-w-md w-full space-y-8">
-        <div className="text-center">
-          <div className="flex justify-center">
-            <img
-              src={LogoSena}
-              alt="Logo SENA"
-              className="w-20 h-20 rounded-2xl shadow-lg"
-            />
-          </div>
-          <h2 className="mt-6 text-3xl font-extrabold text-gray-900 dark:text-white">
-            CDATTG Web
-          </h2>
-          <p className="mt-2 text-sm text-gray-600 dark:text-gray-400">
-            Sistema de Gestión SENA
-          </p>
+    <AuthPageShell>
+      <form className="space-y-6" onSubmit={handleSubmit}>
+        {error && <AuthAlert tipo="error">{error}</AuthAlert>}
+        <div>
+          <label htmlFor="loginId" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+            Correo, documento o celular
+          </label>
+          <input
+            id="loginId"
+            name="loginId"
+            type="text"
+            autoComplete="username"
+            required
+            value={loginId}
+            onChange={(e) => setLoginId(e.target.value)}
+            className="input-field"
+            placeholder="usuario@ejemplo.com, 123456789 o 3001234567"
+          />
         </div>
-        <div className="card">
-          <form className="space-y-6" onSubmit={handleSubmit}>
-            {error && (
-              <div
-                role="alert"
-                className="bg-red-50 dark:bg-red-900/30 border border-red-200 dark:border-red-800 text-red-700 dark:text-red-300 px-4 py-3 rounded-lg"
-              >
-                {error}
-              </div>
-            )}
-            <div>
-              <label htmlFor="loginId" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
-                Correo, documento o celular
-              </label>
-              <input
-                id="loginId"
-                name="loginId"
-                type="text"
-                autoComplete="username"
-                required
-                value={loginId}
-                onChange={(e) => setLoginId(e.target.value)}
-                className="input-field"
-                placeholder="usuario@ejemplo.com, 123456789 o 3001234567"
-              />
-            </div>
-            <div>
-              <label htmlFor="password" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
-                Contraseña
-              </label>
-              <input
-                id="password"
-                name="password"
-                type="password"
-                autoComplete="current-password"
-                required
-                value={password}
-                onChange={(e) => setPassword(e.target.value)}
-                className="input-field"
-                placeholder="••••••••"
-              />
-            </div>
-            <div>
-              <button
-                type="submit"
-                disabled={loading}
-                className="btn-primary w-full"
-              >
-                {loading ? 'Iniciando sesión...' : 'Iniciar Sesión'}
-              </button>
-            </div>
-          </form>
+        <div>
+          <label htmlFor="password" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+            Contraseña
+          </label>
+          <input
+            id="password"
+            name="password"
+            type="password"
+            autoComplete="current-password"
+            required
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            className="input-field"
+            placeholder="••••••••"
+          />
         </div>
-      </div>
-    </div>
+        <div>
+          <button
+            type="submit"
+            disabled={loading}
+            className="btn-primary w-full"
+          >
+            {loading ? 'Iniciando sesión...' : 'Iniciar Sesión'}
+          </button>
+        </div>
+        <p className="text-center text-sm">
+          <Link to={authPaths.recuperarPassword} className="text-primary-600 hover:underline dark:text-primary-400">
+            ¿Olvidó su contraseña?
+          </Link>
+        </p>
+      </form>
+    </AuthPageShell>
   );
 };
@@ -9,13 +9,22 @@ import {
   UserCircleIcon,
   ChevronDownIcon,
   PencilSquareIcon,
+  NewspaperIcon,
+  BellIcon,
 } from '@heroicons/react/24/outline';
 import { useAuth } from '../context/AuthContext';
 import { apiService } from '../services/api';
 import { axiosErrorMessage } from '../utils/httpError';
 import { formatRoleLabel } from '../utils/roles';
 import { PersonaModal } from '../components/PersonaModal';
-import type { PersonaRequest, PersonaResponse, PersonaSelfUpdateRequest, UserResponse } from '../types';
+import type {
+  PersonaRequest,
+  PersonaResponse,
+  PersonaSelfUpdateRequest,
+  ResumenesSemanalesResponse,
+  NotificacionPreferenciaItem,
+  UserResponse,
+} from '../types';
 
 const PERM_EDITAR_MI_PERSONA = 'EDITAR MI PERSONA';
 
@@ -326,6 +335,196 @@ function PerfilPermisosSection({ permissions }: PerfilPermisosSectionProps) {
   );
 }
 
+/** Aviso para confirmar el correo de login: sin verificarlo no se pueden recibir enlaces de recuperación. */
+function PerfilVerificacionEmail({ user }: Readonly<{ user: UserResponse | null }>) {
+  const [enviando, setEnviando] = useState(false);
+  const [mensaje, setMensaje] = useState('');
+  const [error, setError] = useState('');
+
+  if (!user || user.email_verificado !== false) return null;
+
+  const solicitar = async () => {
+    setEnviando(true);
+    setError('');
+    try {
+      const res = await apiService.solicitarVerificacionEmail();
+      setMensaje(res.message);
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo enviar el correo de verificación.'));
+    } finally {
+      setEnviando(false);
+    }
+  };
+
+  return (
+    <section className="rounded-2xl border border-amber-200 bg-amber-50 p-4 text-sm text-amber-800 shadow-sm dark:border-amber-800 dark:bg-amber-900/30 dark:text-amber-200 sm:p-6">
+      <p className="flex items-center gap-2 font-semibold">
+        <EnvelopeIcon className="h-5 w-5" />
+        Correo sin verificar
+      </p>
+      <p className="mt-1">
+        Verifique <span className="font-medium">{user.email}</span> para poder recuperar su contraseña desde la
+        pantalla de inicio de sesión.
+      </p>
+      {mensaje ? <p className="mt-2 font-medium">{mensaje}</p> : null}
+      {error ? <p className="mt-2 text-red-700 dark:text-red-300">{error}</p> : null}
+      {mensaje ? null : (
+        <button
+          type="button"
+          className="btn-secondary mt-3"
+          disabled={enviando}
+          onClick={() => void solicitar()}
+        >
+          {enviando ? 'Enviando…' : 'Enviar enlace de verificación'}
+        </button>
+      )}
+    </section>
+  );
+}
+
+/** Resúmenes semanales por correo que aplican a los roles del usuario; cada uno se puede cancelar o reactivar. */
+function PerfilResumenesSemanales() {
+  const [data, setData] = useState<ResumenesSemanalesResponse | null>(null);
+  const [guardando, setGuardando] = useState('');
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    let cancelled = false;
+    apiService
+      .getResumenesSemanales()
+      .then((res) => {
+        if (!cancelled) setData(res);
+      })
+      .catch(() => {
+        if (!cancelled) setData(null);
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, []);
+
+  if (!data || data.preferencias.length === 0) return null;
+
+  const cambiar = async (tipo: string, activo: boolean) => {
+    setGuardando(tipo);
+    setError('');
+    try {
+      setData(await apiService.actualizarResumenSemanal(tipo, activo));
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo guardar la preferencia.'));
+    } finally {
+      setGuardando('');
+    }
+  };
+
+  return (
+    <section className="rounded-2xl border border-gray-200 bg-white p-4 shadow-sm dark:border-gray-600 dark:bg-gray-800 sm:p-6">
+      <h2 className="mb-1 flex items-center gap-2 text-base font-semibold text-gray-900 dark:text-white sm:text-lg">
+        <NewspaperIcon className="h-5 w-5 text-primary-600 dark:text-primary-400" />
+        Resúmenes semanales por correo
+      </h2>
+      <p className="text-xs text-gray-500 dark:text-gray-400 sm:text-sm">
+        Se envían los lunes en la mañana{data.email ? ` a ${data.email}` : ''}.
+      </p>
+      {data.correo_disponible ? null : (
+        <p className="mt-2 text-sm text-amber-700 dark:text-amber-300">
+          Por ahora no se pueden enviar: su correo no es válido o el envío de correos está deshabilitado.
+        </p>
+      )}
+      {error ? <p className="mt-2 text-sm text-red-700 dark:text-red-300">{error}</p> : null}
+      <ul className="mt-3 divide-y divide-gray-100 dark:divide-gray-700">
+        {data.preferencias.map((p) => (
+          <li key={p.tipo} className="flex items-start justify-between gap-4 py-3">
+            <div className="min-w-0">
+              <p className="text-sm font-medium text-gray-900 dark:text-white">{p.nombre}</p>
+              <p className="mt-0.5 text-xs text-gray-500 dark:text-gray-400 sm:text-sm">{p.descripcion}</p>
+            </div>
+            <label className="flex shrink-0 cursor-pointer items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+              <input
+                type="checkbox"
+                className="h-4 w-4 rounded border-gray-300 text-primary-600 focus:ring-primary-500"
+                checked={p.activo}
+                disabled={guardando === p.tipo}
+                onChange={(e) => void cambiar(p.tipo, e.target.checked)}
+              />
+              {p.activo ? 'Activo' : 'Cancelado'}
+            </label>
+          </li>
+        ))}
+      </ul>
+    </section>
+  );
+}
+
+/** Tipos de notificación en la aplicación que aplican a los roles del usuario; cada uno se puede desactivar. */
+function PerfilNotificaciones() {
+  const [preferencias, setPreferencias] = useState<NotificacionPreferenciaItem[]>([]);
+  const [guardando, setGuardando] = useState('');
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    let cancelled = false;
+    apiService
+      .getNotificacionPreferencias()
+      .then((res) => {
+        if (!cancelled) setPreferencias(res);
+      })
+      .catch(() => {
+        if (!cancelled) setPreferencias([]);
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, []);
+
+  if (preferencias.length === 0) return null;
+
+  const cambiar = async (tipo: string, activo: boolean) => {
+    setGuardando(tipo);
+    setError('');
+    try {
+      setPreferencias(await apiService.actualizarNotificacionPreferencia(tipo, activo));
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo guardar la preferencia.'));
+    } finally {
+      setGuardando('');
+    }
+  };
+
+  return (
+    <section className="rounded-2xl border border-gray-200 bg-white p-4 shadow-sm dark:border-gray-600 dark:bg-gray-800 sm:p-6">
+      <h2 className="mb-1 flex items-center gap-2 text-base font-semibold text-gray-900 dark:text-white sm:text-lg">
+        <BellIcon className="h-5 w-5 text-primary-600 dark:text-primary-400" />
+        Notificaciones en la aplicación
+      </h2>
+      <p className="text-xs text-gray-500 dark:text-gray-400 sm:text-sm">
+        Aparecen en la campana de la barra superior. Las desactivadas dejan de generarse.
+      </p>
+      {error ? <p className="mt-2 text-sm text-red-700 dark:text-red-300">{error}</p> : null}
+      <ul className="mt-3 divide-y divide-gray-100 dark:divide-gray-700">
+        {preferencias.map((p) => (
+          <li key={p.tipo} className="flex items-start justify-between gap-4 py-3">
+            <div className="min-w-0">
+              <p className="text-sm font-medium text-gray-900 dark:text-white">{p.nombre}</p>
+              <p className="mt-0.5 text-xs text-gray-500 dark:text-gray-400 sm:text-sm">{p.descripcion}</p>
+            </div>
+            <label className="flex shrink-0 cursor-pointer items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+              <input
+                type="checkbox"
+                className="h-4 w-4 rounded border-gray-300 text-primary-600 focus:ring-primary-500"
+                checked={p.activo}
+                disabled={guardando === p.tipo}
+                onChange={(e) => void cambiar(p.tipo, e.target.checked)}
+              />
+              {p.activo ? 'Activa' : 'Desactivada'}
+            </label>
+          </li>
+        ))}
+      </ul>
+    </section>
+  );
+}
+
 type PerfilContentProps = Readonly<{
   loading: boolean;
   fullName: string;
@@ -357,7 +556,10 @@ function PerfilContent({
         user={user}
         roles={roles}
       />
+      <PerfilVerificacionEmail user={user} />
       <PerfilContactoSection loading={loading} persona={persona} email={email} />
+      <PerfilResumenesSemanales />
+      <PerfilNotificaciones />
       <PerfilPermisosSection permissions={permissions} />
     </>
   );
@@ -0,0 +1,77 @@
+import { useState, type ComponentProps } from 'react';
+import { Link } from 'react-router-dom';
+import { AuthAlert, AuthPageShell } from '../components/layout/AuthPageShell';
+import { authPaths } from '../routes/paths';
+import { apiService } from '../services/api';
+import { axiosErrorMessage } from '../utils/httpError';
+
+export const RecuperarPassword = () => {
+  const [loginId, setLoginId] = useState('');
+  const [mensaje, setMensaje] = useState('');
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit: NonNullable<ComponentProps<'form'>['onSubmit']> = (e) => {
+    e.preventDefault();
+    setError('');
+    void (async () => {
+      setLoading(true);
+      try {
+        const res = await apiService.recuperarPassword(loginId.trim());
+        setMensaje(res.message);
+      } catch (err: unknown) {
+        setError(axiosErrorMessage(err, 'No se pudo procesar la solicitud'));
+      } finally {
+        setLoading(false);
+      }
+    })();
+  };
+
+  return (
+    <AuthPageShell subtitle="Recuperar contraseña">
+      {mensaje ? (
+        <div className="space-y-6">
+          <AuthAlert tipo="ok">{mensaje}</AuthAlert>
+          <p className="text-sm text-gray-600 dark:text-gray-400">
+            Si no recibe el correo, su cuenta puede no tener el correo verificado: solicite al administrador que
+            restablezca la contraseña.
+          </p>
+          <Link to={authPaths.login} className="btn-primary block w-full text-center">
+            Volver a iniciar sesión
+          </Link>
+        </div>
+      ) : (
+        <form className="space-y-6" onSubmit={handleSubmit}>
+          {error && <AuthAlert tipo="error">{error}</AuthAlert>}
+          <p className="text-sm text-gray-600 dark:text-gray-400">
+            Ingrese su correo, documento o celular. Le enviaremos un enlace para elegir una nueva contraseña al correo
+            verificado de la cuenta.
+          </p>
+          <div>
+            <label htmlFor="loginId" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+              Correo, documento o celular
+            </label>
+            <input
+              id="loginId"
+              name="loginId"
+              type="text"
+              autoComplete="username"
+              required
+              value={loginId}
+              onChange={(e) => setLoginId(e.target.value)}
+              className="input-field"
+            />
+          </div>
+          <button type="submit" disabled={loading} className="btn-primary w-full">
+            {loading ? 'Enviando...' : 'Enviar enlace'}
+          </button>
+          <p className="text-center text-sm">
+            <Link to={authPaths.login} className="text-primary-600 hover:underline dark:text-primary-400">
+              Volver a iniciar sesión
+            </Link>
+          </p>
+        </form>
+      )}
+    </AuthPageShell>
+  );
+};
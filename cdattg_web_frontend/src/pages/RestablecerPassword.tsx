@@ -0,0 +1,108 @@
+import { useState, type ComponentProps } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { AuthAlert, AuthPageShell } from '../components/layout/AuthPageShell';
+import { authPaths } from '../routes/paths';
+import { apiService } from '../services/api';
+import { axiosErrorMessage } from '../utils/httpError';
+
+export const RestablecerPassword = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') ?? '';
+  const [passwordNueva, setPasswordNueva] = useState('');
+  const [passwordConfirm, setPasswordConfirm] = useState('');
+  const [mensaje, setMensaje] = useState('');
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit: NonNullable<ComponentProps<'form'>['onSubmit']> = (e) => {
+    e.preventDefault();
+    setError('');
+    if (passwordNueva.length < 6) {
+      setError('La nueva contraseña debe tener al menos 6 caracteres.');
+      return;
+    }
+    if (passwordNueva !== passwordConfirm) {
+      setError('La nueva contraseña y la confirmación no coinciden.');
+      return;
+    }
+    void (async () => {
+      setLoading(true);
+      try {
+        const res = await apiService.restablecerPassword({ token, password_nueva: passwordNueva });
+        setMensaje(res.message);
+      } catch (err: unknown) {
+        setError(axiosErrorMessage(err, 'No se pudo restablecer la contraseña'));
+      } finally {
+        setLoading(false);
+      }
+    })();
+  };
+
+  if (!token) {
+    return (
+      <AuthPageShell subtitle="Restablecer contraseña">
+        <div className="space-y-6">
+          <AuthAlert tipo="error">El enlace está incompleto. Ábralo de nuevo desde el correo o solicite uno nuevo.</AuthAlert>
+          <Link to={authPaths.recuperarPassword} className="btn-primary block w-full text-center">
+            Solicitar un nuevo enlace
+          </Link>
+        </div>
+      </AuthPageShell>
+    );
+  }
+
+  return (
+    <AuthPageShell subtitle="Restablecer contraseña">
+      {mensaje ? (
+        <div className="space-y-6">
+          <AuthAlert tipo="ok">{mensaje}</AuthAlert>
+          <Link to={authPaths.login} className="btn-primary block w-full text-center">
+            Iniciar sesión
+          </Link>
+        </div>
+      ) : (
+        <form className="space-y-6" onSubmit={handleSubmit}>
+          {error && <AuthAlert tipo="error">{error}</AuthAlert>}
+          <div>
+            <label htmlFor="password_nueva" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+              Nueva contraseña (mín. 6 caracteres)
+            </label>
+            <input
+              id="password_nueva"
+              type="password"
+              autoComplete="new-password"
+              required
+              minLength={6}
+              value={passwordNueva}
+              onChange={(e) => setPasswordNueva(e.target.value)}
+              className="input-field"
+            />
+          </div>
+          <div>
+            <label htmlFor="password_confirm" className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+              Confirmar nueva contraseña
+            </label>
+            <input
+              id="password_confirm"
+              type="password"
+              autoComplete="new-password"
+              required
+              minLength={6}
+              value={passwordConfirm}
+              onChange={(e) => setPasswordConfirm(e.target.value)}
+              className="input-field"
+            />
+          </div>
+          <button type="submit" disabled={loading} className="btn-primary w-full">
+            {loading ? 'Guardando...' : 'Guardar contraseña'}
+          </button>
+          <p className="text-center text-sm">
+            <Link to={authPaths.recuperarPassword} className="text-primary-600 hover:underline dark:text-primary-400">
+              Solicitar un nuevo enlace
+            </Link>
+          </p>
+        </form>
+      )}
+    </AuthPageShell>
+  );
+};
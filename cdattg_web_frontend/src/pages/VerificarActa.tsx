@@ -0,0 +1,67 @@
+import { useEffect, useState } from 'react';
+import { Link, useParams } from 'react-router-dom';
+import { AuthAlert, AuthPageShell } from '../components/layout/AuthPageShell';
+import { authPaths } from '../routes/paths';
+import { apiService } from '../services/api';
+import type { EleccionActaVerificacion } from '../types/eleccion';
+import { formatFechaHoraVista } from '../utils/formatFecha';
+import { axiosErrorMessage } from '../utils/httpError';
+
+function mensajeVerificacion(acta: EleccionActaVerificacion): string {
+  if (acta.valida) return 'El acta es auténtica y coincide con los votos registrados.';
+  if (!acta.contenido_integro) return 'El contenido guardado del acta no corresponde a su hash.';
+  return 'El acta existe, pero el conteo actual de la urna ya no coincide con el registrado.';
+}
+
+export const VerificarActa = () => {
+  const { hash = '' } = useParams<{ hash: string }>();
+  const [acta, setActa] = useState<EleccionActaVerificacion | null>(null);
+  const [error, setError] = useState('');
+  const [cargando, setCargando] = useState(true);
+
+  useEffect(() => {
+    setCargando(true);
+    setError('');
+    apiService
+      .verificarEleccionActa(hash)
+      .then(setActa)
+      .catch((err: unknown) => {
+        setActa(null);
+        setError(axiosErrorMessage(err, 'No se pudo verificar el acta'));
+      })
+      .finally(() => setCargando(false));
+  }, [hash]);
+
+  return (
+    <AuthPageShell subtitle="Verificar acta de escrutinio">
+      <div className="space-y-6">
+        {cargando ? <p className="text-sm text-gray-600 dark:text-gray-400">Verificando el acta…</p> : null}
+        {!cargando && error ? <AuthAlert tipo="error">{error}</AuthAlert> : null}
+        {!cargando && acta ? (
+          <>
+            <AuthAlert tipo={acta.valida ? 'ok' : 'error'}>{mensajeVerificacion(acta)}</AuthAlert>
+            <dl className="grid grid-cols-[auto,1fr] gap-x-4 gap-y-2 text-sm">
+              <dt className="font-medium text-gray-500 dark:text-gray-400">Proceso</dt>
+              <dd className="text-gray-900 dark:text-white">
+                {acta.nombre_ciclo} · {acta.anio}
+              </dd>
+              <dt className="font-medium text-gray-500 dark:text-gray-400">Regional</dt>
+              <dd className="text-gray-900 dark:text-white">{acta.regional}</dd>
+              <dt className="font-medium text-gray-500 dark:text-gray-400">Generada</dt>
+              <dd className="text-gray-900 dark:text-white">{formatFechaHoraVista(acta.generada_at)}</dd>
+              <dt className="font-medium text-gray-500 dark:text-gray-400">Votos</dt>
+              <dd className="tabular-nums text-gray-900 dark:text-white">{acta.votos_totales}</dd>
+              <dt className="font-medium text-gray-500 dark:text-gray-400">Ganadora</dt>
+              <dd className="text-gray-900 dark:text-white">{acta.plancha_ganadora ?? 'Sin plancha ganadora'}</dd>
+              <dt className="font-medium text-gray-500 dark:text-gray-400">SHA-256</dt>
+              <dd className="break-all font-mono text-xs text-gray-700 dark:text-gray-300">{acta.hash}</dd>
+            </dl>
+          </>
+        ) : null}
+        <Link to={authPaths.login} className="btn-primary block w-full text-center">
+          Ir al inicio de sesión
+        </Link>
+      </div>
+    </AuthPageShell>
+  );
+};
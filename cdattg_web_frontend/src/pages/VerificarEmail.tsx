@@ -0,0 +1,47 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { AuthAlert, AuthPageShell } from '../components/layout/AuthPageShell';
+import { authPaths } from '../routes/paths';
+import { apiService } from '../services/api';
+import { axiosErrorMessage } from '../utils/httpError';
+
+export const VerificarEmail = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') ?? '';
+  const [estado, setEstado] = useState<'verificando' | 'ok' | 'error'>(token ? 'verificando' : 'error');
+  const [mensaje, setMensaje] = useState(token ? '' : 'El enlace está incompleto. Ábralo de nuevo desde el correo.');
+  // El token es de un solo uso: evita el segundo envío del doble montaje en modo estricto.
+  const enviado = useRef(false);
+
+  useEffect(() => {
+    if (!token || enviado.current) return;
+    enviado.current = true;
+    apiService
+      .verificarEmail(token)
+      .then((res) => {
+        setEstado('ok');
+        setMensaje(res.message);
+      })
+      .catch((err: unknown) => {
+        setEstado('error');
+        setMensaje(axiosErrorMessage(err, 'No se pudo verificar el correo'));
+      });
+  }, [token]);
+
+  return (
+    <AuthPageShell subtitle="Verificar correo">
+      <div className="space-y-6">
+        {estado === 'verificando' ? (
+          <p className="text-sm text-gray-600 dark:text-gray-400">Verificando su correo…</p>
+        ) : (
+          <AuthAlert tipo={estado === 'ok' ? 'ok' : 'error'}>{mensaje}</AuthAlert>
+        )}
+        {estado === 'verificando' ? null : (
+          <Link to={authPaths.login} className="btn-primary block w-full text-center">
+            Ir al inicio de sesión
+          </Link>
+        )}
+      </div>
+    </AuthPageShell>
+  );
+};
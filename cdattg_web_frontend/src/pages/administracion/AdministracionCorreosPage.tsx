@@ -0,0 +1,377 @@
+import { useCallback, useEffect, useState, type ReactNode } from 'react';
+import { ArrowPathIcon, EnvelopeIcon, PaperClipIcon } from '@heroicons/react/24/outline';
+import { apiService } from '../../services/api';
+import { axiosErrorMessage } from '../../utils/httpError';
+import { useAuth } from '../../context/AuthContext';
+import { hasAnyRole } from '../../utils/roles';
+import { formatFechaHoraVista } from '../../utils/formatFecha';
+import type {
+  CorreoSalienteDetalle,
+  CorreoSalienteEstado,
+  CorreoSalienteItem,
+  CorreosSalientesResumen,
+} from '../../types';
+
+const PAGE_SIZE = 50;
+
+const PLANTILLAS: Record<string, string> = {
+  'recuperar-password': 'Recuperar contraseña',
+  'verificar-email': 'Verificar correo',
+  'recordatorio-bienestar': 'Recordatorio bienestar',
+  'alerta-asistencia': 'Alerta de asistencia',
+  'resumen-coordinador': 'Resumen coordinador',
+  'resumen-instructor': 'Resumen instructor',
+  'reporte-sesion': 'Reporte de sesión',
+};
+
+const ESTADO_BADGE: Record<CorreoSalienteEstado, string> = {
+  pendiente: 'bg-amber-100 text-amber-800 dark:bg-amber-900/40 dark:text-amber-200',
+  enviado: 'bg-emerald-100 text-emerald-800 dark:bg-emerald-900/40 dark:text-emerald-200',
+  fallido: 'bg-red-100 text-red-800 dark:bg-red-900/40 dark:text-red-200',
+};
+
+function EstadoBadge({ estado }: { estado: CorreoSalienteEstado }) {
+  return <span className={`rounded px-2 py-0.5 text-xs font-medium ${ESTADO_BADGE[estado]}`}>{estado}</span>;
+}
+
+function cuerpoTablaCorreos(
+  loading: boolean,
+  items: CorreoSalienteItem[],
+  seleccionado: number | null,
+  onSeleccionar: (id: number) => void,
+): ReactNode {
+  if (loading) {
+    return <p className="text-sm text-gray-500">Cargando…</p>;
+  }
+  if (items.length === 0) {
+    return <p className="text-sm text-gray-500">No hay correos con esos filtros.</p>;
+  }
+  return (
+    <div className="overflow-x-auto">
+      <table className="min-w-full text-sm">
+        <thead>
+          <tr className="border-b border-gray-200 text-left text-xs uppercase text-gray-500 dark:border-gray-600">
+            <th className="px-2 py-2">Creado</th>
+            <th className="px-2 py-2">Destinatarios</th>
+            <th className="px-2 py-2">Asunto</th>
+            <th className="px-2 py-2">Origen</th>
+            <th className="px-2 py-2">Estado</th>
+            <th className="px-2 py-2">Intentos</th>
+          </tr>
+        </thead>
+        <tbody>
+          {items.map((item) => (
+            <tr
+              key={item.id}
+              className={`cursor-pointer border-b border-gray-100 hover:bg-gray-50 dark:border-gray-700 dark:hover:bg-gray-800 ${
+                seleccionado === item.id ? 'bg-emerald-50 dark:bg-emerald-900/20' : ''
+              }`}
+              onClick={() => onSeleccionar(item.id)}
+            >
+              <td className="whitespace-nowrap px-2 py-2">{formatFechaHoraVista(item.created_at)}</td>
+              <td className="px-2 py-2">{item.destinatarios.join(', ')}</td>
+              <td className="px-2 py-2">{item.asunto}</td>
+              <td className="px-2 py-2">{PLANTILLAS[item.plantilla] ?? item.plantilla}</td>
+              <td className="px-2 py-2">
+                <EstadoBadge estado={item.estado} />
+              </td>
+              <td className="px-2 py-2">{item.intentos}</td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    </div>
+  );
+}
+
+function cuerpoCorreo(detalle: CorreoSalienteDetalle): ReactNode {
+  if (detalle.sensible) {
+    return (
+      <p className="text-sm text-gray-500">
+        Contiene un enlace de un solo uso (recuperación o verificación): el cuerpo no se muestra.
+      </p>
+    );
+  }
+  if (detalle.cuerpo_html) {
+    return (
+      <iframe
+        title={`Correo ${detalle.id}`}
+        sandbox=""
+        srcDoc={detalle.cuerpo_html}
+        className="h-[32rem] w-full rounded border border-gray-200 bg-white dark:border-gray-600"
+      />
+    );
+  }
+  return (
+    <pre className="max-h-[32rem] overflow-auto whitespace-pre-wrap rounded bg-gray-50 p-3 text-xs dark:bg-gray-900">
+      {detalle.cuerpo_texto || '—'}
+    </pre>
+  );
+}
+
+export function AdministracionCorreosPage() {
+  const { roles } = useAuth();
+  const canManage = hasAnyRole(roles, ['SUPER ADMINISTRADOR', 'ADMINISTRADOR']);
+  const [items, setItems] = useState<CorreoSalienteItem[]>([]);
+  const [resumen, setResumen] = useState<CorreosSalientesResumen | null>(null);
+  const [total, setTotal] = useState(0);
+  const [page, setPage] = useState(1);
+  const [estado, setEstado] = useState<CorreoSalienteEstado | ''>('');
+  const [destinatario, setDestinatario] = useState('');
+  const [loading, setLoading] = useState(true);
+  const [detalle, setDetalle] = useState<CorreoSalienteDetalle | null>(null);
+  const [reintentando, setReintentando] = useState(false);
+
+  const load = useCallback(async () => {
+    setLoading(true);
+    try {
+      const res = await apiService.getCorreosSalientes({
+        estado: estado || undefined,
+        destinatario: destinatario.trim() || undefined,
+        page,
+        page_size: PAGE_SIZE,
+      });
+      setItems(res.data);
+      setTotal(res.total);
+      setResumen(res.resumen);
+    } catch (err: unknown) {
+      globalThis.alert(axiosErrorMessage(err, 'No se pudo cargar la cola de correos'));
+    } finally {
+      setLoading(false);
+    }
+  }, [estado, destinatario, page]);
+
+  useEffect(() => {
+    if (!canManage) {
+      setLoading(false);
+      return;
+    }
+    void load();
+  }, [canManage, load]);
+
+  const seleccionar = async (id: number) => {
+    try {
+      setDetalle(await apiService.getCorreoSaliente(id));
+    } catch (err: unknown) {
+      globalThis.alert(axiosErrorMessage(err, 'No se pudo cargar el correo'));
+    }
+  };
+
+  const reintentar = async () => {
+    if (!detalle) return;
+    setReintentando(true);
+    try {
+      await apiService.reintentarCorreoSaliente(detalle.id);
+      await Promise.all([load(), seleccionar(detalle.id)]);
+    } catch (err: unknown) {
+      globalThis.alert(axiosErrorMessage(err, 'No se pudo reintentar el envío'));
+    } finally {
+      setReintentando(false);
+    }
+  };
+
+  const descargarAdjunto = async (adjuntoId: number, nombre: string) => {
+    if (!detalle) return;
+    try {
+      const blob = await apiService.descargarAdjuntoCorreoSaliente(detalle.id, adjuntoId);
+      const url = URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = nombre;
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (err: unknown) {
+      globalThis.alert(axiosErrorMessage(err, 'No se pudo descargar el adjunto'));
+    }
+  };
+
+  if (!canManage) {
+    return (
+      <div className="p-6">
+        <p className="text-sm text-gray-600 dark:text-gray-400">No tiene permisos para administrar esta sección.</p>
+      </div>
+    );
+  }
+
+  const totalPaginas = Math.max(1, Math.ceil(total / PAGE_SIZE));
+
+  return (
+    <div className="mx-auto max-w-6xl space-y-6 p-4 sm:p-6">
+      <div>
+        <h1 className="flex items-center gap-2 text-2xl font-bold text-gray-900 dark:text-white">
+          <EnvelopeIcon className="h-7 w-7 text-emerald-600" aria-hidden />
+          Correos salientes
+        </h1>
+        <p className="mt-1 text-sm text-gray-600 dark:text-gray-400">
+          Cola de envío de correos. Los que fallan se reintentan solos con espera creciente
+          {resumen ? ` (hasta ${resumen.max_intentos} intentos)` : ''}; los fallidos se pueden reintentar a mano.
+        </p>
+      </div>
+
+      {resumen && !resumen.smtp_habilitado && (
+        <div className="rounded border border-amber-300 bg-amber-50 p-3 text-sm text-amber-800 dark:border-amber-700 dark:bg-amber-900/30 dark:text-amber-200">
+          El envío SMTP está deshabilitado: no se encolan ni envían correos nuevos.
+        </div>
+      )}
+
+      {resumen && (
+        <div className="grid grid-cols-3 gap-3">
+          {(
+            [
+              ['pendiente', 'Pendientes', resumen.pendientes],
+              ['enviado', 'Enviados', resumen.enviados],
+              ['fallido', 'Fallidos', resumen.fallidos],
+            ] as const
+          ).map(([clave, label, n]) => (
+            <button
+              key={clave}
+              type="button"
+              className={`card p-3 text-left ${estado === clave ? 'ring-2 ring-emerald-500' : ''}`}
+              onClick={() => {
+                setEstado(estado === clave ? '' : clave);
+                setPage(1);
+              }}
+            >
+              <div className="text-xs uppercase text-gray-500">{label}</div>
+              <div className="text-2xl font-semibold text-gray-900 dark:text-white">{n}</div>
+            </button>
+          ))}
+        </div>
+      )}
+
+      <div className="card p-4">
+        <div className="mb-3 flex flex-wrap items-end gap-3">
+          <div>
+            <label htmlFor="correos-estado" className="mb-1 block text-xs text-gray-500">
+              Estado
+            </label>
+            <select
+              id="correos-estado"
+              className="input-field"
+              value={estado}
+              onChange={(e) => {
+                setEstado(e.target.value as CorreoSalienteEstado | '');
+                setPage(1);
+              }}
+            >
+              <option value="">Todos</option>
+              <option value="pendiente">Pendientes</option>
+              <option value="enviado">Enviados</option>
+              <option value="fallido">Fallidos</option>
+            </select>
+          </div>
+          <div className="min-w-[16rem] flex-1 max-w-md">
+            <label htmlFor="correos-destinatario" className="mb-1 block text-xs text-gray-500">
+              Destinatario
+            </label>
+            <input
+              id="correos-destinatario"
+              className="input-field w-full"
+              value={destinatario}
+              onChange={(e) => {
+                setDestinatario(e.target.value);
+                setPage(1);
+              }}
+              placeholder="correo@sena.edu.co"
+            />
+          </div>
+          <button type="button" className="btn-secondary inline-flex items-center gap-1" onClick={() => void load()}>
+            <ArrowPathIcon className="h-4 w-4" aria-hidden />
+            Actualizar
+          </button>
+        </div>
+        {cuerpoTablaCorreos(loading, items, detalle?.id ?? null, (id) => void seleccionar(id))}
+        {totalPaginas > 1 && (
+          <div className="mt-3 flex items-center justify-end gap-2 text-sm">
+            <button
+              type="button"
+              className="btn-secondary px-2 py-1 text-xs"
+              disabled={page <= 1}
+              onClick={() => setPage(page - 1)}
+            >
+              Anterior
+            </button>
+            <span className="text-gray-600 dark:text-gray-400">
+              Página {page} de {totalPaginas}
+            </span>
+            <button
+              type="button"
+              className="btn-secondary px-2 py-1 text-xs"
+              disabled={page >= totalPaginas}
+              onClick={() => setPage(page + 1)}
+            >
+              Siguiente
+            </button>
+          </div>
+        )}
+      </div>
+
+      {detalle && (
+        <div className="card space-y-4 p-4">
+          <div className="flex flex-wrap items-start justify-between gap-3">
+            <div>
+              <h2 className="text-sm font-semibold text-gray-800 dark:text-gray-100">{detalle.asunto}</h2>
+              <p className="mt-1 text-xs text-gray-500">
+                Para: {detalle.destinatarios.join(', ')} · {PLANTILLAS[detalle.plantilla] ?? detalle.plantilla}
+              </p>
+            </div>
+            <div className="flex items-center gap-2">
+              <EstadoBadge estado={detalle.estado} />
+              {detalle.estado === 'fallido' && !detalle.sensible && (
+                <button
+                  type="button"
+                  className="btn-primary inline-flex items-center gap-1 px-2 py-1 text-xs"
+                  onClick={reintentar}
+                  disabled={reintentando}
+                >
+                  <ArrowPathIcon className="h-4 w-4" aria-hidden />
+                  {reintentando ? 'Reintentando…' : 'Reintentar'}
+                </button>
+              )}
+            </div>
+          </div>
+          <dl className="grid grid-cols-2 gap-2 text-xs sm:grid-cols-4">
+            <div>
+              <dt className="text-gray-500">Creado</dt>
+              <dd>{formatFechaHoraVista(detalle.created_at)}</dd>
+            </div>
+            <div>
+              <dt className="text-gray-500">Enviado</dt>
+              <dd>{formatFechaHoraVista(detalle.enviado_at)}</dd>
+            </div>
+            <div>
+              <dt className="text-gray-500">Intentos</dt>
+              <dd>{detalle.intentos}</dd>
+            </div>
+            <div>
+              <dt className="text-gray-500">Próximo intento</dt>
+              <dd>{detalle.estado === 'pendiente' ? formatFechaHoraVista(detalle.proximo_intento_at) : '—'}</dd>
+            </div>
+          </dl>
+          {detalle.ultimo_error && (
+            <p className="rounded bg-red-50 p-2 text-xs text-red-700 dark:bg-red-900/30 dark:text-red-200">
+              Último error: {detalle.ultimo_error}
+            </p>
+          )}
+          {detalle.adjuntos.length > 0 && (
+            <ul className="flex flex-wrap gap-2">
+              {detalle.adjuntos.map((a) => (
+                <li key={a.id}>
+                  <button
+                    type="button"
+                    className="btn-secondary inline-flex items-center gap-1 px-2 py-1 text-xs"
+                    onClick={() => void descargarAdjunto(a.id, a.nombre)}
+                  >
+                    <PaperClipIcon className="h-4 w-4" aria-hidden />
+                    {a.nombre} ({Math.ceil(a.tamano / 1024)} KB)
+                  </button>
+                </li>
+              ))}
+            </ul>
+          )}
+          {cuerpoCorreo(detalle)}
+        </div>
+      )}
+    </div>
+  );
+}
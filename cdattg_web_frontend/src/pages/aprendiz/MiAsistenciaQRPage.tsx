@@ -0,0 +1,90 @@
+import { useState } from 'react';
+import { Navigate } from 'react-router-dom';
+import { CheckCircleIcon, QrCodeIcon } from '@heroicons/react/24/outline';
+import { EscanerQR } from '../../components/EscanerQR';
+import { useAuth } from '../../context/AuthContext';
+import { apiService } from '../../services/api';
+import type { AsistenciaAprendizResponse } from '../../types';
+import { axiosErrorMessage } from '../../utils/httpError';
+import { formatHoraVista } from '../../utils/formatFecha';
+import { canRegistrarMiAsistencia, MENSAJE_SIN_PERMISO_MI_ASISTENCIA } from './miAsistenciaPermissions';
+
+/** Autorregistro: el aprendiz escanea el código rotativo que muestra el instructor en la sesión. */
+export function MiAsistenciaQRPage() {
+  const { roles, permissions } = useAuth();
+  const [escaneando, setEscaneando] = useState(true);
+  const [enviando, setEnviando] = useState(false);
+  const [resultado, setResultado] = useState<AsistenciaAprendizResponse | null>(null);
+  const [error, setError] = useState('');
+
+  if (!canRegistrarMiAsistencia(roles, permissions)) {
+    return <Navigate to="/perfil" replace state={{ message: MENSAJE_SIN_PERMISO_MI_ASISTENCIA }} />;
+  }
+
+  const handleEscaneado = async (codigo: string) => {
+    setEscaneando(false);
+    setEnviando(true);
+    setError('');
+    setResultado(null);
+    try {
+      setResultado(await apiService.autorregistrarAsistencia(codigo));
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo registrar la asistencia.'));
+    } finally {
+      setEnviando(false);
+    }
+  };
+
+  const hora = resultado?.tipo_registro === 'salida' ? resultado.hora_salida : resultado?.hora_ingreso;
+
+  return (
+    <div className="mx-auto max-w-xl space-y-6">
+      <div>
+        <h1 className="text-2xl font-bold text-gray-900 dark:text-white">Registrar mi asistencia</h1>
+        <p className="mt-1 text-sm text-gray-600 dark:text-gray-400">
+          Escanee el código QR que muestra su instructor en la sesión. El primer escaneo marca la entrada y el siguiente
+          (al menos 1 minuto después) la salida.
+        </p>
+      </div>
+
+      <div className="rounded-xl border border-gray-200 bg-white p-5 shadow-sm dark:border-gray-700 dark:bg-gray-800">
+        {escaneando ? (
+          <EscanerQR activo={escaneando} embedded onEscaneado={handleEscaneado} readerId="qr-mi-asistencia" />
+        ) : (
+          <div className="space-y-4">
+            {enviando ? <p className="text-sm text-gray-600 dark:text-gray-300">Registrando…</p> : null}
+            {resultado ? (
+              <div className="flex items-start gap-3 rounded-lg border border-green-200 bg-green-50 px-4 py-3 text-sm text-green-900 dark:border-green-800 dark:bg-green-950/40 dark:text-green-100">
+                <CheckCircleIcon className="h-5 w-5 shrink-0" aria-hidden />
+                <div>
+                  <p className="font-medium">{resultado.mensaje || 'Asistencia registrada'}</p>
+                  {resultado.ficha_numero || hora ? (
+                    <p className="mt-0.5">
+                      {resultado.ficha_numero ? `Ficha ${resultado.ficha_numero}` : null}
+                      {resultado.ficha_numero && hora ? ' · ' : null}
+                      {hora ? formatHoraVista(hora) : null}
+                    </p>
+                  ) : null}
+                </div>
+              </div>
+            ) : null}
+            {error ? (
+              <p className="rounded-lg border border-red-200 bg-red-50 px-4 py-3 text-sm text-red-800 dark:border-red-800 dark:bg-red-950/40 dark:text-red-200">
+                {error}
+              </p>
+            ) : null}
+            <button
+              type="button"
+              onClick={() => setEscaneando(true)}
+              disabled={enviando}
+              className="btn-primary inline-flex items-center gap-1.5 text-sm disabled:opacity-50"
+            >
+              <QrCodeIcon className="h-4 w-4" aria-hidden />
+              Escanear de nuevo
+            </button>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
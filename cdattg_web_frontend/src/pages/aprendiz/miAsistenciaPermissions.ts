@@ -0,0 +1,10 @@
+export const PERM_REGISTRAR_MI_ASISTENCIA = 'REGISTRAR MI ASISTENCIA';
+
+export function canRegistrarMiAsistencia(roles: string[], permissions: string[]): boolean {
+  const normalized = roles.map((r) => r.toUpperCase());
+  if (normalized.includes('APRENDIZ')) return true;
+  return permissions.includes('*') || permissions.includes(PERM_REGISTRAR_MI_ASISTENCIA);
+}
+
+export const MENSAJE_SIN_PERMISO_MI_ASISTENCIA =
+  'No tiene permiso para registrar su asistencia (requiere rol Aprendiz).';
@@ -0,0 +1,96 @@
+import { useEffect, useState } from 'react';
+import { DevicePhoneMobileIcon } from '@heroicons/react/24/outline';
+import { apiService } from '../../services/api';
+import type { AsistenciaCodigoQRResponse } from '../../types';
+import { axiosErrorMessage } from '../../utils/httpError';
+import { AsistenciaCollapsibleCard } from './AsistenciaCollapsibleCard';
+import type { AsistenciaAccordionSectionProps } from './asistenciaConstants';
+import type { AsistenciaSesionPageState } from './useAsistenciaSesion';
+
+type Props = Readonly<{ page: AsistenciaSesionPageState } & AsistenciaAccordionSectionProps>;
+
+/** Margen para pedir el siguiente código apenas cambia la ventana en el servidor. */
+const MARGEN_REFRESCO_MS = 300;
+
+/**
+ * Muestra el código QR rotativo de la sesión para que cada aprendiz lo escanee desde su cuenta.
+ * El código se renueva al vencer; los registros de los aprendices llegan a la lista por el canal en vivo de la sesión.
+ */
+export function AsistenciaCodigoRotativoCard({ page, open, onToggle }: Props) {
+  const sesionId = page.sesionActual?.id;
+  const activo = open && Boolean(sesionId) && !page.sesionSoloLectura;
+  const [codigo, setCodigo] = useState<AsistenciaCodigoQRResponse | null>(null);
+  const [error, setError] = useState('');
+  const [segundos, setSegundos] = useState(0);
+
+  useEffect(() => {
+    if (!activo || !sesionId) {
+      setCodigo(null);
+      return;
+    }
+    let cancelado = false;
+    let timer: ReturnType<typeof setTimeout> | undefined;
+
+    const cargar = async () => {
+      try {
+        const resp = await apiService.getCodigoQRAsistencia(sesionId);
+        if (cancelado) return;
+        setCodigo(resp);
+        setSegundos(resp.segundos_restantes);
+        setError('');
+        timer = setTimeout(() => void cargar(), Math.max(resp.segundos_restantes, 1) * 1000 + MARGEN_REFRESCO_MS);
+      } catch (e: unknown) {
+        if (cancelado) return;
+        setCodigo(null);
+        setError(axiosErrorMessage(e, 'No se pudo generar el código de la sesión.'));
+      }
+    };
+
+    void cargar();
+    return () => {
+      cancelado = true;
+      if (timer) clearTimeout(timer);
+    };
+  }, [activo, sesionId]);
+
+  useEffect(() => {
+    if (!codigo) return;
+    const id = setInterval(() => setSegundos((s) => Math.max(s - 1, 0)), 1000);
+    return () => clearInterval(id);
+  }, [codigo]);
+
+  if (!sesionId || page.sesionSoloLectura) return null;
+
+  return (
+    <AsistenciaCollapsibleCard
+      open={open}
+      onToggle={onToggle}
+      title="Autorregistro de aprendices"
+      description="Proyecte este código; cada aprendiz lo escanea desde su cuenta para marcar entrada o salida."
+      icon={<DevicePhoneMobileIcon className="h-6 w-6" />}
+    >
+      <p className="mb-3 text-xs text-gray-500 dark:text-gray-400">
+        El código cambia cada {codigo?.periodo_segundos ?? 30} segundos y cada aprendiz solo puede usarlo una vez; una
+        foto del código deja de servir en menos de un minuto. Para marcar salida el aprendiz escanea de nuevo (al menos 1
+        minuto después de la entrada).
+      </p>
+      {codigo ? (
+        <div className="flex flex-col items-center gap-2">
+          <img
+            src={`data:image/svg+xml;charset=utf-8,${encodeURIComponent(codigo.svg)}`}
+            alt="Código QR de autorregistro de la sesión"
+            className="h-64 w-64 rounded-lg bg-white p-2 shadow-sm"
+          />
+          <p className="text-sm text-gray-600 dark:text-gray-300" aria-live="polite">
+            Cambia en {segundos} s
+          </p>
+        </div>
+      ) : null}
+      {error ? (
+        <p className="mt-3 rounded-lg border border-red-200 bg-red-50 px-3 py-2 text-sm text-red-800 dark:border-red-800 dark:bg-red-950/40 dark:text-red-200">
+          {error}
+        </p>
+      ) : null}
+    </AsistenciaCollapsibleCard>
+  );
+}
@@ -1,5 +1,6 @@
 import { useCallback, useState } from 'react';
 import type { AsistenciaMetodoRegistroId } from './asistenciaConstants';
+import { AsistenciaCodigoRotativoCard } from './AsistenciaCodigoRotativoCard';
 import { AsistenciaRegistroDocumentoCard } from './AsistenciaRegistroDocumentoCard';
 import { AsistenciaRegistroGrupalCard } from './AsistenciaRegistroGrupalCard';
 import { AsistenciaRegistroIndividualCard } from './AsistenciaRegistroIndividualCard';
@@ -22,6 +23,11 @@ export function AsistenciaMetodosAccordion({ page, sesionId }: Props) {
   return (
     <section className="space-y-3" aria-label="Métodos de registro de asistencia" data-sesion-id={sesionId}>
       <AsistenciaRegistroQrCard page={page} open={metodoAbierto === 'qr'} onToggle={() => toggleMetodo('qr')} />
+      <AsistenciaCodigoRotativoCard
+        page={page}
+        open={metodoAbierto === 'codigo'}
+        onToggle={() => toggleMetodo('codigo')}
+      />
       <AsistenciaRegistroDocumentoCard
         page={page}
         open={metodoAbierto === 'documento'}
@@ -13,7 +13,7 @@ export const ASIST_MODAL_IDS_ROOT = {
 
 export const ASIST_REGISTRO_DOC_INPUT_ID = 'asistencia-registro-doc-manual';
 
-export type AsistenciaMetodoRegistroId = 'documento' | 'qr' | 'individual' | 'grupal';
+export type AsistenciaMetodoRegistroId = 'documento' | 'qr' | 'codigo' | 'individual' | 'grupal';
 
 export type AsistenciaAccordionSectionProps = Readonly<{
   open: boolean;
@@ -2,6 +2,7 @@ import { useState, useEffect, useCallback, useRef, useMemo, type ComponentProps
 import { apiService } from '../../services/api';
 import { axiosErrorMessage } from '../../utils/httpError';
 import type {
+  AsistenciaEvento,
   AsistenciaResponse,
   AprendizResponse,
   AsistenciaAprendizResponse,
@@ -27,6 +28,7 @@ import {
   interpretarRespuestaRegistroAsistencia,
 } from './asistenciaRegistroMensajes';
 import type { AsistenciaModalsModel } from './asistenciaModalsTypes';
+import { useAsistenciaSesionEnVivo } from './useAsistenciaSesionEnVivo';
 
 type UseAsistenciaRegistroParams = Readonly<{
   fichaId: number;
@@ -106,6 +108,15 @@ export function useAsistenciaRegistro({
     }
   }, [fichaId]);
 
+  /** Recarga solo los registros de la sesión (p. ej. al reconectar la lista en vivo). */
+  const refrescarAprendicesEnSesion = useCallback(async (asistenciaId: number) => {
+    try {
+      setAprendicesEnSesion(await apiService.getAsistenciaAprendices(asistenciaId));
+    } catch {
+      /* se conserva el listado actual; el siguiente refresco lo reintenta */
+    }
+  }, []);
+
   const sesionId = sesionActual?.id;
 
   useEffect(() => {
@@ -117,6 +128,31 @@ export function useAsistenciaRegistro({
     if (sesionActual == null) setAprendicesFicha([]);
   }, [sesionActual, fichaId, loadAprendicesYSesion]);
 
+  const onEventoSesion = useCallback(
+    (evento: AsistenciaEvento) => {
+      if (evento.type === 'sesion_cerrada') {
+        setSesionActual((prev) => (prev?.id === evento.asistencia_id ? { ...prev, is_finished: true } : prev));
+        return;
+      }
+      if (evento.type === 'registro_eliminado') {
+        setAprendicesEnSesion((prev) => prev.filter((aa) => aa.id !== evento.asistencia_aprendiz_id));
+        return;
+      }
+      if (evento.registro) upsertAsistenciaAprendizEnSesion(evento.registro);
+      else if (evento.asistencia_aprendiz_id) void refrescarAprendicesEnSesion(evento.asistencia_id);
+    },
+    [setSesionActual, upsertAsistenciaAprendizEnSesion, refrescarAprendicesEnSesion],
+  );
+
+  useAsistenciaSesionEnVivo({
+    asistenciaId: sesionId,
+    activo: !sesionSoloLectura,
+    onEvento: onEventoSesion,
+    onConectado: () => {
+      if (sesionId != null) void refrescarAprendicesEnSesion(sesionId);
+    },
+  });
+
   useEffect(() => {
     if (sesionId == null) return;
     apiService.getTiposObservacionAsistencia().then(setTiposObservacionCatalog).catch(() => setTiposObservacionCatalog([]));
@@ -0,0 +1,60 @@
+import { useEffect, useRef } from 'react';
+import { getAsistenciaSesionWsUrl } from '../../config/api';
+import { useAuth } from '../../context/AuthContext';
+import type { AsistenciaEvento } from '../../types';
+
+const RECONEXION_MS = 5000;
+
+type Params = Readonly<{
+  asistenciaId?: number;
+  activo: boolean;
+  onEvento: (evento: AsistenciaEvento) => void;
+  /** Al (re)conectar: permite recargar lo que se haya perdido mientras no hubo conexión. */
+  onConectado?: () => void;
+}>;
+
+/** Suscribe la vista del instructor a los eventos de su sesión (ingresos, salidas, ajustes, cierre). */
+export function useAsistenciaSesionEnVivo({ asistenciaId, activo, onEvento, onConectado }: Params) {
+  const { token } = useAuth();
+  const onEventoRef = useRef(onEvento);
+  const onConectadoRef = useRef(onConectado);
+  onEventoRef.current = onEvento;
+  onConectadoRef.current = onConectado;
+
+  useEffect(() => {
+    if (!activo || !asistenciaId || !token) return;
+    let ws: WebSocket | null = null;
+    let reconexion: ReturnType<typeof setTimeout> | undefined;
+    let cerrado = false;
+    let primeraConexion = true;
+
+    const connect = () => {
+      ws = new WebSocket(getAsistenciaSesionWsUrl(asistenciaId, token));
+      ws.onopen = () => {
+        if (!primeraConexion) onConectadoRef.current?.();
+        primeraConexion = false;
+      };
+      ws.onclose = () => {
+        ws = null;
+        if (!cerrado) reconexion = setTimeout(connect, RECONEXION_MS);
+      };
+      ws.onerror = () => {
+        ws?.close();
+      };
+      ws.onmessage = (event) => {
+        try {
+          onEventoRef.current(JSON.parse(event.data as string) as AsistenciaEvento);
+        } catch {
+          // ignorar mensajes mal formados
+        }
+      };
+    };
+
+    connect();
+    return () => {
+      cerrado = true;
+      if (reconexion) clearTimeout(reconexion);
+      ws?.close();
+    };
+  }, [activo, asistenciaId, token]);
+}
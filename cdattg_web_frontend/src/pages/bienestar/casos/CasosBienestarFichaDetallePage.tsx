@@ -156,7 +156,7 @@ export function CasosBienestarFichaDetallePage() {
                 Registro de aprendices en alerta
               </h2>
               <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
-                Consulte el detalle de inasistencias o descargue el reporte PDF individual por aprendiz.
+                Consulte el detalle de inasistencias, descargue el reporte PDF individual o abra un caso de seguimiento.
               </p>
             </div>
             <CasosBienestarAprendicesTable
@@ -166,8 +166,10 @@ export function CasosBienestarFichaDetallePage() {
               minFallas={page.minFallas}
               busquedaActiva={page.busquedaActiva}
               pdfDescargandoId={page.pdfDescargandoId}
+              abriendoCasoId={page.abriendoCasoId}
               onVerDetalle={(c) => void page.abrirDetalleAprendiz(c)}
               onDescargarPdf={(c) => void page.descargarReportePdfAprendiz(c)}
+              onAbrirCaso={(c) => void page.abrirCasoAprendiz(c)}
             />
           </div>
         </>
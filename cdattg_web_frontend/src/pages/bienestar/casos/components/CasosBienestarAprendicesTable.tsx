@@ -1,8 +1,12 @@
+import { Link } from 'react-router-dom';
 import {
   ArrowDownTrayIcon,
   ArrowPathIcon,
+  ClipboardDocumentCheckIcon,
   DocumentMagnifyingGlassIcon,
+  FolderPlusIcon,
 } from '@heroicons/react/24/outline';
+import { bienestarPaths } from '../../bienestarPaths';
 import type { CasoBienestarItem } from '../../../../types';
 import { nivelAlertaInasistencias, porcentajeAsistenciaAprendiz } from '../casosBienestarUtils';
 
@@ -13,8 +17,10 @@ type CasosBienestarAprendicesTableProps = Readonly<{
   minFallas: number;
   busquedaActiva: boolean;
   pdfDescargandoId: number | null;
+  abriendoCasoId: number | null;
   onVerDetalle: (caso: CasoBienestarItem) => void;
   onDescargarPdf: (caso: CasoBienestarItem) => void;
+  onAbrirCaso: (caso: CasoBienestarItem) => void;
 }>;
 
 function inicialesNombre(nombre: string): string {
@@ -80,8 +86,10 @@ export function CasosBienestarAprendicesTable({
   minFallas,
   busquedaActiva,
   pdfDescargandoId,
+  abriendoCasoId,
   onVerDetalle,
   onDescargarPdf,
+  onAbrirCaso,
 }: CasosBienestarAprendicesTableProps) {
   if (casosTotal === 0) {
     return (
@@ -225,6 +233,28 @@ export function CasosBienestarAprendicesTable({
                         )}
                         <span className="hidden sm:inline">{pdfCargando ? 'Generando…' : 'PDF'}</span>
                       </button>
+                      {c.caso_id ? (
+                        <Link
+                          to={bienestarPaths.seguimiento.detalle(c.caso_id)}
+                          className="btn-secondary inline-flex items-center gap-1 px-2.5 py-1.5 text-xs"
+                          title="Ver caso de seguimiento"
+                        >
+                          <ClipboardDocumentCheckIcon className="h-4 w-4" aria-hidden />
+                          <span className="hidden sm:inline">Ver caso</span>
+                        </Link>
+                      ) : (
+                        <button
+                          type="button"
+                          onClick={() => onAbrirCaso(c)}
+                          disabled={abriendoCasoId != null}
+                          className="btn-secondary inline-flex items-center gap-1 px-2.5 py-1.5 text-xs disabled:opacity-50"
+                          title="Abrir caso de seguimiento"
+                          aria-busy={abriendoCasoId === c.aprendiz_id}
+                        >
+                          <FolderPlusIcon className="h-4 w-4" aria-hidden />
+                          <span className="hidden sm:inline">Abrir caso</span>
+                        </button>
+                      )}
                     </div>
                   </td>
                 </tr>
@@ -1,5 +1,5 @@
 import { useCallback, useEffect, useMemo, useState } from 'react';
-import { useParams, useSearchParams } from 'react-router-dom';
+import { useNavigate, useParams, useSearchParams } from 'react-router-dom';
 import { apiService } from '../../../../services/api';
 import { axiosErrorMessage } from '../../../../utils/httpError';
 import { useAuth } from '../../../../context/AuthContext';
@@ -7,11 +7,13 @@ import { canViewCasosBienestar, MENSAJE_SIN_PERMISO_CASOS_BIENESTAR } from '../c
 import { generarReportePdfAprendiz } from '../casosBienestarReportePdf';
 import { casosDeFicha, filtrarCasosAprendiz, parseDiasCasosBienestarParam } from '../casosBienestarUtils';
 import type { CasoBienestarItem, InasistenciaDetalleItem } from '../../../../types';
+import { bienestarPaths } from '../../bienestarPaths';
 import { useCasosBienestar } from './useCasosBienestar';
 
 export function useCasosBienestarFichaDetalle() {
   const { roles } = useAuth();
   const { fichaNumero } = useParams<{ fichaNumero: string }>();
+  const navigate = useNavigate();
   const [searchParams, setSearchParams] = useSearchParams();
   const canView = canViewCasosBienestar(roles);
 
@@ -62,6 +64,7 @@ export function useCasosBienestarFichaDetalle() {
   );
   const [pdfDescargandoId, setPdfDescargandoId] = useState<number | null>(null);
   const [pdfError, setPdfError] = useState('');
+  const [abriendoCasoId, setAbriendoCasoId] = useState<number | null>(null);
 
   const { data, loading, error, setError, setLoading } = useCasosBienestar({
     enabled: canView && Boolean(fichaNumero),
@@ -182,6 +185,33 @@ export function useCasosBienestarFichaDetalle() {
     [fichaNumero, dias, minFallas, sedeNombreParam, pdfDescargandoId],
   );
 
+  /** Abre el caso de seguimiento con la foto de inasistencias del período consultado y navega a él. */
+  const abrirCasoAprendiz = useCallback(
+    async (aprendiz: CasoBienestarItem) => {
+      if (abriendoCasoId != null) return;
+      setPdfError('');
+      setAbriendoCasoId(aprendiz.aprendiz_id);
+      try {
+        const res = await apiService.abrirCasosBienestarDesdeCalculo({
+          aprendiz_ids: [aprendiz.aprendiz_id],
+          dias,
+          min_fallas: minFallas,
+        });
+        const casoId = res.creados[0]?.id ?? res.omitidos[0]?.caso_id;
+        if (casoId) {
+          navigate(bienestarPaths.seguimiento.detalle(casoId));
+          return;
+        }
+        setPdfError(res.omitidos[0]?.motivo ?? 'No fue posible abrir el caso.');
+      } catch (e: unknown) {
+        setPdfError(axiosErrorMessage(e, 'No fue posible abrir el caso de seguimiento.'));
+      } finally {
+        setAbriendoCasoId(null);
+      }
+    },
+    [abriendoCasoId, dias, minFallas, navigate],
+  );
+
   return {
     canView,
     permissionError: MENSAJE_SIN_PERMISO_CASOS_BIENESTAR,
@@ -213,6 +243,8 @@ export function useCasosBienestarFichaDetalle() {
     descargarReportePdfAprendiz,
     pdfDescargandoId,
     pdfError,
+    abrirCasoAprendiz,
+    abriendoCasoId,
   };
 }
 
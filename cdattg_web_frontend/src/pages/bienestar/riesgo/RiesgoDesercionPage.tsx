@@ -0,0 +1,247 @@
+import { useCallback, useEffect, useState } from 'react';
+import { useSearchParams } from 'react-router-dom';
+import { apiService } from '../../../services/api';
+import { axiosErrorMessage } from '../../../utils/httpError';
+import { formatFechaVista } from '../../../utils/formatFecha';
+import { useAuth } from '../../../context/AuthContext';
+import type { NivelRiesgoDesercion, RiesgoDesercionItem, RiesgoDesercionResponse, SedeItem } from '../../../types';
+import { canViewCasosBienestar, MENSAJE_SIN_PERMISO_CASOS_BIENESTAR } from '../casos/casosBienestarPermissions';
+
+const NIVEL_BADGE: Record<NivelRiesgoDesercion, string> = {
+  alto: 'bg-red-100 text-red-800 dark:bg-red-900/40 dark:text-red-300',
+  medio: 'bg-amber-100 text-amber-800 dark:bg-amber-900/40 dark:text-amber-300',
+  bajo: 'bg-gray-100 text-gray-700 dark:bg-gray-700 dark:text-gray-300',
+};
+
+const NIVEL_LABEL: Record<NivelRiesgoDesercion, string> = {
+  alto: 'Alto',
+  medio: 'Medio',
+  bajo: 'Bajo',
+};
+
+const DIAS_OPCIONES = [15, 30, 60, 90];
+
+/** Texto corto con los factores que más aportan al puntaje. */
+function factoresPrincipales(a: RiesgoDesercionItem): string {
+  const f = a.factores;
+  const partes: [number, string][] = [
+    [a.aportes.inasistencia, `${f.pct_inasistencia}% inasistencia`],
+    [a.aportes.fallas_consecutivas, `${f.fallas_consecutivas} fallas seguidas`],
+    [
+      a.aportes.tendencia,
+      `sube desde ${f.pct_inasistencia_anterior ?? 0}%`,
+    ],
+    [a.aportes.retardos, `${f.retardos} retardos`],
+    [a.aportes.salidas_parciales, `${f.salidas_parciales} salidas parciales`],
+    [a.aportes.observaciones, 'observaciones'],
+    [a.aportes.raps_no_aprobados, `${f.raps_no_aprobados} RAP no aprobados`],
+  ];
+  return partes
+    .filter(([pts]) => pts > 0)
+    .sort((x, y) => y[0] - x[0])
+    .slice(0, 3)
+    .map(([, label]) => label)
+    .join(' · ');
+}
+
+export function RiesgoDesercionPage() {
+  const { roles } = useAuth();
+  const canView = canViewCasosBienestar(roles);
+  const [searchParams] = useSearchParams();
+  const fichaIdParam = Number(searchParams.get('ficha_id') || '') || undefined;
+
+  const [dias, setDias] = useState(30);
+  const [nivel, setNivel] = useState<NivelRiesgoDesercion | ''>('');
+  const [sedeId, setSedeId] = useState<number | ''>('');
+  const [sedes, setSedes] = useState<SedeItem[]>([]);
+  const [data, setData] = useState<RiesgoDesercionResponse | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState('');
+
+  const cargar = useCallback(async () => {
+    setLoading(true);
+    setError('');
+    try {
+      const res = await apiService.getRiesgoDesercion({
+        dias,
+        nivel: nivel || undefined,
+        sede_id: sedeId || undefined,
+        ficha_id: fichaIdParam,
+      });
+      setData(res);
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo calcular el riesgo de deserción.'));
+    } finally {
+      setLoading(false);
+    }
+  }, [dias, nivel, sedeId, fichaIdParam]);
+
+  useEffect(() => {
+    if (canView) void cargar();
+  }, [canView, cargar]);
+
+  useEffect(() => {
+    if (!canView) return;
+    apiService
+      .getCatalogosSedes()
+      .then(setSedes)
+      .catch(() => setSedes([]));
+  }, [canView]);
+
+  if (!canView) {
+    return (
+      <p role="alert" className="text-red-600 dark:text-red-400">
+        {MENSAJE_SIN_PERMISO_CASOS_BIENESTAR}
+      </p>
+    );
+  }
+
+  const aprendices = data?.aprendices ?? [];
+
+  return (
+    <div className="space-y-6">
+      <div className="max-w-3xl">
+        <h1 className="text-3xl font-bold text-gray-900 dark:text-white">Riesgo de deserción</h1>
+        <p className="mt-2 text-gray-600 dark:text-gray-400">
+          Puntaje de 0 a 100 por aprendiz que combina inasistencias, fallas consecutivas, tendencia frente al
+          período anterior, retardos, salidas parciales, observaciones con peso de riesgo y RAPs no aprobados.
+        </p>
+        {data && (
+          <p className="mt-2 text-xs text-gray-500 dark:text-gray-400">
+            Período {formatFechaVista(data.fecha_inicio)} – {formatFechaVista(data.fecha_fin)}; tendencia frente a{' '}
+            {formatFechaVista(data.fecha_inicio_anterior)} – {formatFechaVista(data.fecha_fin_anterior)}.
+          </p>
+        )}
+      </div>
+
+      <div className="flex flex-wrap items-end gap-4 rounded-xl border border-gray-200 bg-white p-4 dark:border-gray-600 dark:bg-gray-800">
+        <div>
+          <label htmlFor="riesgo-dias" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Período
+          </label>
+          <select
+            id="riesgo-dias"
+            value={dias}
+            onChange={(e) => setDias(Number(e.target.value))}
+            className="input-field min-w-[10rem]"
+          >
+            {DIAS_OPCIONES.map((d) => (
+              <option key={d} value={d}>
+                {d} días
+              </option>
+            ))}
+          </select>
+        </div>
+        <div>
+          <label htmlFor="riesgo-nivel" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Nivel
+          </label>
+          <select
+            id="riesgo-nivel"
+            value={nivel}
+            onChange={(e) => setNivel(e.target.value as NivelRiesgoDesercion | '')}
+            className="input-field min-w-[10rem]"
+          >
+            <option value="">Todos</option>
+            <option value="alto">Alto</option>
+            <option value="medio">Medio</option>
+            <option value="bajo">Bajo</option>
+          </select>
+        </div>
+        {!fichaIdParam && (
+          <div>
+            <label htmlFor="riesgo-sede" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+              Sede
+            </label>
+            <select
+              id="riesgo-sede"
+              value={sedeId}
+              onChange={(e) => setSedeId(e.target.value ? Number(e.target.value) : '')}
+              className="input-field min-w-[14rem]"
+            >
+              <option value="">Todas las sedes</option>
+              {sedes.map((s) => (
+                <option key={s.id} value={s.id}>
+                  {s.nombre}
+                </option>
+              ))}
+            </select>
+          </div>
+        )}
+      </div>
+
+      {error && (
+        <div
+          role="alert"
+          className="rounded-lg border border-red-200 bg-red-50 px-4 py-3 text-red-700 dark:border-red-800 dark:bg-red-900/30 dark:text-red-300"
+        >
+          {error}
+        </div>
+      )}
+
+      <div className="card overflow-hidden">
+        {loading && (
+          <div className="p-8 text-center text-gray-500 dark:text-gray-400" role="status" aria-live="polite">
+            Calculando riesgo…
+          </div>
+        )}
+        {!loading && aprendices.length === 0 && (
+          <div className="px-6 py-12 text-center text-sm text-gray-500 dark:text-gray-400">
+            Ningún aprendiz presenta factores de riesgo en el período.
+          </div>
+        )}
+        {!loading && aprendices.length > 0 && (
+          <div className="overflow-x-auto">
+            <table className="min-w-full divide-y divide-gray-200 dark:divide-gray-700">
+              <caption className="sr-only">Aprendices ordenados por riesgo de deserción</caption>
+              <thead>
+                <tr className="bg-gray-50 dark:bg-gray-800/80">
+                  <th className="px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Aprendiz
+                  </th>
+                  <th className="px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Ficha
+                  </th>
+                  <th className="px-4 py-3.5 text-right text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Puntaje
+                  </th>
+                  <th className="px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Nivel
+                  </th>
+                  <th className="hidden px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400 md:table-cell">
+                    Factores principales
+                  </th>
+                </tr>
+              </thead>
+              <tbody className="divide-y divide-gray-100 bg-white dark:divide-gray-700/80 dark:bg-gray-800/50">
+                {aprendices.map((a) => (
+                  <tr key={a.aprendiz_id} className="hover:bg-gray-50 dark:hover:bg-gray-700/40">
+                    <td className="px-4 py-3.5 text-sm">
+                      <p className="font-medium text-gray-900 dark:text-white">{a.persona_nombre}</p>
+                      <p className="text-xs tabular-nums text-gray-500 dark:text-gray-400">{a.numero_documento}</p>
+                    </td>
+                    <td className="px-4 py-3.5 text-sm text-gray-700 dark:text-gray-300">
+                      {a.ficha_numero}
+                      <p className="text-xs text-gray-500 dark:text-gray-400">{a.sede_nombre}</p>
+                    </td>
+                    <td className="px-4 py-3.5 text-right text-sm font-semibold tabular-nums text-gray-900 dark:text-white">
+                      {a.puntaje}
+                    </td>
+                    <td className="px-4 py-3.5">
+                      <span className={`inline-flex rounded-full px-2.5 py-0.5 text-xs font-semibold ${NIVEL_BADGE[a.nivel]}`}>
+                        {NIVEL_LABEL[a.nivel]}
+                      </span>
+                    </td>
+                    <td className="hidden px-4 py-3.5 text-sm text-gray-600 dark:text-gray-400 md:table-cell">
+                      {factoresPrincipales(a)}
+                    </td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,521 @@
+import { useCallback, useEffect, useState } from 'react';
+import type { FormEvent } from 'react';
+import { Link, useParams } from 'react-router-dom';
+import { ArrowDownTrayIcon, ArrowLeftIcon, CheckIcon, PaperClipIcon } from '@heroicons/react/24/outline';
+import { apiService } from '../../../services/api';
+import { axiosErrorMessage } from '../../../utils/httpError';
+import { formatFechaHoraVista, formatFechaVista } from '../../../utils/formatFecha';
+import { useAuth } from '../../../context/AuthContext';
+import type {
+  CasoBienestar,
+  CasoBienestarAdjunto,
+  CasoBienestarDetalle,
+  CasoBienestarEstado,
+  CasoBienestarIntervencionTipo,
+  ProfesionalBienestarItem,
+} from '../../../types';
+import { bienestarPaths } from '../bienestarPaths';
+import { canViewCasosBienestar, MENSAJE_SIN_PERMISO_CASOS_BIENESTAR } from '../casos/casosBienestarPermissions';
+import {
+  CASO_SEGUIMIENTO_ESTADO_LABEL,
+  CASO_SEGUIMIENTO_TIPOS_INTERVENCION,
+  labelTipoIntervencion,
+} from './casosSeguimientoConstants';
+import { CasoSeguimientoEstadoBadge } from './CasoSeguimientoEstadoBadge';
+
+function hoyISO(): string {
+  const d = new Date();
+  const mm = String(d.getMonth() + 1).padStart(2, '0');
+  const dd = String(d.getDate()).padStart(2, '0');
+  return `${d.getFullYear()}-${mm}-${dd}`;
+}
+
+export function CasoSeguimientoDetallePage() {
+  const { roles } = useAuth();
+  const canView = canViewCasosBienestar(roles);
+  const { casoId: casoIdParam } = useParams<{ casoId: string }>();
+  const casoId = Number(casoIdParam);
+
+  const [caso, setCaso] = useState<CasoBienestarDetalle | null>(null);
+  const [historial, setHistorial] = useState<CasoBienestar[]>([]);
+  const [profesionales, setProfesionales] = useState<ProfesionalBienestarItem[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState('');
+  const [busy, setBusy] = useState<string | null>(null);
+
+  const [estado, setEstado] = useState<CasoBienestarEstado>('abierto');
+  const [responsableId, setResponsableId] = useState('');
+  const [observacionCierre, setObservacionCierre] = useState('');
+
+  const [intFecha, setIntFecha] = useState(hoyISO());
+  const [intTipo, setIntTipo] = useState<CasoBienestarIntervencionTipo>('llamada');
+  const [intDescripcion, setIntDescripcion] = useState('');
+  const [intAcuerdos, setIntAcuerdos] = useState('');
+
+  const [recFecha, setRecFecha] = useState('');
+  const [recNota, setRecNota] = useState('');
+
+  const cargar = useCallback(async () => {
+    if (!Number.isFinite(casoId) || casoId <= 0) {
+      setError('Caso no válido.');
+      setLoading(false);
+      return;
+    }
+    setError('');
+    try {
+      const detalle = await apiService.getCasoBienestarSeguimiento(casoId);
+      setCaso(detalle);
+      setEstado(detalle.estado);
+      setResponsableId(detalle.responsable_user_id ? String(detalle.responsable_user_id) : '');
+      setObservacionCierre(detalle.observacion_cierre ?? '');
+      const otros = await apiService.getHistorialCasosBienestarAprendiz(detalle.aprendiz_id);
+      setHistorial(otros.filter((h) => h.id !== detalle.id));
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo cargar el caso.'));
+    } finally {
+      setLoading(false);
+    }
+  }, [casoId]);
+
+  useEffect(() => {
+    if (!canView) return;
+    void cargar();
+    apiService
+      .getProfesionalesBienestar()
+      .then(setProfesionales)
+      .catch(() => setProfesionales([]));
+  }, [canView, cargar]);
+
+  const ejecutar = async (accion: string, fn: () => Promise<unknown>, mensajeError: string) => {
+    setBusy(accion);
+    setError('');
+    try {
+      await fn();
+      await cargar();
+      return true;
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, mensajeError));
+      return false;
+    } finally {
+      setBusy(null);
+    }
+  };
+
+  const guardarCaso = async (e: FormEvent) => {
+    e.preventDefault();
+    await ejecutar(
+      'caso',
+      () =>
+        apiService.actualizarCasoBienestar(casoId, {
+          estado,
+          responsable_user_id: responsableId ? Number(responsableId) : undefined,
+          observacion_cierre: observacionCierre.trim() || undefined,
+        }),
+      'No se pudo actualizar el caso.',
+    );
+  };
+
+  const registrarIntervencion = async (e: FormEvent) => {
+    e.preventDefault();
+    const ok = await ejecutar(
+      'intervencion',
+      () =>
+        apiService.registrarIntervencionCasoBienestar(casoId, {
+          fecha: intFecha,
+          tipo: intTipo,
+          descripcion: intDescripcion.trim(),
+          acuerdos: intAcuerdos.trim() || undefined,
+        }),
+      'No se pudo registrar la intervención.',
+    );
+    if (ok) {
+      setIntDescripcion('');
+      setIntAcuerdos('');
+    }
+  };
+
+  const subirAdjunto = async (archivo: File | undefined) => {
+    if (!archivo) return;
+    await ejecutar('adjunto', () => apiService.subirAdjuntoCasoBienestar(casoId, archivo), 'No se pudo subir el adjunto.');
+  };
+
+  const descargarAdjunto = async (a: CasoBienestarAdjunto) => {
+    try {
+      const blob = await apiService.descargarAdjuntoCasoBienestar(casoId, a.id);
+      const url = URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = a.archivo_nombre;
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo descargar el adjunto.'));
+    }
+  };
+
+  const crearRecordatorio = async (e: FormEvent) => {
+    e.preventDefault();
+    if (!recFecha) return;
+    const ok = await ejecutar(
+      'recordatorio',
+      () =>
+        apiService.crearRecordatorioCasoBienestar(casoId, {
+          fecha_recordatorio: new Date(recFecha).toISOString(),
+          nota: recNota.trim(),
+        }),
+      'No se pudo crear el recordatorio.',
+    );
+    if (ok) {
+      setRecFecha('');
+      setRecNota('');
+    }
+  };
+
+  if (!canView) {
+    return (
+      <p role="alert" className="text-red-600 dark:text-red-400">
+        {MENSAJE_SIN_PERMISO_CASOS_BIENESTAR}
+      </p>
+    );
+  }
+
+  if (loading) {
+    return (
+      <div className="card p-8 text-center text-gray-500 dark:text-gray-400" role="status" aria-live="polite">
+        Cargando caso…
+      </div>
+    );
+  }
+
+  const cerrado = caso?.estado === 'cerrado';
+
+  return (
+    <div className="space-y-6">
+      <div className="flex flex-wrap items-start justify-between gap-4">
+        <div>
+          <h1 className="text-3xl font-bold text-gray-900 dark:text-white">
+            {caso ? caso.aprendiz_nombre : 'Caso de seguimiento'}
+          </h1>
+          {caso && (
+            <p className="mt-2 flex flex-wrap items-center gap-2 text-sm text-gray-600 dark:text-gray-400">
+              <span className="tabular-nums">{caso.numero_documento}</span>
+              <span>· Ficha {caso.ficha_numero}</span>
+              <span>· Caso #{caso.id}</span>
+              <CasoSeguimientoEstadoBadge estado={caso.estado} />
+            </p>
+          )}
+        </div>
+        <Link to={bienestarPaths.seguimiento.index} className="btn-secondary inline-flex shrink-0 items-center gap-2">
+          <ArrowLeftIcon className="h-5 w-5" aria-hidden />
+          Volver a los casos
+        </Link>
+      </div>
+
+      {error && (
+        <div
+          role="alert"
+          className="rounded-lg border border-red-200 bg-red-50 px-4 py-3 text-red-700 dark:border-red-800 dark:bg-red-900/30 dark:text-red-300"
+        >
+          {error}
+        </div>
+      )}
+
+      {caso && (
+        <>
+          <div className="grid grid-cols-1 gap-4 lg:grid-cols-2">
+            <div className="card p-5">
+              <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Resumen</h2>
+              <dl className="mt-3 space-y-2 text-sm">
+                <div>
+                  <dt className="font-medium text-gray-700 dark:text-gray-300">Motivo</dt>
+                  <dd className="text-gray-900 dark:text-white">{caso.motivo}</dd>
+                </div>
+                <div>
+                  <dt className="font-medium text-gray-700 dark:text-gray-300">Inasistencias al abrir el caso</dt>
+                  <dd className="text-gray-900 dark:text-white">
+                    {caso.inasistencias} sin justificar · {caso.inasistencias_justificadas} justificadas ·{' '}
+                    {caso.total_sesiones} sesiones
+                    {caso.periodo_inicio && (
+                      <span className="text-gray-500 dark:text-gray-400">
+                        {' '}
+                        ({formatFechaVista(caso.periodo_inicio)} – {formatFechaVista(caso.periodo_fin)})
+                      </span>
+                    )}
+                  </dd>
+                </div>
+                <div>
+                  <dt className="font-medium text-gray-700 dark:text-gray-300">Abierto</dt>
+                  <dd className="text-gray-900 dark:text-white">
+                    {formatFechaHoraVista(caso.created_at)} ({caso.origen === 'calculo' ? 'desde el cálculo' : 'manual'})
+                  </dd>
+                </div>
+                {caso.cerrado_at && (
+                  <div>
+                    <dt className="font-medium text-gray-700 dark:text-gray-300">Cerrado</dt>
+                    <dd className="text-gray-900 dark:text-white">
+                      {formatFechaHoraVista(caso.cerrado_at)}
+                      {caso.observacion_cierre && ` · ${caso.observacion_cierre}`}
+                    </dd>
+                  </div>
+                )}
+              </dl>
+            </div>
+
+            <form onSubmit={(e) => void guardarCaso(e)} className="card space-y-3 p-5">
+              <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Estado y responsable</h2>
+              <div>
+                <label htmlFor="caso-estado" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+                  Estado
+                </label>
+                <select
+                  id="caso-estado"
+                  value={estado}
+                  onChange={(e) => setEstado(e.target.value as CasoBienestarEstado)}
+                  className="input-field w-full"
+                >
+                  {(Object.keys(CASO_SEGUIMIENTO_ESTADO_LABEL) as CasoBienestarEstado[]).map((k) => (
+                    <option key={k} value={k}>
+                      {CASO_SEGUIMIENTO_ESTADO_LABEL[k]}
+                    </option>
+                  ))}
+                </select>
+              </div>
+              <div>
+                <label htmlFor="caso-responsable" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+                  Responsable
+                </label>
+                <select
+                  id="caso-responsable"
+                  value={responsableId}
+                  onChange={(e) => setResponsableId(e.target.value)}
+                  className="input-field w-full"
+                >
+                  <option value="">Sin asignar</option>
+                  {profesionales.map((p) => (
+                    <option key={p.user_id} value={p.user_id}>
+                      {p.nombre}
+                    </option>
+                  ))}
+                </select>
+              </div>
+              {estado === 'cerrado' && (
+                <div>
+                  <label htmlFor="caso-observacion-cierre" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+                    Observación de cierre
+                  </label>
+                  <textarea
+                    id="caso-observacion-cierre"
+                    value={observacionCierre}
+                    onChange={(e) => setObservacionCierre(e.target.value)}
+                    maxLength={1000}
+                    rows={3}
+                    required
+                    className="input-field w-full"
+                  />
+                </div>
+              )}
+              <button type="submit" disabled={busy != null} className="btn-primary disabled:opacity-50">
+                {busy === 'caso' ? 'Guardando…' : 'Guardar'}
+              </button>
+            </form>
+          </div>
+
+          <div className="card p-5">
+            <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Intervenciones</h2>
+            {!cerrado && (
+              <form onSubmit={(e) => void registrarIntervencion(e)} className="mt-3 grid grid-cols-1 gap-3 md:grid-cols-4">
+                <input
+                  type="date"
+                  aria-label="Fecha de la intervención"
+                  value={intFecha}
+                  max={hoyISO()}
+                  onChange={(e) => setIntFecha(e.target.value)}
+                  required
+                  className="input-field"
+                />
+                <select
+                  aria-label="Tipo de intervención"
+                  value={intTipo}
+                  onChange={(e) => setIntTipo(e.target.value as CasoBienestarIntervencionTipo)}
+                  className="input-field"
+                >
+                  {CASO_SEGUIMIENTO_TIPOS_INTERVENCION.map((t) => (
+                    <option key={t.value} value={t.value}>
+                      {t.label}
+                    </option>
+                  ))}
+                </select>
+                <textarea
+                  aria-label="Descripción"
+                  placeholder="Descripción de la gestión"
+                  value={intDescripcion}
+                  onChange={(e) => setIntDescripcion(e.target.value)}
+                  maxLength={2000}
+                  rows={2}
+                  required
+                  className="input-field md:col-span-2"
+                />
+                <input
+                  type="text"
+                  aria-label="Acuerdos"
+                  placeholder="Acuerdos (opcional)"
+                  value={intAcuerdos}
+                  onChange={(e) => setIntAcuerdos(e.target.value)}
+                  maxLength={1000}
+                  className="input-field md:col-span-3"
+                />
+                <button type="submit" disabled={busy != null} className="btn-primary disabled:opacity-50">
+                  {busy === 'intervencion' ? 'Registrando…' : 'Registrar'}
+                </button>
+              </form>
+            )}
+            {caso.intervenciones.length === 0 ? (
+              <p className="mt-3 text-sm text-gray-500 dark:text-gray-400">Aún no hay intervenciones registradas.</p>
+            ) : (
+              <ul className="mt-4 divide-y divide-gray-100 dark:divide-gray-700">
+                {caso.intervenciones.map((i) => (
+                  <li key={i.id} className="py-3 text-sm">
+                    <p className="text-xs text-gray-500 dark:text-gray-400">
+                      {formatFechaVista(i.fecha)} · {labelTipoIntervencion(i.tipo)}
+                      {i.autor_nombre && ` · ${i.autor_nombre}`}
+                    </p>
+                    <p className="mt-1 whitespace-pre-line text-gray-900 dark:text-white">{i.descripcion}</p>
+                    {i.acuerdos && (
+                      <p className="mt-1 text-gray-700 dark:text-gray-300">
+                        <span className="font-medium">Acuerdos:</span> {i.acuerdos}
+                      </p>
+                    )}
+                  </li>
+                ))}
+              </ul>
+            )}
+          </div>
+
+          <div className="grid grid-cols-1 gap-4 lg:grid-cols-2">
+            <div className="card p-5">
+              <div className="flex items-center justify-between gap-3">
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Adjuntos</h2>
+                {!cerrado && (
+                  <label className="btn-secondary inline-flex cursor-pointer items-center gap-1 px-2.5 py-1.5 text-xs">
+                    <PaperClipIcon className="h-4 w-4" aria-hidden />
+                    {busy === 'adjunto' ? 'Subiendo…' : 'Adjuntar'}
+                    <input
+                      type="file"
+                      accept=".pdf,.jpg,.jpeg,.png"
+                      className="sr-only"
+                      disabled={busy != null}
+                      onChange={(e) => {
+                        void subirAdjunto(e.target.files?.[0]);
+                        e.target.value = '';
+                      }}
+                    />
+                  </label>
+                )}
+              </div>
+              {caso.adjuntos.length === 0 ? (
+                <p className="mt-3 text-sm text-gray-500 dark:text-gray-400">Sin adjuntos. PDF, JPG o PNG de máximo 5 MB.</p>
+              ) : (
+                <ul className="mt-3 divide-y divide-gray-100 dark:divide-gray-700">
+                  {caso.adjuntos.map((a) => (
+                    <li key={a.id} className="flex items-center justify-between gap-3 py-2 text-sm">
+                      <span className="truncate text-gray-900 dark:text-white">
+                        {a.archivo_nombre}
+                        <span className="ml-2 text-xs text-gray-500">{formatFechaVista(a.created_at)}</span>
+                      </span>
+                      <button
+                        type="button"
+                        onClick={() => void descargarAdjunto(a)}
+                        className="btn-secondary inline-flex items-center gap-1 px-2.5 py-1.5 text-xs"
+                        title="Descargar adjunto"
+                      >
+                        <ArrowDownTrayIcon className="h-4 w-4" aria-hidden />
+                      </button>
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+
+            <div className="card p-5">
+              <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Recordatorios</h2>
+              {!cerrado && (
+                <form onSubmit={(e) => void crearRecordatorio(e)} className="mt-3 flex flex-wrap gap-2">
+                  <input
+                    type="datetime-local"
+                    aria-label="Fecha del recordatorio"
+                    value={recFecha}
+                    onChange={(e) => setRecFecha(e.target.value)}
+                    required
+                    className="input-field"
+                  />
+                  <input
+                    type="text"
+                    aria-label="Nota del recordatorio"
+                    placeholder="Próxima gestión"
+                    value={recNota}
+                    onChange={(e) => setRecNota(e.target.value)}
+                    maxLength={500}
+                    required
+                    className="input-field min-w-[12rem] flex-1"
+                  />
+                  <button type="submit" disabled={busy != null} className="btn-primary disabled:opacity-50">
+                    Programar
+                  </button>
+                </form>
+              )}
+              {caso.recordatorios.length === 0 ? (
+                <p className="mt-3 text-sm text-gray-500 dark:text-gray-400">Sin recordatorios programados.</p>
+              ) : (
+                <ul className="mt-3 divide-y divide-gray-100 dark:divide-gray-700">
+                  {caso.recordatorios.map((r) => (
+                    <li key={r.id} className="flex items-center justify-between gap-3 py-2 text-sm">
+                      <div className={r.completado_at ? 'text-gray-400 line-through' : 'text-gray-900 dark:text-white'}>
+                        <p>{r.nota}</p>
+                        <p className="text-xs text-gray-500 dark:text-gray-400">{formatFechaHoraVista(r.fecha_recordatorio)}</p>
+                      </div>
+                      {!r.completado_at && !cerrado && (
+                        <button
+                          type="button"
+                          disabled={busy != null}
+                          onClick={() =>
+                            void ejecutar(
+                              'recordatorio',
+                              () => apiService.completarRecordatorioCasoBienestar(casoId, r.id),
+                              'No se pudo completar el recordatorio.',
+                            )
+                          }
+                          className="btn-secondary inline-flex items-center gap-1 px-2.5 py-1.5 text-xs disabled:opacity-50"
+                        >
+                          <CheckIcon className="h-4 w-4" aria-hidden />
+                          Hecho
+                        </button>
+                      )}
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+          </div>
+
+          <div className="card p-5">
+            <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Historial del aprendiz</h2>
+            {historial.length === 0 ? (
+              <p className="mt-3 text-sm text-gray-500 dark:text-gray-400">No tiene otros casos de seguimiento.</p>
+            ) : (
+              <ul className="mt-3 divide-y divide-gray-100 dark:divide-gray-700">
+                {historial.map((h) => (
+                  <li key={h.id} className="flex flex-wrap items-center justify-between gap-3 py-2 text-sm">
+                    <Link to={bienestarPaths.seguimiento.detalle(h.id)} className="text-primary-600 hover:underline dark:text-primary-400">
+                      Caso #{h.id} · Ficha {h.ficha_numero} · {formatFechaVista(h.created_at)}
+                    </Link>
+                    <CasoSeguimientoEstadoBadge estado={h.estado} />
+                  </li>
+                ))}
+              </ul>
+            )}
+          </div>
+        </>
+      )}
+    </div>
+  );
+}
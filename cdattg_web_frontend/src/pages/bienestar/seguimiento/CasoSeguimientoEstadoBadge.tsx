@@ -0,0 +1,12 @@
+import type { CasoBienestarEstado } from '../../../types';
+import { CASO_SEGUIMIENTO_ESTADO_BADGE, CASO_SEGUIMIENTO_ESTADO_LABEL } from './casosSeguimientoConstants';
+
+export function CasoSeguimientoEstadoBadge({ estado }: Readonly<{ estado: CasoBienestarEstado }>) {
+  return (
+    <span
+      className={`inline-flex rounded-full px-2.5 py-0.5 text-xs font-semibold ${CASO_SEGUIMIENTO_ESTADO_BADGE[estado]}`}
+    >
+      {CASO_SEGUIMIENTO_ESTADO_LABEL[estado]}
+    </span>
+  );
+}
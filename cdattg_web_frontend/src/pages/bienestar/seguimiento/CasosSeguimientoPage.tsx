@@ -0,0 +1,224 @@
+import { useCallback, useEffect, useState } from 'react';
+import { Link } from 'react-router-dom';
+import { BellAlertIcon, CheckIcon } from '@heroicons/react/24/outline';
+import { apiService } from '../../../services/api';
+import { axiosErrorMessage } from '../../../utils/httpError';
+import { formatFechaHoraVista, formatFechaVista } from '../../../utils/formatFecha';
+import { useAuth } from '../../../context/AuthContext';
+import type { CasoBienestar, CasoBienestarEstado, CasoBienestarRecordatorio } from '../../../types';
+import { bienestarPaths } from '../bienestarPaths';
+import { canViewCasosBienestar, MENSAJE_SIN_PERMISO_CASOS_BIENESTAR } from '../casos/casosBienestarPermissions';
+import { CASO_SEGUIMIENTO_ESTADO_LABEL } from './casosSeguimientoConstants';
+import { CasoSeguimientoEstadoBadge } from './CasoSeguimientoEstadoBadge';
+
+type FiltroResponsable = '' | 'yo' | 'ninguno';
+
+export function CasosSeguimientoPage() {
+  const { roles } = useAuth();
+  const canView = canViewCasosBienestar(roles);
+
+  const [estado, setEstado] = useState<CasoBienestarEstado | ''>('');
+  const [responsable, setResponsable] = useState<FiltroResponsable>('');
+  const [casos, setCasos] = useState<CasoBienestar[]>([]);
+  const [recordatorios, setRecordatorios] = useState<CasoBienestarRecordatorio[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState('');
+
+  const cargar = useCallback(async () => {
+    setLoading(true);
+    setError('');
+    try {
+      const [lista, pendientes] = await Promise.all([
+        apiService.listCasosBienestarSeguimiento({
+          estado: estado || undefined,
+          responsable_user_id: responsable || undefined,
+        }),
+        apiService.getMisRecordatoriosBienestar(),
+      ]);
+      setCasos(lista);
+      setRecordatorios(pendientes);
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudieron cargar los casos de seguimiento.'));
+    } finally {
+      setLoading(false);
+    }
+  }, [estado, responsable]);
+
+  useEffect(() => {
+    if (canView) void cargar();
+  }, [canView, cargar]);
+
+  const completarRecordatorio = async (r: CasoBienestarRecordatorio) => {
+    try {
+      await apiService.completarRecordatorioCasoBienestar(r.caso_id, r.id);
+      setRecordatorios((prev) => prev.filter((x) => x.id !== r.id));
+    } catch (e: unknown) {
+      setError(axiosErrorMessage(e, 'No se pudo completar el recordatorio.'));
+    }
+  };
+
+  if (!canView) {
+    return (
+      <p role="alert" className="text-red-600 dark:text-red-400">
+        {MENSAJE_SIN_PERMISO_CASOS_BIENESTAR}
+      </p>
+    );
+  }
+
+  return (
+    <div className="space-y-6">
+      <div className="max-w-3xl">
+        <h1 className="text-3xl font-bold text-gray-900 dark:text-white">Casos de seguimiento</h1>
+        <p className="mt-2 text-gray-600 dark:text-gray-400">
+          Casos abiertos por Bienestar al Aprendiz con su responsable, bitácora de intervenciones y próximos
+          recordatorios. Los casos se abren desde el listado de aprendices en alerta de cada ficha.
+        </p>
+      </div>
+
+      {error && (
+        <div
+          role="alert"
+          className="rounded-lg border border-red-200 bg-red-50 px-4 py-3 text-red-700 dark:border-red-800 dark:bg-red-900/30 dark:text-red-300"
+        >
+          {error}
+        </div>
+      )}
+
+      {recordatorios.length > 0 && (
+        <div className="card p-5">
+          <h2 className="flex items-center gap-2 text-lg font-semibold text-gray-900 dark:text-white">
+            <BellAlertIcon className="h-5 w-5 text-amber-500" aria-hidden />
+            Mis recordatorios pendientes
+          </h2>
+          <ul className="mt-3 divide-y divide-gray-100 dark:divide-gray-700">
+            {recordatorios.map((r) => (
+              <li key={r.id} className="flex flex-wrap items-center justify-between gap-3 py-2.5">
+                <div className="text-sm">
+                  <p className="font-medium text-gray-900 dark:text-white">{r.nota}</p>
+                  <p className="text-xs text-gray-500 dark:text-gray-400">
+                    {formatFechaHoraVista(r.fecha_recordatorio)} ·{' '}
+                    <Link to={bienestarPaths.seguimiento.detalle(r.caso_id)} className="text-primary-600 hover:underline">
+                      {r.aprendiz_nombre || `Caso #${r.caso_id}`}
+                    </Link>
+                    {r.ficha_numero && ` · Ficha ${r.ficha_numero}`}
+                  </p>
+                </div>
+                <button
+                  type="button"
+                  onClick={() => void completarRecordatorio(r)}
+                  className="btn-secondary inline-flex items-center gap-1 px-2.5 py-1.5 text-xs"
+                >
+                  <CheckIcon className="h-4 w-4" aria-hidden />
+                  Hecho
+                </button>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      <div className="flex flex-wrap items-end gap-4 rounded-xl border border-gray-200 bg-white p-4 dark:border-gray-600 dark:bg-gray-800">
+        <div>
+          <label htmlFor="casos-seguimiento-estado" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Estado
+          </label>
+          <select
+            id="casos-seguimiento-estado"
+            value={estado}
+            onChange={(e) => setEstado(e.target.value as CasoBienestarEstado | '')}
+            className="input-field min-w-[12rem]"
+          >
+            <option value="">Todos</option>
+            {(Object.keys(CASO_SEGUIMIENTO_ESTADO_LABEL) as CasoBienestarEstado[]).map((k) => (
+              <option key={k} value={k}>
+                {CASO_SEGUIMIENTO_ESTADO_LABEL[k]}
+              </option>
+            ))}
+          </select>
+        </div>
+        <div>
+          <label htmlFor="casos-seguimiento-responsable" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Responsable
+          </label>
+          <select
+            id="casos-seguimiento-responsable"
+            value={responsable}
+            onChange={(e) => setResponsable(e.target.value as FiltroResponsable)}
+            className="input-field min-w-[12rem]"
+          >
+            <option value="">Todos</option>
+            <option value="yo">Asignados a mí</option>
+            <option value="ninguno">Sin asignar</option>
+          </select>
+        </div>
+      </div>
+
+      <div className="card overflow-hidden">
+        {loading ? (
+          <div className="p-8 text-center text-gray-500 dark:text-gray-400" role="status" aria-live="polite">
+            Cargando casos…
+          </div>
+        ) : casos.length === 0 ? (
+          <div className="px-6 py-12 text-center text-sm text-gray-500 dark:text-gray-400">
+            No hay casos que coincidan con los filtros.
+          </div>
+        ) : (
+          <div className="overflow-x-auto">
+            <table className="min-w-full divide-y divide-gray-200 dark:divide-gray-700">
+              <thead>
+                <tr className="bg-gray-50 dark:bg-gray-800/80">
+                  <th className="px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Aprendiz
+                  </th>
+                  <th className="px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Ficha
+                  </th>
+                  <th className="px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Estado
+                  </th>
+                  <th className="hidden px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400 md:table-cell">
+                    Responsable
+                  </th>
+                  <th className="px-4 py-3.5 text-right text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400">
+                    Inasistencias
+                  </th>
+                  <th className="hidden px-4 py-3.5 text-left text-xs font-semibold uppercase tracking-wide text-gray-600 dark:text-gray-400 sm:table-cell">
+                    Abierto
+                  </th>
+                </tr>
+              </thead>
+              <tbody className="divide-y divide-gray-100 bg-white dark:divide-gray-700/80 dark:bg-gray-800/50">
+                {casos.map((c) => (
+                  <tr key={c.id} className="hover:bg-gray-50 dark:hover:bg-gray-700/40">
+                    <td className="px-4 py-3.5 text-sm">
+                      <Link
+                        to={bienestarPaths.seguimiento.detalle(c.id)}
+                        className="font-medium text-primary-600 hover:underline dark:text-primary-400"
+                      >
+                        {c.aprendiz_nombre}
+                      </Link>
+                      <p className="text-xs tabular-nums text-gray-500 dark:text-gray-400">{c.numero_documento}</p>
+                    </td>
+                    <td className="px-4 py-3.5 text-sm text-gray-700 dark:text-gray-300">{c.ficha_numero}</td>
+                    <td className="px-4 py-3.5">
+                      <CasoSeguimientoEstadoBadge estado={c.estado} />
+                    </td>
+                    <td className="hidden px-4 py-3.5 text-sm text-gray-700 dark:text-gray-300 md:table-cell">
+                      {c.responsable_nombre || <span className="text-gray-400">Sin asignar</span>}
+                    </td>
+                    <td className="px-4 py-3.5 text-right text-sm tabular-nums text-gray-700 dark:text-gray-300">
+                      {c.inasistencias}
+                    </td>
+                    <td className="hidden px-4 py-3.5 text-sm text-gray-600 dark:text-gray-400 sm:table-cell">
+                      {formatFechaVista(c.created_at)}
+                    </td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,26 @@
+import type { CasoBienestarEstado, CasoBienestarIntervencionTipo } from '../../../types';
+
+export const CASO_SEGUIMIENTO_ESTADO_LABEL: Record<CasoBienestarEstado, string> = {
+  abierto: 'Abierto',
+  en_seguimiento: 'En seguimiento',
+  cerrado: 'Cerrado',
+};
+
+export const CASO_SEGUIMIENTO_ESTADO_BADGE: Record<CasoBienestarEstado, string> = {
+  abierto: 'bg-amber-100 text-amber-800 dark:bg-amber-900/40 dark:text-amber-300',
+  en_seguimiento: 'bg-blue-100 text-blue-800 dark:bg-blue-900/40 dark:text-blue-300',
+  cerrado: 'bg-gray-100 text-gray-700 dark:bg-gray-700 dark:text-gray-300',
+};
+
+export const CASO_SEGUIMIENTO_TIPOS_INTERVENCION: { value: CasoBienestarIntervencionTipo; label: string }[] = [
+  { value: 'llamada', label: 'Llamada' },
+  { value: 'reunion', label: 'Reunión' },
+  { value: 'correo', label: 'Correo' },
+  { value: 'visita', label: 'Visita' },
+  { value: 'remision', label: 'Remisión' },
+  { value: 'otro', label: 'Otro' },
+];
+
+export function labelTipoIntervencion(tipo: CasoBienestarIntervencionTipo): string {
+  return CASO_SEGUIMIENTO_TIPOS_INTERVENCION.find((t) => t.value === tipo)?.label ?? tipo;
+}
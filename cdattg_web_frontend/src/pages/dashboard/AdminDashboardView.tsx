@@ -556,7 +556,7 @@ export function AdminDashboardView() {
 
 
 
-        <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+        <div className="grid grid-cols-1 md:grid-cols-2 xl:grid-cols-4 gap-4">
 
           <KpiCard
 
@@ -572,6 +572,20 @@ export function AdminDashboardView() {
 
           />
 
+          <KpiCard
+
+            label="Riesgo de deserción alto"
+
+            value={fmtDashboardNum(data?.riesgo.riesgo_desercion_alto, loading)}
+
+            tooltip="Aprendices con puntaje de riesgo de deserción ≥ 60 (inasistencias, rachas, tendencia, retardos, salidas parciales, observaciones y RAPs) en los últimos 30 días."
+
+            icon={<ExclamationTriangleIcon className="w-6 h-6 text-red-600 dark:text-red-400" aria-hidden />}
+
+            accentClass="bg-red-50 dark:bg-red-900/30"
+
+          />
+
           <KpiCard
 
             label="Pendientes revisión"
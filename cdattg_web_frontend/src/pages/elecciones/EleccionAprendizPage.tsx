@@ -1,11 +1,16 @@
 import { useCallback, useEffect, useState } from 'react';
 import { apiService } from '../../services/api';
 import { axiosErrorMessage } from '../../utils/httpError';
-import type { EleccionMiRegional, EleccionPlancha, RepresentanteAprendiz } from '../../types/eleccion';
+import { ambitoProcesoTexto } from '../../utils/eleccionAmbito';
+import type { EleccionMiProceso, EleccionMiRegional, EleccionPlancha, RepresentanteAprendiz } from '../../types/eleccion';
 
-function planchaVotadaLabel(plancha: EleccionPlancha | undefined, miVotoPlanchaId?: number): string {
-  if (plancha) return `${plancha.titular.nombre} / ${plancha.suplente.nombre}`;
-  return `plancha #${miVotoPlanchaId}`;
+type PropuestaPlancha = { rol_candidatura: 'titular' | 'suplente'; companero_aprendiz_id: number };
+
+/** Procesos en curso del aprendiz: primero el regional (raíz de la respuesta), luego los de su sede y ficha. */
+function procesosEnCurso(mi: EleccionMiRegional | null): EleccionMiProceso[] {
+  if (!mi) return [];
+  const regional = mi.proceso ? [mi as EleccionMiProceso] : [];
+  return [...regional, ...(mi.otros_procesos ?? [])];
 }
 
 type EleccionErrorAlertProps = Readonly<{ message: string }>;
@@ -18,17 +23,17 @@ function EleccionErrorAlert({ message }: EleccionErrorAlertProps) {
   );
 }
 
-type RepresentantesVigentesCardProps = Readonly<{ representantes: RepresentanteAprendiz }>;
+type RepresentantesVigentesCardProps = Readonly<{ titulo: string; representantes: RepresentanteAprendiz }>;
 
-function RepresentantesVigentesCard({ representantes }: RepresentantesVigentesCardProps) {
+function RepresentantesVigentesCard({ titulo, representantes }: RepresentantesVigentesCardProps) {
   return (
     <section className="rounded-xl border border-primary-200 bg-primary-50/50 p-4 dark:border-primary-800 dark:bg-primary-950/20">
-      <h2 className="font-semibold text-primary-900 dark:text-primary-200">Representantes vigentes</h2>
+      <h2 className="font-semibold text-primary-900 dark:text-primary-200">{titulo}</h2>
       <p className="mt-1 text-sm text-gray-800 dark:text-gray-200">
-        Titular: <strong>{representantes.titular.nombre}</strong>
+        {representantes.cargo_titular ?? 'Titular'}: <strong>{representantes.titular.nombre}</strong>
       </p>
       <p className="text-sm text-gray-800 dark:text-gray-200">
-        Suplente: <strong>{representantes.suplente.nombre}</strong>
+        {representantes.cargo_suplente ?? 'Suplente'}: <strong>{representantes.suplente.nombre}</strong>
       </p>
     </section>
   );
@@ -64,7 +69,7 @@ function ConfirmarPlanchasSection({ planchas, onConfirm }: ConfirmarPlanchasSect
 
 type PostularPlanchaSectionProps = Readonly<{
   procesoId: number;
-  onSubmit: (payload: { rol_candidatura: 'titular' | 'suplente'; companero_aprendiz_id: number }) => Promise<void>;
+  onSubmit: (payload: PropuestaPlancha) => Promise<void>;
 }>;
 
 function PostularPlanchaSection({ procesoId, onSubmit }: PostularPlanchaSectionProps) {
@@ -123,17 +128,22 @@ function PostularPlanchaSection({ procesoId, onSubmit }: PostularPlanchaSectionP
   );
 }
 
-type VotoRegistradoSectionProps = Readonly<{
-  label: string;
-}>;
+type VotoRegistradoSectionProps = Readonly<{ codigoRecibo: string }>;
 
-function VotoRegistradoSection({ label }: VotoRegistradoSectionProps) {
+function VotoRegistradoSection({ codigoRecibo }: VotoRegistradoSectionProps) {
   return (
     <div className="mt-4 space-y-2 border-t border-gray-100 pt-4 dark:border-gray-700">
       <h3 className="text-sm font-medium">Su voto</h3>
-      <p className="text-sm text-gray-700 dark:text-gray-300">
-        Voto registrado para: <strong>{label}</strong>
-      </p>
+      <p className="text-sm text-gray-700 dark:text-gray-300">Su voto fue registrado. El voto es secreto.</p>
+      {codigoRecibo ? (
+        <p className="text-sm text-gray-700 dark:text-gray-300">
+          Código de recibo: <strong className="font-mono">{codigoRecibo}</strong>
+          <br />
+          <span className="text-xs text-gray-500">
+            Guárdelo: solo se muestra una vez y permite verificar que su papeleta fue contabilizada.
+          </span>
+        </p>
+      ) : null}
       <p className="text-xs text-gray-500">El voto es único y no puede modificarse.</p>
     </div>
   );
@@ -172,12 +182,13 @@ function VotarSection({ planchas, votoPlanchaId, onVotoPlanchaIdChange, onSubmit
 }
 
 type EleccionProcesoSectionProps = Readonly<{
-  data: EleccionMiRegional;
+  codigoRecibo: string;
+  data: EleccionMiProceso;
   planchas: EleccionPlancha[];
   votoPlanchaId: string;
   onVotoPlanchaIdChange: (value: string) => void;
   onConfirmPlancha: (planchaId: number) => Promise<void>;
-  onProponerPlancha: (payload: { rol_candidatura: 'titular' | 'suplente'; companero_aprendiz_id: number }) => Promise<void>;
+  onProponerPlancha: (payload: PropuestaPlancha) => Promise<void>;
   onRegistrarVoto: (planchaId: number) => Promise<void>;
 }>;
 
@@ -189,19 +200,20 @@ function EleccionProcesoSection({
   onConfirmPlancha,
   onProponerPlancha,
   onRegistrarVoto,
+  codigoRecibo,
 }: EleccionProcesoSectionProps) {
   const proceso = data.proceso;
   if (!proceso) return null;
 
-  const votoRegistrado = data.ya_voto ?? Boolean(data.mi_voto_plancha_id);
-  const planchaVotada = votoRegistrado
-    ? planchas.find((pl) => pl.id === data.mi_voto_plancha_id)
-    : undefined;
+  const votoRegistrado = Boolean(data.ya_voto);
 
   return (
     <section className="rounded-xl border border-gray-200 bg-white p-4 dark:border-gray-700 dark:bg-gray-800">
       <h2 className="font-semibold text-gray-900 dark:text-white">{proceso.nombre_ciclo}</h2>
-      <p className="text-sm text-gray-600 dark:text-gray-400">Estado: {proceso.estado}</p>
+      <p className="text-sm text-gray-600 dark:text-gray-400">
+        {ambitoProcesoTexto(proceso)} · {proceso.cargo_titular} y {proceso.cargo_suplente.toLowerCase()} · Estado:{' '}
+        {proceso.estado}
+      </p>
 
       <ConfirmarPlanchasSection
         planchas={data.planchas_pendientes_confirmar ?? []}
@@ -213,7 +225,7 @@ function EleccionProcesoSection({
       ) : null}
 
       {votoRegistrado ? (
-        <VotoRegistradoSection label={planchaVotadaLabel(planchaVotada, data.mi_voto_plancha_id)} />
+        <VotoRegistradoSection codigoRecibo={codigoRecibo} />
       ) : null}
 
       {data.puede_votar ? (
@@ -230,10 +242,11 @@ function EleccionProcesoSection({
 
 export function EleccionAprendizPage() {
   const [data, setData] = useState<EleccionMiRegional | null>(null);
-  const [planchas, setPlanchas] = useState<EleccionPlancha[]>([]);
+  const [planchas, setPlanchas] = useState<Record<number, EleccionPlancha[]>>({});
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(true);
-  const [votoPlanchaId, setVotoPlanchaId] = useState('');
+  const [votoPlanchaId, setVotoPlanchaId] = useState<Record<number, string>>({});
+  const [codigoRecibo, setCodigoRecibo] = useState<Record<number, string>>({});
 
   const load = useCallback(async () => {
     setLoading(true);
@@ -241,13 +254,9 @@ export function EleccionAprendizPage() {
     try {
       const mi = await apiService.getEleccionMiRegional();
       setData(mi);
-      if (mi.proceso?.id) {
-        const pl = await apiService.getEleccionPlanchas(mi.proceso.id);
-        setPlanchas(pl);
-        if (mi.mi_voto_plancha_id) setVotoPlanchaId(String(mi.mi_voto_plancha_id));
-      } else {
-        setPlanchas([]);
-      }
+      const ids = procesosEnCurso(mi).flatMap((p) => (p.proceso ? [p.proceso.id] : []));
+      const listas = await Promise.all(ids.map((id) => apiService.getEleccionPlanchas(id)));
+      setPlanchas(Object.fromEntries(ids.map((id, i) => [id, listas[i]])));
     } catch (e) {
       setError(axiosErrorMessage(e, 'Error al cargar la elección.'));
     } finally {
@@ -280,33 +289,30 @@ export function EleccionAprendizPage() {
   );
 
   const handleProponerPlancha = useCallback(
-    (payload: { rol_candidatura: 'titular' | 'suplente'; companero_aprendiz_id: number }) => {
-      const procesoId = data?.proceso?.id;
-      if (!procesoId) return Promise.resolve();
-      return runAction(async () => {
+    (procesoId: number, payload: PropuestaPlancha) =>
+      runAction(async () => {
         await apiService.proponerEleccionPlancha(procesoId, payload);
-      }, 'No se pudo inscribir la plancha.');
-    },
-    [data?.proceso?.id, runAction],
+      }, 'No se pudo inscribir la plancha.'),
+    [runAction],
   );
 
   const handleRegistrarVoto = useCallback(
-    (planchaId: number) => {
-      const procesoId = data?.proceso?.id;
-      if (!procesoId) return Promise.resolve();
-      return runAction(async () => {
-        await apiService.registrarEleccionVoto(procesoId, { plancha_id: planchaId });
-      }, 'No se pudo registrar el voto.');
-    },
-    [data?.proceso?.id, runAction],
+    (procesoId: number, planchaId: number) =>
+      runAction(async () => {
+        const voto = await apiService.registrarEleccionVoto(procesoId, { plancha_id: planchaId });
+        setCodigoRecibo((prev) => ({ ...prev, [procesoId]: voto.codigo_recibo }));
+      }, 'No se pudo registrar el voto.'),
+    [runAction],
   );
 
   if (loading) return <p className="text-gray-500">Cargando…</p>;
 
+  const procesos = procesosEnCurso(data);
+
   return (
     <div className="mx-auto max-w-3xl space-y-6">
       <div>
-        <h1 className="text-2xl font-bold text-gray-900 dark:text-white">Elección de representante</h1>
+        <h1 className="text-2xl font-bold text-gray-900 dark:text-white">Elecciones de aprendices</h1>
         <p className="text-sm text-gray-600 dark:text-gray-400">
           Regional: {data?.regional_nombre ?? data?.regional_id}
         </p>
@@ -315,21 +321,32 @@ export function EleccionAprendizPage() {
       {error ? <EleccionErrorAlert message={error} /> : null}
 
       {data?.representantes_vigentes ? (
-        <RepresentantesVigentesCard representantes={data.representantes_vigentes} />
+        <RepresentantesVigentesCard titulo="Representantes vigentes" representantes={data.representantes_vigentes} />
       ) : null}
 
-      {data?.proceso ? (
-        <EleccionProcesoSection
-          data={data}
-          planchas={planchas}
-          votoPlanchaId={votoPlanchaId}
-          onVotoPlanchaIdChange={setVotoPlanchaId}
-          onConfirmPlancha={handleConfirmPlancha}
-          onProponerPlancha={handleProponerPlancha}
-          onRegistrarVoto={handleRegistrarVoto}
-        />
+      {data?.voceros_vigentes ? (
+        <RepresentantesVigentesCard titulo="Voceros de su ficha" representantes={data.voceros_vigentes} />
+      ) : null}
+
+      {procesos.length > 0 ? (
+        procesos.map((p) => {
+          const procesoId = p.proceso?.id ?? 0;
+          return (
+            <EleccionProcesoSection
+              key={procesoId}
+              data={p}
+              planchas={planchas[procesoId] ?? []}
+              votoPlanchaId={votoPlanchaId[procesoId] ?? ''}
+              onVotoPlanchaIdChange={(value) => setVotoPlanchaId((prev) => ({ ...prev, [procesoId]: value }))}
+              onConfirmPlancha={handleConfirmPlancha}
+              onProponerPlancha={(payload) => handleProponerPlancha(procesoId, payload)}
+              onRegistrarVoto={(planchaId) => handleRegistrarVoto(procesoId, planchaId)}
+              codigoRecibo={codigoRecibo[procesoId] ?? ''}
+            />
+          );
+        })
       ) : (
-        <p className="text-gray-600 dark:text-gray-400">No hay elección activa en su regional.</p>
+        <p className="text-gray-600 dark:text-gray-400">No hay elecciones activas en su regional, sede o ficha.</p>
       )}
     </div>
   );
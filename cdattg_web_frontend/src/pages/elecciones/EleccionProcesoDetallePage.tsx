@@ -7,13 +7,23 @@ import {
   CalculatorIcon,
   CheckBadgeIcon,
   ClipboardDocumentListIcon,
+  DocumentTextIcon,
   UserGroupIcon,
 } from '@heroicons/react/24/outline';
+import { EleccionParticipacionPanel } from '../../components/elecciones/EleccionParticipacionPanel';
 import { apiService } from '../../services/api';
 import { axiosErrorMessage } from '../../utils/httpError';
-import { formatFechaVista, formatRangoFechasVista } from '../../utils/formatFecha';
-import type { EleccionPlancha, EleccionProceso, EleccionResultado, RepresentanteAprendiz } from '../../types/eleccion';
+import { formatFechaHoraVista, formatFechaVista, formatRangoFechasVista } from '../../utils/formatFecha';
+import type {
+  EleccionDesempateMetodo,
+  EleccionPlancha,
+  EleccionProceso,
+  EleccionProcesoEvento,
+  EleccionResultado,
+  RepresentanteAprendiz,
+} from '../../types/eleccion';
 import { administracionPaths } from '../../routes/paths';
+import { ambitoProcesoTexto } from '../../utils/eleccionAmbito';
 
 const ESTADO_LABEL: Record<EleccionProceso['estado'], string> = {
   borrador: 'Borrador',
@@ -135,7 +145,7 @@ type CronogramaCardProps = Readonly<{ proceso: EleccionProceso }>;
 function CronogramaCard({ proceso }: CronogramaCardProps) {
   const inscripcion = formatRangoFechasVista(proceso.fecha_inscripcion_inicio, proceso.fecha_inscripcion_fin);
   const votacion = formatRangoFechasVista(proceso.fecha_votacion_inicio, proceso.fecha_votacion_fin);
-  if (!inscripcion && !votacion && !proceso.min_dias_matricula) return null;
+  if (!inscripcion && !votacion && !proceso.min_dias_matricula && !proceso.auto_transiciones) return null;
 
   return (
     <section className="rounded-xl border border-gray-200 bg-white p-4 dark:border-gray-700 dark:bg-gray-800">
@@ -156,6 +166,12 @@ function CronogramaCard({ proceso }: CronogramaCardProps) {
           </dd>
         </div>
       </dl>
+      <p className="mt-3 text-xs text-gray-500 dark:text-gray-400">
+        {proceso.auto_transiciones
+          ? 'Las fases avanzan automáticamente en estas fechas y el resultado se calcula al cerrar la votación.'
+          : 'Las fases se avanzan manualmente con las acciones del proceso.'}
+        {proceso.cierre_votacion_at ? ` Se aceptan votos hasta ${formatFechaHoraVista(proceso.cierre_votacion_at)}.` : ''}
+      </p>
     </section>
   );
 }
@@ -167,6 +183,7 @@ type AccionesProcesoProps = Readonly<{
   onAbrirVotacion: () => void;
   onCalcular: () => void;
   onExportar: () => void;
+  onDescargarActa: () => void;
   onActualizar: () => void;
 }>;
 
@@ -177,6 +194,7 @@ function AccionesProceso({
   onAbrirVotacion,
   onCalcular,
   onExportar,
+  onDescargarActa,
   onActualizar,
 }: AccionesProcesoProps) {
   const btn = (id: string, label: string, onClick: () => void, primary = false) => (
@@ -211,11 +229,23 @@ function AccionesProceso({
             Exportar CSV
           </button>
         ) : null}
+        {proceso.estado === 'cerrada' ? (
+          <button type="button" className="btn-secondary inline-flex items-center gap-2" disabled={busy !== null} onClick={onDescargarActa}>
+            <DocumentTextIcon className="h-4 w-4" />
+            {busy === 'acta' ? 'Generando…' : 'Descargar acta (PDF)'}
+          </button>
+        ) : null}
       </div>
     </section>
   );
 }
 
+const DESEMPATE_METODO_LABEL: Record<EleccionDesempateMetodo, string> = {
+  sorteo: 'Sorteo',
+  comite: 'Decisión del comité electoral',
+  acuerdo: 'Acuerdo entre las planchas empatadas',
+};
+
 function resultadoTieneDatos(proceso: EleccionProceso): boolean {
   return (proceso.votos_registrados ?? 0) > 0;
 }
@@ -224,9 +254,11 @@ type DesempateSectionProps = Readonly<{
   planchas: EleccionPlancha[];
   resultado: EleccionResultado | null;
   desempatePlanchaId: string;
+  desempateMetodo: EleccionDesempateMetodo | '';
   desempateNota: string;
   busy: boolean;
   onPlanchaChange: (value: string) => void;
+  onMetodoChange: (value: EleccionDesempateMetodo | '') => void;
   onNotaChange: (value: string) => void;
   onSubmit: () => void;
 }>;
@@ -235,9 +267,11 @@ function DesempateSection({
   planchas,
   resultado,
   desempatePlanchaId,
+  desempateMetodo,
   desempateNota,
   busy,
   onPlanchaChange,
+  onMetodoChange,
   onNotaChange,
   onSubmit,
 }: DesempateSectionProps) {
@@ -256,7 +290,8 @@ function DesempateSection({
     <section className="rounded-xl border border-amber-200 bg-amber-50/80 p-4 dark:border-amber-900/50 dark:bg-amber-950/30">
       <h2 className="font-semibold text-amber-900 dark:text-amber-200">Registrar desempate</h2>
       <p className="mt-1 text-sm text-amber-800/90 dark:text-amber-300/90">
-        Seleccione la plancha ganadora y documente el acta o sorteo realizado.
+        Seleccione la plancha ganadora entre las empatadas, el método usado y documente el procedimiento; quedará en el
+        acta de escrutinio.
       </p>
       <div className="mt-4 grid gap-3 sm:grid-cols-2">
         <div>
@@ -277,6 +312,24 @@ function DesempateSection({
             ))}
           </select>
         </div>
+        <div>
+          <label htmlFor="desempate-metodo" className="mb-1 block text-sm font-medium text-amber-900 dark:text-amber-200">
+            Método de desempate
+          </label>
+          <select
+            id="desempate-metodo"
+            className="input-field w-full"
+            value={desempateMetodo}
+            onChange={(e) => onMetodoChange(e.target.value as EleccionDesempateMetodo | '')}
+          >
+            <option value="">Seleccione método</option>
+            {(Object.keys(DESEMPATE_METODO_LABEL) as EleccionDesempateMetodo[]).map((m) => (
+              <option key={m} value={m}>
+                {DESEMPATE_METODO_LABEL[m]}
+              </option>
+            ))}
+          </select>
+        </div>
         <div className="sm:col-span-2">
           <label htmlFor="desempate-nota" className="mb-1 block text-sm font-medium text-amber-900 dark:text-amber-200">
             Acta / nota de desempate
@@ -293,7 +346,7 @@ function DesempateSection({
       <button
         type="button"
         className="btn-primary mt-3"
-        disabled={busy || !desempatePlanchaId || !desempateNota.trim()}
+        disabled={busy || !desempatePlanchaId || !desempateMetodo || !desempateNota.trim()}
         onClick={onSubmit}
       >
         {busy ? 'Registrando…' : 'Registrar desempate y cerrar'}
@@ -407,7 +460,14 @@ function ResultadosSection({ resultado }: ResultadosSectionProps) {
       </div>
       {resultado.nota_desempate ? (
         <p className="mt-3 rounded-lg bg-gray-50 px-3 py-2 text-sm text-gray-700 dark:bg-gray-900/50 dark:text-gray-300">
-          <strong>Desempate:</strong> {resultado.nota_desempate}
+          <strong>Desempate</strong>
+          {resultado.desempate_metodo ? ` (${DESEMPATE_METODO_LABEL[resultado.desempate_metodo]})` : ''}:{' '}
+          {resultado.nota_desempate}
+        </p>
+      ) : null}
+      {resultado.acta_hash ? (
+        <p className="mt-3 text-xs text-gray-500 dark:text-gray-400">
+          Acta de escrutinio · SHA-256 <span className="break-all font-mono">{resultado.acta_hash}</span>
         </p>
       ) : null}
       <ul className="mt-4 space-y-3">
@@ -436,6 +496,35 @@ function ResultadosSection({ resultado }: ResultadosSectionProps) {
   );
 }
 
+type BitacoraFasesSectionProps = Readonly<{ eventos: EleccionProcesoEvento[] }>;
+
+function BitacoraFasesSection({ eventos }: BitacoraFasesSectionProps) {
+  return (
+    <section className="rounded-xl border border-gray-200 bg-white p-4 dark:border-gray-700 dark:bg-gray-800">
+      <div className="flex items-center gap-2">
+        <ClipboardDocumentListIcon className="h-5 w-5 text-gray-500" aria-hidden />
+        <h2 className="font-semibold text-gray-900 dark:text-white">Cambios de fase</h2>
+      </div>
+      <ul className="mt-4 space-y-2">
+        {eventos.map((e) => (
+          <li key={e.id} className="rounded-lg border border-gray-100 px-3 py-2 text-sm dark:border-gray-700">
+            <div className="flex flex-wrap items-center justify-between gap-2">
+              <span className="font-medium text-gray-900 dark:text-white">
+                {ESTADO_LABEL[e.estado_anterior]} → {ESTADO_LABEL[e.estado_nuevo]}
+              </span>
+              <span className="text-xs text-gray-500">{formatFechaHoraVista(e.created_at)}</span>
+            </div>
+            <p className="text-xs text-gray-500">
+              {e.origen === 'automatico' ? 'Automático' : 'Manual'}
+              {e.detalle ? ` · ${e.detalle}` : ''}
+            </p>
+          </li>
+        ))}
+      </ul>
+    </section>
+  );
+}
+
 type HistorialSectionProps = Readonly<{ historial: RepresentanteAprendiz[] }>;
 
 function HistorialSection({ historial }: HistorialSectionProps) {
@@ -455,6 +544,7 @@ function HistorialSection({ historial }: HistorialSectionProps) {
               <span className="font-medium text-gray-900 dark:text-white">
                 {h.nombre_ciclo ?? `Proceso ${h.proceso_id}`}
                 {h.anio ? <span className="text-gray-500"> · {h.anio}</span> : null}
+                <span className="text-gray-500"> · {ambitoProcesoTexto(h)}</span>
               </span>
               {h.vigencia_hasta ? null : (
                 <span className="rounded-full bg-green-100 px-2 py-0.5 text-xs font-medium text-green-800 dark:bg-green-900/40 dark:text-green-200">
@@ -463,7 +553,9 @@ function HistorialSection({ historial }: HistorialSectionProps) {
               )}
             </div>
             <p className="mt-1 text-sm text-gray-700 dark:text-gray-300">
-              {h.titular.nombre} / {h.suplente.nombre}
+              {h.cargo_titular ? `${h.cargo_titular}: ` : ''}
+              {h.titular.nombre} / {h.cargo_suplente ? `${h.cargo_suplente}: ` : ''}
+              {h.suplente.nombre}
             </p>
             <p className="text-xs text-gray-500">
               Desde {formatFechaVista(h.vigencia_desde)}
@@ -486,8 +578,10 @@ export function EleccionProcesoDetallePage() {
   const [loading, setLoading] = useState(true);
   const [busy, setBusy] = useState<string | null>(null);
   const [desempatePlanchaId, setDesempatePlanchaId] = useState('');
+  const [desempateMetodo, setDesempateMetodo] = useState<EleccionDesempateMetodo | ''>('');
   const [desempateNota, setDesempateNota] = useState('');
   const [historial, setHistorial] = useState<RepresentanteAprendiz[]>([]);
+  const [eventos, setEventos] = useState<EleccionProcesoEvento[]>([]);
 
   const load = useCallback(async () => {
     if (!procesoId) return;
@@ -495,15 +589,17 @@ export function EleccionProcesoDetallePage() {
     setError('');
     try {
       const p = await apiService.getEleccionProceso(procesoId);
-      const [pl, res, hist] = await Promise.all([
+      const [pl, res, hist, ev] = await Promise.all([
         apiService.getEleccionPlanchasAdmin(procesoId, false),
         apiService.getEleccionResultados(procesoId, true).catch(() => null),
         apiService.getHistorialRepresentantes(p.regional_id).catch(() => []),
+        apiService.getEleccionProcesoEventos(procesoId).catch(() => []),
       ]);
       setProceso(p);
       setPlanchas(pl);
       setResultado(res);
       setHistorial(hist);
+      setEventos(ev);
     } catch (e) {
       setError(axiosErrorMessage(e, 'Error al cargar el proceso electoral.'));
     } finally {
@@ -546,6 +642,24 @@ export function EleccionProcesoDetallePage() {
     }
   };
 
+  const descargarActa = async () => {
+    setBusy('acta');
+    setError('');
+    try {
+      const blob = await apiService.downloadEleccionActa(procesoId);
+      const url = URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `acta_escrutinio_${procesoId}.pdf`;
+      a.click();
+      URL.revokeObjectURL(url);
+    } catch (e) {
+      setError(axiosErrorMessage(e, 'No se pudo descargar el acta.'));
+    } finally {
+      setBusy(null);
+    }
+  };
+
   const handleRechazar = (planchaId: number, label: string) => {
     if (!globalThis.confirm(`¿Rechazar la plancha ${label}?`)) return;
     void run('rechazar', () => apiService.rechazarEleccionPlancha(planchaId, 'Rechazada por administración'));
@@ -584,7 +698,11 @@ export function EleccionProcesoDetallePage() {
           <div>
             <h1 className="text-2xl font-bold text-gray-900 dark:text-white">{proceso.nombre_ciclo}</h1>
             <p className="mt-1 text-sm text-gray-600 dark:text-gray-400">
-              {proceso.regional_nombre ?? `Regional ${proceso.regional_id}`} · Año {proceso.anio}
+              {proceso.regional_nombre ?? `Regional ${proceso.regional_id}`} · {ambitoProcesoTexto(proceso)} · Año{' '}
+              {proceso.anio}
+            </p>
+            <p className="text-xs text-gray-500 dark:text-gray-400">
+              Elige {proceso.cargo_titular.toLowerCase()} y {proceso.cargo_suplente.toLowerCase()}
             </p>
           </div>
           <EstadoBadge estado={proceso.estado} />
@@ -618,6 +736,7 @@ export function EleccionProcesoDetallePage() {
         onAbrirVotacion={() => void run('votacion', () => apiService.eleccionAbrirVotacion(procesoId))}
         onCalcular={() => void run('calcular', () => apiService.eleccionCalcularResultado(procesoId))}
         onExportar={() => void exportCSV()}
+        onDescargarActa={() => void descargarActa()}
       />
 
       {proceso.estado === 'empate_pendiente' ? (
@@ -625,14 +744,17 @@ export function EleccionProcesoDetallePage() {
           planchas={planchas}
           resultado={resultado}
           desempatePlanchaId={desempatePlanchaId}
+          desempateMetodo={desempateMetodo}
           desempateNota={desempateNota}
           busy={busy === 'desempate'}
           onPlanchaChange={setDesempatePlanchaId}
+          onMetodoChange={setDesempateMetodo}
           onNotaChange={setDesempateNota}
           onSubmit={() =>
             void run('desempate', () =>
               apiService.eleccionRegistrarDesempate(procesoId, {
                 plancha_ganadora_id: Number(desempatePlanchaId),
+                metodo: desempateMetodo as EleccionDesempateMetodo,
                 nota_desempate: desempateNota,
               }),
             )
@@ -640,10 +762,16 @@ export function EleccionProcesoDetallePage() {
         />
       ) : null}
 
+      {['votacion', 'empate_pendiente', 'cerrada'].includes(proceso.estado) ? (
+        <EleccionParticipacionPanel procesoId={procesoId} votacionAbierta={proceso.estado === 'votacion'} />
+      ) : null}
+
       <PlanchasSection planchas={planchas} busy={busy !== null} onRechazar={handleRechazar} />
 
       {resultado ? <ResultadosSection resultado={resultado} /> : null}
 
+      {eventos.length > 0 ? <BitacoraFasesSection eventos={eventos} /> : null}
+
       {historial.length > 0 ? <HistorialSection historial={historial} /> : null}
     </div>
   );
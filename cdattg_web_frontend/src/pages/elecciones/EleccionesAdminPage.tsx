@@ -3,7 +3,9 @@ import { Link } from 'react-router-dom';
 import { PlusIcon, ArrowPathIcon, CalendarDaysIcon, XMarkIcon } from '@heroicons/react/24/outline';
 import { apiService } from '../../services/api';
 import { axiosErrorMessage } from '../../utils/httpError';
-import type { EleccionProceso, EleccionProcesoRequest } from '../../types/eleccion';
+import type { EleccionAmbito, EleccionProceso, EleccionProcesoRequest } from '../../types/eleccion';
+import { AMBITO_LABEL, CARGOS_POR_AMBITO, ambitoProcesoTexto } from '../../utils/eleccionAmbito';
+import type { FichaCaracterizacionResponse, SedeItem } from '../../types';
 import { administracionPaths } from '../../routes/paths';
 import {
   anioActualColombia,
@@ -49,7 +51,19 @@ function buildCreatePayload(form: EleccionProcesoRequest): EleccionProcesoReques
 }
 
 function existeCicloElectoral(procesos: EleccionProceso[], regionalId: number, anio: number): boolean {
-  return procesos.some((p) => p.regional_id === regionalId && p.anio === anio);
+  return procesos.some((p) => p.ambito === 'regional' && p.regional_id === regionalId && p.anio === anio);
+}
+
+/** Un ciclo por ámbito y año: la regional, la sede o la ficha del formulario ya tiene uno. */
+function existeCicloEnAmbito(procesos: EleccionProceso[], form: EleccionProcesoRequest): boolean {
+  const ambito = form.ambito ?? 'regional';
+  if (ambito === 'regional') return existeCicloElectoral(procesos, form.regional_id, form.anio);
+  return procesos.some(
+    (p) =>
+      p.ambito === ambito &&
+      p.anio === form.anio &&
+      (ambito === 'sede' ? p.sede_id === form.sede_id : p.ficha_id === form.ficha_id),
+  );
 }
 
 function primeraRegionalSinCiclo(
@@ -61,14 +75,6 @@ function primeraRegionalSinCiclo(
   return libre?.id ?? null;
 }
 
-function puedeCrearCicloEnAnio(
-  procesos: EleccionProceso[],
-  regionales: { id: number; nombre: string }[],
-  anio: number,
-): boolean {
-  return primeraRegionalSinCiclo(procesos, regionales, anio) !== null;
-}
-
 type FechaFieldKey = (typeof FECHA_SECCIONES)[number]['campos'][number]['key'];
 
 type FechaSeccionesFieldsProps = Readonly<{
@@ -105,9 +111,175 @@ function FechaSeccionesFields({ form, onDateChange }: FechaSeccionesFieldsProps)
   );
 }
 
+type FichaAmbitoSelectProps = Readonly<{
+  fichaId?: number | null;
+  sedeIds: number[];
+  onChange: (fichaId: number | null) => void;
+}>;
+
+/** Busca la ficha del ciclo por número entre las sedes de la regional elegida. */
+function FichaAmbitoSelect({ fichaId, sedeIds, onChange }: FichaAmbitoSelectProps) {
+  const [busqueda, setBusqueda] = useState('');
+  const [fichas, setFichas] = useState<FichaCaracterizacionResponse[]>([]);
+  const [buscando, setBuscando] = useState(false);
+  const [buscada, setBuscada] = useState(false);
+
+  const buscar = async () => {
+    setBuscando(true);
+    try {
+      const res = await apiService.getFichasCaracterizacion(1, 20, undefined, false, busqueda.trim() || undefined);
+      const deLaRegional = res.data.filter((f) => f.sede_id != null && sedeIds.includes(f.sede_id));
+      setFichas(deLaRegional);
+      setBuscada(true);
+      if (deLaRegional.length === 1) onChange(deLaRegional[0].id);
+    } catch {
+      setFichas([]);
+    } finally {
+      setBuscando(false);
+    }
+  };
+
+  return (
+    <div className="sm:col-span-2">
+      <label htmlFor="eleccion-ficha-busqueda" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+        Ficha
+      </label>
+      <div className="flex gap-2">
+        <input
+          id="eleccion-ficha-busqueda"
+          className="input-field"
+          placeholder="Número de ficha"
+          value={busqueda}
+          onChange={(e) => setBusqueda(e.target.value)}
+          onKeyDown={(e) => {
+            if (e.key === 'Enter') {
+              e.preventDefault();
+              void buscar();
+            }
+          }}
+        />
+        <button type="button" className="btn-secondary shrink-0" disabled={buscando} onClick={() => void buscar()}>
+          {buscando ? 'Buscando…' : 'Buscar'}
+        </button>
+      </div>
+      {fichas.length > 0 ? (
+        <select
+          aria-label="Ficha del ciclo"
+          className="input-field mt-2"
+          value={fichaId ?? ''}
+          onChange={(e) => onChange(e.target.value ? Number(e.target.value) : null)}
+        >
+          <option value="">Seleccione la ficha</option>
+          {fichas.map((f) => (
+            <option key={f.id} value={f.id}>
+              {f.ficha} · {f.programa_formacion_nombre ?? 'Programa'} · {f.sede_nombre ?? 'Sede'}
+            </option>
+          ))}
+        </select>
+      ) : null}
+      {buscada && fichas.length === 0 ? (
+        <p className="mt-1 text-xs text-gray-500 dark:text-gray-400">No hay fichas con ese número en la regional.</p>
+      ) : null}
+    </div>
+  );
+}
+
+type AmbitoCicloFieldsProps = Readonly<{
+  form: EleccionProcesoRequest;
+  sedes: SedeItem[];
+  setField: <K extends keyof EleccionProcesoRequest>(key: K, value: EleccionProcesoRequest[K]) => void;
+  onAmbitoChange: (ambito: EleccionAmbito) => void;
+}>;
+
+function AmbitoCicloFields({ form, sedes, setField, onAmbitoChange }: AmbitoCicloFieldsProps) {
+  const ambito = form.ambito ?? 'regional';
+  const sedesRegional = sedes.filter((s) => s.regional_id === form.regional_id);
+  const [cargoTitular, cargoSuplente] = CARGOS_POR_AMBITO[ambito];
+  return (
+    <section>
+      <h3 className="text-sm font-semibold text-gray-900 dark:text-white">Ámbito y cargos</h3>
+      <p className="mt-0.5 text-xs text-gray-500 dark:text-gray-400">
+        Votan y se postulan los aprendices activos de la regional, de la sede o de la ficha.
+      </p>
+      <div className="mt-3 grid grid-cols-1 gap-4 sm:grid-cols-2">
+        <div>
+          <label htmlFor="eleccion-ambito" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Ámbito
+          </label>
+          <select
+            id="eleccion-ambito"
+            className="input-field"
+            value={ambito}
+            onChange={(e) => onAmbitoChange(e.target.value as EleccionAmbito)}
+          >
+            {(Object.keys(AMBITO_LABEL) as EleccionAmbito[]).map((a) => (
+              <option key={a} value={a}>
+                {AMBITO_LABEL[a]}
+              </option>
+            ))}
+          </select>
+        </div>
+        {ambito === 'sede' ? (
+          <div>
+            <label htmlFor="eleccion-sede" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+              Sede
+            </label>
+            <select
+              id="eleccion-sede"
+              className="input-field"
+              value={form.sede_id ?? ''}
+              onChange={(e) => setField('sede_id', e.target.value ? Number(e.target.value) : null)}
+            >
+              <option value="">Seleccione la sede</option>
+              {sedesRegional.map((s) => (
+                <option key={s.id} value={s.id}>
+                  {s.nombre}
+                </option>
+              ))}
+            </select>
+          </div>
+        ) : null}
+        {ambito === 'ficha' ? (
+          <FichaAmbitoSelect
+            key={form.regional_id}
+            fichaId={form.ficha_id}
+            sedeIds={sedesRegional.map((s) => s.id)}
+            onChange={(id) => setField('ficha_id', id)}
+          />
+        ) : null}
+        <div>
+          <label htmlFor="eleccion-cargo-titular" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Cargo del titular
+          </label>
+          <input
+            id="eleccion-cargo-titular"
+            className="input-field"
+            placeholder={cargoTitular}
+            value={form.cargo_titular ?? ''}
+            onChange={(e) => setField('cargo_titular', e.target.value)}
+          />
+        </div>
+        <div>
+          <label htmlFor="eleccion-cargo-suplente" className="mb-1 block text-sm font-medium text-gray-700 dark:text-gray-300">
+            Cargo del suplente
+          </label>
+          <input
+            id="eleccion-cargo-suplente"
+            className="input-field"
+            placeholder={cargoSuplente}
+            value={form.cargo_suplente ?? ''}
+            onChange={(e) => setField('cargo_suplente', e.target.value)}
+          />
+        </div>
+      </div>
+    </section>
+  );
+}
+
 type NuevoCicloEleccionModalProps = Readonly<{
   form: EleccionProcesoRequest;
   regionales: { id: number; nombre: string }[];
+  sedes: SedeItem[];
   procesos: EleccionProceso[];
   saving: boolean;
   onClose: () => void;
@@ -118,6 +290,7 @@ type NuevoCicloEleccionModalProps = Readonly<{
 function NuevoCicloEleccionModal({
   form,
   regionales,
+  sedes,
   procesos,
   saving,
   onClose,
@@ -127,8 +300,13 @@ function NuevoCicloEleccionModal({
   const setField = <K extends keyof EleccionProcesoRequest>(key: K, value: EleccionProcesoRequest[K]) => {
     onFormChange({ ...form, [key]: value });
   };
-  const cicloDuplicado = existeCicloElectoral(procesos, form.regional_id, form.anio);
+  const ambito = form.ambito ?? 'regional';
+  const cicloDuplicado = existeCicloEnAmbito(procesos, form);
   const regionalNombre = regionales.find((r) => r.id === form.regional_id)?.nombre ?? String(form.regional_id);
+  const ambitoIncompleto = (ambito === 'sede' && !form.sede_id) || (ambito === 'ficha' && !form.ficha_id);
+  const onAmbitoChange = (next: EleccionAmbito) => {
+    onFormChange({ ...form, ambito: next, sede_id: null, ficha_id: null });
+  };
 
   return (
     <div className="fixed inset-0 z-50 flex items-center justify-center p-4">
@@ -152,7 +330,7 @@ function NuevoCicloEleccionModal({
               </h2>
             </div>
             <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
-              Configure la regional, los plazos y los requisitos de matrícula.
+              Configure la regional, el ámbito, los plazos y los requisitos de matrícula.
             </p>
           </div>
           <button
@@ -168,8 +346,17 @@ function NuevoCicloEleccionModal({
         <div className="flex-1 space-y-6 overflow-y-auto px-5 py-4">
           {cicloDuplicado ? (
             <div className="rounded-lg border border-amber-200 bg-amber-50 px-4 py-3 text-sm text-amber-900 dark:border-amber-900/50 dark:bg-amber-950/40 dark:text-amber-200">
-              Ya existe un ciclo electoral para <strong>{regionalNombre}</strong> en el año <strong>{form.anio}</strong>.
-              Solo se permite un ciclo por regional y año.
+              {ambito === 'regional' ? (
+                <>
+                  Ya existe un ciclo electoral para <strong>{regionalNombre}</strong> en el año <strong>{form.anio}</strong>.
+                </>
+              ) : (
+                <>
+                  Ya existe un ciclo electoral para esta {AMBITO_LABEL[ambito].toLowerCase()} en el año{' '}
+                  <strong>{form.anio}</strong>.
+                </>
+              )}{' '}
+              Solo se permite un ciclo por regional, sede o ficha y año.
             </div>
           ) : null}
           <section>
@@ -183,10 +370,10 @@ function NuevoCicloEleccionModal({
                   id="eleccion-regional"
                   className="input-field"
                   value={form.regional_id}
-                  onChange={(e) => setField('regional_id', Number(e.target.value))}
+                  onChange={(e) => onFormChange({ ...form, regional_id: Number(e.target.value), sede_id: null, ficha_id: null })}
                 >
                   {regionales.map((r) => {
-                    const ocupada = existeCicloElectoral(procesos, r.id, form.anio);
+                    const ocupada = ambito === 'regional' && existeCicloElectoral(procesos, r.id, form.anio);
                     return (
                       <option key={r.id} value={r.id} disabled={ocupada}>
                         {r.nombre}
@@ -235,14 +422,32 @@ function NuevoCicloEleccionModal({
             </div>
           </section>
 
+          <AmbitoCicloFields form={form} sedes={sedes} setField={setField} onAmbitoChange={onAmbitoChange} />
+
           <FechaSeccionesFields form={form} onDateChange={(key, value) => setField(key, value)} />
+
+          <label className="flex items-start gap-3 rounded-lg border border-gray-200 p-3 text-sm dark:border-gray-700">
+            <input
+              type="checkbox"
+              className="mt-0.5"
+              checked={form.auto_transiciones ?? false}
+              onChange={(e) => setField('auto_transiciones', e.target.checked)}
+            />
+            <span>
+              <span className="block font-medium text-gray-900 dark:text-white">Avanzar fases automáticamente</span>
+              <span className="block text-gray-500 dark:text-gray-400">
+                Abre la inscripción y la votación en las fechas indicadas y, al terminar la votación (con unos minutos
+                de gracia), calcula el resultado. Si hay empate se avisa a la administración.
+              </span>
+            </span>
+          </label>
         </div>
 
         <div className="flex shrink-0 flex-col-reverse gap-2 border-t border-gray-200 bg-gray-50 px-5 py-4 dark:border-gray-700 dark:bg-gray-900/50 sm:flex-row sm:justify-end">
           <button type="button" className="btn-secondary w-full sm:w-auto" onClick={onClose} disabled={saving}>
             Cancelar
           </button>
-          <button type="button" className="btn-primary w-full sm:w-auto" disabled={saving || cicloDuplicado} onClick={onSubmit}>
+          <button type="button" className="btn-primary w-full sm:w-auto" disabled={saving || cicloDuplicado || ambitoIncompleto} onClick={onSubmit}>
             {saving ? 'Guardando…' : 'Crear ciclo'}
           </button>
         </div>
@@ -260,7 +465,7 @@ function ProcesosTableBody({ loading, items }: ProcesosTableBodyProps) {
   if (loading) {
     return (
       <tr>
-        <td colSpan={4} className="px-4 py-8 text-center text-gray-500">
+        <td colSpan={5} className="px-4 py-8 text-center text-gray-500">
           Cargando…
         </td>
       </tr>
@@ -269,7 +474,7 @@ function ProcesosTableBody({ loading, items }: ProcesosTableBodyProps) {
   if (items.length === 0) {
     return (
       <tr>
-        <td colSpan={4} className="px-4 py-8 text-center text-gray-500">
+        <td colSpan={5} className="px-4 py-8 text-center text-gray-500">
           No hay procesos electorales.
         </td>
       </tr>
@@ -284,6 +489,10 @@ function ProcesosTableBody({ loading, items }: ProcesosTableBodyProps) {
             <div className="text-xs text-gray-500">{p.anio}</div>
           </td>
           <td className="px-4 py-3 text-sm text-gray-700 dark:text-gray-300">{p.regional_nombre ?? p.regional_id}</td>
+          <td className="px-4 py-3 text-sm text-gray-700 dark:text-gray-300">
+            <div>{ambitoProcesoTexto(p)}</div>
+            <div className="text-xs text-gray-500">{p.cargo_titular}</div>
+          </td>
           <td className="px-4 py-3">
             <span className="rounded-full bg-primary-100 px-2.5 py-0.5 text-xs font-medium text-primary-800 dark:bg-primary-900/40 dark:text-primary-200">
               {ESTADO_LABEL[p.estado] ?? p.estado}
@@ -303,6 +512,7 @@ function ProcesosTableBody({ loading, items }: ProcesosTableBodyProps) {
 export function EleccionesAdminPage() {
   const [items, setItems] = useState<EleccionProceso[]>([]);
   const [regionales, setRegionales] = useState<{ id: number; nombre: string }[]>([]);
+  const [sedes, setSedes] = useState<SedeItem[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState('');
   const [modalOpen, setModalOpen] = useState(false);
@@ -312,18 +522,22 @@ export function EleccionesAdminPage() {
     anio: anioActualColombia(),
     nombre_ciclo: `Elecciones ${anioActualColombia()}`,
     min_dias_matricula: 30,
+    auto_transiciones: false,
+    ambito: 'regional',
   });
 
   const load = useCallback(async () => {
     setLoading(true);
     setError('');
     try {
-      const [procesos, reg] = await Promise.all([
+      const [procesos, reg, sedesCatalogo] = await Promise.all([
         apiService.getEleccionProcesos(),
         apiService.getCatalogosRegionales(),
+        apiService.getCatalogosSedes(),
       ]);
       setItems(procesos);
       setRegionales(reg);
+      setSedes(sedesCatalogo);
       if (reg.length > 0 && form.regional_id === 0) {
         setForm((f) => ({ ...f, regional_id: reg[0].id }));
       }
@@ -339,18 +553,17 @@ export function EleccionesAdminPage() {
   }, [load]);
 
   const anioActual = anioActualColombia();
-  const puedeCrearCiclo = puedeCrearCicloEnAnio(items, regionales, anioActual);
 
   const handleOpenModal = () => {
     setError('');
+    // Con todas las regionales ocupadas aún se pueden crear ciclos de sede o de ficha.
     const regionalId = primeraRegionalSinCiclo(items, regionales, anioActual);
-    if (regionalId === null) {
-      setError(`Ya existe un ciclo electoral para cada regional en el año ${anioActual}.`);
-      return;
-    }
     setForm((f) => ({
       ...f,
-      regional_id: regionalId,
+      regional_id: regionalId ?? regionales[0]?.id ?? 0,
+      ambito: regionalId === null ? 'ficha' : 'regional',
+      sede_id: null,
+      ficha_id: null,
       anio: anioActual,
       nombre_ciclo: `Elecciones ${anioActual}`,
     }));
@@ -358,8 +571,8 @@ export function EleccionesAdminPage() {
   };
 
   const handleCreate = async () => {
-    if (existeCicloElectoral(items, form.regional_id, form.anio)) {
-      setError('Ya existe un ciclo electoral para esta regional en el mismo año.');
+    if (existeCicloEnAmbito(items, form)) {
+      setError('Ya existe un ciclo electoral para esta regional, sede o ficha en el mismo año.');
       return;
     }
     setSaving(true);
@@ -382,7 +595,7 @@ export function EleccionesAdminPage() {
         <div>
           <h1 className="text-2xl font-bold text-gray-900 dark:text-white">Elecciones de aprendices</h1>
           <p className="text-sm text-gray-600 dark:text-gray-400">
-            Procesos electorales por regional (titular y suplente).
+            Procesos electorales por regional, sede o ficha (titular y suplente, o vocero y su suplente).
           </p>
         </div>
         <div className="flex gap-2">
@@ -393,12 +606,7 @@ export function EleccionesAdminPage() {
           <button
             type="button"
             onClick={handleOpenModal}
-            disabled={!puedeCrearCiclo || loading}
-            title={
-              puedeCrearCiclo
-                ? undefined
-                : `Todas las regionales ya tienen un ciclo en ${anioActual}.`
-            }
+            disabled={loading || regionales.length === 0}
             className="btn-primary inline-flex items-center gap-2 disabled:cursor-not-allowed disabled:opacity-60"
           >
             <PlusIcon className="h-4 w-4" />
@@ -419,6 +627,7 @@ export function EleccionesAdminPage() {
             <tr>
               <th className="px-4 py-3 text-left text-xs font-semibold uppercase text-gray-500">Ciclo</th>
               <th className="px-4 py-3 text-left text-xs font-semibold uppercase text-gray-500">Regional</th>
+              <th className="px-4 py-3 text-left text-xs font-semibold uppercase text-gray-500">Ámbito</th>
               <th className="px-4 py-3 text-left text-xs font-semibold uppercase text-gray-500">Estado</th>
               <th className="px-4 py-3 text-right text-xs font-semibold uppercase text-gray-500">Acción</th>
             </tr>
@@ -433,12 +642,13 @@ export function EleccionesAdminPage() {
         <NuevoCicloEleccionModal
           form={form}
           regionales={regionales}
+          sedes={sedes}
           procesos={items}
           saving={saving}
           onClose={() => setModalOpen(false)}
           onSubmit={() => void handleCreate()}
           onFormChange={(next) => {
-            if (existeCicloElectoral(items, next.regional_id, next.anio)) {
+            if ((next.ambito ?? 'regional') === 'regional' && existeCicloElectoral(items, next.regional_id, next.anio)) {
               const regionalLibre = primeraRegionalSinCiclo(items, regionales, next.anio);
               setForm(regionalLibre === null ? next : { ...next, regional_id: regionalLibre });
               return;
@@ -30,6 +30,14 @@ export const administracionRoutes: RouteObject[] = [
       return { Component: AdministracionConfiguracionAsistenciaPage };
     },
   },
+  {
+    path: administracionPaths.correos,
+    handle: { breadcrumb: { label: 'Correos salientes' } },
+    lazy: async () => {
+      const { AdministracionCorreosPage } = await import('../../pages/administracion/AdministracionCorreosPage');
+      return { Component: AdministracionCorreosPage };
+    },
+  },
   {
     path: administracionPaths.elecciones,
     handle: { breadcrumb: { label: 'Elecciones aprendices' } },
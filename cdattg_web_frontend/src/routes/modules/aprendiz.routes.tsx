@@ -10,6 +10,14 @@ export const aprendizRoutes: RouteObject[] = [
       return { Component: MisInasistenciasPage };
     },
   },
+  {
+    path: aprendizPaths.miAsistencia,
+    handle: { breadcrumb: { label: 'Registrar mi asistencia' } },
+    lazy: async () => {
+      const { MiAsistenciaQRPage } = await import('../../pages/aprendiz/MiAsistenciaQRPage');
+      return { Component: MiAsistenciaQRPage };
+    },
+  },
   {
     path: eleccionAprendizPaths.index,
     handle: { breadcrumb: { label: 'Elección representante' } },
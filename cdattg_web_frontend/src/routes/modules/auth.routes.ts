@@ -1,14 +1,47 @@
 import { createElement } from 'react';
 import type { RouteObject } from 'react-router-dom';
 import { RouteLoadingFallback } from '../../components/RouteLoadingFallback';
+import { authPaths } from '../paths';
 
 export const authRoutes: RouteObject[] = [
   {
-    path: '/login',
+    path: authPaths.login,
     hydrateFallbackElement: createElement(RouteLoadingFallback),
     lazy: async () => {
       const { Login } = await import('../../pages/Login');
       return { Component: Login };
     },
   },
+  {
+    path: authPaths.recuperarPassword,
+    hydrateFallbackElement: createElement(RouteLoadingFallback),
+    lazy: async () => {
+      const { RecuperarPassword } = await import('../../pages/RecuperarPassword');
+      return { Component: RecuperarPassword };
+    },
+  },
+  {
+    path: authPaths.restablecerPassword,
+    hydrateFallbackElement: createElement(RouteLoadingFallback),
+    lazy: async () => {
+      const { RestablecerPassword } = await import('../../pages/RestablecerPassword');
+      return { Component: RestablecerPassword };
+    },
+  },
+  {
+    path: authPaths.verificarEmail,
+    hydrateFallbackElement: createElement(RouteLoadingFallback),
+    lazy: async () => {
+      const { VerificarEmail } = await import('../../pages/VerificarEmail');
+      return { Component: VerificarEmail };
+    },
+  },
+  {
+    path: `${authPaths.verificarActa}/:hash`,
+    hydrateFallbackElement: createElement(RouteLoadingFallback),
+    lazy: async () => {
+      const { VerificarActa } = await import('../../pages/VerificarActa');
+      return { Component: VerificarActa };
+    },
+  },
 ];
@@ -42,5 +42,42 @@ export const bienestarRoutes: RouteObject = {
         },
       ],
     },
+    {
+      path: 'seguimiento',
+      handle: {
+        breadcrumb: { label: 'Casos de seguimiento', to: bienestarPaths.seguimiento.index },
+      },
+      children: [
+        {
+          index: true,
+          lazy: async () => {
+            const { CasosSeguimientoPage } = await import('../../pages/bienestar/seguimiento/CasosSeguimientoPage');
+            return { Component: CasosSeguimientoPage };
+          },
+        },
+        {
+          path: ':casoId',
+          handle: {
+            breadcrumb: (params: Record<string, string | undefined>) => ({
+              label: params.casoId ? `Caso #${params.casoId}` : 'Caso',
+            }),
+          },
+          lazy: async () => {
+            const { CasoSeguimientoDetallePage } = await import(
+              '../../pages/bienestar/seguimiento/CasoSeguimientoDetallePage'
+            );
+            return { Component: CasoSeguimientoDetallePage };
+          },
+        },
+      ],
+    },
+    {
+      path: 'riesgo-desercion',
+      handle: { breadcrumb: { label: 'Riesgo de deserción' } },
+      lazy: async () => {
+        const { RiesgoDesercionPage } = await import('../../pages/bienestar/riesgo/RiesgoDesercionPage');
+        return { Component: RiesgoDesercionPage };
+      },
+    },
   ],
 };
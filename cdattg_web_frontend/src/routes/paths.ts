@@ -2,6 +2,16 @@
 export const DASHBOARD_PATH = '/dashboard';
 export const PERFIL_PATH = '/perfil';
 
+/** Páginas públicas de acceso; restablecer y verificar correo reciben ?token= desde el enlace enviado por correo. */
+export const authPaths = {
+  login: '/login',
+  recuperarPassword: '/recuperar-password',
+  restablecerPassword: '/restablecer-password',
+  verificarEmail: '/verificar-email',
+  /** Enlace impreso en el pie del acta de escrutinio. */
+  verificarActa: '/verificar-acta',
+} as const;
+
 export const personasPaths = {
   index: '/personas',
   importar: '/personas/importar',
@@ -18,6 +28,7 @@ export const aprendicesPaths = {
 
 export const aprendizPaths = {
   misInasistencias: '/mis-inasistencias',
+  miAsistencia: '/mi-asistencia',
 } as const;
 
 export const programasPaths = {
@@ -66,6 +77,11 @@ export const bienestarPaths = {
       return qs ? `${base}?${qs}` : base;
     },
   },
+  seguimiento: {
+    index: '/bienestar/seguimiento',
+    detalle: (id: number | string) => `/bienestar/seguimiento/${id}`,
+  },
+  riesgo: '/bienestar/riesgo-desercion',
 } as const;
 
 export const inventarioPaths = {
@@ -100,6 +116,7 @@ export const administracionPaths = {
   jornadas: '/administracion/jornadas',
   diasSinFormacion: '/administracion/dias-sin-formacion',
   configuracionAsistencia: '/administracion/configuracion-asistencia',
+  correos: '/administracion/correos',
   elecciones: '/administracion/elecciones',
   eleccionDetalle: (id: number | string) => `/administracion/elecciones/${id}`,
 } as const;
@@ -1,24 +1,27 @@
 import axios, { AxiosError } from 'axios';
-import type { AxiosInstance } from 'axios';
+import type { AxiosInstance, InternalAxiosRequestConfig } from 'axios';
 import { API_BASE_URL } from '../config/api';
 import type {
   LoginRequest,
   LoginResponse,
+  TokenResponse,
+  SesionResponse,
+  ResumenesSemanalesResponse,
+  NotificacionesListResponse,
+  NotificacionPreferenciaItem,
+  LoginAccesoResponse,
   ChangePasswordRequest,
+  RestablecerPasswordRequest,
   UserResponse,
   PersonaRequest,
   PersonaSelfUpdateRequest,
   PersonaResponse,
   PaginatedResponse,
-  PersonaImportResult,
   PersonaImportLogItem,
-  PersonaImportProgress,
-  InstructorImportResult,
+  ImportacionTrabajo,
   InstructorImportLogItem,
   ProgramaFormacionRequest,
   ProgramaFormacionResponse,
-  ProgramaImportResult,
-  FichaImportResult,
   FichaCaracterizacionRequest,
   FichaCaracterizacionResponse,
   InstructorItem,
@@ -34,21 +37,44 @@ import type {
   AsistenciaResponse,
   AsistenciaAprendizRequest,
   AsistenciaAprendizResponse,
+  AsistenciaCodigoQRResponse,
   TipoObservacionAsistenciaItem,
   TipoObservacionAsistenciaCreateRequest,
   AsistenciaDashboardResponse,
   DashboardResumenResponse,
   AsistenciaAnalisisResponse,
   CasosBienestarResponse,
+  CasoBienestar,
+  CasoBienestarAdjunto,
+  CasoBienestarDetalle,
+  CasoBienestarEstado,
+  CasoBienestarIntervencion,
+  CasoBienestarIntervencionRequest,
+  CasoBienestarRecordatorio,
+  CasoBienestarUpdateRequest,
+  CasosBienestarAbrirRequest,
+  CasosBienestarAbrirResponse,
+  ProfesionalBienestarItem,
+  NivelRiesgoDesercion,
+  RiesgoDesercionResponse,
   SesionesSinAsistenciaTomadaResponse,
   CasoBienestarAprendizDetalleResponse,
   MisInasistenciasResponse,
+  ExcusaResponse,
+  ExcusaRevisionRequest,
+  EvaluacionFichaResponse,
+  JuicioRegistroItem,
+  EvaluacionAlertaItem,
   SedeItem,
   AmbienteItem,
   ModalidadFormacionItem,
   JornadaItem,
   JornadaAdminItem,
   DiaSinFormacionSedeItem,
+  CorreoSalienteEstado,
+  CorreoSalienteItem,
+  CorreoSalienteDetalle,
+  CorreosSalientesListResponse,
   ConfiguracionAsistenciaItem,
   JornadaPropagateResult,
   JornadaUpdateResponse,
@@ -66,6 +92,7 @@ import type {
   AmbienteUpdateRequest,
   AmbienteResponse,
   AmbienteListItem,
+  AmbienteOcupacionResponse,
   PisoCreateRequest,
   PisoUpdateRequest,
   PisoResponse,
@@ -91,17 +118,27 @@ import type {
   UsuarioPermisosResponse,
   UsuarioRegionalesResponse,
   DefinicionesPermisosResponse,
+  ReporteAsistenciaFormato,
+  ReporteAsistenciaJob,
+  ReporteAsistenciaSolicitud,
 } from '../types';
-import type { InstructorAgendaResponse } from '../types/agenda';
+import type { AgendaSuscripcionResponse, InstructorAgendaResponse } from '../types/agenda';
 import type {
+  EleccionActaVerificacion,
   EleccionDesempateRequest,
+  EleccionParticipacion,
+  EleccionRecordatorioCanal,
+  EleccionRecordatorioResponse,
   EleccionMiRegional,
   EleccionPlancha,
   EleccionPlanchaRequest,
   EleccionProceso,
+  EleccionProcesoEvento,
   EleccionProcesoRequest,
   EleccionResultado,
   EleccionVotoRequest,
+  EleccionVotoResponse,
+  EleccionRecibo,
   RepresentanteAprendiz,
 } from '../types/eleccion';
 import { sortAprendicesAz } from '../utils/sortAprendices';
@@ -110,103 +147,85 @@ function normalizeAprendicesList(data: AprendizResponse[]): AprendizResponse[] {
   return sortAprendicesAz(data);
 }
 
-/** Payload parcial desde el stream NDJSON de importación de personas */
-type PersonaImportStreamJson = PersonaImportProgress & {
-  error?: string;
-  processed_count?: number;
-  duplicates_count?: number;
-  error_count?: number;
-  status?: string;
-  processed?: number;
-  duplicates?: number;
-  errors?: number;
-};
-
-function parsePersonaImportStreamLine(
-  trimmed: string,
-  onProgress: (p: PersonaImportProgress) => void
-): { streamError: string | null; finalResult: PersonaImportResult | null } {
-  if (!trimmed) {
-    return { streamError: null, finalResult: null };
-  }
-  try {
-    const data = JSON.parse(trimmed) as PersonaImportStreamJson;
-    if (data.type === 'error' && data.error) {
-      return { streamError: data.error, finalResult: null };
-    }
-    if (data.total !== undefined) {
-      onProgress(data);
-    }
-    if (data.type === 'done' || data.type === 'result') {
-      return {
-        streamError: null,
-        finalResult: {
-          processed_count: data.processed_count ?? data.processed ?? 0,
-          duplicates_count: data.duplicates_count ?? data.duplicates ?? 0,
-          error_count: data.error_count ?? data.errors ?? 0,
-          status: data.status ?? 'completado',
-        },
-      };
-    }
-  } catch {
-    // Ignorar líneas que no sean JSON válido (fragmentos de chunk)
-  }
-  return { streamError: null, finalResult: null };
+const POLLING_IMPORTACION_MS = 2000;
+
+function importacionTerminada(t: ImportacionTrabajo): boolean {
+  return t.estado === 'completado' || t.estado === 'fallido';
 }
 
-async function consumePersonasImportNdjsonStream(
-  reader: ReadableStreamDefaultReader<Uint8Array>,
-  onProgress: (p: PersonaImportProgress) => void
-): Promise<PersonaImportResult> {
+/** Lee un bloque de Server-Sent Events ("event: x\ndata: {...}"); null si no trae datos. */
+function parseEventoSse(bloque: string): { event: string; data: string } | null {
+  let event = 'message';
+  const data: string[] = [];
+  for (const line of bloque.split('\n')) {
+    if (line.startsWith(':')) continue;
+    const sep = line.indexOf(':');
+    const campo = sep >= 0 ? line.slice(0, sep) : line;
+    const valor = sep >= 0 ? line.slice(sep + 1).replace(/^ /, '') : '';
+    if (campo === 'event') event = valor;
+    else if (campo === 'data') data.push(valor);
+  }
+  return data.length > 0 ? { event, data: data.join('\n') } : null;
+}
+
+/**
+ * Consume /importaciones/:id/eventos hasta que el trabajo termine. Devuelve null si la conexión se corta antes
+ * (el llamador sigue por polling).
+ */
+async function consumirEventosImportacion(
+  url: string,
+  token: string | null,
+  onProgreso: (t: ImportacionTrabajo) => void,
+  signal?: AbortSignal
+): Promise<ImportacionTrabajo | null> {
+  const res = await fetch(url, {
+    headers: {
+      ...(token ? { Authorization: `Bearer ${token}` } : {}),
+      Accept: 'text/event-stream',
+    },
+    signal,
+  });
+  const reader = res.ok ? res.body?.getReader() : undefined;
+  if (!reader) return null;
   const dec = new TextDecoder();
   let buffer = '';
-  let finalResult: PersonaImportResult | null = null;
-  let streamError: string | null = null;
-
-  outer: while (true) {
+  let ultimo: ImportacionTrabajo | null = null;
+  while (true) {
     const { done, value } = await reader.read();
     if (done) break;
-    buffer += dec.decode(value, { stream: true });
-    const lines = buffer.split('\n');
-    buffer = lines.pop() || '';
-    for (const line of lines) {
-      const trimmed = line.trim();
-      const { streamError: err, finalResult: fr } = parsePersonaImportStreamLine(trimmed, onProgress);
-      if (err) {
-        streamError = err;
-        break outer;
-      }
-      if (fr) {
-        finalResult = fr;
+    buffer += dec.decode(value, { stream: true }).replace(/\r\n/g, '\n');
+    const bloques = buffer.split('\n\n');
+    buffer = bloques.pop() || '';
+    for (const bloque of bloques) {
+      const ev = parseEventoSse(bloque);
+      if (!ev) continue;
+      if (ev.event === 'error') {
+        const err = JSON.parse(ev.data) as { error?: string };
+        throw new Error(err.error ?? 'Error siguiendo la importación');
       }
+      if (ev.event !== 'progreso') continue;
+      ultimo = JSON.parse(ev.data) as ImportacionTrabajo;
+      onProgreso(ultimo);
     }
   }
-
-  if (streamError) throw new Error(streamError);
-  if (!finalResult) throw new Error('Importación sin resultado');
-  return finalResult;
+  return ultimo && importacionTerminada(ultimo) ? ultimo : null;
 }
 
-async function openPersonasImportStreamReader(
-  url: string,
-  formData: FormData,
-  token: string | null
-): Promise<ReadableStreamDefaultReader<Uint8Array>> {
-  const res = await fetch(url, {
-    method: 'POST',
-    headers: {
-      ...(token ? { Authorization: `Bearer ${token}` } : {}),
-      'X-Stream-Progress': 'true',
-    },
-    body: formData,
+function esperar(ms: number, signal?: AbortSignal): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const timer = setTimeout(resolve, ms);
+    signal?.addEventListener('abort', () => {
+      clearTimeout(timer);
+      reject(new DOMException('Cancelado', 'AbortError'));
+    });
   });
-  if (!res.ok) {
-    const err = (await res.json().catch(() => ({}))) as { error?: string };
-    throw new Error(err.error ?? res.statusText);
-  }
-  const reader = res.body?.getReader();
-  if (!reader) throw new Error('No se pudo leer la respuesta');
-  return reader;
+}
+
+/** Borra los datos de sesión guardados en el navegador. */
+export function clearStoredSession(): void {
+  localStorage.removeItem('token');
+  localStorage.removeItem('refresh_token');
+  localStorage.removeItem('user');
 }
 
 class ApiService {
@@ -232,24 +251,52 @@ class ApiService {
       (error) => Promise.reject(error)
     );
 
-    // Interceptor para manejar errores
+    // Interceptor para manejar errores: ante 401 intenta renovar el access token una vez con el refresh token.
     this.api.interceptors.response.use(
       (response) => response,
-      (error: AxiosError) => {
-        if (error.response?.status === 401) {
-          const isLoginRequest = error.config?.url?.includes('/auth/login');
-          if (!isLoginRequest) {
-            localStorage.removeItem('token');
-            localStorage.removeItem('user');
-            // Avisar a la app para cerrar sesión sin recargar la página (evita pantalla en blanco en móvil/PC).
-            globalThis.dispatchEvent(new CustomEvent('auth:session-expired'));
+      async (error: AxiosError) => {
+        const original = error.config as (InternalAxiosRequestConfig & { _reintentado?: boolean }) | undefined;
+        const isAuthRequest = original?.url?.includes('/auth/login') || original?.url?.includes('/auth/refresh');
+        if (error.response?.status === 401 && original && !isAuthRequest) {
+          if (!original._reintentado && localStorage.getItem('refresh_token')) {
+            original._reintentado = true;
+            try {
+              const token = await this.refreshTokens();
+              original.headers.Authorization = `Bearer ${token}`;
+              return this.api(original);
+            } catch {
+              // refresh inválido: se cierra la sesión abajo
+            }
           }
+          clearStoredSession();
+          // Avisar a la app para cerrar sesión sin recargar la página (evita pantalla en blanco en móvil/PC).
+          globalThis.dispatchEvent(new CustomEvent('auth:session-expired'));
         }
         return Promise.reject(error);
       }
     );
   }
 
+  private refreshEnCurso: Promise<string> | null = null;
+
+  /** Renueva el par de tokens; las peticiones concurrentes comparten la misma renovación. */
+  refreshTokens(): Promise<string> {
+    if (!this.refreshEnCurso) {
+      const refreshToken = localStorage.getItem('refresh_token') ?? '';
+      this.refreshEnCurso = axios
+        .post<TokenResponse>(`${this.api.defaults.baseURL ?? ''}/auth/refresh`, { refresh_token: refreshToken })
+        .then((response) => {
+          localStorage.setItem('token', response.data.token);
+          localStorage.setItem('refresh_token', response.data.refresh_token);
+          return response.data.token;
+        })
+        .finally(() => {
+          this.refreshEnCurso = null;
+        });
+    }
+    return this.refreshEnCurso;
+  }
+
   // Auth endpoints
   async login(credentials: LoginRequest): Promise<LoginResponse> {
     const response = await this.api.post<LoginResponse>('/auth/login', credentials);
@@ -261,11 +308,106 @@ class ApiService {
     return response.data;
   }
 
+  async logout(): Promise<void> {
+    // El token se lee ya: quien llama limpia el almacenamiento sin esperar la respuesta.
+    const token = localStorage.getItem('token');
+    await this.api.post('/auth/logout', null, { headers: { Authorization: `Bearer ${token}` } });
+  }
+
+  async logoutAll(): Promise<void> {
+    await this.api.post('/auth/logout-all');
+  }
+
+  async getMisSesiones(): Promise<SesionResponse[]> {
+    const response = await this.api.get<{ data: SesionResponse[] }>('/auth/sesiones');
+    return response.data.data ?? [];
+  }
+
+  async cerrarMiSesion(id: number): Promise<void> {
+    await this.api.delete(`/auth/sesiones/${id}`);
+  }
+
+  async getResumenesSemanales(): Promise<ResumenesSemanalesResponse> {
+    const response = await this.api.get<{ data: ResumenesSemanalesResponse }>('/auth/resumenes-semanales');
+    return response.data.data;
+  }
+
+  async actualizarResumenSemanal(tipo: string, activo: boolean): Promise<ResumenesSemanalesResponse> {
+    const response = await this.api.put<{ data: ResumenesSemanalesResponse }>('/auth/resumenes-semanales', {
+      tipo,
+      activo,
+    });
+    return response.data.data;
+  }
+
+  async getNotificaciones(params?: {
+    page?: number;
+    page_size?: number;
+    solo_no_leidas?: boolean;
+  }): Promise<NotificacionesListResponse> {
+    const response = await this.api.get<NotificacionesListResponse>('/notificaciones', { params });
+    return response.data;
+  }
+
+  async getNotificacionesNoLeidas(): Promise<number> {
+    const response = await this.api.get<{ data: { no_leidas: number } }>('/notificaciones/no-leidas');
+    return response.data.data.no_leidas;
+  }
+
+  async marcarNotificacionLeida(id: number): Promise<number> {
+    const response = await this.api.put<{ data: { no_leidas: number } }>(`/notificaciones/${id}/leida`);
+    return response.data.data.no_leidas;
+  }
+
+  async marcarTodasNotificacionesLeidas(): Promise<number> {
+    const response = await this.api.put<{ data: { no_leidas: number } }>('/notificaciones/leidas');
+    return response.data.data.no_leidas;
+  }
+
+  async getNotificacionPreferencias(): Promise<NotificacionPreferenciaItem[]> {
+    const response = await this.api.get<{ data: NotificacionPreferenciaItem[] }>('/notificaciones/preferencias');
+    return response.data.data ?? [];
+  }
+
+  async actualizarNotificacionPreferencia(tipo: string, activo: boolean): Promise<NotificacionPreferenciaItem[]> {
+    const response = await this.api.put<{ data: NotificacionPreferenciaItem[] }>('/notificaciones/preferencias', {
+      tipo,
+      activo,
+    });
+    return response.data.data ?? [];
+  }
+
+  async getMisAccesos(limit = 20): Promise<LoginAccesoResponse[]> {
+    const response = await this.api.get<{ data: LoginAccesoResponse[] }>('/auth/accesos', { params: { limit } });
+    return response.data.data ?? [];
+  }
+
   async changePassword(data: ChangePasswordRequest): Promise<{ message: string }> {
     const response = await this.api.post<{ message: string }>('/auth/change-password', data);
     return response.data;
   }
 
+  /** Siempre responde el mismo mensaje, exista o no la cuenta. */
+  async recuperarPassword(login: string): Promise<{ message: string }> {
+    const response = await this.api.post<{ message: string }>('/auth/recuperar-password', { login });
+    return response.data;
+  }
+
+  async restablecerPassword(data: RestablecerPasswordRequest): Promise<{ message: string }> {
+    const response = await this.api.post<{ message: string }>('/auth/restablecer-password', data);
+    return response.data;
+  }
+
+  async solicitarVerificacionEmail(): Promise<{ message: string }> {
+    const response = await this.api.post<{ message: string }>('/auth/verificar-email/solicitar');
+    return response.data;
+  }
+
+  async verificarEmail(token: string): Promise<{ message: string }> {
+    const response = await this.api.post<{ message: string }>('/auth/verificar-email', { token });
+    return response.data;
+  }
+
   // Personas endpoints
   async getPersonas(page: number = 1, pageSize: number = 20, search: string = ''): Promise<PaginatedResponse<PersonaResponse>> {
     const response = await this.api.get<PaginatedResponse<PersonaResponse>>('/personas', {
@@ -302,30 +444,14 @@ class ApiService {
     await this.api.post(`/personas/${id}/reset-password`);
   }
 
-  async uploadPersonasImport(file: File): Promise<PersonaImportResult> {
+  /** Encola la importación de personas; el avance se sigue con seguirImportacion. */
+  async uploadPersonasImport(file: File): Promise<ImportacionTrabajo> {
     const formData = new FormData();
     formData.append('file', file);
-    const response = await this.api.post<PersonaImportResult>('/personas/import', formData, {
+    const response = await this.api.post<{ data: ImportacionTrabajo }>('/personas/import', formData, {
       headers: { 'Content-Type': 'multipart/form-data' },
     });
-    return response.data;
-  }
-
-  /**
-   * Importa personas con progreso en tiempo real (streaming).
-   * Llama a onProgress con cada actualización y devuelve el resultado final.
-   */
-  async uploadPersonasImportWithProgress(
-    file: File,
-    onProgress: (p: PersonaImportProgress) => void
-  ): Promise<PersonaImportResult> {
-    const token = localStorage.getItem('token');
-    const baseURL = this.api.defaults.baseURL || '';
-    const url = `${baseURL}/personas/import`;
-    const formData = new FormData();
-    formData.append('file', file);
-    const reader = await openPersonasImportStreamReader(url, formData, token);
-    return consumePersonasImportNdjsonStream(reader, onProgress);
+    return response.data.data;
   }
 
   async getPersonaImports(limit: number = 50): Promise<PersonaImportLogItem[]> {
@@ -342,6 +468,62 @@ class ApiService {
     return response.data;
   }
 
+  // Importaciones en segundo plano (personas, instructores, ficha, programas)
+  async getImportaciones(
+    params: { tipo?: string; estado?: string; page?: number; page_size?: number } = {}
+  ): Promise<PaginatedResponse<ImportacionTrabajo>> {
+    const response = await this.api.get<PaginatedResponse<ImportacionTrabajo>>('/importaciones', { params });
+    return response.data;
+  }
+
+  async getImportacion(id: number): Promise<ImportacionTrabajo> {
+    const response = await this.api.get<{ data: ImportacionTrabajo }>(`/importaciones/${id}`);
+    return response.data.data;
+  }
+
+  /** Devuelve a la cola una importación fallida; sigue desde la última fila guardada. */
+  async reanudarImportacion(id: number): Promise<ImportacionTrabajo> {
+    const response = await this.api.post<{ data: ImportacionTrabajo }>(`/importaciones/${id}/reanudar`);
+    return response.data.data;
+  }
+
+  async downloadIncidenciasImportacion(id: number): Promise<Blob> {
+    const response = await this.api.get<Blob>(`/importaciones/${id}/incidencias`, {
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
+  /**
+   * Sigue el avance de una importación hasta que termine (completada o fallida). Usa Server-Sent Events y, si la
+   * conexión se corta o el navegador no la soporta, consulta el trabajo cada 2 segundos.
+   */
+  async seguirImportacion(
+    id: number,
+    onProgreso: (t: ImportacionTrabajo) => void,
+    signal?: AbortSignal
+  ): Promise<ImportacionTrabajo> {
+    const baseURL = this.api.defaults.baseURL || '';
+    try {
+      const final = await consumirEventosImportacion(
+        `${baseURL}/importaciones/${id}/eventos`,
+        localStorage.getItem('token'),
+        onProgreso,
+        signal
+      );
+      if (final) return final;
+    } catch (err: unknown) {
+      if (signal?.aborted) throw err;
+      // Sin canal de eventos (proxy, sesión renovada, error puntual): el polling muestra el error real si lo hay.
+    }
+    while (true) {
+      const t = await this.getImportacion(id);
+      onProgreso(t);
+      if (importacionTerminada(t)) return t;
+      await esperar(POLLING_IMPORTACION_MS, signal);
+    }
+  }
+
   // Programas de formación
   async getProgramasFormacion(page = 1, pageSize = 20, search = ''): Promise<PaginatedResponse<ProgramaFormacionResponse>> {
     const response = await this.api.get<PaginatedResponse<ProgramaFormacionResponse>>('/programas-formacion', {
@@ -369,13 +551,13 @@ class ApiService {
     await this.api.delete(`/programas-formacion/${id}`);
   }
 
-  async uploadProgramasImport(file: File): Promise<ProgramaImportResult> {
+  async uploadProgramasImport(file: File): Promise<ImportacionTrabajo> {
     const formData = new FormData();
     formData.append('file', file);
-    const response = await this.api.post<ProgramaImportResult>('/programas-formacion/import', formData, {
+    const response = await this.api.post<{ data: ImportacionTrabajo }>('/programas-formacion/import', formData, {
       headers: { 'Content-Type': 'multipart/form-data' },
     });
-    return response.data;
+    return response.data.data;
   }
 
   // Catalogos (para formulario de ficha)
@@ -475,6 +657,36 @@ class ApiService {
     await this.api.delete(`/administracion/dias-sin-formacion/${id}`);
   }
 
+  async getCorreosSalientes(params?: {
+    estado?: CorreoSalienteEstado;
+    plantilla?: string;
+    destinatario?: string;
+    desde?: string;
+    hasta?: string;
+    page?: number;
+    page_size?: number;
+  }): Promise<CorreosSalientesListResponse> {
+    const response = await this.api.get<CorreosSalientesListResponse>('/admin/correos', { params });
+    return response.data;
+  }
+
+  async getCorreoSaliente(id: number): Promise<CorreoSalienteDetalle> {
+    const response = await this.api.get<{ data: CorreoSalienteDetalle }>(`/admin/correos/${id}`);
+    return response.data.data;
+  }
+
+  async reintentarCorreoSaliente(id: number): Promise<CorreoSalienteItem> {
+    const response = await this.api.post<{ data: CorreoSalienteItem }>(`/admin/correos/${id}/reintentar`);
+    return response.data.data;
+  }
+
+  async descargarAdjuntoCorreoSaliente(id: number, adjuntoId: number): Promise<Blob> {
+    const response = await this.api.get<Blob>(`/admin/correos/${id}/adjuntos/${adjuntoId}`, {
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
   async getCatalogosDiasFormacion(): Promise<DiaFormacionItem[]> {
     const response = await this.api.get<{ data: DiaFormacionItem[] }>('/catalogos/dias-formacion');
     return response.data.data;
@@ -586,6 +798,14 @@ class ApiService {
     await this.api.delete(`/infraestructura/ambientes/${id}`);
   }
 
+  /** Grilla semanal de ocupación del ambiente; fecha (YYYY-MM-DD) es cualquier día de la semana. */
+  async getAmbienteOcupacion(id: number, fecha?: string): Promise<AmbienteOcupacionResponse> {
+    const response = await this.api.get<AmbienteOcupacionResponse>(`/infraestructura/ambientes/${id}/ocupacion`, {
+      params: fecha ? { fecha } : undefined,
+    });
+    return response.data;
+  }
+
   // Fichas de caracterización
   async getFichasCaracterizacion(
     page = 1,
@@ -625,13 +845,13 @@ class ApiService {
     await this.api.delete(`/fichas-caracterizacion/${id}`);
   }
 
-  async uploadFichasImport(file: File): Promise<FichaImportResult> {
+  async uploadFichasImport(file: File): Promise<ImportacionTrabajo> {
     const formData = new FormData();
     formData.append('file', file);
-    const response = await this.api.post<FichaImportResult>('/fichas-caracterizacion/import', formData, {
+    const response = await this.api.post<{ data: ImportacionTrabajo }>('/fichas-caracterizacion/import', formData, {
       headers: { 'Content-Type': 'multipart/form-data' },
     });
-    return response.data;
+    return response.data.data;
   }
 
   async exportAllFichasExcel(): Promise<Blob> {
@@ -674,6 +894,38 @@ class ApiService {
     return response.data;
   }
 
+  /** Agenda en iCalendar (.ics); sin rango usa la ventana por defecto del servidor */
+  async downloadInstructorAgendaICS(desde?: string, hasta?: string): Promise<Blob> {
+    const response = await this.api.get<Blob>('/instructor/agenda.ics', {
+      params: { desde, hasta },
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
+  async downloadFichaAgendaICS(fichaId: number, desde?: string, hasta?: string): Promise<Blob> {
+    const response = await this.api.get<Blob>(`/fichas-caracterizacion/${fichaId}/agenda.ics`, {
+      params: { desde, hasta },
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
+  async getAgendaSuscripcion(): Promise<AgendaSuscripcionResponse> {
+    const response = await this.api.get<AgendaSuscripcionResponse>('/instructor/agenda/suscripcion');
+    return response.data;
+  }
+
+  /** Genera (o regenera) el enlace de suscripción; el anterior deja de funcionar */
+  async generarAgendaSuscripcion(): Promise<AgendaSuscripcionResponse> {
+    const response = await this.api.post<AgendaSuscripcionResponse>('/instructor/agenda/suscripcion');
+    return response.data;
+  }
+
+  async revocarAgendaSuscripcion(): Promise<void> {
+    await this.api.delete('/instructor/agenda/suscripcion');
+  }
+
   // Aprendices de una ficha
   async getFichaAprendices(fichaId: number): Promise<AprendizResponse[]> {
     const response = await this.api.get<{ data: AprendizResponse[] }>(`/fichas-caracterizacion/${fichaId}/aprendices`);
@@ -688,6 +940,23 @@ class ApiService {
     await this.api.post(`/fichas-caracterizacion/${fichaId}/aprendices/desasignar`, { personas });
   }
 
+  /** Matriz de evaluación: juicio de cada aprendiz por RAP del programa de la ficha. */
+  async getFichaEvaluacion(fichaId: number): Promise<EvaluacionFichaResponse> {
+    const response = await this.api.get<EvaluacionFichaResponse>(`/fichas-caracterizacion/${fichaId}/evaluacion`);
+    return response.data;
+  }
+
+  async registrarJuicios(fichaId: number, juicios: JuicioRegistroItem[]): Promise<EvaluacionFichaResponse> {
+    const response = await this.api.put<EvaluacionFichaResponse>(`/fichas-caracterizacion/${fichaId}/evaluacion/juicios`, { juicios });
+    return response.data;
+  }
+
+  /** Fichas por finalizar con RAPs sin juicio definitivo. */
+  async getAlertasCierreEvaluacion(): Promise<EvaluacionAlertaItem[]> {
+    const response = await this.api.get<{ data: EvaluacionAlertaItem[] }>('/fichas-caracterizacion/evaluacion/alertas');
+    return response.data.data;
+  }
+
   async setOcultoAprendicesAsistencia(
     fichaId: number,
     personas: number[],
@@ -758,14 +1027,14 @@ class ApiService {
   }
 
   /** Importación masiva de instructores desde Excel. Opcional: regional_id para asignar regional por defecto. */
-  async uploadInstructoresImport(file: File, regionalId?: number): Promise<InstructorImportResult> {
+  async uploadInstructoresImport(file: File, regionalId?: number): Promise<ImportacionTrabajo> {
     const formData = new FormData();
     formData.append('file', file);
-    const response = await this.api.post<InstructorImportResult>('/instructores/import', formData, {
+    const response = await this.api.post<{ data: ImportacionTrabajo }>('/instructores/import', formData, {
       headers: { 'Content-Type': 'multipart/form-data' },
       params: regionalId != null && regionalId > 0 ? { regional_id: regionalId } : undefined,
     });
-    return response.data;
+    return response.data.data;
   }
 
   async getInstructorImports(limit: number = 50): Promise<InstructorImportLogItem[]> {
@@ -863,12 +1132,146 @@ class ApiService {
     return response.data;
   }
 
+  /** Casos de seguimiento de bienestar. responsable_user_id acepta un id, 'yo' o 'ninguno'. */
+  async listCasosBienestarSeguimiento(params?: {
+    estado?: CasoBienestarEstado;
+    responsable_user_id?: number | 'yo' | 'ninguno';
+    ficha_id?: number;
+  }): Promise<CasoBienestar[]> {
+    const response = await this.api.get<{ data: CasoBienestar[] }>('/bienestar/casos', { params });
+    return response.data.data ?? [];
+  }
+
+  async crearCasoBienestar(data: {
+    aprendiz_id: number;
+    motivo: string;
+    responsable_user_id?: number;
+  }): Promise<CasoBienestar> {
+    const response = await this.api.post<CasoBienestar>('/bienestar/casos', data);
+    return response.data;
+  }
+
+  /** Abre casos para aprendices del listado calculado; omite los que ya tienen caso abierto. */
+  async abrirCasosBienestarDesdeCalculo(data: CasosBienestarAbrirRequest): Promise<CasosBienestarAbrirResponse> {
+    const response = await this.api.post<CasosBienestarAbrirResponse>('/bienestar/casos/desde-calculo', data);
+    return response.data;
+  }
+
+  async getCasoBienestarSeguimiento(id: number): Promise<CasoBienestarDetalle> {
+    const response = await this.api.get<CasoBienestarDetalle>(`/bienestar/casos/${id}`);
+    return response.data;
+  }
+
+  async actualizarCasoBienestar(id: number, data: CasoBienestarUpdateRequest): Promise<CasoBienestar> {
+    const response = await this.api.put<CasoBienestar>(`/bienestar/casos/${id}`, data);
+    return response.data;
+  }
+
+  async registrarIntervencionCasoBienestar(
+    id: number,
+    data: CasoBienestarIntervencionRequest
+  ): Promise<CasoBienestarIntervencion> {
+    const response = await this.api.post<CasoBienestarIntervencion>(`/bienestar/casos/${id}/intervenciones`, data);
+    return response.data;
+  }
+
+  async subirAdjuntoCasoBienestar(id: number, archivo: File): Promise<CasoBienestarAdjunto> {
+    const formData = new FormData();
+    formData.append('archivo', archivo);
+    const response = await this.api.post<CasoBienestarAdjunto>(`/bienestar/casos/${id}/adjuntos`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  }
+
+  async descargarAdjuntoCasoBienestar(id: number, adjuntoId: number): Promise<Blob> {
+    const response = await this.api.get<Blob>(`/bienestar/casos/${id}/adjuntos/${adjuntoId}`, {
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
+  async crearRecordatorioCasoBienestar(
+    id: number,
+    data: { fecha_recordatorio: string; nota: string }
+  ): Promise<CasoBienestarRecordatorio> {
+    const response = await this.api.post<CasoBienestarRecordatorio>(`/bienestar/casos/${id}/recordatorios`, data);
+    return response.data;
+  }
+
+  async completarRecordatorioCasoBienestar(id: number, recordatorioId: number): Promise<void> {
+    await this.api.put(`/bienestar/casos/${id}/recordatorios/${recordatorioId}/completar`);
+  }
+
+  /** Recordatorios vencidos o de hoy de los casos asignados al usuario autenticado. */
+  async getMisRecordatoriosBienestar(): Promise<CasoBienestarRecordatorio[]> {
+    const response = await this.api.get<{ data: CasoBienestarRecordatorio[] }>('/bienestar/mis-recordatorios');
+    return response.data.data ?? [];
+  }
+
+  /** Casos del aprendiz en todas sus fichas (historial por persona). */
+  async getHistorialCasosBienestarAprendiz(aprendizId: number): Promise<CasoBienestar[]> {
+    const response = await this.api.get<{ data: CasoBienestar[] }>(`/bienestar/aprendices/${aprendizId}/casos`);
+    return response.data.data ?? [];
+  }
+
+  async getProfesionalesBienestar(): Promise<ProfesionalBienestarItem[]> {
+    const response = await this.api.get<{ data: ProfesionalBienestarItem[] }>('/bienestar/profesionales');
+    return response.data.data ?? [];
+  }
+
+  /** Ranking de riesgo de deserción (puntaje 0-100). Params: sede_id, ficha_id, dias (default 30), nivel. */
+  async getRiesgoDesercion(params?: {
+    sede_id?: number;
+    ficha_id?: number;
+    dias?: number;
+    nivel?: NivelRiesgoDesercion;
+  }): Promise<RiesgoDesercionResponse> {
+    const response = await this.api.get<RiesgoDesercionResponse>('/bienestar/riesgo-desercion', { params });
+    return response.data;
+  }
+
   /** Inasistencias del aprendiz autenticado (resuelto por persona_id del JWT). */
   async getMisInasistencias(params?: { dias?: number }): Promise<MisInasistenciasResponse> {
     const response = await this.api.get<MisInasistenciasResponse>('/asistencias/mis-inasistencias', { params });
     return response.data;
   }
 
+  /** Presenta una excusa (aprendiz autenticado) con su soporte PDF/JPG/PNG. */
+  async presentarExcusa(asistenciaIds: number[], motivo: string, archivo: File): Promise<ExcusaResponse> {
+    const formData = new FormData();
+    formData.append('asistencia_ids', asistenciaIds.join(','));
+    formData.append('motivo', motivo);
+    formData.append('archivo', archivo);
+    const response = await this.api.post<ExcusaResponse>('/asistencias/excusas', formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  }
+
+  async getMisExcusas(): Promise<ExcusaResponse[]> {
+    const response = await this.api.get<{ data: ExcusaResponse[] }>('/asistencias/mis-excusas');
+    return response.data.data ?? [];
+  }
+
+  /** Excusas que el usuario puede revisar (instructor de la ficha, bienestar o superadmin). */
+  async getExcusasRevision(params?: { estado?: string; ficha_id?: number }): Promise<ExcusaResponse[]> {
+    const response = await this.api.get<{ data: ExcusaResponse[] }>('/asistencias/excusas', { params });
+    return response.data.data ?? [];
+  }
+
+  async revisarExcusa(id: number, data: ExcusaRevisionRequest): Promise<ExcusaResponse> {
+    const response = await this.api.put<ExcusaResponse>(`/asistencias/excusas/${id}/revision`, data);
+    return response.data;
+  }
+
+  async descargarSoporteExcusa(id: number): Promise<Blob> {
+    const response = await this.api.get<Blob>(`/asistencias/excusas/${id}/archivo`, {
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
   /** Registros de asistencia de aprendices pendientes de revisión para el instructor actual en una fecha (default hoy). */
   async getAsistenciaPendientesRevision(fecha?: string): Promise<AsistenciaAprendizResponse[]> {
     const response = await this.api.get<{ data: AsistenciaAprendizResponse[] }>('/asistencias/pendientes-revision', {
@@ -889,6 +1292,41 @@ class ApiService {
     return response.data.data;
   }
 
+  /** Consolidado aprendices x sesiones; rangos largos se generan en segundo plano (HTTP 202 con el trabajo) */
+  async solicitarReporteAsistenciaFicha(
+    fichaId: number,
+    fechaInicio: string,
+    fechaFin: string,
+    formato: ReporteAsistenciaFormato,
+  ): Promise<ReporteAsistenciaSolicitud> {
+    const response = await this.api.get<Blob>(`/asistencias/ficha/${fichaId}`, {
+      params: { fecha_inicio: fechaInicio, fecha_fin: fechaFin, formato },
+      responseType: 'blob',
+    });
+    if (response.status === 202) {
+      const body = JSON.parse(await response.data.text()) as { data: ReporteAsistenciaJob };
+      return { job: body.data };
+    }
+    return { archivo: response.data };
+  }
+
+  async getReportesAsistenciaFicha(fichaId: number): Promise<ReporteAsistenciaJob[]> {
+    const response = await this.api.get<{ data: ReporteAsistenciaJob[] }>(`/asistencias/ficha/${fichaId}/reportes`);
+    return response.data.data;
+  }
+
+  async getReporteAsistencia(fichaId: number, reporteId: number): Promise<ReporteAsistenciaJob> {
+    const response = await this.api.get<ReporteAsistenciaJob>(`/asistencias/ficha/${fichaId}/reportes/${reporteId}`);
+    return response.data;
+  }
+
+  async downloadReporteAsistencia(fichaId: number, reporteId: number): Promise<Blob> {
+    const response = await this.api.get<Blob>(`/asistencias/ficha/${fichaId}/reportes/${reporteId}/archivo`, {
+      responseType: 'blob',
+    });
+    return response.data;
+  }
+
   async getAsistenciaAprendices(asistenciaId: number): Promise<AsistenciaAprendizResponse[]> {
     const response = await this.api.get<{ data: AsistenciaAprendizResponse[] }>(`/asistencias/${asistenciaId}/aprendices`);
     return response.data.data;
@@ -910,6 +1348,19 @@ class ApiService {
     return response.data;
   }
 
+  async getCodigoQRAsistencia(asistenciaId: number): Promise<AsistenciaCodigoQRResponse> {
+    const response = await this.api.get<{ data: AsistenciaCodigoQRResponse }>(`/asistencias/${asistenciaId}/codigo-qr`);
+    return response.data.data;
+  }
+
+  /** Aprendiz: registra su ingreso o salida con el código QR que muestra el instructor. */
+  async autorregistrarAsistencia(codigo: string): Promise<AsistenciaAprendizResponse> {
+    const response = await this.api.post<AsistenciaAprendizResponse>('/asistencias/autorregistro', {
+      codigo: codigo.trim(),
+    });
+    return response.data;
+  }
+
   async registrarSalidaAsistencia(asistenciaAprendizId: number): Promise<AsistenciaAprendizResponse> {
     const response = await this.api.put<AsistenciaAprendizResponse>(`/asistencias/aprendiz/${asistenciaAprendizId}/salida`);
     return response.data;
@@ -1103,6 +1554,10 @@ class ApiService {
     await this.api.patch(`/usuarios/${userId}/estado`);
   }
 
+  async desbloquearLoginUsuario(userId: number): Promise<void> {
+    await this.api.post(`/usuarios/${userId}/desbloquear-login`);
+  }
+
   async getPermisosDefiniciones(): Promise<DefinicionesPermisosResponse> {
     const response = await this.api.get<DefinicionesPermisosResponse>('/permisos/definiciones');
     return response.data;
@@ -1177,6 +1632,40 @@ class ApiService {
     return response.data;
   }
 
+  async getEleccionProcesoEventos(procesoId: number): Promise<EleccionProcesoEvento[]> {
+    const response = await this.api.get<{ data: EleccionProcesoEvento[] }>(`/elecciones/procesos/${procesoId}/eventos`);
+    return response.data.data;
+  }
+
+  async getEleccionParticipacion(procesoId: number): Promise<EleccionParticipacion> {
+    const response = await this.api.get<{ data: EleccionParticipacion }>(`/elecciones/procesos/${procesoId}/participacion`);
+    return response.data.data;
+  }
+
+  async eleccionEnviarRecordatorioVoto(
+    procesoId: number,
+    canales: EleccionRecordatorioCanal[],
+  ): Promise<EleccionRecordatorioResponse> {
+    const response = await this.api.post<{ data: EleccionRecordatorioResponse }>(
+      `/elecciones/procesos/${procesoId}/recordatorio-voto`,
+      { canales },
+    );
+    return response.data.data;
+  }
+
+  async downloadEleccionActa(procesoId: number): Promise<Blob> {
+    const response = await this.api.get(`/elecciones/procesos/${procesoId}/acta`, { responseType: 'blob' });
+    return response.data;
+  }
+
+  /** Público: no requiere sesión. */
+  async verificarEleccionActa(hash: string): Promise<EleccionActaVerificacion> {
+    const response = await this.api.get<{ data: EleccionActaVerificacion }>(
+      `/elecciones/actas/${encodeURIComponent(hash)}`,
+    );
+    return response.data.data;
+  }
+
   async getEleccionMiRegional(): Promise<EleccionMiRegional> {
     const response = await this.api.get<{ data: EleccionMiRegional }>('/elecciones/mi-regional');
     return response.data.data;
@@ -1197,8 +1686,16 @@ class ApiService {
     return response.data.data;
   }
 
-  async registrarEleccionVoto(procesoId: number, data: EleccionVotoRequest): Promise<void> {
-    await this.api.post(`/elecciones/procesos/${procesoId}/voto`, data);
+  async registrarEleccionVoto(procesoId: number, data: EleccionVotoRequest): Promise<EleccionVotoResponse> {
+    const response = await this.api.post<{ data: EleccionVotoResponse }>(`/elecciones/procesos/${procesoId}/voto`, data);
+    return response.data.data;
+  }
+
+  async verificarEleccionRecibo(procesoId: number, codigo: string): Promise<EleccionRecibo> {
+    const response = await this.api.get<{ data: EleccionRecibo }>(
+      `/elecciones/procesos/${procesoId}/recibos/${encodeURIComponent(codigo)}`,
+    );
+    return response.data.data;
   }
 
   async getRepresentantesVigentes(regionalId: number): Promise<RepresentanteAprendiz | null> {
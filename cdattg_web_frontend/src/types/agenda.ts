@@ -9,9 +9,13 @@ export interface InstructorAgendaEvent {
   programa_nombre?: string;
   sede_nombre?: string;
   ambiente_nombre?: string;
+  jornada_nombre?: string;
   instructor_id?: number;
   instructor_nombre?: string;
   instructor_documento?: string;
+  /** Sesión en festivo o día sin formación de la sede */
+  cancelado?: boolean;
+  motivo_cancelacion?: string;
 }
 
 export interface InstructorAgendaResponse {
@@ -20,6 +24,15 @@ export interface InstructorAgendaResponse {
   eventos: InstructorAgendaEvent[];
 }
 
+/** Enlace secreto para suscribirse a la agenda (url y token solo al generarlo) */
+export interface AgendaSuscripcionResponse {
+  activa: boolean;
+  url?: string;
+  token?: string;
+  created_at?: string;
+  ultimo_uso_at?: string;
+}
+
 export interface FichaDiaFormacionItem {
   dia_formacion_id: number;
   dia_nombre?: string;
@@ -1,15 +1,28 @@
+/** Ámbito del ciclo: quiénes votan y a quién representa el elegido. */
+export type EleccionAmbito = 'regional' | 'sede' | 'ficha';
+
 export type EleccionProceso = {
   id: number;
   regional_id: number;
   regional_nombre?: string;
   anio: number;
   nombre_ciclo: string;
+  ambito: EleccionAmbito;
+  sede_id?: number;
+  sede_nombre?: string;
+  ficha_id?: number;
+  ficha_numero?: string;
+  cargo_titular: string;
+  cargo_suplente: string;
   estado: 'borrador' | 'inscripcion' | 'votacion' | 'empate_pendiente' | 'cerrada';
   fecha_inscripcion_inicio?: string;
   fecha_inscripcion_fin?: string;
   fecha_votacion_inicio?: string;
   fecha_votacion_fin?: string;
   min_dias_matricula?: number;
+  auto_transiciones: boolean;
+  /** Fin de votación más la gracia configurada: hasta entonces se aceptan votos. */
+  cierre_votacion_at?: string;
   planchas_confirmadas?: number;
   votos_registrados?: number;
   aprendices_elegibles?: number;
@@ -44,19 +57,26 @@ export type EleccionResultado = {
   participacion_pct: number;
   empate: boolean;
   nota_desempate?: string;
+  desempate_metodo?: EleccionDesempateMetodo;
+  desempate_at?: string;
+  /** SHA-256 del acta de escrutinio; presente cuando el proceso está cerrado. */
+  acta_hash?: string;
   conteo: { plancha_id: number; label: string; votos: number }[];
-  votos?: {
-    votante_nombre: string;
-    votante_documento?: string;
-    plancha_id: number;
-    plancha_label: string;
-    votado_at: string;
-  }[];
+  auditoria?: { participaciones: number; papeletas: number; consistente: boolean };
+  participantes?: { votante_nombre: string; votante_documento?: string; votado_at: string }[];
+  papeletas?: { recibo_hash: string; plancha_id: number; plancha_label: string }[];
 };
 
 export type RepresentanteAprendiz = {
   regional_id: number;
   regional_nombre?: string;
+  ambito: EleccionAmbito;
+  sede_id?: number;
+  sede_nombre?: string;
+  ficha_id?: number;
+  ficha_numero?: string;
+  cargo_titular?: string;
+  cargo_suplente?: string;
   proceso_id: number;
   nombre_ciclo?: string;
   anio?: number;
@@ -66,29 +86,52 @@ export type RepresentanteAprendiz = {
   vigencia_hasta?: string | null;
 };
 
-export type EleccionMiRegional = {
-  regional_id: number;
-  regional_nombre?: string;
+/** Un proceso en curso visto por el aprendiz. */
+export type EleccionMiProceso = {
   proceso?: EleccionProceso;
-  representantes_vigentes?: RepresentanteAprendiz;
-  mi_aprendiz_id?: number;
   puede_votar: boolean;
   puede_postular: boolean;
   ya_voto?: boolean;
   es_candidato?: boolean;
-  mi_voto_plancha_id?: number;
   planchas_pendientes_confirmar?: EleccionPlancha[];
 };
 
+/** Proceso regional en la raíz; los de la sede y la ficha del aprendiz en `otros_procesos`. */
+export type EleccionMiRegional = EleccionMiProceso & {
+  regional_id: number;
+  regional_nombre?: string;
+  representantes_vigentes?: RepresentanteAprendiz;
+  voceros_vigentes?: RepresentanteAprendiz;
+  mi_aprendiz_id?: number;
+  otros_procesos?: EleccionMiProceso[];
+};
+
 export type EleccionProcesoRequest = {
   regional_id: number;
   anio: number;
   nombre_ciclo: string;
+  ambito?: EleccionAmbito;
+  sede_id?: number | null;
+  ficha_id?: number | null;
+  cargo_titular?: string;
+  cargo_suplente?: string;
   fecha_inscripcion_inicio?: string | null;
   fecha_inscripcion_fin?: string | null;
   fecha_votacion_inicio?: string | null;
   fecha_votacion_fin?: string | null;
   min_dias_matricula?: number | null;
+  auto_transiciones?: boolean;
+};
+
+/** Cambio de fase del proceso; sin usuario lo hizo el planificador. */
+export type EleccionProcesoEvento = {
+  id: number;
+  estado_anterior: EleccionProceso['estado'];
+  estado_nuevo: EleccionProceso['estado'];
+  origen: 'manual' | 'automatico';
+  user_id?: number;
+  detalle?: string;
+  created_at: string;
 };
 
 export type EleccionPlanchaRequest = {
@@ -100,7 +143,75 @@ export type EleccionVotoRequest = {
   plancha_id: number;
 };
 
+export type EleccionVotoResponse = {
+  proceso_id: number;
+  plancha_id: number;
+  votante_nombre?: string;
+  codigo_recibo: string;
+  votado_at: string;
+};
+
+export type EleccionRecibo = {
+  proceso_id: number;
+  recibo_hash: string;
+  valido: boolean;
+};
+
+export type EleccionDesempateMetodo = 'sorteo' | 'comite' | 'acuerdo';
+
 export type EleccionDesempateRequest = {
   plancha_ganadora_id: number;
+  metodo: EleccionDesempateMetodo;
   nota_desempate: string;
 };
+
+/** Verificación pública de un acta de escrutinio por su hash. */
+export type EleccionActaVerificacion = {
+  hash: string;
+  proceso_id: number;
+  nombre_ciclo: string;
+  anio: number;
+  regional: string;
+  generada_at: string;
+  votos_totales: number;
+  plancha_ganadora?: string;
+  contenido_integro: boolean;
+  coincide_con_urna: boolean;
+  valida: boolean;
+};
+
+export type EleccionParticipacionGrupo = {
+  id: number;
+  nombre: string;
+  elegibles: number;
+  votaron: number;
+  participacion_pct: number;
+};
+
+/** Participación en vivo: solo quién votó, nunca por quién. */
+export type EleccionParticipacion = {
+  proceso_id: number;
+  estado_proceso: string;
+  elegibles: number;
+  votaron: number;
+  pendientes: number;
+  participacion_pct: number;
+  votantes_fuera_de_padron: number;
+  por_sede: EleccionParticipacionGrupo[];
+  por_programa: EleccionParticipacionGrupo[];
+  por_ficha: EleccionParticipacionGrupo[];
+  por_hora: { hora: string; votos: number; acumulado: number }[];
+  ultimo_recordatorio_at?: string;
+  actualizado_at: string;
+};
+
+export type EleccionRecordatorioCanal = 'correo' | 'notificacion';
+
+export type EleccionRecordatorioResponse = {
+  pendientes: number;
+  notificados: number;
+  correos: number;
+  sin_correo: number;
+  correo_habilitado: boolean;
+  enviado_at: string;
+};
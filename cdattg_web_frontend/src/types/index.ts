@@ -12,20 +12,114 @@ export interface ChangePasswordRequest {
   password_nueva: string;
 }
 
+export interface RestablecerPasswordRequest {
+  token: string;
+  password_nueva: string;
+}
+
 export interface LoginResponse {
   token: string;
+  refresh_token: string;
   type: string;
+  expires_in: number;
   user: UserResponse;
   roles: string[];
   permissions: string[];
 }
 
+export interface TokenResponse {
+  token: string;
+  refresh_token: string;
+  type: string;
+  expires_in: number;
+}
+
+export interface SesionResponse {
+  id: number;
+  user_agent: string;
+  ip: string;
+  created_at: string;
+  ultimo_uso_at: string;
+  expira_at: string;
+  actual: boolean;
+}
+
+export interface ResumenSemanalPreferenciaItem {
+  tipo: 'coordinador' | 'instructor';
+  nombre: string;
+  descripcion: string;
+  activo: boolean;
+}
+
+export interface ResumenesSemanalesResponse {
+  /** false si el correo no es entregable o el envío SMTP está deshabilitado. */
+  correo_disponible: boolean;
+  email: string;
+  preferencias: ResumenSemanalPreferenciaItem[];
+}
+
+export type NotificacionTipo =
+  | 'asignacion_ficha'
+  | 'traslado_dia'
+  | 'eleccion_plancha'
+  | 'eleccion_votacion'
+  | 'eleccion_empate'
+  | 'inasistencia'
+  | 'importacion';
+
+export interface NotificacionUsuario {
+  id: number;
+  tipo: NotificacionTipo;
+  titulo: string;
+  mensaje: string;
+  /** Ruta del frontend a la que lleva la notificación. */
+  enlace?: string;
+  leida_en?: string | null;
+  created_at: string;
+}
+
+export interface NotificacionesListResponse {
+  data: NotificacionUsuario[];
+  total: number;
+  page: number;
+  page_size: number;
+  no_leidas: number;
+}
+
+/** Mensaje del WebSocket de notificaciones. Sin `notificacion` en un evento "nueva": recargar la lista. */
+export interface NotificacionEvento {
+  type: 'nueva' | 'leidas';
+  notificacion?: NotificacionUsuario;
+  /** Leídas: ids marcados; vacío significa todas. */
+  ids?: number[];
+  no_leidas: number;
+}
+
+export interface NotificacionPreferenciaItem {
+  tipo: NotificacionTipo;
+  nombre: string;
+  descripcion: string;
+  activo: boolean;
+}
+
+export interface LoginAccesoResponse {
+  id: number;
+  fecha_login: string;
+  ip: string;
+  user_agent: string;
+  exitoso: boolean;
+  motivo?: string;
+}
+
 export interface UserResponse {
   id: number;
   email: string;
   full_name: string;
   status: boolean;
   persona_id?: number;
+  email_verificado?: boolean;
+  /** Mientras sea true el backend solo permite cambiar la contraseña (403 PASSWORD_CHANGE_REQUIRED). */
+  debe_cambiar_password?: boolean;
 }
 
 export interface PersonaRequest {
@@ -98,13 +192,6 @@ export interface PaginatedResponse<T> {
   page_size: number;
 }
 
-export interface PersonaImportResult {
-  processed_count: number;
-  duplicates_count: number;
-  error_count: number;
-  status: string;
-}
-
 export interface PersonaImportLogItem {
   id: number;
   filename: string;
@@ -116,22 +203,37 @@ export interface PersonaImportLogItem {
   created_at: string;
 }
 
-/** Progreso en tiempo real durante la importación por streaming */
-export interface PersonaImportProgress {
-  total: number;
-  current: number;
-  processed: number;
-  duplicates: number;
-  errors: number;
-  type?: 'progress' | 'done' | 'error' | 'result';
-}
+/** Importación de Excel procesada en segundo plano; el avance se sigue con /importaciones/:id/eventos. */
+export type ImportacionTipo = 'personas' | 'instructores' | 'ficha' | 'programas';
+export type ImportacionEstado = 'pendiente' | 'procesando' | 'completado' | 'fallido';
 
-// Resultado de importación masiva de instructores desde Excel
-export interface InstructorImportResult {
-  processed_count: number;
-  duplicates_count: number;
-  error_count: number;
-  status: string;
+export interface ImportacionTrabajo {
+  id: number;
+  tipo: ImportacionTipo;
+  estado: ImportacionEstado;
+  filename: string;
+  regional_id?: number;
+  /** Filas de datos o, en programas, códigos distintos. */
+  total_filas: number;
+  fila_actual: number;
+  porcentaje: number;
+  procesados: number;
+  duplicados: number;
+  errores: number;
+  actualizados: number;
+  creados: number;
+  redes_creadas: number;
+  ficha_id?: number;
+  ficha_creada: boolean;
+  incidencias: number;
+  intentos: number;
+  ultimo_error?: string;
+  /** Fallida: se puede reanudar desde la última fila guardada. */
+  reanudable: boolean;
+  iniciado_at?: string;
+  finalizado_at?: string;
+  created_at: string;
+  updated_at: string;
 }
 
 // Ítem del historial de importaciones de instructores
@@ -146,27 +248,6 @@ export interface InstructorImportLogItem {
   created_at: string;
 }
 
-// Resultado de importación de programas desde Excel catálogo
-export interface ProgramaImportResult {
-  processed_count: number;
-  duplicates_count: number;
-  error_count: number;
-  redes_created: number;
-  status: string;
-}
-
-// Resultado de importación de fichas (reporte aprendices Excel)
-export interface FichaImportResult {
-  processed_count: number;
-  updated_count: number;
-  created_count: number;
-  duplicates_count: number;
-  error_count: number;
-  ficha_created: boolean;
-  status: string;
-  incident_report_base64?: string;
-}
-
 // Programas de formación
 export interface ProgramaFormacionRequest {
   codigo: string;
@@ -246,6 +327,52 @@ export interface DiaSinFormacionSedeItem {
   created_at?: string;
 }
 
+export type CorreoSalienteEstado = 'pendiente' | 'enviado' | 'fallido';
+
+export interface CorreoSalienteItem {
+  id: number;
+  destinatarios: string[];
+  asunto: string;
+  plantilla: string;
+  estado: CorreoSalienteEstado;
+  intentos: number;
+  proximo_intento_at?: string;
+  ultimo_error?: string;
+  enviado_at?: string;
+  /** Lleva un enlace de un solo uso: el cuerpo no se muestra y no se puede reintentar. */
+  sensible: boolean;
+  created_at: string;
+}
+
+export interface CorreoSalienteAdjunto {
+  id: number;
+  nombre: string;
+  content_type: string;
+  tamano: number;
+}
+
+export interface CorreoSalienteDetalle extends CorreoSalienteItem {
+  cuerpo_html?: string;
+  cuerpo_texto?: string;
+  adjuntos: CorreoSalienteAdjunto[];
+}
+
+export interface CorreosSalientesResumen {
+  smtp_habilitado: boolean;
+  max_intentos: number;
+  pendientes: number;
+  enviados: number;
+  fallidos: number;
+}
+
+export interface CorreosSalientesListResponse {
+  data: CorreoSalienteItem[];
+  total: number;
+  page: number;
+  page_size: number;
+  resumen: CorreosSalientesResumen;
+}
+
 export interface ConfiguracionAsistenciaItem {
   plazo_edicion_observaciones_dias: number;
   intervalo_auto_cierre_minutos: number;
@@ -348,6 +475,30 @@ export interface AmbienteListItem {
   status: boolean;
 }
 
+export interface AmbienteOcupacionBloque {
+  ficha_id: number;
+  ficha_numero: string;
+  programa_nombre: string;
+  hora_inicio: string;
+  hora_fin: string;
+  conflicto: boolean;
+}
+
+export interface AmbienteOcupacionDia {
+  dia_formacion_id: number;
+  dia_nombre: string;
+  fecha: string;
+  bloques: AmbienteOcupacionBloque[];
+}
+
+export interface AmbienteOcupacionResponse {
+  ambiente_id: number;
+  semana_inicio: string;
+  semana_fin: string;
+  dias: AmbienteOcupacionDia[];
+  conflictos: number;
+}
+
 // Piso (infraestructura)
 export interface PisoCreateRequest {
   nombre: string;
@@ -558,6 +709,29 @@ export interface AsistenciaResponse {
   cantidad_aprendices?: number;
 }
 
+export type ReporteAsistenciaFormato = 'pdf' | 'xlsx';
+
+/** Reporte consolidado generado en segundo plano (rangos de más de 31 días) */
+export interface ReporteAsistenciaJob {
+  id: number;
+  ficha_id: number;
+  fecha_inicio: string;
+  fecha_fin: string;
+  formato: ReporteAsistenciaFormato;
+  estado: 'PENDIENTE' | 'PROCESANDO' | 'COMPLETADO' | 'ERROR';
+  nombre_archivo?: string;
+  error?: string;
+  sesiones: number;
+  solicitado_por: number;
+  created_at: string;
+  finalizado_at?: string;
+}
+
+/** Respuesta de la solicitud: archivo listo o trabajo en cola */
+export type ReporteAsistenciaSolicitud =
+  | { archivo: Blob; job?: undefined }
+  | { archivo?: undefined; job: ReporteAsistenciaJob };
+
 export interface AsistenciaAprendizRequest {
   asistencia_id: number;
   aprendiz_id: number;
@@ -589,16 +763,54 @@ export interface AsistenciaAprendizResponse {
   tipos_observacion?: TipoObservacionAsistenciaItem[];
 }
 
+export type AsistenciaEventoTipo =
+  | 'sesion_abierta'
+  | 'sesion_cerrada'
+  | 'ingreso'
+  | 'salida'
+  | 'estado_ajustado'
+  | 'registro_eliminado';
+
+/** Evento del canal de asistencia en tiempo real (filtrado en el servidor según el alcance del usuario). */
+export interface AsistenciaEvento {
+  type: AsistenciaEventoTipo;
+  asistencia_id: number;
+  instructor_ficha_id?: number;
+  ficha_id?: number;
+  ficha_numero?: string;
+  sede_id?: number;
+  regional_id?: number;
+  asistencia_aprendiz_id?: number;
+  aprendiz_id?: number;
+  at: string;
+  /** Solo en la lista en vivo de la sesión. */
+  registro?: AsistenciaAprendizResponse;
+}
+
+/** Código rotativo firmado que el instructor muestra para el autorregistro de aprendices. */
+export interface AsistenciaCodigoQRResponse {
+  asistencia_id: number;
+  codigo: string;
+  /** SVG del QR generado por el backend. */
+  svg: string;
+  periodo_segundos: number;
+  expira_at: string;
+  segundos_restantes: number;
+}
+
 export interface TipoObservacionAsistenciaItem {
   id: number;
   codigo: string;
   nombre: string;
+  /** Aporte (0-5) al puntaje de riesgo de deserción */
+  peso_riesgo?: number;
 }
 
 export interface TipoObservacionAsistenciaCreateRequest {
   codigo: string;
   nombre: string;
   activo?: boolean;
+  peso_riesgo?: number;
 }
 
 /** Respuesta del dashboard de asistencia (solo superadmin) */
@@ -674,6 +886,8 @@ export interface DashboardResumenResponse {
   riesgo: {
     casos_bienestar: number;
     pendientes_revision: number;
+    /** Aprendices con riesgo de deserción alto (últimos 30 días) */
+    riesgo_desercion_alto?: number;
   };
   alcance: {
     restricted: boolean;
@@ -775,6 +989,7 @@ export interface CasosBienestarResponse {
 }
 
 export interface InasistenciaDetalleItem {
+  asistencia_ids: number[];
   fecha: string;
   instructor_nombre?: string;
   observaciones?: string;
@@ -803,6 +1018,105 @@ export interface MisInasistenciasResponse {
   inasistencias_justificadas?: InasistenciaDetalleItem[];
 }
 
+export type ExcusaEstado = 'pendiente' | 'aprobada' | 'rechazada';
+
+export interface ExcusaSesionItem {
+  asistencia_id: number;
+  fecha: string;
+  instructor_nombre?: string;
+}
+
+export interface ExcusaResponse {
+  id: number;
+  aprendiz_id: number;
+  aprendiz_nombre: string;
+  numero_documento: string;
+  ficha_id: number;
+  ficha_numero: string;
+  motivo: string;
+  estado: ExcusaEstado;
+  archivo_nombre: string;
+  archivo_tipo: string;
+  archivo_tamano: number;
+  created_at: string;
+  revisado_at?: string;
+  observacion_revision?: string;
+  sesiones: ExcusaSesionItem[];
+}
+
+export interface ExcusaRevisionRequest {
+  estado: Exclude<ExcusaEstado, 'pendiente'>;
+  observacion?: string;
+}
+
+export type JuicioValor = 'APROBADO' | 'NO APROBADO' | 'PENDIENTE';
+
+export interface EvaluacionRapItem {
+  id: number;
+  codigo: string;
+  nombre: string;
+}
+
+export interface EvaluacionCompetenciaItem {
+  id: number;
+  codigo: string;
+  nombre: string;
+  raps: EvaluacionRapItem[];
+}
+
+export interface JuicioItem {
+  rap_id: number;
+  juicio: JuicioValor;
+  observaciones?: string;
+  fecha_juicio?: string;
+}
+
+export interface EvaluacionAprendizRow {
+  aprendiz_id: number;
+  aprendiz_nombre: string;
+  numero_documento: string;
+  juicios: JuicioItem[];
+  aprobados: number;
+  no_aprobados: number;
+  pendientes: number;
+}
+
+export interface EvaluacionFichaResponse {
+  ficha_id: number;
+  ficha_numero: string;
+  programa_nombre: string;
+  fecha_fin?: string;
+  dias_para_fin?: number;
+  alerta_cierre: boolean;
+  competencias: EvaluacionCompetenciaItem[];
+  aprendices: EvaluacionAprendizRow[];
+  resumen: {
+    total_raps: number;
+    total_aprendices: number;
+    aprobados: number;
+    no_aprobados: number;
+    pendientes: number;
+  };
+}
+
+export interface JuicioRegistroItem {
+  aprendiz_id: number;
+  rap_id: number;
+  juicio: JuicioValor;
+  observaciones?: string;
+}
+
+export interface EvaluacionAlertaItem {
+  ficha_id: number;
+  ficha_numero: string;
+  programa_nombre: string;
+  fecha_fin: string;
+  dias_para_fin: number;
+  aprendices: number;
+  raps: number;
+  pendientes: number;
+}
+
 export interface SesionSinAsistenciaTomadaItem {
   asistencia_id: number;
   ficha_numero: string;
@@ -842,6 +1156,150 @@ export interface CasoBienestarItem {
   /** Inasistencias sin justificar (umbral de alerta) */
   inasistencias: number;
   inasistencias_justificadas?: number;
+  /** Caso de seguimiento sin cerrar del aprendiz, si bienestar ya lo abrió */
+  caso_id?: number;
+  caso_estado?: CasoBienestarEstado;
+}
+
+export type CasoBienestarEstado = 'abierto' | 'en_seguimiento' | 'cerrado';
+
+export type CasoBienestarIntervencionTipo = 'llamada' | 'reunion' | 'correo' | 'visita' | 'remision' | 'otro';
+
+export interface CasoBienestar {
+  id: number;
+  aprendiz_id: number;
+  persona_id: number;
+  aprendiz_nombre: string;
+  numero_documento: string;
+  ficha_id: number;
+  ficha_numero: string;
+  origen: 'calculo' | 'manual';
+  estado: CasoBienestarEstado;
+  motivo: string;
+  responsable_user_id?: number;
+  responsable_nombre?: string;
+  periodo_inicio?: string;
+  periodo_fin?: string;
+  inasistencias: number;
+  inasistencias_justificadas: number;
+  total_sesiones: number;
+  created_at: string;
+  updated_at: string;
+  cerrado_at?: string;
+  observacion_cierre?: string;
+}
+
+export interface CasoBienestarIntervencion {
+  id: number;
+  fecha: string;
+  tipo: CasoBienestarIntervencionTipo;
+  descripcion: string;
+  acuerdos?: string;
+  autor_user_id: number;
+  autor_nombre?: string;
+  created_at: string;
+}
+
+export interface CasoBienestarAdjunto {
+  id: number;
+  archivo_nombre: string;
+  archivo_tipo: string;
+  archivo_tamano: number;
+  created_at: string;
+}
+
+export interface CasoBienestarRecordatorio {
+  id: number;
+  caso_id: number;
+  fecha_recordatorio: string;
+  nota: string;
+  completado_at?: string;
+  notificado_at?: string;
+  aprendiz_nombre?: string;
+  ficha_numero?: string;
+}
+
+export interface CasoBienestarDetalle extends CasoBienestar {
+  intervenciones: CasoBienestarIntervencion[];
+  adjuntos: CasoBienestarAdjunto[];
+  recordatorios: CasoBienestarRecordatorio[];
+}
+
+export interface CasosBienestarAbrirRequest {
+  aprendiz_ids: number[];
+  dias?: number;
+  min_fallas?: number;
+  sede_id?: number;
+  responsable_user_id?: number;
+}
+
+export interface CasosBienestarAbrirResponse {
+  creados: CasoBienestar[];
+  omitidos: { aprendiz_id: number; motivo: string; caso_id?: number }[];
+}
+
+export interface CasoBienestarUpdateRequest {
+  estado: CasoBienestarEstado;
+  responsable_user_id?: number;
+  observacion_cierre?: string;
+}
+
+export interface CasoBienestarIntervencionRequest {
+  fecha: string;
+  tipo: CasoBienestarIntervencionTipo;
+  descripcion: string;
+  acuerdos?: string;
+}
+
+export interface ProfesionalBienestarItem {
+  user_id: number;
+  nombre: string;
+  email: string;
+}
+
+export type NivelRiesgoDesercion = 'bajo' | 'medio' | 'alto';
+
+export interface RiesgoDesercionItem {
+  aprendiz_id: number;
+  persona_nombre: string;
+  numero_documento: string;
+  ficha_id: number;
+  ficha_numero: string;
+  programa_nombre?: string;
+  sede_nombre: string;
+  jornada_nombre?: string;
+  puntaje: number;
+  nivel: NivelRiesgoDesercion;
+  factores: {
+    total_sesiones: number;
+    inasistencias: number;
+    inasistencias_justificadas: number;
+    pct_inasistencia: number;
+    pct_inasistencia_anterior?: number;
+    fallas_consecutivas: number;
+    retardos: number;
+    salidas_parciales: number;
+    peso_observaciones: number;
+    raps_no_aprobados: number;
+  };
+  aportes: {
+    inasistencia: number;
+    fallas_consecutivas: number;
+    tendencia: number;
+    retardos: number;
+    salidas_parciales: number;
+    observaciones: number;
+    raps_no_aprobados: number;
+  };
+}
+
+export interface RiesgoDesercionResponse {
+  dias_analizados: number;
+  fecha_inicio: string;
+  fecha_fin: string;
+  fecha_inicio_anterior: string;
+  fecha_fin_anterior: string;
+  aprendices: RiesgoDesercionItem[];
 }
 
 export interface AsistenciaDashboardPorFicha {
@@ -0,0 +1,21 @@
+import type { EleccionAmbito, EleccionProceso } from '../types/eleccion';
+
+export const AMBITO_LABEL: Record<EleccionAmbito, string> = {
+  regional: 'Regional',
+  sede: 'Sede',
+  ficha: 'Ficha',
+};
+
+/** Cargos que usa el servidor cuando el ciclo no los configura. */
+export const CARGOS_POR_AMBITO: Record<EleccionAmbito, [string, string]> = {
+  regional: ['Representante', 'Suplente'],
+  sede: ['Representante de sede', 'Suplente'],
+  ficha: ['Vocero', 'Vocero suplente'],
+};
+
+/** Sede o ficha del ciclo para listados y encabezados; los regionales muestran solo "Regional". */
+export function ambitoProcesoTexto(p: Pick<EleccionProceso, 'ambito' | 'sede_id' | 'sede_nombre' | 'ficha_id' | 'ficha_numero'>): string {
+  if (p.ambito === 'ficha') return `Ficha ${p.ficha_numero ?? p.ficha_id}${p.sede_nombre ? ` · ${p.sede_nombre}` : ''}`;
+  if (p.ambito === 'sede') return `Sede ${p.sede_nombre ?? p.sede_id}`;
+  return AMBITO_LABEL.regional;
+}
@@ -31,7 +31,7 @@ var (
 	PermisosFicha = []string{
 		"VER FICHAS", "VER FICHA", "CREAR FICHA", "EDITAR FICHA", "ELIMINAR FICHA",
 		"GESTIONAR INSTRUCTORES FICHA", "GESTIONAR APRENDICES FICHA",
-		"PROGRAMAR INSTRUCTORES",
+		"PROGRAMAR INSTRUCTORES", "VER EVALUACION", "EVALUAR APRENDICES",
 	}
 	PermisosAprendiz = []string{
 		"VER APRENDICES", "VER APRENDIZ", "CREAR APRENDIZ", "EDITAR APRENDIZ", "ELIMINAR APRENDIZ",
@@ -40,7 +40,7 @@ var (
 		"VER INSTRUCTORES", "CREAR INSTRUCTOR", "EDITAR INSTRUCTOR", "ELIMINAR INSTRUCTOR",
 	}
 	PermisosAsistencia = []string{
-		"VER ASISTENCIA", "TOMAR ASISTENCIA", "VER MI AGENDA", "VER MIS INASISTENCIAS",
+		"VER ASISTENCIA", "TOMAR ASISTENCIA", "VER MI AGENDA", "VER MIS INASISTENCIAS", "REGISTRAR MI ASISTENCIA",
 	}
 	PermisosEleccion = []string{
 		"GESTIONAR ELECCION", "VER ELECCION", "VOTAR ELECCION", "VER RESULTADOS ELECCION",
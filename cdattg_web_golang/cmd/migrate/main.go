@@ -0,0 +1,177 @@
+// Programa para aplicar y revisar las migraciones de esquema (database/migrations).
+// Uso:
+//
+//	go run ./cmd/migrate up [-hasta VERSION] [-dry-run] [-fuera-de-orden]
+//	go run ./cmd/migrate down [-pasos N] [-dry-run]
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate verify
+//	go run ./cmd/migrate create [-dir database/migrations/sql] NOMBRE
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/database/migrations"
+	"github.com/sena/cdattg-web-golang/database/migrator"
+	"github.com/sena/cdattg-web-golang/utils"
+	"gorm.io/gorm"
+)
+
+const uso = `uso: migrate <comando> [opciones]
+
+comandos:
+  up      aplica las migraciones pendientes
+  down    revierte las últimas migraciones aplicadas
+  status  lista las migraciones y si están aplicadas
+  verify  falla si hay pendientes o alguna aplicada cambió
+  create  crea los archivos .up.sql y .down.sql de una migración nueva
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, uso)
+		os.Exit(2)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	var err error
+	switch cmd {
+	case "up":
+		err = up(args)
+	case "down":
+		err = down(args)
+	case "status":
+		err = status()
+	case "verify":
+		err = verify()
+	case "create":
+		err = create(args)
+	default:
+		fmt.Fprint(os.Stderr, uso)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("migrate %s: %v", cmd, err)
+	}
+}
+
+func conectar() *gorm.DB {
+	config.LoadConfig()
+	utils.InitAppLocation()
+	if err := database.Initialize(); err != nil {
+		log.Fatal("Error inicializando base de datos:", err)
+	}
+	return database.GetDB()
+}
+
+func migrador() *migrator.Migrador {
+	m, err := migrations.NewMigrador(conectar())
+	if err != nil {
+		log.Fatal("Error leyendo migraciones:", err)
+	}
+	return m
+}
+
+func up(args []string) error {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	hasta := fs.Int64("hasta", 0, "última versión a aplicar (0 = todas)")
+	dryRun := fs.Bool("dry-run", false, "solo lista lo que se aplicaría")
+	fueraDeOrden := fs.Bool("fuera-de-orden", false, "permite aplicar pendientes anteriores a la última aplicada")
+	_ = fs.Parse(args)
+
+	hechas, err := migrador().Up(migrator.OpcionesUp{Hasta: *hasta, DryRun: *dryRun, FueraDeOrden: *fueraDeOrden})
+	informar(hechas, *dryRun, "aplicaría", "aplicadas")
+	return err
+}
+
+func down(args []string) error {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	pasos := fs.Int("pasos", 1, "cantidad de migraciones a revertir")
+	dryRun := fs.Bool("dry-run", false, "solo lista lo que se revertiría")
+	_ = fs.Parse(args)
+
+	hechas, err := migrador().Down(migrator.OpcionesDown{Pasos: *pasos, DryRun: *dryRun})
+	informar(hechas, *dryRun, "revertiría", "revertidas")
+	return err
+}
+
+func informar(hechas []migrator.Migracion, dryRun bool, condicional, participio string) {
+	if dryRun {
+		for _, m := range hechas {
+			fmt.Printf("se %s %s\n", condicional, m)
+		}
+		if len(hechas) == 0 {
+			fmt.Println("nada que hacer")
+		}
+		return
+	}
+	fmt.Printf("%d migraciones %s\n", len(hechas), participio)
+}
+
+func status() error {
+	estados, err := migrador().Estado()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSIÓN\tNOMBRE\tESTADO\tAPLICADA")
+	for _, e := range estados {
+		estado, aplicada := "pendiente", ""
+		if e.Aplicada != nil {
+			estado = "aplicada"
+			aplicada = e.Aplicada.AplicadaAt.In(utils.AppLocation()).Format("2006-01-02 15:04:05")
+		}
+		switch {
+		case e.Huerfana:
+			estado = "HUÉRFANA"
+		case e.Modificada:
+			estado = "MODIFICADA"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.Version, e.Nombre, estado, aplicada)
+	}
+	return w.Flush()
+}
+
+func verify() error {
+	if err := migrations.VerificarAlDia(conectar()); err != nil {
+		return err
+	}
+	fmt.Println("esquema al día")
+	return nil
+}
+
+var nombreMigracion = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func create(args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	dir := fs.String("dir", filepath.Join("database", "migrations", "sql"), "directorio de las migraciones SQL")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 || !nombreMigracion.MatchString(fs.Arg(0)) {
+		return fmt.Errorf("indique un nombre en minúsculas con guiones bajos, p. ej. agregar_columna_x")
+	}
+	base := fmt.Sprintf("%s_%s", utils.Now().UTC().Format("20060102150405"), fs.Arg(0))
+	for _, sentido := range []string{"up", "down"} {
+		ruta := filepath.Join(*dir, base+"."+sentido+".sql")
+		f, err := os.OpenFile(ruta, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(f, "-- %s (%s)\n", fs.Arg(0), sentido)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(ruta)
+	}
+	return nil
+}
@@ -16,7 +16,11 @@ type Config struct {
 	Negocio    NegocioConfig
 	Inventario InventarioConfig
 	SMTP       SMTPConfig
+	Cuentas    CuentasConfig
 	Alertas    AlertasConfig
+	Scheduler  SchedulerConfig
+	Eventos    EventosConfig
+	Elecciones EleccionesConfig
 	Env        string
 }
 
@@ -28,6 +32,17 @@ type SMTPConfig struct {
 	Password string
 	From     string
 	Enabled  bool
+	// MaxIntentos intentos de envío de cada correo de la cola antes de marcarlo fallido.
+	MaxIntentos int
+	// EnviarReporteSesion envía al instructor el PDF de la sesión de asistencia al finalizarla.
+	EnviarReporteSesion bool
+}
+
+// CuentasConfig enlaces de un solo uso enviados por correo (recuperar contraseña, verificar correo).
+type CuentasConfig struct {
+	FrontendURL              string // URL pública del frontend con la que se arman los enlaces
+	RecuperacionTokenMinutos int    // vigencia del enlace para restablecer la contraseña
+	VerificacionTokenHoras   int    // vigencia del enlace para verificar el correo
 }
 
 // AlertasConfig reglas para alertas de asistencia (fichas que no han iniciado toma de asistencia).
@@ -36,6 +51,24 @@ type AlertasConfig struct {
 	Enabled                    bool // Si las alertas por correo están activas
 }
 
+// SchedulerConfig planificador de tareas en segundo plano (auto-cierre de asistencia, alertas, etc.).
+type SchedulerConfig struct {
+	Enabled                    bool   // Si false, las tareas se registran (y pueden dispararse manualmente) pero no se programan
+	AlertaAsistenciaCron       string // Expresión cron de la alerta de fichas sin sesión de asistencia
+	ResumenSemanalCron         string // Expresión cron del envío de resúmenes semanales a coordinadores e instructores
+	EleccionesTransicionesCron string // Expresión cron de las transiciones automáticas de fase de los procesos electorales
+}
+
+// EventosConfig difusión de eventos de asistencia en tiempo real entre réplicas de la API.
+type EventosConfig struct {
+	Backend string // "memoria" (solo esta réplica) o "postgres" (LISTEN/NOTIFY en la misma base de datos)
+}
+
+// EleccionesConfig transiciones automáticas de fase de los procesos electorales.
+type EleccionesConfig struct {
+	GraciaMinutos int // minutos que se aceptan votos después de fecha_votacion_fin antes del cierre automático
+}
+
 // InventarioConfig según documentacion_inventario.md (umbrales, notificaciones)
 type InventarioConfig struct {
 	UmbralMinimo       int  // bajo este valor el nivel es "bajo"
@@ -53,6 +86,7 @@ type NegocioConfig struct {
 	IgnorarVigenciaFicha          bool // Si true, no filtra ni valida por fecha_inicio/fecha_fin de fichas_caracterizacion (datos desactualizados en BD)
 	RelaxarRestriccionAsistencia  bool // Modo transitorio: instructor asignado puede tomar asistencia sin restricción de día/horario (festivos y PARO sede se respetan)
 	RelaxarColisionHorarioInstructor bool // Modo transitorio: omitir validación de solapamiento día/horario entre fichas al programar instructores
+	RelaxarColisionAmbiente          bool // Modo transitorio: permitir que dos fichas compartan ambiente en bloques que se solapan
 }
 
 type DatabaseConfig struct {
@@ -68,11 +102,14 @@ type DatabaseConfig struct {
 type ServerConfig struct {
 	Port string
 	Host string
+	// TrustedProxies IPs o CIDR de los proxies cuyo X-Forwarded-For se acepta; vacío = ninguno (se usa la IP de la conexión).
+	TrustedProxies []string
 }
 
 type JWTConfig struct {
-	Secret         string
-	ExpirationHours int
+	Secret             string
+	AccessTokenMinutes int // vida del access token (JWT)
+	RefreshTokenDays   int // vida del refresh token de cada sesión; se renueva al rotar
 }
 
 type CORSConfig struct {
@@ -101,12 +138,14 @@ func LoadConfig() {
 			TimeZone: getEnv("DB_TIMEZONE", "America/Bogota"),
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			Host:           getEnv("SERVER_HOST", "0.0.0.0"),
+			TrustedProxies: getEnvAsSlice("SERVER_TRUSTED_PROXIES", nil),
 		},
 		JWT: JWTConfig{
-			Secret:         getEnv("JWT_SECRET", "cdattg-web-golang-secret-key-change-in-production"),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			Secret:             getEnv("JWT_SECRET", "cdattg-web-golang-secret-key-change-in-production"),
+			AccessTokenMinutes: getEnvAsInt("JWT_ACCESS_TOKEN_MINUTES", 15),
+			RefreshTokenDays:   getEnvAsInt("JWT_REFRESH_TOKEN_DAYS", 30),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:8000", "http://localhost:3000", "http://localhost:5173"}),
@@ -123,6 +162,7 @@ func LoadConfig() {
 			IgnorarVigenciaFicha:          getEnvAsBool("NEGOCIO_IGNORAR_VIGENCIA_FICHA", true),
 			RelaxarRestriccionAsistencia:     getEnvAsBool("NEGOCIO_RELAXAR_RESTRICCION_ASISTENCIA", false),
 			RelaxarColisionHorarioInstructor: getEnvAsBool("NEGOCIO_RELAXAR_COLISION_HORARIO_INSTRUCTOR", false),
+			RelaxarColisionAmbiente:          getEnvAsBool("NEGOCIO_RELAXAR_COLISION_AMBIENTE", false),
 		},
 		Inventario: InventarioConfig{
 			UmbralMinimo:       getEnvAsInt("INVENTARIO_UMBRAL_MINIMO", 10),
@@ -130,17 +170,36 @@ func LoadConfig() {
 			NotificarStockBajo: getEnvAsBool("INVENTARIO_NOTIFICAR_STOCK_BAJO", true),
 		},
 		SMTP: SMTPConfig{
-			Host:     getEnv("SMTP_HOST", ""),
-			Port:     getEnvAsInt("SMTP_PORT", 587),
-			User:     getEnv("SMTP_USER", ""),
-			Password: getEnv("SMTP_PASSWORD", ""),
-			From:     getEnv("SMTP_FROM", "noreply@sena.local"),
-			Enabled:  getEnvAsBool("SMTP_ENABLED", false),
+			Host:                getEnv("SMTP_HOST", ""),
+			Port:                getEnvAsInt("SMTP_PORT", 587),
+			User:                getEnv("SMTP_USER", ""),
+			Password:            getEnv("SMTP_PASSWORD", ""),
+			From:                getEnv("SMTP_FROM", "noreply@sena.local"),
+			Enabled:             getEnvAsBool("SMTP_ENABLED", false),
+			MaxIntentos:         getEnvAsInt("SMTP_MAX_INTENTOS", 6),
+			EnviarReporteSesion: getEnvAsBool("SMTP_ENVIAR_REPORTE_SESION", true),
+		},
+		Cuentas: CuentasConfig{
+			FrontendURL:              getEnv("FRONTEND_URL", "http://localhost:5173"),
+			RecuperacionTokenMinutos: getEnvAsInt("RECUPERACION_TOKEN_MINUTOS", 30),
+			VerificacionTokenHoras:   getEnvAsInt("VERIFICACION_EMAIL_TOKEN_HORAS", 48),
 		},
 		Alertas: AlertasConfig{
 			MinutosDespuesInicioJornada: getEnvAsInt("ALERTAS_MINUTOS_DESPUES_INICIO_JORNADA", 90),
 			Enabled:                     getEnvAsBool("ALERTAS_ASISTENCIA_ENABLED", true),
 		},
+		Scheduler: SchedulerConfig{
+			Enabled:                    getEnvAsBool("SCHEDULER_ENABLED", true),
+			AlertaAsistenciaCron:       getEnv("SCHEDULER_ALERTA_ASISTENCIA_CRON", alertaAsistenciaCronPorDefecto),
+			ResumenSemanalCron:         getEnv("SCHEDULER_RESUMEN_SEMANAL_CRON", resumenSemanalCronPorDefecto),
+			EleccionesTransicionesCron: getEnv("SCHEDULER_ELECCIONES_TRANSICIONES_CRON", eleccionesTransicionesCronPorDefecto),
+		},
+		Eventos: EventosConfig{
+			Backend: getEnv("ASISTENCIA_EVENTOS_BACKEND", "memoria"),
+		},
+		Elecciones: EleccionesConfig{
+			GraciaMinutos: getEnvAsInt("ELECCION_GRACIA_MINUTOS", 5),
+		},
 		Env: getEnv("ENV", "development"),
 	}
 }
@@ -7,3 +7,11 @@ func RelaxarColisionHorarioInstructor() bool {
 	}
 	return AppConfig.Negocio.RelaxarColisionHorarioInstructor
 }
+
+// RelaxarColisionAmbiente omite la validación de ambiente ocupado por otra ficha en el mismo día/horario.
+func RelaxarColisionAmbiente() bool {
+	if AppConfig == nil {
+		return false
+	}
+	return AppConfig.Negocio.RelaxarColisionAmbiente
+}
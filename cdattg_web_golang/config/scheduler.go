@@ -0,0 +1,21 @@
+package config
+
+// Expresiones cron por defecto de las tareas programadas configurables (SCHEDULER_*_CRON).
+const (
+	alertaAsistenciaCronPorDefecto       = "*/10 * * * *"
+	resumenSemanalCronPorDefecto         = "0 7 * * 1"
+	eleccionesTransicionesCronPorDefecto = "* * * * *"
+)
+
+// SchedulerActual configuración del planificador; sin configuración cargada (p. ej. tests), los valores por defecto.
+func SchedulerActual() SchedulerConfig {
+	if AppConfig == nil {
+		return SchedulerConfig{
+			Enabled:                    true,
+			AlertaAsistenciaCron:       alertaAsistenciaCronPorDefecto,
+			ResumenSemanalCron:         resumenSemanalCronPorDefecto,
+			EleccionesTransicionesCron: eleccionesTransicionesCronPorDefecto,
+		}
+	}
+	return AppConfig.Scheduler
+}
@@ -0,0 +1,16 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// codigoUniqueViolation SQLSTATE de Postgres para una violación de índice único.
+const codigoUniqueViolation = "23505"
+
+// IsUniqueViolation indica si err proviene de una violación de índice único en Postgres.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == codigoUniqueViolation
+}
@@ -0,0 +1,8 @@
+package migrations
+
+import "github.com/sena/cdattg-web-golang/database/migrator"
+
+// migracionesGo migraciones que necesitan código (datos calculados). Lo que se pueda expresar en SQL va en sql/.
+// Cada una lleva en Fuente su propio archivo embebido (//go:embed 20261020153000_nombre.go) para que editar el
+// código después de aplicarla cambie el checksum.
+var migracionesGo = []migrator.Migracion{}
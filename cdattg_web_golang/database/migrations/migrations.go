@@ -0,0 +1,51 @@
+// Package migrations reúne las migraciones de esquema de la aplicación: los archivos de sql/ (embebidos en el
+// binario) y las migraciones en Go. Se aplican con cmd/migrate; la API solo verifica al iniciar
+// que no haya pendientes.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/sena/cdattg-web-golang/database/migrator"
+	"gorm.io/gorm"
+)
+
+//go:embed sql
+var archivosSQL embed.FS
+
+// Todas migraciones SQL y Go, en cualquier orden (el migrador las ordena por versión).
+func Todas() ([]migrator.Migracion, error) {
+	sqls, err := migrator.LeerSQL(archivosSQL, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return append(sqls, migracionesGo...), nil
+}
+
+// NewMigrador migrador con todas las migraciones de la aplicación.
+func NewMigrador(db *gorm.DB) (*migrator.Migrador, error) {
+	todas, err := Todas()
+	if err != nil {
+		return nil, err
+	}
+	return migrator.New(db, todas)
+}
+
+// VerificarAlDia falla si hay migraciones pendientes o alguna aplicada no coincide con el código. La API lo usa
+// al iniciar en lugar de modificar el esquema.
+func VerificarAlDia(db *gorm.DB) error {
+	m, err := NewMigrador(db)
+	if err != nil {
+		return err
+	}
+	pendientes, err := m.Pendientes()
+	if err != nil {
+		return err
+	}
+	if len(pendientes) > 0 {
+		return fmt.Errorf("%d migraciones pendientes (la primera es %s): ejecute `migrate up` antes de iniciar la API",
+			len(pendientes), pendientes[0])
+	}
+	return nil
+}
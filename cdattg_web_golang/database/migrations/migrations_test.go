@@ -0,0 +1,10 @@
+package migrations
+
+import "testing"
+
+// Un archivo mal nombrado o una versión repetida en sql/ debe fallar aquí y no al desplegar.
+func TestTodas_formatoYVersionesUnicas(t *testing.T) {
+	if _, err := NewMigrador(nil); err != nil {
+		t.Fatal(err)
+	}
+}
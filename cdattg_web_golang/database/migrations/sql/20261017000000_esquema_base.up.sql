@@ -0,0 +1,356 @@
+-- Esquema base: foto del esquema al introducir las migraciones versionadas, es decir, lo que antes creaban al
+-- iniciar la API el AutoMigrate de los modelos y los parches de esquema. Es idempotente (IF NOT EXISTS), así que en
+-- una base creada por versiones anteriores solo completa lo que falte. Está congelada: no se regenera desde los
+-- modelos ni se edita; los cambios van en migraciones nuevas. No tiene reversión: para volver atrás se restaura un
+-- respaldo.
+
+-- Roles y permisos viven solo en Casbin: se eliminan las tablas heredadas de Spatie.
+DROP TABLE IF EXISTS model_has_roles CASCADE;
+DROP TABLE IF EXISTS model_has_permissions CASCADE;
+DROP TABLE IF EXISTS role_has_permissions CASCADE;
+DROP TABLE IF EXISTS roles CASCADE;
+DROP TABLE IF EXISTS permissions CASCADE;
+
+-- Tablas e índices. Las columnas agregadas después de crear una tabla se completan con ADD COLUMN IF NOT EXISTS.
+CREATE TABLE IF NOT EXISTS "generos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_generos_deleted_at" ON "generos" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_caracterizaciones" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_persona_caracterizaciones_deleted_at" ON "persona_caracterizaciones" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "paises" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,"codigo" varchar(10),PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_paises_deleted_at" ON "paises" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "departamentos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,"pais_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_paises_departamentos" FOREIGN KEY ("pais_id") REFERENCES "paises"("id"));
+CREATE INDEX IF NOT EXISTS "idx_departamentos_deleted_at" ON "departamentos" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "municipios" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,"departamento_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_departamentos_municipios" FOREIGN KEY ("departamento_id") REFERENCES "departamentos"("id"));
+CREATE INDEX IF NOT EXISTS "idx_municipios_deleted_at" ON "municipios" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "tipos_documento" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_tipos_documento_deleted_at" ON "tipos_documento" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "personas" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"tipo_documento" bigint,"numero_documento" varchar(20),"primer_nombre" varchar(100),"segundo_nombre" varchar(100),"primer_apellido" varchar(100),"segundo_apellido" varchar(100),"fecha_nacimiento" timestamptz,"genero" bigint,"telefono" varchar(20),"celular" varchar(20),"email" varchar(100),"pais_id" bigint,"departamento_id" bigint,"municipio_id" bigint,"direccion" varchar(255),"status" boolean DEFAULT true,"estado_sofia" text,"condocumento" boolean,"parametro_id" bigint,"nivel_escolaridad_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_personas_tipo_documento" FOREIGN KEY ("tipo_documento") REFERENCES "tipos_documento"("id"),CONSTRAINT "fk_personas_genero" FOREIGN KEY ("genero") REFERENCES "generos"("id"),CONSTRAINT "fk_personas_persona_caracterizacion" FOREIGN KEY ("parametro_id") REFERENCES "persona_caracterizaciones"("id"),CONSTRAINT "fk_municipios_personas" FOREIGN KEY ("municipio_id") REFERENCES "municipios"("id"),CONSTRAINT "fk_departamentos_personas" FOREIGN KEY ("departamento_id") REFERENCES "departamentos"("id"),CONSTRAINT "fk_paises_personas" FOREIGN KEY ("pais_id") REFERENCES "paises"("id"));
+CREATE INDEX IF NOT EXISTS "idx_personas_numero_documento" ON "personas" ("numero_documento");
+CREATE INDEX IF NOT EXISTS "idx_personas_deleted_at" ON "personas" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "users" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"email" text NOT NULL,"password" text NOT NULL,"status" boolean DEFAULT true,"persona_id" bigint,"email_verified_at" timestamptz,"debe_cambiar_password" boolean NOT NULL DEFAULT false,PRIMARY KEY ("id"),CONSTRAINT "fk_personas_user" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"));
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "debe_cambiar_password" boolean NOT NULL DEFAULT false;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_import_logs" ("id" bigserial,"filename" varchar(255) NOT NULL,"user_id" bigint NOT NULL,"processed_count" bigint DEFAULT 0,"duplicates_count" bigint DEFAULT 0,"error_count" bigint DEFAULT 0,"status" varchar(50) DEFAULT 'completado',"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_persona_import_logs_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE TABLE IF NOT EXISTS "instructor_import_logs" ("id" bigserial,"filename" varchar(255) NOT NULL,"user_id" bigint NOT NULL,"processed_count" bigint DEFAULT 0,"duplicates_count" bigint DEFAULT 0,"error_count" bigint DEFAULT 0,"status" varchar(50) DEFAULT 'completado',"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_instructor_import_logs_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE TABLE IF NOT EXISTS "regionals" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,"codigo" varchar(50),PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_regionals_deleted_at" ON "regionals" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "sedes" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"nombre" varchar(100) NOT NULL,"direccion" varchar(255),"municipio_id" bigint,"regional_id" bigint,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_regionals_sedes" FOREIGN KEY ("regional_id") REFERENCES "regionals"("id"),CONSTRAINT "fk_municipios_sedes" FOREIGN KEY ("municipio_id") REFERENCES "municipios"("id"));
+CREATE INDEX IF NOT EXISTS "idx_sedes_deleted_at" ON "sedes" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "bloques" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"nombre" varchar(100) NOT NULL,"sede_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_sedes_bloques" FOREIGN KEY ("sede_id") REFERENCES "sedes"("id"));
+CREATE INDEX IF NOT EXISTS "idx_bloques_deleted_at" ON "bloques" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "pisos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"nombre" varchar(100) NOT NULL,"bloque_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_bloques_pisos" FOREIGN KEY ("bloque_id") REFERENCES "bloques"("id"));
+CREATE INDEX IF NOT EXISTS "idx_pisos_deleted_at" ON "pisos" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "ambientes" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"nombre" varchar(100) NOT NULL,"piso_id" bigint NOT NULL,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_pisos_ambientes" FOREIGN KEY ("piso_id") REFERENCES "pisos"("id"));
+CREATE INDEX IF NOT EXISTS "idx_ambientes_deleted_at" ON "ambientes" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "centros_formacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"codigo" varchar(50),"regional_id" bigint,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_centros_formacion_regional" FOREIGN KEY ("regional_id") REFERENCES "regionals"("id"));
+CREATE INDEX IF NOT EXISTS "idx_centros_formacion_deleted_at" ON "centros_formacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "tipos_programa" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"codigo" varchar(50),"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_tipos_programa_deleted_at" ON "tipos_programa" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "niveles_formacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"codigo" varchar(50),"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_niveles_formacion_deleted_at" ON "niveles_formacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "modalidades_formacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"codigo" varchar(50),"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_modalidades_formacion_deleted_at" ON "modalidades_formacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "programas" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"codigo" varchar(50),"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_programas_deleted_at" ON "programas" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "redes_conocimiento" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"regionals_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_redes_conocimiento_deleted_at" ON "redes_conocimiento" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "programas_formacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"codigo" varchar(50) NOT NULL,"nombre" varchar(255) NOT NULL,"red_conocimiento_id" bigint,"nivel_formacion_id" bigint,"status" boolean DEFAULT true,"horas_totales" bigint,"horas_etapa_lectiva" bigint,"horas_etapa_productiva" bigint,"tipo_programa_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_redes_conocimiento_programas" FOREIGN KEY ("red_conocimiento_id") REFERENCES "redes_conocimiento"("id"),CONSTRAINT "fk_tipos_programa_programas" FOREIGN KEY ("tipo_programa_id") REFERENCES "tipos_programa"("id"));
+CREATE INDEX IF NOT EXISTS "idx_programas_formacion_deleted_at" ON "programas_formacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "instructors" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"persona_id" bigint NOT NULL,"regional_id" bigint,"status" boolean DEFAULT true,"especialidades" json,"competencias" json,"anos_experiencia" bigint,"experiencia_laboral" text,"numero_documento_cache" varchar(20),"nombre_completo_cache" varchar(255),"tipo_vinculacion_id" bigint,"jornadas" json,"centro_formacion_id" bigint,"experiencia_instructor_meses" bigint,"fecha_ingreso_sena" timestamptz,"nivel_academico_id" bigint,"titulos_obtenidos" json,"instituciones_educativas" json,"certificaciones_tecnicas" json,"cursos_complementarios" json,"formacion_pedagogia" text,"areas_experticia" json,"competencias_tic" json,"idiomas" json,"habilidades_pedagogicas" json,"documentos_adjuntos" json,"numero_contrato" varchar(50),"fecha_inicio_contrato" timestamptz,"fecha_fin_contrato" timestamptz,"supervisor_contrato" varchar(255),"eps" varchar(100),"arl" varchar(100),PRIMARY KEY ("id"),CONSTRAINT "fk_centros_formacion_instructores" FOREIGN KEY ("centro_formacion_id") REFERENCES "centros_formacion"("id"),CONSTRAINT "fk_regionals_instructores" FOREIGN KEY ("regional_id") REFERENCES "regionals"("id"),CONSTRAINT "fk_instructors_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_instructors_persona_id" ON "instructors" ("persona_id");
+CREATE INDEX IF NOT EXISTS "idx_instructors_deleted_at" ON "instructors" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "jornadas" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,"hora_inicio" varchar(5),"hora_fin" varchar(5),"minutos_extension_fin" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_jornadas_deleted_at" ON "jornadas" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "fichas_caracterizacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"programa_formacion_id" bigint NOT NULL,"ficha" varchar(50) NOT NULL,"instructor_id" bigint,"fecha_inicio" timestamptz,"fecha_fin" timestamptz,"ambiente_id" bigint,"modalidad_formacion_id" bigint,"sede_id" bigint,"jornada_id" bigint,"total_horas" bigint,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_instructors_fichas" FOREIGN KEY ("instructor_id") REFERENCES "instructors"("id"),CONSTRAINT "fk_sedes_fichas" FOREIGN KEY ("sede_id") REFERENCES "sedes"("id"),CONSTRAINT "fk_ambientes_fichas" FOREIGN KEY ("ambiente_id") REFERENCES "ambientes"("id"),CONSTRAINT "fk_fichas_caracterizacion_modalidad_formacion" FOREIGN KEY ("modalidad_formacion_id") REFERENCES "modalidades_formacion"("id"),CONSTRAINT "fk_fichas_caracterizacion_jornada" FOREIGN KEY ("jornada_id") REFERENCES "jornadas"("id"),CONSTRAINT "fk_programas_formacion_fichas" FOREIGN KEY ("programa_formacion_id") REFERENCES "programas_formacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_fichas_caracterizacion_deleted_at" ON "fichas_caracterizacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "aprendices" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"persona_id" bigint NOT NULL,"ficha_caracterizacion_id" bigint NOT NULL,"estado" boolean DEFAULT true,"oculto_en_asistencia" boolean DEFAULT false,PRIMARY KEY ("id"),CONSTRAINT "fk_aprendices_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"),CONSTRAINT "fk_fichas_caracterizacion_aprendices" FOREIGN KEY ("ficha_caracterizacion_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_aprendices_deleted_at" ON "aprendices" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "competencias" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"codigo" varchar(50) NOT NULL,"nombre" varchar(255) NOT NULL,"fecha_inicio" timestamptz,"fecha_fin" timestamptz,"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_competencias_deleted_at" ON "competencias" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "resultados_aprendizajes" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"codigo" varchar(50) NOT NULL,"nombre" varchar(255) NOT NULL,"duracion" decimal(10,2),"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_resultados_aprendizajes_deleted_at" ON "resultados_aprendizajes" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "resultados_aprendizaje_competencia" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"rap_id" bigint NOT NULL,"competencia_id" bigint NOT NULL,"duracion" decimal(10,2),PRIMARY KEY ("id"),CONSTRAINT "fk_resultados_aprendizaje_competencia_rap" FOREIGN KEY ("rap_id") REFERENCES "resultados_aprendizajes"("id"),CONSTRAINT "fk_resultados_aprendizaje_competencia_competencia" FOREIGN KEY ("competencia_id") REFERENCES "competencias"("id"));
+CREATE INDEX IF NOT EXISTS "idx_resultados_aprendizaje_competencia_deleted_at" ON "resultados_aprendizaje_competencia" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "competencia_programa" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"programa_id" bigint NOT NULL,"competencia_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_competencia_programa_programa" FOREIGN KEY ("programa_id") REFERENCES "programas_formacion"("id"),CONSTRAINT "fk_competencia_programa_competencia" FOREIGN KEY ("competencia_id") REFERENCES "competencias"("id"));
+CREATE INDEX IF NOT EXISTS "idx_competencia_programa_deleted_at" ON "competencia_programa" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "asignaciones_instructor" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"instructor_id" bigint NOT NULL,"ficha_id" bigint NOT NULL,"fecha_inicio" timestamptz,"fecha_fin" timestamptz,"total_horas" bigint,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_asignaciones_instructor_instructor" FOREIGN KEY ("instructor_id") REFERENCES "instructors"("id"),CONSTRAINT "fk_asignaciones_instructor_ficha" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_asignaciones_instructor_deleted_at" ON "asignaciones_instructor" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "asignacion_instructor_resultado" ("asignacion_id" bigint,"resultado_aprendizaje_id" bigint,PRIMARY KEY ("asignacion_id","resultado_aprendizaje_id"),CONSTRAINT "fk_asignacion_instructor_resultado_asignacion_instructor" FOREIGN KEY ("asignacion_id") REFERENCES "asignaciones_instructor"("id"),CONSTRAINT "fk_asignacion_instructor_resultado_resultados_aprendizaje" FOREIGN KEY ("resultado_aprendizaje_id") REFERENCES "resultados_aprendizajes"("id"));
+CREATE TABLE IF NOT EXISTS "guias_aprendizaje" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"codigo" varchar(50) NOT NULL,"nombre" varchar(255) NOT NULL,"descripcion" text,"duracion" bigint,"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_guias_aprendizaje_deleted_at" ON "guias_aprendizaje" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "guia_aprendizaje_rap" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"guia_aprendizaje_id" bigint NOT NULL,"rap_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_guia_aprendizaje_rap_rap" FOREIGN KEY ("rap_id") REFERENCES "resultados_aprendizajes"("id"),CONSTRAINT "fk_guia_aprendizaje_rap_guia_aprendizaje" FOREIGN KEY ("guia_aprendizaje_id") REFERENCES "guias_aprendizaje"("id"));
+CREATE INDEX IF NOT EXISTS "idx_guia_aprendizaje_rap_deleted_at" ON "guia_aprendizaje_rap" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "evidencias" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"codigo" varchar(50),"nombre" varchar(255) NOT NULL,"id_estado" bigint,"fecha_evidencia" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_evidencias_deleted_at" ON "evidencias" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "evidencia_guia_aprendizaje" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"evidencia_id" bigint NOT NULL,"guia_aprendizaje_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_evidencia_guia_aprendizaje_evidencia" FOREIGN KEY ("evidencia_id") REFERENCES "evidencias"("id"),CONSTRAINT "fk_evidencia_guia_aprendizaje_guia_aprendizaje" FOREIGN KEY ("guia_aprendizaje_id") REFERENCES "guias_aprendizaje"("id"));
+CREATE INDEX IF NOT EXISTS "idx_evidencia_guia_aprendizaje_deleted_at" ON "evidencia_guia_aprendizaje" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "guias_resultados" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"guia_aprendizaje_id" bigint NOT NULL,"resultado_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_guias_resultados_guia_aprendizaje" FOREIGN KEY ("guia_aprendizaje_id") REFERENCES "guias_aprendizaje"("id"),CONSTRAINT "fk_guias_resultados_resultado" FOREIGN KEY ("resultado_id") REFERENCES "resultados_aprendizajes"("id"));
+CREATE INDEX IF NOT EXISTS "idx_guias_resultados_deleted_at" ON "guias_resultados" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "dias_formacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,"codigo" varchar(50),"status" boolean DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_dias_formacion_deleted_at" ON "dias_formacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "ficha_dias_formacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"ficha_id" bigint NOT NULL,"dia_formacion_id" bigint NOT NULL,"hora_inicio" varchar(10),"hora_fin" varchar(10),"orden" bigint DEFAULT 0,"jornada_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_ficha_dias_formacion_dia_formacion" FOREIGN KEY ("dia_formacion_id") REFERENCES "dias_formacion"("id"),CONSTRAINT "fk_ficha_dias_formacion_jornada" FOREIGN KEY ("jornada_id") REFERENCES "jornadas"("id"),CONSTRAINT "fk_fichas_caracterizacion_ficha_dias_formacion" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_ficha_dias_formacion_ficha_id" ON "ficha_dias_formacion" ("ficha_id");
+CREATE INDEX IF NOT EXISTS "idx_ficha_dias_formacion_deleted_at" ON "ficha_dias_formacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "instructor_ficha_dias" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"instructor_id" bigint NOT NULL,"ficha_id" bigint NOT NULL,"dia_formacion_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_instructor_ficha_dias_instructor" FOREIGN KEY ("instructor_id") REFERENCES "instructors"("id"),CONSTRAINT "fk_instructor_ficha_dias_ficha" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"),CONSTRAINT "fk_instructor_ficha_dias_dia_formacion" FOREIGN KEY ("dia_formacion_id") REFERENCES "dias_formacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_instructor_ficha_dias_deleted_at" ON "instructor_ficha_dias" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "instructor_ficha_traslado_fechas" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"ficha_id" bigint NOT NULL,"instructor_origen_id" bigint NOT NULL,"instructor_destino_id" bigint NOT NULL,"dia_origen_id" bigint NOT NULL,"dia_destino_id" bigint NOT NULL,"fecha_origen" date NOT NULL,"fecha_destino" date NOT NULL,"motivo" varchar(500),"actor_user_id" bigint,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_instructor_ficha_traslado_fechas_fecha_destino" ON "instructor_ficha_traslado_fechas" ("fecha_destino");
+CREATE INDEX IF NOT EXISTS "idx_instructor_ficha_traslado_fechas_fecha_origen" ON "instructor_ficha_traslado_fechas" ("fecha_origen");
+CREATE INDEX IF NOT EXISTS "idx_instructor_ficha_traslado_fechas_ficha_id" ON "instructor_ficha_traslado_fechas" ("ficha_id");
+CREATE INDEX IF NOT EXISTS "idx_instructor_ficha_traslado_fechas_deleted_at" ON "instructor_ficha_traslado_fechas" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "dias_festivos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"fecha" date NOT NULL,"nombre" varchar(120) NOT NULL,"anio" bigint NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_dias_festivos_anio" ON "dias_festivos" ("anio");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_dias_festivos_fecha" ON "dias_festivos" ("fecha");
+CREATE INDEX IF NOT EXISTS "idx_dias_festivos_deleted_at" ON "dias_festivos" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "dias_sin_formacion_sede" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"sede_id" bigint NOT NULL,"fecha_inicio" date NOT NULL,"fecha_fin" date NOT NULL,"motivo" varchar(255) NOT NULL,"actor_user_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_dias_sin_formacion_sede_sede" FOREIGN KEY ("sede_id") REFERENCES "sedes"("id"));
+CREATE INDEX IF NOT EXISTS "idx_dias_sin_formacion_sede_sede_id" ON "dias_sin_formacion_sede" ("sede_id");
+CREATE INDEX IF NOT EXISTS "idx_dias_sin_formacion_sede_deleted_at" ON "dias_sin_formacion_sede" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "asignacion_instructor_logs" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"asignacion_id" bigint NOT NULL,"accion" varchar(50) NOT NULL,"detalles" text,"fecha_accion" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_asignaciones_instructor_logs" FOREIGN KEY ("asignacion_id") REFERENCES "asignaciones_instructor"("id"));
+CREATE INDEX IF NOT EXISTS "idx_asignacion_instructor_logs_deleted_at" ON "asignacion_instructor_logs" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "instructor_fichas_caracterizacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"instructor_id" bigint NOT NULL,"ficha_id" bigint NOT NULL,"competencia_id" bigint,"fecha_inicio" timestamptz,"fecha_fin" timestamptz,"total_horas_instructor" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_instructor_fichas_caracterizacion_instructor" FOREIGN KEY ("instructor_id") REFERENCES "instructors"("id"),CONSTRAINT "fk_instructor_fichas_caracterizacion_competencia" FOREIGN KEY ("competencia_id") REFERENCES "competencias"("id"),CONSTRAINT "fk_fichas_caracterizacion_instructor_fichas" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_instructor_fichas_caracterizacion_deleted_at" ON "instructor_fichas_caracterizacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "asistencias" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"evidencia_id" bigint,"instructor_ficha_id" bigint NOT NULL,"fecha" timestamptz NOT NULL,"hora_inicio" timestamptz,"hora_fin" timestamptz,"is_finished" boolean DEFAULT false,"observaciones" text,PRIMARY KEY ("id"),CONSTRAINT "fk_evidencias_asistencias" FOREIGN KEY ("evidencia_id") REFERENCES "evidencias"("id"),CONSTRAINT "fk_asistencias_instructor_ficha" FOREIGN KEY ("instructor_ficha_id") REFERENCES "instructor_fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_asistencias_deleted_at" ON "asistencias" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "asistencia_aprendices" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"asistencia_id" bigint NOT NULL,"instructor_ficha_id" bigint,"aprendiz_ficha_id" bigint NOT NULL,"hora_ingreso" timestamptz,"hora_salida" timestamptz,"observaciones" text,"estado" varchar(50) DEFAULT '',"requiere_revision" boolean DEFAULT false,"motivo_ajuste" text,"instructor_ficha_id_registro_ingreso" bigint,"instructor_ficha_id_registro_salida" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_asistencias_asistencia_aprendices" FOREIGN KEY ("asistencia_id") REFERENCES "asistencias"("id"),CONSTRAINT "fk_asistencia_aprendices_instructor_registro_ingreso" FOREIGN KEY ("instructor_ficha_id_registro_ingreso") REFERENCES "instructor_fichas_caracterizacion"("id"),CONSTRAINT "fk_asistencia_aprendices_instructor_registro_salida" FOREIGN KEY ("instructor_ficha_id_registro_salida") REFERENCES "instructor_fichas_caracterizacion"("id"),CONSTRAINT "fk_aprendices_asistencias" FOREIGN KEY ("aprendiz_ficha_id") REFERENCES "aprendices"("id"));
+CREATE INDEX IF NOT EXISTS "idx_asistencia_aprendices_deleted_at" ON "asistencia_aprendices" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "tipos_observacion_asistencia" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"codigo" varchar(80) NOT NULL,"nombre" varchar(255) NOT NULL,"activo" boolean DEFAULT true,"peso_riesgo" bigint,PRIMARY KEY ("id"));
+ALTER TABLE "tipos_observacion_asistencia" ADD COLUMN IF NOT EXISTS "peso_riesgo" bigint;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tipos_observacion_asistencia_codigo" ON "tipos_observacion_asistencia" ("codigo");
+CREATE INDEX IF NOT EXISTS "idx_tipos_observacion_asistencia_deleted_at" ON "tipos_observacion_asistencia" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "asistencia_aprendiz_tipo_observacion" ("asistencia_aprendiz_id" bigint,"tipo_observacion_id" bigint,PRIMARY KEY ("asistencia_aprendiz_id","tipo_observacion_id"),CONSTRAINT "fk_asistencia_aprendiz_tipo_observacion_asistencia_aprendiz" FOREIGN KEY ("asistencia_aprendiz_id") REFERENCES "asistencia_aprendices"("id"),CONSTRAINT "fk_asistencia_aprendiz_tipo_observacion_tipo_observacioe4c27b5e" FOREIGN KEY ("tipo_observacion_id") REFERENCES "tipos_observacion_asistencia"("id"));
+CREATE TABLE IF NOT EXISTS "asistencia_qr_usos" ("id" bigserial,"asistencia_id" bigint NOT NULL,"aprendiz_id" bigint NOT NULL,"ventana" bigint NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "uq_asistencia_qr_uso" ON "asistencia_qr_usos" ("asistencia_id","aprendiz_id","ventana");
+CREATE TABLE IF NOT EXISTS "alertas_asistencia_log" ("id" bigserial,"ficha_id" bigint NOT NULL,"fecha" date NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_alertas_asistencia_log_ficha" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_alerta_ficha_fecha" ON "alertas_asistencia_log" ("ficha_id","fecha");
+CREATE TABLE IF NOT EXISTS "tareas_programadas_ejecuciones" ("id" bigserial,"tarea" varchar(100) NOT NULL,"origen" varchar(20) NOT NULL,"instancia" varchar(255),"user_id" bigint,"iniciada_at" timestamptz NOT NULL,"finalizada_at" timestamptz,"duracion_ms" bigint NOT NULL DEFAULT 0,"exitosa" boolean NOT NULL DEFAULT false,"error" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_tarea_ejecucion_tarea_inicio" ON "tareas_programadas_ejecuciones" ("tarea","iniciada_at" desc);
+CREATE TABLE IF NOT EXISTS "entrada_salida" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_id" bigint NOT NULL,"sede_id" bigint NOT NULL,"tipo_persona" varchar(50) NOT NULL,"fecha_entrada" timestamptz NOT NULL,"hora_entrada" timestamptz NOT NULL,"fecha_salida" timestamptz,"hora_salida" timestamptz,"observaciones" text,PRIMARY KEY ("id"),CONSTRAINT "fk_entrada_salida_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"),CONSTRAINT "fk_entrada_salida_sede" FOREIGN KEY ("sede_id") REFERENCES "sedes"("id"));
+CREATE INDEX IF NOT EXISTS "idx_entrada_salida_deleted_at" ON "entrada_salida" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_ingreso_salida" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_id" bigint NOT NULL,"sede_id" bigint NOT NULL,"tipo_persona" varchar(50) NOT NULL,"fecha_entrada" timestamptz NOT NULL,"hora_entrada" timestamptz NOT NULL,"timestamp_entrada" timestamptz NOT NULL,"fecha_salida" timestamptz,"hora_salida" timestamptz,"timestamp_salida" timestamptz,"ambiente_id" bigint,"ficha_caracterizacion_id" bigint,"observaciones" text,PRIMARY KEY ("id"),CONSTRAINT "fk_persona_ingreso_salida_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"),CONSTRAINT "fk_persona_ingreso_salida_sede" FOREIGN KEY ("sede_id") REFERENCES "sedes"("id"),CONSTRAINT "fk_persona_ingreso_salida_ambiente" FOREIGN KEY ("ambiente_id") REFERENCES "ambientes"("id"),CONSTRAINT "fk_persona_ingreso_salida_ficha_caracterizacion" FOREIGN KEY ("ficha_caracterizacion_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_persona_ingreso_salida_deleted_at" ON "persona_ingreso_salida" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "reporte_salida_automatica" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_id" bigint NOT NULL,"sede_id" bigint NOT NULL,"fecha_reporte" timestamptz NOT NULL,"hora_salida" timestamptz NOT NULL,"motivo" varchar(255),"observaciones" text,PRIMARY KEY ("id"),CONSTRAINT "fk_reporte_salida_automatica_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"),CONSTRAINT "fk_reporte_salida_automatica_sede" FOREIGN KEY ("sede_id") REFERENCES "sedes"("id"));
+CREATE INDEX IF NOT EXISTS "idx_reporte_salida_automatica_deleted_at" ON "reporte_salida_automatica" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "jornada_bloques" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"jornada_id" bigint NOT NULL,"dia_formacion_id" bigint NOT NULL,"hora_inicio" varchar(5) NOT NULL,"hora_fin" varchar(5) NOT NULL,"orden" bigint DEFAULT 0,PRIMARY KEY ("id"),CONSTRAINT "fk_jornada_bloques_jornada" FOREIGN KEY ("jornada_id") REFERENCES "jornadas"("id"),CONSTRAINT "fk_jornada_bloques_dia_formacion" FOREIGN KEY ("dia_formacion_id") REFERENCES "dias_formacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_jornada_bloques_jornada_id" ON "jornada_bloques" ("jornada_id");
+CREATE INDEX IF NOT EXISTS "idx_jornada_bloques_deleted_at" ON "jornada_bloques" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "modalidades" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(100) NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_modalidades_deleted_at" ON "modalidades" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "complementarios_catalogo" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"prf_codigo" varchar(50) NOT NULL,"version" bigint NOT NULL,"cod_ver" varchar(50),"denominacion" varchar(255) NOT NULL,"nivel_formacion" varchar(100),"duracion_horas" bigint,"requisitos_ingreso" text,"linea_tecnologica" varchar(255),"red_tecnologica" varchar(255),"red_conocimiento" varchar(255),"modalidad_id" bigint,"apuesta_prioritaria" varchar(255),"tipo_permiso" varchar(100),"multiple_inscripcion" boolean DEFAULT false,"alamedida" boolean DEFAULT false,"fic" boolean DEFAULT false,"creditos" bigint,"indice" varchar(100),"ocupacion" varchar(255),"activo" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_complementarios_catalogo_modalidad" FOREIGN KEY ("modalidad_id") REFERENCES "modalidades"("id"));
+CREATE INDEX IF NOT EXISTS "idx_complementarios_catalogo_deleted_at" ON "complementarios_catalogo" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "complementarios_ofertados" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"codigo" varchar(50) NOT NULL,"nombre" varchar(255) NOT NULL,"justificacion" text,"requisitos_ingreso" text,"duracion" bigint NOT NULL,"cupos" bigint DEFAULT 0,"estado" bigint DEFAULT 0,"modalidad_id" bigint,"jornada_id" bigint,"ambiente_id" bigint,"ambiente_comentario" text,"catalogo_id" bigint,"fecha_inicio" timestamptz,"fecha_fin" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_complementarios_ofertados_jornada" FOREIGN KEY ("jornada_id") REFERENCES "jornadas"("id"),CONSTRAINT "fk_complementarios_ofertados_ambiente" FOREIGN KEY ("ambiente_id") REFERENCES "ambientes"("id"),CONSTRAINT "fk_complementarios_catalogo_complementarios" FOREIGN KEY ("catalogo_id") REFERENCES "complementarios_catalogo"("id"),CONSTRAINT "fk_complementarios_ofertados_modalidad" FOREIGN KEY ("modalidad_id") REFERENCES "modalidades"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_complementarios_ofertados_codigo" ON "complementarios_ofertados" ("codigo");
+CREATE INDEX IF NOT EXISTS "idx_complementarios_ofertados_deleted_at" ON "complementarios_ofertados" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "aspirantes_complementarios" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_id" bigint NOT NULL,"complementario_id" bigint NOT NULL,"estado" bigint DEFAULT 1,"observaciones" text,"documento_identidad_path" varchar(500),"documento_identidad_nombre" varchar(255),"fecha_inscripcion" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_complementarios_ofertados_aspirantes" FOREIGN KEY ("complementario_id") REFERENCES "complementarios_ofertados"("id"),CONSTRAINT "fk_aspirantes_complementarios_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"));
+CREATE INDEX IF NOT EXISTS "idx_aspirantes_complementarios_deleted_at" ON "aspirantes_complementarios" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "categorias_caracterizacion_complementarios" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre" varchar(255) NOT NULL,"parent_id" bigint,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_categorias_caracterizacion_complementarios_children" FOREIGN KEY ("parent_id") REFERENCES "categorias_caracterizacion_complementarios"("id"));
+CREATE INDEX IF NOT EXISTS "idx_categorias_caracterizacion_complementarios_deleted_at" ON "categorias_caracterizacion_complementarios" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_caracterizacion" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_id" bigint NOT NULL,"caracterizacion_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_persona_caracterizacion_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"),CONSTRAINT "fk_persona_caracterizacion_caracterizacion" FOREIGN KEY ("caracterizacion_id") REFERENCES "categorias_caracterizacion_complementarios"("id"));
+CREATE INDEX IF NOT EXISTS "idx_persona_caracterizacion_deleted_at" ON "persona_caracterizacion" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "sofia_validation_progress" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"aspirante_complementario_id" bigint NOT NULL,"estado" varchar(50) NOT NULL,"progreso" bigint DEFAULT 0,"mensaje" text,"fecha_validacion" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_sofia_validation_progress_aspirante_complementario" FOREIGN KEY ("aspirante_complementario_id") REFERENCES "aspirantes_complementarios"("id"));
+CREATE INDEX IF NOT EXISTS "idx_sofia_validation_progress_deleted_at" ON "sofia_validation_progress" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "senasofiaplus_validation_logs" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"aspirante_complementario_id" bigint NOT NULL,"accion" varchar(50) NOT NULL,"request_data" json,"response_data" json,"status" varchar(50),"mensaje" text,"fecha_validacion" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_senasofiaplus_validation_logs_aspirante_complementario" FOREIGN KEY ("aspirante_complementario_id") REFERENCES "aspirantes_complementarios"("id"));
+CREATE INDEX IF NOT EXISTS "idx_senasofiaplus_validation_logs_deleted_at" ON "senasofiaplus_validation_logs" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "logins" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" bigint,"identificador" varchar(150),"exitoso" boolean NOT NULL DEFAULT false,"motivo" varchar(40),"ip_address" varchar(45),"user_agent" varchar(255),"fecha_login" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_logins_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+ALTER TABLE "logins" ALTER COLUMN "user_id" DROP NOT NULL;
+ALTER TABLE "logins" ADD COLUMN IF NOT EXISTS "identificador" varchar(150);
+ALTER TABLE "logins" ADD COLUMN IF NOT EXISTS "exitoso" boolean NOT NULL DEFAULT false;
+ALTER TABLE "logins" ADD COLUMN IF NOT EXISTS "motivo" varchar(40);
+CREATE INDEX IF NOT EXISTS "idx_logins_fecha_login" ON "logins" ("fecha_login");
+CREATE INDEX IF NOT EXISTS "idx_logins_ip_address" ON "logins" ("ip_address");
+CREATE INDEX IF NOT EXISTS "idx_logins_user_id" ON "logins" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_logins_deleted_at" ON "logins" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "login_bloqueos" ("id" bigserial,"clave" varchar(80) NOT NULL,"fallos" bigint NOT NULL DEFAULT 0,"ultimo_fallo_at" timestamptz NOT NULL,"bloqueado_hasta" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_login_bloqueos_clave" ON "login_bloqueos" ("clave");
+CREATE TABLE IF NOT EXISTS "registro_actividades" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" bigint,"accion" varchar(100) NOT NULL,"tabla" varchar(100),"registro_id" bigint,"detalles" text,"fecha_accion" timestamptz NOT NULL,"ip_address" varchar(45),PRIMARY KEY ("id"),CONSTRAINT "fk_registro_actividades_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_registro_actividades_fecha_accion" ON "registro_actividades" ("fecha_accion");
+CREATE INDEX IF NOT EXISTS "idx_registro_actividades_tabla_registro" ON "registro_actividades" ("tabla","registro_id");
+CREATE INDEX IF NOT EXISTS "idx_registro_actividades_accion" ON "registro_actividades" ("accion");
+CREATE INDEX IF NOT EXISTS "idx_registro_actividades_user_id" ON "registro_actividades" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_registro_actividades_deleted_at" ON "registro_actividades" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_contact_alerts" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_id" bigint NOT NULL,"tipo" varchar(50) NOT NULL,"valor" varchar(255) NOT NULL,"mensaje" text,"status" boolean DEFAULT true,PRIMARY KEY ("id"),CONSTRAINT "fk_persona_contact_alerts_persona" FOREIGN KEY ("persona_id") REFERENCES "personas"("id"));
+CREATE INDEX IF NOT EXISTS "idx_persona_contact_alerts_deleted_at" ON "persona_contact_alerts" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_imports" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"nombre_archivo" varchar(255) NOT NULL,"total_registros" bigint NOT NULL,"registros_exitosos" bigint DEFAULT 0,"registros_fallidos" bigint DEFAULT 0,"fecha_importacion" timestamptz NOT NULL,"status" varchar(50) DEFAULT 'PENDIENTE',"observaciones" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_persona_imports_deleted_at" ON "persona_imports" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "persona_import_issues" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"persona_import_id" bigint NOT NULL,"fila" bigint NOT NULL,"campo" varchar(100),"valor" varchar(255),"error" text NOT NULL,"tipo" varchar(50) NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_persona_import_issues_persona_import" FOREIGN KEY ("persona_import_id") REFERENCES "persona_imports"("id"));
+CREATE INDEX IF NOT EXISTS "idx_persona_import_issues_deleted_at" ON "persona_import_issues" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "sesiones" ("id" bigserial,"user_id" bigint NOT NULL,"refresh_token_hash" varchar(64) NOT NULL,"refresh_token_anterior_hash" varchar(64),"user_agent" varchar(255),"ip" varchar(64),"created_at" timestamptz,"ultimo_uso_at" timestamptz NOT NULL,"expira_at" timestamptz NOT NULL,"revocada_at" timestamptz,"motivo_revocacion" varchar(50),PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_sesiones_expira_at" ON "sesiones" ("expira_at");
+CREATE INDEX IF NOT EXISTS "idx_sesiones_refresh_token_anterior_hash" ON "sesiones" ("refresh_token_anterior_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sesiones_refresh_token_hash" ON "sesiones" ("refresh_token_hash");
+CREATE INDEX IF NOT EXISTS "idx_sesiones_user_id" ON "sesiones" ("user_id");
+CREATE TABLE IF NOT EXISTS "tokens_usuario" ("id" bigserial,"user_id" bigint NOT NULL,"tipo" varchar(30) NOT NULL,"token_hash" varchar(64) NOT NULL,"email" varchar(255) NOT NULL,"expira_at" timestamptz NOT NULL,"usado_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tokens_usuario_token_hash" ON "tokens_usuario" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_tokens_usuario_tipo" ON "tokens_usuario" ("tipo");
+CREATE INDEX IF NOT EXISTS "idx_tokens_usuario_user_id" ON "tokens_usuario" ("user_id");
+CREATE TABLE IF NOT EXISTS "excusas_inasistencia" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"aprendiz_id" bigint NOT NULL,"ficha_id" bigint NOT NULL,"motivo" varchar(500) NOT NULL,"estado" varchar(20) NOT NULL DEFAULT 'pendiente',"archivo_nombre" varchar(255) NOT NULL,"archivo_ruta" varchar(500) NOT NULL,"archivo_tipo" varchar(100),"archivo_tamano" bigint,"revisado_por_user_id" bigint,"revisado_at" timestamptz,"observacion_revision" varchar(500),PRIMARY KEY ("id"),CONSTRAINT "fk_excusas_inasistencia_aprendiz" FOREIGN KEY ("aprendiz_id") REFERENCES "aprendices"("id"),CONSTRAINT "fk_excusas_inasistencia_ficha" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"));
+CREATE INDEX IF NOT EXISTS "idx_excusas_inasistencia_estado" ON "excusas_inasistencia" ("estado");
+CREATE INDEX IF NOT EXISTS "idx_excusas_inasistencia_ficha_id" ON "excusas_inasistencia" ("ficha_id");
+CREATE INDEX IF NOT EXISTS "idx_excusas_inasistencia_aprendiz_id" ON "excusas_inasistencia" ("aprendiz_id");
+CREATE INDEX IF NOT EXISTS "idx_excusas_inasistencia_deleted_at" ON "excusas_inasistencia" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "excusa_inasistencia_sesiones" ("id" bigserial,"excusa_id" bigint NOT NULL,"asistencia_id" bigint NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_excusa_inasistencia_sesiones_asistencia" FOREIGN KEY ("asistencia_id") REFERENCES "asistencias"("id"),CONSTRAINT "fk_excusas_inasistencia_sesiones" FOREIGN KEY ("excusa_id") REFERENCES "excusas_inasistencia"("id"));
+CREATE INDEX IF NOT EXISTS "idx_excusa_inasistencia_sesiones_asistencia_id" ON "excusa_inasistencia_sesiones" ("asistencia_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "uk_excusa_sesion" ON "excusa_inasistencia_sesiones" ("excusa_id","asistencia_id");
+CREATE TABLE IF NOT EXISTS "casos_bienestar" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"aprendiz_id" bigint NOT NULL,"persona_id" bigint NOT NULL,"ficha_id" bigint NOT NULL,"origen" varchar(20) NOT NULL,"estado" varchar(20) NOT NULL DEFAULT 'abierto',"motivo" varchar(1000) NOT NULL,"responsable_user_id" bigint,"periodo_inicio" date,"periodo_fin" date,"inasistencias" bigint NOT NULL DEFAULT 0,"inasistencias_justificadas" bigint NOT NULL DEFAULT 0,"total_sesiones" bigint NOT NULL DEFAULT 0,"creado_por_user_id" bigint NOT NULL,"cerrado_por_user_id" bigint,"cerrado_at" timestamptz,"observacion_cierre" varchar(1000),PRIMARY KEY ("id"),CONSTRAINT "fk_casos_bienestar_aprendiz" FOREIGN KEY ("aprendiz_id") REFERENCES "aprendices"("id"),CONSTRAINT "fk_casos_bienestar_ficha" FOREIGN KEY ("ficha_id") REFERENCES "fichas_caracterizacion"("id"),CONSTRAINT "fk_casos_bienestar_responsable" FOREIGN KEY ("responsable_user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_casos_bienestar_responsable_user_id" ON "casos_bienestar" ("responsable_user_id");
+CREATE INDEX IF NOT EXISTS "idx_casos_bienestar_estado" ON "casos_bienestar" ("estado");
+CREATE INDEX IF NOT EXISTS "idx_casos_bienestar_ficha_id" ON "casos_bienestar" ("ficha_id");
+CREATE INDEX IF NOT EXISTS "idx_casos_bienestar_persona_id" ON "casos_bienestar" ("persona_id");
+CREATE INDEX IF NOT EXISTS "idx_casos_bienestar_aprendiz_id" ON "casos_bienestar" ("aprendiz_id");
+CREATE INDEX IF NOT EXISTS "idx_casos_bienestar_deleted_at" ON "casos_bienestar" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "caso_bienestar_intervenciones" ("id" bigserial,"caso_id" bigint NOT NULL,"fecha" date NOT NULL,"tipo" varchar(30) NOT NULL,"descripcion" varchar(2000) NOT NULL,"acuerdos" varchar(1000),"autor_user_id" bigint NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_casos_bienestar_intervenciones" FOREIGN KEY ("caso_id") REFERENCES "casos_bienestar"("id"),CONSTRAINT "fk_caso_bienestar_intervenciones_autor" FOREIGN KEY ("autor_user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_caso_bienestar_intervenciones_caso_id" ON "caso_bienestar_intervenciones" ("caso_id");
+CREATE TABLE IF NOT EXISTS "caso_bienestar_adjuntos" ("id" bigserial,"caso_id" bigint NOT NULL,"archivo_nombre" varchar(255) NOT NULL,"archivo_ruta" varchar(500) NOT NULL,"archivo_tipo" varchar(100),"archivo_tamano" bigint,"subido_por_user_id" bigint NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_casos_bienestar_adjuntos" FOREIGN KEY ("caso_id") REFERENCES "casos_bienestar"("id"));
+CREATE INDEX IF NOT EXISTS "idx_caso_bienestar_adjuntos_caso_id" ON "caso_bienestar_adjuntos" ("caso_id");
+CREATE TABLE IF NOT EXISTS "caso_bienestar_recordatorios" ("id" bigserial,"caso_id" bigint NOT NULL,"fecha_recordatorio" timestamptz NOT NULL,"nota" varchar(500) NOT NULL,"creado_por_user_id" bigint NOT NULL,"completado_at" timestamptz,"notificado_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_casos_bienestar_recordatorios" FOREIGN KEY ("caso_id") REFERENCES "casos_bienestar"("id"));
+CREATE INDEX IF NOT EXISTS "idx_caso_bienestar_recordatorios_fecha_recordatorio" ON "caso_bienestar_recordatorios" ("fecha_recordatorio");
+CREATE INDEX IF NOT EXISTS "idx_caso_bienestar_recordatorios_caso_id" ON "caso_bienestar_recordatorios" ("caso_id");
+CREATE TABLE IF NOT EXISTS "resumen_semanal_preferencias" ("user_id" bigint,"tipo" varchar(20),"activo" boolean NOT NULL DEFAULT true,"updated_at" timestamptz,PRIMARY KEY ("user_id","tipo"));
+CREATE TABLE IF NOT EXISTS "correos_salientes" ("id" bigserial,"destinatarios" varchar(1000) NOT NULL,"asunto" varchar(255) NOT NULL,"plantilla" varchar(50) NOT NULL,"cuerpo_html" text,"cuerpo_texto" text NOT NULL,"sensible" boolean NOT NULL DEFAULT false,"estado" varchar(20) NOT NULL DEFAULT 'pendiente',"intentos" bigint NOT NULL DEFAULT 0,"proximo_intento_at" timestamptz,"ultimo_error" varchar(1000),"enviado_at" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_correos_salientes_proximo_intento_at" ON "correos_salientes" ("proximo_intento_at");
+CREATE INDEX IF NOT EXISTS "idx_correos_salientes_estado" ON "correos_salientes" ("estado");
+CREATE INDEX IF NOT EXISTS "idx_correos_salientes_plantilla" ON "correos_salientes" ("plantilla");
+CREATE TABLE IF NOT EXISTS "correo_saliente_adjuntos" ("id" bigserial,"correo_id" bigint NOT NULL,"nombre" varchar(255) NOT NULL,"content_type" varchar(100) NOT NULL,"tamano" bigint NOT NULL,"contenido" bytea NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_correos_salientes_adjuntos" FOREIGN KEY ("correo_id") REFERENCES "correos_salientes"("id"));
+CREATE INDEX IF NOT EXISTS "idx_correo_saliente_adjuntos_correo_id" ON "correo_saliente_adjuntos" ("correo_id");
+CREATE TABLE IF NOT EXISTS "notificaciones_usuario" ("id" bigserial,"user_id" bigint NOT NULL,"tipo" varchar(40) NOT NULL,"titulo" varchar(255) NOT NULL,"mensaje" text NOT NULL,"enlace" varchar(500),"leida_en" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_notificaciones_usuario_created_at" ON "notificaciones_usuario" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_notificaciones_usuario_user_leida" ON "notificaciones_usuario" ("user_id","leida_en");
+CREATE TABLE IF NOT EXISTS "notificacion_preferencias" ("user_id" bigint,"tipo" varchar(40),"activo" boolean NOT NULL DEFAULT true,"updated_at" timestamptz,PRIMARY KEY ("user_id","tipo"));
+CREATE TABLE IF NOT EXISTS "importacion_trabajos" ("id" bigserial,"tipo" varchar(20) NOT NULL,"estado" varchar(20) NOT NULL DEFAULT 'pendiente',"user_id" bigint NOT NULL,"filename" varchar(255) NOT NULL,"regional_id" bigint,"total_filas" bigint NOT NULL DEFAULT 0,"fila_actual" bigint NOT NULL DEFAULT 0,"procesados" bigint NOT NULL DEFAULT 0,"duplicados" bigint NOT NULL DEFAULT 0,"errores" bigint NOT NULL DEFAULT 0,"actualizados" bigint NOT NULL DEFAULT 0,"creados" bigint NOT NULL DEFAULT 0,"redes_creadas" bigint NOT NULL DEFAULT 0,"ficha_id" bigint,"ficha_creada" boolean NOT NULL DEFAULT false,"incidencias" bigint NOT NULL DEFAULT 0,"intentos" bigint NOT NULL DEFAULT 0,"ultimo_error" varchar(1000),"reclamado_hasta" timestamptz,"iniciado_at" timestamptz,"finalizado_at" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_importacion_trabajos_reclamado_hasta" ON "importacion_trabajos" ("reclamado_hasta");
+CREATE INDEX IF NOT EXISTS "idx_importacion_trabajos_user_id" ON "importacion_trabajos" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_importacion_trabajos_estado" ON "importacion_trabajos" ("estado");
+CREATE INDEX IF NOT EXISTS "idx_importacion_trabajos_tipo" ON "importacion_trabajos" ("tipo");
+CREATE TABLE IF NOT EXISTS "importacion_trabajo_archivos" ("id" bigserial,"trabajo_id" bigint NOT NULL,"content_type" varchar(100) NOT NULL,"tamano" bigint NOT NULL,"contenido" bytea NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_importacion_trabajos_archivo" FOREIGN KEY ("trabajo_id") REFERENCES "importacion_trabajos"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_importacion_trabajo_archivos_trabajo_id" ON "importacion_trabajo_archivos" ("trabajo_id");
+CREATE TABLE IF NOT EXISTS "importacion_incidencias" ("id" bigserial,"trabajo_id" bigint NOT NULL,"fila" bigint NOT NULL,"tipo" varchar(40) NOT NULL,"documento" varchar(50),"nombre" varchar(255),"correo" varchar(255),"celular" varchar(50),"ficha_origen" varchar(50),"detalle" varchar(1000),PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_importacion_incidencias_trabajo_id" ON "importacion_incidencias" ("trabajo_id");
+CREATE TABLE IF NOT EXISTS "juicios_evaluativos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"ficha_id" bigint NOT NULL,"aprendiz_id" bigint NOT NULL,"rap_id" bigint NOT NULL,"competencia_id" bigint NOT NULL,"juicio" varchar(20) NOT NULL,"observaciones" text,"instructor_id" bigint,"fecha_juicio" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_juicios_evaluativos_rap" FOREIGN KEY ("rap_id") REFERENCES "resultados_aprendizajes"("id"),CONSTRAINT "fk_juicios_evaluativos_competencia" FOREIGN KEY ("competencia_id") REFERENCES "competencias"("id"),CONSTRAINT "fk_juicios_evaluativos_instructor" FOREIGN KEY ("instructor_id") REFERENCES "instructors"("id"),CONSTRAINT "fk_juicios_evaluativos_aprendiz" FOREIGN KEY ("aprendiz_id") REFERENCES "aprendices"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "uk_juicio_aprendiz_rap" ON "juicios_evaluativos" ("aprendiz_id","rap_id");
+CREATE INDEX IF NOT EXISTS "idx_juicios_evaluativos_ficha_id" ON "juicios_evaluativos" ("ficha_id");
+CREATE INDEX IF NOT EXISTS "idx_juicios_evaluativos_deleted_at" ON "juicios_evaluativos" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "agenda_suscripciones" ("id" bigserial,"user_id" bigint NOT NULL,"token_hash" varchar(64) NOT NULL,"created_at" timestamptz,"ultimo_uso_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_agenda_suscripciones_token_hash" ON "agenda_suscripciones" ("token_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_agenda_suscripciones_user_id" ON "agenda_suscripciones" ("user_id");
+CREATE TABLE IF NOT EXISTS "reportes_asistencia" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"ficha_id" bigint NOT NULL,"fecha_inicio" date NOT NULL,"fecha_fin" date NOT NULL,"formato" varchar(10) NOT NULL,"estado" varchar(20) NOT NULL DEFAULT 'PENDIENTE',"nombre_archivo" varchar(255),"error" text,"sesiones" bigint DEFAULT 0,"solicitado_por" bigint NOT NULL,"finalizado_at" timestamptz,"intentos" bigint NOT NULL DEFAULT 0,"reclamado_hasta" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_reportes_asistencia_ficha_id" ON "reportes_asistencia" ("ficha_id");
+CREATE INDEX IF NOT EXISTS "idx_reportes_asistencia_deleted_at" ON "reportes_asistencia" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_reportes_asistencia_reclamado_hasta" ON "reportes_asistencia" ("reclamado_hasta");
+CREATE TABLE IF NOT EXISTS "reportes_asistencia_archivos" ("id" bigserial,"reporte_id" bigint NOT NULL,"contenido" bytea NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_reportes_asistencia_archivos_reporte" FOREIGN KEY ("reporte_id") REFERENCES "reportes_asistencia"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reportes_asistencia_archivos_reporte_id" ON "reportes_asistencia_archivos" ("reporte_id");
+CREATE TABLE IF NOT EXISTS "eleccion_procesos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"regional_id" bigint NOT NULL,"anio" bigint NOT NULL,"nombre_ciclo" varchar(120) NOT NULL,"estado" varchar(32) NOT NULL DEFAULT 'borrador',"fecha_inscripcion_inicio" timestamptz,"fecha_inscripcion_fin" timestamptz,"fecha_votacion_inicio" timestamptz,"fecha_votacion_fin" timestamptz,"min_dias_matricula" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_eleccion_procesos_regional" FOREIGN KEY ("regional_id") REFERENCES "regionals"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "uk_eleccion_regional_anio" ON "eleccion_procesos" ("regional_id","anio");
+CREATE INDEX IF NOT EXISTS "idx_eleccion_procesos_deleted_at" ON "eleccion_procesos" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "eleccion_planchas" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_create_id" bigint,"user_edit_id" bigint,"proceso_id" bigint NOT NULL,"titular_aprendiz_id" bigint NOT NULL,"suplente_aprendiz_id" bigint NOT NULL,"estado" varchar(32) NOT NULL,"titular_confirmado_at" timestamptz,"suplente_confirmado_at" timestamptz,"propuesta_por_user_id" bigint,"motivo_rechazo" varchar(500),PRIMARY KEY ("id"),CONSTRAINT "fk_eleccion_planchas_proceso" FOREIGN KEY ("proceso_id") REFERENCES "eleccion_procesos"("id"),CONSTRAINT "fk_eleccion_planchas_titular_aprendiz" FOREIGN KEY ("titular_aprendiz_id") REFERENCES "aprendices"("id"),CONSTRAINT "fk_eleccion_planchas_suplente_aprendiz" FOREIGN KEY ("suplente_aprendiz_id") REFERENCES "aprendices"("id"));
+CREATE INDEX IF NOT EXISTS "idx_eleccion_planchas_proceso_id" ON "eleccion_planchas" ("proceso_id");
+CREATE INDEX IF NOT EXISTS "idx_eleccion_planchas_deleted_at" ON "eleccion_planchas" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "eleccion_participaciones" ("id" bigserial,"proceso_id" bigint NOT NULL,"votante_user_id" bigint NOT NULL,"votante_aprendiz_id" bigint NOT NULL,"votado_at" timestamptz NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_eleccion_participaciones_proceso" FOREIGN KEY ("proceso_id") REFERENCES "eleccion_procesos"("id"),CONSTRAINT "fk_eleccion_participaciones_votante_aprendiz" FOREIGN KEY ("votante_aprendiz_id") REFERENCES "aprendices"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_eleccion_participacion_proceso_user" ON "eleccion_participaciones" ("proceso_id","votante_user_id");
+CREATE TABLE IF NOT EXISTS "eleccion_papeletas" ("recibo_hash" varchar(64),"proceso_id" bigint NOT NULL,"plancha_id" bigint NOT NULL,PRIMARY KEY ("recibo_hash"),CONSTRAINT "fk_eleccion_papeletas_plancha" FOREIGN KEY ("plancha_id") REFERENCES "eleccion_planchas"("id"));
+CREATE INDEX IF NOT EXISTS "idx_eleccion_papeletas_plancha_id" ON "eleccion_papeletas" ("plancha_id");
+CREATE INDEX IF NOT EXISTS "idx_eleccion_papeletas_proceso_id" ON "eleccion_papeletas" ("proceso_id");
+CREATE TABLE IF NOT EXISTS "eleccion_resultados" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"proceso_id" bigint NOT NULL,"plancha_ganadora_id" bigint,"votos_totales" bigint NOT NULL DEFAULT 0,"detalle_json" text,"empate" boolean NOT NULL DEFAULT false,"nota_desempate" text,"user_registro_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_eleccion_resultados_proceso" FOREIGN KEY ("proceso_id") REFERENCES "eleccion_procesos"("id"),CONSTRAINT "fk_eleccion_resultados_plancha_ganadora" FOREIGN KEY ("plancha_ganadora_id") REFERENCES "eleccion_planchas"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_eleccion_resultados_proceso_id" ON "eleccion_resultados" ("proceso_id");
+CREATE INDEX IF NOT EXISTS "idx_eleccion_resultados_deleted_at" ON "eleccion_resultados" ("deleted_at");
+CREATE TABLE IF NOT EXISTS "representantes_aprendiz" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"regional_id" bigint NOT NULL,"proceso_id" bigint NOT NULL,"titular_aprendiz_id" bigint NOT NULL,"suplente_aprendiz_id" bigint NOT NULL,"vigencia_desde" timestamptz NOT NULL,"vigencia_hasta" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_representantes_aprendiz_regional" FOREIGN KEY ("regional_id") REFERENCES "regionals"("id"),CONSTRAINT "fk_representantes_aprendiz_proceso" FOREIGN KEY ("proceso_id") REFERENCES "eleccion_procesos"("id"),CONSTRAINT "fk_representantes_aprendiz_titular_aprendiz" FOREIGN KEY ("titular_aprendiz_id") REFERENCES "aprendices"("id"),CONSTRAINT "fk_representantes_aprendiz_suplente_aprendiz" FOREIGN KEY ("suplente_aprendiz_id") REFERENCES "aprendices"("id"));
+CREATE INDEX IF NOT EXISTS "idx_representantes_aprendiz_regional_id" ON "representantes_aprendiz" ("regional_id");
+CREATE INDEX IF NOT EXISTS "idx_representantes_aprendiz_deleted_at" ON "representantes_aprendiz" ("deleted_at");
+
+-- Parches de columnas y datos que la API aplicaba al iniciar.
+ALTER TABLE aprendices
+    ADD COLUMN IF NOT EXISTS oculto_en_asistencia BOOLEAN NOT NULL DEFAULT false;
+UPDATE ficha_dias_formacion
+    SET hora_inicio = (regexp_match(hora_inicio, 'T(\d{2}:\d{2})'))[1]
+    WHERE hora_inicio ~ 'T\d{2}:\d{2}';
+UPDATE ficha_dias_formacion
+    SET hora_fin = (regexp_match(hora_fin, 'T(\d{2}:\d{2})'))[1]
+    WHERE hora_fin ~ 'T\d{2}:\d{2}';
+UPDATE ficha_dias_formacion
+    SET hora_inicio = ''
+    WHERE hora_inicio IS NOT NULL AND hora_inicio <> '' AND hora_inicio !~ '^\d{2}:\d{2}(:|$)';
+UPDATE ficha_dias_formacion
+    SET hora_fin = ''
+    WHERE hora_fin IS NOT NULL AND hora_fin <> '' AND hora_fin !~ '^\d{2}:\d{2}(:|$)';
+ALTER TABLE ficha_dias_formacion ADD COLUMN IF NOT EXISTS orden INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE ficha_dias_formacion
+    ADD COLUMN IF NOT EXISTS jornada_id BIGINT REFERENCES jornadas(id);
+UPDATE ficha_dias_formacion
+    SET jornada_id = jb.jornada_id
+    FROM jornada_bloques jb, fichas_caracterizacion fc
+    WHERE fc.id = ficha_dias_formacion.ficha_id
+      AND ficha_dias_formacion.jornada_id IS NULL
+      AND ficha_dias_formacion.deleted_at IS NULL
+      AND fc.jornada_id = jb.jornada_id
+      AND ficha_dias_formacion.dia_formacion_id = jb.dia_formacion_id
+      AND LEFT(TRIM(ficha_dias_formacion.hora_inicio), 5) = jb.hora_inicio
+      AND LEFT(TRIM(ficha_dias_formacion.hora_fin), 5) = jb.hora_fin;
+UPDATE jornadas
+    SET hora_inicio = '06:30', hora_fin = COALESCE(NULLIF(TRIM(hora_fin), ''), '13:00')
+    WHERE TRIM(hora_inicio) IN ('03:00', '3:00');
+INSERT INTO jornada_bloques (created_at, updated_at, jornada_id, dia_formacion_id, hora_inicio, hora_fin, orden)
+    SELECT NOW(), NOW(), 1, d.dia, '06:30', '13:00', 0
+    FROM (VALUES (1), (2), (3), (4), (5)) AS d(dia)
+    WHERE EXISTS (SELECT 1 FROM jornadas WHERE id = 1)
+      AND NOT EXISTS (SELECT 1 FROM jornada_bloques WHERE jornada_id = 1);
+UPDATE ficha_dias_formacion fd
+    SET hora_inicio = jb.hora_inicio,
+        hora_fin = jb.hora_fin,
+        jornada_id = jb.jornada_id
+    FROM fichas_caracterizacion fc, jornada_bloques jb
+    WHERE fc.id = fd.ficha_id
+      AND fc.jornada_id = jb.jornada_id
+      AND fd.dia_formacion_id = jb.dia_formacion_id
+      AND fd.deleted_at IS NULL
+      AND (fd.hora_inicio IS NULL OR TRIM(fd.hora_inicio) = '')
+      AND (fd.hora_fin IS NULL OR TRIM(fd.hora_fin) = '');
+UPDATE jornadas j
+    SET hora_inicio = sub.hi, hora_fin = sub.hf
+    FROM (
+        SELECT DISTINCT ON (jornada_id) jornada_id, hora_inicio AS hi, hora_fin AS hf
+        FROM jornada_bloques
+        ORDER BY jornada_id, orden, id
+    ) sub
+    WHERE j.id = sub.jornada_id
+      AND (
+        TRIM(COALESCE(j.hora_inicio, '')) = ''
+        OR TRIM(j.hora_inicio) IN ('03:00', '3:00')
+      );
+UPDATE jornadas SET nombre = 'DIURNA' WHERE nombre = 'MAÑANA';
+DELETE FROM ficha_dias_formacion fd
+    USING fichas_caracterizacion fc
+    WHERE fd.ficha_id = fc.id
+      AND fd.deleted_at IS NULL
+      AND fc.jornada_id IS NOT NULL
+      AND EXISTS (SELECT 1 FROM jornada_bloques jb WHERE jb.jornada_id = fc.jornada_id)
+      AND NOT EXISTS (
+        SELECT 1 FROM jornada_bloques jb
+        WHERE jb.jornada_id = fc.jornada_id AND jb.dia_formacion_id = fd.dia_formacion_id
+      );
+CREATE TABLE IF NOT EXISTS "configuracion_asistencia" ("id" bigserial,"plazo_edicion_observaciones_dias" bigint NOT NULL DEFAULT 5,"intervalo_auto_cierre_minutos" bigint NOT NULL DEFAULT 5,"minutos_alerta_sin_sesion" bigint NOT NULL DEFAULT 90,"minutos_extension_default" bigint NOT NULL DEFAULT 60,PRIMARY KEY ("id"));
+CREATE TABLE IF NOT EXISTS "usuario_regionales" ("user_id" bigint,"regional_id" bigint,PRIMARY KEY ("user_id","regional_id"),CONSTRAINT "fk_usuario_regionales_regional" FOREIGN KEY ("regional_id") REFERENCES "regionals"("id"),CONSTRAINT "fk_usuario_regionales_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+INSERT INTO configuracion_asistencia (id, plazo_edicion_observaciones_dias, intervalo_auto_cierre_minutos, minutos_alerta_sin_sesion, minutos_extension_default)
+    VALUES (1, 5, 5, 90, 60)
+    ON CONFLICT (id) DO NOTHING;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_casos_bienestar_aprendiz_abierto
+    ON casos_bienestar (aprendiz_id)
+    WHERE estado <> 'cerrado' AND deleted_at IS NULL;
+UPDATE tipos_observacion_asistencia
+    SET peso_riesgo = CASE codigo WHEN 'ABANDONO_FORMACION' THEN 3 WHEN 'RETARDO' THEN 1 ELSE 0 END
+    WHERE peso_riesgo IS NULL;
+
+-- Voto secreto: la antigua eleccion_votos (que unía votante y plancha) pasa a participaciones y papeletas. Las
+-- papeletas migradas reciben un hash aleatorio (no tienen recibo verificable) y se insertan en orden aleatorio.
+DO $$
+BEGIN
+    IF to_regclass('public.eleccion_votos') IS NOT NULL THEN
+        INSERT INTO eleccion_participaciones (proceso_id, votante_user_id, votante_aprendiz_id, votado_at)
+            SELECT proceso_id, votante_user_id, votante_aprendiz_id, COALESCE(updated_at, created_at, NOW())
+            FROM eleccion_votos WHERE deleted_at IS NULL
+            ON CONFLICT DO NOTHING;
+        INSERT INTO eleccion_papeletas (recibo_hash, proceso_id, plancha_id)
+            SELECT md5(random()::text || clock_timestamp()::text) || md5(random()::text), proceso_id, plancha_id
+            FROM eleccion_votos WHERE deleted_at IS NULL
+            ORDER BY random();
+        DROP TABLE eleccion_votos;
+    END IF;
+END $$;
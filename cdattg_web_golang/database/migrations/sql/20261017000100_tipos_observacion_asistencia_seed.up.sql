@@ -1,6 +1,5 @@
--- Seed de tipos de observación para asistencia (opcional si no se ejecuta el seeder Go).
--- Las tablas tipos_observacion_asistencia y asistencia_aprendiz_tipo_observacion las crea GORM AutoMigrate.
--- PostgreSQL: ejecutar después del despliegue para poblar el catálogo.
+-- Catálogo de tipos de observación de asistencia (el script suelto 20250313000000_tipos_observacion_asistencia_seed.sql
+-- pasa a migración versionada). ON CONFLICT: no pisa los códigos que ya haya cargado el seeder Go.
 
 INSERT INTO tipos_observacion_asistencia (codigo, nombre, activo, created_at, updated_at)
 VALUES
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS eleccion_actas;
+
+ALTER TABLE eleccion_resultados
+    DROP COLUMN IF EXISTS cerrada_at,
+    DROP COLUMN IF EXISTS desempate_at,
+    DROP COLUMN IF EXISTS desempate_metodo;
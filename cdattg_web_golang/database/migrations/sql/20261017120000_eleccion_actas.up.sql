@@ -0,0 +1,17 @@
+-- Desempate estructurado, momento del cierre del escrutinio y actas con hash de integridad.
+ALTER TABLE eleccion_resultados
+    ADD COLUMN desempate_metodo VARCHAR(32),
+    ADD COLUMN desempate_at TIMESTAMPTZ,
+    ADD COLUMN cerrada_at TIMESTAMPTZ;
+
+CREATE TABLE eleccion_actas (
+    id BIGSERIAL PRIMARY KEY,
+    proceso_id BIGINT NOT NULL REFERENCES eleccion_procesos (id),
+    hash VARCHAR(64) NOT NULL,
+    contenido TEXT NOT NULL,
+    generada_at TIMESTAMPTZ NOT NULL,
+    user_genero_id BIGINT
+);
+
+CREATE UNIQUE INDEX idx_eleccion_actas_proceso_id ON eleccion_actas (proceso_id);
+CREATE UNIQUE INDEX idx_eleccion_actas_hash ON eleccion_actas (hash);
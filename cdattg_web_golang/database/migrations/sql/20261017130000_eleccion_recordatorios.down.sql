@@ -0,0 +1 @@
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS ultimo_recordatorio_at;
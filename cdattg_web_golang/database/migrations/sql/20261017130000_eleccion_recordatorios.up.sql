@@ -0,0 +1,2 @@
+-- Último recordatorio de votar enviado a los aprendices que aún no votan en el proceso.
+ALTER TABLE eleccion_procesos ADD COLUMN ultimo_recordatorio_at TIMESTAMPTZ;
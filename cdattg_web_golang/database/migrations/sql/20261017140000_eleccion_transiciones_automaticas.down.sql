@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS eleccion_proceso_eventos;
+
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS auto_transiciones;
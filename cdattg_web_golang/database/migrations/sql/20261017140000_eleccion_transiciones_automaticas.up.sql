@@ -0,0 +1,15 @@
+-- Transiciones automáticas de fase según las fechas del proceso y bitácora de cambios de fase.
+ALTER TABLE eleccion_procesos ADD COLUMN auto_transiciones BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE eleccion_proceso_eventos (
+    id BIGSERIAL PRIMARY KEY,
+    proceso_id BIGINT NOT NULL REFERENCES eleccion_procesos (id),
+    estado_anterior VARCHAR(32) NOT NULL,
+    estado_nuevo VARCHAR(32) NOT NULL,
+    origen VARCHAR(16) NOT NULL,
+    user_id BIGINT,
+    detalle VARCHAR(500),
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_eleccion_proceso_eventos_proceso_id ON eleccion_proceso_eventos (proceso_id);
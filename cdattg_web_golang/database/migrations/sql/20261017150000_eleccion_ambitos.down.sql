@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_representantes_aprendiz_ficha_id;
+ALTER TABLE representantes_aprendiz DROP COLUMN IF EXISTS ficha_id;
+ALTER TABLE representantes_aprendiz DROP COLUMN IF EXISTS sede_id;
+ALTER TABLE representantes_aprendiz DROP COLUMN IF EXISTS ambito;
+
+DROP INDEX IF EXISTS uk_eleccion_ficha_anio;
+DROP INDEX IF EXISTS uk_eleccion_sede_anio;
+DROP INDEX IF EXISTS uk_eleccion_regional_anio_ambito;
+DROP INDEX IF EXISTS idx_eleccion_procesos_regional_id;
+
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS cargo_suplente;
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS cargo_titular;
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS ficha_id;
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS sede_id;
+ALTER TABLE eleccion_procesos DROP COLUMN IF EXISTS ambito;
+
+-- Falla si quedan ciclos de sede o ficha en la misma regional y año: deben eliminarse antes de revertir.
+CREATE UNIQUE INDEX IF NOT EXISTS uk_eleccion_regional_anio ON eleccion_procesos (regional_id, anio);
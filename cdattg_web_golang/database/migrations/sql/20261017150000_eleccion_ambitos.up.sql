@@ -0,0 +1,18 @@
+-- Elecciones por ámbito: además de la regional, ciclos de sede y de ficha (vocero) con cargos configurables.
+ALTER TABLE eleccion_procesos ADD COLUMN ambito VARCHAR(16) NOT NULL DEFAULT 'regional';
+ALTER TABLE eleccion_procesos ADD COLUMN sede_id BIGINT REFERENCES sedes (id);
+ALTER TABLE eleccion_procesos ADD COLUMN ficha_id BIGINT REFERENCES fichas_caracterizacion (id);
+ALTER TABLE eleccion_procesos ADD COLUMN cargo_titular VARCHAR(80) NOT NULL DEFAULT 'Representante';
+ALTER TABLE eleccion_procesos ADD COLUMN cargo_suplente VARCHAR(80) NOT NULL DEFAULT 'Suplente';
+
+-- Un ciclo por ámbito y año en lugar de uno por regional y año.
+DROP INDEX IF EXISTS uk_eleccion_regional_anio;
+CREATE INDEX idx_eleccion_procesos_regional_id ON eleccion_procesos (regional_id);
+CREATE UNIQUE INDEX uk_eleccion_regional_anio_ambito ON eleccion_procesos (regional_id, anio) WHERE ambito = 'regional';
+CREATE UNIQUE INDEX uk_eleccion_sede_anio ON eleccion_procesos (sede_id, anio) WHERE ambito = 'sede';
+CREATE UNIQUE INDEX uk_eleccion_ficha_anio ON eleccion_procesos (ficha_id, anio) WHERE ambito = 'ficha';
+
+ALTER TABLE representantes_aprendiz ADD COLUMN ambito VARCHAR(16) NOT NULL DEFAULT 'regional';
+ALTER TABLE representantes_aprendiz ADD COLUMN sede_id BIGINT;
+ALTER TABLE representantes_aprendiz ADD COLUMN ficha_id BIGINT;
+CREATE INDEX idx_representantes_aprendiz_ficha_id ON representantes_aprendiz (ficha_id);
@@ -0,0 +1,434 @@
+// Package migrator aplica migraciones de esquema versionadas y registra cuáles se aplicaron en schema_migrations.
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DirectivaSinTransaccion primera línea de un .up.sql o .down.sql que no puede ir en una transacción (p. ej.
+// CREATE INDEX CONCURRENTLY).
+const DirectivaSinTransaccion = "-- migrate:sin-transaccion"
+
+// lockMigraciones clave del advisory lock de Postgres: dos procesos no aplican migraciones a la vez.
+const lockMigraciones int64 = 72657102
+
+var (
+	ErrChecksumDiferente   = errors.New("migración aplicada modificada: el checksum no coincide")
+	ErrMigracionHuerfana   = errors.New("migración aplicada que no existe en el código")
+	ErrFueraDeOrden        = errors.New("migración pendiente anterior a la última aplicada")
+	ErrSinReversion        = errors.New("la migración no tiene reversión")
+	ErrVersionDuplicada    = errors.New("versión de migración duplicada")
+	ErrArchivoNoReconocido = errors.New("archivo de migración con nombre no reconocido")
+	ErrGoSinFuente         = errors.New("migración Go sin Fuente: su código no entraría en el checksum")
+)
+
+// Migracion cambio de esquema identificado por su versión (AAAAMMDDhhmmss). Las SQL salen de archivos
+// <version>_<nombre>.up.sql y .down.sql; las Go definen Up y Down.
+type Migracion struct {
+	Version int64
+	Nombre  string
+	UpSQL   string
+	DownSQL string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+	// Fuente código de una migración Go (su archivo embebido con go:embed); entra en el checksum.
+	Fuente string
+	// SinTransaccion se ejecuta fuera de transacción; si falla a medias hay que revisar el esquema a mano.
+	SinTransaccion bool
+}
+
+// Reversible indica si la migración tiene Down.
+func (m Migracion) Reversible() bool {
+	return m.DownSQL != "" || m.Down != nil
+}
+
+// Checksum huella del Up. En las migraciones Go cubre versión, nombre y Fuente.
+func (m Migracion) Checksum() string {
+	contenido := m.UpSQL
+	if m.Up != nil {
+		contenido = fmt.Sprintf("go:%d:%s\n%s", m.Version, m.Nombre, m.Fuente)
+	}
+	sum := sha256.Sum256([]byte(contenido))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m Migracion) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Nombre)
+}
+
+// SchemaMigration fila de schema_migrations por cada migración aplicada.
+type SchemaMigration struct {
+	Version    int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Nombre     string    `gorm:"column:nombre;size:255;not null"`
+	Checksum   string    `gorm:"column:checksum;size:64;not null"`
+	AplicadaAt time.Time `gorm:"column:aplicada_at;not null"`
+	DuracionMs int64     `gorm:"column:duracion_ms;not null;default:0"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var nombreArchivo = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// LeerSQL migraciones de los archivos .sql de dir. Un .down.sql sin su .up.sql o un nombre que no siga el formato
+// es un error, para que un archivo mal nombrado no quede sin aplicar en silencio.
+func LeerSQL(fsys fs.FS, dir string) ([]Migracion, error) {
+	entradas, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	porVersion := map[int64]*Migracion{}
+	for _, e := range entradas {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		partes := nombreArchivo.FindStringSubmatch(e.Name())
+		if partes == nil {
+			return nil, fmt.Errorf("%w: %s (formato: AAAAMMDDhhmmss_nombre.up.sql)", ErrArchivoNoReconocido, e.Name())
+		}
+		version, _ := strconv.ParseInt(partes[1], 10, 64)
+		contenido, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m, ok := porVersion[version]
+		if !ok {
+			m = &Migracion{Version: version, Nombre: partes[2]}
+			porVersion[version] = m
+		}
+		if m.Nombre != partes[2] {
+			return nil, fmt.Errorf("%w: %d (%s y %s)", ErrVersionDuplicada, version, m.Nombre, partes[2])
+		}
+		sql := string(contenido)
+		sinTx := strings.HasPrefix(strings.TrimSpace(sql), DirectivaSinTransaccion)
+		if partes[3] == "up" {
+			m.UpSQL = sql
+			m.SinTransaccion = m.SinTransaccion || sinTx
+		} else {
+			m.DownSQL = sql
+		}
+	}
+	out := make([]Migracion, 0, len(porVersion))
+	for _, m := range porVersion {
+		if strings.TrimSpace(m.UpSQL) == "" {
+			return nil, fmt.Errorf("%w: %s no tiene .up.sql", ErrArchivoNoReconocido, m)
+		}
+		out = append(out, *m)
+	}
+	return out, nil
+}
+
+// Migrador aplica y revierte un conjunto de migraciones sobre la base.
+type Migrador struct {
+	db          *gorm.DB
+	migraciones []Migracion
+	logf        func(format string, args ...interface{})
+}
+
+// New ordena las migraciones por versión; dos con la misma versión o una Go sin Fuente son un error.
+func New(db *gorm.DB, migraciones []Migracion) (*Migrador, error) {
+	for _, m := range migraciones {
+		if m.Up != nil && m.Fuente == "" {
+			return nil, fmt.Errorf("%w: %s", ErrGoSinFuente, m)
+		}
+	}
+	ordenadas := append([]Migracion(nil), migraciones...)
+	sort.Slice(ordenadas, func(i, j int) bool { return ordenadas[i].Version < ordenadas[j].Version })
+	for i := 1; i < len(ordenadas); i++ {
+		if ordenadas[i].Version == ordenadas[i-1].Version {
+			return nil, fmt.Errorf("%w: %s y %s", ErrVersionDuplicada, ordenadas[i-1], ordenadas[i])
+		}
+	}
+	return &Migrador{db: db, migraciones: ordenadas, logf: log.Printf}, nil
+}
+
+// Estado situación de una migración frente a schema_migrations.
+type Estado struct {
+	Version  int64
+	Nombre   string
+	Aplicada *SchemaMigration
+	// Modificada el archivo cambió después de aplicarse.
+	Modificada bool
+	// Huerfana está aplicada pero no existe en el código (p. ej. una rama que no se fusionó).
+	Huerfana bool
+}
+
+// aplicadas filas de schema_migrations; sin la tabla (base nueva) no hay ninguna. Consultar no crea la tabla.
+func (m *Migrador) aplicadas(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[int64]SchemaMigration{}, nil
+	}
+	var list []SchemaMigration
+	if err := db.Order("version").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]SchemaMigration, len(list))
+	for _, a := range list {
+		out[a.Version] = a
+	}
+	return out, nil
+}
+
+// Estado de todas las migraciones, del código y huérfanas, por versión.
+func (m *Migrador) Estado() ([]Estado, error) {
+	aplicadas, err := m.aplicadas(m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.estado(aplicadas), nil
+}
+
+func (m *Migrador) estado(aplicadas map[int64]SchemaMigration) []Estado {
+	out := make([]Estado, 0, len(m.migraciones))
+	enCodigo := make(map[int64]bool, len(m.migraciones))
+	for _, mig := range m.migraciones {
+		enCodigo[mig.Version] = true
+		e := Estado{Version: mig.Version, Nombre: mig.Nombre}
+		if a, ok := aplicadas[mig.Version]; ok {
+			e.Aplicada = &a
+			e.Modificada = a.Checksum != mig.Checksum()
+		}
+		out = append(out, e)
+	}
+	for v, a := range aplicadas {
+		if !enCodigo[v] {
+			out = append(out, Estado{Version: v, Nombre: a.Nombre, Aplicada: &a, Huerfana: true})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+// verificar falla si alguna migración aplicada cambió o ya no existe en el código.
+func verificar(estados []Estado) error {
+	var errs []error
+	for _, e := range estados {
+		switch {
+		case e.Huerfana:
+			errs = append(errs, fmt.Errorf("%w: %d_%s", ErrMigracionHuerfana, e.Version, e.Nombre))
+		case e.Modificada:
+			errs = append(errs, fmt.Errorf("%w: %d_%s", ErrChecksumDiferente, e.Version, e.Nombre))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Verificar compara las migraciones aplicadas con las del código.
+func (m *Migrador) Verificar() error {
+	estados, err := m.Estado()
+	if err != nil {
+		return err
+	}
+	return verificar(estados)
+}
+
+// Pendientes migraciones sin aplicar, en orden. Falla si una aplicada cambió o no existe en el código.
+func (m *Migrador) Pendientes() ([]Migracion, error) {
+	estados, err := m.Estado()
+	if err != nil {
+		return nil, err
+	}
+	if err := verificar(estados); err != nil {
+		return nil, err
+	}
+	return m.pendientes(estados), nil
+}
+
+func (m *Migrador) pendientes(estados []Estado) []Migracion {
+	aplicada := make(map[int64]bool, len(estados))
+	for _, e := range estados {
+		aplicada[e.Version] = e.Aplicada != nil
+	}
+	var out []Migracion
+	for _, mig := range m.migraciones {
+		if !aplicada[mig.Version] {
+			out = append(out, mig)
+		}
+	}
+	return out
+}
+
+// OpcionesUp opciones de Up.
+type OpcionesUp struct {
+	// Hasta última versión a aplicar (0 = todas).
+	Hasta int64
+	// DryRun solo informa qué se aplicaría.
+	DryRun bool
+	// FueraDeOrden permite aplicar una pendiente con versión menor a la última aplicada (p. ej. tras fusionar
+	// ramas); sin ella es un error para revisar el orden antes de aplicarla.
+	FueraDeOrden bool
+}
+
+// Up aplica las migraciones pendientes en orden, cada una en su transacción con su fila en schema_migrations.
+// Devuelve las aplicadas (o las que se aplicarían con DryRun).
+func (m *Migrador) Up(opts OpcionesUp) ([]Migracion, error) {
+	var hechas []Migracion
+	err := m.conLock(func(db *gorm.DB) error {
+		aplicadas, err := m.aplicadas(db)
+		if err != nil {
+			return err
+		}
+		estados := m.estado(aplicadas)
+		if err := verificar(estados); err != nil {
+			return err
+		}
+		if !opts.DryRun {
+			if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+				return err
+			}
+		}
+		var ultima int64
+		for v := range aplicadas {
+			if v > ultima {
+				ultima = v
+			}
+		}
+		for _, mig := range m.pendientes(estados) {
+			if opts.Hasta > 0 && mig.Version > opts.Hasta {
+				break
+			}
+			if mig.Version < ultima && !opts.FueraDeOrden {
+				return fmt.Errorf("%w: %s (última aplicada %d)", ErrFueraDeOrden, mig, ultima)
+			}
+			if !opts.DryRun {
+				if err := m.ejecutar(db, mig, true); err != nil {
+					return err
+				}
+			}
+			hechas = append(hechas, mig)
+		}
+		return nil
+	})
+	return hechas, err
+}
+
+// OpcionesDown opciones de Down.
+type OpcionesDown struct {
+	// Pasos migraciones a revertir, de la última hacia atrás (mínimo 1).
+	Pasos  int
+	DryRun bool
+}
+
+// Down revierte las últimas migraciones aplicadas. Una sin reversión detiene el proceso antes de tocarla.
+func (m *Migrador) Down(opts OpcionesDown) ([]Migracion, error) {
+	if opts.Pasos < 1 {
+		opts.Pasos = 1
+	}
+	var hechas []Migracion
+	err := m.conLock(func(db *gorm.DB) error {
+		aplicadas, err := m.aplicadas(db)
+		if err != nil {
+			return err
+		}
+		estados := m.estado(aplicadas)
+		if err := verificar(estados); err != nil {
+			return err
+		}
+		porVersion := make(map[int64]Migracion, len(m.migraciones))
+		for _, mig := range m.migraciones {
+			porVersion[mig.Version] = mig
+		}
+		for i := len(estados) - 1; i >= 0 && len(hechas) < opts.Pasos; i-- {
+			if estados[i].Aplicada == nil {
+				continue
+			}
+			mig := porVersion[estados[i].Version]
+			if !mig.Reversible() {
+				return fmt.Errorf("%w: %s", ErrSinReversion, mig)
+			}
+			if !opts.DryRun {
+				if err := m.ejecutar(db, mig, false); err != nil {
+					return err
+				}
+			}
+			hechas = append(hechas, mig)
+		}
+		return nil
+	})
+	return hechas, err
+}
+
+// ejecutar aplica (up) o revierte una migración y actualiza schema_migrations en la misma transacción.
+func (m *Migrador) ejecutar(db *gorm.DB, mig Migracion, up bool) error {
+	inicio := time.Now()
+	sentido := "revirtiendo"
+	if up {
+		sentido = "aplicando"
+	}
+	m.logf("[migraciones] %s %s", sentido, mig)
+	cambio := func(tx *gorm.DB) error {
+		if up {
+			if mig.Up != nil {
+				return mig.Up(tx)
+			}
+			return tx.Exec(mig.UpSQL).Error
+		}
+		if mig.Down != nil {
+			return mig.Down(tx)
+		}
+		return tx.Exec(mig.DownSQL).Error
+	}
+	registrar := func(tx *gorm.DB) error {
+		if !up {
+			return tx.Delete(&SchemaMigration{}, mig.Version).Error
+		}
+		return tx.Create(&SchemaMigration{
+			Version:    mig.Version,
+			Nombre:     mig.Nombre,
+			Checksum:   mig.Checksum(),
+			AplicadaAt: time.Now(),
+			DuracionMs: time.Since(inicio).Milliseconds(),
+		}).Error
+	}
+	var err error
+	if mig.SinTransaccion {
+		if err = cambio(db); err == nil {
+			err = registrar(db)
+		}
+	} else {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := cambio(tx); err != nil {
+				return err
+			}
+			return registrar(tx)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", sentido, mig, err)
+	}
+	m.logf("[migraciones] %s listo en %s", mig, time.Since(inicio).Round(time.Millisecond))
+	return nil
+}
+
+// conLock ejecuta fn en una sola conexión con el advisory lock de migraciones tomado (solo en Postgres), para que
+// dos réplicas o despliegues simultáneos no apliquen la misma migración.
+func (m *Migrador) conLock(fn func(db *gorm.DB) error) error {
+	if m.db.Dialector.Name() != "postgres" {
+		return fn(m.db)
+	}
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockMigraciones).Error; err != nil {
+			return err
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", lockMigraciones).Error; err != nil {
+				m.logf("[migraciones] liberando lock: %v", err)
+			}
+		}()
+		return fn(conn)
+	})
+}
@@ -0,0 +1,231 @@
+package migrator
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// :memory: es una base por conexión.
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func testMigrador(t *testing.T, db *gorm.DB, migs ...Migracion) *Migrador {
+	t.Helper()
+	m, err := New(db, migs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.logf = func(string, ...interface{}) {}
+	return m
+}
+
+var (
+	migTareas = Migracion{Version: 20260101000000, Nombre: "tareas",
+		UpSQL: "CREATE TABLE tareas (id INTEGER PRIMARY KEY)", DownSQL: "DROP TABLE tareas"}
+	migTitulo = Migracion{Version: 20260102000000, Nombre: "titulo",
+		UpSQL: "ALTER TABLE tareas ADD COLUMN titulo TEXT", DownSQL: "ALTER TABLE tareas DROP COLUMN titulo"}
+)
+
+func TestLeerSQL(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/20260101000000_tareas.up.sql":   {Data: []byte(migTareas.UpSQL)},
+		"sql/20260101000000_tareas.down.sql": {Data: []byte(migTareas.DownSQL)},
+		"sql/20260102000000_indice.up.sql":   {Data: []byte(DirectivaSinTransaccion + "\nCREATE INDEX i ON tareas (id)")},
+		"sql/README.md":                      {Data: []byte("no es una migración")},
+	}
+	migs, err := LeerSQL(fsys, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(nil, migs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.migraciones) != 2 {
+		t.Fatalf("migraciones = %v", m.migraciones)
+	}
+	if got := m.migraciones[0]; got.Nombre != "tareas" || !got.Reversible() || got.SinTransaccion {
+		t.Errorf("primera = %+v", got)
+	}
+	if got := m.migraciones[1]; got.Reversible() || !got.SinTransaccion {
+		t.Errorf("segunda = %+v", got)
+	}
+}
+
+func TestLeerSQL_archivosInvalidos(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"nombre sin formato": {"20260101_tareas.up.sql": {Data: []byte("SELECT 1")}},
+		"down sin up":        {"20260101000000_tareas.down.sql": {Data: []byte("SELECT 1")}},
+	}
+	for nombre, fsys := range cases {
+		if _, err := LeerSQL(fsys, "."); !errors.Is(err, ErrArchivoNoReconocido) {
+			t.Errorf("%s: err = %v", nombre, err)
+		}
+	}
+	dup := fstest.MapFS{
+		"20260101000000_tareas.up.sql": {Data: []byte("SELECT 1")},
+		"20260101000000_otra.up.sql":   {Data: []byte("SELECT 1")},
+	}
+	if _, err := LeerSQL(dup, "."); !errors.Is(err, ErrVersionDuplicada) {
+		t.Errorf("versión duplicada: err = %v", err)
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	db := testDB(t)
+	m := testMigrador(t, db, migTitulo, migTareas)
+
+	hechas, err := m.Up(OpcionesUp{})
+	if err != nil || len(hechas) != 2 || hechas[0].Version != migTareas.Version {
+		t.Fatalf("Up = %v, %v", hechas, err)
+	}
+	if !db.Migrator().HasColumn("tareas", "titulo") {
+		t.Fatal("falta la columna titulo")
+	}
+	if hechas, err := m.Up(OpcionesUp{}); err != nil || len(hechas) != 0 {
+		t.Fatalf("segundo Up = %v, %v", hechas, err)
+	}
+
+	hechas, err = m.Down(OpcionesDown{Pasos: 1})
+	if err != nil || len(hechas) != 1 || hechas[0].Version != migTitulo.Version {
+		t.Fatalf("Down = %v, %v", hechas, err)
+	}
+	if db.Migrator().HasColumn("tareas", "titulo") {
+		t.Fatal("la columna titulo no se revirtió")
+	}
+	pendientes, err := m.Pendientes()
+	if err != nil || len(pendientes) != 1 || pendientes[0].Version != migTitulo.Version {
+		t.Fatalf("Pendientes = %v, %v", pendientes, err)
+	}
+}
+
+func TestUp_dryRunNoCambiaNada(t *testing.T) {
+	db := testDB(t)
+	m := testMigrador(t, db, migTareas, migTitulo)
+
+	hechas, err := m.Up(OpcionesUp{DryRun: true})
+	if err != nil || len(hechas) != 2 {
+		t.Fatalf("Up dry-run = %v, %v", hechas, err)
+	}
+	if db.Migrator().HasTable("tareas") || db.Migrator().HasTable(&SchemaMigration{}) {
+		t.Fatal("dry-run modificó el esquema")
+	}
+}
+
+func TestUp_hasta(t *testing.T) {
+	m := testMigrador(t, testDB(t), migTareas, migTitulo)
+	hechas, err := m.Up(OpcionesUp{Hasta: migTareas.Version})
+	if err != nil || len(hechas) != 1 {
+		t.Fatalf("Up hasta = %v, %v", hechas, err)
+	}
+}
+
+func TestUp_fallaRevierteLaMigracion(t *testing.T) {
+	db := testDB(t)
+	rota := Migracion{Version: 20260103000000, Nombre: "rota", Up: func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE TABLE parcial (id INTEGER)").Error; err != nil {
+			return err
+		}
+		return errors.New("falla a mitad")
+	}, Fuente: "rota.go"}
+	m := testMigrador(t, db, migTareas, rota)
+
+	hechas, err := m.Up(OpcionesUp{})
+	if err == nil || len(hechas) != 1 {
+		t.Fatalf("Up = %v, %v", hechas, err)
+	}
+	if db.Migrator().HasTable("parcial") {
+		t.Error("el cambio de la migración fallida no se revirtió")
+	}
+	var n int64
+	db.Model(&SchemaMigration{}).Count(&n)
+	if n != 1 {
+		t.Errorf("schema_migrations = %d filas, want 1", n)
+	}
+}
+
+func TestVerificar_checksumYHuerfanas(t *testing.T) {
+	db := testDB(t)
+	if _, err := testMigrador(t, db, migTareas, migTitulo).Up(OpcionesUp{}); err != nil {
+		t.Fatal(err)
+	}
+
+	editada := migTitulo
+	editada.UpSQL = "ALTER TABLE tareas ADD COLUMN titulo VARCHAR(200)"
+	m := testMigrador(t, db, migTareas, editada)
+	if err := m.Verificar(); !errors.Is(err, ErrChecksumDiferente) {
+		t.Errorf("archivo editado: err = %v", err)
+	}
+	if _, err := m.Up(OpcionesUp{}); !errors.Is(err, ErrChecksumDiferente) {
+		t.Errorf("Up con archivo editado: err = %v", err)
+	}
+
+	if err := testMigrador(t, db, migTareas).Verificar(); !errors.Is(err, ErrMigracionHuerfana) {
+		t.Errorf("migración borrada: err = %v", err)
+	}
+}
+
+// Editar el código de una migración Go ya aplicada se detecta igual que un archivo SQL editado.
+func TestVerificar_checksumMigracionGo(t *testing.T) {
+	crear := func(tx *gorm.DB) error { return tx.Exec("CREATE TABLE go_tabla (id INTEGER)").Error }
+	if _, err := New(nil, []Migracion{{Version: 20260103000000, Nombre: "go", Up: crear}}); !errors.Is(err, ErrGoSinFuente) {
+		t.Fatalf("sin Fuente: err = %v", err)
+	}
+
+	db := testDB(t)
+	migGo := Migracion{Version: 20260103000000, Nombre: "go", Up: crear, Fuente: "package x // v1"}
+	if _, err := testMigrador(t, db, migTareas, migGo).Up(OpcionesUp{}); err != nil {
+		t.Fatal(err)
+	}
+	editada := migGo
+	editada.Fuente = "package x // v2"
+	if err := testMigrador(t, db, migTareas, editada).Verificar(); !errors.Is(err, ErrChecksumDiferente) {
+		t.Errorf("código editado: err = %v", err)
+	}
+}
+
+func TestUp_fueraDeOrden(t *testing.T) {
+	db := testDB(t)
+	if _, err := testMigrador(t, db, migTareas, migTitulo).Up(OpcionesUp{}); err != nil {
+		t.Fatal(err)
+	}
+	intermedia := Migracion{Version: 20260101120000, Nombre: "intermedia", UpSQL: "CREATE TABLE otra (id INTEGER)"}
+	m := testMigrador(t, db, migTareas, intermedia, migTitulo)
+
+	if _, err := m.Up(OpcionesUp{}); !errors.Is(err, ErrFueraDeOrden) {
+		t.Fatalf("err = %v, want ErrFueraDeOrden", err)
+	}
+	if hechas, err := m.Up(OpcionesUp{FueraDeOrden: true}); err != nil || len(hechas) != 1 {
+		t.Fatalf("Up fuera de orden = %v, %v", hechas, err)
+	}
+}
+
+func TestDown_sinReversionSeDetiene(t *testing.T) {
+	db := testDB(t)
+	base := Migracion{Version: 20251231000000, Nombre: "base", UpSQL: "CREATE TABLE base (id INTEGER)"}
+	m := testMigrador(t, db, base, migTareas)
+	if _, err := m.Up(OpcionesUp{}); err != nil {
+		t.Fatal(err)
+	}
+
+	hechas, err := m.Down(OpcionesDown{Pasos: 2})
+	if !errors.Is(err, ErrSinReversion) || len(hechas) != 1 {
+		t.Fatalf("Down = %v, %v", hechas, err)
+	}
+	if !db.Migrator().HasTable("base") {
+		t.Error("se revirtió una migración sin reversión")
+	}
+}
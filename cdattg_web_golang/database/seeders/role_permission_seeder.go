@@ -42,6 +42,12 @@ func RunRolePermissionSeeder(db *gorm.DB) error {
 	if err := seedEleccionPermissions(e); err != nil {
 		return err
 	}
+	if err := seedEvaluacionPermissions(e); err != nil {
+		return err
+	}
+	if err := seedAsistenciaQRPermissions(e); err != nil {
+		return err
+	}
 
 	if err := e.SavePolicy(); err != nil {
 		return err
@@ -162,6 +168,49 @@ func SyncEleccionPermissionsToRoles(db *gorm.DB) error {
 	return e.SavePolicy()
 }
 
+// seedEvaluacionPermissions juicios evaluativos: coordinación/admin e instructores (estos, solo en sus fichas).
+func seedEvaluacionPermissions(e *casbin.Enforcer) error {
+	perms := []string{"VER EVALUACION", "EVALUAR APRENDICES"}
+	for _, role := range []string{"ADMINISTRADOR", "COORDINADOR", "INSTRUCTOR"} {
+		if err := addPermissionsForObject(e, role, authz.ObjFicha, perms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncEvaluacionPermissionsToRoles idempotente para despliegues existentes.
+func SyncEvaluacionPermissionsToRoles(db *gorm.DB) error {
+	log.Println("Sincronizando permisos de evaluación...")
+	e, err := authz.GetEnforcer(db)
+	if err != nil {
+		return err
+	}
+	if err := seedEvaluacionPermissions(e); err != nil {
+		return err
+	}
+	return e.SavePolicy()
+}
+
+// seedAsistenciaQRPermissions autorregistro de asistencia escaneando el código rotativo de la sesión.
+func seedAsistenciaQRPermissions(e *casbin.Enforcer) error {
+	_, err := authz.AddPermissionForRole(e, "APRENDIZ", authz.ObjAsistencia, "REGISTRAR MI ASISTENCIA")
+	return err
+}
+
+// SyncAsistenciaQRPermissionsToRoles idempotente para despliegues existentes.
+func SyncAsistenciaQRPermissionsToRoles(db *gorm.DB) error {
+	log.Println("Sincronizando permisos de autorregistro de asistencia...")
+	e, err := authz.GetEnforcer(db)
+	if err != nil {
+		return err
+	}
+	if err := seedAsistenciaQRPermissions(e); err != nil {
+		return err
+	}
+	return e.SavePolicy()
+}
+
 // SyncAprendizPermissionsToRoles aplica permisos Casbin de aprendiz y sincroniza roles (idempotente).
 func SyncAprendizPermissionsToRoles(db *gorm.DB) error {
 	log.Println("Sincronizando permisos y roles de aprendiz...")
@@ -5,17 +5,23 @@ import (
 	"log"
 
 	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/database/migrations"
+	"github.com/sena/cdattg-web-golang/database/migrator"
 	"gorm.io/gorm"
 )
 
-// RunAll ejecuta migraciones y todos los seeders en orden (igual que cdattg_web DatabaseSeeder).
+// RunAll aplica las migraciones pendientes y ejecuta todos los seeders en orden (igual que cdattg_web DatabaseSeeder).
 func RunAll() error {
 	db := database.GetDB()
 	if db == nil {
 		return fmt.Errorf("base de datos no inicializada: ejecute database.Initialize() antes de los seeders")
 	}
 
-	if err := database.Migrate(); err != nil {
+	m, err := migrations.NewMigrador(db)
+	if err != nil {
+		return err
+	}
+	if _, err := m.Up(migrator.OpcionesUp{}); err != nil {
 		return err
 	}
 
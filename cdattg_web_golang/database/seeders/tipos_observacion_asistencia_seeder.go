@@ -7,27 +7,33 @@ import (
 	"gorm.io/gorm"
 )
 
+// PesoRiesgo por defecto: aporte de la observación al puntaje de riesgo de deserción (editable en el catálogo).
 var tiposObservacionAsistencia = []struct {
-	Codigo string
-	Nombre string
+	Codigo     string
+	Nombre     string
+	PesoRiesgo int
 }{
-	{"NO_UNIFORME", "No trajo uniforme"},
-	{"INASISTENCIA_JUSTIFICADA", "Inasistencia justificada"},
-	{"ABANDONO_FORMACION", "Abandono de formación"},
-	{"RETARDO", "Retardo"},
-	{"OTRO", "Otro"},
+	{"NO_UNIFORME", "No trajo uniforme", 0},
+	{"INASISTENCIA_JUSTIFICADA", "Inasistencia justificada", 0},
+	{"ABANDONO_FORMACION", "Abandono de formación", 3},
+	{"RETARDO", "Retardo", 1},
+	{"OTRO", "Otro", 0},
 }
 
 func RunTiposObservacionAsistenciaSeeder(db *gorm.DB) error {
 	log.Println("Ejecutando TiposObservacionAsistenciaSeeder...")
 	for _, t := range tiposObservacionAsistencia {
-		rec := models.TipoObservacionAsistencia{Codigo: t.Codigo, Nombre: t.Nombre, Activo: true}
+		peso := t.PesoRiesgo
+		rec := models.TipoObservacionAsistencia{Codigo: t.Codigo, Nombre: t.Nombre, Activo: true, PesoRiesgo: &peso}
 		if err := db.Where("codigo = ?", t.Codigo).FirstOrCreate(&rec).Error; err != nil {
 			return err
 		}
 		if rec.Nombre != t.Nombre {
 			_ = db.Model(&models.TipoObservacionAsistencia{}).Where("codigo = ?", t.Codigo).Update("nombre", t.Nombre).Error
 		}
+		if rec.PesoRiesgo == nil {
+			_ = db.Model(&models.TipoObservacionAsistencia{}).Where("codigo = ?", t.Codigo).Update("peso_riesgo", t.PesoRiesgo).Error
+		}
 	}
 	log.Println("TiposObservacionAsistenciaSeeder completado.")
 	return nil
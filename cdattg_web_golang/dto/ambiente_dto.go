@@ -32,3 +32,30 @@ type AmbienteListItem struct {
 	Status       bool   `json:"status"`
 }
 
+
+// AmbienteOcupacionBloque bloque horario de una ficha en el ambiente.
+type AmbienteOcupacionBloque struct {
+	FichaID        uint   `json:"ficha_id"`
+	FichaNumero    string `json:"ficha_numero"`
+	ProgramaNombre string `json:"programa_nombre"`
+	HoraInicio     string `json:"hora_inicio"`
+	HoraFin        string `json:"hora_fin"`
+	Conflicto      bool   `json:"conflicto"` // se solapa con otra ficha el mismo día
+}
+
+// AmbienteOcupacionDia ocupación de un día de la semana consultada.
+type AmbienteOcupacionDia struct {
+	DiaFormacionID uint                      `json:"dia_formacion_id"`
+	DiaNombre      string                    `json:"dia_nombre"`
+	Fecha          string                    `json:"fecha"` // YYYY-MM-DD
+	Bloques        []AmbienteOcupacionBloque `json:"bloques"`
+}
+
+// AmbienteOcupacionResponse grilla semanal (lunes a domingo) de ocupación del ambiente.
+type AmbienteOcupacionResponse struct {
+	AmbienteID   uint                   `json:"ambiente_id"`
+	SemanaInicio string                 `json:"semana_inicio"`
+	SemanaFin    string                 `json:"semana_fin"`
+	Dias         []AmbienteOcupacionDia `json:"dias"`
+	Conflictos   int                    `json:"conflictos"`
+}
@@ -36,6 +36,8 @@ type AsistenciaResponse struct {
 	IsFinished         bool       `json:"is_finished"`
 	Observaciones      string     `json:"observaciones"`
 	CantidadAprendices int        `json:"cantidad_aprendices,omitempty"`
+	// Nueva: EntrarTomarAsistencia creó la sesión en esta llamada (no la reutilizó).
+	Nueva bool `json:"nueva,omitempty"`
 }
 
 // AsistenciaAprendizRequest registrar ingreso
@@ -52,6 +54,37 @@ type AsistenciaIngresoPorDocumentoRequest struct {
 	Accion          string `json:"accion" binding:"omitempty,oneof=ingreso salida"`
 }
 
+// AsistenciaEvento mensaje del canal de asistencia en tiempo real. Registro solo se envía a quien sigue la lista en
+// vivo de la sesión; los dashboards reciben los metadatos para decidir qué recargar.
+type AsistenciaEvento struct {
+	Type                 string                      `json:"type"`
+	AsistenciaID         uint                        `json:"asistencia_id"`
+	InstructorFichaID    uint                        `json:"instructor_ficha_id,omitempty"`
+	FichaID              uint                        `json:"ficha_id,omitempty"`
+	FichaNumero          string                      `json:"ficha_numero,omitempty"`
+	SedeID               *uint                       `json:"sede_id,omitempty"`
+	RegionalID           *uint                       `json:"regional_id,omitempty"`
+	AsistenciaAprendizID uint                        `json:"asistencia_aprendiz_id,omitempty"`
+	AprendizID           uint                        `json:"aprendiz_id,omitempty"`
+	At                   time.Time                   `json:"at"`
+	Registro             *AsistenciaAprendizResponse `json:"registro,omitempty"`
+}
+
+// AsistenciaAutorregistroRequest el aprendiz registra ingreso/salida escaneando el código rotativo de la sesión.
+type AsistenciaAutorregistroRequest struct {
+	Codigo string `json:"codigo" binding:"required"`
+}
+
+// AsistenciaCodigoQRResponse código rotativo firmado que muestra el instructor en la sesión abierta.
+type AsistenciaCodigoQRResponse struct {
+	AsistenciaID      uint      `json:"asistencia_id"`
+	Codigo            string    `json:"codigo"`
+	SVG               string    `json:"svg"`
+	PeriodoSegundos   int       `json:"periodo_segundos"`
+	ExpiraAt          time.Time `json:"expira_at"` // cuando se debe mostrar el siguiente código
+	SegundosRestantes int       `json:"segundos_restantes"`
+}
+
 // AsistenciaAprendizObservacionesRequest actualizar observaciones de un registro de asistencia-aprendiz (texto libre + tipos predefinidos)
 type AsistenciaAprendizObservacionesRequest struct {
 	Observaciones      string `json:"observaciones"`
@@ -96,23 +129,26 @@ type AsistenciaAprendizResponse struct {
 
 // TipoObservacionAsistenciaItem ítem del catálogo para dropdown y respuesta
 type TipoObservacionAsistenciaItem struct {
-	ID     uint   `json:"id"`
-	Codigo string `json:"codigo"`
-	Nombre string `json:"nombre"`
+	ID         uint   `json:"id"`
+	Codigo     string `json:"codigo"`
+	Nombre     string `json:"nombre"`
+	PesoRiesgo int    `json:"peso_riesgo"`
 }
 
 // TipoObservacionAsistenciaCreateRequest para crear nuevos tipos de observación (superadmin)
 type TipoObservacionAsistenciaCreateRequest struct {
-	Codigo string `json:"codigo" binding:"required"`
-	Nombre string `json:"nombre" binding:"required"`
-	Activo *bool  `json:"activo"`
+	Codigo     string `json:"codigo" binding:"required"`
+	Nombre     string `json:"nombre" binding:"required"`
+	Activo     *bool  `json:"activo"`
+	PesoRiesgo *int   `json:"peso_riesgo" binding:"omitempty,min=0,max=5"` // aporte al riesgo de deserción
 }
 
 // TipoObservacionAsistenciaUpdateRequest para actualizar tipos de observación (superadmin/admin).
 type TipoObservacionAsistenciaUpdateRequest struct {
-	Codigo string `json:"codigo" binding:"required"`
-	Nombre string `json:"nombre" binding:"required"`
-	Activo *bool  `json:"activo"`
+	Codigo     string `json:"codigo" binding:"required"`
+	Nombre     string `json:"nombre" binding:"required"`
+	Activo     *bool  `json:"activo"`
+	PesoRiesgo *int   `json:"peso_riesgo" binding:"omitempty,min=0,max=5"` // aporte al riesgo de deserción
 }
 
 // AsistenciaAprendizEstadoRequest para ajustar estado/motivo de un registro de asistencia de aprendiz
@@ -194,10 +230,14 @@ type CasoBienestarItem struct {
 	AsistenciasEfectivas      int    `json:"asistencias_efectivas"`
 	Inasistencias             int    `json:"inasistencias"` // sin justificar (umbral de alerta)
 	InasistenciasJustificadas int    `json:"inasistencias_justificadas"`
+	// Caso de seguimiento sin cerrar del aprendiz, si bienestar ya lo abrió.
+	CasoID     *uint  `json:"caso_id,omitempty"`
+	CasoEstado string `json:"caso_estado,omitempty"`
 }
 
 // InasistenciaDetalleItem representa una fecha de sesión en la que el aprendiz no asistió.
 type InasistenciaDetalleItem struct {
+	AsistenciaIDs    []uint `json:"asistencia_ids"` // sesiones del día; las que se citan al presentar una excusa
 	Fecha            string `json:"fecha"` // YYYY-MM-DD
 	InstructorNombre string `json:"instructor_nombre,omitempty"`
 	Observaciones    string `json:"observaciones,omitempty"`
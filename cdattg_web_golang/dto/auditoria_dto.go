@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// Actor usuario que ejecuta una operación auditada y la IP desde la que lo hace
+type Actor struct {
+	UserID uint
+	IP     string
+}
+
+// AuditoriaCambio valor de un campo antes y después de la operación
+type AuditoriaCambio struct {
+	Antes   interface{} `json:"antes"`
+	Despues interface{} `json:"despues"`
+}
+
+// AuditoriaDetalle contenido JSON de registro_actividades.detalles
+type AuditoriaDetalle struct {
+	Antes   interface{}                `json:"antes,omitempty"`
+	Despues interface{}                `json:"despues,omitempty"`
+	Cambios map[string]AuditoriaCambio `json:"cambios,omitempty"`
+	Metodo  string                     `json:"metodo,omitempty"`
+	Ruta    string                     `json:"ruta,omitempty"`
+	Estado  int                        `json:"estado,omitempty"`
+}
+
+// RegistroActividadResponse fila del listado de auditoría
+type RegistroActividadResponse struct {
+	ID          uint        `json:"id"`
+	UserID      uint        `json:"user_id"`
+	UserEmail   string      `json:"user_email,omitempty"`
+	Accion      string      `json:"accion"`
+	Tabla       string      `json:"tabla"`
+	RegistroID  *uint       `json:"registro_id,omitempty"`
+	Detalles    interface{} `json:"detalles,omitempty"`
+	FechaAccion time.Time   `json:"fecha_accion"`
+	IPAddress   string      `json:"ip_address"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // LoginRequest representa la solicitud de login (Email puede ser correo, documento o celular)
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
@@ -8,20 +10,54 @@ type LoginRequest struct {
 
 // LoginResponse representa la respuesta de login
 type LoginResponse struct {
-	Token       string       `json:"token"`
-	Type        string       `json:"type"`
-	User        UserResponse `json:"user"`
-	Roles       []string     `json:"roles"`
-	Permissions []string     `json:"permissions"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	Type         string       `json:"type"`
+	ExpiresIn    int          `json:"expires_in"` // segundos de vida del access token
+	User         UserResponse `json:"user"`
+	Roles        []string     `json:"roles"`
+	Permissions  []string     `json:"permissions"`
+}
+
+// ClienteInfo datos del dispositivo que abre o renueva una sesión
+type ClienteInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// RefreshRequest solicitud de renovación del access token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse par de tokens emitido al renovar; el refresh token anterior deja de ser válido
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	Type         string `json:"type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// SesionResponse sesión abierta (un dispositivo) del usuario autenticado
+type SesionResponse struct {
+	ID          uint      `json:"id"`
+	UserAgent   string    `json:"user_agent"`
+	IP          string    `json:"ip"`
+	CreatedAt   time.Time `json:"created_at"`
+	UltimoUsoAt time.Time `json:"ultimo_uso_at"`
+	ExpiraAt    time.Time `json:"expira_at"`
+	Actual      bool      `json:"actual"`
 }
 
 // UserResponse representa la información del usuario
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Email     string `json:"email"`
-	FullName  string `json:"full_name"`
-	Status    bool   `json:"status"`
-	PersonaID *uint  `json:"persona_id"`
+	ID                  uint   `json:"id"`
+	Email               string `json:"email"`
+	FullName            string `json:"full_name"`
+	Status              bool   `json:"status"`
+	PersonaID           *uint  `json:"persona_id"`
+	EmailVerificado     bool   `json:"email_verificado"`
+	DebeCambiarPassword bool   `json:"debe_cambiar_password"` // el resto de la API responde 403 hasta que la cambie
 }
 
 // ChangePasswordRequest solicitud para cambiar contraseña (usuario autenticado)
@@ -29,3 +65,29 @@ type ChangePasswordRequest struct {
 	PasswordActual string `json:"password_actual" binding:"required"`
 	PasswordNueva  string `json:"password_nueva" binding:"required,min=6"`
 }
+
+// RecuperarPasswordRequest solicitud de enlace para restablecer la contraseña (correo, documento o celular)
+type RecuperarPasswordRequest struct {
+	Login string `json:"login" binding:"required"`
+}
+
+// RestablecerPasswordRequest nueva contraseña con el token recibido por correo
+type RestablecerPasswordRequest struct {
+	Token         string `json:"token" binding:"required"`
+	PasswordNueva string `json:"password_nueva" binding:"required,min=6"`
+}
+
+// VerificarEmailRequest token recibido por correo para confirmar la dirección
+type VerificarEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// LoginAccesoResponse intento de inicio de sesión sobre la cuenta del usuario
+type LoginAccesoResponse struct {
+	ID         uint      `json:"id"`
+	FechaLogin time.Time `json:"fecha_login"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Exitoso    bool      `json:"exitoso"`
+	Motivo     string    `json:"motivo,omitempty"`
+}
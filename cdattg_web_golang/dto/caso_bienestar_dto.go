@@ -0,0 +1,126 @@
+package dto
+
+import "time"
+
+// CasoBienestarCreateRequest abre un caso manualmente para un aprendiz
+type CasoBienestarCreateRequest struct {
+	AprendizID        uint   `json:"aprendiz_id" binding:"required"`
+	Motivo            string `json:"motivo" binding:"required,max=1000"`
+	ResponsableUserID *uint  `json:"responsable_user_id"`
+}
+
+// CasosBienestarAbrirRequest abre casos para aprendices del cálculo de inasistencias (mismos parámetros que el listado)
+type CasosBienestarAbrirRequest struct {
+	AprendizIDs       []uint `json:"aprendiz_ids" binding:"required,min=1,max=200"`
+	Dias              *int   `json:"dias"`
+	MinFallas         int    `json:"min_fallas"`
+	SedeID            *uint  `json:"sede_id"`
+	ResponsableUserID *uint  `json:"responsable_user_id"`
+}
+
+// CasosBienestarAbrirResponse casos creados y aprendices omitidos (ya tenían caso abierto o no superan el umbral)
+type CasosBienestarAbrirResponse struct {
+	Creados  []CasoBienestarResponse `json:"creados"`
+	Omitidos []CasoBienestarOmitido  `json:"omitidos"`
+}
+
+// CasoBienestarOmitido aprendiz para el que no se abrió caso
+type CasoBienestarOmitido struct {
+	AprendizID uint   `json:"aprendiz_id"`
+	Motivo     string `json:"motivo"`
+	CasoID     *uint  `json:"caso_id,omitempty"`
+}
+
+// CasoBienestarUpdateRequest estado y profesional asignado. Sin responsable_user_id el caso queda sin asignar.
+type CasoBienestarUpdateRequest struct {
+	Estado            string `json:"estado" binding:"required,oneof=abierto en_seguimiento cerrado"`
+	ResponsableUserID *uint  `json:"responsable_user_id"`
+	ObservacionCierre string `json:"observacion_cierre" binding:"max=1000"`
+}
+
+// CasoBienestarIntervencionRequest nota de una gestión sobre el caso
+type CasoBienestarIntervencionRequest struct {
+	Fecha       string `json:"fecha" binding:"required"` // YYYY-MM-DD
+	Tipo        string `json:"tipo" binding:"required,oneof=llamada reunion correo visita remision otro"`
+	Descripcion string `json:"descripcion" binding:"required,max=2000"`
+	Acuerdos    string `json:"acuerdos" binding:"max=1000"`
+}
+
+// CasoBienestarRecordatorioRequest próxima gestión a recordar al responsable
+type CasoBienestarRecordatorioRequest struct {
+	FechaRecordatorio time.Time `json:"fecha_recordatorio" binding:"required"`
+	Nota              string    `json:"nota" binding:"required,max=500"`
+}
+
+// CasoBienestarResponse caso de seguimiento (listados e historial)
+type CasoBienestarResponse struct {
+	ID                        uint       `json:"id"`
+	AprendizID                uint       `json:"aprendiz_id"`
+	PersonaID                 uint       `json:"persona_id"`
+	AprendizNombre            string     `json:"aprendiz_nombre"`
+	NumeroDocumento           string     `json:"numero_documento"`
+	FichaID                   uint       `json:"ficha_id"`
+	FichaNumero               string     `json:"ficha_numero"`
+	Origen                    string     `json:"origen"`
+	Estado                    string     `json:"estado"`
+	Motivo                    string     `json:"motivo"`
+	ResponsableUserID         *uint      `json:"responsable_user_id,omitempty"`
+	ResponsableNombre         string     `json:"responsable_nombre,omitempty"`
+	PeriodoInicio             string     `json:"periodo_inicio,omitempty"` // YYYY-MM-DD
+	PeriodoFin                string     `json:"periodo_fin,omitempty"`
+	Inasistencias             int        `json:"inasistencias"`
+	InasistenciasJustificadas int        `json:"inasistencias_justificadas"`
+	TotalSesiones             int        `json:"total_sesiones"`
+	CreatedAt                 time.Time  `json:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at"`
+	CerradoAt                 *time.Time `json:"cerrado_at,omitempty"`
+	ObservacionCierre         string     `json:"observacion_cierre,omitempty"`
+}
+
+// CasoBienestarIntervencionResponse nota de gestión con su autor
+type CasoBienestarIntervencionResponse struct {
+	ID          uint      `json:"id"`
+	Fecha       string    `json:"fecha"` // YYYY-MM-DD
+	Tipo        string    `json:"tipo"`
+	Descripcion string    `json:"descripcion"`
+	Acuerdos    string    `json:"acuerdos,omitempty"`
+	AutorUserID uint      `json:"autor_user_id"`
+	AutorNombre string    `json:"autor_nombre,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CasoBienestarAdjuntoResponse metadatos de un adjunto (se descarga por su endpoint)
+type CasoBienestarAdjuntoResponse struct {
+	ID            uint      `json:"id"`
+	ArchivoNombre string    `json:"archivo_nombre"`
+	ArchivoTipo   string    `json:"archivo_tipo"`
+	ArchivoTamano int64     `json:"archivo_tamano"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CasoBienestarRecordatorioResponse recordatorio; en "mis recordatorios" incluye el aprendiz y la ficha del caso
+type CasoBienestarRecordatorioResponse struct {
+	ID                uint       `json:"id"`
+	CasoID            uint       `json:"caso_id"`
+	FechaRecordatorio time.Time  `json:"fecha_recordatorio"`
+	Nota              string     `json:"nota"`
+	CompletadoAt      *time.Time `json:"completado_at,omitempty"`
+	NotificadoAt      *time.Time `json:"notificado_at,omitempty"`
+	AprendizNombre    string     `json:"aprendiz_nombre,omitempty"`
+	FichaNumero       string     `json:"ficha_numero,omitempty"`
+}
+
+// CasoBienestarDetalleResponse caso con su bitácora completa
+type CasoBienestarDetalleResponse struct {
+	CasoBienestarResponse
+	Intervenciones []CasoBienestarIntervencionResponse `json:"intervenciones"`
+	Adjuntos       []CasoBienestarAdjuntoResponse      `json:"adjuntos"`
+	Recordatorios  []CasoBienestarRecordatorioResponse `json:"recordatorios"`
+}
+
+// ProfesionalBienestarItem usuario al que se puede asignar un caso
+type ProfesionalBienestarItem struct {
+	UserID uint   `json:"user_id"`
+	Nombre string `json:"nombre"`
+	Email  string `json:"email"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// CorreoSalienteAdjuntoResponse adjunto de un correo de la cola (sin contenido; se descarga aparte).
+type CorreoSalienteAdjuntoResponse struct {
+	ID          uint   `json:"id"`
+	Nombre      string `json:"nombre"`
+	ContentType string `json:"content_type"`
+	Tamano      int64  `json:"tamano"`
+}
+
+// CorreoSalienteResponse correo de la cola de salida para la vista de administración.
+type CorreoSalienteResponse struct {
+	ID               uint       `json:"id"`
+	Destinatarios    []string   `json:"destinatarios"`
+	Asunto           string     `json:"asunto"`
+	Plantilla        string     `json:"plantilla"`
+	Estado           string     `json:"estado"` // pendiente, enviado, fallido
+	Intentos         int        `json:"intentos"`
+	ProximoIntentoAt *time.Time `json:"proximo_intento_at,omitempty"`
+	UltimoError      string     `json:"ultimo_error,omitempty"`
+	EnviadoAt        *time.Time `json:"enviado_at,omitempty"`
+	Sensible         bool       `json:"sensible"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// CorreoSalienteDetalleResponse correo con su cuerpo y adjuntos. Los correos sensibles (enlaces de un solo uso)
+// no incluyen el cuerpo.
+type CorreoSalienteDetalleResponse struct {
+	CorreoSalienteResponse
+	CuerpoHTML  string                          `json:"cuerpo_html,omitempty"`
+	CuerpoTexto string                          `json:"cuerpo_texto,omitempty"`
+	Adjuntos    []CorreoSalienteAdjuntoResponse `json:"adjuntos"`
+}
+
+// CorreosSalientesResumen total de correos por estado y si el envío SMTP está activo.
+type CorreosSalientesResumen struct {
+	SMTPHabilitado bool  `json:"smtp_habilitado"`
+	MaxIntentos    int   `json:"max_intentos"`
+	Pendientes     int64 `json:"pendientes"`
+	Enviados       int64 `json:"enviados"`
+	Fallidos       int64 `json:"fallidos"`
+}
@@ -69,6 +69,8 @@ type DashboardRegionalStats struct {
 type DashboardRiesgoStats struct {
 	CasosBienestar       int `json:"casos_bienestar"`
 	PendientesRevision   int `json:"pendientes_revision"`
+	// Aprendices con puntaje de riesgo de deserción alto en los últimos 30 días
+	RiesgoDesercionAlto int `json:"riesgo_desercion_alto"`
 }
 
 type DashboardAlcance struct {
@@ -6,11 +6,17 @@ type EleccionProcesoRequest struct {
 	RegionalID             uint       `json:"regional_id" binding:"required"`
 	Anio                   int        `json:"anio" binding:"required"`
 	NombreCiclo            string     `json:"nombre_ciclo" binding:"required"`
+	Ambito                 string     `json:"ambito" binding:"omitempty,oneof=regional sede ficha"` // vacío: regional
+	SedeID                 *uint      `json:"sede_id"`
+	FichaID                *uint      `json:"ficha_id"`
+	CargoTitular           string     `json:"cargo_titular"`
+	CargoSuplente          string     `json:"cargo_suplente"`
 	FechaInscripcionInicio *time.Time `json:"fecha_inscripcion_inicio"`
 	FechaInscripcionFin    *time.Time `json:"fecha_inscripcion_fin"`
 	FechaVotacionInicio    *time.Time `json:"fecha_votacion_inicio"`
 	FechaVotacionFin       *time.Time `json:"fecha_votacion_fin"`
 	MinDiasMatricula       *int       `json:"min_dias_matricula"`
+	AutoTransiciones       bool       `json:"auto_transiciones"`
 }
 
 type EleccionProcesoResponse struct {
@@ -19,12 +25,21 @@ type EleccionProcesoResponse struct {
 	RegionalNombre         string     `json:"regional_nombre,omitempty"`
 	Anio                   int        `json:"anio"`
 	NombreCiclo            string     `json:"nombre_ciclo"`
+	Ambito                 string     `json:"ambito"`
+	SedeID                 *uint      `json:"sede_id,omitempty"`
+	SedeNombre             string     `json:"sede_nombre,omitempty"`
+	FichaID                *uint      `json:"ficha_id,omitempty"`
+	FichaNumero            string     `json:"ficha_numero,omitempty"`
+	CargoTitular           string     `json:"cargo_titular"`
+	CargoSuplente          string     `json:"cargo_suplente"`
 	Estado                 string     `json:"estado"`
 	FechaInscripcionInicio *time.Time `json:"fecha_inscripcion_inicio,omitempty"`
 	FechaInscripcionFin    *time.Time `json:"fecha_inscripcion_fin,omitempty"`
 	FechaVotacionInicio    *time.Time `json:"fecha_votacion_inicio,omitempty"`
 	FechaVotacionFin       *time.Time `json:"fecha_votacion_fin,omitempty"`
 	MinDiasMatricula       *int       `json:"min_dias_matricula,omitempty"`
+	AutoTransiciones       bool       `json:"auto_transiciones"`
+	CierreVotacionAt       *time.Time `json:"cierre_votacion_at,omitempty"` // fecha_votacion_fin más la gracia
 	PlanchasConfirmadas    int        `json:"planchas_confirmadas,omitempty"`
 	VotosRegistrados       int        `json:"votos_registrados,omitempty"`
 	AprendicesElegibles    int        `json:"aprendices_elegibles,omitempty"`
@@ -60,11 +75,21 @@ type EleccionVotoRequest struct {
 	PlanchaID uint `json:"plancha_id" binding:"required"`
 }
 
+// EleccionVotoResponse confirmación al votante. CodigoRecibo solo se entrega aquí; no se puede recuperar después.
 type EleccionVotoResponse struct {
-	ProcesoID   uint      `json:"proceso_id"`
-	PlanchaID   uint      `json:"plancha_id"`
-	VotanteNombre string  `json:"votante_nombre"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ProcesoID     uint      `json:"proceso_id"`
+	PlanchaID     uint      `json:"plancha_id"`
+	VotanteNombre string    `json:"votante_nombre"`
+	CodigoRecibo  string    `json:"codigo_recibo"`
+	VotadoAt      time.Time `json:"votado_at"`
+}
+
+// EleccionReciboResponse verificación de un código de recibo contra las papeletas del proceso: solo si está
+// en la urna, nunca por qué plancha.
+type EleccionReciboResponse struct {
+	ProcesoID  uint   `json:"proceso_id"`
+	ReciboHash string `json:"recibo_hash"`
+	Valido     bool   `json:"valido"`
 }
 
 type EleccionResultadoPlanchaConteo struct {
@@ -81,23 +106,123 @@ type EleccionResultadoResponse struct {
 	ParticipacionPct  float64                          `json:"participacion_pct"`
 	Empate            bool                             `json:"empate"`
 	NotaDesempate     *string                          `json:"nota_desempate,omitempty"`
+	DesempateMetodo   *string                          `json:"desempate_metodo,omitempty"`
+	DesempateAt       *time.Time                       `json:"desempate_at,omitempty"`
+	ActaHash          *string                          `json:"acta_hash,omitempty"`
 	Conteo            []EleccionResultadoPlanchaConteo `json:"conteo"`
-	Votos             []EleccionVotoAuditoriaItem      `json:"votos,omitempty"`
+	Auditoria         *EleccionAuditoriaResumen        `json:"auditoria,omitempty"`
+	Participantes     []EleccionParticipanteItem       `json:"participantes,omitempty"`
+	Papeletas         []EleccionPapeletaAuditoriaItem  `json:"papeletas,omitempty"`
+}
+
+// EleccionAuditoriaResumen cuadre entre participaciones (quién votó) y papeletas (votos anónimos).
+type EleccionAuditoriaResumen struct {
+	Participaciones int  `json:"participaciones"`
+	Papeletas       int  `json:"papeletas"`
+	Consistente     bool `json:"consistente"`
 }
 
-type EleccionVotoAuditoriaItem struct {
+// EleccionParticipanteItem aprendiz que votó (sin la plancha elegida).
+type EleccionParticipanteItem struct {
 	VotanteNombre string    `json:"votante_nombre"`
 	VotanteDoc    string    `json:"votante_documento,omitempty"`
-	PlanchaID     uint      `json:"plancha_id"`
-	PlanchaLabel  string    `json:"plancha_label"`
 	VotadoAt      time.Time `json:"votado_at"`
 }
 
+// EleccionPapeletaAuditoriaItem papeleta anónima identificada solo por el hash de su recibo.
+type EleccionPapeletaAuditoriaItem struct {
+	ReciboHash   string `json:"recibo_hash"`
+	PlanchaID    uint   `json:"plancha_id"`
+	PlanchaLabel string `json:"plancha_label"`
+}
+
+// EleccionDesempateRequest decisión sobre un empate: la plancha elegida debe estar entre las empatadas.
 type EleccionDesempateRequest struct {
 	PlanchaGanadoraID uint   `json:"plancha_ganadora_id" binding:"required"`
+	Metodo            string `json:"metodo" binding:"required,oneof=sorteo comite acuerdo"`
 	NotaDesempate     string `json:"nota_desempate" binding:"required"`
 }
 
+// EleccionActaVerificacionResponse resultado público de verificar un acta por su hash. No incluye documentos ni
+// datos de votantes.
+type EleccionActaVerificacionResponse struct {
+	Hash            string    `json:"hash"`
+	ProcesoID       uint      `json:"proceso_id"`
+	NombreCiclo     string    `json:"nombre_ciclo"`
+	Anio            int       `json:"anio"`
+	Regional        string    `json:"regional"`
+	GeneradaAt      time.Time `json:"generada_at"`
+	VotosTotales    int       `json:"votos_totales"`
+	PlanchaGanadora string    `json:"plancha_ganadora,omitempty"`
+	// ContenidoIntegro el contenido guardado del acta sigue correspondiendo a su hash.
+	ContenidoIntegro bool `json:"contenido_integro"`
+	// CoincideConUrna el conteo actual de las papeletas es el mismo que registra el acta.
+	CoincideConUrna bool `json:"coincide_con_urna"`
+	Valida          bool `json:"valida"`
+}
+
+// EleccionParticipacionGrupo elegibles y votantes de una sede, programa o ficha.
+type EleccionParticipacionGrupo struct {
+	ID               uint    `json:"id"`
+	Nombre           string  `json:"nombre"`
+	Elegibles        int     `json:"elegibles"`
+	Votaron          int     `json:"votaron"`
+	ParticipacionPct float64 `json:"participacion_pct"`
+}
+
+// EleccionParticipacionHora votos registrados en una hora (hora local de inicio) y el acumulado hasta ella.
+type EleccionParticipacionHora struct {
+	Hora      time.Time `json:"hora"`
+	Votos     int       `json:"votos"`
+	Acumulado int       `json:"acumulado"`
+}
+
+// EleccionParticipacionResponse participación en vivo del proceso. Solo cuenta constancias de participación:
+// no revela por quién votó nadie.
+type EleccionParticipacionResponse struct {
+	ProcesoID        uint    `json:"proceso_id"`
+	EstadoProceso    string  `json:"estado_proceso"`
+	Elegibles        int     `json:"elegibles"`
+	Votaron          int     `json:"votaron"`
+	Pendientes       int     `json:"pendientes"`
+	ParticipacionPct float64 `json:"participacion_pct"`
+	// VotantesFueraDePadron votaron pero ya no figuran como aprendices activos de la regional (retiro o traslado).
+	VotantesFueraDePadron int                          `json:"votantes_fuera_de_padron"`
+	PorSede               []EleccionParticipacionGrupo `json:"por_sede"`
+	PorPrograma           []EleccionParticipacionGrupo `json:"por_programa"`
+	PorFicha              []EleccionParticipacionGrupo `json:"por_ficha"`
+	PorHora               []EleccionParticipacionHora  `json:"por_hora"`
+	UltimoRecordatorioAt  *time.Time                   `json:"ultimo_recordatorio_at,omitempty"`
+	ActualizadoAt         time.Time                    `json:"actualizado_at"`
+}
+
+// EleccionRecordatorioRequest canales por los que se recuerda votar a quienes aún no lo hacen.
+type EleccionRecordatorioRequest struct {
+	Canales []string `json:"canales" binding:"required,min=1,dive,oneof=correo notificacion"`
+}
+
+type EleccionRecordatorioResponse struct {
+	Pendientes int `json:"pendientes"`
+	// Notificados aprendices con usuario a los que se envió el aviso en la aplicación (salvo quienes lo desactivaron).
+	Notificados int `json:"notificados"`
+	Correos     int `json:"correos"`
+	// SinCorreo pendientes sin correo entregable; solo se cuenta si se pidió el canal correo.
+	SinCorreo        int       `json:"sin_correo"`
+	CorreoHabilitado bool      `json:"correo_habilitado"`
+	EnviadoAt        time.Time `json:"enviado_at"`
+}
+
+// EleccionProcesoEventoResponse cambio de fase del proceso; sin usuario lo hizo el planificador.
+type EleccionProcesoEventoResponse struct {
+	ID             uint      `json:"id"`
+	EstadoAnterior string    `json:"estado_anterior"`
+	EstadoNuevo    string    `json:"estado_nuevo"`
+	Origen         string    `json:"origen"`
+	UserID         *uint     `json:"user_id,omitempty"`
+	Detalle        string    `json:"detalle,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
 type EleccionRechazarPlanchaRequest struct {
 	Motivo string `json:"motivo" binding:"required"`
 }
@@ -105,6 +230,13 @@ type EleccionRechazarPlanchaRequest struct {
 type RepresentanteAprendizResponse struct {
 	RegionalID       uint                    `json:"regional_id"`
 	RegionalNombre   string                  `json:"regional_nombre,omitempty"`
+	Ambito           string                  `json:"ambito"`
+	SedeID           *uint                   `json:"sede_id,omitempty"`
+	SedeNombre       string                  `json:"sede_nombre,omitempty"`
+	FichaID          *uint                   `json:"ficha_id,omitempty"`
+	FichaNumero      string                  `json:"ficha_numero,omitempty"`
+	CargoTitular     string                  `json:"cargo_titular,omitempty"`
+	CargoSuplente    string                  `json:"cargo_suplente,omitempty"`
 	ProcesoID        uint                    `json:"proceso_id"`
 	NombreCiclo      string                  `json:"nombre_ciclo,omitempty"`
 	Anio             int                     `json:"anio,omitempty"`
@@ -114,16 +246,24 @@ type RepresentanteAprendizResponse struct {
 	VigenciaHasta    *time.Time              `json:"vigencia_hasta,omitempty"`
 }
 
-type EleccionMiRegionalResponse struct {
-	RegionalID              uint                         `json:"regional_id"`
-	RegionalNombre          string                       `json:"regional_nombre,omitempty"`
-	Proceso                 *EleccionProcesoResponse     `json:"proceso,omitempty"`
-	RepresentantesVigentes  *RepresentanteAprendizResponse `json:"representantes_vigentes,omitempty"`
-	MiAprendizID            *uint                        `json:"mi_aprendiz_id,omitempty"`
-	PuedeVotar              bool                         `json:"puede_votar"`
-	PuedePostular           bool                         `json:"puede_postular"`
-	MiVotoPlanchaID         *uint                        `json:"mi_voto_plancha_id,omitempty"`
-	YaVoto                  bool                         `json:"ya_voto"`
-	EsCandidato             bool                         `json:"es_candidato"`
+// EleccionMiProcesoResponse un proceso en curso visto por el aprendiz: qué puede hacer en él.
+type EleccionMiProcesoResponse struct {
+	Proceso                     *EleccionProcesoResponse  `json:"proceso,omitempty"`
+	PuedeVotar                  bool                      `json:"puede_votar"`
+	PuedePostular               bool                      `json:"puede_postular"`
+	YaVoto                      bool                      `json:"ya_voto"`
+	EsCandidato                 bool                      `json:"es_candidato"`
 	PlanchasPendientesConfirmar []EleccionPlanchaResponse `json:"planchas_pendientes_confirmar,omitempty"`
 }
+
+// EleccionMiRegionalResponse proceso regional en curso (campos en la raíz, como antes) y, en OtrosProcesos, los
+// de la sede y la ficha del aprendiz.
+type EleccionMiRegionalResponse struct {
+	EleccionMiProcesoResponse
+	RegionalID             uint                           `json:"regional_id"`
+	RegionalNombre         string                         `json:"regional_nombre,omitempty"`
+	RepresentantesVigentes *RepresentanteAprendizResponse `json:"representantes_vigentes,omitempty"`
+	VocerosVigentes        *RepresentanteAprendizResponse `json:"voceros_vigentes,omitempty"`
+	MiAprendizID           *uint                          `json:"mi_aprendiz_id,omitempty"`
+	OtrosProcesos          []EleccionMiProcesoResponse    `json:"otros_procesos"`
+}
@@ -0,0 +1,84 @@
+package dto
+
+import "time"
+
+// EvaluacionRapItem RAP del programa en la matriz de evaluación
+type EvaluacionRapItem struct {
+	ID     uint   `json:"id"`
+	Codigo string `json:"codigo"`
+	Nombre string `json:"nombre"`
+}
+
+// EvaluacionCompetenciaItem competencia del programa con sus RAPs
+type EvaluacionCompetenciaItem struct {
+	ID     uint                `json:"id"`
+	Codigo string              `json:"codigo"`
+	Nombre string              `json:"nombre"`
+	Raps   []EvaluacionRapItem `json:"raps"`
+}
+
+// JuicioItem juicio de un aprendiz sobre un RAP (PENDIENTE si no se ha registrado)
+type JuicioItem struct {
+	RapID         uint       `json:"rap_id"`
+	Juicio        string     `json:"juicio"`
+	Observaciones string     `json:"observaciones,omitempty"`
+	FechaJuicio   *time.Time `json:"fecha_juicio,omitempty"`
+}
+
+// EvaluacionAprendizRow fila de la matriz: un aprendiz con un juicio por cada RAP del programa
+type EvaluacionAprendizRow struct {
+	AprendizID      uint         `json:"aprendiz_id"`
+	AprendizNombre  string       `json:"aprendiz_nombre"`
+	NumeroDocumento string       `json:"numero_documento"`
+	Juicios         []JuicioItem `json:"juicios"`
+	Aprobados       int          `json:"aprobados"`
+	NoAprobados     int          `json:"no_aprobados"`
+	Pendientes      int          `json:"pendientes"`
+}
+
+// EvaluacionResumen conteos de la ficha (aprendices x RAPs)
+type EvaluacionResumen struct {
+	TotalRaps       int `json:"total_raps"`
+	TotalAprendices int `json:"total_aprendices"`
+	Aprobados       int `json:"aprobados"`
+	NoAprobados     int `json:"no_aprobados"`
+	Pendientes      int `json:"pendientes"`
+}
+
+// EvaluacionFichaResponse matriz de evaluación de una ficha
+type EvaluacionFichaResponse struct {
+	FichaID        uint                        `json:"ficha_id"`
+	FichaNumero    string                      `json:"ficha_numero"`
+	ProgramaNombre string                      `json:"programa_nombre"`
+	FechaFin       *time.Time                  `json:"fecha_fin,omitempty"`
+	DiasParaFin    *int                        `json:"dias_para_fin,omitempty"`
+	AlertaCierre   bool                        `json:"alerta_cierre"`
+	Competencias   []EvaluacionCompetenciaItem `json:"competencias"`
+	Aprendices     []EvaluacionAprendizRow     `json:"aprendices"`
+	Resumen        EvaluacionResumen           `json:"resumen"`
+}
+
+// JuicioRegistroItem juicio a registrar para un aprendiz y RAP
+type JuicioRegistroItem struct {
+	AprendizID    uint   `json:"aprendiz_id" binding:"required"`
+	RapID         uint   `json:"rap_id" binding:"required"`
+	Juicio        string `json:"juicio" binding:"required,max=20"`
+	Observaciones string `json:"observaciones" binding:"max=1000"`
+}
+
+// JuiciosRegistroRequest registro en lote de juicios de una ficha
+type JuiciosRegistroRequest struct {
+	Juicios []JuicioRegistroItem `json:"juicios" binding:"required,min=1,dive"`
+}
+
+// EvaluacionAlertaItem ficha próxima a finalizar con RAPs sin juicio definitivo
+type EvaluacionAlertaItem struct {
+	FichaID        uint      `json:"ficha_id"`
+	FichaNumero    string    `json:"ficha_numero"`
+	ProgramaNombre string    `json:"programa_nombre"`
+	FechaFin       time.Time `json:"fecha_fin"`
+	DiasParaFin    int       `json:"dias_para_fin"`
+	Aprendices     int       `json:"aprendices"`
+	Raps           int       `json:"raps"`
+	Pendientes     int       `json:"pendientes"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// ExcusaCreateRequest campos del formulario multipart para presentar una excusa (el soporte va en "archivo")
+type ExcusaCreateRequest struct {
+	AsistenciaIDs []uint
+	Motivo        string
+}
+
+// ExcusaRevisionRequest decisión del instructor o de bienestar sobre una excusa pendiente
+type ExcusaRevisionRequest struct {
+	Estado      string `json:"estado" binding:"required,oneof=aprobada rechazada"`
+	Observacion string `json:"observacion" binding:"max=500"`
+}
+
+// ExcusaSesionItem sesión cubierta por la excusa
+type ExcusaSesionItem struct {
+	AsistenciaID     uint   `json:"asistencia_id"`
+	Fecha            string `json:"fecha"` // YYYY-MM-DD
+	InstructorNombre string `json:"instructor_nombre,omitempty"`
+}
+
+// ExcusaResponse excusa de inasistencia con sus sesiones
+type ExcusaResponse struct {
+	ID                  uint               `json:"id"`
+	AprendizID          uint               `json:"aprendiz_id"`
+	AprendizNombre      string             `json:"aprendiz_nombre"`
+	NumeroDocumento     string             `json:"numero_documento"`
+	FichaID             uint               `json:"ficha_id"`
+	FichaNumero         string             `json:"ficha_numero"`
+	Motivo              string             `json:"motivo"`
+	Estado              string             `json:"estado"`
+	ArchivoNombre       string             `json:"archivo_nombre"`
+	ArchivoTipo         string             `json:"archivo_tipo"`
+	ArchivoTamano       int64              `json:"archivo_tamano"`
+	CreatedAt           time.Time          `json:"created_at"`
+	RevisadoAt          *time.Time         `json:"revisado_at,omitempty"`
+	ObservacionRevision string             `json:"observacion_revision,omitempty"`
+	Sesiones            []ExcusaSesionItem `json:"sesiones"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// ImportacionTrabajoResponse trabajo de importación de Excel con su avance.
+type ImportacionTrabajoResponse struct {
+	ID         uint   `json:"id"`
+	Tipo       string `json:"tipo"`   // personas, instructores, ficha, programas
+	Estado     string `json:"estado"` // pendiente, procesando, completado, fallido
+	Filename   string `json:"filename"`
+	RegionalID *uint  `json:"regional_id,omitempty"`
+	// TotalFilas y FilaActual miden el avance; en programas cuentan códigos de programa, no filas.
+	TotalFilas   int        `json:"total_filas"`
+	FilaActual   int        `json:"fila_actual"`
+	Porcentaje   int        `json:"porcentaje"`
+	Procesados   int        `json:"procesados"`
+	Duplicados   int        `json:"duplicados"`
+	Errores      int        `json:"errores"`
+	Actualizados int        `json:"actualizados"`
+	Creados      int        `json:"creados"`
+	RedesCreadas int        `json:"redes_creadas"`
+	FichaID      *uint      `json:"ficha_id,omitempty"`
+	FichaCreada  bool       `json:"ficha_creada"`
+	Incidencias  int        `json:"incidencias"`
+	Intentos     int        `json:"intentos"`
+	UltimoError  string     `json:"ultimo_error,omitempty"`
+	Reanudable   bool       `json:"reanudable"`
+	IniciadoAt   *time.Time `json:"iniciado_at,omitempty"`
+	FinalizadoAt *time.Time `json:"finalizado_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -1,25 +1,40 @@
 package dto
 
+import "time"
+
 // InstructorAgendaEvent evento expandido en el calendario semanal.
 type InstructorAgendaEvent struct {
-	Fecha              string `json:"fecha"` // YYYY-MM-DD
-	DiaFormacionID     uint   `json:"dia_formacion_id"`
-	DiaNombre          string `json:"dia_nombre,omitempty"`
-	HoraInicio         string `json:"hora_inicio"`
-	HoraFin            string `json:"hora_fin"`
-	FichaID            uint   `json:"ficha_id"`
-	FichaNumero        string `json:"ficha_numero"`
-	ProgramaNombre     string `json:"programa_nombre,omitempty"`
-	SedeNombre         string `json:"sede_nombre,omitempty"`
-	AmbienteNombre     string `json:"ambiente_nombre,omitempty"`
-	InstructorID       uint   `json:"instructor_id,omitempty"`
-	InstructorNombre   string `json:"instructor_nombre,omitempty"`
+	Fecha               string `json:"fecha"` // YYYY-MM-DD
+	DiaFormacionID      uint   `json:"dia_formacion_id"`
+	DiaNombre           string `json:"dia_nombre,omitempty"`
+	HoraInicio          string `json:"hora_inicio"`
+	HoraFin             string `json:"hora_fin"`
+	FichaID             uint   `json:"ficha_id"`
+	FichaNumero         string `json:"ficha_numero"`
+	ProgramaNombre      string `json:"programa_nombre,omitempty"`
+	SedeNombre          string `json:"sede_nombre,omitempty"`
+	AmbienteNombre      string `json:"ambiente_nombre,omitempty"`
+	JornadaNombre       string `json:"jornada_nombre,omitempty"`
+	InstructorID        uint   `json:"instructor_id,omitempty"`
+	InstructorNombre    string `json:"instructor_nombre,omitempty"`
 	InstructorDocumento string `json:"instructor_documento,omitempty"`
+	// Cancelado sesión que cae en festivo o día sin formación de la sede; en el .ics sale con STATUS:CANCELLED.
+	Cancelado         bool   `json:"cancelado,omitempty"`
+	MotivoCancelacion string `json:"motivo_cancelacion,omitempty"`
 }
 
 // InstructorAgendaResponse respuesta de endpoints de agenda.
 type InstructorAgendaResponse struct {
-	Desde  string                  `json:"desde"`
-	Hasta  string                  `json:"hasta"`
+	Desde   string                  `json:"desde"`
+	Hasta   string                  `json:"hasta"`
 	Eventos []InstructorAgendaEvent `json:"eventos"`
 }
+
+// AgendaSuscripcionResponse estado del enlace de suscripción; URL y Token solo se devuelven al generarlo.
+type AgendaSuscripcionResponse struct {
+	Activa      bool       `json:"activa"`
+	URL         string     `json:"url,omitempty"`
+	Token       string     `json:"token,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UltimoUsoAt *time.Time `json:"ultimo_uso_at,omitempty"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// NotificacionUsuarioResponse notificación del centro de notificaciones.
+type NotificacionUsuarioResponse struct {
+	ID        uint       `json:"id"`
+	Tipo      string     `json:"tipo"`
+	Titulo    string     `json:"titulo"`
+	Mensaje   string     `json:"mensaje"`
+	Enlace    string     `json:"enlace,omitempty"`
+	LeidaEn   *time.Time `json:"leida_en,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificacionEvento mensaje del canal WebSocket de notificaciones. Type "nueva" trae la notificación; "leidas"
+// avisa que se marcaron como leídas (IDs vacío: todas). NoLeidas es el conteo actualizado del usuario.
+type NotificacionEvento struct {
+	Type         string                       `json:"type"`
+	Notificacion *NotificacionUsuarioResponse `json:"notificacion,omitempty"`
+	IDs          []uint                       `json:"ids,omitempty"`
+	NoLeidas     int64                        `json:"no_leidas"`
+}
+
+// NotificacionPreferenciaItem tipo de notificación y si el usuario la recibe.
+type NotificacionPreferenciaItem struct {
+	Tipo        string `json:"tipo"`
+	Nombre      string `json:"nombre"`
+	Descripcion string `json:"descripcion"`
+	Activo      bool   `json:"activo"`
+}
+
+// ActualizarNotificacionPreferenciaRequest activa o desactiva un tipo de notificación.
+type ActualizarNotificacionPreferenciaRequest struct {
+	Tipo   string `json:"tipo" binding:"required,oneof=asignacion_ficha traslado_dia eleccion_plancha inasistencia importacion"`
+	Activo *bool  `json:"activo" binding:"required"`
+}
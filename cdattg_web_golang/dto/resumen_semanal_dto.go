@@ -0,0 +1,22 @@
+package dto
+
+// ResumenSemanalPreferenciaItem resumen semanal por correo que aplica al usuario según sus roles.
+type ResumenSemanalPreferenciaItem struct {
+	Tipo        string `json:"tipo"` // coordinador, instructor
+	Nombre      string `json:"nombre"`
+	Descripcion string `json:"descripcion"`
+	Activo      bool   `json:"activo"`
+}
+
+// ResumenesSemanalesResponse preferencias del usuario y si puede recibir correos (correo entregable y SMTP activo).
+type ResumenesSemanalesResponse struct {
+	CorreoDisponible bool                            `json:"correo_disponible"`
+	Email            string                          `json:"email"`
+	Preferencias     []ResumenSemanalPreferenciaItem `json:"preferencias"`
+}
+
+// ActualizarResumenSemanalRequest suscribe o cancela un tipo de resumen semanal.
+type ActualizarResumenSemanalRequest struct {
+	Tipo   string `json:"tipo" binding:"required,oneof=coordinador instructor"`
+	Activo *bool  `json:"activo" binding:"required"`
+}
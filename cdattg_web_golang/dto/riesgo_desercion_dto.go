@@ -0,0 +1,55 @@
+package dto
+
+// RiesgoDesercionResponse aprendices ordenados por puntaje de riesgo de deserción (mayor primero)
+type RiesgoDesercionResponse struct {
+	DiasAnalizados int    `json:"dias_analizados"`
+	FechaInicio    string `json:"fecha_inicio"`
+	FechaFin       string `json:"fecha_fin"`
+	// Periodo anterior de igual duración con el que se calcula la tendencia
+	FechaInicioAnterior string                `json:"fecha_inicio_anterior"`
+	FechaFinAnterior    string                `json:"fecha_fin_anterior"`
+	Aprendices          []RiesgoDesercionItem `json:"aprendices"`
+}
+
+// RiesgoDesercionItem puntaje (0-100), nivel y factores de un aprendiz
+type RiesgoDesercionItem struct {
+	AprendizID      uint                    `json:"aprendiz_id"`
+	PersonaNombre   string                  `json:"persona_nombre"`
+	NumeroDocumento string                  `json:"numero_documento"`
+	FichaID         uint                    `json:"ficha_id"`
+	FichaNumero     string                  `json:"ficha_numero"`
+	ProgramaNombre  string                  `json:"programa_nombre,omitempty"`
+	SedeNombre      string                  `json:"sede_nombre"`
+	JornadaNombre   string                  `json:"jornada_nombre,omitempty"`
+	Puntaje         int                     `json:"puntaje"`
+	Nivel           string                  `json:"nivel"` // bajo, medio, alto
+	Factores        RiesgoDesercionFactores `json:"factores"`
+	Aportes         RiesgoDesercionAportes  `json:"aportes"`
+}
+
+// RiesgoDesercionFactores indicadores medidos en el periodo
+type RiesgoDesercionFactores struct {
+	TotalSesiones             int     `json:"total_sesiones"`
+	Inasistencias             int     `json:"inasistencias"`
+	InasistenciasJustificadas int     `json:"inasistencias_justificadas"`
+	PctInasistencia           float64 `json:"pct_inasistencia"`
+	// Nil si el aprendiz no tuvo sesiones en el periodo anterior
+	PctInasistenciaAnterior *float64 `json:"pct_inasistencia_anterior,omitempty"`
+	// Fallas sin justificar seguidas hasta la última sesión del periodo
+	FallasConsecutivas int `json:"fallas_consecutivas"`
+	Retardos           int `json:"retardos"`
+	SalidasParciales   int `json:"salidas_parciales"`
+	PesoObservaciones  int `json:"peso_observaciones"`
+	RapsNoAprobados    int `json:"raps_no_aprobados"`
+}
+
+// RiesgoDesercionAportes puntos que aporta cada factor al puntaje
+type RiesgoDesercionAportes struct {
+	Inasistencia       int `json:"inasistencia"`
+	FallasConsecutivas int `json:"fallas_consecutivas"`
+	Tendencia          int `json:"tendencia"`
+	Retardos           int `json:"retardos"`
+	SalidasParciales   int `json:"salidas_parciales"`
+	Observaciones      int `json:"observaciones"`
+	RapsNoAprobados    int `json:"raps_no_aprobados"`
+}
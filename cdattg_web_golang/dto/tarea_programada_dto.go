@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// TareaProgramadaEjecucionResponse una corrida del planificador.
+type TareaProgramadaEjecucionResponse struct {
+	ID           uint       `json:"id"`
+	Tarea        string     `json:"tarea"`
+	Origen       string     `json:"origen"`
+	Instancia    string     `json:"instancia"`
+	UserID       *uint      `json:"user_id,omitempty"`
+	IniciadaAt   time.Time  `json:"iniciada_at"`
+	FinalizadaAt *time.Time `json:"finalizada_at,omitempty"`
+	DuracionMs   int64      `json:"duracion_ms"`
+	Exitosa      bool       `json:"exitosa"`
+	Error        *string    `json:"error,omitempty"`
+}
+
+// TareaProgramadaResponse tarea registrada con su próxima y última ejecución.
+type TareaProgramadaResponse struct {
+	Nombre           string                            `json:"nombre"`
+	Descripcion      string                            `json:"descripcion"`
+	Expresion        string                            `json:"expresion"`
+	ProximaEjecucion *time.Time                        `json:"proxima_ejecucion,omitempty"`
+	EnCurso          bool                              `json:"en_curso"`
+	UltimaEjecucion  *TareaProgramadaEjecucionResponse `json:"ultima_ejecucion,omitempty"`
+}
+
+// TareasProgramadasResponse listado de tareas y estado de liderazgo de la instancia que responde.
+type TareasProgramadasResponse struct {
+	InstanciaLider bool                      `json:"instancia_lider"`
+	Tareas         []TareaProgramadaResponse `json:"tareas"`
+}
@@ -1,4 +1,9 @@
 #!/bin/sh
 set -e
 mkdir -p /app/storage/asistencia_pdfs
+# La API no modifica el esquema: aplica las migraciones pendientes antes de iniciarla (DB_MIGRATE_ON_START=false
+# para hacerlo aparte con /app/migrate up). Un advisory lock evita que dos réplicas migren a la vez.
+if [ "${DB_MIGRATE_ON_START:-true}" != "false" ]; then
+  /app/migrate up
+fi
 exec /app/cdattg-api
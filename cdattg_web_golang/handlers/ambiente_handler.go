@@ -3,21 +3,25 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/services"
+	"github.com/sena/cdattg-web-golang/utils"
 )
 
 const errMsgDatosInvalidos = "Datos inválidos"
 
 type AmbienteHandler struct {
-	svc services.AmbienteService
+	svc          services.AmbienteService
+	ocupacionSvc *services.AmbienteOcupacionService
 }
 
 func NewAmbienteHandler() *AmbienteHandler {
 	return &AmbienteHandler{
-		svc: services.NewAmbienteService(),
+		svc:          services.NewAmbienteService(),
+		ocupacionSvc: services.NewAmbienteOcupacionService(),
 	}
 }
 
@@ -76,6 +80,30 @@ func (h *AmbienteHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// Ocupacion grilla semanal de fichas que usan el ambiente. Query: fecha (YYYY-MM-DD, cualquier día de la semana; por defecto hoy).
+// @Router /api/infraestructura/ambientes/{id}/ocupacion [get]
+func (h *AmbienteHandler) Ocupacion(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	fecha := utils.Now()
+	if raw := c.Query("fecha"); raw != "" {
+		fecha, err = time.ParseInLocation(time.DateOnly, raw, utils.AppLocation())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fecha inválida (use YYYY-MM-DD)"})
+			return
+		}
+	}
+	res, err := h.ocupacionSvc.Ocupacion(id, fecha)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
 type SedeHandler struct {
 	svc services.SedeService
 }
@@ -11,25 +11,42 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/sena/cdattg-web-golang/authz"
 	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/middleware"
 	"github.com/sena/cdattg-web-golang/models"
-	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/services"
 	"github.com/sena/cdattg-web-golang/utils"
 )
 
+// Tipos de evento del canal de asistencia en tiempo real.
 const (
-	roleSuperAdminAsistenciaWS = "SUPER ADMINISTRADOR"
-	roleBienestarAsistenciaWS  = "BIENESTAR AL APRENDIZ"
+	EventoAsistenciaSesionAbierta   = "sesion_abierta"
+	EventoAsistenciaSesionCerrada   = "sesion_cerrada"
+	EventoAsistenciaIngreso         = "ingreso"
+	EventoAsistenciaSalida          = "salida"
+	EventoAsistenciaEstadoAjustado  = "estado_ajustado"
+	EventoAsistenciaRegistroBorrado = "registro_eliminado"
 )
 
-// AsistenciaDashboardHub mantiene las conexiones WebSocket del dashboard de asistencia (solo superadmin).
+// rolesAsistenciaDashboardWS pueden seguir el dashboard; el coordinador solo recibe eventos de las sedes de sus regionales.
+var rolesAsistenciaDashboardWS = []string{"SUPER ADMINISTRADOR", "ADMINISTRADOR", "BIENESTAR AL APRENDIZ", "COORDINADOR"}
+
+// AsistenciaDashboardHub mantiene las conexiones WebSocket de asistencia: dashboards (filtrados por alcance territorial)
+// y listas en vivo de una sesión (instructor).
 type AsistenciaDashboardHub struct {
 	mu      sync.RWMutex
 	clients map[*asistenciaDashboardClient]struct{}
+	// backend reenvía los eventos a las demás réplicas de la API; nil solo entrega a los clientes locales.
+	backend AsistenciaEventosBackend
 }
 
 type asistenciaDashboardClient struct {
 	conn *websocket.Conn
 	send chan []byte
+	// scope alcance del dashboard; nil sin restricción.
+	scope *services.DashboardScope
+	// asistenciaID distinto de cero: el cliente sigue solo la lista de esa sesión.
+	asistenciaID uint
 }
 
 var globalAsistenciaDashboardHub = &AsistenciaDashboardHub{
@@ -41,16 +58,55 @@ func GetAsistenciaDashboardHub() *AsistenciaDashboardHub {
 	return globalAsistenciaDashboardHub
 }
 
-// BroadcastRefresh envía a todos los clientes conectados un mensaje para que recarguen los datos.
-func (h *AsistenciaDashboardHub) BroadcastRefresh() {
-	msg, err := json.Marshal(map[string]string{"type": "refresh"})
+// recibe indica si el evento le corresponde al cliente.
+func (c *asistenciaDashboardClient) recibe(ev *dto.AsistenciaEvento) bool {
+	if c.asistenciaID != 0 {
+		return ev.AsistenciaID == c.asistenciaID
+	}
+	return c.scope.IncluyeSede(ev.SedeID)
+}
+
+// Publicar entrega el evento a los clientes locales y lo reenvía a las demás réplicas por el backend configurado.
+func (h *AsistenciaDashboardHub) Publicar(ev dto.AsistenciaEvento) {
+	h.entregar(ev)
+	h.mu.RLock()
+	backend := h.backend
+	h.mu.RUnlock()
+	if backend == nil {
+		return
+	}
+	if err := backend.Publicar(ev); err != nil {
+		log.Printf("[asistencia-dashboard-ws] reenviar %s a otras réplicas: %v", ev.Type, err)
+	}
+}
+
+// entregar envía el evento a los clientes locales cuyo alcance lo incluye. El registro del aprendiz solo viaja a la
+// lista en vivo de la sesión.
+func (h *AsistenciaDashboardHub) entregar(ev dto.AsistenciaEvento) {
+	completo, err := json.Marshal(ev)
 	if err != nil {
-		log.Printf("[asistencia-dashboard-ws] marshal refresh: %v", err)
+		log.Printf("[asistencia-dashboard-ws] marshal %s: %v", ev.Type, err)
 		return
 	}
+	resumen := completo
+	if ev.Registro != nil {
+		sinRegistro := ev
+		sinRegistro.Registro = nil
+		if resumen, err = json.Marshal(sinRegistro); err != nil {
+			log.Printf("[asistencia-dashboard-ws] marshal %s: %v", ev.Type, err)
+			return
+		}
+	}
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 	for c := range h.clients {
+		if !c.recibe(&ev) {
+			continue
+		}
+		msg := resumen
+		if c.asistenciaID != 0 {
+			msg = completo
+		}
 		select {
 		case c.send <- msg:
 		default:
@@ -91,57 +147,74 @@ var wsUpgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
-// authorizeAsistenciaDashboardWS valida token, usuario activo y roles permitidos.
-// Si no hay acceso, responde JSON y devuelve nil, false.
-func authorizeAsistenciaDashboardWS(c *gin.Context) (*models.User, bool) {
+// autenticarAsistenciaWS valida el token de la query y que el usuario esté activo; devuelve sus roles.
+// Si no hay acceso, responde JSON y devuelve nil, nil, false.
+func autenticarAsistenciaWS(c *gin.Context) (*models.User, []string, bool) {
 	token := c.Query("token")
 	if token == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "token requerido en query (?token=...)"})
-		return nil, false
+		return nil, nil, false
 	}
-	claims, err := utils.ValidateToken(token)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
-		return nil, false
+	user, _, msg := middleware.AutenticarToken(token)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": msg})
+		return nil, nil, false
 	}
-	userRepo := repositories.NewUserRepository()
-	user, err := userRepo.FindByID(claims.UserID)
-	if err != nil || user == nil || !user.Status {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no encontrado o inactivo"})
-		return nil, false
+	if user.DebeCambiarPassword {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Debe cambiar su contraseña antes de continuar", "code": middleware.CodigoDebeCambiarPassword})
+		return nil, nil, false
 	}
 	e, err := authz.GetEnforcer(database.GetDB())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error de autorización"})
-		return nil, false
+		return nil, nil, false
 	}
-	sub := strconv.FormatUint(uint64(user.ID), 10)
-	roles, err := authz.GetRolesForUser(e, sub)
+	roles, err := authz.GetRolesForUser(e, strconv.FormatUint(uint64(user.ID), 10))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error de autorización"})
+		return nil, nil, false
+	}
+	return user, roles, true
+}
+
+// authorizeAsistenciaDashboardWS valida token y rol, y resuelve el alcance territorial del usuario.
+func authorizeAsistenciaDashboardWS(c *gin.Context) (*services.DashboardScope, bool) {
+	user, roles, ok := autenticarAsistenciaWS(c)
+	if !ok {
+		return nil, false
+	}
+	if !tieneAlgunRolWS(roles, rolesAsistenciaDashboardWS) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Su rol no tiene acceso al dashboard de asistencia en tiempo real"})
+		return nil, false
+	}
+	scope, err := services.NewDashboardScopeService().Resolve(user.ID, roles)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo resolver el alcance del dashboard"})
 		return nil, false
 	}
+	return scope, true
+}
+
+func tieneAlgunRolWS(roles, permitidos []string) bool {
 	for _, r := range roles {
-		if r == roleSuperAdminAsistenciaWS || r == roleBienestarAsistenciaWS {
-			return user, true
+		for _, p := range permitidos {
+			if r == p {
+				return true
+			}
 		}
 	}
-	c.JSON(http.StatusForbidden, gin.H{"error": "Solo usuarios con rol SUPER ADMINISTRADOR o BIENESTAR AL APRENDIZ pueden acceder al dashboard en tiempo real"})
-	return nil, false
+	return false
 }
 
-// DashboardWebSocket maneja la conexión WebSocket del dashboard de asistencia. Solo superadmin. Token por query: ?token=xxx
-func DashboardWebSocket(c *gin.Context) {
-	_, ok := authorizeAsistenciaDashboardWS(c)
-	if !ok {
-		return
-	}
+// conectarAsistenciaWS hace el upgrade y registra el cliente en el hub.
+func conectarAsistenciaWS(c *gin.Context, client *asistenciaDashboardClient) {
 	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("[asistencia-dashboard-ws] upgrade: %v", err)
 		return
 	}
-	client := &asistenciaDashboardClient{conn: conn, send: make(chan []byte, 256)}
+	client.conn = conn
+	client.send = make(chan []byte, 256)
 	hub := GetAsistenciaDashboardHub()
 	hub.Register(client)
 	go client.Run(hub)
@@ -155,3 +228,74 @@ func DashboardWebSocket(c *gin.Context) {
 		}
 	}()
 }
+
+// DashboardWebSocket canal del dashboard de asistencia: eventos tipados filtrados por el alcance del usuario.
+// Token por query: ?token=xxx
+func DashboardWebSocket(c *gin.Context) {
+	scope, ok := authorizeAsistenciaDashboardWS(c)
+	if !ok {
+		return
+	}
+	conectarAsistenciaWS(c, &asistenciaDashboardClient{scope: scope})
+}
+
+// SesionWebSocket lista en vivo de una sesión: solo el instructor dueño de la sesión. Token por query: ?token=xxx
+func (h *AsistenciaHandler) SesionWebSocket(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	user, _, ok := autenticarAsistenciaWS(c)
+	if !ok {
+		return
+	}
+	asist, err := h.asistenciaRepo.FindContextoEvento(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "sesión no encontrada"})
+		return
+	}
+	var fichaID uint
+	if asist.InstructorFicha != nil {
+		fichaID = asist.InstructorFicha.FichaID
+	}
+	c.Set("user", user)
+	propia := h.getInstructorFichaIDForCurrentUser(c, fichaID)
+	if propia == nil || *propia != asist.InstructorFichaID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Solo el instructor de la sesión puede seguir su lista en vivo"})
+		return
+	}
+	conectarAsistenciaWS(c, &asistenciaDashboardClient{asistenciaID: asist.ID})
+}
+
+// publicarEventoAsistencia completa el evento con ficha, sede y regional de la sesión y lo envía al hub.
+func (h *AsistenciaHandler) publicarEventoAsistencia(tipo string, asistenciaID uint, registro *dto.AsistenciaAprendizResponse) {
+	ev := dto.AsistenciaEvento{Type: tipo, AsistenciaID: asistenciaID, At: utils.Now(), Registro: registro}
+	if registro != nil {
+		ev.AsistenciaAprendizID = registro.ID
+		ev.AprendizID = registro.AprendizID
+	}
+	if a, err := h.asistenciaRepo.FindContextoEvento(asistenciaID); err == nil {
+		ev.InstructorFichaID = a.InstructorFichaID
+		if a.InstructorFicha != nil && a.InstructorFicha.Ficha != nil {
+			f := a.InstructorFicha.Ficha
+			ev.FichaID = f.ID
+			ev.FichaNumero = f.Ficha
+			ev.SedeID = f.SedeID
+			if f.Sede != nil {
+				ev.RegionalID = f.Sede.RegionalID
+			}
+		}
+	} else {
+		log.Printf("[asistencia-dashboard-ws] contexto de la sesión %d: %v", asistenciaID, err)
+	}
+	GetAsistenciaDashboardHub().Publicar(ev)
+}
+
+// tipoEventoRegistro traduce la acción inferida en el registro por documento/QR al tipo de evento.
+func tipoEventoRegistro(resp *dto.AsistenciaAprendizResponse) string {
+	if resp != nil && resp.TipoRegistro == "salida" {
+		return EventoAsistenciaSalida
+	}
+	return EventoAsistenciaIngreso
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+func recibidoWS(t *testing.T, c *asistenciaDashboardClient) *dto.AsistenciaEvento {
+	t.Helper()
+	select {
+	case msg := <-c.send:
+		var ev dto.AsistenciaEvento
+		if err := json.Unmarshal(msg, &ev); err != nil {
+			t.Fatalf("mensaje inválido: %v", err)
+		}
+		return &ev
+	default:
+		return nil
+	}
+}
+
+func TestAsistenciaDashboardHubFiltraPorAlcanceYSesion(t *testing.T) {
+	sede1, sede2 := uint(1), uint(2)
+	global := &asistenciaDashboardClient{send: make(chan []byte, 4)}
+	coordinador := &asistenciaDashboardClient{
+		send:  make(chan []byte, 4),
+		scope: &services.DashboardScope{Restricted: true, SedeIDs: []uint{sede1}},
+	}
+	sesion := &asistenciaDashboardClient{send: make(chan []byte, 4), asistenciaID: 7}
+	hub := &AsistenciaDashboardHub{clients: map[*asistenciaDashboardClient]struct{}{
+		global: {}, coordinador: {}, sesion: {},
+	}}
+	registro := &dto.AsistenciaAprendizResponse{ID: 30, AsistenciaID: 7, AprendizID: 5, AprendizNombre: "Ana"}
+	hub.Publicar(dto.AsistenciaEvento{Type: EventoAsistenciaIngreso, AsistenciaID: 7, SedeID: &sede2, Registro: registro})
+
+	if ev := recibidoWS(t, global); ev == nil || ev.Registro != nil {
+		t.Fatalf("dashboard global: got %+v, want evento sin registro", ev)
+	}
+	if ev := recibidoWS(t, coordinador); ev != nil {
+		t.Fatalf("coordinador de otra sede no debe recibir el evento: %+v", ev)
+	}
+	if ev := recibidoWS(t, sesion); ev == nil || ev.Registro == nil || ev.Registro.AprendizNombre != "Ana" {
+		t.Fatalf("lista de la sesión: got %+v, want evento con registro", ev)
+	}
+
+	hub.Publicar(dto.AsistenciaEvento{Type: EventoAsistenciaSesionAbierta, AsistenciaID: 8, SedeID: &sede1})
+	if recibidoWS(t, coordinador) == nil || recibidoWS(t, global) == nil {
+		t.Fatal("dashboards con la sede en su alcance deben recibir el evento")
+	}
+	if ev := recibidoWS(t, sesion); ev != nil {
+		t.Fatalf("otra sesión no debe llegar a la lista en vivo: %+v", ev)
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/dto"
+	"gorm.io/gorm"
+)
+
+// Backends de difusión de eventos de asistencia (ASISTENCIA_EVENTOS_BACKEND).
+const (
+	BackendEventosMemoria  = "memoria"
+	BackendEventosPostgres = "postgres"
+)
+
+const (
+	// canalAsistenciaEventos canal de LISTEN/NOTIFY compartido por todas las réplicas.
+	canalAsistenciaEventos = "asistencia_eventos"
+	// maxPayloadNotify margen bajo el límite de 8000 bytes que Postgres admite en el payload de NOTIFY.
+	maxPayloadNotify = 7900
+	// reconexionEventosPostgres espera antes de volver a abrir la conexión de LISTEN tras un error.
+	reconexionEventosPostgres = 5 * time.Second
+)
+
+// AsistenciaEventosBackend reenvía los eventos de asistencia entre las réplicas de la API. Cada réplica entrega a sus
+// propios clientes WebSocket tanto lo que publica como lo que el backend le hace llegar de las demás.
+type AsistenciaEventosBackend interface {
+	// Publicar reenvía a las demás réplicas un evento que ya se entregó a los clientes locales.
+	Publicar(ev dto.AsistenciaEvento) error
+	// Escuchar recibe los eventos publicados por otras réplicas y los pasa a entregar hasta que ctx se cancele.
+	Escuchar(ctx context.Context, entregar func(dto.AsistenciaEvento))
+}
+
+// IniciarEventosAsistencia conecta el hub con el backend de ASISTENCIA_EVENTOS_BACKEND. Con "memoria" (por defecto)
+// los eventos no salen del proceso; con "postgres" se difunden por LISTEN/NOTIFY en la misma base de datos.
+func IniciarEventosAsistencia(ctx context.Context) {
+	nombre := nombreBackendEventos()
+	switch nombre {
+	case BackendEventosMemoria:
+		return
+	case BackendEventosPostgres:
+		db := database.GetDB()
+		if db == nil || config.AppConfig == nil {
+			log.Printf("[asistencia-eventos] backend postgres sin base de datos; los eventos quedan en esta réplica")
+			return
+		}
+		GetAsistenciaDashboardHub().UsarBackend(ctx, NewAsistenciaEventosPostgres(db, config.GetDSN()))
+		log.Printf("[asistencia-eventos] difusión entre réplicas por LISTEN/NOTIFY (%s)", canalAsistenciaEventos)
+	default:
+		log.Printf("[asistencia-eventos] backend %q desconocido; los eventos quedan en esta réplica", nombre)
+	}
+}
+
+// UsarBackend reemplaza el backend del hub y empieza a escuchar los eventos de las demás réplicas.
+func (h *AsistenciaDashboardHub) UsarBackend(ctx context.Context, backend AsistenciaEventosBackend) {
+	h.mu.Lock()
+	h.backend = backend
+	h.mu.Unlock()
+	if backend != nil {
+		go backend.Escuchar(ctx, h.entregar)
+	}
+}
+
+// asistenciaEventoNotify sobre enviado por NOTIFY; origen evita que la réplica que publicó entregue el evento dos veces.
+type asistenciaEventoNotify struct {
+	Origen string               `json:"origen"`
+	Evento dto.AsistenciaEvento `json:"evento"`
+}
+
+type asistenciaEventosPostgres struct {
+	db        *gorm.DB
+	dsn       string
+	instancia string
+}
+
+// NewAsistenciaEventosPostgres crea el backend de LISTEN/NOTIFY. Publica con el pool de GORM y escucha con una conexión
+// dedicada (LISTEN pertenece a la sesión que lo ejecutó, así que no puede compartirse con el pool).
+func NewAsistenciaEventosPostgres(db *gorm.DB, dsn string) AsistenciaEventosBackend {
+	return &asistenciaEventosPostgres{
+		db:        db,
+		dsn:       dsn,
+		instancia: instanciaEventosPostgres(),
+	}
+}
+
+// codificarEventoNotify arma el payload de NOTIFY. Si excede el límite, el evento viaja sin el registro del aprendiz
+// y la lista en vivo lo recarga al recibirlo.
+func codificarEventoNotify(origen string, ev dto.AsistenciaEvento) ([]byte, error) {
+	payload, err := json.Marshal(asistenciaEventoNotify{Origen: origen, Evento: ev})
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) <= maxPayloadNotify {
+		return payload, nil
+	}
+	if ev.Registro != nil {
+		ev.Registro = nil
+		return codificarEventoNotify(origen, ev)
+	}
+	return nil, fmt.Errorf("evento %s excede %d bytes", ev.Type, maxPayloadNotify)
+}
+
+func (b *asistenciaEventosPostgres) Publicar(ev dto.AsistenciaEvento) error {
+	payload, err := codificarEventoNotify(b.instancia, ev)
+	if err != nil {
+		return err
+	}
+	return b.db.Exec("SELECT pg_notify(?, ?)", canalAsistenciaEventos, string(payload)).Error
+}
+
+// Escuchar mantiene el LISTEN y reconecta tras cualquier error. Los eventos publicados mientras la conexión está caída
+// se pierden; los clientes los recuperan al recargar su vista.
+func (b *asistenciaEventosPostgres) Escuchar(ctx context.Context, entregar func(dto.AsistenciaEvento)) {
+	escucharCanalPostgres(ctx, b.dsn, canalAsistenciaEventos, func(payload string) {
+		var msg asistenciaEventoNotify
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			log.Printf("[asistencia-eventos] payload inválido: %v", err)
+			return
+		}
+		if msg.Origen == b.instancia {
+			return
+		}
+		entregar(msg.Evento)
+	})
+}
+
+// instanciaEventosPostgres identifica a esta réplica en los sobres de NOTIFY.
+func instanciaEventosPostgres() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// escucharCanalPostgres mantiene un LISTEN sobre canal con una conexión dedicada y reconecta tras cualquier error hasta
+// que ctx se cancele. Cada payload recibido se pasa a recibir.
+func escucharCanalPostgres(ctx context.Context, dsn, canal string, recibir func(payload string)) {
+	for {
+		err := escucharCanalPostgresUnaVez(ctx, dsn, canal, recibir)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[eventos-postgres] LISTEN %s: %v; reintento en %s", canal, err, reconexionEventosPostgres)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconexionEventosPostgres):
+		}
+	}
+}
+
+func escucharCanalPostgresUnaVez(ctx context.Context, dsn, canal string, recibir func(payload string)) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+canal); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		recibir(n.Payload)
+	}
+}
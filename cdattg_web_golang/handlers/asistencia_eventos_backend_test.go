@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sena/cdattg-web-golang/dto"
+)
+
+type backendEventosFalso struct {
+	publicados []dto.AsistenciaEvento
+	entregar   func(dto.AsistenciaEvento)
+	listo      chan struct{}
+	err        error
+}
+
+func (b *backendEventosFalso) Publicar(ev dto.AsistenciaEvento) error {
+	b.publicados = append(b.publicados, ev)
+	return b.err
+}
+
+func (b *backendEventosFalso) Escuchar(_ context.Context, entregar func(dto.AsistenciaEvento)) {
+	b.entregar = entregar
+	close(b.listo)
+}
+
+func TestAsistenciaDashboardHubReenviaYEntregaEventosDeOtrasReplicas(t *testing.T) {
+	local := &asistenciaDashboardClient{send: make(chan []byte, 4)}
+	hub := &AsistenciaDashboardHub{clients: map[*asistenciaDashboardClient]struct{}{local: {}}}
+	backend := &backendEventosFalso{listo: make(chan struct{}), err: errors.New("sin conexión")}
+	hub.UsarBackend(context.Background(), backend)
+	<-backend.listo
+
+	hub.Publicar(dto.AsistenciaEvento{Type: EventoAsistenciaSesionAbierta, AsistenciaID: 3})
+	if len(backend.publicados) != 1 {
+		t.Fatalf("publicados en el backend = %d, want 1", len(backend.publicados))
+	}
+	if ev := recibidoWS(t, local); ev == nil || ev.AsistenciaID != 3 {
+		t.Fatalf("el cliente local debe recibir el evento aunque el backend falle: %+v", ev)
+	}
+
+	backend.entregar(dto.AsistenciaEvento{Type: EventoAsistenciaSesionCerrada, AsistenciaID: 4})
+	if ev := recibidoWS(t, local); ev == nil || ev.Type != EventoAsistenciaSesionCerrada {
+		t.Fatalf("evento de otra réplica: got %+v", ev)
+	}
+	if len(backend.publicados) != 1 {
+		t.Fatal("un evento recibido de otra réplica no debe volver a publicarse")
+	}
+}
+
+func TestCodificarEventoNotifyRespetaLimite(t *testing.T) {
+	ev := dto.AsistenciaEvento{Type: EventoAsistenciaIngreso, AsistenciaID: 7, Registro: &dto.AsistenciaAprendizResponse{ID: 1, AprendizNombre: "Ana"}}
+	payload, err := codificarEventoNotify("a", ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg asistenciaEventoNotify
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Origen != "a" || msg.Evento.Registro == nil {
+		t.Fatalf("payload corto: got %+v (%v), want origen y registro", msg, err)
+	}
+
+	ev.Registro.AprendizNombre = strings.Repeat("x", maxPayloadNotify)
+	payload, err = codificarEventoNotify("a", ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) > maxPayloadNotify {
+		t.Fatalf("payload de %d bytes excede el límite", len(payload))
+	}
+	msg = asistenciaEventoNotify{}
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Evento.Registro != nil || msg.Evento.AsistenciaID != 7 {
+		t.Fatalf("payload largo: got %+v (%v), want evento sin registro", msg, err)
+	}
+}
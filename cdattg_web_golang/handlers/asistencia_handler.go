@@ -1,13 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sena/cdattg-web-golang/config"
-	"github.com/sena/cdattg-web-golang/database"
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
@@ -28,6 +28,8 @@ type AsistenciaHandler struct {
 	instFichaRepo  repositories.InstructorFichaRepository
 	asistenciaRepo repositories.AsistenciaRepository
 	repoAA         repositories.AsistenciaAprendizRepository
+	reporteSvc     *services.ReporteAsistenciaService
+	casoSvc        services.CasoBienestarService
 }
 
 func NewAsistenciaHandler() *AsistenciaHandler {
@@ -37,6 +39,8 @@ func NewAsistenciaHandler() *AsistenciaHandler {
 		instFichaRepo:  repositories.NewInstructorFichaRepository(),
 		asistenciaRepo: repositories.NewAsistenciaRepository(),
 		repoAA:         repositories.NewAsistenciaAprendizRepository(),
+		reporteSvc:     services.NewReporteAsistenciaService(),
+		casoSvc:        services.NewCasoBienestarService(),
 	}
 }
 
@@ -69,7 +73,7 @@ func (h *AsistenciaHandler) CreateSesion(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	GetAsistenciaDashboardHub().BroadcastRefresh()
+	h.publicarEventoAsistencia(EventoAsistenciaSesionAbierta, resp.ID, nil)
 	c.JSON(http.StatusCreated, resp)
 }
 
@@ -96,6 +100,9 @@ func (h *AsistenciaHandler) EntrarTomarAsistencia(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	if resp.Nueva {
+		h.publicarEventoAsistencia(EventoAsistenciaSesionAbierta, resp.ID, nil)
+	}
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -146,6 +153,10 @@ func (h *AsistenciaHandler) ListByFichaAndFechas(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "fecha_inicio y fecha_fin son requeridos"})
 		return
 	}
+	if c.Query("formato") != "" {
+		responderReporteConsolidado(c, h.reporteSvc, uint(fichaID), fechaInicio, fechaFin)
+		return
+	}
 	list, err := h.svc.ListByFichaIDAndFechas(uint(fichaID), fechaInicio, fechaFin)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -154,27 +165,6 @@ func (h *AsistenciaHandler) ListByFichaAndFechas(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": list})
 }
 
-// StartAsistenciaAutoFinalize inicia la goroutine que finaliza sesiones al terminar el horario de la jornada (más extensión).
-// Los instructores no pueden finalizar manualmente; la finalización es automática.
-// Sin DB inicializada (p. ej. tests de router) no hace nada para evitar panic en repositorios.
-func StartAsistenciaAutoFinalize(h *AsistenciaHandler) {
-	if database.GetDB() == nil {
-		return
-	}
-	run := func() {
-		h.svc.FinalizarSesionesVencidas()
-		GetAsistenciaDashboardHub().BroadcastRefresh()
-	}
-	run() // una vez al arranque
-	go func() {
-		for {
-			mins := services.IntervaloAutoCierreMinutos()
-			time.Sleep(time.Duration(mins) * time.Minute)
-			run()
-		}
-	}()
-}
-
 func (h *AsistenciaHandler) RegistrarIngreso(c *gin.Context) {
 	var req dto.AsistenciaAprendizRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -195,7 +185,7 @@ func (h *AsistenciaHandler) RegistrarIngreso(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	GetAsistenciaDashboardHub().BroadcastRefresh()
+	h.publicarEventoAsistencia(EventoAsistenciaIngreso, resp.AsistenciaID, resp)
 	c.JSON(http.StatusCreated, resp)
 }
 
@@ -219,7 +209,61 @@ func (h *AsistenciaHandler) RegistrarIngresoPorDocumento(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	GetAsistenciaDashboardHub().BroadcastRefresh()
+	h.publicarEventoAsistencia(tipoEventoRegistro(resp), resp.AsistenciaID, resp)
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CodigoQRSesion código rotativo (y su SVG) que el instructor muestra en la sesión abierta; el cliente lo vuelve a
+// pedir al vencer segundos_restantes.
+func (h *AsistenciaHandler) CodigoQRSesion(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	id := uint(id64)
+	var fichaID uint
+	if asist, err := h.asistenciaRepo.FindByID(id); err == nil && asist != nil {
+		if asist.InstructorFicha != nil {
+			fichaID = asist.InstructorFicha.FichaID
+		} else if ifc, _ := h.instFichaRepo.FindByID(asist.InstructorFichaID); ifc != nil {
+			fichaID = ifc.FichaID
+		}
+	}
+	resp, err := h.svc.CodigoQRSesion(id, h.getInstructorFichaIDForCurrentUser(c, fichaID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// RegistrarPorCodigoQR el aprendiz autenticado registra su ingreso o salida con el código rotativo de la sesión.
+func (h *AsistenciaHandler) RegistrarPorCodigoQR(c *gin.Context) {
+	personaID := personaIDFromContext(c)
+	if personaID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Su cuenta no está vinculada a una persona."})
+		return
+	}
+	var req dto.AsistenciaAutorregistroRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.RegistrarPorCodigoQR(*personaID, req.Codigo)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, services.ErrCodigoQRUsado):
+			status = http.StatusConflict
+		case errors.Is(err, services.ErrCodigoQRVencido):
+			status = http.StatusGone
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	h.publicarEventoAsistencia(tipoEventoRegistro(resp), resp.AsistenciaID, resp)
 	c.JSON(http.StatusCreated, resp)
 }
 
@@ -243,7 +287,7 @@ func (h *AsistenciaHandler) RegistrarSalida(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	GetAsistenciaDashboardHub().BroadcastRefresh()
+	h.publicarEventoAsistencia(EventoAsistenciaSalida, resp.AsistenciaID, resp)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -387,10 +431,18 @@ func (h *AsistenciaHandler) EliminarRegistroAprendiz(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
 		return
 	}
-	if err := h.svc.EliminarRegistroAprendiz(uint(id)); err != nil {
+	aa, _ := h.repoAA.FindByID(uint(id))
+	if err := h.svc.EliminarRegistroAprendiz(actorAuditoria(c), uint(id)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if aa != nil {
+		h.publicarEventoAsistencia(EventoAsistenciaRegistroBorrado, aa.AsistenciaID, &dto.AsistenciaAprendizResponse{
+			ID:           aa.ID,
+			AsistenciaID: aa.AsistenciaID,
+			AprendizID:   aa.AprendizFichaID,
+		})
+	}
 	c.Status(http.StatusNoContent)
 }
 
@@ -478,11 +530,12 @@ func (h *AsistenciaHandler) AjustarEstadoAprendiz(c *gin.Context) {
 		}
 	}
 	instructorFichaID := h.getInstructorFichaIDForCurrentUser(c, fichaID)
-	resp, err := h.svc.AjustarEstadoAprendiz(uint(id), req.Estado, req.Motivo, instructorFichaID)
+	resp, err := h.svc.AjustarEstadoAprendiz(actorAuditoria(c), uint(id), req.Estado, req.Motivo, instructorFichaID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	h.publicarEventoAsistencia(EventoAsistenciaEstadoAjustado, resp.AsistenciaID, resp)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -541,6 +594,7 @@ func (h *AsistenciaHandler) GetCasosBienestar(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.casoSvc.AnotarCasosAbiertos(resp.Casos)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/middleware"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/services"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+// actorAuditoria devuelve quién ejecuta la petición para los servicios que auditan con diff, y marca la
+// petición para que el middleware de auditoría no agregue además el registro genérico.
+func actorAuditoria(c *gin.Context) dto.Actor {
+	middleware.MarcarAuditoriaDetallada(c)
+	return dto.Actor{UserID: c.GetUint("userID"), IP: c.ClientIP()}
+}
+
+type AuditoriaHandler struct {
+	svc services.AuditoriaService
+}
+
+func NewAuditoriaHandler() *AuditoriaHandler {
+	return &AuditoriaHandler{svc: services.NewAuditoriaService()}
+}
+
+// List GET /api/auditoria?user_id=&accion=&tabla=&registro_id=&desde=YYYY-MM-DD&hasta=YYYY-MM-DD&page=&page_size=
+func (h *AuditoriaHandler) List(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if err != nil || pageSize < 1 || pageSize > 200 {
+		pageSize = 50
+	}
+	f := repositories.RegistroActividadFiltro{
+		Accion:   strings.TrimSpace(c.Query("accion")),
+		Tabla:    strings.TrimSpace(c.Query("tabla")),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	var ok bool
+	if f.UserID, ok = queryUintOpcional(c, "user_id"); !ok {
+		return
+	}
+	if f.RegistroID, ok = queryUintOpcional(c, "registro_id"); !ok {
+		return
+	}
+	if f.Desde, ok = queryFechaOpcional(c, "desde", 0); !ok {
+		return
+	}
+	// hasta inclusive: se filtra por < hasta + 1 día
+	if f.Hasta, ok = queryFechaOpcional(c, "hasta", 1); !ok {
+		return
+	}
+	list, total, err := h.svc.List(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":      list,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+func queryUintOpcional(c *gin.Context, name string) (*uint, bool) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return nil, true
+	}
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " inválido"})
+		return nil, false
+	}
+	id := uint(v)
+	return &id, true
+}
+
+func queryFechaOpcional(c *gin.Context, name string, sumarDias int) (*time.Time, bool) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return nil, true
+	}
+	t, err := time.ParseInLocation(time.DateOnly, raw, utils.AppLocation())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " debe tener formato YYYY-MM-DD"})
+		return nil, false
+	}
+	t = t.AddDate(0, 0, sumarDias)
+	return &t, true
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sena/cdattg-web-golang/dto"
@@ -33,6 +37,7 @@ func NewAuthHandlerWithService(authService services.AuthService) *AuthHandler {
 // @Success 200 {object} dto.LoginResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Router /api/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -41,8 +46,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(req, clienteInfo(c))
 	if err != nil {
+		var bloqueo *services.LoginBloqueadoError
+		if errors.As(err, &bloqueo) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(bloqueo.Hasta).Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "bloqueado_hasta": bloqueo.Hasta})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -96,3 +107,200 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Contraseña actualizada correctamente"})
 }
+
+// clienteInfo identifica el dispositivo de la petición para registrar la sesión.
+func clienteInfo(c *gin.Context) dto.ClienteInfo {
+	return dto.ClienteInfo{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+// sesionIDFromContext sesión del access token (la fija AuthMiddleware).
+func sesionIDFromContext(c *gin.Context) uint {
+	v, _ := c.Get("sessionID")
+	id, _ := v.(uint)
+	return id
+}
+
+// Refresh renueva el access token con el refresh token de la sesión (que se rota)
+// @Summary Renovar token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshRequest true "Refresh token"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+	tokens, err := h.authService.Refresh(req.RefreshToken, clienteInfo(c))
+	if err != nil {
+		if errors.Is(err, services.ErrRefreshTokenInvalido) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout cierra la sesión del dispositivo actual
+// @Router /api/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(sesionIDFromContext(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada"})
+}
+
+// LogoutAll cierra todas las sesiones del usuario en todos sus dispositivos, incluida la actual
+// @Router /api/auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	n, err := h.authService.CerrarTodasLasSesiones(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sesiones cerradas", "cerradas": n})
+}
+
+// ListSesiones lista las sesiones abiertas del usuario autenticado
+// @Router /api/auth/sesiones [get]
+func (h *AuthHandler) ListSesiones(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	list, err := h.authService.ListSesiones(userID.(uint), sesionIDFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// CerrarSesion cierra una sesión (dispositivo) propia
+// @Router /api/auth/sesiones/{id} [delete]
+func (h *AuthHandler) CerrarSesion(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	userID, _ := c.Get("userID")
+	if err := h.authService.CerrarSesion(userID.(uint), id); err != nil {
+		if errors.Is(err, services.ErrSesionNoEncontrada) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada"})
+}
+
+// ListAccesos últimos inicios de sesión (exitosos y fallidos) sobre la cuenta del usuario autenticado
+// @Router /api/auth/accesos [get]
+func (h *AuthHandler) ListAccesos(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	userID, _ := c.Get("userID")
+	list, err := h.authService.ListAccesos(userID.(uint), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// DesbloquearCuenta levanta el bloqueo por intentos fallidos de login de un usuario
+// @Router /api/usuarios/{id}/desbloquear-login [post]
+func (h *AuthHandler) DesbloquearCuenta(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	if err := h.authService.DesbloquearCuenta(actorAuditoria(c), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Cuenta desbloqueada"})
+}
+
+// msgRecuperacionEnviada respuesta única de la solicitud de recuperación, exista o no la cuenta.
+const msgRecuperacionEnviada = "Si la cuenta existe y tiene el correo verificado, recibirá un enlace para restablecer la contraseña"
+
+// RecuperarPassword envía por correo un enlace de un solo uso para restablecer la contraseña
+// @Router /api/auth/recuperar-password [post]
+func (h *AuthHandler) RecuperarPassword(c *gin.Context) {
+	var req dto.RecuperarPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+	if err := h.authService.SolicitarRecuperacion(req.Login); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": msgRecuperacionEnviada})
+}
+
+// RestablecerPassword fija una nueva contraseña con el token del enlace de recuperación
+// @Router /api/auth/restablecer-password [post]
+func (h *AuthHandler) RestablecerPassword(c *gin.Context) {
+	var req dto.RestablecerPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+	if err := h.authService.RestablecerPassword(req, clienteInfo(c)); err != nil {
+		if errors.Is(err, services.ErrTokenCuentaInvalido) || errors.Is(err, services.ErrPasswordIgualDefecto) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Contraseña restablecida. Inicie sesión con la nueva contraseña"})
+}
+
+// SolicitarVerificacionEmail envía al usuario autenticado el enlace para verificar su correo
+// @Router /api/auth/verificar-email/solicitar [post]
+func (h *AuthHandler) SolicitarVerificacionEmail(c *gin.Context) {
+	err := h.authService.SolicitarVerificacionEmail(c.GetUint("userID"))
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Enviamos un enlace de verificación a su correo"})
+	case errors.Is(err, services.ErrEnvioCorreoReciente):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEmailYaVerificado):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEmailNoVerificable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCorreoNoDisponible):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// VerificarEmail confirma el correo con el token del enlace de verificación
+// @Router /api/auth/verificar-email [post]
+func (h *AuthHandler) VerificarEmail(c *gin.Context) {
+	var req dto.VerificarEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+	if err := h.authService.VerificarEmail(req.Token, clienteInfo(c)); err != nil {
+		if errors.Is(err, services.ErrTokenCuentaInvalido) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Correo verificado"})
+}
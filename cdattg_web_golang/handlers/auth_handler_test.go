@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/services"
 	"github.com/sena/cdattg-web-golang/testutil"
 )
 
@@ -17,6 +19,8 @@ const (
 	testPathAuthLogin        = "/api/auth/login"
 	testPathAuthMe           = "/api/auth/me"
 	testPathAuthChangePass   = "/api/auth/change-password"
+	testPathAuthRefresh      = "/api/auth/refresh"
+	testPathAuthLogout       = "/api/auth/logout"
 	testLoginEmail           = "user@test.com"
 	testErrFmtMessage        = "error message: got %v, want %q"
 )
@@ -26,9 +30,12 @@ type mockAuthService struct {
 	loginFunc      func(dto.LoginRequest) (*dto.LoginResponse, error)
 	getCurrentUser func(uint) (*dto.UserResponse, error)
 	changePassword func(uint, dto.ChangePasswordRequest) error
+	refreshFunc    func(string) (*dto.TokenResponse, error)
+	logoutFunc     func(uint) error
+	restablecer    func(dto.RestablecerPasswordRequest) error
 }
 
-func (m *mockAuthService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
+func (m *mockAuthService) Login(req dto.LoginRequest, _ dto.ClienteInfo) (*dto.LoginResponse, error) {
 	if m.loginFunc != nil {
 		return m.loginFunc(req)
 	}
@@ -49,6 +56,43 @@ func (m *mockAuthService) ChangePassword(userID uint, req dto.ChangePasswordRequ
 	return nil
 }
 
+func (m *mockAuthService) Refresh(refreshToken string, _ dto.ClienteInfo) (*dto.TokenResponse, error) {
+	if m.refreshFunc != nil {
+		return m.refreshFunc(refreshToken)
+	}
+	return nil, nil
+}
+
+func (m *mockAuthService) Logout(sesionID uint) error {
+	if m.logoutFunc != nil {
+		return m.logoutFunc(sesionID)
+	}
+	return nil
+}
+
+func (m *mockAuthService) ListSesiones(uint, uint) ([]dto.SesionResponse, error) { return nil, nil }
+
+func (m *mockAuthService) CerrarSesion(uint, uint) error { return nil }
+
+func (m *mockAuthService) CerrarTodasLasSesiones(uint) (int64, error) { return 0, nil }
+
+func (m *mockAuthService) ListAccesos(uint, int) ([]dto.LoginAccesoResponse, error) { return nil, nil }
+
+func (m *mockAuthService) DesbloquearCuenta(dto.Actor, uint) error { return nil }
+
+func (m *mockAuthService) SolicitarRecuperacion(string) error { return nil }
+
+func (m *mockAuthService) RestablecerPassword(req dto.RestablecerPasswordRequest, _ dto.ClienteInfo) error {
+	if m.restablecer != nil {
+		return m.restablecer(req)
+	}
+	return nil
+}
+
+func (m *mockAuthService) SolicitarVerificacionEmail(uint) error { return nil }
+
+func (m *mockAuthService) VerificarEmail(string, dto.ClienteInfo) error { return nil }
+
 func assertResponseErrorEquals(t *testing.T, w *httptest.ResponseRecorder, want string) {
 	t.Helper()
 	var m map[string]interface{}
@@ -121,6 +165,16 @@ func TestAuthHandlerLogin(t *testing.T) {
 			wantStatus:     http.StatusUnauthorized,
 			wantErrMessage: "credenciales inválidas",
 		},
+		{
+			name: "cuenta_bloqueada_retorna_429",
+			setupRequest: func() (*httptest.ResponseRecorder, *gin.Context) {
+				return testutil.RecorderAndContext(http.MethodPost, testPathAuthLogin, dto.LoginRequest{Email: testLoginEmail, Password: "secret"})
+			},
+			mockLogin: func(dto.LoginRequest) (*dto.LoginResponse, error) {
+				return nil, &services.LoginBloqueadoError{Hasta: time.Now().Add(2 * time.Minute)}
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
 		{
 			name: "login_exitoso_retorna_200_y_token",
 			setupRequest: func() (*httptest.ResponseRecorder, *gin.Context) {
@@ -277,3 +331,124 @@ func TestAuthHandlerChangePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandlerRefresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name        string
+		body        interface{}
+		mockRefresh func(string) (*dto.TokenResponse, error)
+		wantStatus  int
+	}{
+		{
+			name:       "sin_refresh_token_retorna_400",
+			body:       map[string]string{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "refresh_token_invalido_retorna_401",
+			body: dto.RefreshRequest{RefreshToken: "viejo"},
+			mockRefresh: func(string) (*dto.TokenResponse, error) {
+				return nil, services.ErrRefreshTokenInvalido
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "exito_retorna_200_y_nuevo_par",
+			body: dto.RefreshRequest{RefreshToken: "vigente"},
+			mockRefresh: func(tok string) (*dto.TokenResponse, error) {
+				if tok != "vigente" {
+					t.Errorf("refresh token recibido %q", tok)
+				}
+				return &dto.TokenResponse{Token: "nuevo-jwt", RefreshToken: "nuevo-refresh", Type: "Bearer", ExpiresIn: 900}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAuthHandlerWithService(&mockAuthService{refreshFunc: tt.mockRefresh})
+			w, c := testutil.RecorderAndContext(http.MethodPost, testPathAuthRefresh, tt.body)
+			h.Refresh(c)
+			testutil.AssertStatus(t, w, tt.wantStatus)
+			if tt.wantStatus == http.StatusOK {
+				assertLoginResponseHasToken(t, w)
+			}
+		})
+	}
+}
+
+func TestAuthHandlerLogoutRevocaSesionDelToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var revocada uint
+	h := NewAuthHandlerWithService(&mockAuthService{logoutFunc: func(id uint) error {
+		revocada = id
+		return nil
+	}})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, testPathAuthLogout, nil)
+	c.Set("userID", uint(1))
+	c.Set("sessionID", uint(42))
+	h.Logout(c)
+	testutil.AssertStatus(t, w, http.StatusOK)
+	if revocada != 42 {
+		t.Fatalf("sesión revocada: got %d, want 42", revocada)
+	}
+}
+
+func TestAuthHandlerRestablecerPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name        string
+		body        interface{}
+		restablecer func(dto.RestablecerPasswordRequest) error
+		wantStatus  int
+	}{
+		{
+			name:       "password_corta_retorna_400",
+			body:       dto.RestablecerPasswordRequest{Token: "abc", PasswordNueva: "123"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "token_invalido_retorna_400",
+			body: dto.RestablecerPasswordRequest{Token: "abc", PasswordNueva: "nueva123"},
+			restablecer: func(dto.RestablecerPasswordRequest) error {
+				return services.ErrTokenCuentaInvalido
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ok_retorna_200",
+			body:       dto.RestablecerPasswordRequest{Token: "abc", PasswordNueva: "nueva123"},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAuthHandlerWithService(&mockAuthService{restablecer: tt.restablecer})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			raw, _ := json.Marshal(tt.body)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/restablecer-password", bytes.NewReader(raw))
+			c.Request.Header.Set("Content-Type", "application/json")
+			h.RestablecerPassword(c)
+			testutil.AssertStatus(t, w, tt.wantStatus)
+		})
+	}
+}
+
+func TestAuthHandlerRecuperarPasswordRespuestaGenerica(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewAuthHandlerWithService(&mockAuthService{})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/recuperar-password", bytes.NewBufferString(`{"login":"no-existe@test.com"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.RecuperarPassword(c)
+	testutil.AssertStatus(t, w, http.StatusOK)
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["message"] != msgRecuperacionEnviada {
+		t.Errorf("respuesta = %s", w.Body.String())
+	}
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// maxMultipartAdjuntoCaso límite del formulario del adjunto (archivo de 5 MB más campos).
+const maxMultipartAdjuntoCaso = 6 * 1024 * 1024
+
+// CasoBienestarHandler casos de seguimiento de bienestar (rutas protegidas con RequireSuperAdminOrBienestar).
+type CasoBienestarHandler struct {
+	svc services.CasoBienestarService
+}
+
+func NewCasoBienestarHandler() *CasoBienestarHandler {
+	return &CasoBienestarHandler{svc: services.NewCasoBienestarService()}
+}
+
+// NewCasoBienestarHandlerWithService permite inyectar el servicio (p. ej. para tests).
+func NewCasoBienestarHandlerWithService(svc services.CasoBienestarService) *CasoBienestarHandler {
+	return &CasoBienestarHandler{svc: svc}
+}
+
+func respondCasoBienestarError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCasoBienestarNoEncontrado),
+		errors.Is(err, services.ErrCasoBienestarAdjuntoNoExiste),
+		errors.Is(err, services.ErrCasoBienestarAprendizNoExiste):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCasoBienestarYaAbierto):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// casoBienestarIDParam lee :id; responde 400 si no es válido.
+func casoBienestarIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := parseUintParam(c, name)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return 0, false
+	}
+	return id, true
+}
+
+// List casos de bienestar. Query: estado, responsable_user_id (o "yo" / "ninguno"), ficha_id.
+// @Router /api/bienestar/casos [get]
+func (h *CasoBienestarHandler) List(c *gin.Context) {
+	var f repositories.CasoBienestarFiltro
+	switch estado := c.Query("estado"); estado {
+	case "", models.CasoBienestarEstadoAbierto, models.CasoBienestarEstadoEnSeguimiento, models.CasoBienestarEstadoCerrado:
+		f.Estado = estado
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "estado inválido"})
+		return
+	}
+	switch v := c.Query("responsable_user_id"); v {
+	case "":
+	case "yo":
+		uid := c.GetUint("userID")
+		f.ResponsableUserID = &uid
+	case "ninguno":
+		f.SinResponsable = true
+	default:
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "responsable_user_id inválido"})
+			return
+		}
+		u := uint(id)
+		f.ResponsableUserID = &u
+	}
+	if v := c.Query("ficha_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errMsgFichaIDInvalidoQuery})
+			return
+		}
+		u := uint(id)
+		f.FichaID = &u
+	}
+	list, err := h.svc.List(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Create abre un caso manualmente
+// @Router /api/bienestar/casos [post]
+func (h *CasoBienestarHandler) Create(c *gin.Context) {
+	var req dto.CasoBienestarCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.Crear(actorAuditoria(c), req)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// AbrirDesdeCalculo abre casos para aprendices del listado calculado de inasistencias
+// @Router /api/bienestar/casos/desde-calculo [post]
+func (h *CasoBienestarHandler) AbrirDesdeCalculo(c *gin.Context) {
+	var req dto.CasosBienestarAbrirRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.AbrirDesdeCalculo(actorAuditoria(c), req)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get caso con intervenciones, adjuntos y recordatorios
+// @Router /api/bienestar/casos/{id} [get]
+func (h *CasoBienestarHandler) Get(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	resp, err := h.svc.Get(id)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Update cambia estado y responsable del caso (cerrar exige observación)
+// @Router /api/bienestar/casos/{id} [put]
+func (h *CasoBienestarHandler) Update(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.CasoBienestarUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.Actualizar(actorAuditoria(c), id, req)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateIntervencion registra una gestión fechada sobre el caso
+// @Router /api/bienestar/casos/{id}/intervenciones [post]
+func (h *CasoBienestarHandler) CreateIntervencion(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.CasoBienestarIntervencionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.RegistrarIntervencion(actorAuditoria(c), id, req)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UploadAdjunto adjunta un soporte al caso. Multipart: archivo (PDF/JPG/PNG, máx. 5 MB).
+// @Router /api/bienestar/casos/{id}/adjuntos [post]
+func (h *CasoBienestarHandler) UploadAdjunto(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMultipartAdjuntoCaso)
+	file, err := c.FormFile("archivo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el archivo en 'archivo' (máximo 5 MB)"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
+		return
+	}
+	defer f.Close()
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
+		return
+	}
+	resp, err := h.svc.SubirAdjunto(actorAuditoria(c), id, buf, file.Filename)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// DownloadAdjunto descarga un adjunto del caso
+// @Router /api/bienestar/casos/{id}/adjuntos/{adjuntoId} [get]
+func (h *CasoBienestarHandler) DownloadAdjunto(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	adjuntoID, ok := casoBienestarIDParam(c, "adjuntoId")
+	if !ok {
+		return
+	}
+	ruta, nombre, err := h.svc.Adjunto(id, adjuntoID)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.FileAttachment(ruta, nombre)
+}
+
+// CreateRecordatorio programa un recordatorio para el responsable del caso
+// @Router /api/bienestar/casos/{id}/recordatorios [post]
+func (h *CasoBienestarHandler) CreateRecordatorio(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.CasoBienestarRecordatorioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.CrearRecordatorio(actorAuditoria(c), id, req)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CompletarRecordatorio marca un recordatorio como hecho
+// @Router /api/bienestar/casos/{id}/recordatorios/{recordatorioId}/completar [put]
+func (h *CasoBienestarHandler) CompletarRecordatorio(c *gin.Context) {
+	id, ok := casoBienestarIDParam(c, "id")
+	if !ok {
+		return
+	}
+	recordatorioID, ok := casoBienestarIDParam(c, "recordatorioId")
+	if !ok {
+		return
+	}
+	if err := h.svc.CompletarRecordatorio(id, recordatorioID); err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recordatorio completado"})
+}
+
+// MisRecordatorios recordatorios vencidos o de hoy de los casos asignados al usuario autenticado
+// @Router /api/bienestar/mis-recordatorios [get]
+func (h *CasoBienestarHandler) MisRecordatorios(c *gin.Context) {
+	list, err := h.svc.MisRecordatorios(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// HistorialAprendiz casos de bienestar del aprendiz en todas sus fichas
+// @Router /api/bienestar/aprendices/{aprendizId}/casos [get]
+func (h *CasoBienestarHandler) HistorialAprendiz(c *gin.Context) {
+	aprendizID, ok := casoBienestarIDParam(c, "aprendizId")
+	if !ok {
+		return
+	}
+	list, err := h.svc.HistorialAprendiz(aprendizID)
+	if err != nil {
+		respondCasoBienestarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Profesionales usuarios a los que se puede asignar un caso
+// @Router /api/bienestar/profesionales [get]
+func (h *CasoBienestarHandler) Profesionales(c *gin.Context) {
+	list, err := h.svc.Profesionales()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
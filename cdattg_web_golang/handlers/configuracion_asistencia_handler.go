@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/scheduler"
 	"github.com/sena/cdattg-web-golang/services"
 )
 
@@ -31,5 +33,8 @@ func (h *ConfiguracionAsistenciaHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := scheduler.Default().Reschedule(TareaAutoCierreAsistencia, services.ExpresionAutoCierreAsistencia()); err != nil {
+		log.Printf("[scheduler] no se pudo reprogramar %s: %v", TareaAutoCierreAsistencia, err)
+	}
 	c.JSON(http.StatusOK, gin.H{"data": item})
 }
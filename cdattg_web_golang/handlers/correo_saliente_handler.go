@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+type CorreoSalienteHandler struct {
+	svc services.CorreoService
+}
+
+func NewCorreoSalienteHandler() *CorreoSalienteHandler {
+	return &CorreoSalienteHandler{svc: services.NewCorreoService()}
+}
+
+// List GET /api/admin/correos?estado=&plantilla=&destinatario=&desde=YYYY-MM-DD&hasta=YYYY-MM-DD&page=&page_size=
+// Incluye el conteo por estado de toda la cola.
+func (h *CorreoSalienteHandler) List(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if err != nil || pageSize < 1 || pageSize > 200 {
+		pageSize = 50
+	}
+	f := repositories.CorreoSalienteFiltro{
+		Estado:       strings.TrimSpace(c.Query("estado")),
+		Plantilla:    strings.TrimSpace(c.Query("plantilla")),
+		Destinatario: strings.TrimSpace(c.Query("destinatario")),
+		Page:         page,
+		PageSize:     pageSize,
+	}
+	var ok bool
+	if f.Desde, ok = queryFechaOpcional(c, "desde", 0); !ok {
+		return
+	}
+	// hasta inclusive: se filtra por < hasta + 1 día
+	if f.Hasta, ok = queryFechaOpcional(c, "hasta", 1); !ok {
+		return
+	}
+	list, total, err := h.svc.List(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resumen, err := h.svc.Resumen()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":      list,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+		"resumen":   resumen,
+	})
+}
+
+// GetByID GET /api/admin/correos/:id (el cuerpo de los correos con enlaces de un solo uso no se devuelve).
+func (h *CorreoSalienteHandler) GetByID(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	out, err := h.svc.GetByID(id)
+	if err != nil {
+		respondCorreoSalienteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// DescargarAdjunto GET /api/admin/correos/:id/adjuntos/:adjuntoId
+func (h *CorreoSalienteHandler) DescargarAdjunto(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	adjuntoID, err := parseUintParam(c, "adjuntoId")
+	if err != nil || adjuntoID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	a, err := h.svc.GetAdjunto(id, adjuntoID)
+	if err != nil {
+		respondCorreoSalienteError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.Nombre))
+	c.Data(http.StatusOK, a.ContentType, a.Contenido)
+}
+
+// Reintentar POST /api/admin/correos/:id/reintentar devuelve a la cola un correo fallido.
+func (h *CorreoSalienteHandler) Reintentar(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	out, err := h.svc.Reintentar(id, actorAuditoria(c))
+	if err != nil {
+		respondCorreoSalienteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+func respondCorreoSalienteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCorreoNoEncontrado):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCorreoNoReintentable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -299,6 +301,18 @@ func (h *EleccionHandler) RegistrarVoto(c *gin.Context) {
 	})
 }
 
+// VerificarRecibo comprueba con el código entregado al votar que la papeleta está en la urna.
+func (h *EleccionHandler) VerificarRecibo(c *gin.Context) {
+	h.withAuthRolesAndID(c, func(_ eleccionAuth, id uint) {
+		res, err := h.svc.VerificarRecibo(id, c.Param("codigo"))
+		if err != nil {
+			respondEleccionBadRequest(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": res})
+	})
+}
+
 func (h *EleccionHandler) GetResultados(c *gin.Context) {
 	h.withAuthRolesAndID(c, func(auth eleccionAuth, id uint) {
 		incluirVotos := c.Query("auditoria") == "1"
@@ -311,6 +325,45 @@ func (h *EleccionHandler) GetResultados(c *gin.Context) {
 	})
 }
 
+func (h *EleccionHandler) GetParticipacion(c *gin.Context) {
+	h.withAuthRolesAndID(c, func(auth eleccionAuth, id uint) {
+		res, err := h.svc.GetParticipacion(auth.userID, auth.roles, id)
+		if err != nil {
+			respondEleccionBadRequest(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": res})
+	})
+}
+
+// ListEventosProceso bitácora de cambios de fase, manuales y automáticos.
+func (h *EleccionHandler) ListEventosProceso(c *gin.Context) {
+	h.withAuthRolesAndID(c, func(auth eleccionAuth, id uint) {
+		list, err := h.svc.ListEventosProceso(auth.userID, auth.roles, id)
+		if err != nil {
+			respondEleccionBadRequest(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": list})
+	})
+}
+
+func (h *EleccionHandler) EnviarRecordatorioVoto(c *gin.Context) {
+	h.withAuthRolesAndID(c, func(auth eleccionAuth, id uint) {
+		var req dto.EleccionRecordatorioRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondEleccionBadRequest(c, err)
+			return
+		}
+		res, err := h.svc.EnviarRecordatorioVoto(auth.userID, auth.roles, id, req)
+		if err != nil {
+			respondEleccionBadRequest(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": res})
+	})
+}
+
 func (h *EleccionHandler) ExportResultadosCSV(c *gin.Context) {
 	h.withAuthRolesAndID(c, func(auth eleccionAuth, id uint) {
 		data, err := h.svc.ExportResultadosCSV(auth.userID, auth.roles, id)
@@ -325,6 +378,33 @@ func (h *EleccionHandler) ExportResultadosCSV(c *gin.Context) {
 	})
 }
 
+// DescargarActa acta oficial de escrutinio (PDF) de un proceso cerrado.
+func (h *EleccionHandler) DescargarActa(c *gin.Context) {
+	h.withAuthRolesAndID(c, func(auth eleccionAuth, id uint) {
+		data, err := h.svc.GenerarActaPDF(auth.userID, auth.roles, id)
+		if err != nil {
+			respondEleccionBadRequest(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=acta_escrutinio_%d.pdf", id))
+		c.Data(http.StatusOK, "application/pdf", data)
+	})
+}
+
+// VerificarActa consulta pública (sin autenticación) de un acta por el hash impreso en ella.
+func (h *EleccionHandler) VerificarActa(c *gin.Context) {
+	res, err := h.svc.VerificarActa(c.Param("hash"))
+	if err != nil {
+		if errors.Is(err, services.ErrEleccionActaNoEncontrada) {
+			respondEleccionError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		respondEleccionError(c, http.StatusInternalServerError, "no se pudo verificar el acta")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
 func (h *EleccionHandler) GetMiRegional(c *gin.Context) {
 	auth, ok := eleccionAuthFromContext(c)
 	if !ok {
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+type EvaluacionHandler struct {
+	svc services.EvaluacionService
+}
+
+func NewEvaluacionHandler() *EvaluacionHandler {
+	return &EvaluacionHandler{svc: services.NewEvaluacionService()}
+}
+
+func respondEvaluacionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEvaluacionFichaNoEncontrada):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEvaluacionSinPermiso):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// GetMatriz matriz de evaluación de la ficha: competencias y RAPs del programa con el juicio de cada aprendiz
+// @Router /api/fichas-caracterizacion/{id}/evaluacion [get]
+func (h *EvaluacionHandler) GetMatriz(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	resp, err := h.svc.GetMatrizFicha(personaIDFromContext(c), rolesFromContext(c), id)
+	if err != nil {
+		respondEvaluacionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RegistrarJuicios registra o actualiza en lote juicios (APROBADO / NO APROBADO / PENDIENTE) por aprendiz y RAP
+// @Router /api/fichas-caracterizacion/{id}/evaluacion/juicios [put]
+func (h *EvaluacionHandler) RegistrarJuicios(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	var req dto.JuiciosRegistroRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.RegistrarJuicios(actorAuditoria(c), personaIDFromContext(c), rolesFromContext(c), id, req)
+	if err != nil {
+		respondEvaluacionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListAlertasCierre fichas por finalizar (próximos 30 días) con RAPs sin juicio definitivo
+// @Router /api/fichas-caracterizacion/evaluacion/alertas [get]
+func (h *EvaluacionHandler) ListAlertasCierre(c *gin.Context) {
+	list, err := h.svc.ListAlertasCierre(personaIDFromContext(c), rolesFromContext(c))
+	if err != nil {
+		respondEvaluacionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// maxMultipartExcusa límite del formulario completo (soporte de 5 MB más campos).
+const maxMultipartExcusa = 6 * 1024 * 1024
+
+type ExcusaHandler struct {
+	svc services.ExcusaService
+}
+
+func NewExcusaHandler() *ExcusaHandler {
+	return &ExcusaHandler{svc: services.NewExcusaService()}
+}
+
+func personaIDFromContext(c *gin.Context) *uint {
+	u, _ := c.Get("user")
+	user, _ := u.(*models.User)
+	if user == nil {
+		return nil
+	}
+	return user.PersonaID
+}
+
+// parseAsistenciaIDsForm acepta asistencia_ids repetido o separado por comas.
+func parseAsistenciaIDsForm(values []string) ([]uint, error) {
+	var ids []uint
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+func respondExcusaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrExcusaNoEncontrada), errors.Is(err, services.ErrExcusaAprendizNoEncontrado):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrExcusaSinPermisoRevision), errors.Is(err, services.ErrExcusaSinPermisoArchivo):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// Presentar el aprendiz autenticado presenta una excusa. Multipart: asistencia_ids, motivo, archivo (PDF/JPG/PNG, máx. 5 MB).
+// @Router /api/asistencias/excusas [post]
+func (h *ExcusaHandler) Presentar(c *gin.Context) {
+	personaID := personaIDFromContext(c)
+	if personaID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Su cuenta no está vinculada a una persona."})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMultipartExcusa)
+	ids, err := parseAsistenciaIDsForm(c.PostFormArray("asistencia_ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "asistencia_ids inválido"})
+		return
+	}
+	file, err := c.FormFile("archivo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el soporte en 'archivo' (máximo 5 MB)"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
+		return
+	}
+	defer f.Close()
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
+		return
+	}
+	req := dto.ExcusaCreateRequest{AsistenciaIDs: ids, Motivo: c.PostForm("motivo")}
+	resp, err := h.svc.Presentar(*personaID, req, buf, file.Filename)
+	if err != nil {
+		respondExcusaError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListMias excusas presentadas por el aprendiz autenticado
+// @Router /api/asistencias/mis-excusas [get]
+func (h *ExcusaHandler) ListMias(c *gin.Context) {
+	personaID := personaIDFromContext(c)
+	if personaID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Su cuenta no está vinculada a una persona."})
+		return
+	}
+	list, err := h.svc.ListMias(*personaID)
+	if err != nil {
+		respondExcusaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// ListParaRevision excusas que el usuario puede revisar. Query: estado (pendiente|aprobada|rechazada), ficha_id.
+// @Router /api/asistencias/excusas [get]
+func (h *ExcusaHandler) ListParaRevision(c *gin.Context) {
+	estado := c.Query("estado")
+	switch estado {
+	case "", models.ExcusaEstadoPendiente, models.ExcusaEstadoAprobada, models.ExcusaEstadoRechazada:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "estado inválido"})
+		return
+	}
+	var fichaID *uint
+	if v := c.Query("ficha_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errMsgFichaIDInvalidoQuery})
+			return
+		}
+		u := uint(id)
+		fichaID = &u
+	}
+	list, err := h.svc.ListParaRevision(personaIDFromContext(c), rolesFromContext(c), estado, fichaID)
+	if err != nil {
+		respondExcusaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Revisar aprueba o rechaza una excusa pendiente (instructor de la ficha, bienestar o super admin)
+// @Router /api/asistencias/excusas/{id}/revision [put]
+func (h *ExcusaHandler) Revisar(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	var req dto.ExcusaRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	resp, err := h.svc.Revisar(actorAuditoria(c), personaIDFromContext(c), rolesFromContext(c), id, req)
+	if err != nil {
+		respondExcusaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Archivo descarga el soporte de la excusa (el aprendiz dueño o quien puede revisarla)
+// @Router /api/asistencias/excusas/{id}/archivo [get]
+func (h *ExcusaHandler) Archivo(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	ruta, nombre, err := h.svc.Archivo(personaIDFromContext(c), rolesFromContext(c), id)
+	if err != nil {
+		respondExcusaError(c, err)
+		return
+	}
+	c.FileAttachment(ruta, nombre)
+}
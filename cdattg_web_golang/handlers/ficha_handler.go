@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -27,16 +26,16 @@ type fichaExportSheetState struct {
 }
 
 type FichaHandler struct {
-	svc       services.FichaService
-	importSvc services.FichaImportService
-	instRepo  repositories.InstructorRepository
+	svc           services.FichaService
+	importaciones services.ImportacionTrabajoService
+	instRepo      repositories.InstructorRepository
 }
 
 func NewFichaHandler() *FichaHandler {
 	return &FichaHandler{
-		svc:       services.NewFichaService(),
-		importSvc: services.NewFichaImportService(),
-		instRepo:  repositories.NewInstructorRepository(),
+		svc:           services.NewFichaService(),
+		importaciones: services.NewImportacionTrabajoService(),
+		instRepo:      repositories.NewInstructorRepository(),
 	}
 }
 
@@ -150,7 +149,7 @@ func (h *FichaHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
 		return
 	}
-	f, err := h.svc.Create(req)
+	f, err := h.svc.Create(actorAuditoria(c), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -169,7 +168,7 @@ func (h *FichaHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
 		return
 	}
-	f, err := h.svc.Update(uint(id), req)
+	f, err := h.svc.Update(actorAuditoria(c), uint(id), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -183,7 +182,7 @@ func (h *FichaHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
 		return
 	}
-	if err := h.svc.Delete(uint(id)); err != nil {
+	if err := h.svc.Delete(actorAuditoria(c), uint(id)); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -344,43 +343,25 @@ func (h *FichaHandler) OcultarAprendicesEnAsistencia(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": msg})
 }
 
-// ImportFichas sube un Excel de reporte de aprendices (ficha de caracterización) e importa ficha y personas como aprendices.
+// ImportFichas sube un Excel de reporte de aprendices (ficha de caracterización) y encola la importación de la ficha
+// y sus personas como aprendices. Responde 202 con el trabajo.
 func (h *FichaHandler) ImportFichas(c *gin.Context) {
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el archivo 'file'"})
-		return
-	}
-	if file.Size == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo está vacío"})
-		return
-	}
-	if file.Size > 20*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo no debe superar 20 MB"})
+	userID := c.GetUint("userID")
+	buf, filename, ok := leerExcelSubido(c, 20)
+	if !ok {
 		return
 	}
-	lower := strings.ToLower(file.Filename)
+	lower := strings.ToLower(filename)
 	if !strings.HasSuffix(lower, ".xlsx") && !strings.HasSuffix(lower, ".xls") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Solo se permiten archivos Excel (.xlsx o .xls)"})
 		return
 	}
-	f, err := file.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
-		return
-	}
-	defer f.Close()
-	buf, err := io.ReadAll(f)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
-		return
-	}
-	result, err := h.importSvc.ImportFromExcel(buf, file.Filename)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, result)
+	encolarImportacion(c, h.importaciones, services.ImportacionNueva{
+		Tipo:     models.ImportacionTipoFicha,
+		UserID:   userID,
+		Filename: filename,
+		Archivo:  buf,
+	})
 }
 
 // ExportAllExcel genera un archivo Excel con una hoja por ficha y su listado de aprendices activos.
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+const (
+	// intervaloEventosImportacion cada cuánto el canal de eventos consulta el avance del trabajo.
+	intervaloEventosImportacion = time.Second
+	// latidoEventosImportacion sin cambios, se envía un comentario para que proxies no cierren la conexión.
+	latidoEventosImportacion = 15 * time.Second
+)
+
+type ImportacionHandler struct {
+	svc services.ImportacionTrabajoService
+}
+
+func NewImportacionHandler() *ImportacionHandler {
+	return &ImportacionHandler{svc: services.NewImportacionTrabajoService()}
+}
+
+// NewImportacionHandlerWithService permite inyectar el servicio (p. ej. para tests).
+func NewImportacionHandlerWithService(svc services.ImportacionTrabajoService) *ImportacionHandler {
+	return &ImportacionHandler{svc: svc}
+}
+
+// leerExcelSubido lee el archivo 'file' del formulario multipart. Si no es válido responde el error y devuelve false.
+func leerExcelSubido(c *gin.Context, maxMB int64) ([]byte, string, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el archivo 'file'"})
+		return nil, "", false
+	}
+	if file.Size == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo está vacío"})
+		return nil, "", false
+	}
+	if file.Size > maxMB*1024*1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("El archivo no debe superar %d MB", maxMB)})
+		return nil, "", false
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
+		return nil, "", false
+	}
+	defer f.Close()
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
+		return nil, "", false
+	}
+	return buf, file.Filename, true
+}
+
+// encolarImportacion valida el archivo, crea el trabajo y responde 202 con él; el avance se consulta en
+// /api/importaciones/:id.
+func encolarImportacion(c *gin.Context, svc services.ImportacionTrabajoService, req services.ImportacionNueva) {
+	out, err := svc.Encolar(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": out})
+}
+
+// List GET /api/importaciones?tipo=&estado=&page=&page_size= importaciones del usuario, más recientes primero.
+func (h *ImportacionHandler) List(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	list, total, err := h.svc.List(repositories.ImportacionTrabajoFiltro{
+		UserID:   c.GetUint("userID"),
+		Tipo:     strings.TrimSpace(c.Query("tipo")),
+		Estado:   strings.TrimSpace(c.Query("estado")),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":      list,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// GetByID GET /api/importaciones/:id avance y resultado de la importación.
+func (h *ImportacionHandler) GetByID(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	out, err := h.svc.Get(id, c.GetUint("userID"), rolesFromContext(c))
+	if err != nil {
+		respondImportacionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+func importacionTerminada(estado string) bool {
+	return estado == models.ImportacionEstadoCompletado || estado == models.ImportacionEstadoFallido
+}
+
+// Eventos GET /api/importaciones/:id/eventos avance por Server-Sent Events. Consulta el trabajo cada segundo (puede
+// estar corriendo en otra réplica), envía "progreso" cuando cambia y cierra al completarse o fallar.
+func (h *ImportacionHandler) Eventos(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	userID, roles := c.GetUint("userID"), rolesFromContext(c)
+	actual, err := h.svc.Get(id, userID, roles)
+	if err != nil {
+		respondImportacionError(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(intervaloEventosImportacion)
+	defer ticker.Stop()
+	var enviado *dto.ImportacionTrabajoResponse
+	ultimoEnvio := time.Now()
+	c.Stream(func(w io.Writer) bool {
+		if enviado != nil {
+			select {
+			case <-c.Request.Context().Done():
+				return false
+			case <-ticker.C:
+			}
+			if actual, err = h.svc.Get(id, userID, roles); err != nil {
+				c.SSEvent("error", gin.H{"error": err.Error()})
+				return false
+			}
+		}
+		if enviado == nil || !actual.UpdatedAt.Equal(enviado.UpdatedAt) || actual.Estado != enviado.Estado {
+			c.SSEvent("progreso", actual)
+			enviado, ultimoEnvio = actual, time.Now()
+		} else if time.Since(ultimoEnvio) >= latidoEventosImportacion {
+			_, _ = io.WriteString(w, ": latido\n\n")
+			ultimoEnvio = time.Now()
+		}
+		return !importacionTerminada(actual.Estado)
+	})
+}
+
+// DescargarIncidencias GET /api/importaciones/:id/incidencias Excel con las filas duplicadas, con error o ajustadas.
+func (h *ImportacionHandler) DescargarIncidencias(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	data, nombre, err := h.svc.ExcelIncidencias(id, c.GetUint("userID"), rolesFromContext(c))
+	if err != nil {
+		respondImportacionError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nombre))
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+}
+
+// Reanudar POST /api/importaciones/:id/reanudar devuelve a la cola una importación fallida; sigue desde la última
+// fila guardada.
+func (h *ImportacionHandler) Reanudar(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	out, err := h.svc.Reanudar(id, c.GetUint("userID"), rolesFromContext(c))
+	if err != nil {
+		respondImportacionError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": out})
+}
+
+func respondImportacionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrImportacionNoEncontrada), errors.Is(err, services.ErrImportacionSinIncidencias):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrImportacionNoReanudable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
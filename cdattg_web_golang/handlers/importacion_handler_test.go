@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// importacionSvcFake devuelve los trabajos en orden en cada Get; el último se repite.
+type importacionSvcFake struct {
+	services.ImportacionTrabajoService
+	trabajos []dto.ImportacionTrabajoResponse
+	err      error
+	llamadas int
+}
+
+func (s *importacionSvcFake) Get(_, _ uint, _ []string) (*dto.ImportacionTrabajoResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	i := s.llamadas
+	if i >= len(s.trabajos) {
+		i = len(s.trabajos) - 1
+	}
+	s.llamadas++
+	out := s.trabajos[i]
+	return &out, nil
+}
+
+func (s *importacionSvcFake) Reanudar(_, _ uint, _ []string) (*dto.ImportacionTrabajoResponse, error) {
+	return nil, s.err
+}
+
+// streamRecorder httptest.ResponseRecorder no implementa CloseNotify, que c.Stream necesita.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (streamRecorder) CloseNotify() <-chan bool { return make(chan bool) }
+
+func importacionTestRouter(svc services.ImportacionTrabajoService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewImportacionHandlerWithService(svc)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", uint(3))
+		c.Set("userRoles", []string{"COORDINADOR"})
+	})
+	r.GET("/importaciones/:id/eventos", h.Eventos)
+	r.POST("/importaciones/:id/reanudar", h.Reanudar)
+	return r
+}
+
+func TestImportacionEventos_emiteProgresoHastaCompletar(t *testing.T) {
+	svc := &importacionSvcFake{trabajos: []dto.ImportacionTrabajoResponse{
+		{ID: 5, Estado: models.ImportacionEstadoProcesando, FilaActual: 0, TotalFilas: 40},
+		{ID: 5, Estado: models.ImportacionEstadoProcesando, FilaActual: 0, TotalFilas: 40},
+		{ID: 5, Estado: models.ImportacionEstadoCompletado, FilaActual: 40, TotalFilas: 40, Porcentaje: 100},
+	}}
+	w := streamRecorder{httptest.NewRecorder()}
+	importacionTestRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/importaciones/5/eventos", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	body := w.Body.String()
+	// El segundo Get no trae cambios: solo se envían el estado inicial y el completado.
+	if n := strings.Count(body, "event:progreso"); n != 2 {
+		t.Fatalf("eventos progreso = %d, body:\n%s", n, body)
+	}
+	if !strings.Contains(body, `"estado":"completado"`) {
+		t.Fatalf("falta el evento de completado:\n%s", body)
+	}
+}
+
+func TestImportacionErrores_codigoHTTP(t *testing.T) {
+	cases := []struct {
+		metodo, ruta string
+		err          error
+		want         int
+	}{
+		{http.MethodGet, "/importaciones/5/eventos", services.ErrImportacionNoEncontrada, http.StatusNotFound},
+		{http.MethodGet, "/importaciones/x/eventos", nil, http.StatusBadRequest},
+		{http.MethodPost, "/importaciones/5/reanudar", services.ErrImportacionNoReanudable, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		importacionTestRouter(&importacionSvcFake{err: tc.err}).ServeHTTP(w, httptest.NewRequest(tc.metodo, tc.ruta, nil))
+		if w.Code != tc.want {
+			t.Errorf("%s %s con %v: status %d, want %d", tc.metodo, tc.ruta, tc.err, w.Code, tc.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -8,19 +10,53 @@ import (
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
 	"github.com/sena/cdattg-web-golang/services"
+	"github.com/sena/cdattg-web-golang/utils"
 )
 
+const icsContentType = "text/calendar; charset=utf-8"
+
 // InstructorAgendaHandler endpoints de agenda de programación.
 type InstructorAgendaHandler struct {
-	agendaSvc *services.InstructorAgendaService
-	instRepo  repositories.InstructorRepository
+	agendaSvc      *services.InstructorAgendaService
+	suscripcionSvc services.AgendaSuscripcionService
+	instRepo       repositories.InstructorRepository
 }
 
 func NewInstructorAgendaHandler() *InstructorAgendaHandler {
 	return &InstructorAgendaHandler{
-		agendaSvc: services.NewInstructorAgendaService(),
-		instRepo:  repositories.NewInstructorRepository(),
+		agendaSvc:      services.NewInstructorAgendaService(),
+		suscripcionSvc: services.NewAgendaSuscripcionService(),
+		instRepo:       repositories.NewInstructorRepository(),
+	}
+}
+
+// rangoICS desde/hasta de la query o, si faltan, la ventana por defecto del calendario.
+func rangoICS(c *gin.Context) (string, string) {
+	desde, hasta := c.Query("desde"), c.Query("hasta")
+	if desde == "" || hasta == "" {
+		return services.VentanaAgendaICS(utils.Now())
+	}
+	return desde, hasta
+}
+
+func responderICS(c *gin.Context, filename string, data []byte) {
+	if filename != "" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
 	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, icsContentType, data)
+}
+
+// urlSuscripcionAgenda URL absoluta del feed público según el host por el que llegó la solicitud.
+func urlSuscripcionAgenda(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if p := c.GetHeader("X-Forwarded-Proto"); p != "" {
+		scheme = p
+	}
+	return fmt.Sprintf("%s://%s/api/calendario/%s/agenda.ics", scheme, c.Request.Host, token)
 }
 
 func (h *InstructorAgendaHandler) instructorIDFromContext(c *gin.Context) (uint, bool) {
@@ -80,3 +116,87 @@ func (h *InstructorAgendaHandler) GetAgendaFicha(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, resp)
 }
+
+// GetMiAgendaICS GET /instructor/agenda.ics?desde=&hasta= (rango opcional)
+func (h *InstructorAgendaHandler) GetMiAgendaICS(c *gin.Context) {
+	instID, ok := h.instructorIDFromContext(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": services.ErrAgendaSinInstructor.Error()})
+		return
+	}
+	desde, hasta := rangoICS(c)
+	resp, err := h.agendaSvc.AgendaInstructor(instID, desde, hasta)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	responderICS(c, "agenda.ics", services.GenerarAgendaICS("Mi agenda de formación", resp.Eventos, utils.AppLocation(), utils.Now()))
+}
+
+// GetAgendaFichaICS GET /fichas-caracterizacion/:id/agenda.ics?desde=&hasta= (rango opcional)
+func (h *InstructorAgendaHandler) GetAgendaFichaICS(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	desde, hasta := rangoICS(c)
+	resp, err := h.agendaSvc.AgendaFicha(uint(id), desde, hasta)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	nombre := fmt.Sprintf("Agenda ficha %d", id)
+	if len(resp.Eventos) > 0 {
+		nombre = "Agenda ficha " + resp.Eventos[0].FichaNumero
+	}
+	responderICS(c, fmt.Sprintf("agenda_ficha_%d.ics", id), services.GenerarAgendaICS(nombre, resp.Eventos, utils.AppLocation(), utils.Now()))
+}
+
+// GetSuscripcion GET /instructor/agenda/suscripcion estado del enlace (sin el token)
+func (h *InstructorAgendaHandler) GetSuscripcion(c *gin.Context) {
+	resp, err := h.suscripcionSvc.Estado(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GenerarSuscripcion POST /instructor/agenda/suscripcion crea o regenera el enlace; el anterior deja de funcionar
+func (h *InstructorAgendaHandler) GenerarSuscripcion(c *gin.Context) {
+	token, resp, err := h.suscripcionSvc.Generar(c.GetUint("userID"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrAgendaSinInstructor) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	resp.URL = urlSuscripcionAgenda(c, token)
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevocarSuscripcion DELETE /instructor/agenda/suscripcion
+func (h *InstructorAgendaHandler) RevocarSuscripcion(c *gin.Context) {
+	if err := h.suscripcionSvc.Revocar(c.GetUint("userID")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Enlace de suscripción revocado"})
+}
+
+// GetAgendaSuscripcionICS GET /api/calendario/:token/agenda.ics feed público; el token del enlace es la credencial
+func (h *InstructorAgendaHandler) GetAgendaSuscripcionICS(c *gin.Context) {
+	data, err := h.suscripcionSvc.AgendaICS(c.Param("token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrAgendaSuscripcionInvalida) || errors.Is(err, services.ErrAgendaSinInstructor) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	responderICS(c, "", data)
+}
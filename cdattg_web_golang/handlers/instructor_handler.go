@@ -15,6 +15,7 @@ type InstructorHandler struct {
 	repo                repositories.InstructorRepository
 	svc                 services.InstructorService
 	instructorImportSvc services.InstructorImportService
+	importaciones       services.ImportacionTrabajoService
 }
 
 func NewInstructorHandler() *InstructorHandler {
@@ -22,6 +23,7 @@ func NewInstructorHandler() *InstructorHandler {
 		repo:                repositories.NewInstructorRepository(),
 		svc:                 services.NewInstructorService(),
 		instructorImportSvc: services.NewInstructorImportService(),
+		importaciones:       services.NewImportacionTrabajoService(),
 	}
 }
 
@@ -113,8 +115,9 @@ func (h *InstructorHandler) CreateFromPersona(c *gin.Context) {
 	c.JSON(http.StatusCreated, item)
 }
 
-// ImportInstructores sube un Excel e importa instructores (crea persona si no existe y vincula como instructor).
-// Query opcional: regional_id (ID de regional por defecto para los instructores creados).
+// ImportInstructores sube un Excel y encola la importación de instructores (crea persona si no existe y vincula como
+// instructor). Query opcional: regional_id (ID de regional por defecto para los instructores creados). Responde 202
+// con el trabajo.
 func (h *InstructorHandler) ImportInstructores(c *gin.Context) {
 	userIDVal, exists := c.Get("userID")
 	if !exists {
@@ -123,28 +126,8 @@ func (h *InstructorHandler) ImportInstructores(c *gin.Context) {
 	}
 	userID := userIDVal.(uint)
 
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el archivo 'file'"})
-		return
-	}
-	if file.Size == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo está vacío"})
-		return
-	}
-	if file.Size > 10*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo no debe superar 10 MB"})
-		return
-	}
-	f, err := file.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
-		return
-	}
-	defer f.Close()
-	buf := make([]byte, file.Size)
-	if _, err := f.Read(buf); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
+	buf, filename, ok := leerExcelSubido(c, 10)
+	if !ok {
 		return
 	}
 
@@ -156,12 +139,13 @@ func (h *InstructorHandler) ImportInstructores(c *gin.Context) {
 		}
 	}
 
-	result, err := h.instructorImportSvc.ImportFromExcel(buf, file.Filename, userID, regionalID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, result)
+	encolarImportacion(c, h.importaciones, services.ImportacionNueva{
+		Tipo:       models.ImportacionTipoInstructores,
+		UserID:     userID,
+		Filename:   filename,
+		Archivo:    buf,
+		RegionalID: regionalID,
+	})
 }
 
 // ListInstructorImports devuelve el historial de importaciones de instructores.
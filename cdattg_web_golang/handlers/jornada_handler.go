@@ -51,7 +51,7 @@ func (h *JornadaHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	item, err := h.svc.Update(uint(id), req)
+	item, err := h.svc.Update(actorAuditoria(c), uint(id), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -65,7 +65,7 @@ func (h *JornadaHandler) Propagar(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
 		return
 	}
-	result, err := h.svc.Propagar(uint(id))
+	result, err := h.svc.Propagar(actorAuditoria(c), uint(id))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
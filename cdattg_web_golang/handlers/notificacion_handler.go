@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// NotificacionHandler centro de notificaciones del usuario autenticado.
+type NotificacionHandler struct {
+	svc services.NotificacionUsuarioService
+}
+
+func NewNotificacionHandler() *NotificacionHandler {
+	return &NotificacionHandler{svc: services.NewNotificacionUsuarioService()}
+}
+
+// List notificaciones del usuario, las más recientes primero
+// @Router /api/notificaciones [get]
+func (h *NotificacionHandler) List(c *gin.Context) {
+	userID := c.GetUint("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	soloNoLeidas := c.Query("solo_no_leidas") == "true"
+	list, total, err := h.svc.List(userID, soloNoLeidas, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	noLeidas, err := h.svc.CountNoLeidas(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "total": total, "page": page, "page_size": pageSize, "no_leidas": noLeidas})
+}
+
+// CountNoLeidas conteo para el indicador de la campana
+// @Router /api/notificaciones/no-leidas [get]
+func (h *NotificacionHandler) CountNoLeidas(c *gin.Context) {
+	n, err := h.svc.CountNoLeidas(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"no_leidas": n}})
+}
+
+// MarcarLeida marca una notificación propia como leída
+// @Router /api/notificaciones/{id}/leida [put]
+func (h *NotificacionHandler) MarcarLeida(c *gin.Context) {
+	id, err := parseUintParam(c, "id")
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return
+	}
+	n, err := h.svc.MarcarLeida(c.GetUint("userID"), id)
+	if err != nil {
+		if errors.Is(err, services.ErrNotificacionNoEncontrada) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"no_leidas": n}})
+}
+
+// MarcarTodasLeidas marca como leídas todas las notificaciones del usuario
+// @Router /api/notificaciones/leidas [put]
+func (h *NotificacionHandler) MarcarTodasLeidas(c *gin.Context) {
+	n, err := h.svc.MarcarTodasLeidas(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"no_leidas": n}})
+}
+
+// GetPreferencias tipos de notificación que aplican al usuario según su rol y si están activos
+// @Router /api/notificaciones/preferencias [get]
+func (h *NotificacionHandler) GetPreferencias(c *gin.Context) {
+	out, err := h.svc.Preferencias(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// ActualizarPreferencia desactiva o reactiva un tipo de notificación
+// @Router /api/notificaciones/preferencias [put]
+func (h *NotificacionHandler) ActualizarPreferencia(c *gin.Context) {
+	var req dto.ActualizarNotificacionPreferenciaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	out, err := h.svc.ActualizarPreferencia(c.GetUint("userID"), req)
+	if err != nil {
+		if errors.Is(err, services.ErrNotificacionTipoNoAplica) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
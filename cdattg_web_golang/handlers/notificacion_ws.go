@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/services"
+	"gorm.io/gorm"
+)
+
+// canalNotificacionesUsuario canal de LISTEN/NOTIFY para las notificaciones en la aplicación.
+const canalNotificacionesUsuario = "notificaciones_usuario"
+
+// NotificacionesHub conexiones WebSocket del centro de notificaciones, agrupadas por usuario.
+type NotificacionesHub struct {
+	mu      sync.RWMutex
+	clients map[uint]map[*notificacionClient]struct{}
+	// backend reenvía los eventos a las demás réplicas de la API; nil solo entrega a los clientes locales.
+	backend NotificacionesBackend
+}
+
+type notificacionClient struct {
+	conn   *websocket.Conn
+	send   chan []byte
+	userID uint
+}
+
+var globalNotificacionesHub = &NotificacionesHub{
+	clients: make(map[uint]map[*notificacionClient]struct{}),
+}
+
+// GetNotificacionesHub devuelve el hub global de notificaciones.
+func GetNotificacionesHub() *NotificacionesHub {
+	return globalNotificacionesHub
+}
+
+// NotificacionesBackend reenvía los eventos de notificación entre réplicas (mismo criterio que los de asistencia).
+type NotificacionesBackend interface {
+	Publicar(userID uint, ev dto.NotificacionEvento) error
+	Escuchar(ctx context.Context, entregar func(userID uint, ev dto.NotificacionEvento))
+}
+
+// IniciarNotificacionesUsuario conecta el servicio de notificaciones con el hub y, con ASISTENCIA_EVENTOS_BACKEND=postgres,
+// difunde los eventos entre réplicas por LISTEN/NOTIFY.
+func IniciarNotificacionesUsuario(ctx context.Context) {
+	hub := GetNotificacionesHub()
+	services.RegistrarEntregaNotificaciones(hub.Publicar)
+	if nombreBackendEventos() != BackendEventosPostgres {
+		return
+	}
+	db := database.GetDB()
+	if db == nil || config.AppConfig == nil {
+		log.Printf("[notificaciones-ws] backend postgres sin base de datos; las notificaciones quedan en esta réplica")
+		return
+	}
+	hub.UsarBackend(ctx, NewNotificacionesPostgres(db, config.GetDSN()))
+}
+
+// Publicar entrega el evento a las conexiones locales del usuario y lo reenvía a las demás réplicas.
+func (h *NotificacionesHub) Publicar(userID uint, ev dto.NotificacionEvento) {
+	h.entregar(userID, ev)
+	h.mu.RLock()
+	backend := h.backend
+	h.mu.RUnlock()
+	if backend == nil {
+		return
+	}
+	if err := backend.Publicar(userID, ev); err != nil {
+		log.Printf("[notificaciones-ws] reenviar %s a otras réplicas: %v", ev.Type, err)
+	}
+}
+
+func (h *NotificacionesHub) entregar(userID uint, ev dto.NotificacionEvento) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	conexiones := h.clients[userID]
+	if len(conexiones) == 0 {
+		return
+	}
+	msg, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("[notificaciones-ws] marshal %s: %v", ev.Type, err)
+		return
+	}
+	for c := range conexiones {
+		select {
+		case c.send <- msg:
+		default:
+			// canal lleno, omitir
+		}
+	}
+}
+
+// UsarBackend reemplaza el backend del hub y empieza a escuchar los eventos de las demás réplicas.
+func (h *NotificacionesHub) UsarBackend(ctx context.Context, backend NotificacionesBackend) {
+	h.mu.Lock()
+	h.backend = backend
+	h.mu.Unlock()
+	if backend != nil {
+		go backend.Escuchar(ctx, h.entregar)
+	}
+}
+
+// Register añade un cliente al hub.
+func (h *NotificacionesHub) Register(c *notificacionClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[c.userID] == nil {
+		h.clients[c.userID] = make(map[*notificacionClient]struct{})
+	}
+	h.clients[c.userID][c] = struct{}{}
+}
+
+// Unregister quita un cliente del hub.
+func (h *NotificacionesHub) Unregister(c *notificacionClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients[c.userID], c)
+	if len(h.clients[c.userID]) == 0 {
+		delete(h.clients, c.userID)
+	}
+}
+
+// Run inicia el loop de escritura del cliente (debe llamarse en goroutine).
+func (c *notificacionClient) Run(hub *NotificacionesHub) {
+	defer func() {
+		hub.Unregister(c)
+		_ = c.conn.Close()
+	}()
+	for msg := range c.send {
+		if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			log.Printf("[notificaciones-ws] write: %v", err)
+			return
+		}
+	}
+}
+
+// NotificacionesWebSocket canal del usuario autenticado: nuevas notificaciones y cambios del conteo de no leídas.
+// Token por query: ?token=xxx
+func NotificacionesWebSocket(c *gin.Context) {
+	user, _, ok := autenticarAsistenciaWS(c)
+	if !ok {
+		return
+	}
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("[notificaciones-ws] upgrade: %v", err)
+		return
+	}
+	client := &notificacionClient{conn: conn, send: make(chan []byte, 64), userID: user.ID}
+	hub := GetNotificacionesHub()
+	hub.Register(client)
+	go client.Run(hub)
+	go func() {
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				close(client.send)
+				return
+			}
+		}
+	}()
+}
+
+// nombreBackendEventos backend configurado en ASISTENCIA_EVENTOS_BACKEND, normalizado.
+func nombreBackendEventos() string {
+	if config.AppConfig == nil {
+		return BackendEventosMemoria
+	}
+	nombre := strings.ToLower(strings.TrimSpace(config.AppConfig.Eventos.Backend))
+	if nombre == "" {
+		return BackendEventosMemoria
+	}
+	return nombre
+}
+
+// notificacionEventoNotify sobre enviado por NOTIFY.
+type notificacionEventoNotify struct {
+	Origen string                 `json:"origen"`
+	UserID uint                   `json:"user_id"`
+	Evento dto.NotificacionEvento `json:"evento"`
+}
+
+type notificacionesPostgres struct {
+	db        *gorm.DB
+	dsn       string
+	instancia string
+}
+
+// NewNotificacionesPostgres crea el backend de LISTEN/NOTIFY de las notificaciones.
+func NewNotificacionesPostgres(db *gorm.DB, dsn string) NotificacionesBackend {
+	return &notificacionesPostgres{db: db, dsn: dsn, instancia: instanciaEventosPostgres()}
+}
+
+// codificarNotificacionNotify arma el payload de NOTIFY. Si excede el límite, el evento viaja sin la notificación y
+// el cliente recarga la lista al recibirlo.
+func codificarNotificacionNotify(origen string, userID uint, ev dto.NotificacionEvento) ([]byte, error) {
+	payload, err := json.Marshal(notificacionEventoNotify{Origen: origen, UserID: userID, Evento: ev})
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) <= maxPayloadNotify {
+		return payload, nil
+	}
+	if ev.Notificacion != nil || len(ev.IDs) > 0 {
+		ev.Notificacion = nil
+		ev.IDs = nil
+		return codificarNotificacionNotify(origen, userID, ev)
+	}
+	return nil, fmt.Errorf("evento %s excede %d bytes", ev.Type, maxPayloadNotify)
+}
+
+func (b *notificacionesPostgres) Publicar(userID uint, ev dto.NotificacionEvento) error {
+	payload, err := codificarNotificacionNotify(b.instancia, userID, ev)
+	if err != nil {
+		return err
+	}
+	return b.db.Exec("SELECT pg_notify(?, ?)", canalNotificacionesUsuario, string(payload)).Error
+}
+
+func (b *notificacionesPostgres) Escuchar(ctx context.Context, entregar func(userID uint, ev dto.NotificacionEvento)) {
+	escucharCanalPostgres(ctx, b.dsn, canalNotificacionesUsuario, func(payload string) {
+		var msg notificacionEventoNotify
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			log.Printf("[notificaciones-ws] payload inválido: %v", err)
+			return
+		}
+		if msg.Origen == b.instancia {
+			return
+		}
+		entregar(msg.UserID, msg.Evento)
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sena/cdattg-web-golang/dto"
+)
+
+func TestNotificacionesHubEntregaSoloAlUsuario(t *testing.T) {
+	hub := &NotificacionesHub{clients: make(map[uint]map[*notificacionClient]struct{})}
+	pestana1 := &notificacionClient{userID: 1, send: make(chan []byte, 2)}
+	pestana2 := &notificacionClient{userID: 1, send: make(chan []byte, 2)}
+	otro := &notificacionClient{userID: 2, send: make(chan []byte, 2)}
+	for _, c := range []*notificacionClient{pestana1, pestana2, otro} {
+		hub.Register(c)
+	}
+
+	hub.Publicar(1, dto.NotificacionEvento{Type: "leidas", NoLeidas: 4})
+	for _, c := range []*notificacionClient{pestana1, pestana2} {
+		select {
+		case msg := <-c.send:
+			var ev dto.NotificacionEvento
+			if err := json.Unmarshal(msg, &ev); err != nil || ev.NoLeidas != 4 {
+				t.Fatalf("evento = %s (%v)", msg, err)
+			}
+		default:
+			t.Fatal("cada conexión del usuario debe recibir el evento")
+		}
+	}
+	if len(otro.send) != 0 {
+		t.Fatal("otro usuario no debe recibir el evento")
+	}
+
+	hub.Unregister(pestana1)
+	hub.Unregister(pestana2)
+	if _, ok := hub.clients[1]; ok {
+		t.Fatal("el usuario sin conexiones debe salir del hub")
+	}
+}
+
+func TestCodificarNotificacionNotifyRespetaLimite(t *testing.T) {
+	ev := dto.NotificacionEvento{
+		Type:         "nueva",
+		Notificacion: &dto.NotificacionUsuarioResponse{ID: 9, Mensaje: strings.Repeat("x", maxPayloadNotify)},
+		NoLeidas:     3,
+	}
+	payload, err := codificarNotificacionNotify("a", 5, ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) > maxPayloadNotify {
+		t.Fatalf("payload de %d bytes excede el límite", len(payload))
+	}
+	var msg notificacionEventoNotify
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.UserID != 5 || msg.Evento.Notificacion != nil || msg.Evento.NoLeidas != 3 {
+		t.Fatalf("payload largo: got %+v (%v), want evento sin notificación con el conteo", msg, err)
+	}
+}
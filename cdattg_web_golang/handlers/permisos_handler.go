@@ -66,7 +66,7 @@ func (h *PermisosHandler) AsignarPermiso(c *gin.Context) {
 		return
 	}
 	userID := getParamID(c, "id")
-	if err := h.svc.AsignarPermisoDirecto(userID, req.Obj, req.Act); err != nil {
+	if err := h.svc.AsignarPermisoDirecto(actorAuditoria(c), userID, req.Obj, req.Act); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -83,7 +83,7 @@ func (h *PermisosHandler) QuitarPermiso(c *gin.Context) {
 	obj := c.Param("obj")
 	act := c.Param("act")
 	userID := getParamID(c, "id")
-	if err := h.svc.QuitarPermisoDirecto(userID, obj, act); err != nil {
+	if err := h.svc.QuitarPermisoDirecto(actorAuditoria(c), userID, obj, act); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -103,7 +103,7 @@ func (h *PermisosHandler) SetRoles(c *gin.Context) {
 		return
 	}
 	userID := getParamID(c, "id")
-	if err := h.svc.SetRoles(userID, req.Roles); err != nil {
+	if err := h.svc.SetRoles(actorAuditoria(c), userID, req.Roles); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -118,7 +118,7 @@ func (h *PermisosHandler) ToggleEstado(c *gin.Context) {
 		return
 	}
 	userID := getParamID(c, "id")
-	if err := h.svc.ToggleEstado(userID); err != nil {
+	if err := h.svc.ToggleEstado(actorAuditoria(c), userID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -149,7 +149,7 @@ func (h *PermisosHandler) SetUsuarioRegionales(c *gin.Context) {
 		return
 	}
 	userID := getParamID(c, "id")
-	if err := h.svc.SetUsuarioRegionales(userID, req.RegionalIDs); err != nil {
+	if err := h.svc.SetUsuarioRegionales(actorAuditoria(c), userID, req.RegionalIDs); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"encoding/json"
 	"net/http"
 	"strconv"
 	"strings"
@@ -19,6 +18,7 @@ const errMsgPersonaNoEncontrada = "Persona no encontrada"
 type PersonaHandler struct {
 	personaService   services.PersonaService
 	personaImportSvc services.PersonaImportService
+	importaciones    services.ImportacionTrabajoService
 }
 
 func NewPersonaHandler() *PersonaHandler {
@@ -26,6 +26,7 @@ func NewPersonaHandler() *PersonaHandler {
 	return &PersonaHandler{
 		personaService:   personaSvc,
 		personaImportSvc: services.NewPersonaImportService(personaSvc),
+		importaciones:    services.NewImportacionTrabajoService(),
 	}
 }
 
@@ -88,7 +89,7 @@ func (h *PersonaHandler) Create(c *gin.Context) {
 		return
 	}
 
-	persona, err := h.personaService.Create(req)
+	persona, err := h.personaService.Create(actorAuditoria(c), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -111,7 +112,7 @@ func (h *PersonaHandler) Update(c *gin.Context) {
 		return
 	}
 
-	persona, err := h.personaService.Update(uint(id), req)
+	persona, err := h.personaService.Update(actorAuditoria(c), uint(id), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -156,7 +157,7 @@ func (h *PersonaHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.personaService.Delete(uint(id)); err != nil {
+	if err := h.personaService.Delete(actorAuditoria(c), uint(id)); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": errMsgPersonaNoEncontrada})
 		return
 	}
@@ -180,7 +181,8 @@ func (h *PersonaHandler) ResetPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Contraseña restablecida al número de documento"})
 }
 
-// ImportPersonas sube un Excel e importa personas (validación de duplicados por documento, correo, celular).
+// ImportPersonas sube un Excel y encola la importación de personas (validación de duplicados por documento, correo,
+// celular). Responde 202 con el trabajo.
 func (h *PersonaHandler) ImportPersonas(c *gin.Context) {
 	userIDVal, exists := c.Get("userID")
 	if !exists {
@@ -189,73 +191,16 @@ func (h *PersonaHandler) ImportPersonas(c *gin.Context) {
 	}
 	userID := userIDVal.(uint)
 
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el archivo 'file'"})
-		return
-	}
-	if file.Size == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo está vacío"})
-		return
-	}
-	if file.Size > 10*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo no debe superar 10 MB"})
-		return
-	}
-	f, err := file.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
-		return
-	}
-	defer f.Close()
-	buf := make([]byte, file.Size)
-	if _, err := f.Read(buf); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
-		return
-	}
-
-	// Si el cliente pide streaming, responder con NDJSON y progreso en tiempo real
-	if c.GetHeader("X-Stream-Progress") == "true" {
-		h.importPersonasStream(c, buf, file.Filename, userID)
-		return
-	}
-
-	result, err := h.personaImportSvc.ImportFromExcel(buf, file.Filename, userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, result)
-}
-
-// importPersonasStream escribe NDJSON (progress/done/error) y hace flush tras cada línea.
-func (h *PersonaHandler) importPersonasStream(c *gin.Context, buf []byte, filename string, userID uint) {
-	c.Header("Content-Type", "application/x-ndjson")
-	c.Header("Cache-Control", "no-cache")
-	c.Header("X-Accel-Buffering", "no")
-	flusher, ok := c.Writer.(http.Flusher)
+	buf, filename, ok := leerExcelSubido(c, 10)
 	if !ok {
-		_ = json.NewEncoder(c.Writer).Encode(map[string]string{"type": "error", "error": "streaming no soportado"})
-		return
-	}
-	result, err := h.personaImportSvc.ImportFromExcelWithProgress(buf, filename, userID, func(p services.ImportProgress) {
-		_ = json.NewEncoder(c.Writer).Encode(p)
-		flusher.Flush()
-	})
-	if err != nil {
-		_ = json.NewEncoder(c.Writer).Encode(map[string]string{"type": "error", "error": err.Error()})
-		flusher.Flush()
 		return
 	}
-	// "done" ya se envió en el callback; enviar resultado final por si el cliente lo usa
-	_ = json.NewEncoder(c.Writer).Encode(map[string]interface{}{
-		"type":             "result",
-		"processed_count":  result.ProcessedCount,
-		"duplicates_count": result.DuplicatesCount,
-		"error_count":      result.ErrorCount,
-		"status":           result.Status,
+	encolarImportacion(c, h.importaciones, services.ImportacionNueva{
+		Tipo:     models.ImportacionTipoPersonas,
+		UserID:   userID,
+		Filename: filename,
+		Archivo:  buf,
 	})
-	flusher.Flush()
 }
 
 // ListPersonaImports devuelve el historial de importaciones.
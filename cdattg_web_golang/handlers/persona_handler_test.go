@@ -41,7 +41,7 @@ func (m *mockPersonaService) FindByID(id uint) (*dto.PersonaResponse, error) {
 
 func (m *mockPersonaService) FindByNumeroDocumento(string) (*dto.PersonaResponse, error) { return nil, nil }
 
-func (m *mockPersonaService) Create(req dto.PersonaRequest) (*dto.PersonaResponse, error) {
+func (m *mockPersonaService) Create(_ dto.Actor, req dto.PersonaRequest) (*dto.PersonaResponse, error) {
 	if m.createFunc != nil {
 		return m.createFunc(req)
 	}
@@ -49,14 +49,14 @@ func (m *mockPersonaService) Create(req dto.PersonaRequest) (*dto.PersonaRespons
 }
 
 func (m *mockPersonaService) CreateWithoutUser(req dto.PersonaRequest) (*dto.PersonaResponse, error) {
-	return m.Create(req)
+	return m.Create(dto.Actor{}, req)
 }
 
 func (m *mockPersonaService) EnsureUsersForPersonas([]uint) error {
 	return nil
 }
 
-func (m *mockPersonaService) Update(id uint, req dto.PersonaRequest) (*dto.PersonaResponse, error) {
+func (m *mockPersonaService) Update(_ dto.Actor, id uint, req dto.PersonaRequest) (*dto.PersonaResponse, error) {
 	if m.updateFunc != nil {
 		return m.updateFunc(id, req)
 	}
@@ -70,7 +70,7 @@ func (m *mockPersonaService) UpdateSelf(id uint, req dto.PersonaSelfUpdateReques
 	return nil, nil
 }
 
-func (m *mockPersonaService) Delete(id uint) error {
+func (m *mockPersonaService) Delete(_ dto.Actor, id uint) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(id)
 	}
@@ -88,14 +88,6 @@ type mockPersonaImportService struct {
 	listImportsFunc func(int) ([]services.ImportLogItem, error)
 }
 
-func (m *mockPersonaImportService) ImportFromExcel([]byte, string, uint) (*services.ImportResult, error) {
-	return nil, nil
-}
-
-func (m *mockPersonaImportService) ImportFromExcelWithProgress([]byte, string, uint, func(services.ImportProgress)) (*services.ImportResult, error) {
-	return nil, nil
-}
-
 func (m *mockPersonaImportService) ListImports(limit int) ([]services.ImportLogItem, error) {
 	if m.listImportsFunc != nil {
 		return m.listImportsFunc(limit)
@@ -6,18 +6,19 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/services"
 )
 
 type ProgramaFormacionHandler struct {
-	svc       services.ProgramaFormacionService
-	importSvc services.ProgramaFormacionImportService
+	svc           services.ProgramaFormacionService
+	importaciones services.ImportacionTrabajoService
 }
 
 func NewProgramaFormacionHandler() *ProgramaFormacionHandler {
 	return &ProgramaFormacionHandler{
-		svc:       services.NewProgramaFormacionService(),
-		importSvc: services.NewProgramaFormacionImportService(),
+		svc:           services.NewProgramaFormacionService(),
+		importaciones: services.NewImportacionTrabajoService(),
 	}
 }
 
@@ -100,36 +101,17 @@ func (h *ProgramaFormacionHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
-// ImportProgramas sube el Excel catálogo e importa solo programas TÉCNICO y TECNÓLOGO (versión más alta por código).
+// ImportProgramas sube el Excel catálogo y encola la importación de los programas TÉCNICO, TECNÓLOGO, OPERARIO y
+// AUXILIAR (versión más alta por código). Responde 202 con el trabajo.
 func (h *ProgramaFormacionHandler) ImportProgramas(c *gin.Context) {
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el archivo 'file'"})
-		return
-	}
-	if file.Size == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo está vacío"})
-		return
-	}
-	if file.Size > 20*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo no debe superar 20 MB"})
-		return
-	}
-	f, err := file.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo leer el archivo"})
-		return
-	}
-	defer f.Close()
-	buf := make([]byte, file.Size)
-	if _, err := f.Read(buf); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error leyendo el archivo"})
-		return
-	}
-	result, err := h.importSvc.ImportFromCatalogoExcel(buf, file.Filename)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	buf, filename, ok := leerExcelSubido(c, 20)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	encolarImportacion(c, h.importaciones, services.ImportacionNueva{
+		Tipo:     models.ImportacionTipoProgramas,
+		UserID:   c.GetUint("userID"),
+		Filename: filename,
+		Archivo:  buf,
+	})
 }
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// ReporteAsistenciaHandler reportes consolidados de asistencia por ficha y periodo (PDF / XLSX).
+type ReporteAsistenciaHandler struct {
+	svc *services.ReporteAsistenciaService
+}
+
+func NewReporteAsistenciaHandler() *ReporteAsistenciaHandler {
+	return &ReporteAsistenciaHandler{svc: services.NewReporteAsistenciaService()}
+}
+
+func respondReporteAsistenciaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrReporteNoEncontrado):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReporteNoDisponible):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReporteArchivoExpirado):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReporteFormatoInvalido), errors.Is(err, services.ErrReporteRangoInvalido):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// responderReporteConsolidado atiende GET /asistencias/ficha/:fichaId?...&formato=pdf|xlsx. Rangos cortos devuelven
+// el archivo; los largos responden 202 con el reporte en cola (consultar /asistencias/ficha/:fichaId/reportes/:reporteId).
+func responderReporteConsolidado(c *gin.Context, svc *services.ReporteAsistenciaService, fichaID uint, fechaInicio, fechaFin string) {
+	archivo, rep, err := svc.Solicitar(c.GetUint("userID"), fichaID, fechaInicio, fechaFin, c.Query("formato"))
+	if err != nil {
+		respondReporteAsistenciaError(c, err)
+		return
+	}
+	if rep != nil {
+		c.JSON(http.StatusAccepted, gin.H{"data": rep})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archivo.Nombre))
+	c.Data(http.StatusOK, archivo.ContentType, archivo.Datos)
+}
+
+func reporteParams(c *gin.Context) (uint, uint, bool) {
+	fichaID, err := parseUintParam(c, "fichaId")
+	if err != nil || fichaID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgFichaIDInvalidoQuery})
+		return 0, 0, false
+	}
+	reporteID, err := parseUintParam(c, "reporteId")
+	if err != nil || reporteID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgIDInvalido})
+		return 0, 0, false
+	}
+	return fichaID, reporteID, true
+}
+
+// List GET /asistencias/ficha/:fichaId/reportes últimos reportes generados en segundo plano
+func (h *ReporteAsistenciaHandler) List(c *gin.Context) {
+	fichaID, err := parseUintParam(c, "fichaId")
+	if err != nil || fichaID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgFichaIDInvalidoQuery})
+		return
+	}
+	list, err := h.svc.ListByFicha(fichaID)
+	if err != nil {
+		respondReporteAsistenciaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Estado GET /asistencias/ficha/:fichaId/reportes/:reporteId
+func (h *ReporteAsistenciaHandler) Estado(c *gin.Context) {
+	fichaID, reporteID, ok := reporteParams(c)
+	if !ok {
+		return
+	}
+	rep, err := h.svc.Estado(fichaID, reporteID)
+	if err != nil {
+		respondReporteAsistenciaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rep)
+}
+
+// Descargar GET /asistencias/ficha/:fichaId/reportes/:reporteId/archivo
+func (h *ReporteAsistenciaHandler) Descargar(c *gin.Context) {
+	fichaID, reporteID, ok := reporteParams(c)
+	if !ok {
+		return
+	}
+	archivo, err := h.svc.Archivo(fichaID, reporteID)
+	if err != nil {
+		respondReporteAsistenciaError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archivo.Nombre))
+	c.Data(http.StatusOK, archivo.ContentType, archivo.Datos)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// ResumenSemanalHandler preferencias del usuario sobre los resúmenes semanales por correo.
+type ResumenSemanalHandler struct {
+	svc services.ResumenSemanalService
+}
+
+func NewResumenSemanalHandler() *ResumenSemanalHandler {
+	return &ResumenSemanalHandler{svc: services.NewResumenSemanalService()}
+}
+
+// GetPreferencias resúmenes que aplican al usuario según su rol y si están activos
+// @Router /api/auth/resumenes-semanales [get]
+func (h *ResumenSemanalHandler) GetPreferencias(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	out, err := h.svc.Preferencias(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// ActualizarPreferencia cancela o reactiva un tipo de resumen semanal
+// @Router /api/auth/resumenes-semanales [put]
+func (h *ResumenSemanalHandler) ActualizarPreferencia(c *gin.Context) {
+	var req dto.ActualizarResumenSemanalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsgDatosInvalidos, "details": err.Error()})
+		return
+	}
+	userID, _ := c.Get("userID")
+	out, err := h.svc.ActualizarPreferencia(userID.(uint), req)
+	if err != nil {
+		if errors.Is(err, services.ErrResumenSemanalNoAplica) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
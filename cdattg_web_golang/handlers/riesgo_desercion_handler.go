@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// RiesgoDesercionHandler ranking de riesgo de deserción (rutas protegidas con RequireSuperAdminOrBienestar).
+type RiesgoDesercionHandler struct {
+	svc *services.RiesgoDesercionService
+}
+
+func NewRiesgoDesercionHandler() *RiesgoDesercionHandler {
+	return &RiesgoDesercionHandler{svc: services.NewRiesgoDesercionService()}
+}
+
+// List aprendices ordenados por puntaje de riesgo. Query: sede_id, ficha_id (opcionales), dias (default 30), nivel (bajo, medio, alto).
+// @Router /api/bienestar/riesgo-desercion [get]
+func (h *RiesgoDesercionHandler) List(c *gin.Context) {
+	sedeID, ok := queryUintOpcional(c, "sede_id")
+	if !ok {
+		return
+	}
+	fichaID, ok := queryUintOpcional(c, "ficha_id")
+	if !ok {
+		return
+	}
+	dias := 0
+	if s := c.Query("dias"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dias debe estar entre 1 y 365"})
+			return
+		}
+		dias = n
+	}
+	nivel := c.Query("nivel")
+	switch nivel {
+	case "", services.NivelRiesgoDesercionBajo, services.NivelRiesgoDesercionMedio, services.NivelRiesgoDesercionAlto:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nivel inválido (bajo, medio o alto)"})
+		return
+	}
+	resp, err := h.svc.Calcular(sedeID, fichaID, dias)
+	if err != nil {
+		if errors.Is(err, services.ErrRiesgoDesercionFichaNoExiste) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if nivel != "" {
+		filtrados := resp.Aprendices[:0]
+		for _, a := range resp.Aprendices {
+			if a.Nivel == nivel {
+				filtrados = append(filtrados, a)
+			}
+		}
+		resp.Aprendices = filtrados
+	}
+	c.JSON(http.StatusOK, resp)
+}
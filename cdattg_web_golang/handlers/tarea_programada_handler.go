@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/scheduler"
+	"github.com/sena/cdattg-web-golang/services"
+)
+
+// Nombres de las tareas registradas en el planificador.
+const (
+	TareaAutoCierreAsistencia      = "asistencia-auto-cierre"
+	TareaAlertaAsistenciaSinSesion = "asistencia-alerta-sin-sesion"
+	TareaLimpiezaSesiones          = "auth-limpieza-sesiones"
+	TareaLimpiezaHistorialLogin    = "auth-limpieza-historial-login"
+	TareaRecordatoriosBienestar    = "bienestar-recordatorios"
+	TareaResumenesSemanales        = "resumenes-semanales"
+	TareaEnvioCorreos              = "correo-envio"
+	TareaLimpiezaCorreos           = "correo-limpieza"
+	TareaLimpiezaNotificaciones    = "notificaciones-limpieza"
+	TareaRecuperarImportaciones    = "importaciones-recuperar"
+	TareaLimpiezaImportaciones     = "importaciones-limpieza"
+	TareaRecuperarReportes         = "reportes-asistencia-recuperar"
+	TareaLimpiezaReportes          = "reportes-asistencia-limpieza"
+	TareaTransicionesElecciones    = "elecciones-transiciones"
+)
+
+// RegisterTareasProgramadas registra las tareas periódicas de la aplicación; el planificador lo arranca
+// IniciarTareasProgramadas. Nuevas tareas periódicas deben registrarse aquí.
+func RegisterTareasProgramadas(asistencia *AsistenciaHandler) {
+	sched := scheduler.Default()
+	if err := sched.Register(
+		TareaAutoCierreAsistencia,
+		"Finaliza sesiones de asistencia cuyo horario de jornada (más extensión) ya terminó",
+		services.ExpresionAutoCierreAsistencia(),
+		func(ctx context.Context) error {
+			cerradas, err := asistencia.svc.FinalizarSesionesVencidas()
+			for _, id := range cerradas {
+				asistencia.publicarEventoAsistencia(EventoAsistenciaSesionCerrada, id, nil)
+			}
+			return err
+		},
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaAutoCierreAsistencia, err)
+	}
+
+	schedCfg := config.SchedulerActual()
+	alertaSvc := services.NewAlertaAsistenciaService()
+	if err := sched.Register(
+		TareaAlertaAsistenciaSinSesion,
+		"Avisa por correo a coordinadores de fichas que no han iniciado toma de asistencia",
+		schedCfg.AlertaAsistenciaCron,
+		func(ctx context.Context) error { return alertaSvc.CheckAndNotify() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaAlertaAsistenciaSinSesion, err)
+	}
+
+	if err := sched.Register(
+		TareaLimpiezaSesiones,
+		"Elimina sesiones de usuario y enlaces de recuperación o verificación vencidos hace más de 30 días",
+		"30 3 * * *",
+		func(ctx context.Context) error { return services.PurgarSesionesCaducadas() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaLimpiezaSesiones, err)
+	}
+
+	if err := sched.Register(
+		TareaLimpiezaHistorialLogin,
+		"Elimina intentos de login de más de 180 días y contadores de bloqueo inactivos",
+		"45 3 * * *",
+		func(ctx context.Context) error { return services.PurgarHistorialLogin() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaLimpiezaHistorialLogin, err)
+	}
+
+	casoBienestarSvc := services.NewCasoBienestarService()
+	if err := sched.Register(
+		TareaRecordatoriosBienestar,
+		"Avisa por correo al responsable de cada recordatorio vencido de los casos de bienestar",
+		"*/15 * * * *",
+		func(ctx context.Context) error { return casoBienestarSvc.NotificarRecordatorios() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaRecordatoriosBienestar, err)
+	}
+
+	resumenSvc := services.NewResumenSemanalService()
+	if err := sched.Register(
+		TareaResumenesSemanales,
+		"Envía por correo el resumen semanal a coordinadores (por regional) e instructores que no lo hayan cancelado",
+		schedCfg.ResumenSemanalCron,
+		func(ctx context.Context) error { return resumenSvc.EnviarResumenes() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaResumenesSemanales, err)
+	}
+
+	correoSvc := services.NewCorreoService()
+	if err := sched.Register(
+		TareaEnvioCorreos,
+		"Envía los correos pendientes de la cola de salida y reintenta los que fallaron",
+		"*/2 * * * *",
+		func(ctx context.Context) error { return correoSvc.ProcesarPendientes(ctx) },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaEnvioCorreos, err)
+	}
+
+	if err := sched.Register(
+		TareaLimpiezaCorreos,
+		"Elimina de la cola de salida los correos enviados o fallidos de más de 90 días",
+		"15 4 * * *",
+		func(ctx context.Context) error { return correoSvc.PurgarAntiguos() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaLimpiezaCorreos, err)
+	}
+
+	notificacionSvc := services.NewNotificacionUsuarioService()
+	if err := sched.Register(
+		TareaLimpiezaNotificaciones,
+		"Elimina las notificaciones en la aplicación leídas hace más de 90 días",
+		"30 4 * * *",
+		func(ctx context.Context) error { return notificacionSvc.PurgarLeidasAntiguas() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaLimpiezaNotificaciones, err)
+	}
+
+	importacionSvc := services.NewImportacionTrabajoService()
+	if err := sched.Register(
+		TareaRecuperarImportaciones,
+		"Procesa las importaciones de Excel en cola y retoma las que quedaron a medias en una réplica caída",
+		"* * * * *",
+		func(ctx context.Context) error { return importacionSvc.ProcesarPendientes(ctx) },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaRecuperarImportaciones, err)
+	}
+
+	if err := sched.Register(
+		TareaLimpiezaImportaciones,
+		"Elimina las importaciones de Excel terminadas hace más de 30 días con su archivo e incidencias",
+		"45 4 * * *",
+		func(ctx context.Context) error { return importacionSvc.PurgarAntiguos() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaLimpiezaImportaciones, err)
+	}
+
+	reporteSvc := services.NewReporteAsistenciaService()
+	if err := sched.Register(
+		TareaRecuperarReportes,
+		"Genera los reportes consolidados de asistencia en cola y retoma los que quedaron a medias en una réplica caída",
+		"* * * * *",
+		func(ctx context.Context) error { return reporteSvc.ProcesarPendientes(ctx) },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaRecuperarReportes, err)
+	}
+
+	if err := sched.Register(
+		TareaLimpiezaReportes,
+		"Elimina los reportes consolidados de asistencia terminados hace más de 30 días con su archivo",
+		"50 4 * * *",
+		func(ctx context.Context) error { return reporteSvc.PurgarAntiguos() },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaLimpiezaReportes, err)
+	}
+
+	eleccionSvc := services.NewEleccionService()
+	if err := sched.Register(
+		TareaTransicionesElecciones,
+		"Avanza las fases de los procesos electorales con transiciones automáticas y calcula el resultado al cerrar la votación",
+		schedCfg.EleccionesTransicionesCron,
+		func(ctx context.Context) error { return eleccionSvc.AvanzarFasesAutomaticas(ctx) },
+	); err != nil {
+		log.Printf("[scheduler] no se pudo registrar %s: %v", TareaTransicionesElecciones, err)
+	}
+}
+
+// IniciarTareasProgramadas arranca el planificador (si está habilitado y hay BD) con las tareas ya registradas por
+// RegisterTareasProgramadas. Las ejecuciones se cancelan con ctx.
+func IniciarTareasProgramadas(ctx context.Context) {
+	if !config.SchedulerActual().Enabled {
+		return
+	}
+	sched := scheduler.Default()
+	sched.Start(ctx)
+	// Cierra de una vez las sesiones que vencieron mientras la API estaba detenida.
+	sched.EjecutarAlArranque(TareaAutoCierreAsistencia)
+}
+
+type TareaProgramadaHandler struct {
+	svc services.TareaProgramadaService
+}
+
+func NewTareaProgramadaHandler() *TareaProgramadaHandler {
+	return &TareaProgramadaHandler{svc: services.NewTareaProgramadaService()}
+}
+
+// NewTareaProgramadaHandlerWithService permite inyectar el servicio (p. ej. para tests).
+func NewTareaProgramadaHandlerWithService(svc services.TareaProgramadaService) *TareaProgramadaHandler {
+	return &TareaProgramadaHandler{svc: svc}
+}
+
+// List devuelve las tareas registradas con su próxima y última ejecución.
+func (h *TareaProgramadaHandler) List(c *gin.Context) {
+	out, err := h.svc.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// ListEjecuciones devuelve el historial de ejecuciones de una tarea (limit por query, 50 por defecto).
+func (h *TareaProgramadaHandler) ListEjecuciones(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 50
+	}
+	list, err := h.svc.ListEjecuciones(c.Param("nombre"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Ejecutar dispara manualmente una tarea en esta instancia y espera a que termine.
+func (h *TareaProgramadaHandler) Ejecutar(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	uid, _ := userID.(uint)
+	ej, err := h.svc.Ejecutar(c.Request.Context(), c.Param("nombre"), uid)
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrTareaNoEncontrada):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, scheduler.ErrTareaEnCurso):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ej})
+}
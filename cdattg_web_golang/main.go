@@ -1,16 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/sena/cdattg-web-golang/authz"
 	"github.com/sena/cdattg-web-golang/config"
 	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/database/migrations"
 	"github.com/sena/cdattg-web-golang/database/seeders"
+	"github.com/sena/cdattg-web-golang/handlers"
 	"github.com/sena/cdattg-web-golang/router"
+	"github.com/sena/cdattg-web-golang/scheduler"
 	"github.com/sena/cdattg-web-golang/utils"
 )
 
+// tiempoApagado plazo para que terminen las peticiones en curso al recibir SIGINT o SIGTERM.
+const tiempoApagado = 20 * time.Second
+
 func initAppLocation() {
 	utils.InitAppLocation()
 }
@@ -24,12 +37,19 @@ func main() {
 	if err := database.Initialize(); err != nil {
 		log.Fatal("Error inicializando base de datos:", err)
 	}
-	if err := database.EnsureSchemaPatches(); err != nil {
-		log.Fatal("Error aplicando parches de esquema:", err)
+	// El esquema lo cambia solo cmd/migrate; la API se niega a iniciar con migraciones pendientes
+	if err := migrations.VerificarAlDia(database.GetDB()); err != nil {
+		log.Fatal("Esquema de base de datos desactualizado:", err)
 	}
 	if err := seeders.SyncEleccionPermissionsToRoles(database.GetDB()); err != nil {
 		log.Fatal("Error sincronizando permisos de elecciones:", err)
 	}
+	if err := seeders.SyncEvaluacionPermissionsToRoles(database.GetDB()); err != nil {
+		log.Fatal("Error sincronizando permisos de evaluación:", err)
+	}
+	if err := seeders.SyncAsistenciaQRPermissionsToRoles(database.GetDB()); err != nil {
+		log.Fatal("Error sincronizando permisos de autorregistro de asistencia:", err)
+	}
 	if err := seeders.RunFestivosColombiaSeeder(database.GetDB()); err != nil {
 		log.Fatal("Error sembrando festivos Colombia:", err)
 	}
@@ -39,14 +59,35 @@ func main() {
 		log.Fatal("Error inicializando Casbin:", err)
 	}
 
-	// Configurar router
+	// ctx se cancela con SIGINT/SIGTERM: detiene las escuchas de eventos entre réplicas y el servidor
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	handlers.IniciarEventosAsistencia(ctx)
+	handlers.IniciarNotificacionesUsuario(ctx)
+
+	// Configurar router (registra también las tareas programadas)
 	r := router.SetupRouter()
+	handlers.IniciarTareasProgramadas(ctx)
 
 	// Iniciar servidor
 	serverAddr := config.AppConfig.Server.Host + ":" + config.AppConfig.Server.Port
+	srv := &http.Server{Addr: serverAddr, Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Error iniciando servidor:", err)
+		}
+	}()
 	log.Printf("Servidor iniciado en http://%s", serverAddr)
 
-	if err := r.Run(serverAddr); err != nil {
-		log.Fatal("Error iniciando servidor:", err)
+	<-ctx.Done()
+	stop()
+	log.Println("Deteniendo servidor...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tiempoApagado)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error deteniendo servidor: %v", err)
 	}
+	// Espera a que terminen las tareas programadas en curso y libera el liderazgo
+	scheduler.Default().Stop()
+	log.Println("Servidor detenido")
 }
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+// ctxAuditoriaDetallada marca que el servicio ya registró la operación con su diff antes/después.
+const ctxAuditoriaDetallada = "auditoria_detallada"
+
+// MarcarAuditoriaDetallada evita que Auditoria() duplique con un registro genérico una operación
+// que el servicio ya auditó en detalle.
+func MarcarAuditoriaDetallada(c *gin.Context) {
+	c.Set(ctxAuditoriaDetallada, true)
+}
+
+// Auditoria registra en registro_actividades toda llamada mutante (POST/PUT/PATCH/DELETE) que termina bien:
+// usuario, método, ruta, recurso, id e IP. No guarda el cuerpo (puede traer contraseñas o archivos).
+// Debe ir después de AuthMiddleware.
+func Auditoria() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if !esMetodoMutante(c.Request.Method) || c.Writer.Status() >= http.StatusBadRequest || c.GetBool(ctxAuditoriaDetallada) {
+			return
+		}
+		userID := c.GetUint("userID")
+		if userID == 0 || database.GetDB() == nil {
+			return
+		}
+		ruta := c.FullPath()
+		if ruta == "" {
+			ruta = c.Request.URL.Path
+		}
+		payload, _ := json.Marshal(dto.AuditoriaDetalle{
+			Metodo: c.Request.Method,
+			Ruta:   c.Request.URL.Path,
+			Estado: c.Writer.Status(),
+		})
+		reg := &models.RegistroActividades{
+			UserID:      userID,
+			Accion:      "HTTP_" + c.Request.Method,
+			Tabla:       recursoDeRuta(ruta),
+			RegistroID:  idDeRuta(c),
+			Detalles:    string(payload),
+			FechaAccion: time.Now(),
+			IPAddress:   c.ClientIP(),
+		}
+		if err := repositories.NewRegistroActividadRepository().Create(reg); err != nil {
+			log.Printf("[auditoria] %s: %v", reg.Accion, err)
+		}
+	}
+}
+
+func esMetodoMutante(m string) bool {
+	return m == http.MethodPost || m == http.MethodPut || m == http.MethodPatch || m == http.MethodDelete
+}
+
+// recursoDeRuta primer segmento tras /api (p. ej. /api/fichas/:id/instructores -> fichas).
+func recursoDeRuta(ruta string) string {
+	partes := strings.Split(strings.TrimPrefix(ruta, "/api/"), "/")
+	return partes[0]
+}
+
+// idDeRuta toma el primer parámetro numérico de la ruta (:id, :fichaId, ...).
+func idDeRuta(c *gin.Context) *uint {
+	for _, p := range c.Params {
+		if v, err := strconv.ParseUint(p.Value, 10, 64); err == nil && v > 0 {
+			id := uint(v)
+			return &id
+		}
+	}
+	return nil
+}
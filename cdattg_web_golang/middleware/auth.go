@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/casbin/casbin/v3"
 	"github.com/gin-gonic/gin"
@@ -25,6 +26,7 @@ const (
 	msgErrorVerificandoPermiso    = "Error verificando permiso"
 	msgErrorVerificandoRol        = "Error verificando rol"
 	msgRolInsuficiente            = "No tiene rol suficiente para acceder a esta sección"
+	msgSesionCerrada              = "Sesión cerrada o expirada"
 	roleSuperAdministrador        = "SUPER ADMINISTRADOR"
 	roleAdministrador             = "ADMINISTRADOR"
 	roleCoordinador               = "COORDINADOR"
@@ -156,6 +158,40 @@ func instructorTieneFichaAsignada(c *gin.Context, fichaID uint) bool {
 	return errIF == nil
 }
 
+// CodigoDebeCambiarPassword código de la respuesta 403 mientras la cuenta tenga pendiente el cambio de contraseña.
+const CodigoDebeCambiarPassword = "PASSWORD_CHANGE_REQUIRED"
+
+const msgDebeCambiarPassword = "Debe cambiar su contraseña antes de continuar"
+
+// rutasConPasswordPorCambiar lo único permitido a una cuenta con contraseña por defecto o restablecida.
+var rutasConPasswordPorCambiar = map[string]bool{
+	"/api/auth/me":              true,
+	"/api/auth/change-password": true,
+	"/api/auth/logout":          true,
+	"/api/auth/logout-all":      true,
+}
+
+// AutenticarToken valida el access token, que su sesión siga abierta y que el usuario esté activo.
+// Si no es válido devuelve user nil y el mensaje para responder 401.
+func AutenticarToken(tokenString string) (*models.User, *utils.Claims, string) {
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil {
+		return nil, nil, "Token inválido o expirado"
+	}
+	if claims.SessionID == 0 {
+		return nil, nil, msgSesionCerrada
+	}
+	sesion, err := repositories.NewSesionRepository().FindByID(claims.SessionID)
+	if err != nil || sesion.UserID != claims.UserID || !sesion.Activa(time.Now()) {
+		return nil, nil, msgSesionCerrada
+	}
+	user, err := repositories.NewUserRepository().FindByID(claims.UserID)
+	if err != nil || !user.Status {
+		return nil, nil, "Usuario no encontrado o inactivo"
+	}
+	return user, claims, ""
+}
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -173,18 +209,9 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		claims, err := utils.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
-			c.Abort()
-			return
-		}
-
-		// Cargar usuario completo
-		userRepo := repositories.NewUserRepository()
-		user, err := userRepo.FindByID(claims.UserID)
-		if err != nil || !user.Status {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no encontrado o inactivo"})
+		user, claims, msg := AutenticarToken(tokenString)
+		if user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": msg})
 			c.Abort()
 			return
 		}
@@ -193,6 +220,13 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("user", user)
 		c.Set("userID", claims.UserID)
 		c.Set("email", claims.Email)
+		c.Set("sessionID", claims.SessionID)
+
+		if user.DebeCambiarPassword && !rutasConPasswordPorCambiar[c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{"error": msgDebeCambiarPassword, "code": CodigoDebeCambiarPassword})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
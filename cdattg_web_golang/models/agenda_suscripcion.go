@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AgendaSuscripcion enlace secreto (uno por usuario) para suscribirse a la agenda del instructor en formato iCalendar
+// desde Google Calendar, Outlook, etc. Solo se guarda el hash del token; regenerarlo invalida el enlace anterior.
+type AgendaSuscripcion struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"column:user_id;not null;uniqueIndex" json:"user_id"`
+	TokenHash   string     `gorm:"column:token_hash;size:64;not null;uniqueIndex" json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UltimoUsoAt *time.Time `gorm:"column:ultimo_uso_at" json:"ultimo_uso_at,omitempty"`
+}
+
+func (AgendaSuscripcion) TableName() string { return "agenda_suscripciones" }
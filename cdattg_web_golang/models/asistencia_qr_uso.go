@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AsistenciaQRUso código QR rotativo de una sesión ya usado por un aprendiz (autorregistro). La clave única impide
+// reutilizar el mismo código (sesión + ventana) para más de un registro; las filas se borran al finalizar la sesión.
+type AsistenciaQRUso struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	AsistenciaID uint      `gorm:"column:asistencia_id;not null;uniqueIndex:uq_asistencia_qr_uso" json:"asistencia_id"`
+	AprendizID   uint      `gorm:"column:aprendiz_id;not null;uniqueIndex:uq_asistencia_qr_uso" json:"aprendiz_id"`
+	Ventana      int64     `gorm:"column:ventana;not null;uniqueIndex:uq_asistencia_qr_uso" json:"ventana"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (AsistenciaQRUso) TableName() string { return "asistencia_qr_usos" }
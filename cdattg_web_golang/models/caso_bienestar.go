@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+// Estados de un caso de bienestar.
+const (
+	CasoBienestarEstadoAbierto       = "abierto"
+	CasoBienestarEstadoEnSeguimiento = "en_seguimiento"
+	CasoBienestarEstadoCerrado       = "cerrado"
+)
+
+// Origen de un caso de bienestar.
+const (
+	CasoBienestarOrigenCalculo = "calculo"
+	CasoBienestarOrigenManual  = "manual"
+)
+
+// CasoBienestar seguimiento que bienestar abre a un aprendiz (desde el cálculo de inasistencias o manualmente).
+// Conserva la foto de las inasistencias al abrirlo, así el historial no depende de la ventana de análisis vigente.
+type CasoBienestar struct {
+	BaseModel
+	AprendizID uint `gorm:"column:aprendiz_id;not null;index" json:"aprendiz_id"`
+	// PersonaID permite reunir el historial del aprendiz aunque cambie de ficha.
+	PersonaID                 uint       `gorm:"column:persona_id;not null;index" json:"persona_id"`
+	FichaID                   uint       `gorm:"column:ficha_id;not null;index" json:"ficha_id"`
+	Origen                    string     `gorm:"column:origen;size:20;not null" json:"origen"`
+	Estado                    string     `gorm:"column:estado;size:20;not null;default:abierto;index" json:"estado"`
+	Motivo                    string     `gorm:"column:motivo;size:1000;not null" json:"motivo"`
+	ResponsableUserID         *uint      `gorm:"column:responsable_user_id;index" json:"responsable_user_id,omitempty"`
+	PeriodoInicio             *time.Time `gorm:"column:periodo_inicio;type:date" json:"periodo_inicio,omitempty"`
+	PeriodoFin                *time.Time `gorm:"column:periodo_fin;type:date" json:"periodo_fin,omitempty"`
+	Inasistencias             int        `gorm:"column:inasistencias;not null;default:0" json:"inasistencias"`
+	InasistenciasJustificadas int        `gorm:"column:inasistencias_justificadas;not null;default:0" json:"inasistencias_justificadas"`
+	TotalSesiones             int        `gorm:"column:total_sesiones;not null;default:0" json:"total_sesiones"`
+	CreadoPorUserID           uint       `gorm:"column:creado_por_user_id;not null" json:"creado_por_user_id"`
+	CerradoPorUserID          *uint      `gorm:"column:cerrado_por_user_id" json:"cerrado_por_user_id,omitempty"`
+	CerradoAt                 *time.Time `gorm:"column:cerrado_at" json:"cerrado_at,omitempty"`
+	ObservacionCierre         string     `gorm:"column:observacion_cierre;size:1000" json:"observacion_cierre,omitempty"`
+
+	// Relaciones
+	Aprendiz       *Aprendiz                   `gorm:"foreignKey:AprendizID" json:"aprendiz,omitempty"`
+	Ficha          *FichaCaracterizacion       `gorm:"foreignKey:FichaID" json:"ficha,omitempty"`
+	Responsable    *User                       `gorm:"foreignKey:ResponsableUserID" json:"responsable,omitempty"`
+	Intervenciones []CasoBienestarIntervencion `gorm:"foreignKey:CasoID" json:"intervenciones,omitempty"`
+	Adjuntos       []CasoBienestarAdjunto      `gorm:"foreignKey:CasoID" json:"adjuntos,omitempty"`
+	Recordatorios  []CasoBienestarRecordatorio `gorm:"foreignKey:CasoID" json:"recordatorios,omitempty"`
+}
+
+func (CasoBienestar) TableName() string {
+	return "casos_bienestar"
+}
+
+// CasoBienestarIntervencion nota fechada de una gestión sobre el caso (llamada, reunión, acuerdo...).
+type CasoBienestarIntervencion struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CasoID      uint      `gorm:"column:caso_id;not null;index" json:"caso_id"`
+	Fecha       time.Time `gorm:"column:fecha;type:date;not null" json:"fecha"`
+	Tipo        string    `gorm:"column:tipo;size:30;not null" json:"tipo"`
+	Descripcion string    `gorm:"column:descripcion;size:2000;not null" json:"descripcion"`
+	Acuerdos    string    `gorm:"column:acuerdos;size:1000" json:"acuerdos,omitempty"`
+	AutorUserID uint      `gorm:"column:autor_user_id;not null" json:"autor_user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Autor *User `gorm:"foreignKey:AutorUserID" json:"autor,omitempty"`
+}
+
+func (CasoBienestarIntervencion) TableName() string {
+	return "caso_bienestar_intervenciones"
+}
+
+// CasoBienestarAdjunto archivo de soporte del caso (acta, remisión, compromiso firmado...).
+type CasoBienestarAdjunto struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	CasoID          uint      `gorm:"column:caso_id;not null;index" json:"caso_id"`
+	ArchivoNombre   string    `gorm:"column:archivo_nombre;size:255;not null" json:"archivo_nombre"`
+	ArchivoRuta     string    `gorm:"column:archivo_ruta;size:500;not null" json:"-"`
+	ArchivoTipo     string    `gorm:"column:archivo_tipo;size:100" json:"archivo_tipo"`
+	ArchivoTamano   int64     `gorm:"column:archivo_tamano" json:"archivo_tamano"`
+	SubidoPorUserID uint      `gorm:"column:subido_por_user_id;not null" json:"subido_por_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (CasoBienestarAdjunto) TableName() string {
+	return "caso_bienestar_adjuntos"
+}
+
+// CasoBienestarRecordatorio próxima gestión pendiente; al vencer se avisa por correo al responsable.
+type CasoBienestarRecordatorio struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	CasoID            uint       `gorm:"column:caso_id;not null;index" json:"caso_id"`
+	FechaRecordatorio time.Time  `gorm:"column:fecha_recordatorio;not null;index" json:"fecha_recordatorio"`
+	Nota              string     `gorm:"column:nota;size:500;not null" json:"nota"`
+	CreadoPorUserID   uint       `gorm:"column:creado_por_user_id;not null" json:"creado_por_user_id"`
+	CompletadoAt      *time.Time `gorm:"column:completado_at" json:"completado_at,omitempty"`
+	NotificadoAt      *time.Time `gorm:"column:notificado_at" json:"notificado_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	Caso *CasoBienestar `gorm:"foreignKey:CasoID" json:"caso,omitempty"`
+}
+
+func (CasoBienestarRecordatorio) TableName() string {
+	return "caso_bienestar_recordatorios"
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Estados de un correo de la cola de salida.
+const (
+	CorreoSalienteEstadoPendiente = "pendiente"
+	CorreoSalienteEstadoEnviado   = "enviado"
+	// CorreoSalienteEstadoFallido agotó los reintentos; solo vuelve a la cola si un administrador lo reintenta.
+	CorreoSalienteEstadoFallido = "fallido"
+)
+
+// CorreoSaliente correo en la cola de salida. Se guarda antes de enviarse y la tarea de envío lo reintenta
+// con espera creciente hasta enviarlo o agotar los intentos.
+type CorreoSaliente struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	Destinatarios string `gorm:"column:destinatarios;size:1000;not null" json:"destinatarios"` // separados por coma
+	Asunto        string `gorm:"column:asunto;size:255;not null" json:"asunto"`
+	// Plantilla que generó el correo (recuperar-password, reporte-sesion...), para filtrar en la vista de administración.
+	Plantilla   string `gorm:"column:plantilla;size:50;not null;index" json:"plantilla"`
+	CuerpoHTML  string `gorm:"column:cuerpo_html;type:text" json:"-"`
+	CuerpoTexto string `gorm:"column:cuerpo_texto;type:text;not null" json:"-"`
+	// Sensible: el cuerpo lleva un enlace de un solo uso. No se muestra a administradores y se borra al terminar.
+	Sensible         bool       `gorm:"column:sensible;not null;default:false" json:"sensible"`
+	Estado           string     `gorm:"column:estado;size:20;not null;default:pendiente;index" json:"estado"`
+	Intentos         int        `gorm:"column:intentos;not null;default:0" json:"intentos"`
+	ProximoIntentoAt *time.Time `gorm:"column:proximo_intento_at;index" json:"proximo_intento_at,omitempty"`
+	UltimoError      string     `gorm:"column:ultimo_error;size:1000" json:"ultimo_error,omitempty"`
+	EnviadoAt        *time.Time `gorm:"column:enviado_at" json:"enviado_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	Adjuntos []CorreoSalienteAdjunto `gorm:"foreignKey:CorreoID" json:"adjuntos,omitempty"`
+}
+
+func (CorreoSaliente) TableName() string {
+	return "correos_salientes"
+}
+
+// CorreoSalienteAdjunto archivo adjunto guardado con el correo (la réplica que lo envía puede no tener el archivo en disco).
+type CorreoSalienteAdjunto struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	CorreoID    uint   `gorm:"column:correo_id;not null;index" json:"correo_id"`
+	Nombre      string `gorm:"column:nombre;size:255;not null" json:"nombre"`
+	ContentType string `gorm:"column:content_type;size:100;not null" json:"content_type"`
+	Tamano      int64  `gorm:"column:tamano;not null" json:"tamano"`
+	Contenido   []byte `gorm:"column:contenido;type:bytea;not null" json:"-"`
+}
+
+func (CorreoSalienteAdjunto) TableName() string {
+	return "correo_saliente_adjuntos"
+}
@@ -19,11 +19,31 @@ const (
 	PlanchaEstadoRetirada   = "retirada"
 )
 
-// EleccionProceso ciclo electoral anual por regional.
+// Métodos para resolver un empate entre planchas.
+const (
+	EleccionDesempateSorteo  = "sorteo"
+	EleccionDesempateComite  = "comite"
+	EleccionDesempateAcuerdo = "acuerdo"
+)
+
+// Ámbitos de un proceso electoral: quiénes votan y a quién representa el elegido.
+const (
+	EleccionAmbitoRegional = "regional"
+	EleccionAmbitoSede     = "sede"
+	EleccionAmbitoFicha    = "ficha"
+)
+
+// EleccionProceso ciclo electoral anual de un ámbito: la regional, una sede o una ficha de la regional. Los
+// ciclos de sede y ficha indican SedeID o FichaID; RegionalID siempre es la regional a la que pertenecen.
 type EleccionProceso struct {
 	UserAuditModel
-	RegionalID             uint       `gorm:"column:regional_id;not null;uniqueIndex:uk_eleccion_regional_anio" json:"regional_id"`
-	Anio                   int        `gorm:"column:anio;not null;uniqueIndex:uk_eleccion_regional_anio" json:"anio"`
+	RegionalID             uint       `gorm:"column:regional_id;not null;index" json:"regional_id"`
+	Anio                   int        `gorm:"column:anio;not null" json:"anio"`
+	Ambito                 string     `gorm:"column:ambito;size:16;not null;default:regional" json:"ambito"`
+	SedeID                 *uint      `gorm:"column:sede_id" json:"sede_id,omitempty"`
+	FichaID                *uint      `gorm:"column:ficha_id" json:"ficha_id,omitempty"`
+	CargoTitular           string     `gorm:"column:cargo_titular;size:80;not null;default:Representante" json:"cargo_titular"`
+	CargoSuplente          string     `gorm:"column:cargo_suplente;size:80;not null;default:Suplente" json:"cargo_suplente"`
 	NombreCiclo            string     `gorm:"column:nombre_ciclo;size:120;not null" json:"nombre_ciclo"`
 	Estado                 string     `gorm:"column:estado;size:32;not null;default:borrador" json:"estado"`
 	FechaInscripcionInicio *time.Time `gorm:"column:fecha_inscripcion_inicio" json:"fecha_inscripcion_inicio,omitempty"`
@@ -31,8 +51,12 @@ type EleccionProceso struct {
 	FechaVotacionInicio    *time.Time `gorm:"column:fecha_votacion_inicio" json:"fecha_votacion_inicio,omitempty"`
 	FechaVotacionFin       *time.Time `gorm:"column:fecha_votacion_fin" json:"fecha_votacion_fin,omitempty"`
 	MinDiasMatricula       *int       `gorm:"column:min_dias_matricula" json:"min_dias_matricula,omitempty"`
+	UltimoRecordatorioAt   *time.Time `gorm:"column:ultimo_recordatorio_at" json:"ultimo_recordatorio_at,omitempty"`
+	AutoTransiciones       bool       `gorm:"column:auto_transiciones;not null;default:false" json:"auto_transiciones"` // el planificador avanza las fases en las fechas configuradas
 
-	Regional *Regional `gorm:"foreignKey:RegionalID" json:"regional,omitempty"`
+	Regional *Regional             `gorm:"foreignKey:RegionalID" json:"regional,omitempty"`
+	Sede     *Sede                 `gorm:"foreignKey:SedeID" json:"sede,omitempty"`
+	Ficha    *FichaCaracterizacion `gorm:"foreignKey:FichaID" json:"ficha,omitempty"`
 }
 
 func (EleccionProceso) TableName() string { return "eleccion_procesos" }
@@ -56,31 +80,46 @@ type EleccionPlancha struct {
 
 func (EleccionPlancha) TableName() string { return "eleccion_planchas" }
 
-// EleccionVoto registro transparente (quién votó y por qué plancha).
-type EleccionVoto struct {
-	BaseModel
-	ProcesoID         uint `gorm:"column:proceso_id;not null;uniqueIndex:idx_eleccion_voto_proceso_user" json:"proceso_id"`
-	PlanchaID         uint `gorm:"column:plancha_id;not null" json:"plancha_id"`
-	VotanteUserID     uint `gorm:"column:votante_user_id;not null;uniqueIndex:idx_eleccion_voto_proceso_user" json:"votante_user_id"`
-	VotanteAprendizID uint `gorm:"column:votante_aprendiz_id;not null" json:"votante_aprendiz_id"`
+// EleccionParticipacion constancia de que un aprendiz votó en el proceso. No guarda la plancha elegida:
+// el voto en sí vive en EleccionPapeleta, sin ningún vínculo con el votante.
+type EleccionParticipacion struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ProcesoID         uint      `gorm:"column:proceso_id;not null;uniqueIndex:idx_eleccion_participacion_proceso_user" json:"proceso_id"`
+	VotanteUserID     uint      `gorm:"column:votante_user_id;not null;uniqueIndex:idx_eleccion_participacion_proceso_user" json:"votante_user_id"`
+	VotanteAprendizID uint      `gorm:"column:votante_aprendiz_id;not null" json:"votante_aprendiz_id"`
+	VotadoAt          time.Time `gorm:"column:votado_at;not null" json:"votado_at"`
 
 	Proceso         *EleccionProceso `gorm:"foreignKey:ProcesoID" json:"proceso,omitempty"`
-	Plancha         *EleccionPlancha `gorm:"foreignKey:PlanchaID" json:"plancha,omitempty"`
 	VotanteAprendiz *Aprendiz        `gorm:"foreignKey:VotanteAprendizID" json:"votante_aprendiz,omitempty"`
 }
 
-func (EleccionVoto) TableName() string { return "eleccion_votos" }
+func (EleccionParticipacion) TableName() string { return "eleccion_participaciones" }
+
+// EleccionPapeleta voto anónimo. La llave es el hash del código de recibo entregado al votante;
+// no tiene ID secuencial ni marcas de tiempo para que no pueda correlacionarse con la participación.
+type EleccionPapeleta struct {
+	ReciboHash string `gorm:"column:recibo_hash;primaryKey;size:64" json:"recibo_hash"`
+	ProcesoID  uint   `gorm:"column:proceso_id;not null;index" json:"proceso_id"`
+	PlanchaID  uint   `gorm:"column:plancha_id;not null;index" json:"plancha_id"`
+
+	Plancha *EleccionPlancha `gorm:"foreignKey:PlanchaID" json:"plancha,omitempty"`
+}
+
+func (EleccionPapeleta) TableName() string { return "eleccion_papeletas" }
 
 // EleccionResultado conteo y ganador del proceso.
 type EleccionResultado struct {
 	BaseModel
-	ProcesoID         uint    `gorm:"column:proceso_id;not null;uniqueIndex" json:"proceso_id"`
-	PlanchaGanadoraID *uint   `gorm:"column:plancha_ganadora_id" json:"plancha_ganadora_id,omitempty"`
-	VotosTotales      int     `gorm:"column:votos_totales;not null;default:0" json:"votos_totales"`
-	DetalleJSON       string  `gorm:"column:detalle_json;type:text" json:"detalle_json"`
-	Empate            bool    `gorm:"column:empate;not null;default:false" json:"empate"`
-	NotaDesempate     *string `gorm:"column:nota_desempate;type:text" json:"nota_desempate,omitempty"`
-	UserRegistroID    *uint   `gorm:"column:user_registro_id" json:"user_registro_id,omitempty"`
+	ProcesoID         uint       `gorm:"column:proceso_id;not null;uniqueIndex" json:"proceso_id"`
+	PlanchaGanadoraID *uint      `gorm:"column:plancha_ganadora_id" json:"plancha_ganadora_id,omitempty"`
+	VotosTotales      int        `gorm:"column:votos_totales;not null;default:0" json:"votos_totales"`
+	DetalleJSON       string     `gorm:"column:detalle_json;type:text" json:"detalle_json"`
+	Empate            bool       `gorm:"column:empate;not null;default:false" json:"empate"`
+	NotaDesempate     *string    `gorm:"column:nota_desempate;type:text" json:"nota_desempate,omitempty"`
+	DesempateMetodo   *string    `gorm:"column:desempate_metodo;size:32" json:"desempate_metodo,omitempty"`
+	DesempateAt       *time.Time `gorm:"column:desempate_at" json:"desempate_at,omitempty"`
+	CerradaAt         *time.Time `gorm:"column:cerrada_at" json:"cerrada_at,omitempty"` // cierre del escrutinio; nil mientras el empate siga pendiente
+	UserRegistroID    *uint      `gorm:"column:user_registro_id" json:"user_registro_id,omitempty"`
 
 	Proceso         *EleccionProceso `gorm:"foreignKey:ProcesoID" json:"proceso,omitempty"`
 	PlanchaGanadora *EleccionPlancha `gorm:"foreignKey:PlanchaGanadoraID" json:"plancha_ganadora,omitempty"`
@@ -88,10 +127,49 @@ type EleccionResultado struct {
 
 func (EleccionResultado) TableName() string { return "eleccion_resultados" }
 
-// RepresentanteAprendiz vigencia histórica y actual por regional.
+// EleccionActa acta oficial de escrutinio de un proceso cerrado. Contenido es el JSON del escrutinio con el que
+// se imprime el PDF y Hash su SHA-256, que va en cada página y se verifica en el endpoint público.
+type EleccionActa struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProcesoID    uint      `gorm:"column:proceso_id;not null;uniqueIndex" json:"proceso_id"`
+	Hash         string    `gorm:"column:hash;size:64;not null;uniqueIndex" json:"hash"`
+	Contenido    string    `gorm:"column:contenido;type:text;not null" json:"-"`
+	GeneradaAt   time.Time `gorm:"column:generada_at;not null" json:"generada_at"`
+	UserGeneroID *uint     `gorm:"column:user_genero_id" json:"user_genero_id,omitempty"`
+
+	Proceso *EleccionProceso `gorm:"foreignKey:ProcesoID" json:"proceso,omitempty"`
+}
+
+func (EleccionActa) TableName() string { return "eleccion_actas" }
+
+// Origen de un cambio de fase del proceso.
+const (
+	EleccionEventoOrigenManual     = "manual"
+	EleccionEventoOrigenAutomatico = "automatico"
+)
+
+// EleccionProcesoEvento bitácora de cambios de fase de un proceso, hechos por un usuario o por el planificador
+// (UserID nil).
+type EleccionProcesoEvento struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ProcesoID      uint      `gorm:"column:proceso_id;not null;index" json:"proceso_id"`
+	EstadoAnterior string    `gorm:"column:estado_anterior;size:32;not null" json:"estado_anterior"`
+	EstadoNuevo    string    `gorm:"column:estado_nuevo;size:32;not null" json:"estado_nuevo"`
+	Origen         string    `gorm:"column:origen;size:16;not null" json:"origen"`
+	UserID         *uint     `gorm:"column:user_id" json:"user_id,omitempty"`
+	Detalle        string    `gorm:"column:detalle;size:500" json:"detalle,omitempty"`
+	CreatedAt      time.Time `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+func (EleccionProcesoEvento) TableName() string { return "eleccion_proceso_eventos" }
+
+// RepresentanteAprendiz vigencia histórica y actual por ámbito (regional, sede o ficha).
 type RepresentanteAprendiz struct {
 	BaseModel
 	RegionalID           uint       `gorm:"column:regional_id;not null;index" json:"regional_id"`
+	Ambito               string     `gorm:"column:ambito;size:16;not null;default:regional" json:"ambito"`
+	SedeID               *uint      `gorm:"column:sede_id" json:"sede_id,omitempty"`
+	FichaID              *uint      `gorm:"column:ficha_id;index" json:"ficha_id,omitempty"`
 	ProcesoID            uint       `gorm:"column:proceso_id;not null" json:"proceso_id"`
 	TitularAprendizID    uint       `gorm:"column:titular_aprendiz_id;not null" json:"titular_aprendiz_id"`
 	SuplenteAprendizID   uint       `gorm:"column:suplente_aprendiz_id;not null" json:"suplente_aprendiz_id"`
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Estados de una excusa de inasistencia.
+const (
+	ExcusaEstadoPendiente = "pendiente"
+	ExcusaEstadoAprobada  = "aprobada"
+	ExcusaEstadoRechazada = "rechazada"
+)
+
+// ExcusaInasistencia justificación que presenta un aprendiz (con soporte adjunto) para una o varias sesiones
+// de asistencia. Solo las aprobadas cuentan como inasistencia justificada.
+type ExcusaInasistencia struct {
+	BaseModel
+	AprendizID          uint       `gorm:"column:aprendiz_id;not null;index" json:"aprendiz_id"`
+	FichaID             uint       `gorm:"column:ficha_id;not null;index" json:"ficha_id"`
+	Motivo              string     `gorm:"column:motivo;size:500;not null" json:"motivo"`
+	Estado              string     `gorm:"column:estado;size:20;not null;default:pendiente;index" json:"estado"`
+	ArchivoNombre       string     `gorm:"column:archivo_nombre;size:255;not null" json:"archivo_nombre"`
+	ArchivoRuta         string     `gorm:"column:archivo_ruta;size:500;not null" json:"-"`
+	ArchivoTipo         string     `gorm:"column:archivo_tipo;size:100" json:"archivo_tipo"`
+	ArchivoTamano       int64      `gorm:"column:archivo_tamano" json:"archivo_tamano"`
+	RevisadoPorUserID   *uint      `gorm:"column:revisado_por_user_id" json:"revisado_por_user_id,omitempty"`
+	RevisadoAt          *time.Time `gorm:"column:revisado_at" json:"revisado_at,omitempty"`
+	ObservacionRevision string     `gorm:"column:observacion_revision;size:500" json:"observacion_revision,omitempty"`
+
+	// Relaciones
+	Aprendiz *Aprendiz                  `gorm:"foreignKey:AprendizID" json:"aprendiz,omitempty"`
+	Ficha    *FichaCaracterizacion      `gorm:"foreignKey:FichaID" json:"ficha,omitempty"`
+	Sesiones []ExcusaInasistenciaSesion `gorm:"foreignKey:ExcusaID" json:"sesiones,omitempty"`
+}
+
+func (ExcusaInasistencia) TableName() string {
+	return "excusas_inasistencia"
+}
+
+// ExcusaInasistenciaSesion sesión de asistencia cubierta por una excusa.
+type ExcusaInasistenciaSesion struct {
+	ID           uint `gorm:"primaryKey" json:"id"`
+	ExcusaID     uint `gorm:"column:excusa_id;not null;uniqueIndex:uk_excusa_sesion" json:"excusa_id"`
+	AsistenciaID uint `gorm:"column:asistencia_id;not null;uniqueIndex:uk_excusa_sesion;index" json:"asistencia_id"`
+
+	Asistencia *Asistencia `gorm:"foreignKey:AsistenciaID" json:"asistencia,omitempty"`
+}
+
+func (ExcusaInasistenciaSesion) TableName() string {
+	return "excusa_inasistencia_sesiones"
+}
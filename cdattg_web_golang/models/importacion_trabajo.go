@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Tipos de importación de Excel que se procesan como trabajo en segundo plano.
+const (
+	ImportacionTipoPersonas     = "personas"
+	ImportacionTipoInstructores = "instructores"
+	ImportacionTipoFicha        = "ficha"
+	ImportacionTipoProgramas    = "programas"
+)
+
+// Estados de un trabajo de importación.
+const (
+	ImportacionEstadoPendiente  = "pendiente"
+	ImportacionEstadoProcesando = "procesando"
+	ImportacionEstadoCompletado = "completado"
+	// ImportacionEstadoFallido se detuvo por un error; el usuario puede reanudarlo desde FilaActual.
+	ImportacionEstadoFallido = "fallido"
+)
+
+// ImportacionTrabajo importación de Excel encolada. Las filas se procesan en segundo plano y el avance se guarda
+// cada pocas filas: si el proceso cae o el trabajo falla, se retoma desde FilaActual.
+type ImportacionTrabajo struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Tipo     string `gorm:"column:tipo;size:20;not null;index" json:"tipo"`
+	Estado   string `gorm:"column:estado;size:20;not null;default:pendiente;index" json:"estado"`
+	UserID   uint   `gorm:"column:user_id;not null;index" json:"user_id"`
+	Filename string `gorm:"column:filename;size:255;not null" json:"filename"`
+	// RegionalID regional asignada a los instructores nuevos (solo tipo instructores).
+	RegionalID *uint `gorm:"column:regional_id" json:"regional_id,omitempty"`
+	// TotalFilas unidades a procesar: filas de datos o, en programas, códigos distintos.
+	TotalFilas int `gorm:"column:total_filas;not null;default:0" json:"total_filas"`
+	// FilaActual siguiente unidad a procesar (base 0); todo lo anterior ya quedó guardado.
+	FilaActual   int `gorm:"column:fila_actual;not null;default:0" json:"fila_actual"`
+	Procesados   int `gorm:"column:procesados;not null;default:0" json:"procesados"`
+	Duplicados   int `gorm:"column:duplicados;not null;default:0" json:"duplicados"`
+	Errores      int `gorm:"column:errores;not null;default:0" json:"errores"`
+	Actualizados int `gorm:"column:actualizados;not null;default:0" json:"actualizados"`
+	Creados      int `gorm:"column:creados;not null;default:0" json:"creados"`
+	RedesCreadas int `gorm:"column:redes_creadas;not null;default:0" json:"redes_creadas"`
+	// FichaID ficha importada (tipo ficha); FichaCreada si la creó la importación.
+	FichaID     *uint `gorm:"column:ficha_id" json:"ficha_id,omitempty"`
+	FichaCreada bool  `gorm:"column:ficha_creada;not null;default:false" json:"ficha_creada"`
+	// Incidencias filas registradas para el Excel de incidencias.
+	Incidencias int `gorm:"column:incidencias;not null;default:0" json:"incidencias"`
+	// Intentos veces que una réplica tomó el trabajo; identifica la reserva vigente al guardar avance.
+	Intentos    int    `gorm:"column:intentos;not null;default:0" json:"intentos"`
+	UltimoError string `gorm:"column:ultimo_error;size:1000" json:"ultimo_error,omitempty"`
+	// ReclamadoHasta reserva de la réplica que lo procesa; vencida, otra réplica lo retoma.
+	ReclamadoHasta *time.Time `gorm:"column:reclamado_hasta;index" json:"-"`
+	IniciadoAt     *time.Time `gorm:"column:iniciado_at" json:"iniciado_at,omitempty"`
+	FinalizadoAt   *time.Time `gorm:"column:finalizado_at" json:"finalizado_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Archivo *ImportacionTrabajoArchivo `gorm:"foreignKey:TrabajoID" json:"-"`
+}
+
+func (ImportacionTrabajo) TableName() string {
+	return "importacion_trabajos"
+}
+
+// ImportacionTrabajoArchivo Excel subido, guardado con el trabajo para que cualquier réplica pueda procesarlo.
+type ImportacionTrabajoArchivo struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	TrabajoID   uint   `gorm:"column:trabajo_id;not null;uniqueIndex" json:"trabajo_id"`
+	ContentType string `gorm:"column:content_type;size:100;not null" json:"content_type"`
+	Tamano      int64  `gorm:"column:tamano;not null" json:"tamano"`
+	Contenido   []byte `gorm:"column:contenido;type:bytea;not null" json:"-"`
+}
+
+func (ImportacionTrabajoArchivo) TableName() string {
+	return "importacion_trabajo_archivos"
+}
+
+// ImportacionIncidencia fila con error, duplicada o importada con ajustes; alimenta el Excel de incidencias.
+type ImportacionIncidencia struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	TrabajoID uint   `gorm:"column:trabajo_id;not null;index" json:"trabajo_id"`
+	Fila      int    `gorm:"column:fila;not null" json:"fila"` // fila del Excel (base 1) o posición del código
+	Tipo      string `gorm:"column:tipo;size:40;not null" json:"tipo"`
+	Documento string `gorm:"column:documento;size:50" json:"documento,omitempty"`
+	Nombre    string `gorm:"column:nombre;size:255" json:"nombre,omitempty"`
+	Correo    string `gorm:"column:correo;size:255" json:"correo,omitempty"`
+	Celular   string `gorm:"column:celular;size:50" json:"celular,omitempty"`
+	// FichaOrigen ficha en la que ya estaba inscrito el aprendiz (importación de ficha).
+	FichaOrigen string `gorm:"column:ficha_origen;size:50" json:"ficha_origen,omitempty"`
+	Detalle     string `gorm:"column:detalle;size:1000" json:"detalle,omitempty"`
+}
+
+func (ImportacionIncidencia) TableName() string {
+	return "importacion_incidencias"
+}
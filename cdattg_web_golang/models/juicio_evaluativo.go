@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Valores de juicio evaluativo de un RAP.
+const (
+	JuicioAprobado   = "APROBADO"
+	JuicioNoAprobado = "NO APROBADO"
+	JuicioPendiente  = "PENDIENTE"
+)
+
+// JuicioEvaluativo juicio de un aprendiz sobre un resultado de aprendizaje (RAP) del programa de su ficha.
+// Un registro por aprendiz y RAP; al volver a evaluar se actualiza.
+type JuicioEvaluativo struct {
+	UserAuditModel
+	FichaID       uint      `gorm:"column:ficha_id;not null;index" json:"ficha_id"`
+	AprendizID    uint      `gorm:"column:aprendiz_id;not null;uniqueIndex:uk_juicio_aprendiz_rap" json:"aprendiz_id"`
+	RapID         uint      `gorm:"column:rap_id;not null;uniqueIndex:uk_juicio_aprendiz_rap" json:"rap_id"`
+	CompetenciaID uint      `gorm:"column:competencia_id;not null" json:"competencia_id"`
+	Juicio        string    `gorm:"column:juicio;size:20;not null" json:"juicio"`
+	Observaciones string    `gorm:"type:text" json:"observaciones"`
+	InstructorID  *uint     `gorm:"column:instructor_id" json:"instructor_id,omitempty"`
+	FechaJuicio   time.Time `gorm:"column:fecha_juicio;not null" json:"fecha_juicio"`
+
+	// Relaciones
+	Aprendiz    *Aprendiz              `gorm:"foreignKey:AprendizID" json:"aprendiz,omitempty"`
+	Rap         *ResultadosAprendizaje `gorm:"foreignKey:RapID" json:"rap,omitempty"`
+	Competencia *Competencia           `gorm:"foreignKey:CompetenciaID" json:"competencia,omitempty"`
+	Instructor  *Instructor            `gorm:"foreignKey:InstructorID" json:"instructor,omitempty"`
+}
+
+func (JuicioEvaluativo) TableName() string {
+	return "juicios_evaluativos"
+}
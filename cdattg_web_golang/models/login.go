@@ -2,14 +2,24 @@ package models
 
 import "time"
 
-// Login representa un registro de login
+// Motivos de un intento de login fallido
+const (
+	LoginMotivoUsuarioNoEncontrado = "usuario_no_encontrado"
+	LoginMotivoPasswordIncorrecta  = "password_incorrecta"
+	LoginMotivoBloqueado           = "bloqueado"
+)
+
+// Login representa un intento de inicio de sesión (exitoso o fallido)
 type Login struct {
 	BaseModel
-	UserID    uint      `gorm:"column:user_id;not null" json:"user_id"`
-	IPAddress string    `gorm:"size:45" json:"ip_address"`
-	UserAgent string    `gorm:"size:255" json:"user_agent"`
-	FechaLogin time.Time `gorm:"column:fecha_login;not null" json:"fecha_login"`
-	
+	UserID        *uint     `gorm:"column:user_id;index" json:"user_id"`
+	Identificador string    `gorm:"size:150" json:"identificador"`
+	Exitoso       bool      `gorm:"not null;default:false" json:"exitoso"`
+	Motivo        string    `gorm:"size:40" json:"motivo,omitempty"`
+	IPAddress     string    `gorm:"size:45;index" json:"ip_address"`
+	UserAgent     string    `gorm:"size:255" json:"user_agent"`
+	FechaLogin    time.Time `gorm:"column:fecha_login;not null;index" json:"fecha_login"`
+
 	// Relaciones
 	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LoginBloqueo contador de intentos fallidos de login por cuenta ("cuenta:<id>") o por IP ("ip:<dir>").
+// Al superar el umbral se fija BloqueadoHasta, cada vez más lejos mientras sigan los fallos.
+type LoginBloqueo struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Clave          string     `gorm:"size:80;not null;uniqueIndex" json:"clave"`
+	Fallos         int        `gorm:"not null;default:0" json:"fallos"`
+	UltimoFalloAt  time.Time  `gorm:"not null" json:"ultimo_fallo_at"`
+	BloqueadoHasta *time.Time `json:"bloqueado_hasta,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (LoginBloqueo) TableName() string {
+	return "login_bloqueos"
+}
+
+// Bloqueado indica si la clave sigue bloqueada en t.
+func (b *LoginBloqueo) Bloqueado(t time.Time) bool {
+	return b.BloqueadoHasta != nil && t.Before(*b.BloqueadoHasta)
+}
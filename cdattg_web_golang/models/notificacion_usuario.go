@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Tipos del centro de notificaciones. Cada tipo se puede desactivar en las preferencias del usuario.
+const (
+	// NotificacionTipoAsignacionFicha asignación o retiro de un instructor en una ficha.
+	NotificacionTipoAsignacionFicha = "asignacion_ficha"
+	// NotificacionTipoTrasladoDia traslado de un día de formación entre instructores de la ficha.
+	NotificacionTipoTrasladoDia = "traslado_dia"
+	// NotificacionTipoEleccionPlancha solicitud de confirmación como integrante de una plancha.
+	NotificacionTipoEleccionPlancha = "eleccion_plancha"
+	// NotificacionTipoEleccionVotacion recordatorio de votar mientras la votación está abierta.
+	NotificacionTipoEleccionVotacion = "eleccion_votacion"
+	// NotificacionTipoEleccionEmpate escrutinio empatado pendiente de desempate.
+	NotificacionTipoEleccionEmpate = "eleccion_empate"
+	// NotificacionTipoInasistencia inasistencia registrada al aprendiz al cerrar una sesión.
+	NotificacionTipoInasistencia = "inasistencia"
+	// NotificacionTipoImportacion importación de Excel terminada.
+	NotificacionTipoImportacion = "importacion"
+)
+
+// NotificacionUsuario aviso en la aplicación para un usuario. Distinta de inventario.Notificacion (tabla
+// notificaciones del módulo de inventario).
+type NotificacionUsuario struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"column:user_id;not null;index:idx_notificaciones_usuario_user_leida,priority:1" json:"user_id"`
+	Tipo      string     `gorm:"column:tipo;size:40;not null" json:"tipo"`
+	Titulo    string     `gorm:"column:titulo;size:255;not null" json:"titulo"`
+	Mensaje   string     `gorm:"column:mensaje;type:text;not null" json:"mensaje"`
+	Enlace    string     `gorm:"column:enlace;size:500" json:"enlace,omitempty"` // ruta del frontend
+	LeidaEn   *time.Time `gorm:"column:leida_en;index:idx_notificaciones_usuario_user_leida,priority:2" json:"leida_en,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at;index" json:"created_at"`
+}
+
+func (NotificacionUsuario) TableName() string {
+	return "notificaciones_usuario"
+}
+
+// NotificacionPreferencia tipo de notificación desactivado o reactivado por el usuario. Sin fila el tipo está activo.
+type NotificacionPreferencia struct {
+	UserID    uint      `gorm:"primaryKey;column:user_id" json:"user_id"`
+	Tipo      string    `gorm:"primaryKey;column:tipo;size:40" json:"tipo"`
+	Activo    bool      `gorm:"column:activo;not null;default:true" json:"activo"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (NotificacionPreferencia) TableName() string {
+	return "notificacion_preferencias"
+}
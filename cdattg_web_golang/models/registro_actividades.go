@@ -5,12 +5,12 @@ import "time"
 // RegistroActividades representa un registro de actividad del sistema
 type RegistroActividades struct {
 	BaseModel
-	UserID      uint      `gorm:"column:user_id" json:"user_id"`
-	Accion      string    `gorm:"size:100;not null" json:"accion"`
-	Tabla       string    `gorm:"size:100" json:"tabla"`
-	RegistroID  *uint     `gorm:"column:registro_id" json:"registro_id"`
+	UserID      uint      `gorm:"column:user_id;index" json:"user_id"`
+	Accion      string    `gorm:"size:100;not null;index" json:"accion"`
+	Tabla       string    `gorm:"size:100;index:idx_registro_actividades_tabla_registro" json:"tabla"`
+	RegistroID  *uint     `gorm:"column:registro_id;index:idx_registro_actividades_tabla_registro" json:"registro_id"`
 	Detalles    string    `gorm:"type:text" json:"detalles"`
-	FechaAccion time.Time `gorm:"column:fecha_accion;not null" json:"fecha_accion"`
+	FechaAccion time.Time `gorm:"column:fecha_accion;not null;index" json:"fecha_accion"`
 	IPAddress   string    `gorm:"size:45" json:"ip_address"`
 	
 	// Relaciones
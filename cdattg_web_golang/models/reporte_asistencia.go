@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Estados de generación de un reporte consolidado de asistencia
+const (
+	ReporteAsistenciaPendiente  = "PENDIENTE"
+	ReporteAsistenciaProcesando = "PROCESANDO"
+	ReporteAsistenciaCompletado = "COMPLETADO"
+	ReporteAsistenciaError      = "ERROR"
+)
+
+// ReporteAsistencia reporte consolidado de asistencia (aprendices x sesiones) de una ficha en un periodo, generado en
+// segundo plano cuando el rango es grande. El archivo se guarda en la base para que cualquier réplica lo entregue.
+type ReporteAsistencia struct {
+	BaseModel
+	FichaID       uint       `gorm:"column:ficha_id;not null;index" json:"ficha_id"`
+	FechaInicio   time.Time  `gorm:"column:fecha_inicio;type:date;not null" json:"fecha_inicio"`
+	FechaFin      time.Time  `gorm:"column:fecha_fin;type:date;not null" json:"fecha_fin"`
+	Formato       string     `gorm:"size:10;not null" json:"formato"` // pdf, xlsx
+	Estado        string     `gorm:"size:20;not null;default:PENDIENTE" json:"estado"`
+	NombreArchivo string     `gorm:"column:nombre_archivo;size:255" json:"nombre_archivo,omitempty"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	Sesiones      int        `gorm:"default:0" json:"sesiones"`
+	SolicitadoPor uint       `gorm:"column:solicitado_por;not null" json:"solicitado_por"`
+	FinalizadoAt  *time.Time `gorm:"column:finalizado_at" json:"finalizado_at,omitempty"`
+	// Intentos veces que una réplica tomó el reporte; identifica la reserva vigente al guardar el resultado.
+	Intentos int `gorm:"column:intentos;not null;default:0" json:"-"`
+	// ReclamadoHasta reserva de la réplica que lo genera; vencida, otra réplica lo retoma.
+	ReclamadoHasta *time.Time `gorm:"column:reclamado_hasta;index" json:"-"`
+}
+
+func (ReporteAsistencia) TableName() string { return "reportes_asistencia" }
+
+// ReporteAsistenciaArchivo archivo generado de un reporte completado.
+type ReporteAsistenciaArchivo struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ReporteID uint   `gorm:"column:reporte_id;not null;uniqueIndex" json:"reporte_id"`
+	Contenido []byte `gorm:"column:contenido;type:bytea;not null" json:"-"`
+}
+
+func (ReporteAsistenciaArchivo) TableName() string { return "reportes_asistencia_archivos" }
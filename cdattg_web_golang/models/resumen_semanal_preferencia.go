@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Tipos de resumen semanal enviado por correo.
+const (
+	ResumenSemanalCoordinador = "coordinador"
+	ResumenSemanalInstructor  = "instructor"
+)
+
+// ResumenSemanalPreferencia suscripción de un usuario a un tipo de resumen semanal. Sin fila el usuario recibe el
+// resumen que corresponde a su rol; con Activo=false lo canceló.
+type ResumenSemanalPreferencia struct {
+	UserID    uint      `gorm:"primaryKey;column:user_id" json:"user_id"`
+	Tipo      string    `gorm:"primaryKey;column:tipo;size:20" json:"tipo"`
+	Activo    bool      `gorm:"column:activo;not null;default:true" json:"activo"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ResumenSemanalPreferencia) TableName() string {
+	return "resumen_semanal_preferencias"
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Motivos de revocación de una sesión.
+const (
+	SesionMotivoLogout          = "logout"
+	SesionMotivoCerrarTodas     = "cerrar_todas"
+	SesionMotivoCerradaUsuario  = "cerrada_por_usuario"
+	SesionMotivoUsuarioInactivo = "usuario_inactivo"
+	SesionMotivoReusoRefresh    = "reuso_refresh_token"
+)
+
+// Sesion sesión de un usuario en un dispositivo. El refresh token rota en cada renovación y solo se guarda
+// su hash; el hash anterior se conserva para detectar la reutilización de un token ya rotado (robo).
+type Sesion struct {
+	ID                       uint       `gorm:"primaryKey" json:"id"`
+	UserID                   uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	RefreshTokenHash         string     `gorm:"column:refresh_token_hash;size:64;not null;uniqueIndex" json:"-"`
+	RefreshTokenAnteriorHash *string    `gorm:"column:refresh_token_anterior_hash;size:64;index" json:"-"`
+	UserAgent                string     `gorm:"column:user_agent;size:255" json:"user_agent"`
+	IP                       string     `gorm:"column:ip;size:64" json:"ip"`
+	CreatedAt                time.Time  `json:"created_at"`
+	UltimoUsoAt              time.Time  `gorm:"column:ultimo_uso_at;not null" json:"ultimo_uso_at"`
+	ExpiraAt                 time.Time  `gorm:"column:expira_at;not null;index" json:"expira_at"`
+	RevocadaAt               *time.Time `gorm:"column:revocada_at" json:"revocada_at,omitempty"`
+	MotivoRevocacion         *string    `gorm:"column:motivo_revocacion;size:50" json:"motivo_revocacion,omitempty"`
+}
+
+func (Sesion) TableName() string { return "sesiones" }
+
+// Activa indica si la sesión no ha sido revocada ni ha expirado en el instante t.
+func (s *Sesion) Activa(t time.Time) bool {
+	return s.RevocadaAt == nil && t.Before(s.ExpiraAt)
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Origen de una ejecución de tarea programada.
+const (
+	TareaOrigenProgramada = "programada"
+	TareaOrigenManual     = "manual"
+	TareaOrigenArranque   = "arranque"
+)
+
+// TareaProgramadaEjecucion historial de ejecuciones del planificador (duración, error, instancia que la corrió).
+type TareaProgramadaEjecucion struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Tarea        string     `gorm:"column:tarea;size:100;not null;index:idx_tarea_ejecucion_tarea_inicio,priority:1" json:"tarea"`
+	Origen       string     `gorm:"column:origen;size:20;not null" json:"origen"`
+	Instancia    string     `gorm:"column:instancia;size:255" json:"instancia"`
+	UserID       *uint      `gorm:"column:user_id" json:"user_id,omitempty"`
+	IniciadaAt   time.Time  `gorm:"column:iniciada_at;not null;index:idx_tarea_ejecucion_tarea_inicio,priority:2,sort:desc" json:"iniciada_at"`
+	FinalizadaAt *time.Time `gorm:"column:finalizada_at" json:"finalizada_at,omitempty"`
+	DuracionMs   int64      `gorm:"column:duracion_ms;not null;default:0" json:"duracion_ms"`
+	Exitosa      bool       `gorm:"column:exitosa;not null;default:false" json:"exitosa"`
+	Error        *string    `gorm:"column:error;type:text" json:"error,omitempty"`
+}
+
+// TableName especifica el nombre de la tabla
+func (TareaProgramadaEjecucion) TableName() string {
+	return "tareas_programadas_ejecuciones"
+}
@@ -6,6 +6,8 @@ type TipoObservacionAsistencia struct {
 	Codigo  string `gorm:"column:codigo;size:80;not null;uniqueIndex" json:"codigo"`
 	Nombre  string `gorm:"column:nombre;size:255;not null" json:"nombre"`
 	Activo  bool   `gorm:"column:activo;default:true" json:"activo"`
+	// PesoRiesgo aporte (0-5) de la observación al puntaje de riesgo de deserción. NULL: sin configurar (el seeder asigna el valor por defecto).
+	PesoRiesgo *int `gorm:"column:peso_riesgo" json:"peso_riesgo"`
 }
 
 // TableName especifica el nombre de la tabla
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Tipos de token de un solo uso enviados por correo.
+const (
+	TokenUsuarioRecuperacionPassword = "RECUPERACION_PASSWORD"
+	TokenUsuarioVerificacionEmail    = "VERIFICACION_EMAIL"
+)
+
+// TokenUsuario enlace de un solo uso con vencimiento (recuperar contraseña, verificar correo). Solo se guarda el hash;
+// emitir uno nuevo del mismo tipo invalida los pendientes del usuario.
+type TokenUsuario struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	Tipo      string     `gorm:"column:tipo;size:30;not null;index" json:"tipo"`
+	TokenHash string     `gorm:"column:token_hash;size:64;not null;uniqueIndex" json:"-"`
+	Email     string     `gorm:"column:email;size:255;not null" json:"email"` // destinatario al emitirlo
+	ExpiraAt  time.Time  `gorm:"column:expira_at;not null" json:"expira_at"`
+	UsadoAt   *time.Time `gorm:"column:usado_at" json:"usado_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (TokenUsuario) TableName() string { return "tokens_usuario" }
+
+// Vigente indica si el token no se ha usado ni ha vencido en el instante t.
+func (t *TokenUsuario) Vigente(at time.Time) bool {
+	return t.UsadoAt == nil && at.Before(t.ExpiraAt)
+}
@@ -12,6 +12,9 @@ type User struct {
 	Status          bool      `gorm:"default:true" json:"status"`
 	PersonaID       *uint     `gorm:"column:persona_id" json:"persona_id"`
 	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at" json:"email_verified_at"`
+	// DebeCambiarPassword cuenta con contraseña por defecto o restablecida por un administrador: hasta cambiarla
+	// solo puede consultar su perfil, cambiar la contraseña y cerrar sesión.
+	DebeCambiarPassword bool `gorm:"column:debe_cambiar_password;not null;default:false" json:"debe_cambiar_password"`
 	
 	// Relaciones (roles y permisos están solo en Casbin; ver authz)
 	Persona *Persona `gorm:"foreignKey:PersonaID" json:"persona,omitempty"`
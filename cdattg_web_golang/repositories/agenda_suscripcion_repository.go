@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AgendaSuscripcionRepository enlaces secretos de suscripción a la agenda (uno por usuario)
+type AgendaSuscripcionRepository interface {
+	FindByUserID(userID uint) (*models.AgendaSuscripcion, error)
+	FindByTokenHash(hash string) (*models.AgendaSuscripcion, error)
+	// Guardar crea la suscripción del usuario o reemplaza su token (el enlace anterior deja de funcionar).
+	Guardar(userID uint, tokenHash string, ahora time.Time) error
+	DeleteByUserID(userID uint) error
+	MarcarUso(id uint, at time.Time) error
+}
+
+type agendaSuscripcionRepository struct {
+	db *gorm.DB
+}
+
+func NewAgendaSuscripcionRepository() AgendaSuscripcionRepository {
+	return &agendaSuscripcionRepository{db: database.GetDB()}
+}
+
+func (r *agendaSuscripcionRepository) FindByUserID(userID uint) (*models.AgendaSuscripcion, error) {
+	var s models.AgendaSuscripcion
+	if err := r.db.Where("user_id = ?", userID).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *agendaSuscripcionRepository) FindByTokenHash(hash string) (*models.AgendaSuscripcion, error) {
+	var s models.AgendaSuscripcion
+	if err := r.db.Where("token_hash = ?", hash).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *agendaSuscripcionRepository) Guardar(userID uint, tokenHash string, ahora time.Time) error {
+	s := models.AgendaSuscripcion{UserID: userID, TokenHash: tokenHash, CreatedAt: ahora}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"token_hash":    tokenHash,
+			"created_at":    ahora,
+			"ultimo_uso_at": nil,
+		}),
+	}).Create(&s).Error
+}
+
+func (r *agendaSuscripcionRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.AgendaSuscripcion{}).Error
+}
+
+func (r *agendaSuscripcionRepository) MarcarUso(id uint, at time.Time) error {
+	return r.db.Model(&models.AgendaSuscripcion{}).Where("id = ?", id).Update("ultimo_uso_at", at).Error
+}
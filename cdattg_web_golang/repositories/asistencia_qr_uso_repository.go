@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AsistenciaQRUsoRepository códigos QR rotativos ya usados en el autorregistro de asistencia
+type AsistenciaQRUsoRepository interface {
+	// Registrar guarda el uso; false si el aprendiz ya había usado ese código en la sesión.
+	Registrar(u *models.AsistenciaQRUso) (bool, error)
+	DeleteByAsistenciaID(asistenciaID uint) error
+}
+
+type asistenciaQRUsoRepository struct {
+	db *gorm.DB
+}
+
+func NewAsistenciaQRUsoRepository() AsistenciaQRUsoRepository {
+	return &asistenciaQRUsoRepository{db: database.GetDB()}
+}
+
+func (r *asistenciaQRUsoRepository) Registrar(u *models.AsistenciaQRUso) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(u)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *asistenciaQRUsoRepository) DeleteByAsistenciaID(asistenciaID uint) error {
+	return r.db.Where("asistencia_id = ?", asistenciaID).Delete(&models.AsistenciaQRUso{}).Error
+}
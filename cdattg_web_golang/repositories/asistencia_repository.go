@@ -28,6 +28,15 @@ const (
     WHERE aato.asistencia_aprendiz_id = aa.id
       AND toa.codigo = '` + codigoInasistenciaJustificada + `'
       AND toa.deleted_at IS NULL
+  )`
+	// Excusa aprobada del aprendiz (parámetro) para la sesión a.
+	asistSQLExcusaAprobada = `EXISTS (
+    SELECT 1 FROM excusa_inasistencia_sesiones eis
+    INNER JOIN excusas_inasistencia e ON e.id = eis.excusa_id
+    WHERE eis.asistencia_id = a.id
+      AND e.aprendiz_id = ?
+      AND e.estado = '` + models.ExcusaEstadoAprobada + `'
+      AND e.deleted_at IS NULL
   )`
 	// Sesiones abiertas/cerradas sin ningún aprendiz en asistencia_aprendices (ruido operativo).
 	asistSQLSoloSesionesConAprendices = `
@@ -53,6 +62,8 @@ const (
 type AsistenciaRepository interface {
 	Create(a *models.Asistencia) error
 	FindByID(id uint) (*models.Asistencia, error)
+	// FindContextoEvento carga solo ficha, sede y regional de la sesión (sin registros) para los eventos en tiempo real.
+	FindContextoEvento(id uint) (*models.Asistencia, error)
 	FindByInstructorFichaID(instructorFichaID uint) ([]models.Asistencia, error)
 	FindActivaByInstructorFichaID(instructorFichaID uint) (*models.Asistencia, error)
 	FindByInstructorFichaIDAndFecha(instructorFichaID uint, fecha time.Time) (*models.Asistencia, error)
@@ -104,6 +115,7 @@ type PendienteInstructorRow struct {
 }
 
 type InasistenciaDetalleRow struct {
+	AsistenciaIDs    []uint
 	Fecha            string
 	InstructorNombre string
 	Observaciones    string
@@ -185,7 +197,7 @@ type AsistenciaEfectivaRaw struct {
 	Observaciones string
 }
 
-// InasistenciaJustificadaRaw inasistencia con tipo INASISTENCIA_JUSTIFICADA en la sesión.
+// InasistenciaJustificadaRaw inasistencia con tipo INASISTENCIA_JUSTIFICADA o con excusa aprobada en la sesión.
 type InasistenciaJustificadaRaw struct {
 	AprendizID   uint
 	AsistenciaID uint
@@ -257,6 +269,14 @@ func (r *asistenciaRepository) FindByID(id uint) (*models.Asistencia, error) {
 	return &m, nil
 }
 
+func (r *asistenciaRepository) FindContextoEvento(id uint) (*models.Asistencia, error) {
+	var m models.Asistencia
+	if err := r.db.Preload("InstructorFicha.Ficha.Sede").First(&m, id).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 func (r *asistenciaRepository) FindByInstructorFichaID(instructorFichaID uint) ([]models.Asistencia, error) {
 	var list []models.Asistencia
 	if err := r.db.Where("instructor_ficha_id = ?", instructorFichaID).
@@ -671,13 +691,22 @@ func (r *asistenciaRepository) ListInasistenciasJustificadasEnSesiones(asistenci
 	}
 	var rows []row
 	err := r.db.Raw(`
-SELECT DISTINCT
+SELECT
   aa.aprendiz_ficha_id AS aprendiz_id,
   aa.asistencia_id
 FROM asistencia_aprendices aa
 WHERE aa.asistencia_id IN ?
   AND `+asistSQLInasistenciaJustificadaAA+`
-`, asistenciaIDs).Scan(&rows).Error
+UNION
+SELECT
+  e.aprendiz_id,
+  eis.asistencia_id
+FROM excusa_inasistencia_sesiones eis
+INNER JOIN excusas_inasistencia e ON e.id = eis.excusa_id
+WHERE eis.asistencia_id IN ?
+  AND e.estado = ?
+  AND e.deleted_at IS NULL
+`, asistenciaIDs, asistenciaIDs, models.ExcusaEstadoAprobada).Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
@@ -729,7 +758,7 @@ SELECT
     AND (aa.estado IS NULL OR aa.estado = '' OR aa.estado = 'ASISTENCIA_COMPLETA' OR aa.estado = 'ASISTENCIA_PARCIAL'),
     false
   ) AS asistio_efectivo,
-  COALESCE(`+asistSQLInasistenciaJustificadaAA+`, false) AS justificada,
+  (COALESCE(`+asistSQLInasistenciaJustificadaAA+`, false) OR `+asistSQLExcusaAprobada+`) AS justificada,
   COALESCE(aa.observaciones, '') AS observaciones
 FROM asistencias a
 INNER JOIN instructor_fichas_caracterizacion ifc ON a.instructor_ficha_id = ifc.id
@@ -742,7 +771,7 @@ WHERE a.fecha >= ? AND a.fecha < ?
   AND fc.ficha = ?
 ` + asistSQLSesionConAsistenciaTomada + `
 `
-	args := []interface{}{aprendizID, tInicio, tFin, fichaNumero}
+	args := []interface{}{aprendizID, aprendizID, tInicio, tFin, fichaNumero}
 	if strings.TrimSpace(sedeNombre) != "" {
 		raw += " AND COALESCE(s.nombre, '') = ?"
 		args = append(args, sedeNombre)
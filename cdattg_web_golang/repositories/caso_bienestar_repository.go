@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+const (
+	casoBienestarPreloadAprendizPersona = "Aprendiz.Persona"
+	casoBienestarPreloadResponsable     = "Responsable.Persona"
+	// casoBienestarPreloadRecordatorioResponsable responsable del caso al que pertenece un recordatorio.
+	casoBienestarPreloadRecordatorioResponsable = "Caso.Responsable"
+)
+
+// CasoBienestarFiltro filtros del listado de casos de bienestar.
+type CasoBienestarFiltro struct {
+	Estado            string
+	ResponsableUserID *uint
+	FichaID           *uint
+	// SinResponsable solo casos sin profesional asignado.
+	SinResponsable bool
+}
+
+// CasoBienestarRepository casos de seguimiento de bienestar con sus intervenciones, adjuntos y recordatorios.
+type CasoBienestarRepository interface {
+	Create(c *models.CasoBienestar) error
+	Update(c *models.CasoBienestar) error
+	FindByID(id uint) (*models.CasoBienestar, error)
+	// FindDetalle carga el caso con intervenciones, adjuntos y recordatorios.
+	FindDetalle(id uint) (*models.CasoBienestar, error)
+	List(f CasoBienestarFiltro) ([]models.CasoBienestar, error)
+	ListByPersona(personaID uint) ([]models.CasoBienestar, error)
+	// MapAbiertosPorAprendiz caso sin cerrar de cada aprendiz de aprendizIDs (si tiene).
+	MapAbiertosPorAprendiz(aprendizIDs []uint) (map[uint]models.CasoBienestar, error)
+
+	CreateIntervencion(i *models.CasoBienestarIntervencion) error
+	CreateAdjunto(a *models.CasoBienestarAdjunto) error
+	FindAdjunto(casoID, adjuntoID uint) (*models.CasoBienestarAdjunto, error)
+	CreateRecordatorio(r *models.CasoBienestarRecordatorio) error
+	// CompletarRecordatorio marca el recordatorio como hecho; devuelve false si no existe o ya estaba completado.
+	CompletarRecordatorio(casoID, recordatorioID uint, at time.Time) (bool, error)
+	// ListRecordatoriosPendientes recordatorios sin completar de casos abiertos con fecha hasta hasta.
+	// Con responsableUserID solo los de los casos asignados a ese usuario.
+	ListRecordatoriosPendientes(hasta time.Time, responsableUserID *uint) ([]models.CasoBienestarRecordatorio, error)
+	// ListRecordatoriosPorNotificar vencidos sin completar ni notificar de casos abiertos.
+	ListRecordatoriosPorNotificar(hasta time.Time) ([]models.CasoBienestarRecordatorio, error)
+	MarcarRecordatorioNotificado(id uint, at time.Time) error
+}
+
+type casoBienestarRepository struct {
+	db *gorm.DB
+}
+
+func NewCasoBienestarRepository() CasoBienestarRepository {
+	return &casoBienestarRepository{db: database.GetDB()}
+}
+
+func (r *casoBienestarRepository) Create(c *models.CasoBienestar) error {
+	return r.db.Create(c).Error
+}
+
+func (r *casoBienestarRepository) Update(c *models.CasoBienestar) error {
+	return r.db.Omit("Aprendiz", "Ficha", "Responsable", "Intervenciones", "Adjuntos", "Recordatorios").Save(c).Error
+}
+
+func (r *casoBienestarRepository) preloads() *gorm.DB {
+	return r.db.Preload(casoBienestarPreloadAprendizPersona).
+		Preload("Ficha").
+		Preload(casoBienestarPreloadResponsable)
+}
+
+func (r *casoBienestarRepository) FindByID(id uint) (*models.CasoBienestar, error) {
+	var c models.CasoBienestar
+	if err := r.preloads().First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *casoBienestarRepository) FindDetalle(id uint) (*models.CasoBienestar, error) {
+	var c models.CasoBienestar
+	err := r.preloads().
+		Preload("Intervenciones", func(db *gorm.DB) *gorm.DB { return db.Order("fecha DESC, id DESC") }).
+		Preload("Intervenciones.Autor.Persona").
+		Preload("Adjuntos", func(db *gorm.DB) *gorm.DB { return db.Order("created_at DESC, id DESC") }).
+		Preload("Recordatorios", func(db *gorm.DB) *gorm.DB { return db.Order("fecha_recordatorio ASC, id ASC") }).
+		First(&c, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *casoBienestarRepository) List(f CasoBienestarFiltro) ([]models.CasoBienestar, error) {
+	q := r.preloads()
+	if f.Estado != "" {
+		q = q.Where("estado = ?", f.Estado)
+	}
+	if f.ResponsableUserID != nil {
+		q = q.Where("responsable_user_id = ?", *f.ResponsableUserID)
+	}
+	if f.SinResponsable {
+		q = q.Where("responsable_user_id IS NULL")
+	}
+	if f.FichaID != nil {
+		q = q.Where("ficha_id = ?", *f.FichaID)
+	}
+	var list []models.CasoBienestar
+	err := q.Order("updated_at DESC, id DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *casoBienestarRepository) ListByPersona(personaID uint) ([]models.CasoBienestar, error) {
+	var list []models.CasoBienestar
+	err := r.preloads().
+		Where("persona_id = ?", personaID).
+		Order("created_at DESC, id DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *casoBienestarRepository) MapAbiertosPorAprendiz(aprendizIDs []uint) (map[uint]models.CasoBienestar, error) {
+	out := make(map[uint]models.CasoBienestar)
+	if len(aprendizIDs) == 0 {
+		return out, nil
+	}
+	var list []models.CasoBienestar
+	err := r.db.Where("aprendiz_id IN ? AND estado <> ?", aprendizIDs, models.CasoBienestarEstadoCerrado).Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		out[c.AprendizID] = c
+	}
+	return out, nil
+}
+
+func (r *casoBienestarRepository) CreateIntervencion(i *models.CasoBienestarIntervencion) error {
+	return r.db.Create(i).Error
+}
+
+func (r *casoBienestarRepository) CreateAdjunto(a *models.CasoBienestarAdjunto) error {
+	return r.db.Create(a).Error
+}
+
+func (r *casoBienestarRepository) FindAdjunto(casoID, adjuntoID uint) (*models.CasoBienestarAdjunto, error) {
+	var a models.CasoBienestarAdjunto
+	if err := r.db.Where("id = ? AND caso_id = ?", adjuntoID, casoID).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *casoBienestarRepository) CreateRecordatorio(rec *models.CasoBienestarRecordatorio) error {
+	return r.db.Create(rec).Error
+}
+
+func (r *casoBienestarRepository) CompletarRecordatorio(casoID, recordatorioID uint, at time.Time) (bool, error) {
+	res := r.db.Model(&models.CasoBienestarRecordatorio{}).
+		Where("id = ? AND caso_id = ? AND completado_at IS NULL", recordatorioID, casoID).
+		Update("completado_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// recordatoriosDeCasosAbiertos recordatorios sin completar cuyo caso sigue abierto.
+func (r *casoBienestarRepository) recordatoriosDeCasosAbiertos(hasta time.Time) *gorm.DB {
+	return r.db.Model(&models.CasoBienestarRecordatorio{}).
+		Joins("INNER JOIN casos_bienestar c ON c.id = caso_bienestar_recordatorios.caso_id").
+		Where("caso_bienestar_recordatorios.completado_at IS NULL AND caso_bienestar_recordatorios.fecha_recordatorio <= ?", hasta).
+		Where("c.estado <> ? AND c.deleted_at IS NULL", models.CasoBienestarEstadoCerrado)
+}
+
+func (r *casoBienestarRepository) ListRecordatoriosPendientes(hasta time.Time, responsableUserID *uint) ([]models.CasoBienestarRecordatorio, error) {
+	q := r.recordatoriosDeCasosAbiertos(hasta)
+	if responsableUserID != nil {
+		q = q.Where("c.responsable_user_id = ?", *responsableUserID)
+	}
+	var list []models.CasoBienestarRecordatorio
+	err := q.Preload("Caso.Aprendiz.Persona").Preload("Caso.Ficha").
+		Order("caso_bienestar_recordatorios.fecha_recordatorio ASC, caso_bienestar_recordatorios.id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *casoBienestarRepository) ListRecordatoriosPorNotificar(hasta time.Time) ([]models.CasoBienestarRecordatorio, error) {
+	var list []models.CasoBienestarRecordatorio
+	err := r.recordatoriosDeCasosAbiertos(hasta).
+		Where("caso_bienestar_recordatorios.notificado_at IS NULL").
+		Preload("Caso.Aprendiz.Persona").Preload("Caso.Ficha").Preload(casoBienestarPreloadRecordatorioResponsable).
+		Order("caso_bienestar_recordatorios.fecha_recordatorio ASC, caso_bienestar_recordatorios.id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *casoBienestarRepository) MarcarRecordatorioNotificado(id uint, at time.Time) error {
+	return r.db.Model(&models.CasoBienestarRecordatorio{}).Where("id = ?", id).Update("notificado_at", at).Error
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// CorreoSalienteFiltro filtros del listado de la cola de correos (Hasta es exclusivo).
+type CorreoSalienteFiltro struct {
+	Estado       string
+	Plantilla    string
+	Destinatario string
+	Desde        *time.Time
+	Hasta        *time.Time
+	Page         int
+	PageSize     int
+}
+
+// CorreoSalienteRepository acceso a la cola de salida de correos.
+type CorreoSalienteRepository interface {
+	// Create guarda el correo con sus adjuntos.
+	Create(c *models.CorreoSaliente) error
+	// FindByID correo con sus adjuntos sin el contenido.
+	FindByID(id uint) (*models.CorreoSaliente, error)
+	// FindParaEnviar correo con el contenido de los adjuntos.
+	FindParaEnviar(id uint) (*models.CorreoSaliente, error)
+	FindAdjunto(correoID, adjuntoID uint) (*models.CorreoSalienteAdjunto, error)
+	List(f CorreoSalienteFiltro) ([]models.CorreoSaliente, int64, error)
+	CountPorEstado() (map[string]int64, error)
+	// Update guarda estado, intentos y cuerpo (no toca los adjuntos).
+	Update(c *models.CorreoSaliente) error
+	// ReclamarPendientes toma hasta limite correos pendientes con intento vencido y corre su próximo intento a
+	// hasta, para que otra ejecución no los envíe a la vez. Si el proceso cae, vuelven a la cola al pasar hasta.
+	ReclamarPendientes(ahora, hasta time.Time, limite int) ([]uint, error)
+	// ReclamarPorID igual que ReclamarPendientes para un solo correo; false si no estaba pendiente o ya fue tomado.
+	ReclamarPorID(id uint, ahora, hasta time.Time) (bool, error)
+	// DeleteTerminadosAntesDe elimina correos enviados o fallidos (con sus adjuntos) actualizados antes de la fecha.
+	DeleteTerminadosAntesDe(antes time.Time) (int64, error)
+}
+
+type correoSalienteRepository struct {
+	db *gorm.DB
+}
+
+func NewCorreoSalienteRepository() CorreoSalienteRepository {
+	return &correoSalienteRepository{db: database.GetDB()}
+}
+
+func (r *correoSalienteRepository) Create(c *models.CorreoSaliente) error {
+	return r.db.Create(c).Error
+}
+
+func (r *correoSalienteRepository) FindByID(id uint) (*models.CorreoSaliente, error) {
+	var c models.CorreoSaliente
+	err := r.db.Preload("Adjuntos", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "correo_id", "nombre", "content_type", "tamano").Order("id")
+	}).First(&c, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *correoSalienteRepository) FindParaEnviar(id uint) (*models.CorreoSaliente, error) {
+	var c models.CorreoSaliente
+	err := r.db.Preload("Adjuntos", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&c, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *correoSalienteRepository) FindAdjunto(correoID, adjuntoID uint) (*models.CorreoSalienteAdjunto, error) {
+	var a models.CorreoSalienteAdjunto
+	if err := r.db.Where("correo_id = ?", correoID).First(&a, adjuntoID).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *correoSalienteRepository) List(f CorreoSalienteFiltro) ([]models.CorreoSaliente, int64, error) {
+	q := r.db.Model(&models.CorreoSaliente{})
+	if f.Estado != "" {
+		q = q.Where("estado = ?", f.Estado)
+	}
+	if f.Plantilla != "" {
+		q = q.Where("plantilla = ?", f.Plantilla)
+	}
+	if d := strings.TrimSpace(f.Destinatario); d != "" {
+		q = q.Where("destinatarios ILIKE ?", "%"+d+"%")
+	}
+	if f.Desde != nil {
+		q = q.Where("created_at >= ?", *f.Desde)
+	}
+	if f.Hasta != nil {
+		q = q.Where("created_at < ?", *f.Hasta)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.CorreoSaliente
+	err := q.Omit("cuerpo_html", "cuerpo_texto").
+		Order("created_at DESC, id DESC").
+		Offset((f.Page - 1) * f.PageSize).
+		Limit(f.PageSize).
+		Find(&list).Error
+	return list, total, err
+}
+
+func (r *correoSalienteRepository) CountPorEstado() (map[string]int64, error) {
+	type row struct {
+		Estado string
+		Total  int64
+	}
+	var rows []row
+	err := r.db.Model(&models.CorreoSaliente{}).
+		Select("estado, COUNT(*) AS total").
+		Group("estado").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int64, len(rows))
+	for _, rw := range rows {
+		out[rw.Estado] = rw.Total
+	}
+	return out, nil
+}
+
+func (r *correoSalienteRepository) Update(c *models.CorreoSaliente) error {
+	return r.db.Model(c).Select(
+		"estado", "intentos", "proximo_intento_at", "ultimo_error", "enviado_at", "cuerpo_html", "cuerpo_texto", "updated_at",
+	).Updates(c).Error
+}
+
+func (r *correoSalienteRepository) ReclamarPendientes(ahora, hasta time.Time, limite int) ([]uint, error) {
+	raw := `
+UPDATE correos_salientes SET proximo_intento_at = ?, updated_at = ?
+WHERE id IN (
+  SELECT id FROM correos_salientes
+  WHERE estado = ? AND proximo_intento_at <= ?
+  ORDER BY proximo_intento_at, id
+  LIMIT ?
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id`
+	var ids []uint
+	err := r.db.Raw(raw, hasta, ahora, models.CorreoSalienteEstadoPendiente, ahora, limite).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *correoSalienteRepository) ReclamarPorID(id uint, ahora, hasta time.Time) (bool, error) {
+	res := r.db.Model(&models.CorreoSaliente{}).
+		Where("id = ? AND estado = ? AND proximo_intento_at <= ?", id, models.CorreoSalienteEstadoPendiente, ahora).
+		Updates(map[string]interface{}{"proximo_intento_at": hasta, "updated_at": ahora})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *correoSalienteRepository) DeleteTerminadosAntesDe(antes time.Time) (int64, error) {
+	var n int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		terminados := tx.Model(&models.CorreoSaliente{}).Select("id").
+			Where("estado IN ? AND updated_at < ?",
+				[]string{models.CorreoSalienteEstadoEnviado, models.CorreoSalienteEstadoFallido}, antes)
+		if err := tx.Where("correo_id IN (?)", terminados).Delete(&models.CorreoSalienteAdjunto{}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("estado IN ? AND updated_at < ?",
+			[]string{models.CorreoSalienteEstadoEnviado, models.CorreoSalienteEstadoFallido}, antes).
+			Delete(&models.CorreoSaliente{})
+		n = res.RowsAffected
+		return res.Error
+	})
+	return n, err
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/sena/cdattg-web-golang/database"
 	"github.com/sena/cdattg-web-golang/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
@@ -17,13 +18,70 @@ const (
 	eleccionWhereProcesoIDAprendizEnPlancha = eleccionWhereProcesoID + " AND estado NOT IN ? AND (titular_aprendiz_id = ? OR suplente_aprendiz_id = ?)"
 )
 
+// EleccionAmbito ámbito de un proceso o de sus representantes: Tipo es uno de models.EleccionAmbito* e ID la
+// regional, sede o ficha correspondiente.
+type EleccionAmbito struct {
+	Tipo string
+	ID   uint
+}
+
+// columna columna con el ID del ámbito en eleccion_procesos y representantes_aprendiz.
+func (a EleccionAmbito) columna() string {
+	switch a.Tipo {
+	case models.EleccionAmbitoSede:
+		return "sede_id"
+	case models.EleccionAmbitoFicha:
+		return "ficha_id"
+	default:
+		return "regional_id"
+	}
+}
+
+// EleccionPadronRow aprendiz habilitado para votar (activo en el ámbito del proceso) con su sede, programa y
+// ficha, y si ya tiene constancia de participación. No dice por quién votó.
+type EleccionPadronRow struct {
+	AprendizID     uint
+	SedeID         uint
+	SedeNombre     string
+	ProgramaID     uint
+	ProgramaNombre string
+	FichaID        uint
+	FichaNumero    string
+	Voto           bool
+}
+
+// EleccionPendienteVotoRow aprendiz habilitado que aún no vota, con el correo de su usuario activo (vacío si no tiene).
+type EleccionPendienteVotoRow struct {
+	AprendizID   uint
+	Nombre       string // primer nombre, para el saludo
+	Email        string
+	TieneUsuario bool
+}
+
 type EleccionRepository interface {
 	CreateProceso(p *models.EleccionProceso) error
+	// UpdateProceso guarda la edición del ciclo; no toca estado ni ultimo_recordatorio_at, que solo cambian con
+	// CambiarEstadoProceso y ReservarRecordatorio.
 	UpdateProceso(p *models.EleccionProceso) error
+	// CambiarEstadoProceso pasa el proceso de anterior a nuevo; false si su estado ya no era anterior.
+	CambiarEstadoProceso(id uint, anterior, nuevo string, userID *uint) (bool, error)
+	// EnProcesoBloqueado bloquea la fila del proceso (SELECT … FOR UPDATE) y ejecuta fn con un repositorio sobre la
+	// misma transacción. El voto y el cierre del conteo pasan por aquí, así que no se cruzan.
+	EnProcesoBloqueado(id uint, fn func(repo EleccionRepository, p *models.EleccionProceso) error) error
 	FindProcesoByID(id uint) (*models.EleccionProceso, error)
-	ExistsProcesoByRegionalAnio(regionalID uint, anio int, excludeID uint) (bool, error)
+	// ReservarRecordatorio marca el recordatorio de voto de un proceso en votación solo si el anterior es previo a
+	// desde (o no hay); false si otra petición lo marcó primero.
+	ReservarRecordatorio(procesoID, userID uint, at, desde time.Time) (bool, error)
+	// LiberarRecordatorio devuelve ultimo_recordatorio_at al valor anterior si sigue siendo la marca at.
+	LiberarRecordatorio(procesoID uint, at time.Time, anterior *time.Time) error
+	ExistsProcesoEnAmbitoAnio(ambito EleccionAmbito, anio int, excludeID uint) (bool, error)
 	ListProcesos(regionalIDs []uint, unrestricted bool) ([]models.EleccionProceso, error)
-	FindProcesoActivoByRegional(regionalID uint) (*models.EleccionProceso, error)
+	ListProcesosActivosAprendiz(regionalID, sedeID, fichaID uint) ([]models.EleccionProceso, error)
+	FindSedeByID(id uint) (*models.Sede, error)
+	FindFichaByID(id uint) (*models.FichaCaracterizacion, error)
+	ListProcesosAutoTransicion() ([]models.EleccionProceso, error)
+	CreateProcesoEvento(e *models.EleccionProcesoEvento) error
+	ListProcesoEventos(procesoID uint) ([]models.EleccionProcesoEvento, error)
 
 	CreatePlancha(p *models.EleccionPlancha) error
 	UpdatePlancha(p *models.EleccionPlancha) error
@@ -32,23 +90,37 @@ type EleccionRepository interface {
 	CountPlanchasConfirmadas(procesoID uint) (int64, error)
 	ExistsAprendizEnPlancha(procesoID, aprendizID uint) (bool, error)
 
-	UpsertVoto(v *models.EleccionVoto) error
-	CreateVoto(v *models.EleccionVoto) error
-	FindVotoByProcesoAndUser(procesoID, userID uint) (*models.EleccionVoto, error)
-	CountVotosByProceso(procesoID uint) (int64, error)
-	ListVotosByProceso(procesoID uint) ([]models.EleccionVoto, error)
-	CountVotosByPlancha(planchaID uint) (int64, error)
+	RegistrarVotoSecreto(part *models.EleccionParticipacion, papeleta *models.EleccionPapeleta) error
+	// MezclarPapeletas reescribe todas las papeletas del proceso en una transacción propia.
+	MezclarPapeletas(procesoID uint) error
+	FindParticipacion(procesoID, userID uint) (*models.EleccionParticipacion, error)
+	CountParticipacionesByProceso(procesoID uint) (int64, error)
+	ListParticipacionesByProceso(procesoID uint) ([]models.EleccionParticipacion, error)
+	ListVotadoAtByProceso(procesoID uint) ([]time.Time, error)
+	CountPapeletasByProceso(procesoID uint) (int64, error)
+	CountPapeletasByPlancha(planchaID uint) (int64, error)
+	ListPapeletasByProceso(procesoID uint) ([]models.EleccionPapeleta, error)
+	FindPapeletaByRecibo(procesoID uint, reciboHash string) (*models.EleccionPapeleta, error)
 
 	SaveResultado(r *models.EleccionResultado) error
 	FindResultadoByProceso(procesoID uint) (*models.EleccionResultado, error)
 
-	FindRepresentantesVigentesByRegional(regionalID uint) (*models.RepresentanteAprendiz, error)
+	CreateActa(a *models.EleccionActa) error
+	FindActaByProceso(procesoID uint) (*models.EleccionActa, error)
+	FindActaByHash(hash string) (*models.EleccionActa, error)
+
+	FindRepresentantesVigentes(ambito EleccionAmbito) (*models.RepresentanteAprendiz, error)
 	FindRepresentantesHistorial(regionalID uint) ([]models.RepresentanteAprendiz, error)
-	CerrarVigenciaRepresentantes(regionalID uint, hasta time.Time) error
+	CerrarVigenciaRepresentantes(ambito EleccionAmbito, hasta time.Time) error
 	CreateRepresentante(r *models.RepresentanteAprendiz) error
 
-	CountAprendicesActivosByRegional(regionalID uint) (int64, error)
-	FindAprendizIDsExRepresentantes(regionalID uint, anioActual int) ([]uint, error)
+	CountPadron(ambito EleccionAmbito) (int64, error)
+	// FindAprendizActivoEnAmbito matrícula activa de la persona dentro del ámbito; una persona puede estar activa
+	// en varias fichas y solo cuenta la del ámbito del proceso.
+	FindAprendizActivoEnAmbito(personaID uint, ambito EleccionAmbito) (*models.Aprendiz, error)
+	ListPadronParticipacion(procesoID uint, ambito EleccionAmbito) ([]EleccionPadronRow, error)
+	ListPendientesVoto(procesoID uint, ambito EleccionAmbito) ([]EleccionPendienteVotoRow, error)
+	FindAprendizIDsExRepresentantes(ambito EleccionAmbito, anioActual int) ([]uint, error)
 }
 
 type eleccionRepository struct {
@@ -60,7 +132,7 @@ func NewEleccionRepository() EleccionRepository {
 }
 
 func (r *eleccionRepository) preloadProceso(q *gorm.DB) *gorm.DB {
-	return q.Preload("Regional")
+	return q.Preload("Regional").Preload("Sede").Preload("Ficha")
 }
 
 func (r *eleccionRepository) preloadPlancha(q *gorm.DB) *gorm.DB {
@@ -75,7 +147,46 @@ func (r *eleccionRepository) CreateProceso(p *models.EleccionProceso) error {
 }
 
 func (r *eleccionRepository) UpdateProceso(p *models.EleccionProceso) error {
-	return r.db.Save(p).Error
+	return r.db.Omit("estado", "ultimo_recordatorio_at").Save(p).Error
+}
+
+func (r *eleccionRepository) CambiarEstadoProceso(id uint, anterior, nuevo string, userID *uint) (bool, error) {
+	res := r.db.Model(&models.EleccionProceso{}).
+		Where("id = ? AND estado = ?", id, anterior).
+		Updates(map[string]interface{}{
+			"estado":       nuevo,
+			"user_edit_id": userID,
+			"updated_at":   time.Now(),
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *eleccionRepository) EnProcesoBloqueado(id uint, fn func(repo EleccionRepository, p *models.EleccionProceso) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var p models.EleccionProceso
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&p, id).Error; err != nil {
+			return err
+		}
+		return fn(&eleccionRepository{db: tx}, &p)
+	})
+}
+
+func (r *eleccionRepository) ReservarRecordatorio(procesoID, userID uint, at, desde time.Time) (bool, error) {
+	res := r.db.Model(&models.EleccionProceso{}).
+		Where("id = ? AND estado = ? AND (ultimo_recordatorio_at IS NULL OR ultimo_recordatorio_at < ?)",
+			procesoID, models.EleccionEstadoVotacion, desde).
+		Updates(map[string]interface{}{
+			"ultimo_recordatorio_at": at,
+			"user_edit_id":           userID,
+			"updated_at":             at,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *eleccionRepository) LiberarRecordatorio(procesoID uint, at time.Time, anterior *time.Time) error {
+	return r.db.Model(&models.EleccionProceso{}).
+		Where("id = ? AND ultimo_recordatorio_at = ?", procesoID, at).
+		Update("ultimo_recordatorio_at", anterior).Error
 }
 
 func (r *eleccionRepository) FindProcesoByID(id uint) (*models.EleccionProceso, error) {
@@ -86,9 +197,10 @@ func (r *eleccionRepository) FindProcesoByID(id uint) (*models.EleccionProceso,
 	return &p, nil
 }
 
-func (r *eleccionRepository) ExistsProcesoByRegionalAnio(regionalID uint, anio int, excludeID uint) (bool, error) {
+func (r *eleccionRepository) ExistsProcesoEnAmbitoAnio(ambito EleccionAmbito, anio int, excludeID uint) (bool, error) {
 	var n int64
-	q := r.db.Model(&models.EleccionProceso{}).Where("regional_id = ? AND anio = ?", regionalID, anio)
+	q := r.db.Model(&models.EleccionProceso{}).
+		Where("ambito = ? AND "+ambito.columna()+" = ? AND anio = ?", ambito.Tipo, ambito.ID, anio)
 	if excludeID > 0 {
 		q = q.Where("id <> ?", excludeID)
 	}
@@ -110,20 +222,72 @@ func (r *eleccionRepository) ListProcesos(regionalIDs []uint, unrestricted bool)
 	return list, nil
 }
 
-func (r *eleccionRepository) FindProcesoActivoByRegional(regionalID uint) (*models.EleccionProceso, error) {
-	var p models.EleccionProceso
+// ListProcesosActivosAprendiz procesos en curso en los que participa un aprendiz: el de su regional, el de su sede
+// y el de su ficha. Los más recientes primero.
+func (r *eleccionRepository) ListProcesosActivosAprendiz(regionalID, sedeID, fichaID uint) ([]models.EleccionProceso, error) {
+	var list []models.EleccionProceso
 	err := r.preloadProceso(r.db).
-		Where("regional_id = ? AND estado IN ?", regionalID, []string{
+		Where("estado IN ?", []string{
 			models.EleccionEstadoInscripcion,
 			models.EleccionEstadoVotacion,
 			models.EleccionEstadoEmpatePendiente,
 		}).
+		Where("(ambito = ? AND regional_id = ?) OR (ambito = ? AND sede_id = ?) OR (ambito = ? AND ficha_id = ?)",
+			models.EleccionAmbitoRegional, regionalID,
+			models.EleccionAmbitoSede, sedeID,
+			models.EleccionAmbitoFicha, fichaID).
 		Order("id DESC").
-		First(&p).Error
+		Find(&list).Error
 	if err != nil {
 		return nil, err
 	}
-	return &p, nil
+	return list, nil
+}
+
+func (r *eleccionRepository) FindSedeByID(id uint) (*models.Sede, error) {
+	var sede models.Sede
+	if err := r.db.First(&sede, id).Error; err != nil {
+		return nil, err
+	}
+	return &sede, nil
+}
+
+// FindFichaByID ficha con su sede, para ubicarla en una regional.
+func (r *eleccionRepository) FindFichaByID(id uint) (*models.FichaCaracterizacion, error) {
+	var ficha models.FichaCaracterizacion
+	if err := r.db.Preload("Sede").First(&ficha, id).Error; err != nil {
+		return nil, err
+	}
+	return &ficha, nil
+}
+
+// ListProcesosAutoTransicion procesos con transiciones automáticas que aún no llegan al escrutinio.
+func (r *eleccionRepository) ListProcesosAutoTransicion() ([]models.EleccionProceso, error) {
+	var list []models.EleccionProceso
+	err := r.preloadProceso(r.db).
+		Where("auto_transiciones AND estado IN ?", []string{
+			models.EleccionEstadoBorrador,
+			models.EleccionEstadoInscripcion,
+			models.EleccionEstadoVotacion,
+		}).
+		Order("id").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *eleccionRepository) CreateProcesoEvento(e *models.EleccionProcesoEvento) error {
+	return r.db.Create(e).Error
+}
+
+func (r *eleccionRepository) ListProcesoEventos(procesoID uint) ([]models.EleccionProcesoEvento, error) {
+	var list []models.EleccionProcesoEvento
+	if err := r.db.Where(eleccionWhereProcesoID, procesoID).Order("created_at, id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
 }
 
 func (r *eleccionRepository) CreatePlancha(p *models.EleccionPlancha) error {
@@ -173,54 +337,92 @@ func (r *eleccionRepository) ExistsAprendizEnPlancha(procesoID, aprendizID uint)
 	return n > 0, err
 }
 
-func (r *eleccionRepository) CreateVoto(v *models.EleccionVoto) error {
-	return r.db.Create(v).Error
-}
-
-func (r *eleccionRepository) UpsertVoto(v *models.EleccionVoto) error {
-	var existing models.EleccionVoto
-	err := r.db.Where(eleccionWhereProcesoIDVotanteUser, v.ProcesoID, v.VotanteUserID).First(&existing).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return r.db.Create(v).Error
-	}
-	if err != nil {
-		return err
-	}
-	existing.PlanchaID = v.PlanchaID
-	existing.VotanteAprendizID = v.VotanteAprendizID
-	return r.db.Save(&existing).Error
-}
-
-func (r *eleccionRepository) FindVotoByProcesoAndUser(procesoID, userID uint) (*models.EleccionVoto, error) {
-	var v models.EleccionVoto
-	if err := r.db.Where(eleccionWhereProcesoIDVotanteUser, procesoID, userID).First(&v).Error; err != nil {
+// RegistrarVotoSecreto guarda participación y papeleta juntas: si el votante ya participó, el índice único
+// rechaza la participación y no queda papeleta. Se llama dentro de EnProcesoBloqueado. Ambas filas salen de la
+// misma transacción, así que hasta que MezclarPapeletas reescriba la papeleta su xmin es el de la participación.
+func (r *eleccionRepository) RegistrarVotoSecreto(part *models.EleccionParticipacion, papeleta *models.EleccionPapeleta) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(part).Error; err != nil {
+			return err
+		}
+		return tx.Create(papeleta).Error
+	})
+}
+
+// MezclarPapeletas reescribe en una sola transacción, con el proceso bloqueado, todas las papeletas del proceso:
+// quedan con el mismo xmin, que no corresponde a ninguna participación. Debe llamarse después de confirmar el voto,
+// nunca dentro de la transacción que lo guarda. La reescritura va en una subtransacción: el bloqueo ya consumió un
+// xid, así que el de las papeletas tampoco queda pegado al de la última participación.
+func (r *eleccionRepository) MezclarPapeletas(procesoID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var p models.EleccionProceso
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&p, procesoID).Error; err != nil {
+			return err
+		}
+		return tx.Transaction(func(sp *gorm.DB) error {
+			return sp.Exec("UPDATE eleccion_papeletas SET plancha_id = plancha_id WHERE proceso_id = ?", procesoID).Error
+		})
+	})
+}
+
+func (r *eleccionRepository) FindParticipacion(procesoID, userID uint) (*models.EleccionParticipacion, error) {
+	var p models.EleccionParticipacion
+	if err := r.db.Where(eleccionWhereProcesoIDVotanteUser, procesoID, userID).First(&p).Error; err != nil {
 		return nil, err
 	}
-	return &v, nil
+	return &p, nil
 }
 
-func (r *eleccionRepository) CountVotosByProceso(procesoID uint) (int64, error) {
+func (r *eleccionRepository) CountParticipacionesByProceso(procesoID uint) (int64, error) {
 	var n int64
-	err := r.db.Model(&models.EleccionVoto{}).Where(eleccionWhereProcesoID, procesoID).Count(&n).Error
+	err := r.db.Model(&models.EleccionParticipacion{}).Where(eleccionWhereProcesoID, procesoID).Count(&n).Error
 	return n, err
 }
 
-func (r *eleccionRepository) ListVotosByProceso(procesoID uint) ([]models.EleccionVoto, error) {
-	var list []models.EleccionVoto
+func (r *eleccionRepository) ListParticipacionesByProceso(procesoID uint) ([]models.EleccionParticipacion, error) {
+	var list []models.EleccionParticipacion
 	err := r.db.Where(eleccionWhereProcesoID, procesoID).
 		Preload("VotanteAprendiz."+eleccionPreloadAprendiz).
-		Preload("Plancha").
-		Order("updated_at ASC").
+		Order("votado_at ASC").
 		Find(&list).Error
 	return list, err
 }
 
-func (r *eleccionRepository) CountVotosByPlancha(planchaID uint) (int64, error) {
+// ListVotadoAtByProceso solo las horas de voto, para la serie de participación en vivo.
+func (r *eleccionRepository) ListVotadoAtByProceso(procesoID uint) ([]time.Time, error) {
+	var out []time.Time
+	err := r.db.Model(&models.EleccionParticipacion{}).Where(eleccionWhereProcesoID, procesoID).
+		Order("votado_at ASC").Pluck("votado_at", &out).Error
+	return out, err
+}
+
+func (r *eleccionRepository) CountPapeletasByProceso(procesoID uint) (int64, error) {
 	var n int64
-	err := r.db.Model(&models.EleccionVoto{}).Where("plancha_id = ?", planchaID).Count(&n).Error
+	err := r.db.Model(&models.EleccionPapeleta{}).Where(eleccionWhereProcesoID, procesoID).Count(&n).Error
 	return n, err
 }
 
+func (r *eleccionRepository) CountPapeletasByPlancha(planchaID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.EleccionPapeleta{}).Where("plancha_id = ?", planchaID).Count(&n).Error
+	return n, err
+}
+
+// ListPapeletasByProceso ordena por hash de recibo (no por orden de inserción) para no revelar la secuencia de votos.
+func (r *eleccionRepository) ListPapeletasByProceso(procesoID uint) ([]models.EleccionPapeleta, error) {
+	var list []models.EleccionPapeleta
+	err := r.db.Where(eleccionWhereProcesoID, procesoID).Order("recibo_hash ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *eleccionRepository) FindPapeletaByRecibo(procesoID uint, reciboHash string) (*models.EleccionPapeleta, error) {
+	var p models.EleccionPapeleta
+	if err := r.db.Where(eleccionWhereProcesoID+" AND recibo_hash = ?", procesoID, reciboHash).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 func (r *eleccionRepository) SaveResultado(res *models.EleccionResultado) error {
 	var existing models.EleccionResultado
 	err := r.db.Where(eleccionWhereProcesoID, res.ProcesoID).First(&existing).Error
@@ -235,6 +437,9 @@ func (r *eleccionRepository) SaveResultado(res *models.EleccionResultado) error
 	existing.DetalleJSON = res.DetalleJSON
 	existing.Empate = res.Empate
 	existing.NotaDesempate = res.NotaDesempate
+	existing.DesempateMetodo = res.DesempateMetodo
+	existing.DesempateAt = res.DesempateAt
+	existing.CerradaAt = res.CerradaAt
 	existing.UserRegistroID = res.UserRegistroID
 	return r.db.Save(&existing).Error
 }
@@ -247,17 +452,38 @@ func (r *eleccionRepository) FindResultadoByProceso(procesoID uint) (*models.Ele
 	return &res, nil
 }
 
+func (r *eleccionRepository) CreateActa(a *models.EleccionActa) error {
+	return r.db.Create(a).Error
+}
+
+func (r *eleccionRepository) FindActaByProceso(procesoID uint) (*models.EleccionActa, error) {
+	var a models.EleccionActa
+	if err := r.db.Where(eleccionWhereProcesoID, procesoID).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *eleccionRepository) FindActaByHash(hash string) (*models.EleccionActa, error) {
+	var a models.EleccionActa
+	if err := r.db.Where("hash = ?", hash).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
 func (r *eleccionRepository) preloadRepresentante(q *gorm.DB) *gorm.DB {
 	return q.Preload("Regional").
-		Preload("Proceso").
+		Preload("Proceso.Sede").
+		Preload("Proceso.Ficha").
 		Preload("TitularAprendiz."+eleccionPreloadAprendiz).
 		Preload("SuplenteAprendiz."+eleccionPreloadAprendiz)
 }
 
-func (r *eleccionRepository) FindRepresentantesVigentesByRegional(regionalID uint) (*models.RepresentanteAprendiz, error) {
+func (r *eleccionRepository) FindRepresentantesVigentes(ambito EleccionAmbito) (*models.RepresentanteAprendiz, error) {
 	var rep models.RepresentanteAprendiz
 	err := r.preloadRepresentante(r.db).
-		Where("regional_id = ? AND vigencia_hasta IS NULL", regionalID).
+		Where("ambito = ? AND "+ambito.columna()+" = ? AND vigencia_hasta IS NULL", ambito.Tipo, ambito.ID).
 		Order("vigencia_desde DESC").
 		First(&rep).Error
 	if err != nil {
@@ -266,6 +492,7 @@ func (r *eleccionRepository) FindRepresentantesVigentesByRegional(regionalID uin
 	return &rep, nil
 }
 
+// FindRepresentantesHistorial representantes de todos los ámbitos de la regional (regional, sedes y fichas).
 func (r *eleccionRepository) FindRepresentantesHistorial(regionalID uint) ([]models.RepresentanteAprendiz, error) {
 	var list []models.RepresentanteAprendiz
 	err := r.preloadRepresentante(r.db).
@@ -275,9 +502,9 @@ func (r *eleccionRepository) FindRepresentantesHistorial(regionalID uint) ([]mod
 	return list, err
 }
 
-func (r *eleccionRepository) CerrarVigenciaRepresentantes(regionalID uint, hasta time.Time) error {
+func (r *eleccionRepository) CerrarVigenciaRepresentantes(ambito EleccionAmbito, hasta time.Time) error {
 	return r.db.Model(&models.RepresentanteAprendiz{}).
-		Where("regional_id = ? AND vigencia_hasta IS NULL", regionalID).
+		Where("ambito = ? AND "+ambito.columna()+" = ? AND vigencia_hasta IS NULL", ambito.Tipo, ambito.ID).
 		Update("vigencia_hasta", hasta).Error
 }
 
@@ -285,29 +512,83 @@ func (r *eleccionRepository) CreateRepresentante(rep *models.RepresentanteAprend
 	return r.db.Create(rep).Error
 }
 
-func (r *eleccionRepository) CountAprendicesActivosByRegional(regionalID uint) (int64, error) {
-	var n int64
-	err := r.db.Model(&models.Aprendiz{}).
+// padronActivos aprendices activos con ficha en el ámbito (una sede de la regional, la sede o la ficha): quienes
+// pueden votar en su proceso.
+func (r *eleccionRepository) padronActivos(ambito EleccionAmbito) *gorm.DB {
+	filtro := "s.regional_id = ?"
+	switch ambito.Tipo {
+	case models.EleccionAmbitoSede:
+		filtro = "s.id = ?"
+	case models.EleccionAmbitoFicha:
+		filtro = "fc.id = ?"
+	}
+	return r.db.Model(&models.Aprendiz{}).
 		Joins("INNER JOIN fichas_caracterizacion fc ON fc.id = aprendices.ficha_caracterizacion_id").
 		Joins("INNER JOIN sedes s ON s.id = fc.sede_id").
-		Where("aprendices.estado = ? AND s.regional_id = ?", true, regionalID).
-		Count(&n).Error
+		Where("aprendices.estado = ? AND "+filtro, true, ambito.ID)
+}
+
+func (r *eleccionRepository) CountPadron(ambito EleccionAmbito) (int64, error) {
+	var n int64
+	err := r.padronActivos(ambito).Count(&n).Error
 	return n, err
 }
 
-func (r *eleccionRepository) FindAprendizIDsExRepresentantes(regionalID uint, anioActual int) ([]uint, error) {
+func (r *eleccionRepository) FindAprendizActivoEnAmbito(personaID uint, ambito EleccionAmbito) (*models.Aprendiz, error) {
+	var a models.Aprendiz
+	if err := r.padronActivos(ambito).
+		Where("aprendices.persona_id = ?", personaID).
+		Order("aprendices.updated_at DESC").
+		Preload("Persona").
+		Preload(aprendizPreloadFichaPrograma).
+		Preload(aprendizPreloadFichaSedeRegional).
+		First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *eleccionRepository) ListPadronParticipacion(procesoID uint, ambito EleccionAmbito) ([]EleccionPadronRow, error) {
+	var out []EleccionPadronRow
+	err := r.padronActivos(ambito).
+		Select(`aprendices.id AS aprendiz_id, s.id AS sede_id, s.nombre AS sede_nombre,
+			pf.id AS programa_id, pf.nombre AS programa_nombre, fc.id AS ficha_id, fc.ficha AS ficha_numero,
+			(ep.id IS NOT NULL) AS voto`).
+		Joins("INNER JOIN programas_formacion pf ON pf.id = fc.programa_formacion_id").
+		Joins("LEFT JOIN eleccion_participaciones ep ON ep.votante_aprendiz_id = aprendices.id AND ep.proceso_id = ?", procesoID).
+		Scan(&out).Error
+	return out, err
+}
+
+func (r *eleccionRepository) ListPendientesVoto(procesoID uint, ambito EleccionAmbito) ([]EleccionPendienteVotoRow, error) {
+	var out []EleccionPendienteVotoRow
+	err := r.padronActivos(ambito).
+		Select(`aprendices.id AS aprendiz_id,
+			COALESCE(p.primer_nombre, '') AS nombre,
+			COALESCE(u.email, '') AS email, (u.id IS NOT NULL) AS tiene_usuario`).
+		Joins("INNER JOIN personas p ON p.id = aprendices.persona_id").
+		Joins("LEFT JOIN users u ON u.persona_id = aprendices.persona_id AND u.status = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM eleccion_participaciones ep WHERE ep.votante_aprendiz_id = aprendices.id AND ep.proceso_id = ?)", procesoID).
+		Order("aprendices.id ASC").
+		Scan(&out).Error
+	return out, err
+}
+
+// FindAprendizIDsExRepresentantes titulares y suplentes de ciclos anteriores del mismo ámbito.
+func (r *eleccionRepository) FindAprendizIDsExRepresentantes(ambito EleccionAmbito, anioActual int) ([]uint, error) {
 	var out []uint
+	filtro := "ra.ambito = ? AND ra." + ambito.columna() + " = ? AND ep.anio < ?"
 	err := r.db.Raw(`
 		SELECT DISTINCT aprendiz_id FROM (
 			SELECT titular_aprendiz_id AS aprendiz_id
 			FROM representantes_aprendiz ra
 			INNER JOIN eleccion_procesos ep ON ep.id = ra.proceso_id
-			WHERE ra.regional_id = ? AND ep.anio < ?
+			WHERE `+filtro+`
 			UNION
 			SELECT suplente_aprendiz_id AS aprendiz_id
 			FROM representantes_aprendiz ra
 			INNER JOIN eleccion_procesos ep ON ep.id = ra.proceso_id
-			WHERE ra.regional_id = ? AND ep.anio < ?
-		) ex`, regionalID, anioActual, regionalID, anioActual).Scan(&out).Error
+			WHERE `+filtro+`
+		) ex`, ambito.Tipo, ambito.ID, anioActual, ambito.Tipo, ambito.ID, anioActual).Scan(&out).Error
 	return out, err
 }
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// eleccionTestDB abre TEST_DATABASE_DSN (PostgreSQL) con un esquema propio que se borra al terminar. El xmin solo
+// existe en PostgreSQL, así que sin la variable la prueba se omite.
+func eleccionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN no definido: la prueba necesita PostgreSQL")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Discard, DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Una sola conexión para que el search_path valga en todas las consultas.
+	sqlDB.SetMaxOpenConns(1)
+	esquema := fmt.Sprintf("prueba_eleccion_%d", time.Now().UnixNano())
+	if err := db.Exec("CREATE SCHEMA " + esquema).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + esquema + " CASCADE")
+		sqlDB.Close()
+	})
+	if err := db.Exec("SET search_path TO " + esquema).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.EleccionProceso{}, &models.EleccionParticipacion{}, &models.EleccionPapeleta{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMezclarPapeletas_xminNoDelataAlVotante(t *testing.T) {
+	db := eleccionTestDB(t)
+	p := &models.EleccionProceso{RegionalID: 1, Anio: 2026, NombreCiclo: "Prueba", Estado: models.EleccionEstadoVotacion}
+	if err := db.Omit(clause.Associations).Create(p).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo := &eleccionRepository{db: db}
+	for i := uint(1); i <= 3; i++ {
+		part := &models.EleccionParticipacion{ProcesoID: p.ID, VotanteUserID: i, VotanteAprendizID: i, VotadoAt: time.Now()}
+		papeleta := &models.EleccionPapeleta{ReciboHash: fmt.Sprintf("%064d", i), ProcesoID: p.ID, PlanchaID: i % 2}
+		err := repo.EnProcesoBloqueado(p.ID, func(tx EleccionRepository, _ *models.EleccionProceso) error {
+			return tx.RegistrarVotoSecreto(part, papeleta)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.MezclarPapeletas(p.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var participaciones, papeletas []int64
+	if err := db.Raw("SELECT xmin::text::bigint FROM eleccion_participaciones WHERE proceso_id = ?", p.ID).
+		Scan(&participaciones).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Raw("SELECT DISTINCT xmin::text::bigint FROM eleccion_papeletas WHERE proceso_id = ?", p.ID).
+		Scan(&papeletas).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(papeletas) != 1 {
+		t.Fatalf("las papeletas deben compartir un solo xmin, hay %v", papeletas)
+	}
+	for _, x := range participaciones {
+		if d := papeletas[0] - x; d >= -1 && d <= 1 {
+			t.Fatalf("xmin de las papeletas %d junto al de una participación %d", papeletas[0], x)
+		}
+	}
+}
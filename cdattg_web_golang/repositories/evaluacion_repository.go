@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RAPs vigentes del programa (competencia_programa -> resultados_aprendizaje_competencia).
+const evalSQLRapsPrograma = `
+SELECT DISTINCT rac.rap_id
+FROM competencia_programa cp
+INNER JOIN competencias c ON c.id = cp.competencia_id AND c.deleted_at IS NULL AND c.status = true
+INNER JOIN resultados_aprendizaje_competencia rac ON rac.competencia_id = cp.competencia_id AND rac.deleted_at IS NULL
+INNER JOIN resultados_aprendizajes ra ON ra.id = rac.rap_id AND ra.deleted_at IS NULL AND ra.status = true
+WHERE cp.programa_id = fc.programa_formacion_id
+  AND cp.deleted_at IS NULL`
+
+// RapProgramaRow un RAP del programa con su competencia.
+type RapProgramaRow struct {
+	CompetenciaID     uint
+	CompetenciaCodigo string
+	CompetenciaNombre string
+	RapID             uint
+	RapCodigo         string
+	RapNombre         string
+}
+
+// FichaEvaluacionPendienteRow ficha con fecha de fin en el rango y el conteo de juicios que faltan.
+type FichaEvaluacionPendienteRow struct {
+	FichaID        uint
+	FichaNumero    string
+	ProgramaNombre string
+	FechaFin       time.Time
+	Aprendices     int
+	Raps           int
+	Juzgados       int
+}
+
+// EvaluacionRepository juicios evaluativos por RAP y consultas de la matriz de evaluación de fichas
+type EvaluacionRepository interface {
+	ListRapsPrograma(programaID uint) ([]RapProgramaRow, error)
+	ListJuiciosFicha(fichaID uint) ([]models.JuicioEvaluativo, error)
+	// UpsertJuicios crea o actualiza (por aprendiz y RAP) los juicios en una transacción.
+	UpsertJuicios(juicios []models.JuicioEvaluativo) error
+	// ListFichasPorFinalizar fichas activas con fecha_fin en [desde, hasta).
+	// Con instructorID se limita a las fichas que el instructor lidera o tiene asignadas.
+	ListFichasPorFinalizar(desde, hasta time.Time, instructorID *uint) ([]FichaEvaluacionPendienteRow, error)
+}
+
+type evaluacionRepository struct {
+	db *gorm.DB
+}
+
+func NewEvaluacionRepository() EvaluacionRepository {
+	return &evaluacionRepository{db: database.GetDB()}
+}
+
+func (r *evaluacionRepository) ListRapsPrograma(programaID uint) ([]RapProgramaRow, error) {
+	type row struct {
+		CompetenciaID     uint   `gorm:"column:competencia_id"`
+		CompetenciaCodigo string `gorm:"column:competencia_codigo"`
+		CompetenciaNombre string `gorm:"column:competencia_nombre"`
+		RapID             uint   `gorm:"column:rap_id"`
+		RapCodigo         string `gorm:"column:rap_codigo"`
+		RapNombre         string `gorm:"column:rap_nombre"`
+	}
+	var rows []row
+	err := r.db.Raw(`
+SELECT DISTINCT
+  c.id AS competencia_id,
+  c.codigo AS competencia_codigo,
+  c.nombre AS competencia_nombre,
+  ra.id AS rap_id,
+  ra.codigo AS rap_codigo,
+  ra.nombre AS rap_nombre
+FROM competencia_programa cp
+INNER JOIN competencias c ON c.id = cp.competencia_id AND c.deleted_at IS NULL AND c.status = true
+INNER JOIN resultados_aprendizaje_competencia rac ON rac.competencia_id = c.id AND rac.deleted_at IS NULL
+INNER JOIN resultados_aprendizajes ra ON ra.id = rac.rap_id AND ra.deleted_at IS NULL AND ra.status = true
+WHERE cp.programa_id = ?
+  AND cp.deleted_at IS NULL
+ORDER BY c.codigo, c.id, ra.codigo, ra.id
+`, programaID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RapProgramaRow, len(rows))
+	for i := range rows {
+		out[i] = RapProgramaRow(rows[i])
+	}
+	return out, nil
+}
+
+func (r *evaluacionRepository) ListJuiciosFicha(fichaID uint) ([]models.JuicioEvaluativo, error) {
+	var list []models.JuicioEvaluativo
+	err := r.db.Where("ficha_id = ?", fichaID).Find(&list).Error
+	return list, err
+}
+
+func (r *evaluacionRepository) UpsertJuicios(juicios []models.JuicioEvaluativo) error {
+	if len(juicios) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "aprendiz_id"}, {Name: "rap_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"ficha_id", "competencia_id", "juicio", "observaciones", "instructor_id",
+				"fecha_juicio", "user_edit_id", "updated_at",
+			}),
+		}).Create(&juicios).Error
+	})
+}
+
+func (r *evaluacionRepository) ListFichasPorFinalizar(desde, hasta time.Time, instructorID *uint) ([]FichaEvaluacionPendienteRow, error) {
+	type row struct {
+		FichaID        uint      `gorm:"column:ficha_id"`
+		FichaNumero    string    `gorm:"column:ficha_numero"`
+		ProgramaNombre string    `gorm:"column:programa_nombre"`
+		FechaFin       time.Time `gorm:"column:fecha_fin"`
+		Aprendices     int       `gorm:"column:aprendices"`
+		Raps           int       `gorm:"column:raps"`
+		Juzgados       int       `gorm:"column:juzgados"`
+	}
+	raw := `
+SELECT
+  fc.id AS ficha_id,
+  fc.ficha AS ficha_numero,
+  COALESCE(pf.nombre, '') AS programa_nombre,
+  fc.fecha_fin,
+  (SELECT COUNT(*) FROM aprendices ap
+    WHERE ap.ficha_caracterizacion_id = fc.id AND ap.estado = true AND ap.deleted_at IS NULL) AS aprendices,
+  (SELECT COUNT(*) FROM (` + evalSQLRapsPrograma + `) raps) AS raps,
+  (SELECT COUNT(*) FROM juicios_evaluativos je
+    INNER JOIN aprendices ap ON ap.id = je.aprendiz_id AND ap.estado = true AND ap.deleted_at IS NULL
+    WHERE je.ficha_id = fc.id
+      AND je.deleted_at IS NULL
+      AND je.juicio IN ?
+      AND je.rap_id IN (` + evalSQLRapsPrograma + `)) AS juzgados
+FROM fichas_caracterizacion fc
+LEFT JOIN programas_formacion pf ON pf.id = fc.programa_formacion_id
+WHERE fc.deleted_at IS NULL
+  AND fc.status = true
+  AND fc.fecha_fin >= ? AND fc.fecha_fin < ?`
+	args := []interface{}{[]string{models.JuicioAprobado, models.JuicioNoAprobado}, desde, hasta}
+	if instructorID != nil {
+		raw += ` AND (fc.instructor_id = ? OR fc.id IN (
+  SELECT ifc.ficha_id FROM instructor_fichas_caracterizacion ifc
+  WHERE ifc.instructor_id = ? AND ifc.deleted_at IS NULL))`
+		args = append(args, *instructorID, *instructorID)
+	}
+	raw += " ORDER BY fc.fecha_fin, fc.ficha"
+	var rows []row
+	if err := r.db.Raw(raw, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]FichaEvaluacionPendienteRow, len(rows))
+	for i := range rows {
+		out[i] = FichaEvaluacionPendienteRow(rows[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+const (
+	excusaPreloadAprendizPersona    = "Aprendiz.Persona"
+	excusaPreloadSesionesAsistencia = "Sesiones.Asistencia"
+	excusaPreloadSesionesInstructor = "Sesiones.Asistencia.InstructorFicha.Instructor.Persona"
+)
+
+// ExcusaFiltro filtros del listado de excusas para revisión. Con FichaIDs no nil solo se listan esas fichas.
+type ExcusaFiltro struct {
+	Estado   string
+	FichaID  *uint
+	FichaIDs []uint
+}
+
+// ExcusaRepository excusas de inasistencia presentadas por aprendices
+type ExcusaRepository interface {
+	Create(e *models.ExcusaInasistencia) error
+	FindByID(id uint) (*models.ExcusaInasistencia, error)
+	ListByAprendiz(aprendizID uint) ([]models.ExcusaInasistencia, error)
+	List(f ExcusaFiltro) ([]models.ExcusaInasistencia, error)
+	// ListAsistenciaIDsCubiertas sesiones de asistenciaIDs que ya tienen una excusa pendiente o aprobada del aprendiz.
+	ListAsistenciaIDsCubiertas(aprendizID uint, asistenciaIDs []uint) ([]uint, error)
+	// ListAprendicesExcusadosEnSesion aprendices con excusa aprobada para la sesión.
+	ListAprendicesExcusadosEnSesion(asistenciaID uint) ([]uint, error)
+	// ListExcusadosEnSesiones pares aprendiz/sesión con excusa aprobada entre las sesiones dadas.
+	ListExcusadosEnSesiones(asistenciaIDs []uint) ([]InasistenciaJustificadaRaw, error)
+	// Revisar cambia el estado de una excusa pendiente; devuelve false si ya no estaba pendiente.
+	Revisar(id uint, estado string, userID uint, at time.Time, observacion string) (bool, error)
+}
+
+type excusaRepository struct {
+	db *gorm.DB
+}
+
+func NewExcusaRepository() ExcusaRepository {
+	return &excusaRepository{db: database.GetDB()}
+}
+
+func (r *excusaRepository) Create(e *models.ExcusaInasistencia) error {
+	return r.db.Create(e).Error
+}
+
+func (r *excusaRepository) preloads() *gorm.DB {
+	return r.db.Preload(excusaPreloadAprendizPersona).
+		Preload("Ficha").
+		Preload(excusaPreloadSesionesAsistencia).
+		Preload(excusaPreloadSesionesInstructor)
+}
+
+func (r *excusaRepository) FindByID(id uint) (*models.ExcusaInasistencia, error) {
+	var e models.ExcusaInasistencia
+	if err := r.preloads().First(&e, id).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *excusaRepository) ListByAprendiz(aprendizID uint) ([]models.ExcusaInasistencia, error) {
+	var list []models.ExcusaInasistencia
+	err := r.preloads().
+		Where("aprendiz_id = ?", aprendizID).
+		Order("created_at DESC, id DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *excusaRepository) List(f ExcusaFiltro) ([]models.ExcusaInasistencia, error) {
+	q := r.preloads()
+	if f.Estado != "" {
+		q = q.Where("estado = ?", f.Estado)
+	}
+	if f.FichaID != nil {
+		q = q.Where("ficha_id = ?", *f.FichaID)
+	}
+	if f.FichaIDs != nil {
+		if len(f.FichaIDs) == 0 {
+			return nil, nil
+		}
+		q = q.Where("ficha_id IN ?", f.FichaIDs)
+	}
+	var list []models.ExcusaInasistencia
+	err := q.Order("created_at ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *excusaRepository) ListAsistenciaIDsCubiertas(aprendizID uint, asistenciaIDs []uint) ([]uint, error) {
+	if len(asistenciaIDs) == 0 {
+		return nil, nil
+	}
+	var ids []uint
+	err := r.db.Table("excusa_inasistencia_sesiones eis").
+		Joins("INNER JOIN excusas_inasistencia e ON e.id = eis.excusa_id").
+		Where("e.aprendiz_id = ? AND eis.asistencia_id IN ?", aprendizID, asistenciaIDs).
+		Where("e.estado IN ? AND e.deleted_at IS NULL", []string{models.ExcusaEstadoPendiente, models.ExcusaEstadoAprobada}).
+		Distinct().
+		Pluck("eis.asistencia_id", &ids).Error
+	return ids, err
+}
+
+func (r *excusaRepository) ListAprendicesExcusadosEnSesion(asistenciaID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("excusa_inasistencia_sesiones eis").
+		Joins("INNER JOIN excusas_inasistencia e ON e.id = eis.excusa_id").
+		Where("eis.asistencia_id = ? AND e.estado = ? AND e.deleted_at IS NULL", asistenciaID, models.ExcusaEstadoAprobada).
+		Distinct().
+		Pluck("e.aprendiz_id", &ids).Error
+	return ids, err
+}
+
+func (r *excusaRepository) ListExcusadosEnSesiones(asistenciaIDs []uint) ([]InasistenciaJustificadaRaw, error) {
+	if len(asistenciaIDs) == 0 {
+		return nil, nil
+	}
+	var rows []InasistenciaJustificadaRaw
+	err := r.db.Table("excusa_inasistencia_sesiones eis").
+		Select("DISTINCT e.aprendiz_id, eis.asistencia_id").
+		Joins("INNER JOIN excusas_inasistencia e ON e.id = eis.excusa_id").
+		Where("eis.asistencia_id IN ? AND e.estado = ? AND e.deleted_at IS NULL", asistenciaIDs, models.ExcusaEstadoAprobada).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *excusaRepository) Revisar(id uint, estado string, userID uint, at time.Time, observacion string) (bool, error) {
+	res := r.db.Model(&models.ExcusaInasistencia{}).
+		Where("id = ? AND estado = ?", id, models.ExcusaEstadoPendiente).
+		Updates(map[string]interface{}{
+			"estado":               estado,
+			"revisado_por_user_id": userID,
+			"revisado_at":          at,
+			"observacion_revision": observacion,
+		})
+	return res.RowsAffected > 0, res.Error
+}
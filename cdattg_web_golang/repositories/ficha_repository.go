@@ -20,6 +20,8 @@ type FichaRepository interface {
 	FindActivasParaFechaConJornada(fecha time.Time, sedeID *uint) ([]models.FichaCaracterizacion, error)
 	// FindActivasSolapandoRango fichas activas cuyo período solapa [desde, hasta] (para análisis histórico).
 	FindActivasSolapandoRango(desde, hasta time.Time, sedeIDs []uint, jornadaNombre string, soloEnFormacion bool) ([]models.FichaCaracterizacion, error)
+	// FindActivasByAmbienteID fichas activas que usan el ambiente, con Jornada, programa y FichaDiasFormacion.
+	FindActivasByAmbienteID(ambienteID uint) ([]models.FichaCaracterizacion, error)
 	Search(query string) ([]models.FichaCaracterizacion, error)
 	Create(ficha *models.FichaCaracterizacion) error
 	Update(ficha *models.FichaCaracterizacion) error
@@ -105,6 +107,15 @@ func (r *fichaRepository) FindActivasParaFechaConJornada(fecha time.Time, sedeID
 	return list, err
 }
 
+func (r *fichaRepository) FindActivasByAmbienteID(ambienteID uint) ([]models.FichaCaracterizacion, error) {
+	var list []models.FichaCaracterizacion
+	err := r.db.Where("ambiente_id = ? AND status = ?", ambienteID, true).
+		Preload("Jornada").Preload("ProgramaFormacion").Preload("FichaDiasFormacion").
+		Order("ficha").
+		Find(&list).Error
+	return list, err
+}
+
 // FindActivasSolapandoRango devuelve fichas activas con vigencia que intersecta el rango consultado.
 func (r *fichaRepository) FindActivasSolapandoRango(desde, hasta time.Time, sedeIDs []uint, jornadaNombre string, soloEnFormacion bool) ([]models.FichaCaracterizacion, error) {
 	desdeStr := desde.Format(time.DateOnly)
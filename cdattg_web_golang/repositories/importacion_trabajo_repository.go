@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// ImportacionTrabajoFiltro filtros del listado de trabajos de importación (UserID 0 = todos los usuarios).
+type ImportacionTrabajoFiltro struct {
+	UserID   uint
+	Tipo     string
+	Estado   string
+	Page     int
+	PageSize int
+}
+
+// ImportacionTrabajoRepository acceso a los trabajos de importación de Excel, su archivo y sus incidencias.
+type ImportacionTrabajoRepository interface {
+	// Create guarda el trabajo con su archivo.
+	Create(t *models.ImportacionTrabajo) error
+	// FindByID trabajo sin el archivo.
+	FindByID(id uint) (*models.ImportacionTrabajo, error)
+	FindArchivo(trabajoID uint) (*models.ImportacionTrabajoArchivo, error)
+	List(f ImportacionTrabajoFiltro) ([]models.ImportacionTrabajo, int64, error)
+	ListIncidencias(trabajoID uint) ([]models.ImportacionIncidencia, error)
+	// ListReclamables trabajos pendientes o en proceso con la reserva vencida (su réplica cayó), más antiguos primero.
+	ListReclamables(ahora time.Time, limite int) ([]uint, error)
+	// ReclamarPorID pasa el trabajo a procesando con reserva hasta la fecha y suma un intento; false si no estaba
+	// pendiente ni con la reserva vencida (otra réplica lo tiene o ya terminó).
+	ReclamarPorID(id uint, ahora, hasta time.Time) (bool, error)
+	// GuardarAvance guarda contadores, fila actual, estado y reserva del trabajo junto con las incidencias nuevas, en
+	// una transacción. Solo aplica si el trabajo sigue con la reserva del intento t.Intentos; false si otra réplica
+	// lo tomó entretanto.
+	GuardarAvance(t *models.ImportacionTrabajo, incidencias []models.ImportacionIncidencia) (bool, error)
+	// MarcarPendiente devuelve a la cola un trabajo fallido; false si no estaba fallido.
+	MarcarPendiente(id uint, ahora time.Time) (bool, error)
+	// DeleteTerminadosAntesDe elimina trabajos completados o fallidos (con archivo e incidencias) actualizados antes
+	// de la fecha.
+	DeleteTerminadosAntesDe(antes time.Time) (int64, error)
+}
+
+type importacionTrabajoRepository struct {
+	db *gorm.DB
+}
+
+func NewImportacionTrabajoRepository() ImportacionTrabajoRepository {
+	return &importacionTrabajoRepository{db: database.GetDB()}
+}
+
+func (r *importacionTrabajoRepository) Create(t *models.ImportacionTrabajo) error {
+	return r.db.Create(t).Error
+}
+
+func (r *importacionTrabajoRepository) FindByID(id uint) (*models.ImportacionTrabajo, error) {
+	var t models.ImportacionTrabajo
+	if err := r.db.First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *importacionTrabajoRepository) FindArchivo(trabajoID uint) (*models.ImportacionTrabajoArchivo, error) {
+	var a models.ImportacionTrabajoArchivo
+	if err := r.db.Where("trabajo_id = ?", trabajoID).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *importacionTrabajoRepository) List(f ImportacionTrabajoFiltro) ([]models.ImportacionTrabajo, int64, error) {
+	q := r.db.Model(&models.ImportacionTrabajo{})
+	if f.UserID != 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if f.Tipo != "" {
+		q = q.Where("tipo = ?", f.Tipo)
+	}
+	if f.Estado != "" {
+		q = q.Where("estado = ?", f.Estado)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.ImportacionTrabajo
+	err := q.Order("created_at DESC, id DESC").
+		Offset((f.Page - 1) * f.PageSize).
+		Limit(f.PageSize).
+		Find(&list).Error
+	return list, total, err
+}
+
+func (r *importacionTrabajoRepository) ListIncidencias(trabajoID uint) ([]models.ImportacionIncidencia, error) {
+	var list []models.ImportacionIncidencia
+	err := r.db.Where("trabajo_id = ?", trabajoID).Order("fila, id").Find(&list).Error
+	return list, err
+}
+
+func (r *importacionTrabajoRepository) ListReclamables(ahora time.Time, limite int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.ImportacionTrabajo{}).
+		Where("estado = ? OR (estado = ? AND (reclamado_hasta IS NULL OR reclamado_hasta <= ?))",
+			models.ImportacionEstadoPendiente, models.ImportacionEstadoProcesando, ahora).
+		Order("created_at, id").
+		Limit(limite).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *importacionTrabajoRepository) ReclamarPorID(id uint, ahora, hasta time.Time) (bool, error) {
+	res := r.db.Model(&models.ImportacionTrabajo{}).
+		Where("id = ? AND (estado = ? OR (estado = ? AND (reclamado_hasta IS NULL OR reclamado_hasta <= ?)))",
+			id, models.ImportacionEstadoPendiente, models.ImportacionEstadoProcesando, ahora).
+		Updates(map[string]interface{}{
+			"estado":          models.ImportacionEstadoProcesando,
+			"reclamado_hasta": hasta,
+			"intentos":        gorm.Expr("intentos + 1"),
+			"iniciado_at":     gorm.Expr("COALESCE(iniciado_at, ?)", ahora),
+			"ultimo_error":    "",
+			"updated_at":      ahora,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *importacionTrabajoRepository) GuardarAvance(t *models.ImportacionTrabajo, incidencias []models.ImportacionIncidencia) (bool, error) {
+	vigente := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.ImportacionTrabajo{}).
+			Where("id = ? AND intentos = ? AND estado = ?", t.ID, t.Intentos, models.ImportacionEstadoProcesando).
+			Updates(map[string]interface{}{
+				"estado":          t.Estado,
+				"total_filas":     t.TotalFilas,
+				"fila_actual":     t.FilaActual,
+				"procesados":      t.Procesados,
+				"duplicados":      t.Duplicados,
+				"errores":         t.Errores,
+				"actualizados":    t.Actualizados,
+				"creados":         t.Creados,
+				"redes_creadas":   t.RedesCreadas,
+				"ficha_id":        t.FichaID,
+				"ficha_creada":    t.FichaCreada,
+				"incidencias":     t.Incidencias,
+				"ultimo_error":    t.UltimoError,
+				"reclamado_hasta": t.ReclamadoHasta,
+				"finalizado_at":   t.FinalizadoAt,
+				"updated_at":      t.UpdatedAt,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		vigente = true
+		if len(incidencias) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(incidencias, 200).Error
+	})
+	return vigente && err == nil, err
+}
+
+func (r *importacionTrabajoRepository) MarcarPendiente(id uint, ahora time.Time) (bool, error) {
+	res := r.db.Model(&models.ImportacionTrabajo{}).
+		Where("id = ? AND estado = ?", id, models.ImportacionEstadoFallido).
+		Updates(map[string]interface{}{
+			"estado":          models.ImportacionEstadoPendiente,
+			"reclamado_hasta": nil,
+			"finalizado_at":   nil,
+			"updated_at":      ahora,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *importacionTrabajoRepository) DeleteTerminadosAntesDe(antes time.Time) (int64, error) {
+	var n int64
+	estados := []string{models.ImportacionEstadoCompletado, models.ImportacionEstadoFallido}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		terminados := func() *gorm.DB {
+			return tx.Model(&models.ImportacionTrabajo{}).Select("id").Where("estado IN ? AND updated_at < ?", estados, antes)
+		}
+		if err := tx.Where("trabajo_id IN (?)", terminados()).Delete(&models.ImportacionIncidencia{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trabajo_id IN (?)", terminados()).Delete(&models.ImportacionTrabajoArchivo{}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("estado IN ? AND updated_at < ?", estados, antes).Delete(&models.ImportacionTrabajo{})
+		n = res.RowsAffected
+		return res.Error
+	})
+	return n, err
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginRepository historial de intentos de login y contadores de bloqueo
+type LoginRepository interface {
+	Create(l *models.Login) error
+	ListByUser(userID uint, limit int) ([]models.Login, error)
+	DeleteAnteriores(antesDe time.Time) (int64, error)
+
+	FindBloqueo(clave string) (*models.LoginBloqueo, error)
+	RegistrarFallo(clave string, ahora, ventanaDesde time.Time) (*models.LoginBloqueo, error)
+	Bloquear(clave string, hasta time.Time) error
+	DeleteBloqueo(clave string) error
+	DeleteBloqueosInactivos(antesDe time.Time) (int64, error)
+}
+
+type loginRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginRepository() LoginRepository {
+	return &loginRepository{db: database.GetDB()}
+}
+
+func (r *loginRepository) Create(l *models.Login) error {
+	return r.db.Create(l).Error
+}
+
+func (r *loginRepository) ListByUser(userID uint, limit int) ([]models.Login, error) {
+	var list []models.Login
+	err := r.db.Where("user_id = ?", userID).
+		Order("fecha_login DESC, id DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// DeleteAnteriores borra definitivamente el historial anterior a antesDe.
+func (r *loginRepository) DeleteAnteriores(antesDe time.Time) (int64, error) {
+	res := r.db.Unscoped().Where("fecha_login < ?", antesDe).Delete(&models.Login{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *loginRepository) FindBloqueo(clave string) (*models.LoginBloqueo, error) {
+	var b models.LoginBloqueo
+	if err := r.db.Where("clave = ?", clave).First(&b).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// RegistrarFallo suma un fallo a la clave de forma atómica; si el último fallo es anterior a ventanaDesde
+// el contador vuelve a empezar. Devuelve el estado actualizado.
+func (r *loginRepository) RegistrarFallo(clave string, ahora, ventanaDesde time.Time) (*models.LoginBloqueo, error) {
+	b := models.LoginBloqueo{Clave: clave, Fallos: 1, UltimoFalloAt: ahora}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "clave"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"fallos":          gorm.Expr("CASE WHEN login_bloqueos.ultimo_fallo_at < ? THEN 1 ELSE login_bloqueos.fallos + 1 END", ventanaDesde),
+			"ultimo_fallo_at": ahora,
+			"updated_at":      ahora,
+		}),
+	}).Create(&b).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.FindBloqueo(clave)
+}
+
+func (r *loginRepository) Bloquear(clave string, hasta time.Time) error {
+	return r.db.Model(&models.LoginBloqueo{}).Where("clave = ?", clave).Update("bloqueado_hasta", hasta).Error
+}
+
+func (r *loginRepository) DeleteBloqueo(clave string) error {
+	return r.db.Where("clave = ?", clave).Delete(&models.LoginBloqueo{}).Error
+}
+
+// DeleteBloqueosInactivos borra contadores sin fallos recientes ni bloqueo vigente.
+func (r *loginRepository) DeleteBloqueosInactivos(antesDe time.Time) (int64, error) {
+	res := r.db.Where("ultimo_fallo_at < ? AND (bloqueado_hasta IS NULL OR bloqueado_hasta < ?)", antesDe, antesDe).
+		Delete(&models.LoginBloqueo{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificacionUsuarioRepository acceso al centro de notificaciones y a las preferencias por tipo.
+type NotificacionUsuarioRepository interface {
+	CreateBatch(list []models.NotificacionUsuario) error
+	List(userID uint, soloNoLeidas bool, page, pageSize int) ([]models.NotificacionUsuario, int64, error)
+	CountNoLeidas(userID uint) (int64, error)
+	// MarcarLeida false si la notificación no existe, es de otro usuario o ya estaba leída.
+	MarcarLeida(id, userID uint, en time.Time) (bool, error)
+	MarcarTodasLeidas(userID uint, en time.Time) (int64, error)
+	// DeleteLeidasAntesDe elimina las notificaciones leídas antes de la fecha.
+	DeleteLeidasAntesDe(antes time.Time) (int64, error)
+	ListPreferencias(userID uint) ([]models.NotificacionPreferencia, error)
+	GuardarPreferencia(p *models.NotificacionPreferencia) error
+	// MapDesactivados de userIDs, los que desactivaron el tipo.
+	MapDesactivados(tipo string, userIDs []uint) (map[uint]bool, error)
+	// UserIDsPorPersonas usuarios activos de las personas.
+	UserIDsPorPersonas(personaIDs []uint) ([]uint, error)
+	// UserIDsPorInstructores usuarios activos de los instructores.
+	UserIDsPorInstructores(instructorIDs []uint) ([]uint, error)
+	// UserIDsPorAprendices usuarios activos de los aprendices.
+	UserIDsPorAprendices(aprendizIDs []uint) ([]uint, error)
+}
+
+type notificacionUsuarioRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificacionUsuarioRepository() NotificacionUsuarioRepository {
+	return &notificacionUsuarioRepository{db: database.GetDB()}
+}
+
+func (r *notificacionUsuarioRepository) CreateBatch(list []models.NotificacionUsuario) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(&list, 200).Error
+}
+
+func (r *notificacionUsuarioRepository) List(userID uint, soloNoLeidas bool, page, pageSize int) ([]models.NotificacionUsuario, int64, error) {
+	q := r.db.Model(&models.NotificacionUsuario{}).Where("user_id = ?", userID)
+	if soloNoLeidas {
+		q = q.Where("leida_en IS NULL")
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.NotificacionUsuario
+	err := q.Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&list).Error
+	return list, total, err
+}
+
+func (r *notificacionUsuarioRepository) CountNoLeidas(userID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.NotificacionUsuario{}).Where("user_id = ? AND leida_en IS NULL", userID).Count(&n).Error
+	return n, err
+}
+
+func (r *notificacionUsuarioRepository) MarcarLeida(id, userID uint, en time.Time) (bool, error) {
+	res := r.db.Model(&models.NotificacionUsuario{}).
+		Where("id = ? AND user_id = ? AND leida_en IS NULL", id, userID).
+		Update("leida_en", en)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *notificacionUsuarioRepository) MarcarTodasLeidas(userID uint, en time.Time) (int64, error) {
+	res := r.db.Model(&models.NotificacionUsuario{}).
+		Where("user_id = ? AND leida_en IS NULL", userID).
+		Update("leida_en", en)
+	return res.RowsAffected, res.Error
+}
+
+func (r *notificacionUsuarioRepository) DeleteLeidasAntesDe(antes time.Time) (int64, error) {
+	res := r.db.Where("leida_en IS NOT NULL AND leida_en < ?", antes).Delete(&models.NotificacionUsuario{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *notificacionUsuarioRepository) ListPreferencias(userID uint) ([]models.NotificacionPreferencia, error) {
+	var list []models.NotificacionPreferencia
+	err := r.db.Where("user_id = ?", userID).Find(&list).Error
+	return list, err
+}
+
+func (r *notificacionUsuarioRepository) GuardarPreferencia(p *models.NotificacionPreferencia) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "tipo"}},
+		DoUpdates: clause.AssignmentColumns([]string{"activo", "updated_at"}),
+	}).Create(p).Error
+}
+
+func (r *notificacionUsuarioRepository) MapDesactivados(tipo string, userIDs []uint) (map[uint]bool, error) {
+	out := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return out, nil
+	}
+	var ids []uint
+	err := r.db.Model(&models.NotificacionPreferencia{}).
+		Where("tipo = ? AND activo = ? AND user_id IN ?", tipo, false, userIDs).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
+func (r *notificacionUsuarioRepository) UserIDsPorPersonas(personaIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(personaIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.User{}).
+		Where("persona_id IN ? AND status = ?", personaIDs, true).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *notificacionUsuarioRepository) UserIDsPorInstructores(instructorIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(instructorIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.User{}).
+		Joins("JOIN instructors ON instructors.persona_id = users.persona_id").
+		Where("instructors.id IN ? AND users.status = ?", instructorIDs, true).
+		Distinct().
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
+
+func (r *notificacionUsuarioRepository) UserIDsPorAprendices(aprendizIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(aprendizIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.User{}).
+		Joins("JOIN aprendices ON aprendices.persona_id = users.persona_id").
+		Where("aprendices.id IN ? AND users.status = ?", aprendizIDs, true).
+		Distinct().
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
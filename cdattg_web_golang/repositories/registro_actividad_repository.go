@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// RegistroActividadFiltro filtros del listado de auditoría (Hasta es exclusivo)
+type RegistroActividadFiltro struct {
+	UserID     *uint
+	Accion     string
+	Tabla      string
+	RegistroID *uint
+	Desde      *time.Time
+	Hasta      *time.Time
+	Page       int
+	PageSize   int
+}
+
+// RegistroActividadRepository acceso a registro_actividades (auditoría)
+type RegistroActividadRepository interface {
+	Create(r *models.RegistroActividades) error
+	List(f RegistroActividadFiltro) ([]models.RegistroActividades, int64, error)
+}
+
+type registroActividadRepository struct {
+	db *gorm.DB
+}
+
+func NewRegistroActividadRepository() RegistroActividadRepository {
+	return &registroActividadRepository{db: database.GetDB()}
+}
+
+func (r *registroActividadRepository) Create(reg *models.RegistroActividades) error {
+	return r.db.Create(reg).Error
+}
+
+func (r *registroActividadRepository) List(f RegistroActividadFiltro) ([]models.RegistroActividades, int64, error) {
+	q := r.db.Model(&models.RegistroActividades{})
+	if f.UserID != nil {
+		q = q.Where("user_id = ?", *f.UserID)
+	}
+	if f.Accion != "" {
+		q = q.Where("accion = ?", f.Accion)
+	}
+	if f.Tabla != "" {
+		q = q.Where("tabla = ?", f.Tabla)
+	}
+	if f.RegistroID != nil {
+		q = q.Where("registro_id = ?", *f.RegistroID)
+	}
+	if f.Desde != nil {
+		q = q.Where("fecha_accion >= ?", *f.Desde)
+	}
+	if f.Hasta != nil {
+		q = q.Where("fecha_accion < ?", *f.Hasta)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.RegistroActividades
+	err := q.Preload("User").
+		Order("fecha_accion DESC, id DESC").
+		Offset((f.Page - 1) * f.PageSize).
+		Limit(f.PageSize).
+		Find(&list).Error
+	return list, total, err
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// ReporteAsistenciaRepository trabajos de generación de reportes consolidados de asistencia y su archivo
+type ReporteAsistenciaRepository interface {
+	Create(r *models.ReporteAsistencia) error
+	FindByID(id uint) (*models.ReporteAsistencia, error)
+	FindArchivo(reporteID uint) (*models.ReporteAsistenciaArchivo, error)
+	ListByFicha(fichaID uint, limit int) ([]models.ReporteAsistencia, error)
+	// ListReclamables reportes pendientes o en proceso con la reserva vencida (su réplica cayó), más antiguos primero.
+	ListReclamables(ahora time.Time, limite int) ([]uint, error)
+	// ReclamarPorID pasa el reporte a PROCESANDO con reserva hasta la fecha y suma un intento; false si no estaba
+	// pendiente ni con la reserva vencida (otra réplica lo tiene o ya terminó).
+	ReclamarPorID(id uint, ahora, hasta time.Time) (bool, error)
+	// Finalizar guarda el estado final del reporte y, si lo hay, su archivo, en una transacción. Solo aplica si el
+	// reporte sigue con la reserva del intento r.Intentos; false si otra réplica lo tomó entretanto.
+	Finalizar(r *models.ReporteAsistencia, archivo *models.ReporteAsistenciaArchivo) (bool, error)
+	// DeleteTerminadosAntesDe elimina reportes completados o con error (con su archivo) finalizados antes de la fecha.
+	DeleteTerminadosAntesDe(antes time.Time) (int64, error)
+}
+
+type reporteAsistenciaRepository struct {
+	db *gorm.DB
+}
+
+func NewReporteAsistenciaRepository() ReporteAsistenciaRepository {
+	return &reporteAsistenciaRepository{db: database.GetDB()}
+}
+
+func (r *reporteAsistenciaRepository) Create(rep *models.ReporteAsistencia) error {
+	return r.db.Create(rep).Error
+}
+
+func (r *reporteAsistenciaRepository) FindByID(id uint) (*models.ReporteAsistencia, error) {
+	var rep models.ReporteAsistencia
+	if err := r.db.First(&rep, id).Error; err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+func (r *reporteAsistenciaRepository) FindArchivo(reporteID uint) (*models.ReporteAsistenciaArchivo, error) {
+	var a models.ReporteAsistenciaArchivo
+	if err := r.db.Where("reporte_id = ?", reporteID).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *reporteAsistenciaRepository) ListByFicha(fichaID uint, limit int) ([]models.ReporteAsistencia, error) {
+	var list []models.ReporteAsistencia
+	if err := r.db.Where("ficha_id = ?", fichaID).Order("created_at DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *reporteAsistenciaRepository) ListReclamables(ahora time.Time, limite int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.ReporteAsistencia{}).
+		Where("estado = ? OR (estado = ? AND (reclamado_hasta IS NULL OR reclamado_hasta <= ?))",
+			models.ReporteAsistenciaPendiente, models.ReporteAsistenciaProcesando, ahora).
+		Order("created_at, id").
+		Limit(limite).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *reporteAsistenciaRepository) ReclamarPorID(id uint, ahora, hasta time.Time) (bool, error) {
+	res := r.db.Model(&models.ReporteAsistencia{}).
+		Where("id = ? AND (estado = ? OR (estado = ? AND (reclamado_hasta IS NULL OR reclamado_hasta <= ?)))",
+			id, models.ReporteAsistenciaPendiente, models.ReporteAsistenciaProcesando, ahora).
+		Updates(map[string]interface{}{
+			"estado":          models.ReporteAsistenciaProcesando,
+			"reclamado_hasta": hasta,
+			"intentos":        gorm.Expr("intentos + 1"),
+			"updated_at":      ahora,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *reporteAsistenciaRepository) Finalizar(rep *models.ReporteAsistencia, archivo *models.ReporteAsistenciaArchivo) (bool, error) {
+	vigente := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.ReporteAsistencia{}).
+			Where("id = ? AND intentos = ? AND estado = ?", rep.ID, rep.Intentos, models.ReporteAsistenciaProcesando).
+			Updates(map[string]interface{}{
+				"estado":          rep.Estado,
+				"nombre_archivo":  rep.NombreArchivo,
+				"error":           rep.Error,
+				"sesiones":        rep.Sesiones,
+				"finalizado_at":   rep.FinalizadoAt,
+				"reclamado_hasta": nil,
+				"updated_at":      rep.UpdatedAt,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		vigente = true
+		if archivo == nil {
+			return nil
+		}
+		archivo.ReporteID = rep.ID
+		return tx.Create(archivo).Error
+	})
+	return vigente && err == nil, err
+}
+
+func (r *reporteAsistenciaRepository) DeleteTerminadosAntesDe(antes time.Time) (int64, error) {
+	var n int64
+	estados := []string{models.ReporteAsistenciaCompletado, models.ReporteAsistenciaError}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		terminados := tx.Unscoped().Model(&models.ReporteAsistencia{}).Select("id").Where("estado IN ? AND finalizado_at < ?", estados, antes)
+		if err := tx.Where("reporte_id IN (?)", terminados).Delete(&models.ReporteAsistenciaArchivo{}).Error; err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where("estado IN ? AND finalizado_at < ?", estados, antes).Delete(&models.ReporteAsistencia{})
+		n = res.RowsAffected
+		return res.Error
+	})
+	return n, err
+}
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sqlNombrePersonaAlias nombre completo de la persona con el alias indicado en la consulta.
+func sqlNombrePersonaAlias(alias string) string {
+	return fmt.Sprintf(`TRIM(COALESCE(%[1]s.primer_nombre,'') || ' ' || COALESCE(%[1]s.segundo_nombre,'') || ' ' ||
+       COALESCE(%[1]s.primer_apellido,'') || ' ' || COALESCE(%[1]s.segundo_apellido,''))`, alias)
+}
+
+// CasoBienestarNuevoRaw caso de bienestar abierto en el periodo del resumen.
+type CasoBienestarNuevoRaw struct {
+	CasoID        uint
+	PersonaNombre string
+	FichaNumero   string
+	SedeNombre    string
+	Origen        string
+	Motivo        string
+	CreatedAt     time.Time
+}
+
+// PendienteRevisionRaw registro de asistencia de un aprendiz marcado para revisión en una sesión del instructor.
+type PendienteRevisionRaw struct {
+	FichaNumero     string
+	PersonaNombre   string
+	NumeroDocumento string
+	Fecha           time.Time
+}
+
+// TrasladoProximoRaw traslado puntual de sesión en el que participa el instructor.
+type TrasladoProximoRaw struct {
+	FichaNumero             string
+	InstructorOrigenID      uint
+	InstructorOrigenNombre  string
+	InstructorDestinoNombre string
+	FechaOrigen             time.Time
+	FechaDestino            time.Time
+	Motivo                  string
+}
+
+// ResumenSemanalRepository preferencias de suscripción y consultas de los resúmenes semanales por correo.
+type ResumenSemanalRepository interface {
+	ListPreferencias(userID uint) ([]models.ResumenSemanalPreferencia, error)
+	GuardarPreferencia(p *models.ResumenSemanalPreferencia) error
+	// MapDesuscritos usuarios que cancelaron el tipo de resumen.
+	MapDesuscritos(tipo string) (map[uint]bool, error)
+	// CountSesionesTomadasPorFicha días (por instructor) con al menos un ingreso registrado, por ficha.
+	CountSesionesTomadasPorFicha(fichaIDs []uint, fechaInicio, fechaFin string) (map[uint]int, error)
+	ListCasosBienestarNuevos(sedeIDs []uint, desde time.Time) ([]CasoBienestarNuevoRaw, error)
+	ListPendientesRevisionInstructor(instructorID uint) ([]PendienteRevisionRaw, error)
+	// ListTrasladosProximos traslados con fecha de origen o destino entre desde y hasta (inclusive).
+	ListTrasladosProximos(instructorID uint, desde, hasta time.Time) ([]TrasladoProximoRaw, error)
+}
+
+type resumenSemanalRepository struct {
+	db *gorm.DB
+}
+
+func NewResumenSemanalRepository() ResumenSemanalRepository {
+	return &resumenSemanalRepository{db: database.GetDB()}
+}
+
+func (r *resumenSemanalRepository) ListPreferencias(userID uint) ([]models.ResumenSemanalPreferencia, error) {
+	var list []models.ResumenSemanalPreferencia
+	err := r.db.Where("user_id = ?", userID).Find(&list).Error
+	return list, err
+}
+
+func (r *resumenSemanalRepository) GuardarPreferencia(p *models.ResumenSemanalPreferencia) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "tipo"}},
+		DoUpdates: clause.AssignmentColumns([]string{"activo", "updated_at"}),
+	}).Create(p).Error
+}
+
+func (r *resumenSemanalRepository) MapDesuscritos(tipo string) (map[uint]bool, error) {
+	var ids []uint
+	err := r.db.Model(&models.ResumenSemanalPreferencia{}).
+		Where("tipo = ? AND activo = ?", tipo, false).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
+func (r *resumenSemanalRepository) CountSesionesTomadasPorFicha(fichaIDs []uint, fechaInicio, fechaFin string) (map[uint]int, error) {
+	out := make(map[uint]int)
+	if len(fichaIDs) == 0 {
+		return out, nil
+	}
+	type row struct {
+		FichaID uint `gorm:"column:ficha_id"`
+		Total   int  `gorm:"column:total"`
+	}
+	raw := `
+SELECT ifc.ficha_id, COUNT(DISTINCT (a.instructor_ficha_id, a.fecha::date))::int AS total
+FROM asistencias a
+INNER JOIN instructor_fichas_caracterizacion ifc ON ifc.id = a.instructor_ficha_id
+WHERE ifc.ficha_id IN ?
+  AND a.fecha::date BETWEEN ?::date AND ?::date
+  AND a.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM asistencia_aprendices aa
+    WHERE aa.asistencia_id = a.id AND aa.hora_ingreso IS NOT NULL AND aa.deleted_at IS NULL
+  )
+GROUP BY ifc.ficha_id`
+	var rows []row
+	if err := r.db.Raw(raw, fichaIDs, fechaInicio, fechaFin).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, rw := range rows {
+		out[rw.FichaID] = rw.Total
+	}
+	return out, nil
+}
+
+func (r *resumenSemanalRepository) ListCasosBienestarNuevos(sedeIDs []uint, desde time.Time) ([]CasoBienestarNuevoRaw, error) {
+	if len(sedeIDs) == 0 {
+		return nil, nil
+	}
+	type row struct {
+		CasoID        uint      `gorm:"column:caso_id"`
+		PersonaNombre string    `gorm:"column:persona_nombre"`
+		FichaNumero   string    `gorm:"column:ficha_numero"`
+		SedeNombre    string    `gorm:"column:sede_nombre"`
+		Origen        string    `gorm:"column:origen"`
+		Motivo        string    `gorm:"column:motivo"`
+		CreatedAt     time.Time `gorm:"column:created_at"`
+	}
+	raw := `
+SELECT
+  cb.id AS caso_id,
+  ` + sqlNombrePersonaAlias("p") + ` AS persona_nombre,
+  fc.ficha AS ficha_numero,
+  COALESCE(s.nombre,'') AS sede_nombre,
+  cb.origen,
+  cb.motivo,
+  cb.created_at
+FROM casos_bienestar cb
+INNER JOIN personas p ON p.id = cb.persona_id
+INNER JOIN fichas_caracterizacion fc ON fc.id = cb.ficha_id
+LEFT JOIN sedes s ON s.id = fc.sede_id
+WHERE cb.deleted_at IS NULL
+  AND cb.created_at >= ?
+  AND fc.sede_id IN ?
+ORDER BY cb.created_at DESC, cb.id DESC`
+	var rows []row
+	if err := r.db.Raw(raw, desde, sedeIDs).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]CasoBienestarNuevoRaw, len(rows))
+	for i := range rows {
+		out[i] = CasoBienestarNuevoRaw(rows[i])
+	}
+	return out, nil
+}
+
+func (r *resumenSemanalRepository) ListPendientesRevisionInstructor(instructorID uint) ([]PendienteRevisionRaw, error) {
+	type row struct {
+		FichaNumero     string    `gorm:"column:ficha_numero"`
+		PersonaNombre   string    `gorm:"column:persona_nombre"`
+		NumeroDocumento string    `gorm:"column:numero_documento"`
+		Fecha           time.Time `gorm:"column:fecha"`
+	}
+	raw := `
+SELECT
+  fc.ficha AS ficha_numero,
+  ` + sqlNombrePersonaAlias("p") + ` AS persona_nombre,
+  COALESCE(p.numero_documento,'') AS numero_documento,
+  a.fecha
+FROM asistencia_aprendices aa
+INNER JOIN asistencias a ON a.id = aa.asistencia_id
+INNER JOIN instructor_fichas_caracterizacion ifc ON ifc.id = a.instructor_ficha_id
+INNER JOIN fichas_caracterizacion fc ON fc.id = ifc.ficha_id
+INNER JOIN aprendices ap ON ap.id = aa.aprendiz_ficha_id
+INNER JOIN personas p ON p.id = ap.persona_id
+WHERE ifc.instructor_id = ?
+  AND aa.requiere_revision = TRUE
+  AND aa.deleted_at IS NULL
+  AND a.deleted_at IS NULL
+ORDER BY a.fecha DESC, fc.ficha, persona_nombre`
+	var rows []row
+	if err := r.db.Raw(raw, instructorID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]PendienteRevisionRaw, len(rows))
+	for i := range rows {
+		out[i] = PendienteRevisionRaw(rows[i])
+	}
+	return out, nil
+}
+
+func (r *resumenSemanalRepository) ListTrasladosProximos(instructorID uint, desde, hasta time.Time) ([]TrasladoProximoRaw, error) {
+	type row struct {
+		FichaNumero             string    `gorm:"column:ficha_numero"`
+		InstructorOrigenID      uint      `gorm:"column:instructor_origen_id"`
+		InstructorOrigenNombre  string    `gorm:"column:instructor_origen_nombre"`
+		InstructorDestinoNombre string    `gorm:"column:instructor_destino_nombre"`
+		FechaOrigen             time.Time `gorm:"column:fecha_origen"`
+		FechaDestino            time.Time `gorm:"column:fecha_destino"`
+		Motivo                  string    `gorm:"column:motivo"`
+	}
+	raw := `
+SELECT
+  fc.ficha AS ficha_numero,
+  t.instructor_origen_id,
+  ` + sqlNombrePersonaAlias("po") + ` AS instructor_origen_nombre,
+  ` + sqlNombrePersonaAlias("pd") + ` AS instructor_destino_nombre,
+  t.fecha_origen,
+  t.fecha_destino,
+  COALESCE(t.motivo,'') AS motivo
+FROM instructor_ficha_traslado_fechas t
+INNER JOIN fichas_caracterizacion fc ON fc.id = t.ficha_id
+LEFT JOIN instructors io ON io.id = t.instructor_origen_id
+LEFT JOIN personas po ON po.id = io.persona_id
+LEFT JOIN instructors idest ON idest.id = t.instructor_destino_id
+LEFT JOIN personas pd ON pd.id = idest.persona_id
+WHERE t.deleted_at IS NULL
+  AND (t.instructor_origen_id = ? OR t.instructor_destino_id = ?)
+  AND ((t.fecha_origen BETWEEN ? AND ?) OR (t.fecha_destino BETWEEN ? AND ?))
+ORDER BY LEAST(t.fecha_origen, t.fecha_destino), fc.ficha`
+	var rows []row
+	err := r.db.Raw(raw, instructorID, instructorID, desde, hasta, desde, hasta).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TrasladoProximoRaw, len(rows))
+	for i := range rows {
+		out[i] = TrasladoProximoRaw(rows[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// RegistroRiesgoDesercionRaw registro de un aprendiz en una sesión con la hora de inicio de la jornada de su ficha
+// y la suma de peso_riesgo de los tipos de observación marcados.
+type RegistroRiesgoDesercionRaw struct {
+	AprendizID        uint
+	AsistenciaID      uint
+	HoraIngreso       *time.Time
+	Estado            string
+	JornadaHoraInicio string
+	PesoObservaciones int
+}
+
+// RiesgoDesercionRepository consultas complementarias al cálculo de inasistencias para el puntaje de riesgo de deserción.
+type RiesgoDesercionRepository interface {
+	ListRegistrosRiesgo(asistenciaIDs []uint) ([]RegistroRiesgoDesercionRaw, error)
+	// CountRapsNoAprobados juicios NO APROBADO vigentes por aprendiz.
+	CountRapsNoAprobados(aprendizIDs []uint) (map[uint]int, error)
+}
+
+type riesgoDesercionRepository struct {
+	db *gorm.DB
+}
+
+func NewRiesgoDesercionRepository() RiesgoDesercionRepository {
+	return &riesgoDesercionRepository{db: database.GetDB()}
+}
+
+func (r *riesgoDesercionRepository) ListRegistrosRiesgo(asistenciaIDs []uint) ([]RegistroRiesgoDesercionRaw, error) {
+	if len(asistenciaIDs) == 0 {
+		return nil, nil
+	}
+	type row struct {
+		AprendizID        uint       `gorm:"column:aprendiz_id"`
+		AsistenciaID      uint       `gorm:"column:asistencia_id"`
+		HoraIngreso       *time.Time `gorm:"column:hora_ingreso"`
+		Estado            string     `gorm:"column:estado"`
+		JornadaHoraInicio string     `gorm:"column:jornada_hora_inicio"`
+		PesoObservaciones int        `gorm:"column:peso_observaciones"`
+	}
+	raw := `
+SELECT
+  aa.aprendiz_ficha_id AS aprendiz_id,
+  aa.asistencia_id,
+  aa.hora_ingreso,
+  COALESCE(aa.estado, '') AS estado,
+  COALESCE(j.hora_inicio, '') AS jornada_hora_inicio,
+  COALESCE((
+    SELECT SUM(COALESCE(toa.peso_riesgo, 0))
+    FROM asistencia_aprendiz_tipo_observacion aato
+    INNER JOIN tipos_observacion_asistencia toa ON toa.id = aato.tipo_observacion_id AND toa.deleted_at IS NULL
+    WHERE aato.asistencia_aprendiz_id = aa.id
+  ), 0) AS peso_observaciones
+FROM asistencia_aprendices aa
+INNER JOIN aprendices ap ON ap.id = aa.aprendiz_ficha_id
+INNER JOIN fichas_caracterizacion fc ON fc.id = ap.ficha_caracterizacion_id
+LEFT JOIN jornadas j ON j.id = fc.jornada_id
+WHERE aa.asistencia_id IN ?
+  AND aa.deleted_at IS NULL`
+	var rows []row
+	if err := r.db.Raw(raw, asistenciaIDs).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]RegistroRiesgoDesercionRaw, len(rows))
+	for i := range rows {
+		out[i] = RegistroRiesgoDesercionRaw(rows[i])
+	}
+	return out, nil
+}
+
+func (r *riesgoDesercionRepository) CountRapsNoAprobados(aprendizIDs []uint) (map[uint]int, error) {
+	out := make(map[uint]int)
+	if len(aprendizIDs) == 0 {
+		return out, nil
+	}
+	type row struct {
+		AprendizID uint `gorm:"column:aprendiz_id"`
+		Total      int  `gorm:"column:total"`
+	}
+	var rows []row
+	err := r.db.Model(&models.JuicioEvaluativo{}).
+		Select("aprendiz_id, COUNT(*) AS total").
+		Where("aprendiz_id IN ? AND juicio = ?", aprendizIDs, models.JuicioNoAprobado).
+		Group("aprendiz_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		out[r.AprendizID] = r.Total
+	}
+	return out, nil
+}
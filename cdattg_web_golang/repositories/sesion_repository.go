@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// SesionRepository acceso a sesiones (refresh tokens por dispositivo)
+type SesionRepository interface {
+	Create(s *models.Sesion) error
+	FindByID(id uint) (*models.Sesion, error)
+	FindByRefreshHash(hash string) (*models.Sesion, error)
+	FindByRefreshAnteriorHash(hash string) (*models.Sesion, error)
+	Rotar(id uint, hashActual, hashNuevo string, expira, ahora time.Time) (bool, error)
+	ListActivasByUser(userID uint) ([]models.Sesion, error)
+	Revocar(id uint, motivo string) error
+	RevocarTodasByUser(userID uint, motivo string, exceptoID uint) (int64, error)
+	DeleteCaducadas(antesDe time.Time) (int64, error)
+}
+
+type sesionRepository struct {
+	db *gorm.DB
+}
+
+func NewSesionRepository() SesionRepository {
+	return &sesionRepository{db: database.GetDB()}
+}
+
+func (r *sesionRepository) Create(s *models.Sesion) error {
+	return r.db.Create(s).Error
+}
+
+func (r *sesionRepository) FindByID(id uint) (*models.Sesion, error) {
+	var s models.Sesion
+	if err := r.db.First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *sesionRepository) FindByRefreshHash(hash string) (*models.Sesion, error) {
+	var s models.Sesion
+	if err := r.db.Where("refresh_token_hash = ?", hash).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *sesionRepository) FindByRefreshAnteriorHash(hash string) (*models.Sesion, error) {
+	var s models.Sesion
+	if err := r.db.Where("refresh_token_anterior_hash = ?", hash).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Rotar reemplaza el refresh token solo si sigue siendo hashActual y la sesión no está revocada.
+// Devuelve false si otra petición ya lo rotó (carrera) o la sesión fue revocada.
+func (r *sesionRepository) Rotar(id uint, hashActual, hashNuevo string, expira, ahora time.Time) (bool, error) {
+	res := r.db.Model(&models.Sesion{}).
+		Where("id = ? AND refresh_token_hash = ? AND revocada_at IS NULL", id, hashActual).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":          hashNuevo,
+			"refresh_token_anterior_hash": hashActual,
+			"ultimo_uso_at":               ahora,
+			"expira_at":                   expira,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *sesionRepository) ListActivasByUser(userID uint) ([]models.Sesion, error) {
+	var list []models.Sesion
+	err := r.db.Where("user_id = ? AND revocada_at IS NULL AND expira_at > ?", userID, time.Now()).
+		Order("ultimo_uso_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *sesionRepository) Revocar(id uint, motivo string) error {
+	return r.db.Model(&models.Sesion{}).
+		Where("id = ? AND revocada_at IS NULL", id).
+		Updates(map[string]interface{}{"revocada_at": time.Now(), "motivo_revocacion": motivo}).Error
+}
+
+// RevocarTodasByUser revoca las sesiones abiertas del usuario salvo exceptoID (0 = todas).
+func (r *sesionRepository) RevocarTodasByUser(userID uint, motivo string, exceptoID uint) (int64, error) {
+	q := r.db.Model(&models.Sesion{}).Where("user_id = ? AND revocada_at IS NULL", userID)
+	if exceptoID != 0 {
+		q = q.Where("id <> ?", exceptoID)
+	}
+	res := q.Updates(map[string]interface{}{"revocada_at": time.Now(), "motivo_revocacion": motivo})
+	return res.RowsAffected, res.Error
+}
+
+// DeleteCaducadas borra sesiones expiradas o revocadas antes de la fecha indicada.
+func (r *sesionRepository) DeleteCaducadas(antesDe time.Time) (int64, error) {
+	res := r.db.Where("expira_at < ? OR revocada_at < ?", antesDe, antesDe).Delete(&models.Sesion{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// TareaProgramadaRepository acceso a tareas_programadas_ejecuciones
+type TareaProgramadaRepository interface {
+	CreateEjecucion(e *models.TareaProgramadaEjecucion) error
+	UpdateEjecucion(e *models.TareaProgramadaEjecucion) error
+	ListEjecuciones(tarea string, limit int) ([]models.TareaProgramadaEjecucion, error)
+	FindUltimasPorTarea() (map[string]models.TareaProgramadaEjecucion, error)
+}
+
+type tareaProgramadaRepository struct {
+	db *gorm.DB
+}
+
+func NewTareaProgramadaRepository() TareaProgramadaRepository {
+	return &tareaProgramadaRepository{db: database.GetDB()}
+}
+
+func (r *tareaProgramadaRepository) CreateEjecucion(e *models.TareaProgramadaEjecucion) error {
+	return r.db.Create(e).Error
+}
+
+func (r *tareaProgramadaRepository) UpdateEjecucion(e *models.TareaProgramadaEjecucion) error {
+	return r.db.Save(e).Error
+}
+
+func (r *tareaProgramadaRepository) ListEjecuciones(tarea string, limit int) ([]models.TareaProgramadaEjecucion, error) {
+	var list []models.TareaProgramadaEjecucion
+	q := r.db.Order("iniciada_at DESC, id DESC")
+	if tarea != "" {
+		q = q.Where("tarea = ?", tarea)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// FindUltimasPorTarea devuelve la ejecución más reciente de cada tarea, indexada por nombre.
+func (r *tareaProgramadaRepository) FindUltimasPorTarea() (map[string]models.TareaProgramadaEjecucion, error) {
+	var list []models.TareaProgramadaEjecucion
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (tarea) *
+		FROM tareas_programadas_ejecuciones
+		ORDER BY tarea, iniciada_at DESC, id DESC`).Scan(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]models.TareaProgramadaEjecucion, len(list))
+	for _, e := range list {
+		out[e.Tarea] = e
+	}
+	return out, nil
+}
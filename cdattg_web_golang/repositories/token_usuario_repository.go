@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// TokenUsuarioRepository tokens de un solo uso enviados por correo (recuperación de contraseña, verificación de correo)
+type TokenUsuarioRepository interface {
+	// Emitir guarda el token y elimina los pendientes (sin usar) del mismo tipo del usuario.
+	Emitir(t *models.TokenUsuario) error
+	UltimoEmitido(userID uint, tipo string) (*models.TokenUsuario, error)
+	FindByTokenHash(tipo, hash string) (*models.TokenUsuario, error)
+	// Consumir marca el token como usado y aplica los cambios al usuario en una transacción; false (sin cambios)
+	// si otra petición lo consumió antes.
+	Consumir(id uint, at time.Time, userID uint, cambios map[string]interface{}) (bool, error)
+	DeleteVencidos(antesDe time.Time) (int64, error)
+}
+
+type tokenUsuarioRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenUsuarioRepository() TokenUsuarioRepository {
+	return &tokenUsuarioRepository{db: database.GetDB()}
+}
+
+func (r *tokenUsuarioRepository) Emitir(t *models.TokenUsuario) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND tipo = ? AND usado_at IS NULL", t.UserID, t.Tipo).
+			Delete(&models.TokenUsuario{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(t).Error
+	})
+}
+
+func (r *tokenUsuarioRepository) UltimoEmitido(userID uint, tipo string) (*models.TokenUsuario, error) {
+	var t models.TokenUsuario
+	if err := r.db.Where("user_id = ? AND tipo = ?", userID, tipo).Order("created_at DESC").First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tokenUsuarioRepository) FindByTokenHash(tipo, hash string) (*models.TokenUsuario, error) {
+	var t models.TokenUsuario
+	if err := r.db.Where("tipo = ? AND token_hash = ?", tipo, hash).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tokenUsuarioRepository) Consumir(id uint, at time.Time, userID uint, cambios map[string]interface{}) (bool, error) {
+	usado := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.TokenUsuario{}).Where("id = ? AND usado_at IS NULL", id).Update("usado_at", at)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		usado = true
+		return tx.Model(&models.User{}).Where("id = ?", userID).Updates(cambios).Error
+	})
+	return usado && err == nil, err
+}
+
+func (r *tokenUsuarioRepository) DeleteVencidos(antesDe time.Time) (int64, error) {
+	res := r.db.Where("expira_at < ?", antesDe).Delete(&models.TokenUsuario{})
+	return res.RowsAffected, res.Error
+}
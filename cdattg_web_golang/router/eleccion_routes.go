@@ -18,6 +18,7 @@ func registerEleccionRoutes(group *gin.RouterGroup, h *handlers.EleccionHandler)
 	group.GET("/reglas", h.GetReglas)
 	group.GET("/mi-regional", middleware.RequirePermission(objEleccion, permVerEleccion), h.GetMiRegional)
 	group.GET("/regionales/:id/representantes-vigentes", middleware.RequirePermission(objEleccion, permVerEleccion), h.GetRepresentantesVigentes)
+	group.GET("/procesos/:id/recibos/:codigo", middleware.RequirePermission(objEleccion, permVerEleccion), h.VerificarRecibo)
 
 	aprendiz := group.Group("")
 	aprendiz.Use(middleware.RequirePermission(objEleccion, permVotarEleccion))
@@ -47,9 +48,13 @@ func registerEleccionAdminRoutes(admin *gin.RouterGroup, h *handlers.EleccionHan
 	admin.POST("/procesos/:id/abrir-votacion", gestionar, h.AbrirVotacion)
 	admin.POST("/procesos/:id/calcular-resultado", gestionar, h.CalcularResultado)
 	admin.POST("/procesos/:id/registrar-desempate", gestionar, h.RegistrarDesempate)
+	admin.POST("/procesos/:id/recordatorio-voto", gestionar, h.EnviarRecordatorioVoto)
 	admin.GET("/procesos/:id/planchas-admin", gestionar, h.ListPlanchasAdmin)
+	admin.GET("/procesos/:id/eventos", gestionar, h.ListEventosProceso)
 	admin.POST("/planchas/:id/rechazar", gestionar, h.RechazarPlancha)
+	admin.GET("/procesos/:id/participacion", resultados, h.GetParticipacion)
 	admin.GET("/procesos/:id/resultados", resultados, h.GetResultados)
 	admin.GET("/procesos/:id/resultados/export", resultados, h.ExportResultadosCSV)
+	admin.GET("/procesos/:id/acta", resultados, h.DescargarActa)
 	admin.GET("/regionales/:id/historial-representantes", resultados, h.GetHistorialRepresentantes)
 }
@@ -1,7 +1,10 @@
 package router
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
+	"github.com/sena/cdattg-web-golang/config"
 	"github.com/sena/cdattg-web-golang/handlers"
 	"github.com/sena/cdattg-web-golang/middleware"
 )
@@ -28,9 +31,12 @@ const (
 	permTomarAsistencia = "TOMAR ASISTENCIA"
 	permVerAsistencia          = "VER ASISTENCIA"
 	permVerMisInasistencias    = "VER MIS INASISTENCIAS"
+	permRegistrarMiAsistencia  = "REGISTRAR MI ASISTENCIA"
 	permProgramarInstructores  = "PROGRAMAR INSTRUCTORES"
 	permGestionarAprendicesFicha = "GESTIONAR APRENDICES FICHA"
 	permVerMiAgenda            = "VER MI AGENDA"
+	permVerEvaluacion          = "VER EVALUACION"
+	permEvaluarAprendices      = "EVALUAR APRENDICES"
 )
 
 func SetupRouter() *gin.Engine {
@@ -39,6 +45,11 @@ func SetupRouter() *gin.Engine {
 	}
 
 	r := gin.Default()
+	// La IP del cliente (bloqueo de login por IP, auditoría) solo se toma de X-Forwarded-For si viene de un proxy de confianza.
+	if err := r.SetTrustedProxies(config.AppConfig.Server.TrustedProxies); err != nil {
+		log.Printf("SERVER_TRUSTED_PROXIES inválido, no se confía en ningún proxy: %v", err)
+		_ = r.SetTrustedProxies(nil)
+	}
 
 	// Middleware global
 	r.Use(middleware.CORSMiddleware())
@@ -53,7 +64,17 @@ func SetupRouter() *gin.Engine {
 	aprendizHandler := handlers.NewAprendizHandler()
 	instructorHandler := handlers.NewInstructorHandler()
 	asistenciaHandler := handlers.NewAsistenciaHandler()
-	handlers.StartAsistenciaAutoFinalize(asistenciaHandler)
+	excusaHandler := handlers.NewExcusaHandler()
+	casoBienestarHandler := handlers.NewCasoBienestarHandler()
+	riesgoDesercionHandler := handlers.NewRiesgoDesercionHandler()
+	resumenSemanalHandler := handlers.NewResumenSemanalHandler()
+	reporteAsistenciaHandler := handlers.NewReporteAsistenciaHandler()
+	evaluacionHandler := handlers.NewEvaluacionHandler()
+	handlers.RegisterTareasProgramadas(asistenciaHandler)
+	notificacionHandler := handlers.NewNotificacionHandler()
+	importacionHandler := handlers.NewImportacionHandler()
+	tareaProgramadaHandler := handlers.NewTareaProgramadaHandler()
+	correoSalienteHandler := handlers.NewCorreoSalienteHandler()
 	adminHandler := handlers.NewAdminHandler()
 	permisosHandler := handlers.NewPermisosHandler()
 	statsHandler := handlers.NewStatsHandler()
@@ -74,23 +95,45 @@ func SetupRouter() *gin.Engine {
 	diaSinFormacionHandler := handlers.NewDiaSinFormacionSedeHandler()
 	configAsistenciaHandler := handlers.NewConfiguracionAsistenciaHandler()
 	eleccionHandler := handlers.NewEleccionHandler()
+	auditoriaHandler := handlers.NewAuditoriaHandler()
 
 	// Rutas públicas
 	api := r.Group("/api")
 	{
-		// WebSocket dashboard asistencia (token por query; solo superadmin; sin AuthMiddleware)
+		// WebSocket de asistencia (token por query; sin AuthMiddleware): dashboard filtrado por alcance y lista en vivo
+		// de una sesión para su instructor.
 		api.GET("/asistencias/dashboard/ws", handlers.DashboardWebSocket)
+		api.GET("/asistencias/:id/ws", asistenciaHandler.SesionWebSocket)
+		// Notificaciones en la aplicación del usuario autenticado (token por query)
+		api.GET("/notificaciones/ws", handlers.NotificacionesWebSocket)
+
+		// Feed iCalendar de suscripción (el token secreto del enlace reemplaza la autenticación)
+		api.GET("/calendario/:token/agenda.ics", agendaHandler.GetAgendaSuscripcionICS)
+		// Verificación pública de actas de escrutinio por el hash impreso en el PDF
+		api.GET("/elecciones/actas/:hash", eleccionHandler.VerificarActa)
 
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
 			auth.GET("/me", middleware.AuthMiddleware(), authHandler.GetCurrentUser)
 			auth.POST("/change-password", middleware.AuthMiddleware(), authHandler.ChangePassword)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", middleware.AuthMiddleware(), authHandler.Logout)
+			auth.POST("/logout-all", middleware.AuthMiddleware(), authHandler.LogoutAll)
+			auth.GET("/sesiones", middleware.AuthMiddleware(), authHandler.ListSesiones)
+			auth.DELETE("/sesiones/:id", middleware.AuthMiddleware(), authHandler.CerrarSesion)
+			auth.GET("/accesos", middleware.AuthMiddleware(), authHandler.ListAccesos)
+			auth.POST("/recuperar-password", authHandler.RecuperarPassword)
+			auth.POST("/restablecer-password", authHandler.RestablecerPassword)
+			auth.POST("/verificar-email/solicitar", middleware.AuthMiddleware(), authHandler.SolicitarVerificacionEmail)
+			auth.POST("/verificar-email", authHandler.VerificarEmail)
+			auth.GET("/resumenes-semanales", middleware.AuthMiddleware(), resumenSemanalHandler.GetPreferencias)
+			auth.PUT("/resumenes-semanales", middleware.AuthMiddleware(), resumenSemanalHandler.ActualizarPreferencia)
 		}
 
 		// Rutas protegidas (auth + Casbin por permiso)
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(), middleware.Auditoria())
 		{
 			personas := protected.Group("/personas")
 			{
@@ -150,6 +193,7 @@ func SetupRouter() *gin.Engine {
 				fichas.DELETE("/:id", middleware.RequirePermission("ficha", "ELIMINAR FICHA"), fichaHandler.Delete)
 				fichas.GET("/:id/instructores", middleware.RequirePermissionListInstructoresFicha(), fichaHandler.ListInstructores)
 				fichas.GET("/:id/agenda", middleware.RequirePermission("ficha", permProgramarInstructores), agendaHandler.GetAgendaFicha)
+				fichas.GET("/:id/agenda.ics", middleware.RequirePermission("ficha", permProgramarInstructores), agendaHandler.GetAgendaFichaICS)
 				fichas.POST("/:id/instructores", middleware.RequirePermission("ficha", permProgramarInstructores), fichaHandler.AsignarInstructores)
 				fichas.POST("/:id/instructores/traslado-dia", middleware.RequirePermission("ficha", permProgramarInstructores), fichaHandler.TrasladarDiaInstructor)
 				fichas.DELETE("/:id/instructores/:instructorId", middleware.RequirePermission("ficha", permProgramarInstructores), fichaHandler.DesasignarInstructor)
@@ -157,6 +201,9 @@ func SetupRouter() *gin.Engine {
 				fichas.POST(routeIDAprendices, middleware.RequirePermission("ficha", permGestionarAprendicesFicha), fichaHandler.AsignarAprendices)
 				fichas.POST(routeIDAprendices+"/desasignar", middleware.RequirePermission("ficha", permGestionarAprendicesFicha), fichaHandler.DesasignarAprendices)
 				fichas.POST(routeIDAprendices+"/ocultar-asistencia", middleware.RequirePermission("ficha", permGestionarAprendicesFicha), fichaHandler.OcultarAprendicesEnAsistencia)
+				fichas.GET("/evaluacion/alertas", middleware.RequirePermission("ficha", permVerEvaluacion), evaluacionHandler.ListAlertasCierre)
+				fichas.GET("/:id/evaluacion", middleware.RequirePermission("ficha", permVerEvaluacion), evaluacionHandler.GetMatriz)
+				fichas.PUT("/:id/evaluacion/juicios", middleware.RequirePermission("ficha", permEvaluarAprendices), evaluacionHandler.RegistrarJuicios)
 			}
 
 			instructores := protected.Group("/instructores")
@@ -165,6 +212,10 @@ func SetupRouter() *gin.Engine {
 
 			instructorSelf := protected.Group("/instructor")
 			instructorSelf.GET("/agenda", middleware.RequirePermission("asistencia", permVerMiAgenda), agendaHandler.GetMiAgenda)
+			instructorSelf.GET("/agenda.ics", middleware.RequirePermission("asistencia", permVerMiAgenda), agendaHandler.GetMiAgendaICS)
+			instructorSelf.GET("/agenda/suscripcion", middleware.RequirePermission("asistencia", permVerMiAgenda), agendaHandler.GetSuscripcion)
+			instructorSelf.POST("/agenda/suscripcion", middleware.RequirePermission("asistencia", permVerMiAgenda), agendaHandler.GenerarSuscripcion)
+			instructorSelf.DELETE("/agenda/suscripcion", middleware.RequirePermission("asistencia", permVerMiAgenda), agendaHandler.RevocarSuscripcion)
 			instructores.GET("/imports", middleware.RequirePermission("instructor", permCrearInstructor), instructorHandler.ListInstructorImports)
 			instructores.POST(routeImport, middleware.RequirePermission("instructor", permCrearInstructor), instructorHandler.ImportInstructores)
 			instructores.GET("/:id", middleware.RequirePermission("ficha", permVerFichas), instructorHandler.GetByID)
@@ -178,6 +229,12 @@ func SetupRouter() *gin.Engine {
 			asistencias.GET("/dashboard/casos-bienestar", middleware.RequireSuperAdminOrBienestar(), asistenciaHandler.GetCasosBienestar)
 			asistencias.GET("/dashboard/casos-bienestar/ficha/:fichaNumero/aprendiz/:aprendizId/detalle", middleware.RequireSuperAdminOrBienestar(), asistenciaHandler.GetDetalleInasistenciasAprendiz)
 			asistencias.GET("/mis-inasistencias", middleware.RequirePermission("asistencia", permVerMisInasistencias), asistenciaHandler.GetMisInasistencias)
+			// Excusas: el aprendiz las presenta; el servicio limita la revisión al instructor de la ficha, bienestar o superadmin.
+			asistencias.POST("/excusas", middleware.RequirePermission("asistencia", permVerMisInasistencias), excusaHandler.Presentar)
+			asistencias.GET("/mis-excusas", middleware.RequirePermission("asistencia", permVerMisInasistencias), excusaHandler.ListMias)
+			asistencias.GET("/excusas", excusaHandler.ListParaRevision)
+			asistencias.PUT("/excusas/:id/revision", excusaHandler.Revisar)
+			asistencias.GET("/excusas/:id/archivo", excusaHandler.Archivo)
 			asistencias.GET("/dashboard/pendientes-revision-instructor", middleware.RequireSuperAdminOrBienestar(), asistenciaHandler.ListPendientesRevisionAdmin)
 			asistencias.GET("/dashboard/sesiones-sin-asistencia-tomada", middleware.RequireSuperAdminAdminOrCoordinator(), asistenciaHandler.GetSesionesSinAsistenciaTomada)
 			// Entrar a tomar asistencia: solo requiere estar autenticado; el servicio valida que el usuario sea instructor asignado a la ficha.
@@ -186,12 +243,18 @@ func SetupRouter() *gin.Engine {
 			asistencias.POST("", middleware.RequirePermission("asistencia", permTomarAsistencia), asistenciaHandler.CreateSesion)
 			asistencias.GET("/instructor-ficha/:instructorFichaId", middleware.RequirePermission("asistencia", permVerAsistencia), asistenciaHandler.ListByInstructorFicha)
 			asistencias.GET("/ficha/:fichaId", middleware.RequirePermissionListAsistenciasPorFicha(), asistenciaHandler.ListByFichaAndFechas)
+			asistencias.GET("/ficha/:fichaId/reportes", middleware.RequirePermissionListAsistenciasPorFicha(), reporteAsistenciaHandler.List)
+			asistencias.GET("/ficha/:fichaId/reportes/:reporteId", middleware.RequirePermissionListAsistenciasPorFicha(), reporteAsistenciaHandler.Estado)
+			asistencias.GET("/ficha/:fichaId/reportes/:reporteId/archivo", middleware.RequirePermissionListAsistenciasPorFicha(), reporteAsistenciaHandler.Descargar)
 			// Pendientes de revisión:
 			// ya se valida dentro del handler que el usuario autenticado
 			// esté vinculado como instructor. No se requiere permiso Casbin adicional.
 			asistencias.GET("/pendientes-revision", asistenciaHandler.ListPendientesRevision)
 			asistencias.POST("/ingreso", middleware.RequirePermission("asistencia", permTomarAsistencia), asistenciaHandler.RegistrarIngreso)
 			asistencias.POST("/ingreso-por-documento", middleware.RequirePermission("asistencia", permTomarAsistencia), asistenciaHandler.RegistrarIngresoPorDocumento)
+			asistencias.GET("/:id/codigo-qr", middleware.RequirePermission("asistencia", permTomarAsistencia), asistenciaHandler.CodigoQRSesion)
+			// Autorregistro del aprendiz con el código rotativo que muestra el instructor en la sesión abierta.
+			asistencias.POST("/autorregistro", middleware.RequirePermission("asistencia", permRegistrarMiAsistencia), asistenciaHandler.RegistrarPorCodigoQR)
 			asistencias.PUT("/:id/observaciones-sesion", middleware.RequirePermission("asistencia", permTomarAsistencia), asistenciaHandler.ActualizarObservacionesSesion)
 			asistencias.PUT("/aprendiz/:asistenciaAprendizId/salida", middleware.RequirePermission("asistencia", permTomarAsistencia), asistenciaHandler.RegistrarSalida)
 			asistencias.DELETE("/aprendiz/:asistenciaAprendizId", middleware.RequireSuperAdminOrAdmin(), asistenciaHandler.EliminarRegistroAprendiz)
@@ -212,6 +275,13 @@ func SetupRouter() *gin.Engine {
 			admin.POST("/sync-aprendiz-permissions", middleware.RequireSuperAdminOrAdmin(), adminHandler.SyncAprendizPermissions)
 			admin.POST("/sync-agenda-permissions", middleware.RequireSuperAdminOrAdmin(), adminHandler.SyncAgendaPermissions)
 			// sync-inventario-permissions desactivado (módulo inventario no en uso)
+			admin.GET("/tareas", middleware.RequireSuperAdminOrAdmin(), tareaProgramadaHandler.List)
+			admin.GET("/tareas/:nombre/ejecuciones", middleware.RequireSuperAdminOrAdmin(), tareaProgramadaHandler.ListEjecuciones)
+			admin.POST("/tareas/:nombre/ejecutar", middleware.RequireSuperAdminOrAdmin(), tareaProgramadaHandler.Ejecutar)
+			admin.GET("/correos", middleware.RequireSuperAdminOrAdmin(), correoSalienteHandler.List)
+			admin.GET("/correos/:id", middleware.RequireSuperAdminOrAdmin(), correoSalienteHandler.GetByID)
+			admin.GET("/correos/:id/adjuntos/:adjuntoId", middleware.RequireSuperAdminOrAdmin(), correoSalienteHandler.DescargarAdjunto)
+			admin.POST("/correos/:id/reintentar", middleware.RequireSuperAdminOrAdmin(), correoSalienteHandler.Reintentar)
 
 			administracion := protected.Group("/administracion")
 			administracion.Use(middleware.RequireSuperAdminAdminOrCoordinator())
@@ -245,7 +315,11 @@ func SetupRouter() *gin.Engine {
 				usuarios.POST("/:id/permisos", permisosHandler.AsignarPermiso)
 				usuarios.DELETE("/:id/permisos/:obj/:act", permisosHandler.QuitarPermiso)
 				usuarios.PATCH("/:id/estado", permisosHandler.ToggleEstado)
+				usuarios.POST("/:id/desbloquear-login", authHandler.DesbloquearCuenta)
 			}
+			// Bitácora de auditoría (solo superadmin)
+			protected.GET("/auditoria", middleware.RequireSuperAdmin(), auditoriaHandler.List)
+
 			usuariosRoles := protected.Group(routeUsuarios)
 			usuariosRoles.Use(middleware.RequireSuperAdmin())
 			{
@@ -258,6 +332,27 @@ func SetupRouter() *gin.Engine {
 				usuariosRegionales.PUT("/:id/regionales", permisosHandler.SetUsuarioRegionales)
 			}
 
+			// Centro de notificaciones: cada usuario solo ve y marca las suyas
+			notificaciones := protected.Group("/notificaciones")
+			{
+				notificaciones.GET("", notificacionHandler.List)
+				notificaciones.GET("/no-leidas", notificacionHandler.CountNoLeidas)
+				notificaciones.PUT("/leidas", notificacionHandler.MarcarTodasLeidas)
+				notificaciones.PUT("/:id/leida", notificacionHandler.MarcarLeida)
+				notificaciones.GET("/preferencias", notificacionHandler.GetPreferencias)
+				notificaciones.PUT("/preferencias", notificacionHandler.ActualizarPreferencia)
+			}
+
+			// Importaciones de Excel en segundo plano: cada usuario consulta las suyas (el super administrador, todas)
+			importaciones := protected.Group("/importaciones")
+			{
+				importaciones.GET("", importacionHandler.List)
+				importaciones.GET("/:id", importacionHandler.GetByID)
+				importaciones.GET("/:id/eventos", importacionHandler.Eventos)
+				importaciones.GET("/:id/incidencias", importacionHandler.DescargarIncidencias)
+				importaciones.POST("/:id/reanudar", importacionHandler.Reanudar)
+			}
+
 			stats := protected.Group("/stats")
 			stats.Use(middleware.RequireDashboardStats())
 			{
@@ -265,6 +360,26 @@ func SetupRouter() *gin.Engine {
 				stats.GET("/asistencia-analisis", statsHandler.GetAsistenciaAnalisis)
 			}
 
+			// Casos de seguimiento de bienestar (SUPER ADMINISTRADOR y BIENESTAR AL APRENDIZ)
+			bienestar := protected.Group("/bienestar")
+			bienestar.Use(middleware.RequireSuperAdminOrBienestar())
+			{
+				bienestar.GET("/casos", casoBienestarHandler.List)
+				bienestar.POST("/casos", casoBienestarHandler.Create)
+				bienestar.POST("/casos/desde-calculo", casoBienestarHandler.AbrirDesdeCalculo)
+				bienestar.GET("/casos/:id", casoBienestarHandler.Get)
+				bienestar.PUT("/casos/:id", casoBienestarHandler.Update)
+				bienestar.POST("/casos/:id/intervenciones", casoBienestarHandler.CreateIntervencion)
+				bienestar.POST("/casos/:id/adjuntos", casoBienestarHandler.UploadAdjunto)
+				bienestar.GET("/casos/:id/adjuntos/:adjuntoId", casoBienestarHandler.DownloadAdjunto)
+				bienestar.POST("/casos/:id/recordatorios", casoBienestarHandler.CreateRecordatorio)
+				bienestar.PUT("/casos/:id/recordatorios/:recordatorioId/completar", casoBienestarHandler.CompletarRecordatorio)
+				bienestar.GET("/mis-recordatorios", casoBienestarHandler.MisRecordatorios)
+				bienestar.GET("/aprendices/:aprendizId/casos", casoBienestarHandler.HistorialAprendiz)
+				bienestar.GET("/profesionales", casoBienestarHandler.Profesionales)
+				bienestar.GET("/riesgo-desercion", riesgoDesercionHandler.List)
+			}
+
 			elecciones := protected.Group("/elecciones")
 			registerEleccionRoutes(elecciones, eleccionHandler)
 
@@ -302,6 +417,7 @@ func SetupRouter() *gin.Engine {
 				infraestructura.POST(routeAmbientes, ambienteHandler.Create)
 				infraestructura.PUT(routeAmbientes+"/:id", ambienteHandler.Update)
 				infraestructura.DELETE(routeAmbientes+"/:id", ambienteHandler.Delete)
+				infraestructura.GET(routeAmbientes+"/:id/ocupacion", ambienteHandler.Ocupacion)
 			}
 		}
 	}
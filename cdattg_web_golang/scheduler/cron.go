@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule calcula la siguiente activación estrictamente posterior a t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// everySchedule intervalo fijo (@every 5m).
+type everySchedule struct {
+	intervalo time.Duration
+}
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.intervalo).Truncate(time.Second)
+}
+
+// cronSchedule expresión estándar de 5 campos: minuto hora día-mes mes día-semana.
+type cronSchedule struct {
+	minuto, hora, diaMes, mes, diaSemana uint64
+	diaMesLibre, diaSemanaLibre          bool
+}
+
+type cronCampo struct {
+	nombre   string
+	min, max int
+}
+
+var cronCampos = []cronCampo{
+	{"minuto", 0, 59},
+	{"hora", 0, 23},
+	{"día del mes", 1, 31},
+	{"mes", 1, 12},
+	{"día de la semana", 0, 7},
+}
+
+var cronDescriptores = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// Parse interpreta una expresión cron de 5 campos (admite *, listas, rangos y pasos),
+// los descriptores @hourly/@daily/@weekly/@monthly y @every <duración>.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("intervalo @every inválido: %q", expr)
+		}
+		return everySchedule{intervalo: d}, nil
+	}
+	if alias, ok := cronDescriptores[expr]; ok {
+		expr = alias
+	}
+	campos := strings.Fields(expr)
+	if len(campos) != len(cronCampos) {
+		return nil, fmt.Errorf("expresión cron inválida %q: se esperaban 5 campos", expr)
+	}
+	bits := make([]uint64, len(campos))
+	for i, c := range campos {
+		b, err := parseCampo(c, cronCampos[i])
+		if err != nil {
+			return nil, fmt.Errorf("expresión cron inválida %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+	diaSemana := bits[4]
+	if diaSemana&(1<<7) != 0 {
+		diaSemana |= 1 // 7 también es domingo
+	}
+	return &cronSchedule{
+		minuto:         bits[0],
+		hora:           bits[1],
+		diaMes:         bits[2],
+		mes:            bits[3],
+		diaSemana:      diaSemana,
+		diaMesLibre:    campos[2] == "*",
+		diaSemanaLibre: campos[4] == "*",
+	}, nil
+}
+
+func parseCampo(campo string, def cronCampo) (uint64, error) {
+	var bits uint64
+	for _, parte := range strings.Split(campo, ",") {
+		b, err := parseRango(parte, def)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parseRango(parte string, def cronCampo) (uint64, error) {
+	rango, paso := parte, 1
+	if i := strings.Index(parte, "/"); i >= 0 {
+		p, err := strconv.Atoi(parte[i+1:])
+		if err != nil || p <= 0 {
+			return 0, fmt.Errorf("paso inválido en %s: %q", def.nombre, parte)
+		}
+		rango, paso = parte[:i], p
+	}
+	desde, hasta := def.min, def.max
+	switch {
+	case rango == "*":
+	case strings.Contains(rango, "-"):
+		lim := strings.SplitN(rango, "-", 2)
+		var err1, err2 error
+		desde, err1 = strconv.Atoi(lim[0])
+		hasta, err2 = strconv.Atoi(lim[1])
+		if err1 != nil || err2 != nil {
+			return 0, fmt.Errorf("rango inválido en %s: %q", def.nombre, parte)
+		}
+	default:
+		v, err := strconv.Atoi(rango)
+		if err != nil {
+			return 0, fmt.Errorf("valor inválido en %s: %q", def.nombre, parte)
+		}
+		desde, hasta = v, v
+		if paso > 1 {
+			hasta = def.max
+		}
+	}
+	if desde < def.min || hasta > def.max || desde > hasta {
+		return 0, fmt.Errorf("%s fuera de rango (%d-%d): %q", def.nombre, def.min, def.max, parte)
+	}
+	var bits uint64
+	for v := desde; v <= hasta; v += paso {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+// cronMaxAniosBusqueda evita bucles infinitos con expresiones imposibles (p. ej. 30 de febrero).
+const cronMaxAniosBusqueda = 5
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limite := t.AddDate(cronMaxAniosBusqueda, 0, 0)
+	for t.Before(limite) {
+		if s.mes&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.coincideDia(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hora&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minuto&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// coincideDia aplica la semántica cron: si ambos campos de día están restringidos basta con que coincida uno.
+func (s *cronSchedule) coincideDia(t time.Time) bool {
+	dm := s.diaMes&(1<<uint(t.Day())) != 0
+	ds := s.diaSemana&(1<<uint(t.Weekday())) != 0
+	if s.diaMesLibre || s.diaSemanaLibre {
+		return dm && ds
+	}
+	return dm || ds
+}
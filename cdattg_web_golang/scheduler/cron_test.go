@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) Schedule {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	return s
+}
+
+func TestParseExpresionesInvalidas(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+		"@every 10ms",
+		"@yearly",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q): esperaba error", expr)
+		}
+	}
+}
+
+func TestNextCadaQuinceMinutos(t *testing.T) {
+	s := mustParse(t, "*/15 * * * *")
+	loc := time.UTC
+	got := s.Next(time.Date(2026, 3, 10, 8, 7, 30, 0, loc))
+	want := time.Date(2026, 3, 10, 8, 15, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	got = s.Next(want)
+	want = time.Date(2026, 3, 10, 8, 30, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNextDiasHabilesSaltaFinDeSemana(t *testing.T) {
+	s := mustParse(t, "0 7 * * 1-5")
+	loc := time.UTC
+	// Viernes 13/03/2026 a las 08:00 -> lunes 16/03/2026 07:00
+	got := s.Next(time.Date(2026, 3, 13, 8, 0, 0, 0, loc))
+	want := time.Date(2026, 3, 16, 7, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNextDomingoComoSiete(t *testing.T) {
+	s := mustParse(t, "30 18 * * 7")
+	got := s.Next(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	if got.Weekday() != time.Sunday || got.Hour() != 18 || got.Minute() != 30 {
+		t.Fatalf("esperaba domingo 18:30, got %v", got)
+	}
+}
+
+func TestNextDiaMesODiaSemana(t *testing.T) {
+	// Con ambos campos restringidos basta que coincida uno: día 1 o cualquier lunes.
+	s := mustParse(t, "0 0 1 * 1")
+	got := s.Next(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)) // lunes 2/03
+	want := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)         // lunes siguiente
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNextListasYDescriptores(t *testing.T) {
+	s := mustParse(t, "0 6,12,18 * * *")
+	got := s.Next(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	if got.Hour() != 18 {
+		t.Fatalf("esperaba 18:00, got %v", got)
+	}
+	d := mustParse(t, "@daily")
+	got = d.Next(time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC))
+	if !got.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("@daily: got %v", got)
+	}
+}
+
+func TestNextFechaImposible(t *testing.T) {
+	s := mustParse(t, "0 0 30 2 *")
+	if got := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("30 de febrero no existe; got %v", got)
+	}
+}
+
+func TestNextEvery(t *testing.T) {
+	s := mustParse(t, "@every 5m")
+	base := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	if got := s.Next(base); !got.Equal(base.Add(5 * time.Minute)) {
+		t.Fatalf("got %v", got)
+	}
+}
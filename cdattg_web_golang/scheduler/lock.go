@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Prefijos de las llaves de advisory lock en Postgres (hashtext(prefijo || nombre)).
+const (
+	lockKeyLider = "cdattg:scheduler:lider"
+	lockKeyTarea = "cdattg:scheduler:tarea:"
+)
+
+// advisoryLock mantiene una conexión dedicada: los advisory locks de sesión de Postgres
+// pertenecen a la conexión que los tomó, así que no puede devolverse al pool mientras se retiene.
+type advisoryLock struct {
+	conn *sql.Conn
+	key  string
+}
+
+// tryAdvisoryLock intenta tomar el lock sin bloquear. Devuelve nil, nil si otra sesión ya lo tiene.
+func tryAdvisoryLock(ctx context.Context, db *gorm.DB, key string) (*advisoryLock, error) {
+	if db == nil {
+		return nil, errors.New("base de datos no inicializada")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", key).Scan(&ok); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !ok {
+		_ = conn.Close()
+		return nil, nil
+	}
+	return &advisoryLock{conn: conn, key: key}, nil
+}
+
+// alive verifica que la conexión (y por tanto el lock) siga viva.
+func (l *advisoryLock) alive(ctx context.Context) bool {
+	return l.conn.PingContext(ctx) == nil
+}
+
+// release libera el lock y devuelve la conexión al pool.
+func (l *advisoryLock) release() {
+	_, _ = l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", l.key)
+	_ = l.conn.Close()
+}
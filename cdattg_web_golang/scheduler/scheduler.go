@@ -0,0 +1,381 @@
+// Package scheduler ejecuta tareas periódicas en segundo plano (expresiones cron o @every).
+// Con varias réplicas, solo la instancia que retiene el advisory lock de líder en Postgres
+// corre las ejecuciones programadas; cada ejecución además toma un lock por tarea para que
+// un disparo manual no se solape con la corrida programada. Todas las ejecuciones quedan en
+// tareas_programadas_ejecuciones.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+// intervaloLiderazgo cada cuánto se intenta tomar (o se verifica) el lock de líder.
+const intervaloLiderazgo = 15 * time.Second
+
+var (
+	ErrTareaNoEncontrada = errors.New("tarea programada no encontrada")
+	ErrTareaEnCurso      = errors.New("la tarea ya se está ejecutando")
+	ErrSinBaseDatos      = errors.New("base de datos no inicializada")
+)
+
+// JobFunc cuerpo de una tarea. El error devuelto queda registrado en el historial.
+type JobFunc func(ctx context.Context) error
+
+type tarea struct {
+	nombre      string
+	descripcion string
+	expresion   string
+	schedule    Schedule
+	run         JobFunc
+	proxima     time.Time
+	enCurso     bool
+	resched     chan struct{}
+}
+
+// TareaInfo estado de una tarea registrada, para listados de administración.
+type TareaInfo struct {
+	Nombre           string
+	Descripcion      string
+	Expresion        string
+	ProximaEjecucion *time.Time
+	EnCurso          bool
+}
+
+// Scheduler planificador de tareas con elección de líder en Postgres. ctx vive mientras el planificador corre
+// (nil detenido); Stop lo cancela y con él las ejecuciones en curso.
+type Scheduler struct {
+	mu        sync.Mutex
+	tareas    map[string]*tarea
+	lider     *advisoryLock
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	instancia string
+}
+
+var globalScheduler = New()
+
+// Default devuelve el planificador global de la aplicación.
+func Default() *Scheduler {
+	return globalScheduler
+}
+
+// New crea un planificador vacío y detenido.
+func New() *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		tareas:    make(map[string]*tarea),
+		instancia: fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}
+}
+
+// Register añade (o reemplaza) una tarea. Si el planificador ya está corriendo, la tarea se programa de inmediato.
+func (s *Scheduler) Register(nombre, descripcion, expresion string, run JobFunc) error {
+	sched, err := Parse(expresion)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.tareas[nombre]; ok {
+		prev.descripcion, prev.expresion, prev.schedule, prev.run = descripcion, expresion, sched, run
+		s.notificarCambio(prev)
+		return nil
+	}
+	t := &tarea{
+		nombre:      nombre,
+		descripcion: descripcion,
+		expresion:   expresion,
+		schedule:    sched,
+		run:         run,
+		resched:     make(chan struct{}, 1),
+	}
+	s.tareas[nombre] = t
+	if s.ctx != nil {
+		s.lanzar(t)
+	}
+	return nil
+}
+
+// Reschedule cambia la expresión de una tarea registrada (p. ej. al editar la configuración).
+func (s *Scheduler) Reschedule(nombre, expresion string) error {
+	sched, err := Parse(expresion)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tareas[nombre]
+	if !ok {
+		return ErrTareaNoEncontrada
+	}
+	t.expresion, t.schedule = expresion, sched
+	s.notificarCambio(t)
+	return nil
+}
+
+func (s *Scheduler) notificarCambio(t *tarea) {
+	select {
+	case t.resched <- struct{}{}:
+	default:
+	}
+}
+
+// Start inicia el bucle de liderazgo y los temporizadores de cada tarea. Las ejecuciones programadas reciben un
+// contexto derivado de ctx que se cancela al cancelarse ctx o al llamar Stop. Sin BD no hace nada.
+func (s *Scheduler) Start(ctx context.Context) {
+	if database.GetDB() == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go s.mantenerLiderazgo(s.ctx)
+	for _, t := range s.tareas {
+		s.lanzar(t)
+	}
+	log.Printf("[scheduler] iniciado en %s con %d tareas", s.instancia, len(s.tareas))
+}
+
+// Stop detiene los temporizadores, cancela el contexto de las ejecuciones en curso, espera a que terminen y libera
+// el liderazgo.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.ctx == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.cancel()
+	s.ctx, s.cancel = nil, nil
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// lanzar debe llamarse con s.mu tomado.
+func (s *Scheduler) lanzar(t *tarea) {
+	s.wg.Add(1)
+	go s.bucleTarea(s.ctx, t)
+}
+
+func (s *Scheduler) bucleTarea(ctx context.Context, t *tarea) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		proxima := t.schedule.Next(time.Now().In(utils.AppLocation()))
+		t.proxima = proxima
+		s.mu.Unlock()
+		if proxima.IsZero() {
+			log.Printf("[scheduler] %s: la expresión %q no tiene próximas ejecuciones", t.nombre, t.expresion)
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.resched:
+				continue
+			}
+		}
+		timer := time.NewTimer(time.Until(proxima))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-t.resched:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+		if !s.EsLider() {
+			continue
+		}
+		if _, err := s.ejecutar(ctx, t, models.TareaOrigenProgramada, nil); err != nil && !errors.Is(err, ErrTareaEnCurso) {
+			log.Printf("[scheduler] %s: %v", t.nombre, err)
+		}
+	}
+}
+
+func (s *Scheduler) mantenerLiderazgo(ctx context.Context) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(intervaloLiderazgo)
+	defer ticker.Stop()
+	for {
+		s.revisarLiderazgo()
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			if s.lider != nil {
+				s.lider.release()
+				s.lider = nil
+			}
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) revisarLiderazgo() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.mu.Lock()
+	actual := s.lider
+	s.mu.Unlock()
+	if actual != nil {
+		if actual.alive(ctx) {
+			return
+		}
+		log.Printf("[scheduler] %s perdió la conexión del lock de líder", s.instancia)
+		actual.release()
+		s.mu.Lock()
+		s.lider = nil
+		s.mu.Unlock()
+	}
+	lock, err := tryAdvisoryLock(ctx, database.GetDB(), lockKeyLider)
+	if err != nil {
+		log.Printf("[scheduler] error intentando tomar liderazgo: %v", err)
+		return
+	}
+	if lock == nil {
+		return
+	}
+	s.mu.Lock()
+	s.lider = lock
+	s.mu.Unlock()
+	log.Printf("[scheduler] %s es ahora la instancia líder", s.instancia)
+}
+
+// EjecutarAlArranque corre una tarea una vez en segundo plano al iniciar esta instancia, sin esperar a su
+// próxima ejecución programada. Requiere el planificador iniciado.
+func (s *Scheduler) EjecutarAlArranque(nombre string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tareas[nombre]
+	if !ok || s.ctx == nil {
+		return
+	}
+	ctx := s.ctx
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if _, err := s.ejecutar(ctx, t, models.TareaOrigenArranque, nil); err != nil && !errors.Is(err, ErrTareaEnCurso) {
+			log.Printf("[scheduler] %s al arranque: %v", t.nombre, err)
+		}
+	}()
+}
+
+// Trigger ejecuta una tarea de inmediato en esta instancia (disparo manual) y devuelve la ejecución registrada.
+func (s *Scheduler) Trigger(ctx context.Context, nombre string, userID *uint) (*models.TareaProgramadaEjecucion, error) {
+	s.mu.Lock()
+	t, ok := s.tareas[nombre]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrTareaNoEncontrada
+	}
+	return s.ejecutar(ctx, t, models.TareaOrigenManual, userID)
+}
+
+func (s *Scheduler) ejecutar(ctx context.Context, t *tarea, origen string, userID *uint) (*models.TareaProgramadaEjecucion, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, ErrSinBaseDatos
+	}
+	lock, err := tryAdvisoryLock(ctx, db, lockKeyTarea+t.nombre)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, ErrTareaEnCurso
+	}
+	defer lock.release()
+
+	s.marcarEnCurso(t, true)
+	defer s.marcarEnCurso(t, false)
+
+	repo := repositories.NewTareaProgramadaRepository()
+	ej := &models.TareaProgramadaEjecucion{
+		Tarea:      t.nombre,
+		Origen:     origen,
+		Instancia:  s.instancia,
+		UserID:     userID,
+		IniciadaAt: time.Now(),
+	}
+	if errCreate := repo.CreateEjecucion(ej); errCreate != nil {
+		log.Printf("[scheduler] %s: no se pudo registrar inicio: %v", t.nombre, errCreate)
+	}
+	s.mu.Lock()
+	run := t.run
+	s.mu.Unlock()
+	errRun := ejecutarSeguro(ctx, run)
+	fin := time.Now()
+	ej.FinalizadaAt = &fin
+	ej.DuracionMs = fin.Sub(ej.IniciadaAt).Milliseconds()
+	ej.Exitosa = errRun == nil
+	if errRun != nil {
+		msg := errRun.Error()
+		ej.Error = &msg
+		log.Printf("[scheduler] %s falló tras %d ms: %v", t.nombre, ej.DuracionMs, errRun)
+	}
+	if errSave := repo.UpdateEjecucion(ej); errSave != nil {
+		log.Printf("[scheduler] %s: no se pudo registrar fin: %v", t.nombre, errSave)
+	}
+	return ej, nil
+}
+
+func (s *Scheduler) marcarEnCurso(t *tarea, v bool) {
+	s.mu.Lock()
+	t.enCurso = v
+	s.mu.Unlock()
+}
+
+// ejecutarSeguro convierte un panic de la tarea en error para no tumbar el proceso.
+func ejecutarSeguro(ctx context.Context, run JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+// Tareas lista las tareas registradas ordenadas por nombre.
+func (s *Scheduler) Tareas() []TareaInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]TareaInfo, 0, len(s.tareas))
+	for _, t := range s.tareas {
+		info := TareaInfo{
+			Nombre:      t.nombre,
+			Descripcion: t.descripcion,
+			Expresion:   t.expresion,
+			EnCurso:     t.enCurso,
+		}
+		if !t.proxima.IsZero() {
+			p := t.proxima
+			info.ProximaEjecucion = &p
+		}
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Nombre < out[j].Nombre })
+	return out
+}
+
+// EsLider indica si esta instancia corre actualmente las ejecuciones programadas.
+func (s *Scheduler) EsLider() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lider != nil
+}
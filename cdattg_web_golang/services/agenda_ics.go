@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+)
+
+const (
+	icsProdID        = "-//SENA CDATTG//Agenda de formacion//ES"
+	icsDominioUID    = "cdattg-agenda"
+	icsFormatoUTC    = "20060102T150405Z"
+	icsMaxOctetos    = 75
+	icsMotivoDefecto = "Día sin formación"
+	// Ventana del .ics cuando no se indica rango: historial reciente y programación próxima.
+	icsDiasAtras    = 30
+	icsDiasAdelante = 180
+)
+
+// VentanaAgendaICS rango por defecto (YYYY-MM-DD) del .ics y del feed de suscripción.
+func VentanaAgendaICS(ahora time.Time) (string, string) {
+	return ahora.AddDate(0, 0, -icsDiasAtras).Format(time.DateOnly), ahora.AddDate(0, 0, icsDiasAdelante).Format(time.DateOnly)
+}
+
+// icsEscapar escapa un valor TEXT según RFC 5545 §3.3.11.
+func icsEscapar(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+// icsPlegar parte la línea en tramos de máximo 75 octetos (continuación con CRLF + espacio) sin cortar runas UTF-8.
+func icsPlegar(buf *bytes.Buffer, linea string) {
+	limite := icsMaxOctetos
+	n := 0
+	for _, r := range linea {
+		size := len(string(r))
+		if n+size > limite {
+			buf.WriteString("\r\n ")
+			n = 0
+			limite = icsMaxOctetos - 1
+		}
+		buf.WriteRune(r)
+		n += size
+	}
+	buf.WriteString("\r\n")
+}
+
+// icsUID identificador estable de la sesión: al regenerarse el feed, el cliente actualiza el mismo evento
+// (por ejemplo, al pasar a cancelado) en lugar de duplicarlo.
+func icsUID(ev dto.InstructorAgendaEvent) string {
+	return fmt.Sprintf("%s-f%d-i%d-%s@%s",
+		strings.ReplaceAll(ev.Fecha, "-", ""), ev.FichaID, ev.InstructorID,
+		strings.ReplaceAll(ev.HoraInicio, ":", ""), icsDominioUID)
+}
+
+// icsIntervalo inicio y fin de la sesión en loc; si la hora fin no es posterior a la de inicio la sesión termina al día siguiente.
+func icsIntervalo(ev dto.InstructorAgendaEvent, loc *time.Location) (time.Time, time.Time, bool) {
+	ini, err := time.ParseInLocation("2006-01-02 15:04", ev.Fecha+" "+normalizeHoraMM(ev.HoraInicio), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	fin, err := time.ParseInLocation("2006-01-02 15:04", ev.Fecha+" "+normalizeHoraMM(ev.HoraFin), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	if !fin.After(ini) {
+		fin = fin.AddDate(0, 0, 1)
+	}
+	return ini, fin, true
+}
+
+func icsUnir(partes ...string) string {
+	var out []string
+	for _, p := range partes {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " - ")
+}
+
+func icsDescripcion(ev dto.InstructorAgendaEvent) string {
+	var lineas []string
+	add := func(etiqueta, valor string) {
+		if strings.TrimSpace(valor) != "" {
+			lineas = append(lineas, etiqueta+": "+valor)
+		}
+	}
+	add("Ficha", ev.FichaNumero)
+	add("Programa", ev.ProgramaNombre)
+	add("Sede", ev.SedeNombre)
+	add("Ambiente", ev.AmbienteNombre)
+	add("Jornada", ev.JornadaNombre)
+	add("Instructor", ev.InstructorNombre)
+	if ev.Cancelado {
+		motivo := ev.MotivoCancelacion
+		if motivo == "" {
+			motivo = icsMotivoDefecto
+		}
+		add("Sesión cancelada", motivo)
+	}
+	return strings.Join(lineas, "\n")
+}
+
+// GenerarAgendaICS calendario iCalendar (RFC 5545) con un VEVENT por sesión de la agenda. Las horas se interpretan en
+// loc y se emiten en UTC; las sesiones canceladas (festivo o día sin formación) salen con STATUS:CANCELLED.
+func GenerarAgendaICS(nombre string, eventos []dto.InstructorAgendaEvent, loc *time.Location, ahora time.Time) []byte {
+	var buf bytes.Buffer
+	linea := func(s string) { icsPlegar(&buf, s) }
+	dtstamp := ahora.UTC().Format(icsFormatoUTC)
+
+	linea("BEGIN:VCALENDAR")
+	linea("VERSION:2.0")
+	linea("PRODID:" + icsProdID)
+	linea("CALSCALE:GREGORIAN")
+	linea("METHOD:PUBLISH")
+	linea("X-WR-CALNAME:" + icsEscapar(nombre))
+	linea("X-WR-TIMEZONE:" + loc.String())
+	linea("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	linea("X-PUBLISHED-TTL:PT1H")
+	for _, ev := range eventos {
+		ini, fin, ok := icsIntervalo(ev, loc)
+		if !ok {
+			continue
+		}
+		resumen := icsUnir("Ficha "+ev.FichaNumero, ev.ProgramaNombre)
+		estado, secuencia := "CONFIRMED", 0
+		if ev.Cancelado {
+			resumen = "CANCELADA: " + resumen
+			estado, secuencia = "CANCELLED", 1
+		}
+		linea("BEGIN:VEVENT")
+		linea("UID:" + icsUID(ev))
+		linea("DTSTAMP:" + dtstamp)
+		linea("DTSTART:" + ini.UTC().Format(icsFormatoUTC))
+		linea("DTEND:" + fin.UTC().Format(icsFormatoUTC))
+		linea("SUMMARY:" + icsEscapar(resumen))
+		if lugar := icsUnir(ev.AmbienteNombre, ev.SedeNombre); lugar != "" {
+			linea("LOCATION:" + icsEscapar(lugar))
+		}
+		linea("DESCRIPTION:" + icsEscapar(icsDescripcion(ev)))
+		linea("STATUS:" + estado)
+		linea(fmt.Sprintf("SEQUENCE:%d", secuencia))
+		linea("TRANSP:OPAQUE")
+		linea("END:VEVENT")
+	}
+	linea("END:VCALENDAR")
+	return buf.Bytes()
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+)
+
+func eventoAgendaICS() dto.InstructorAgendaEvent {
+	return dto.InstructorAgendaEvent{
+		Fecha: "2026-03-10", HoraInicio: "18:00", HoraFin: "22:00",
+		FichaID: 12, FichaNumero: "2900001", ProgramaNombre: "Análisis y desarrollo de software",
+		SedeNombre: "Sede Norte", AmbienteNombre: "Bloque A; Sala 2", JornadaNombre: "Noche",
+		InstructorID: 5, InstructorNombre: "Ana Ruiz",
+	}
+}
+
+func TestIcsEscaparYPlegar(t *testing.T) {
+	t.Parallel()
+	if got := icsEscapar("a,b;c\\d\ne"); got != `a\,b\;c\\d\ne` {
+		t.Errorf("icsEscapar = %q", got)
+	}
+	var buf bytes.Buffer
+	icsPlegar(&buf, "SUMMARY:"+strings.Repeat("ñ", 60))
+	lineas := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lineas) < 2 {
+		t.Fatalf("esperaba línea plegada, got %q", buf.String())
+	}
+	for i, l := range lineas {
+		if len(l) > icsMaxOctetos {
+			t.Errorf("línea %d con %d octetos", i, len(l))
+		}
+		if i > 0 && !strings.HasPrefix(l, " ") {
+			t.Errorf("continuación %d sin espacio inicial", i)
+		}
+		if !strings.HasSuffix(l, "ñ") && !strings.HasSuffix(l, ":") {
+			t.Errorf("línea %d corta una runa: %q", i, l)
+		}
+	}
+}
+
+func TestGenerarAgendaICS(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("COT", -5*3600)
+	ahora := time.Date(2026, 3, 1, 8, 0, 0, 0, loc)
+	ev := eventoAgendaICS()
+	nocturna := ev
+	nocturna.Fecha, nocturna.HoraInicio, nocturna.HoraFin = "2026-03-11", "22:00", "02:00"
+	cancelada := ev
+	cancelada.Fecha, cancelada.Cancelado, cancelada.MotivoCancelacion = "2026-03-12", true, "Jornada pedagógica"
+
+	out := string(GenerarAgendaICS("Mi agenda", []dto.InstructorAgendaEvent{ev, nocturna, cancelada}, loc, ahora))
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Error("todas las líneas deben terminar en CRLF")
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Mi agenda\r\n",
+		"UID:20260310-f12-i5-1800@" + icsDominioUID + "\r\n",
+		"DTSTART:20260310T230000Z\r\nDTEND:20260311T030000Z\r\n",
+		"DTSTART:20260312T030000Z\r\nDTEND:20260312T070000Z\r\n",
+		`LOCATION:Bloque A\; Sala 2 - Sede Norte`,
+		`Jornada: Noche\n`,
+		"SUMMARY:CANCELADA: Ficha 2900001 - Análisis y desarrollo de software\r\n",
+		`Sesión cancelada: Jornada pedagógica`,
+		"STATUS:CANCELLED\r\nSEQUENCE:1\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("falta %q en\n%s", want, unfolded)
+		}
+	}
+	if n := strings.Count(out, "STATUS:CONFIRMED"); n != 2 {
+		t.Errorf("STATUS:CONFIRMED = %d, want 2", n)
+	}
+	if icsUID(ev) != icsUID(eventoAgendaICS()) {
+		t.Error("el UID debe ser estable entre generaciones")
+	}
+}
+
+func TestMarcarCancelacionesAgenda(t *testing.T) {
+	t.Parallel()
+	eventos := []dto.InstructorAgendaEvent{{Fecha: "2026-03-09"}, {Fecha: "2026-03-23"}, {Fecha: "2026-03-24"}}
+	motivos := map[string]string{"2026-03-23": "Festivo", "2026-03-24": "Paro"}
+	marcarCancelacionesAgenda(eventos, func(fecha string) string { return motivos[fecha] })
+	if eventos[0].Cancelado || !eventos[1].Cancelado || eventos[2].MotivoCancelacion != "Paro" {
+		t.Errorf("eventos = %+v", eventos)
+	}
+}
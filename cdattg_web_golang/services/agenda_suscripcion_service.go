@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAgendaSinInstructor         = errors.New("usuario no vinculado como instructor")
+	ErrAgendaSuscripcionInvalida   = errors.New("enlace de agenda inválido o revocado")
+	ErrAgendaSuscripcionNoGenerada = errors.New("no se pudo generar el enlace de suscripción")
+)
+
+// AgendaSuscripcionService enlace secreto por usuario para suscribirse a su agenda de instructor desde un cliente de
+// calendario (Google Calendar, Outlook). El token solo se muestra al generarlo; en base de datos queda su hash.
+type AgendaSuscripcionService interface {
+	Estado(userID uint) (*dto.AgendaSuscripcionResponse, error)
+	// Generar crea o reemplaza el enlace del usuario y devuelve el token en claro.
+	Generar(userID uint) (string, *dto.AgendaSuscripcionResponse, error)
+	Revocar(userID uint) error
+	// AgendaICS valida el token y genera el .ics del instructor dueño del enlace para la ventana por defecto.
+	AgendaICS(token string) ([]byte, error)
+}
+
+type agendaSuscripcionService struct {
+	repo      repositories.AgendaSuscripcionRepository
+	userRepo  repositories.UserRepository
+	instRepo  repositories.InstructorRepository
+	agendaSvc *InstructorAgendaService
+}
+
+func NewAgendaSuscripcionService() AgendaSuscripcionService {
+	return &agendaSuscripcionService{
+		repo:      repositories.NewAgendaSuscripcionRepository(),
+		userRepo:  repositories.NewUserRepository(),
+		instRepo:  repositories.NewInstructorRepository(),
+		agendaSvc: NewInstructorAgendaService(),
+	}
+}
+
+func (s *agendaSuscripcionService) instructorDeUsuario(user *models.User) (*models.Instructor, error) {
+	if user == nil || user.PersonaID == nil {
+		return nil, ErrAgendaSinInstructor
+	}
+	inst, err := s.instRepo.FindByPersonaID(*user.PersonaID)
+	if err != nil || inst == nil {
+		return nil, ErrAgendaSinInstructor
+	}
+	return inst, nil
+}
+
+func (s *agendaSuscripcionService) Estado(userID uint) (*dto.AgendaSuscripcionResponse, error) {
+	sus, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &dto.AgendaSuscripcionResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AgendaSuscripcionResponse{Activa: true, CreatedAt: &sus.CreatedAt, UltimoUsoAt: sus.UltimoUsoAt}, nil
+}
+
+func (s *agendaSuscripcionService) Generar(userID uint) (string, *dto.AgendaSuscripcionResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", nil, ErrAgendaSinInstructor
+	}
+	if _, err := s.instructorDeUsuario(user); err != nil {
+		return "", nil, err
+	}
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, ErrAgendaSuscripcionNoGenerada
+	}
+	ahora := utils.Now()
+	if err := s.repo.Guardar(userID, utils.HashRefreshToken(token), ahora); err != nil {
+		return "", nil, err
+	}
+	return token, &dto.AgendaSuscripcionResponse{Activa: true, Token: token, CreatedAt: &ahora}, nil
+}
+
+func (s *agendaSuscripcionService) Revocar(userID uint) error {
+	return s.repo.DeleteByUserID(userID)
+}
+
+func (s *agendaSuscripcionService) AgendaICS(token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrAgendaSuscripcionInvalida
+	}
+	sus, err := s.repo.FindByTokenHash(utils.HashRefreshToken(token))
+	if err != nil {
+		return nil, ErrAgendaSuscripcionInvalida
+	}
+	user, err := s.userRepo.FindByID(sus.UserID)
+	if err != nil || !user.Status {
+		return nil, ErrAgendaSuscripcionInvalida
+	}
+	inst, err := s.instructorDeUsuario(user)
+	if err != nil {
+		return nil, err
+	}
+	ahora := utils.Now()
+	desde, hasta := VentanaAgendaICS(ahora)
+	agenda, err := s.agendaSvc.AgendaInstructor(inst.ID, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.repo.MarcarUso(sus.ID, ahora)
+	nombre := "Agenda de formación"
+	if inst.NombreCompletoCache != "" {
+		nombre += " - " + inst.NombreCompletoCache
+	}
+	return GenerarAgendaICS(nombre, agenda.Eventos, utils.AppLocation(), ahora), nil
+}
@@ -21,6 +21,7 @@ type AlertaAsistenciaService struct {
 	asistenciaRepo repositories.AsistenciaRepository
 	alertaRepo     repositories.AlertaAsistenciaRepository
 	userRepo       repositories.UserRepository
+	correos        CorreoService
 }
 
 // NewAlertaAsistenciaService crea el servicio.
@@ -30,16 +31,18 @@ func NewAlertaAsistenciaService() *AlertaAsistenciaService {
 		asistenciaRepo: repositories.NewAsistenciaRepository(),
 		alertaRepo:     repositories.NewAlertaAsistenciaRepository(),
 		userRepo:       repositories.NewUserRepository(),
+		correos:        NewCorreoService(),
 	}
 }
 
 // CheckAndNotify ejecuta la verificación: si alguna ficha activa con formación hoy no ha abierto sesión de asistencia
 // pasados N minutos desde el inicio de la jornada, envía un correo a todos los coordinadores (una sola vez por ficha por día).
-func (s *AlertaAsistenciaService) CheckAndNotify() {
+// Devuelve error solo si no se pudieron consultar las fichas; los fallos de envío por ficha se registran en el log.
+func (s *AlertaAsistenciaService) CheckAndNotify() error {
 	cfgAlertas := config.AppConfig.Alertas
 	cfgSMTP := config.AppConfig.SMTP
 	if !cfgAlertas.Enabled || !cfgSMTP.Enabled {
-		return
+		return nil
 	}
 
 	loc := utils.AppLocation()
@@ -50,13 +53,12 @@ func (s *AlertaAsistenciaService) CheckAndNotify() {
 	emails := s.emailsCoordinadores()
 	if len(emails) == 0 {
 		log.Println("Alerta asistencia: no hay coordinadores con correo para notificar")
-		return
+		return nil
 	}
 
 	fichas, err := s.fichaRepo.FindActivasParaHoyConJornada(now)
 	if err != nil {
-		log.Printf("Alerta asistencia: error listando fichas: %v", err)
-		return
+		return fmt.Errorf("alerta asistencia: error listando fichas: %w", err)
 	}
 
 	minutosDespues := minutosAlertaSinSesion()
@@ -67,6 +69,7 @@ func (s *AlertaAsistenciaService) CheckAndNotify() {
 	for i := range fichas {
 		s.notifyFichaSiAplica(&fichas[i], now, hoy, fechaStr, minutosDespues, emails)
 	}
+	return nil
 }
 
 func minutosAlertaDesdeConfig(m int) int {
@@ -98,17 +101,34 @@ func fichaPendienteAlertaAsistencia(
 	return errEx == nil && !existe
 }
 
-func cuerpoCorreoAlertaAsistencia(f *models.FichaCaracterizacion, fechaStr string, minutosDespues int) (string, string) {
+func correoAlertaAsistencia(emails []string, f *models.FichaCaracterizacion, fechaStr string, minutosDespues int) (CorreoNuevo, error) {
 	nombreJornada := f.Jornada.Nombre
 	if nombreJornada == "" {
 		nombreJornada = "N/A"
 	}
-	asunto := fmt.Sprintf("[CDATTG] Ficha %s no ha iniciado toma de asistencia", f.Ficha)
-	cuerpo := fmt.Sprintf(
-		"Se informa que la ficha %s (jornada %s) no ha registrado inicio de toma de asistencia el día %s, pasados %d minutos desde el inicio de la jornada.\n\nFicha: %s\nJornada: %s\nFecha: %s",
-		f.Ficha, nombreJornada, fechaStr, minutosDespues, f.Ficha, nombreJornada, fechaStr,
-	)
-	return asunto, cuerpo
+	html, texto, err := renderAviso(&avisoCorreo{
+		correoBase: correoBase{Titulo: "Ficha sin toma de asistencia"},
+		Parrafos: []string{fmt.Sprintf(
+			"La ficha %s no ha registrado inicio de toma de asistencia hoy, pasados %d minutos desde el inicio de la jornada.",
+			f.Ficha, minutosDespues,
+		)},
+		Datos: []datoCorreo{
+			{Etiqueta: "Ficha", Valor: f.Ficha},
+			{Etiqueta: "Jornada", Valor: nombreJornada},
+			{Etiqueta: "Fecha", Valor: fechaStr},
+		},
+		Boton: &botonCorreo{Texto: "Abrir el panel de asistencia", Enlace: enlaceResumen(frontendURL(), rutaPanelResumen)},
+	})
+	if err != nil {
+		return CorreoNuevo{}, err
+	}
+	return CorreoNuevo{
+		Para:      emails,
+		Asunto:    fmt.Sprintf("[CDATTG] Ficha %s no ha iniciado toma de asistencia", f.Ficha),
+		Plantilla: PlantillaCorreoAlertaAsistencia,
+		HTML:      html,
+		Texto:     texto,
+	}, nil
 }
 
 // notifyFichaSiAplica envía correo y registra log si la ficha cumple condiciones de alerta.
@@ -116,12 +136,15 @@ func (s *AlertaAsistenciaService) notifyFichaSiAplica(f *models.FichaCaracteriza
 	if !fichaPendienteAlertaAsistencia(s, f, now, hoy, fechaStr, minutosDespues) {
 		return
 	}
-	asunto, cuerpo := cuerpoCorreoAlertaAsistencia(f, fechaStr, minutosDespues)
-	if errSend := utils.SendMail(emails, asunto, cuerpo); errSend != nil {
+	correo, errSend := correoAlertaAsistencia(emails, f, fechaStr, minutosDespues)
+	if errSend == nil {
+		errSend = s.correos.Encolar(correo)
+	}
+	if errSend != nil {
 		log.Printf("Alerta asistencia: error enviando correo para ficha %s: %v", f.Ficha, errSend)
 		return
 	}
-	log.Printf("Alerta asistencia: correo enviado a coordinadores por ficha %s (sin asistencia iniciada)", f.Ficha)
+	log.Printf("Alerta asistencia: correo encolado a coordinadores por ficha %s (sin asistencia iniciada)", f.Ficha)
 	if errCreate := s.alertaRepo.Create(&models.AlertaAsistenciaLog{FichaID: f.ID, Fecha: hoy}); errCreate != nil {
 		log.Printf("Alerta asistencia: error registrando log para ficha %s: %v", f.Ficha, errCreate)
 	}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+const errMsgColisionAmbiente = "EL AMBIENTE YA ESTÁ OCUPADO EN ESE DÍA Y HORARIO POR OTRA FICHA"
+
+// AmbienteOcupacionService ocupación semanal de ambientes a partir de los bloques horarios de las fichas
+// (ficha_dias_formacion o, en su defecto, la plantilla de la jornada) y detección de doble asignación.
+type AmbienteOcupacionService struct {
+	fichaRepo  repositories.FichaRepository
+	horarioSvc *InstructorHorarioService
+}
+
+func NewAmbienteOcupacionService() *AmbienteOcupacionService {
+	return &AmbienteOcupacionService{
+		fichaRepo:  repositories.NewFichaRepository(),
+		horarioSvc: NewInstructorHorarioService(),
+	}
+}
+
+// bloquesSemanaFicha bloques de la semana tipo de la ficha. Sin días configurados se usa la plantilla de su jornada;
+// sin ninguno de los dos la ficha no ocupa el ambiente.
+func (s *AmbienteOcupacionService) bloquesSemanaFicha(ficha *models.FichaCaracterizacion) []HorarioBloqueInput {
+	diaIDs := s.horarioSvc.diaIDsParaBloques(ficha, nil)
+	if len(diaIDs) == 0 {
+		if jid := jornadaIDFromFicha(ficha); jid != nil && *jid > 0 {
+			plantilla, _ := BloquesJornadaPlantilla(*jid)
+			return plantilla
+		}
+		return nil
+	}
+	var out []HorarioBloqueInput
+	for _, diaID := range diaIDs {
+		for _, b := range s.horarioSvc.bloquesDiaFicha(ficha, diaID) {
+			b.DiaFormacionID = diaID
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func (s *AmbienteOcupacionService) bloquesAmbiente(ambienteID, excluirFichaID uint) ([]bloqueSemanal, error) {
+	fichas, err := s.fichaRepo.FindActivasByAmbienteID(ambienteID)
+	if err != nil {
+		return nil, err
+	}
+	var out []bloqueSemanal
+	for i := range fichas {
+		f := &fichas[i]
+		if excluirFichaID > 0 && f.ID == excluirFichaID {
+			continue
+		}
+		vigInicio, vigFin := config.FechasVigenciaFicha(f)
+		for _, b := range s.bloquesSemanaFicha(f) {
+			out = append(out, bloqueSemanal{
+				fichaID: f.ID, fichaNum: f.Ficha,
+				diaFormacionID: b.DiaFormacionID, horaInicio: b.HoraInicio, horaFin: b.HoraFin,
+				vigenciaInicio: vigInicio, vigenciaFin: vigFin,
+			})
+		}
+	}
+	return out, nil
+}
+
+// ValidarAmbienteDisponible rechaza asignar el ambiente a la ficha si otra ficha activa lo ocupa en un bloque que se
+// solapa (mismo día, horario y vigencia). ficha lleva los datos a guardar; excluirFichaID es la propia ficha al actualizar.
+func (s *AmbienteOcupacionService) ValidarAmbienteDisponible(ambienteID, excluirFichaID uint, ficha *models.FichaCaracterizacion) error {
+	if config.RelaxarColisionAmbiente() || ambienteID == 0 || ficha == nil || !ficha.Status {
+		return nil
+	}
+	nuevos := s.bloquesSemanaFicha(ficha)
+	if len(nuevos) == 0 {
+		return nil
+	}
+	existing, err := s.bloquesAmbiente(ambienteID, excluirFichaID)
+	if err != nil {
+		return err
+	}
+	vigInicio, vigFin := config.FechasVigenciaFicha(ficha)
+	for _, b := range nuevos {
+		if ex := bloqueQueSolapa(b.DiaFormacionID, b.HoraInicio, b.HoraFin, vigInicio, vigFin, existing); ex != nil {
+			return fmt.Errorf("%s: el %s %s–%s lo usa la ficha %s (%s–%s)",
+				errMsgColisionAmbiente, nombreDia(b.DiaFormacionID), b.HoraInicio, b.HoraFin, ex.fichaNum, ex.horaInicio, ex.horaFin)
+		}
+	}
+	return nil
+}
+
+// lunesDeSemana fecha (00:00) del lunes de la semana que contiene fecha.
+func lunesDeSemana(fecha time.Time) time.Time {
+	d := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, fecha.Location())
+	return d.AddDate(0, 0, -int(WeekdayToDiaFormacionID(d.Weekday())-1))
+}
+
+// marcarConflictosDia marca los bloques del día que se solapan con otro de distinta ficha y devuelve cuántos son.
+func marcarConflictosDia(bloques []dto.AmbienteOcupacionBloque) int {
+	n := 0
+	for i := range bloques {
+		for j := range bloques {
+			if i == j || bloques[i].FichaID == bloques[j].FichaID {
+				continue
+			}
+			if intervalosSeSolapan(bloques[i].HoraInicio, bloques[i].HoraFin, bloques[j].HoraInicio, bloques[j].HoraFin) {
+				bloques[i].Conflicto = true
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// Ocupacion grilla lunes–domingo de la semana de fecha con los bloques de cada ficha activa vigente ese día.
+func (s *AmbienteOcupacionService) Ocupacion(ambienteID uint, fecha time.Time) (*dto.AmbienteOcupacionResponse, error) {
+	fichas, err := s.fichaRepo.FindActivasByAmbienteID(ambienteID)
+	if err != nil {
+		return nil, err
+	}
+	lunes := lunesDeSemana(fecha)
+	resp := &dto.AmbienteOcupacionResponse{
+		AmbienteID:   ambienteID,
+		SemanaInicio: lunes.Format(time.DateOnly),
+		SemanaFin:    lunes.AddDate(0, 0, 6).Format(time.DateOnly),
+		Dias:         make([]dto.AmbienteOcupacionDia, 7),
+	}
+	for i := range resp.Dias {
+		resp.Dias[i] = dto.AmbienteOcupacionDia{
+			DiaFormacionID: uint(i + 1),
+			DiaNombre:      nombreDia(uint(i + 1)),
+			Fecha:          lunes.AddDate(0, 0, i).Format(time.DateOnly),
+			Bloques:        make([]dto.AmbienteOcupacionBloque, 0),
+		}
+	}
+	for i := range fichas {
+		f := &fichas[i]
+		vigInicio, vigFin := config.FechasVigenciaFicha(f)
+		programa := ""
+		if f.ProgramaFormacion != nil {
+			programa = f.ProgramaFormacion.Nombre
+		}
+		for _, b := range s.bloquesSemanaFicha(f) {
+			if b.DiaFormacionID < 1 || b.DiaFormacionID > 7 {
+				continue
+			}
+			dia := &resp.Dias[b.DiaFormacionID-1]
+			if !diaDentroDeVigencia(lunes.AddDate(0, 0, int(b.DiaFormacionID-1)), vigInicio, vigFin) {
+				continue
+			}
+			dia.Bloques = append(dia.Bloques, dto.AmbienteOcupacionBloque{
+				FichaID: f.ID, FichaNumero: f.Ficha, ProgramaNombre: programa,
+				HoraInicio: b.HoraInicio, HoraFin: b.HoraFin,
+			})
+		}
+	}
+	for i := range resp.Dias {
+		bloques := resp.Dias[i].Bloques
+		sort.SliceStable(bloques, func(a, b int) bool {
+			return bloques[a].HoraInicio < bloques[b].HoraInicio
+		})
+		resp.Conflictos += marcarConflictosDia(bloques)
+	}
+	return resp, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/models"
+)
+
+func fichaConBloques(id uint, numero string, dias ...models.FichaDiasFormacion) models.FichaCaracterizacion {
+	f := models.FichaCaracterizacion{Ficha: numero, Status: true, FichaDiasFormacion: dias}
+	f.ID = id
+	return f
+}
+
+func diaFicha(diaID uint, hi, hf string) models.FichaDiasFormacion {
+	return models.FichaDiasFormacion{DiaFormacionID: diaID, HoraInicio: hi, HoraFin: hf}
+}
+
+func testOcupacionService(fichas ...models.FichaCaracterizacion) *AmbienteOcupacionService {
+	fichaRepo := &stubFichaRepoHorario{porAmbiente: fichas}
+	return &AmbienteOcupacionService{
+		fichaRepo: fichaRepo,
+		horarioSvc: &InstructorHorarioService{
+			fichaRepo:     fichaRepo,
+			fichaDiasRepo: &stubFichaDiasRepo{},
+		},
+	}
+}
+
+func setRelaxarColisionAmbiente(t *testing.T, enabled bool) {
+	t.Helper()
+	prev := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prev })
+	cfg := &config.Config{}
+	if prev != nil {
+		copy := *prev
+		cfg = &copy
+	}
+	cfg.Negocio.RelaxarColisionAmbiente = enabled
+	config.AppConfig = cfg
+}
+
+func TestValidarAmbienteDisponible(t *testing.T) {
+	setRelaxarColisionAmbiente(t, false)
+	svc := testOcupacionService(
+		fichaConBloques(1, "2900001", diaFicha(1, "07:00", "12:00"), diaFicha(3, "07:00", "12:00")),
+	)
+
+	cand := fichaConBloques(0, "2900002", diaFicha(1, "11:00", "13:00"))
+	err := svc.ValidarAmbienteDisponible(5, 0, &cand)
+	if err == nil || !strings.Contains(err.Error(), errMsgColisionAmbiente) || !strings.Contains(err.Error(), "2900001") {
+		t.Fatalf("esperaba colisión con la ficha 2900001, got %v", err)
+	}
+
+	libre := fichaConBloques(0, "2900002", diaFicha(1, "12:00", "18:00"), diaFicha(2, "07:00", "12:00"))
+	if err := svc.ValidarAmbienteDisponible(5, 0, &libre); err != nil {
+		t.Errorf("bloques contiguos u otro día no colisionan: %v", err)
+	}
+
+	misma := fichaConBloques(1, "2900001", diaFicha(1, "08:00", "12:00"))
+	if err := svc.ValidarAmbienteDisponible(5, 1, &misma); err != nil {
+		t.Errorf("la propia ficha se excluye al actualizar: %v", err)
+	}
+
+	inactiva := cand
+	inactiva.Status = false
+	if err := svc.ValidarAmbienteDisponible(5, 0, &inactiva); err != nil {
+		t.Errorf("una ficha inactiva no ocupa el ambiente: %v", err)
+	}
+
+	setRelaxarColisionAmbiente(t, true)
+	if err := svc.ValidarAmbienteDisponible(5, 0, &cand); err != nil {
+		t.Errorf("con el modo relajado no se valida: %v", err)
+	}
+}
+
+func TestValidarAmbienteDisponible_VigenciasSinCruce(t *testing.T) {
+	setRelaxarColisionAmbiente(t, false)
+	config.AppConfig.Negocio.IgnorarVigenciaFicha = false
+	ini1, fin1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	ini2, fin2 := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	existente := fichaConBloques(1, "2900001", diaFicha(1, "07:00", "12:00"))
+	existente.FechaInicio, existente.FechaFin = &ini1, &fin1
+	cand := fichaConBloques(0, "2900002", diaFicha(1, "07:00", "12:00"))
+	cand.FechaInicio, cand.FechaFin = &ini2, &fin2
+
+	if err := testOcupacionService(existente).ValidarAmbienteDisponible(5, 0, &cand); err != nil {
+		t.Errorf("fichas con vigencias consecutivas no colisionan: %v", err)
+	}
+}
+
+func TestOcupacionSemanal(t *testing.T) {
+	setRelaxarColisionAmbiente(t, false)
+	svc := testOcupacionService(
+		fichaConBloques(1, "2900001", diaFicha(1, "07:00", "12:00"), diaFicha(5, "13:00", "18:00")),
+		fichaConBloques(2, "2900002", diaFicha(1, "11:00", "13:00")),
+		fichaConBloques(3, "2900003", diaFicha(1, "13:00", "18:00")),
+	)
+	miercoles := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	resp, err := svc.Ocupacion(5, miercoles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.SemanaInicio != "2026-03-09" || resp.SemanaFin != "2026-03-15" || len(resp.Dias) != 7 {
+		t.Fatalf("semana = %s..%s (%d días)", resp.SemanaInicio, resp.SemanaFin, len(resp.Dias))
+	}
+	lunes := resp.Dias[0]
+	if lunes.Fecha != "2026-03-09" || len(lunes.Bloques) != 3 {
+		t.Fatalf("lunes = %+v", lunes)
+	}
+	if !lunes.Bloques[0].Conflicto || !lunes.Bloques[1].Conflicto || lunes.Bloques[2].Conflicto {
+		t.Errorf("conflictos lunes = %+v", lunes.Bloques)
+	}
+	if resp.Conflictos != 2 {
+		t.Errorf("Conflictos = %d, want 2", resp.Conflictos)
+	}
+	if v := resp.Dias[4]; len(v.Bloques) != 1 || v.Bloques[0].FichaNumero != "2900001" {
+		t.Errorf("viernes = %+v", v)
+	}
+}
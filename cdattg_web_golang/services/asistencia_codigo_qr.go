@@ -0,0 +1,88 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+)
+
+var (
+	ErrCodigoQRInvalido = errors.New("el código QR no es válido para registrar asistencia")
+	ErrCodigoQRVencido  = errors.New("el código QR ya venció; escanee el código que muestra ahora el instructor")
+	ErrCodigoQRUsado    = errors.New("ya usó este código QR; espere a que el instructor muestre el siguiente")
+)
+
+const (
+	// prefijoCodigoQRAsistencia distingue el código rotativo de un documento escaneado y versiona el formato.
+	prefijoCodigoQRAsistencia = "CDATTG1"
+	// periodoCodigoQRAsistencia segundos que se muestra cada código; se acepta también el anterior para cubrir la
+	// demora entre escanear y enviar.
+	periodoCodigoQRAsistencia = 30
+	bytesFirmaCodigoQR        = 16
+	tamModuloSVGCodigoQR      = 6
+)
+
+// claveCodigoQRAsistencia deriva del secreto JWT una clave propia para no reutilizarla tal cual en otro propósito.
+func claveCodigoQRAsistencia() []byte {
+	secreto := "cdattg-web-golang-secret-key-change-in-production"
+	if config.AppConfig != nil && config.AppConfig.JWT.Secret != "" {
+		secreto = config.AppConfig.JWT.Secret
+	}
+	mac := hmac.New(sha256.New, []byte(secreto))
+	mac.Write([]byte("asistencia-qr"))
+	return mac.Sum(nil)
+}
+
+func ventanaCodigoQR(t time.Time) int64 {
+	return t.Unix() / periodoCodigoQRAsistencia
+}
+
+func firmaCodigoQRAsistencia(clave []byte, asistenciaID uint, ventana int64) string {
+	mac := hmac.New(sha256.New, clave)
+	fmt.Fprintf(mac, "%s|%d|%d", prefijoCodigoQRAsistencia, asistenciaID, ventana)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:bytesFirmaCodigoQR])
+}
+
+// generarCodigoQRAsistencia código de la ventana actual: CDATTG1.<asistencia>.<ventana>.<firma>. Devuelve también el
+// instante en que empieza la siguiente ventana.
+func generarCodigoQRAsistencia(clave []byte, asistenciaID uint, ahora time.Time) (string, time.Time) {
+	ventana := ventanaCodigoQR(ahora)
+	codigo := fmt.Sprintf("%s.%d.%d.%s", prefijoCodigoQRAsistencia, asistenciaID, ventana,
+		firmaCodigoQRAsistencia(clave, asistenciaID, ventana))
+	return codigo, time.Unix((ventana+1)*periodoCodigoQRAsistencia, 0).In(ahora.Location())
+}
+
+// verificarCodigoQRAsistencia valida la firma y que el código sea de la ventana actual o de la anterior.
+func verificarCodigoQRAsistencia(clave []byte, codigo string, ahora time.Time) (uint, int64, error) {
+	partes := strings.Split(strings.TrimSpace(codigo), ".")
+	if len(partes) != 4 || partes[0] != prefijoCodigoQRAsistencia {
+		return 0, 0, ErrCodigoQRInvalido
+	}
+	asistenciaID, err := strconv.ParseUint(partes[1], 10, 64)
+	if err != nil || asistenciaID == 0 {
+		return 0, 0, ErrCodigoQRInvalido
+	}
+	ventana, err := strconv.ParseInt(partes[2], 10, 64)
+	if err != nil {
+		return 0, 0, ErrCodigoQRInvalido
+	}
+	esperada := firmaCodigoQRAsistencia(clave, uint(asistenciaID), ventana)
+	if !hmac.Equal([]byte(partes[3]), []byte(esperada)) {
+		return 0, 0, ErrCodigoQRInvalido
+	}
+	actual := ventanaCodigoQR(ahora)
+	if ventana > actual {
+		return 0, 0, ErrCodigoQRInvalido
+	}
+	if ventana < actual-1 {
+		return 0, 0, ErrCodigoQRVencido
+	}
+	return uint(asistenciaID), ventana, nil
+}
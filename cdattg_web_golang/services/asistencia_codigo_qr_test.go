@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodigoQRAsistenciaFirmaYVentanas(t *testing.T) {
+	t.Parallel()
+	clave := []byte("clave-de-prueba")
+	emitido := time.Unix(1_800_000_000, 0) // inicio exacto de una ventana de 30 s
+	codigo, siguiente := generarCodigoQRAsistencia(clave, 42, emitido)
+	if !strings.HasPrefix(codigo, prefijoCodigoQRAsistencia+".42.") {
+		t.Fatalf("codigo = %q", codigo)
+	}
+	if got := siguiente.Sub(emitido); got != periodoCodigoQRAsistencia*time.Second {
+		t.Fatalf("siguiente código en %v, want 30s", got)
+	}
+
+	cases := []struct {
+		name  string
+		ahora time.Time
+		err   error
+	}{
+		{"misma ventana", emitido.Add(29 * time.Second), nil},
+		{"ventana siguiente (tolerancia)", emitido.Add(59 * time.Second), nil},
+		{"dos ventanas después", emitido.Add(60 * time.Second), ErrCodigoQRVencido},
+		{"antes de emitirse", emitido.Add(-time.Second), ErrCodigoQRInvalido},
+	}
+	for _, tc := range cases {
+		id, ventana, err := verificarCodigoQRAsistencia(clave, codigo, tc.ahora)
+		if err != tc.err {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.err)
+			continue
+		}
+		if err == nil && (id != 42 || ventana != ventanaCodigoQR(emitido)) {
+			t.Errorf("%s: id=%d ventana=%d", tc.name, id, ventana)
+		}
+	}
+}
+
+func TestCodigoQRAsistenciaRechazaAlterados(t *testing.T) {
+	t.Parallel()
+	clave := []byte("clave-de-prueba")
+	ahora := time.Unix(1_800_000_000, 0)
+	codigo, _ := generarCodigoQRAsistencia(clave, 42, ahora)
+	partes := strings.Split(codigo, ".")
+	otroFinal := "A"
+	if strings.HasSuffix(codigo, otroFinal) {
+		otroFinal = "B"
+	}
+
+	alterados := []string{
+		"",
+		"1234567890",
+		strings.Replace(codigo, ".42.", ".43.", 1),
+		partes[0] + "." + partes[1] + ".60000001." + partes[3],
+		codigo[:len(codigo)-1] + otroFinal,
+		"OTRO." + strings.Join(partes[1:], "."),
+	}
+	for _, c := range alterados {
+		if _, _, err := verificarCodigoQRAsistencia(clave, c, ahora); err != ErrCodigoQRInvalido {
+			t.Errorf("verificar(%q) err = %v, want ErrCodigoQRInvalido", c, err)
+		}
+	}
+	if _, _, err := verificarCodigoQRAsistencia([]byte("otra-clave"), codigo, ahora); err != ErrCodigoQRInvalido {
+		t.Errorf("otra clave: err = %v, want ErrCodigoQRInvalido", err)
+	}
+}
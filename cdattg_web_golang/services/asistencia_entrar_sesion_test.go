@@ -34,6 +34,9 @@ func (s *stubAsistenciaRepoEntrar) FindByID(id uint) (*models.Asistencia, error)
 	}
 	return nil, nil
 }
+func (s *stubAsistenciaRepoEntrar) FindContextoEvento(id uint) (*models.Asistencia, error) {
+	return s.FindByID(id)
+}
 func (s *stubAsistenciaRepoEntrar) FindByInstructorFichaID(uint) ([]models.Asistencia, error) {
 	return nil, nil
 }
@@ -2,30 +2,24 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/sena/cdattg-web-golang/config"
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
 )
 
-// paraPDF normaliza texto para fuentes PDF sin UTF-8 (ñ y tildes).
-func paraPDF(s string) string {
-	repl := strings.NewReplacer(
-		"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u",
-		"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U",
-		"ñ", "n", "Ñ", "N", "ü", "u", "Ü", "U",
-	)
-	return repl.Replace(s)
-}
-
 const reporteAsistenciaDir = "storage/asistencia_pdfs"
 
 // GenerateReporteFinalizacion genera un PDF de reporte (asistieron / no asistieron) al finalizar una sesión.
-// Guarda en storage/asistencia_pdfs/ y devuelve la ruta del archivo.
+// Guarda en storage/asistencia_pdfs/ y devuelve la ruta del archivo. Los que no asistieron con excusa aprobada
+// se marcan como tal.
 func GenerateReporteFinalizacion(asist *models.Asistencia, aprendicesFicha []models.Aprendiz) (string, error) {
 	if err := os.MkdirAll(reporteAsistenciaDir, 0755); err != nil {
 		return "", fmt.Errorf("crear directorio reportes: %w", err)
@@ -36,8 +30,9 @@ func GenerateReporteFinalizacion(asist *models.Asistencia, aprendicesFicha []mod
 	ruta := filepath.Join(reporteAsistenciaDir, nombreArchivo)
 
 	idsConIngreso := idsAprendizConIngreso(asist)
+	idsExcusados := idsAprendizExcusados(asist.ID)
 
-	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf := utils.NewPDF("P")
 	pdf.SetMargins(15, 15, 15)
 	pdf.SetAutoPageBreak(true, 15)
 	pdf.AddPage()
@@ -45,7 +40,7 @@ func GenerateReporteFinalizacion(asist *models.Asistencia, aprendicesFicha []mod
 
 	const wDoc, wNom, wIng, wSal = 35.0, 70.0, 28.0, 28.0
 	pdfReporteTablaAsistieron(pdf, asist, wDoc, wNom, wIng, wSal)
-	pdfReporteTablaNoAsistieron(pdf, aprendicesFicha, idsConIngreso, wDoc)
+	pdfReporteTablaNoAsistieron(pdf, aprendicesFicha, idsConIngreso, idsExcusados, wDoc, wNom)
 
 	if err := pdf.OutputFileAndClose(ruta); err != nil {
 		return "", fmt.Errorf("escribir PDF: %w", err)
@@ -71,20 +66,32 @@ func idsAprendizConIngreso(asist *models.Asistencia) map[uint]bool {
 	return ids
 }
 
+func idsAprendizExcusados(asistenciaID uint) map[uint]bool {
+	ids := make(map[uint]bool)
+	list, err := repositories.NewExcusaRepository().ListAprendicesExcusadosEnSesion(asistenciaID)
+	if err != nil {
+		return ids
+	}
+	for _, id := range list {
+		ids[id] = true
+	}
+	return ids
+}
+
 func pdfReporteEncabezado(pdf *gofpdf.Fpdf, fichaNum, fecha string, asist *models.Asistencia) {
-	pdf.SetFont("Helvetica", "B", 14)
+	pdf.SetFont(utils.PDFFuente, "B", 14)
 	pdf.CellFormat(0, 8, "REPORTE DE ASISTENCIA", "", 1, "C", false, 0, "")
 	pdf.Ln(4)
-	pdf.SetFont("Helvetica", "", 10)
-	pdf.CellFormat(0, 6, fmt.Sprintf("Ficha: %s  |  Fecha: %s  |  Sesion ID: %d", fichaNum, fecha, asist.ID), "", 1, "L", false, 0, "")
+	pdf.SetFont(utils.PDFFuente, "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Ficha: %s  |  Fecha: %s  |  Sesión ID: %d", fichaNum, fecha, asist.ID), "", 1, "L", false, 0, "")
 	pdf.CellFormat(0, 6, fmt.Sprintf("Hora inicio: %s  |  Hora fin: %s", formatTime(asist.HoraInicio), formatTime(asist.HoraFin)), "", 1, "L", false, 0, "")
 	pdf.Ln(6)
 }
 
 func pdfReporteTablaAsistieron(pdf *gofpdf.Fpdf, asist *models.Asistencia, wDoc, wNom, wIng, wSal float64) {
-	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetFont(utils.PDFFuente, "B", 11)
 	pdf.CellFormat(0, 7, "ASISTIERON", "", 1, "L", false, 0, "")
-	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetFont(utils.PDFFuente, "", 9)
 	pdf.SetFillColor(230, 230, 230)
 	pdf.CellFormat(wDoc, 7, "Documento", "1", 0, "L", true, 0, "")
 	pdf.CellFormat(wNom, 7, "Nombre", "1", 0, "L", true, 0, "")
@@ -97,10 +104,10 @@ func pdfReporteTablaAsistieron(pdf *gofpdf.Fpdf, asist *models.Asistencia, wDoc,
 		nombre := "-"
 		doc := "-"
 		if a.Aprendiz != nil && a.Aprendiz.Persona != nil {
-			nombre = paraPDF(truncateStr(a.Aprendiz.Persona.GetFullName(), 40))
+			nombre = truncateStr(a.Aprendiz.Persona.GetFullName(), 40)
 			doc = a.Aprendiz.Persona.NumeroDocumento
 		}
-		pdf.CellFormat(wDoc, 6, paraPDF(doc), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(wDoc, 6, doc, "1", 0, "L", false, 0, "")
 		pdf.CellFormat(wNom, 6, nombre, "1", 0, "L", false, 0, "")
 		pdf.CellFormat(wIng, 6, formatTime(a.HoraIngreso), "1", 0, "C", false, 0, "")
 		pdf.CellFormat(wSal, 6, formatTime(a.HoraSalida), "1", 1, "C", false, 0, "")
@@ -108,13 +115,14 @@ func pdfReporteTablaAsistieron(pdf *gofpdf.Fpdf, asist *models.Asistencia, wDoc,
 	pdf.Ln(8)
 }
 
-func pdfReporteTablaNoAsistieron(pdf *gofpdf.Fpdf, aprendicesFicha []models.Aprendiz, idsConIngreso map[uint]bool, wDoc float64) {
-	pdf.SetFont("Helvetica", "B", 11)
+func pdfReporteTablaNoAsistieron(pdf *gofpdf.Fpdf, aprendicesFicha []models.Aprendiz, idsConIngreso, idsExcusados map[uint]bool, wDoc, wNom float64) {
+	pdf.SetFont(utils.PDFFuente, "B", 11)
 	pdf.CellFormat(0, 7, "NO ASISTIERON", "", 1, "L", false, 0, "")
-	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetFont(utils.PDFFuente, "", 9)
 	pdf.SetFillColor(230, 230, 230)
 	pdf.CellFormat(wDoc, 7, "Documento", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(0, 7, "Nombre", "1", 1, "L", true, 0, "")
+	pdf.CellFormat(wNom, 7, "Nombre", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(0, 7, "Novedad", "1", 1, "L", true, 0, "")
 	for _, a := range aprendicesFicha {
 		if !a.Estado || idsConIngreso[a.ID] {
 			continue
@@ -122,11 +130,16 @@ func pdfReporteTablaNoAsistieron(pdf *gofpdf.Fpdf, aprendicesFicha []models.Apre
 		nombre := "-"
 		doc := "-"
 		if a.Persona != nil {
-			nombre = paraPDF(truncateStr(a.Persona.GetFullName(), 70))
+			nombre = truncateStr(a.Persona.GetFullName(), 40)
 			doc = a.Persona.NumeroDocumento
 		}
-		pdf.CellFormat(wDoc, 6, paraPDF(doc), "1", 0, "L", false, 0, "")
-		pdf.CellFormat(0, 6, nombre, "1", 1, "L", false, 0, "")
+		novedad := "-"
+		if idsExcusados[a.ID] {
+			novedad = "Excusa aprobada"
+		}
+		pdf.CellFormat(wDoc, 6, doc, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(wNom, 6, nombre, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, novedad, "1", 1, "L", false, 0, "")
 	}
 }
 
@@ -145,6 +158,118 @@ func truncateStr(s string, max int) string {
 	return string(runes[:max])
 }
 
+// conteoReporteSesion aprendices activos que asistieron (con ingreso) y que no asistieron.
+func conteoReporteSesion(asist *models.Asistencia, aprendicesFicha []models.Aprendiz) (asistieron, noAsistieron int) {
+	conIngreso := idsAprendizConIngreso(asist)
+	for _, a := range aprendicesFicha {
+		if !a.Estado {
+			continue
+		}
+		if conIngreso[a.ID] {
+			asistieron++
+		} else {
+			noAsistieron++
+		}
+	}
+	return asistieron, noAsistieron
+}
+
+// enviarReporteSesionInstructor encola al correo del instructor el PDF de la sesión recién finalizada
+// (SMTP_ENVIAR_REPORTE_SESION). Los fallos solo se registran: no impiden finalizar la sesión.
+func (s *asistenciaService) enviarReporteSesionInstructor(asist *models.Asistencia, aprendicesFicha []models.Aprendiz, ruta string) {
+	if config.AppConfig == nil || !config.AppConfig.SMTP.EnviarReporteSesion || !utils.SMTPHabilitado() || asist.InstructorFicha == nil {
+		return
+	}
+	inst, err := s.instRepo.FindByID(asist.InstructorFicha.InstructorID)
+	if err != nil {
+		return
+	}
+	user, err := s.userRepo.FindByPersonaID(inst.PersonaID)
+	if err != nil || !user.Status || !correoEntregable(user.Email) {
+		return
+	}
+	pdf, err := os.ReadFile(ruta)
+	if err != nil {
+		log.Printf("[asistencia] leyendo reporte de la sesión %d: %v", asist.ID, err)
+		return
+	}
+	fichaNum := fichaNumeroParaReporte(asist)
+	fecha := asist.Fecha.Format("02/01/2006")
+	asistieron, noAsistieron := conteoReporteSesion(asist, aprendicesFicha)
+	html, texto, err := renderAviso(&avisoCorreo{
+		correoBase: correoBase{Titulo: "Reporte de asistencia", Periodo: fmt.Sprintf("Ficha %s · %s", fichaNum, fecha)},
+		Parrafos:   []string{"La sesión de asistencia finalizó. Adjuntamos el reporte en PDF con quienes asistieron y quienes no."},
+		Datos: []datoCorreo{
+			{Etiqueta: "Ficha", Valor: fichaNum},
+			{Etiqueta: "Fecha", Valor: fecha},
+			{Etiqueta: "Horario", Valor: formatTime(asist.HoraInicio) + " - " + formatTime(asist.HoraFin)},
+			{Etiqueta: "Asistieron", Valor: strconv.Itoa(asistieron)},
+			{Etiqueta: "No asistieron", Valor: strconv.Itoa(noAsistieron)},
+		},
+		Nota: "Si se aprueba una excusa después de hoy, el reporte actualizado queda disponible en el historial de asistencia.",
+	})
+	if err != nil {
+		log.Printf("[asistencia] armando correo del reporte de la sesión %d: %v", asist.ID, err)
+		return
+	}
+	err = s.correos.Encolar(CorreoNuevo{
+		Para:      []string{user.Email},
+		Asunto:    fmt.Sprintf("CDATTG Web - Reporte de asistencia ficha %s (%s)", fichaNum, fecha),
+		Plantilla: PlantillaCorreoReporteSesion,
+		HTML:      html,
+		Texto:     texto,
+		Adjuntos:  []utils.AdjuntoCorreo{{Nombre: filepath.Base(ruta), ContentType: "application/pdf", Contenido: pdf}},
+	})
+	if err != nil {
+		log.Printf("[asistencia] encolando reporte de la sesión %d: %v", asist.ID, err)
+	}
+}
+
+// notificarInasistencias avisa a los aprendices activos de la ficha que no registraron ingreso en la sesión
+// finalizada. Se omiten los ocultos en asistencia y los que ya tienen excusa aprobada.
+func (s *asistenciaService) notificarInasistencias(asist *models.Asistencia, aprendicesFicha []models.Aprendiz) {
+	if s.notificaciones == nil {
+		return
+	}
+	conIngreso := idsAprendizConIngreso(asist)
+	excusados := idsAprendizExcusados(asist.ID)
+	ausentes := make([]uint, 0)
+	for _, a := range aprendicesFicha {
+		if !a.Estado || a.OcultoEnAsistencia || conIngreso[a.ID] || excusados[a.ID] {
+			continue
+		}
+		ausentes = append(ausentes, a.ID)
+	}
+	if len(ausentes) == 0 {
+		return
+	}
+	fichaNum := fichaNumeroParaReporte(asist)
+	fecha := asist.Fecha.Format("02/01/2006")
+	s.notificaciones.NotificarAprendices(ausentes, NotificacionNueva{
+		Tipo:    models.NotificacionTipoInasistencia,
+		Titulo:  fmt.Sprintf("Inasistencia registrada el %s", fecha),
+		Mensaje: fmt.Sprintf("La sesión de la ficha %s del %s (%s - %s) finalizó sin registro de su ingreso. Si tuvo una novedad, puede radicar la excusa.", fichaNum, fecha, formatTime(asist.HoraInicio), formatTime(asist.HoraFin)),
+		Enlace:  rutaNotificacionInasistencias,
+	})
+}
+
+// RegenerarReporteSesion vuelve a generar el PDF de una sesión ya finalizada (p. ej. tras aprobar una excusa).
+func RegenerarReporteSesion(asistenciaID uint) error {
+	asist, err := repositories.NewAsistenciaRepository().FindByID(asistenciaID)
+	if err != nil {
+		return err
+	}
+	if !asist.IsFinished || asist.InstructorFicha == nil {
+		return nil
+	}
+	aprendices, err := CargarAprendicesFichaParaReporte(asist.InstructorFicha.FichaID)
+	if err != nil {
+		return err
+	}
+	_, err = GenerateReporteFinalizacion(asist, aprendices)
+	return err
+}
+
 // CargarAprendicesFichaParaReporte carga los aprendices activos de la ficha para el reporte.
 func CargarAprendicesFichaParaReporte(fichaID uint) ([]models.Aprendiz, error) {
 	repo := repositories.NewAprendizRepository()
@@ -0,0 +1,816 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// Rangos de más días se generan en segundo plano (consultar el estado y descargar después).
+	diasMaxReporteSincrono   = 31
+	diasMaxReporteAsistencia = 366
+	maxReportesListados      = 20
+	// maxReportesSimultaneos reportes que genera a la vez cada réplica; el resto espera turno.
+	maxReportesSimultaneos = 2
+	// reservaReporteAsistencia tiempo que un reporte tomado queda fuera de la cola; si la réplica cae, al vencer otra
+	// lo retoma. Debe superar la generación más larga.
+	reservaReporteAsistencia = 10 * time.Minute
+	// maxIntentosReporteAsistencia tomas tras las que un reporte que sigue interrumpiéndose se da por fallido.
+	maxIntentosReporteAsistencia = 3
+	diasRetencionReportes        = 30
+
+	FormatoReportePDF  = "pdf"
+	FormatoReporteXLSX = "xlsx"
+
+	// Códigos de la matriz aprendiz x sesión
+	codAsistio     = "A"
+	codParcial     = "P"
+	codAbandono    = "AB"
+	codPorCorregir = "R"
+	codJustificada = "J"
+	codFalla       = "F"
+)
+
+var (
+	ErrReporteFormatoInvalido = errors.New("formato inválido (pdf o xlsx)")
+	ErrReporteRangoInvalido   = errors.New("rango de fechas inválido")
+	ErrReporteNoEncontrado    = errors.New("reporte no encontrado")
+	ErrReporteNoDisponible    = errors.New("el reporte aún no está disponible")
+	ErrReporteArchivoExpirado = errors.New("el archivo del reporte ya no está disponible")
+)
+
+var leyendaCodigosAsistencia = []struct{ codigo, descripcion string }{
+	{codAsistio, "Asistió"},
+	{codParcial, "Asistencia parcial"},
+	{codAbandono, "Abandono de jornada"},
+	{codPorCorregir, "Registro por corregir"},
+	{codJustificada, "Inasistencia justificada / excusa aprobada"},
+	{codFalla, "Falla"},
+}
+
+type consolidadoSesion struct {
+	ID         uint
+	Fecha      time.Time
+	HoraInicio *time.Time
+	HoraFin    *time.Time
+	Instructor string
+}
+
+type consolidadoFila struct {
+	Documento    string
+	Nombre       string
+	Codigos      []string
+	Asistencias  int // A + P
+	Parciales    int
+	Abandonos    int
+	PorCorregir  int
+	Justificadas int
+	Fallas       int
+	Porcentaje   float64
+}
+
+type consolidadoFirma struct {
+	Nombre    string
+	Documento string
+	Sesiones  int
+}
+
+// consolidadoAsistencia matriz aprendices x sesiones de una ficha en un periodo, lista para PDF o Excel.
+type consolidadoAsistencia struct {
+	FichaNumero string
+	Programa    string
+	Desde       time.Time
+	Hasta       time.Time
+	GeneradoEn  time.Time
+	Sesiones    []consolidadoSesion
+	Filas       []consolidadoFila
+	Firmas      []consolidadoFirma
+}
+
+// codigoAsistenciaSesion código del aprendiz en la sesión; J si no asistió y la falta está justificada.
+func codigoAsistenciaSesion(aa *models.AsistenciaAprendiz, justificada bool) string {
+	if aa != nil && aa.HoraIngreso != nil {
+		switch aa.Estado {
+		case "ASISTENCIA_PARCIAL":
+			return codParcial
+		case "ABANDONO_JORNADA":
+			return codAbandono
+		case "REGISTRO_POR_CORREGIR":
+			return codPorCorregir
+		default:
+			return codAsistio
+		}
+	}
+	if justificada {
+		return codJustificada
+	}
+	return codFalla
+}
+
+// totalizarFila cuenta códigos y calcula el porcentaje de asistencia: (A + P) sobre las sesiones que cuentan;
+// las justificadas y los registros por corregir no entran en el cálculo.
+func totalizarFila(f *consolidadoFila) {
+	for _, c := range f.Codigos {
+		switch c {
+		case codAsistio:
+			f.Asistencias++
+		case codParcial:
+			f.Asistencias++
+			f.Parciales++
+		case codAbandono:
+			f.Abandonos++
+		case codPorCorregir:
+			f.PorCorregir++
+		case codJustificada:
+			f.Justificadas++
+		case codFalla:
+			f.Fallas++
+		}
+	}
+	base := len(f.Codigos) - f.Justificadas - f.PorCorregir
+	if base <= 0 {
+		f.Porcentaje = 100
+		return
+	}
+	f.Porcentaje = float64(f.Asistencias) * 100 / float64(base)
+}
+
+func claveSesionAprendiz(asistenciaID, aprendizID uint) [2]uint {
+	return [2]uint{asistenciaID, aprendizID}
+}
+
+// armarConsolidado construye la matriz. Filas: aprendices activos de la ficha (en el orden recibido, por nombre)
+// más, al final, quien tenga registro en alguna sesión del periodo; columnas: sesiones en orden cronológico. instructores resuelve el nombre por instructor_ficha.
+func armarConsolidado(
+	sesiones []models.Asistencia,
+	aprendices []models.Aprendiz,
+	justificadas map[[2]uint]bool,
+	instructores func(instructorFichaID uint) (string, string),
+) ([]consolidadoSesion, []consolidadoFila, []consolidadoFirma) {
+	sort.SliceStable(sesiones, func(i, j int) bool {
+		if !sesiones[i].Fecha.Equal(sesiones[j].Fecha) {
+			return sesiones[i].Fecha.Before(sesiones[j].Fecha)
+		}
+		hi, hj := sesiones[i].HoraInicio, sesiones[j].HoraInicio
+		return hi != nil && (hj == nil || hi.Before(*hj))
+	})
+
+	registros := make(map[[2]uint]*models.AsistenciaAprendiz)
+	enRegistros := make(map[uint]*models.Aprendiz)
+	var ordenRegistros []uint
+	cols := make([]consolidadoSesion, len(sesiones))
+	firmas := make(map[string]*consolidadoFirma)
+	var ordenFirmas []string
+	for i := range sesiones {
+		s := &sesiones[i]
+		nombre, doc := instructores(s.InstructorFichaID)
+		cols[i] = consolidadoSesion{ID: s.ID, Fecha: s.Fecha, HoraInicio: s.HoraInicio, HoraFin: s.HoraFin, Instructor: nombre}
+		if nombre != "" {
+			key := doc + "|" + nombre
+			if firmas[key] == nil {
+				firmas[key] = &consolidadoFirma{Nombre: nombre, Documento: doc}
+				ordenFirmas = append(ordenFirmas, key)
+			}
+			firmas[key].Sesiones++
+		}
+		for j := range s.AsistenciaAprendices {
+			aa := &s.AsistenciaAprendices[j]
+			registros[claveSesionAprendiz(s.ID, aa.AprendizFichaID)] = aa
+			if aa.Aprendiz != nil && enRegistros[aa.AprendizFichaID] == nil {
+				enRegistros[aa.AprendizFichaID] = aa.Aprendiz
+				ordenRegistros = append(ordenRegistros, aa.AprendizFichaID)
+			}
+		}
+	}
+
+	incluidos := make(map[uint]bool)
+	var lista []models.Aprendiz
+	for _, a := range aprendices {
+		if a.Estado || enRegistros[a.ID] != nil {
+			lista = append(lista, a)
+			incluidos[a.ID] = true
+		}
+	}
+	for _, id := range ordenRegistros {
+		if !incluidos[id] {
+			lista = append(lista, *enRegistros[id])
+		}
+	}
+
+	filas := make([]consolidadoFila, 0, len(lista))
+	for _, a := range lista {
+		f := consolidadoFila{Codigos: make([]string, len(sesiones))}
+		if a.Persona != nil {
+			f.Nombre = strings.TrimSpace(a.Persona.GetFullName())
+			f.Documento = a.Persona.NumeroDocumento
+		}
+		for i := range sesiones {
+			aa := registros[claveSesionAprendiz(sesiones[i].ID, a.ID)]
+			f.Codigos[i] = codigoAsistenciaSesion(aa, justificadas[claveSesionAprendiz(sesiones[i].ID, a.ID)])
+		}
+		totalizarFila(&f)
+		filas = append(filas, f)
+	}
+
+	out := make([]consolidadoFirma, 0, len(ordenFirmas))
+	for _, k := range ordenFirmas {
+		out = append(out, *firmas[k])
+	}
+	return cols, filas, out
+}
+
+// ReporteAsistenciaService reportes consolidados de asistencia por ficha y periodo (PDF con fuente UTF-8 o XLSX).
+type ReporteAsistenciaService struct {
+	asistRepo     repositories.AsistenciaRepository
+	aprendizRepo  repositories.AprendizRepository
+	excusaRepo    repositories.ExcusaRepository
+	fichaRepo     repositories.FichaRepository
+	instFichaRepo repositories.InstructorFichaRepository
+	instRepo      repositories.InstructorRepository
+	reporteRepo   repositories.ReporteAsistenciaRepository
+	ejecutor      *ejecutorTrabajos
+}
+
+var ejecutorReportesGlobal = newEjecutorTrabajos(maxReportesSimultaneos)
+
+func NewReporteAsistenciaService() *ReporteAsistenciaService {
+	return &ReporteAsistenciaService{
+		asistRepo:     repositories.NewAsistenciaRepository(),
+		aprendizRepo:  repositories.NewAprendizRepository(),
+		excusaRepo:    repositories.NewExcusaRepository(),
+		fichaRepo:     repositories.NewFichaRepository(),
+		instFichaRepo: repositories.NewInstructorFichaRepository(),
+		instRepo:      repositories.NewInstructorRepository(),
+		reporteRepo:   repositories.NewReporteAsistenciaRepository(),
+		ejecutor:      ejecutorReportesGlobal,
+	}
+}
+
+// ArchivoReporteAsistencia archivo generado listo para descargar.
+type ArchivoReporteAsistencia struct {
+	Nombre      string
+	ContentType string
+	Datos       []byte
+}
+
+func contentTypeReporte(formato string) string {
+	if formato == FormatoReporteXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/pdf"
+}
+
+func parseRangoReporte(fechaInicio, fechaFin string) (time.Time, time.Time, error) {
+	d0, err := parseFechaLocal(fechaInicio)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrReporteRangoInvalido
+	}
+	d1, err := parseFechaLocal(fechaFin)
+	if err != nil || d1.Before(d0) {
+		return time.Time{}, time.Time{}, ErrReporteRangoInvalido
+	}
+	if d1.Sub(d0) > diasMaxReporteAsistencia*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: máximo %d días", ErrReporteRangoInvalido, diasMaxReporteAsistencia)
+	}
+	return d0, d1, nil
+}
+
+func nombreArchivoReporte(fichaNumero string, d0, d1 time.Time, formato string) string {
+	return fmt.Sprintf("asistencia_consolidada_%s_%s_%s.%s", fichaNumero, d0.Format("20060102"), d1.Format("20060102"), formato)
+}
+
+// Solicitar genera el reporte de inmediato si el rango es corto (devuelve el archivo) o encola su generación en
+// segundo plano (devuelve el registro del trabajo para consultar su estado).
+func (s *ReporteAsistenciaService) Solicitar(userID, fichaID uint, fechaInicio, fechaFin, formato string) (*ArchivoReporteAsistencia, *models.ReporteAsistencia, error) {
+	formato = strings.ToLower(strings.TrimSpace(formato))
+	if formato == "" {
+		formato = FormatoReportePDF
+	}
+	if formato != FormatoReportePDF && formato != FormatoReporteXLSX {
+		return nil, nil, ErrReporteFormatoInvalido
+	}
+	d0, d1, err := parseRangoReporte(fechaInicio, fechaFin)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d1.Sub(d0) < diasMaxReporteSincrono*24*time.Hour {
+		archivo, _, err := s.generar(fichaID, d0, d1, formato)
+		return archivo, nil, err
+	}
+	rep := &models.ReporteAsistencia{
+		FichaID: fichaID, FechaInicio: d0, FechaFin: d1, Formato: formato,
+		Estado: models.ReporteAsistenciaPendiente, SolicitadoPor: userID,
+	}
+	if err := s.reporteRepo.Create(rep); err != nil {
+		return nil, nil, err
+	}
+	s.lanzar(rep.ID)
+	return nil, rep, nil
+}
+
+// lanzar genera el reporte en segundo plano cuando haya cupo en la réplica. Si otra réplica lo tomó antes, no hace nada.
+func (s *ReporteAsistenciaService) lanzar(id uint) {
+	s.ejecutor.lanzar(id, func() {
+		ahora := utils.Now()
+		ok, err := s.reporteRepo.ReclamarPorID(id, ahora, ahora.Add(reservaReporteAsistencia))
+		if err != nil {
+			log.Printf("[reportes asistencia] tomando reporte %d: %v", id, err)
+			return
+		}
+		if ok {
+			s.ejecutar(id)
+		}
+	})
+}
+
+// ejecutar genera un reporte ya tomado y guarda el archivo. Un reporte que ya interrumpió varias réplicas (p. ej.
+// por memoria) no se reintenta más.
+func (s *ReporteAsistenciaService) ejecutar(id uint) {
+	rep, err := s.reporteRepo.FindByID(id)
+	if err != nil {
+		log.Printf("[reportes asistencia] cargando reporte %d: %v", id, err)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[reportes asistencia] reporte %d: panic: %v", id, r)
+			s.finalizar(rep, nil, 0, fmt.Errorf("error inesperado: %v", r))
+		}
+	}()
+	if rep.Intentos > maxIntentosReporteAsistencia {
+		s.finalizar(rep, nil, 0, fmt.Errorf("la generación se interrumpió %d veces", rep.Intentos-1))
+		return
+	}
+	archivo, sesiones, err := s.generar(rep.FichaID, rep.FechaInicio, rep.FechaFin, rep.Formato)
+	s.finalizar(rep, archivo, sesiones, err)
+}
+
+// finalizar guarda el resultado con el archivo o el error. Si otra réplica tomó el reporte entretanto, descarta este.
+func (s *ReporteAsistenciaService) finalizar(rep *models.ReporteAsistencia, archivo *ArchivoReporteAsistencia, sesiones int, causa error) {
+	ahora := utils.Now()
+	fin := *rep
+	fin.FinalizadoAt = &ahora
+	fin.UpdatedAt = ahora
+	var guardado *models.ReporteAsistenciaArchivo
+	if causa != nil {
+		log.Printf("[reportes asistencia] reporte %d: %v", rep.ID, causa)
+		fin.Estado = models.ReporteAsistenciaError
+		fin.Error = causa.Error()
+	} else {
+		fin.Estado = models.ReporteAsistenciaCompletado
+		fin.NombreArchivo = archivo.Nombre
+		fin.Sesiones = sesiones
+		guardado = &models.ReporteAsistenciaArchivo{Contenido: archivo.Datos}
+	}
+	vigente, err := s.reporteRepo.Finalizar(&fin, guardado)
+	if err != nil {
+		log.Printf("[reportes asistencia] reporte %d: guardar resultado: %v", rep.ID, err)
+		return
+	}
+	if !vigente {
+		log.Printf("[reportes asistencia] reporte %d: otra réplica lo tomó; se descarta este intento", rep.ID)
+		return
+	}
+	*rep = fin
+}
+
+// ProcesarPendientes genera los reportes en cola y retoma los que quedaron a medias en una réplica caída (tarea
+// programada).
+func (s *ReporteAsistenciaService) ProcesarPendientes(ctx context.Context) error {
+	ids, err := s.reporteRepo.ListReclamables(utils.Now(), loteReclamables)
+	if err != nil {
+		return fmt.Errorf("cola de reportes de asistencia: %w", err)
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.lanzar(id)
+	}
+	if len(ids) > 0 {
+		log.Printf("[reportes asistencia] %d reportes pendientes o interrumpidos en cola", len(ids))
+	}
+	return nil
+}
+
+// PurgarAntiguos elimina los reportes terminados hace más de 30 días con su archivo.
+func (s *ReporteAsistenciaService) PurgarAntiguos() error {
+	n, err := s.reporteRepo.DeleteTerminadosAntesDe(utils.Now().AddDate(0, 0, -diasRetencionReportes))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("[reportes asistencia] eliminados %d reportes terminados hace más de %d días", n, diasRetencionReportes)
+	}
+	return nil
+}
+
+// Estado devuelve el trabajo de reporte de la ficha.
+func (s *ReporteAsistenciaService) Estado(fichaID, reporteID uint) (*models.ReporteAsistencia, error) {
+	rep, err := s.reporteRepo.FindByID(reporteID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && rep.FichaID != fichaID) {
+		return nil, ErrReporteNoEncontrado
+	}
+	return rep, err
+}
+
+// ListByFicha últimos reportes en segundo plano de la ficha.
+func (s *ReporteAsistenciaService) ListByFicha(fichaID uint) ([]models.ReporteAsistencia, error) {
+	return s.reporteRepo.ListByFicha(fichaID, maxReportesListados)
+}
+
+// Archivo archivo de un reporte completado.
+func (s *ReporteAsistenciaService) Archivo(fichaID, reporteID uint) (*ArchivoReporteAsistencia, error) {
+	rep, err := s.Estado(fichaID, reporteID)
+	if err != nil {
+		return nil, err
+	}
+	if rep.Estado != models.ReporteAsistenciaCompletado {
+		return nil, ErrReporteNoDisponible
+	}
+	archivo, err := s.reporteRepo.FindArchivo(rep.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReporteArchivoExpirado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ArchivoReporteAsistencia{Nombre: rep.NombreArchivo, ContentType: contentTypeReporte(rep.Formato), Datos: archivo.Contenido}, nil
+}
+
+func (s *ReporteAsistenciaService) nombresInstructores() func(uint) (string, string) {
+	cache := make(map[uint][2]string)
+	return func(instructorFichaID uint) (string, string) {
+		if v, ok := cache[instructorFichaID]; ok {
+			return v[0], v[1]
+		}
+		var v [2]string
+		if ifc, err := s.instFichaRepo.FindByID(instructorFichaID); err == nil && ifc != nil {
+			if inst, err := s.instRepo.FindByID(ifc.InstructorID); err == nil && inst != nil {
+				if inst.Persona != nil {
+					v = [2]string{strings.TrimSpace(inst.Persona.GetFullName()), inst.Persona.NumeroDocumento}
+				} else {
+					v = [2]string{inst.NombreCompletoCache, inst.NumeroDocumentoCache}
+				}
+			}
+		}
+		cache[instructorFichaID] = v
+		return v[0], v[1]
+	}
+}
+
+func (s *ReporteAsistenciaService) consolidar(fichaID uint, d0, d1 time.Time) (*consolidadoAsistencia, error) {
+	ficha, err := s.fichaRepo.FindByID(fichaID)
+	if err != nil {
+		return nil, err
+	}
+	sesiones, err := s.asistRepo.FindByFichaIDAndFechas(fichaID, d0.Format(time.DateOnly), d1.Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	aprendices, err := s.aprendizRepo.FindByFichaID(fichaID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(sesiones))
+	for i := range sesiones {
+		ids[i] = sesiones[i].ID
+	}
+	justificadas := make(map[[2]uint]bool)
+	obs, err := s.asistRepo.ListInasistenciasJustificadasEnSesiones(ids)
+	if err != nil {
+		return nil, err
+	}
+	exc, err := s.excusaRepo.ListExcusadosEnSesiones(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range append(obs, exc...) {
+		justificadas[claveSesionAprendiz(r.AsistenciaID, r.AprendizID)] = true
+	}
+	c := &consolidadoAsistencia{FichaNumero: ficha.Ficha, Desde: d0, Hasta: d1, GeneradoEn: utils.Now()}
+	if ficha.ProgramaFormacion != nil {
+		c.Programa = ficha.ProgramaFormacion.Nombre
+	}
+	c.Sesiones, c.Filas, c.Firmas = armarConsolidado(sesiones, aprendices, justificadas, s.nombresInstructores())
+	return c, nil
+}
+
+func (s *ReporteAsistenciaService) generar(fichaID uint, d0, d1 time.Time, formato string) (*ArchivoReporteAsistencia, int, error) {
+	c, err := s.consolidar(fichaID, d0, d1)
+	if err != nil {
+		return nil, 0, err
+	}
+	var datos []byte
+	if formato == FormatoReporteXLSX {
+		datos, err = renderConsolidadoXLSX(c)
+	} else {
+		datos, err = renderConsolidadoPDF(c)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return &ArchivoReporteAsistencia{
+		Nombre:      nombreArchivoReporte(c.FichaNumero, d0, d1, formato),
+		ContentType: contentTypeReporte(formato),
+		Datos:       datos,
+	}, len(c.Sesiones), nil
+}
+
+func formatPorcentaje(p float64) string {
+	return fmt.Sprintf("%.1f%%", p)
+}
+
+func encabezadoSesion(s consolidadoSesion) (string, string) {
+	return s.Fecha.Format("02/01"), formatTime(s.HoraInicio)
+}
+
+// Anchos (mm) del PDF horizontal: columnas fijas + columnas de sesión repartidas en bloques por página.
+const (
+	pdfConsWDoc        = 24.0
+	pdfConsWNom        = 58.0
+	pdfConsWSesion     = 9.0
+	pdfConsSesPorPag   = 21
+	pdfConsAltoFila    = 5.0
+	pdfConsAltoEncabez = 5.0
+)
+
+func pdfConsolidadoEncabezado(pdf *gofpdf.Fpdf, c *consolidadoAsistencia) {
+	pdf.SetFont(utils.PDFFuente, "B", 13)
+	pdf.CellFormat(0, 7, "REPORTE CONSOLIDADO DE ASISTENCIA", "", 1, "C", false, 0, "")
+	pdf.SetFont(utils.PDFFuente, "", 9)
+	pdf.CellFormat(0, 5, fmt.Sprintf("Ficha: %s  |  Programa: %s", c.FichaNumero, c.Programa), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, fmt.Sprintf("Periodo: %s a %s  |  Sesiones: %d  |  Aprendices: %d  |  Generado: %s",
+		c.Desde.Format("02/01/2006"), c.Hasta.Format("02/01/2006"), len(c.Sesiones), len(c.Filas),
+		c.GeneradoEn.Format("02/01/2006 15:04")), "", 1, "L", false, 0, "")
+	var ley []string
+	for _, l := range leyendaCodigosAsistencia {
+		ley = append(ley, l.codigo+" = "+l.descripcion)
+	}
+	pdf.SetFont(utils.PDFFuente, "", 7)
+	pdf.CellFormat(0, 5, strings.Join(ley, "   "), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+}
+
+func pdfConsolidadoEncabezadoMatriz(pdf *gofpdf.Fpdf, c *consolidadoAsistencia, desde, hasta int) {
+	pdf.SetFont(utils.PDFFuente, "B", 7)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(pdfConsWDoc, pdfConsAltoEncabez*2, "Documento", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(pdfConsWNom, pdfConsAltoEncabez*2, "Aprendiz", "1", 0, "L", true, 0, "")
+	x, y := pdf.GetX(), pdf.GetY()
+	for i := desde; i < hasta; i++ {
+		dia, hora := encabezadoSesion(c.Sesiones[i])
+		pdf.SetXY(x+float64(i-desde)*pdfConsWSesion, y)
+		pdf.CellFormat(pdfConsWSesion, pdfConsAltoEncabez, dia, "LTR", 2, "C", true, 0, "")
+		pdf.SetFont(utils.PDFFuente, "", 6)
+		pdf.CellFormat(pdfConsWSesion, pdfConsAltoEncabez, hora, "LBR", 0, "C", true, 0, "")
+		pdf.SetFont(utils.PDFFuente, "B", 7)
+	}
+	pdf.SetXY(pdf.GetX(), y)
+	pdf.Ln(pdfConsAltoEncabez * 2)
+}
+
+func pdfConsolidadoMatriz(pdf *gofpdf.Fpdf, c *consolidadoAsistencia, desde, hasta int) {
+	pdfConsolidadoEncabezadoMatriz(pdf, c, desde, hasta)
+	_, altoPag := pdf.GetPageSize()
+	_, _, _, margenInf := pdf.GetMargins()
+	for _, f := range c.Filas {
+		if pdf.GetY()+pdfConsAltoFila > altoPag-margenInf {
+			pdf.AddPage()
+			pdfConsolidadoEncabezadoMatriz(pdf, c, desde, hasta)
+		}
+		pdf.SetFont(utils.PDFFuente, "", 7)
+		pdf.CellFormat(pdfConsWDoc, pdfConsAltoFila, f.Documento, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(pdfConsWNom, pdfConsAltoFila, truncateStr(f.Nombre, 38), "1", 0, "L", false, 0, "")
+		for i := desde; i < hasta; i++ {
+			cod := f.Codigos[i]
+			fill := cod == codFalla || cod == codAbandono
+			if fill {
+				pdf.SetFillColor(248, 215, 218)
+			}
+			pdf.CellFormat(pdfConsWSesion, pdfConsAltoFila, cod, "1", 0, "C", fill, 0, "")
+		}
+		pdf.Ln(pdfConsAltoFila)
+	}
+}
+
+func pdfConsolidadoTotales(pdf *gofpdf.Fpdf, c *consolidadoAsistencia) {
+	pdf.SetFont(utils.PDFFuente, "B", 10)
+	pdf.CellFormat(0, 7, "TOTALES POR APRENDIZ", "", 1, "L", false, 0, "")
+	cols := []struct {
+		titulo string
+		w      float64
+	}{{"Documento", pdfConsWDoc}, {"Aprendiz", pdfConsWNom}, {"Sesiones", 16}, {"Asistió", 16}, {"Parcial", 16},
+		{"Abandono", 16}, {"Justif.", 16}, {"Por corregir", 20}, {"Fallas", 16}, {"% Asistencia", 22}}
+	pdf.SetFont(utils.PDFFuente, "B", 7)
+	pdf.SetFillColor(230, 230, 230)
+	for i, col := range cols {
+		ln := 0
+		if i == len(cols)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(col.w, 6, col.titulo, "1", ln, "C", true, 0, "")
+	}
+	pdf.SetFont(utils.PDFFuente, "", 7)
+	for _, f := range c.Filas {
+		vals := []string{f.Documento, truncateStr(f.Nombre, 38), fmt.Sprint(len(f.Codigos)), fmt.Sprint(f.Asistencias - f.Parciales),
+			fmt.Sprint(f.Parciales), fmt.Sprint(f.Abandonos), fmt.Sprint(f.Justificadas), fmt.Sprint(f.PorCorregir),
+			fmt.Sprint(f.Fallas), formatPorcentaje(f.Porcentaje)}
+		for i, v := range vals {
+			align, ln := "C", 0
+			if i < 2 {
+				align = "L"
+			}
+			if i == len(vals)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(cols[i].w, pdfConsAltoFila, v, "1", ln, align, false, 0, "")
+		}
+	}
+}
+
+func pdfConsolidadoFirmas(pdf *gofpdf.Fpdf, c *consolidadoAsistencia) {
+	if len(c.Firmas) == 0 {
+		return
+	}
+	pdf.Ln(6)
+	pdf.SetFont(utils.PDFFuente, "B", 10)
+	pdf.CellFormat(0, 7, "FIRMAS DE LOS INSTRUCTORES", "", 1, "L", false, 0, "")
+	const wFirma, porFila = 85.0, 3
+	for i := 0; i < len(c.Firmas); i += porFila {
+		if pdf.GetY() > 170 {
+			pdf.AddPage()
+		}
+		pdf.Ln(14)
+		y := pdf.GetY()
+		for j := i; j < i+porFila && j < len(c.Firmas); j++ {
+			f := c.Firmas[j]
+			x := 10 + float64(j-i)*(wFirma+7)
+			pdf.Line(x, y, x+wFirma-10, y)
+			pdf.SetXY(x, y+1)
+			pdf.SetFont(utils.PDFFuente, "", 8)
+			pdf.CellFormat(wFirma, 4, f.Nombre, "", 2, "L", false, 0, "")
+			pdf.CellFormat(wFirma, 4, fmt.Sprintf("C.C. %s  |  %d sesiones", f.Documento, f.Sesiones), "", 0, "L", false, 0, "")
+		}
+		pdf.SetXY(10, y+10)
+	}
+}
+
+func renderConsolidadoPDF(c *consolidadoAsistencia) ([]byte, error) {
+	pdf := utils.NewPDF("L")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 10)
+	pdf.AddPage()
+	pdfConsolidadoEncabezado(pdf, c)
+	if len(c.Sesiones) == 0 {
+		pdf.SetFont(utils.PDFFuente, "", 10)
+		pdf.CellFormat(0, 8, "No hay sesiones de asistencia en el periodo.", "", 1, "L", false, 0, "")
+	}
+	for desde := 0; desde < len(c.Sesiones); desde += pdfConsSesPorPag {
+		if desde > 0 {
+			pdf.AddPage()
+			pdfConsolidadoEncabezado(pdf, c)
+		}
+		hasta := desde + pdfConsSesPorPag
+		if hasta > len(c.Sesiones) {
+			hasta = len(c.Sesiones)
+		}
+		pdfConsolidadoMatriz(pdf, c, desde, hasta)
+	}
+	pdf.AddPage()
+	pdfConsolidadoTotales(pdf, c)
+	pdfConsolidadoFirmas(pdf, c)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("generar PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func renderConsolidadoXLSX(c *consolidadoAsistencia) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := "Consolidado"
+	_ = f.SetSheetName(f.GetSheetName(0), sheet)
+	bold, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	header, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"E6E6E6"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	falla, _ := f.NewStyle(&excelize.Style{
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"F8D7DA"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	centro, _ := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}})
+	cell := func(col, row int) string {
+		name, _ := excelize.CoordinatesToCellName(col, row)
+		return name
+	}
+
+	_ = f.SetCellValue(sheet, "A1", "REPORTE CONSOLIDADO DE ASISTENCIA")
+	_ = f.SetCellStyle(sheet, "A1", "A1", bold)
+	_ = f.SetCellValue(sheet, "A2", "Ficha")
+	_ = f.SetCellValue(sheet, "B2", c.FichaNumero)
+	_ = f.SetCellValue(sheet, "A3", "Programa")
+	_ = f.SetCellValue(sheet, "B3", c.Programa)
+	_ = f.SetCellValue(sheet, "A4", "Periodo")
+	_ = f.SetCellValue(sheet, "B4", c.Desde.Format(time.DateOnly)+" a "+c.Hasta.Format(time.DateOnly))
+	_ = f.SetCellValue(sheet, "A5", "Generado")
+	_ = f.SetCellValue(sheet, "B5", c.GeneradoEn.Format("2006-01-02 15:04"))
+
+	const filaEnc = 7
+	headers := []string{"Documento", "Aprendiz"}
+	for _, s := range c.Sesiones {
+		dia, hora := encabezadoSesion(s)
+		headers = append(headers, dia+"\n"+hora)
+	}
+	headers = append(headers, "Sesiones", "Asistió", "Parcial", "Abandono", "Justificadas", "Por corregir", "Fallas", "% Asistencia")
+	for i, h := range headers {
+		_ = f.SetCellValue(sheet, cell(i+1, filaEnc), h)
+	}
+	_ = f.SetCellStyle(sheet, cell(1, filaEnc), cell(len(headers), filaEnc), header)
+	_ = f.SetRowHeight(sheet, filaEnc, 30)
+
+	for r, fila := range c.Filas {
+		row := filaEnc + 1 + r
+		_ = f.SetCellValue(sheet, cell(1, row), fila.Documento)
+		_ = f.SetCellValue(sheet, cell(2, row), fila.Nombre)
+		for i, cod := range fila.Codigos {
+			_ = f.SetCellValue(sheet, cell(3+i, row), cod)
+			style := centro
+			if cod == codFalla || cod == codAbandono {
+				style = falla
+			}
+			_ = f.SetCellStyle(sheet, cell(3+i, row), cell(3+i, row), style)
+		}
+		col := 3 + len(fila.Codigos)
+		for i, v := range []int{len(fila.Codigos), fila.Asistencias - fila.Parciales, fila.Parciales, fila.Abandonos,
+			fila.Justificadas, fila.PorCorregir, fila.Fallas} {
+			_ = f.SetCellValue(sheet, cell(col+i, row), v)
+		}
+		_ = f.SetCellValue(sheet, cell(col+7, row), fmt.Sprintf("%.1f", fila.Porcentaje))
+	}
+	_ = f.SetColWidth(sheet, "A", "A", 16)
+	_ = f.SetColWidth(sheet, "B", "B", 36)
+	if len(c.Sesiones) > 0 {
+		first, _ := excelize.ColumnNumberToName(3)
+		last, _ := excelize.ColumnNumberToName(2 + len(c.Sesiones))
+		_ = f.SetColWidth(sheet, first, last, 7)
+	}
+	_ = f.SetPanes(sheet, &excelize.Panes{Freeze: true, XSplit: 2, YSplit: filaEnc, TopLeftCell: cell(3, filaEnc+1), ActivePane: "bottomRight"})
+
+	row := filaEnc + len(c.Filas) + 3
+	_ = f.SetCellValue(sheet, cell(1, row), "Convenciones")
+	_ = f.SetCellStyle(sheet, cell(1, row), cell(1, row), bold)
+	for _, l := range leyendaCodigosAsistencia {
+		row++
+		_ = f.SetCellValue(sheet, cell(1, row), l.codigo)
+		_ = f.SetCellValue(sheet, cell(2, row), l.descripcion)
+	}
+	if len(c.Firmas) > 0 {
+		row += 2
+		_ = f.SetCellValue(sheet, cell(1, row), "Firmas de los instructores")
+		_ = f.SetCellStyle(sheet, cell(1, row), cell(1, row), bold)
+		for _, firma := range c.Firmas {
+			row += 3
+			_ = f.SetCellValue(sheet, cell(2, row), "______________________________")
+			_ = f.SetCellValue(sheet, cell(2, row+1), fmt.Sprintf("%s — C.C. %s (%d sesiones)", firma.Nombre, firma.Documento, firma.Sesiones))
+			row++
+		}
+	}
+
+	ses := "Sesiones"
+	_, _ = f.NewSheet(ses)
+	for i, h := range []string{"Fecha", "Hora inicio", "Hora fin", "Instructor"} {
+		_ = f.SetCellValue(ses, cell(i+1, 1), h)
+	}
+	_ = f.SetCellStyle(ses, "A1", "D1", header)
+	for i, s := range c.Sesiones {
+		_ = f.SetCellValue(ses, cell(1, i+2), s.Fecha.Format(time.DateOnly))
+		_ = f.SetCellValue(ses, cell(2, i+2), formatTime(s.HoraInicio))
+		_ = f.SetCellValue(ses, cell(3, i+2), formatTime(s.HoraFin))
+		_ = f.SetCellValue(ses, cell(4, i+2), s.Instructor)
+	}
+	_ = f.SetColWidth(ses, "A", "C", 12)
+	_ = f.SetColWidth(ses, "D", "D", 36)
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("generar Excel: %w", err)
+	}
+	return buf.Bytes(), nil
+}
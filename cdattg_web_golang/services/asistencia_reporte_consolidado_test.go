@@ -0,0 +1,226 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+func aprendizReporte(id uint, nombre, doc string, activo bool) models.Aprendiz {
+	a := models.Aprendiz{Estado: activo, Persona: &models.Persona{PrimerNombre: nombre, NumeroDocumento: doc}}
+	a.ID = id
+	return a
+}
+
+func sesionReporte(id uint, fecha time.Time, hora int, registros ...models.AsistenciaAprendiz) models.Asistencia {
+	hi := fecha.Add(time.Duration(hora) * time.Hour)
+	s := models.Asistencia{InstructorFichaID: 7, Fecha: fecha, HoraInicio: &hi, AsistenciaAprendices: registros}
+	s.ID = id
+	return s
+}
+
+func registroReporte(aprendizID uint, estado string) models.AsistenciaAprendiz {
+	ing := time.Date(2026, 3, 2, 7, 5, 0, 0, time.Local)
+	return models.AsistenciaAprendiz{AprendizFichaID: aprendizID, HoraIngreso: &ing, Estado: estado}
+}
+
+func consolidadoPrueba() *consolidadoAsistencia {
+	d1 := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	d2 := d1.AddDate(0, 0, 1)
+	retirado := aprendizReporte(3, "Zoe", "300", false)
+	reg := registroReporte(3, "")
+	reg.Aprendiz = &retirado
+	sesiones := []models.Asistencia{
+		sesionReporte(11, d2, 7, registroReporte(1, "ASISTENCIA_PARCIAL")),
+		sesionReporte(10, d1, 7, registroReporte(1, "ASISTENCIA_COMPLETA"), registroReporte(2, "ABANDONO_JORNADA"), reg),
+		sesionReporte(12, d2, 13, registroReporte(1, "")),
+	}
+	aprendices := []models.Aprendiz{
+		aprendizReporte(1, "Ángela", "100", true),
+		aprendizReporte(2, "Beatriz", "200", true),
+		retirado,
+		aprendizReporte(4, "Inactivo", "400", false),
+	}
+	justificadas := map[[2]uint]bool{claveSesionAprendiz(11, 2): true}
+	nombres := func(uint) (string, string) { return "Instructor Peña", "900" }
+	c := &consolidadoAsistencia{FichaNumero: "2900001", Programa: "Programación", Desde: d1, Hasta: d2, GeneradoEn: d2}
+	c.Sesiones, c.Filas, c.Firmas = armarConsolidado(sesiones, aprendices, justificadas, nombres)
+	return c
+}
+
+func TestArmarConsolidado(t *testing.T) {
+	t.Parallel()
+	c := consolidadoPrueba()
+	if len(c.Sesiones) != 3 || c.Sesiones[0].ID != 10 || c.Sesiones[1].ID != 11 || c.Sesiones[2].ID != 12 {
+		t.Fatalf("sesiones fuera de orden: %+v", c.Sesiones)
+	}
+	if len(c.Filas) != 3 {
+		t.Fatalf("filas = %d, want 3 (activos + retirado con registro)", len(c.Filas))
+	}
+	byDoc := map[string]consolidadoFila{}
+	for _, f := range c.Filas {
+		byDoc[f.Documento] = f
+	}
+	if c.Filas[0].Documento != "100" || c.Filas[2].Documento != "300" {
+		t.Errorf("orden de filas: %s, %s, %s", c.Filas[0].Nombre, c.Filas[1].Nombre, c.Filas[2].Nombre)
+	}
+	if f := byDoc["100"]; f.Asistencias != 3 || f.Parciales != 1 || f.Porcentaje != 100 {
+		t.Errorf("Ángela = %+v", f)
+	}
+	f := byDoc["200"]
+	if got := f.Codigos; got[0] != codAbandono || got[1] != codJustificada || got[2] != codFalla {
+		t.Errorf("códigos Beatriz = %v", got)
+	}
+	if f.Porcentaje != 0 || f.Fallas != 1 || f.Justificadas != 1 {
+		t.Errorf("Beatriz = %+v", f)
+	}
+	if f := byDoc["300"]; math.Abs(f.Porcentaje-100.0/3) > 0.01 {
+		t.Errorf("retirado porcentaje = %v", f.Porcentaje)
+	}
+	if len(c.Firmas) != 1 || c.Firmas[0].Sesiones != 3 {
+		t.Errorf("firmas = %+v", c.Firmas)
+	}
+}
+
+func TestTotalizarFila_SoloJustificadas(t *testing.T) {
+	t.Parallel()
+	f := consolidadoFila{Codigos: []string{codJustificada, codPorCorregir}}
+	totalizarFila(&f)
+	if f.Porcentaje != 100 || f.Justificadas != 1 || f.PorCorregir != 1 {
+		t.Errorf("fila = %+v", f)
+	}
+}
+
+func TestRenderConsolidado(t *testing.T) {
+	t.Parallel()
+	c := consolidadoPrueba()
+	pdf, err := renderConsolidadoPDF(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Error("el PDF no tiene cabecera válida")
+	}
+	data, err := renderConsolidadoXLSX(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := f.GetCellValue("Consolidado", "B8"); v != "Ángela" {
+		t.Errorf("B8 = %q, want Ángela", v)
+	}
+	if v, _ := f.GetCellValue("Consolidado", "D9"); v != codJustificada {
+		t.Errorf("D9 = %q, want J", v)
+	}
+	if v, _ := f.GetCellValue("Sesiones", "D2"); v != "Instructor Peña" {
+		t.Errorf("Sesiones!D2 = %q", v)
+	}
+}
+
+func TestParseRangoReporte(t *testing.T) {
+	t.Parallel()
+	if _, _, err := parseRangoReporte("2026-03-10", "2026-03-01"); !errors.Is(err, ErrReporteRangoInvalido) {
+		t.Errorf("rango invertido: %v", err)
+	}
+	if _, _, err := parseRangoReporte("2025-01-01", "2026-06-01"); !errors.Is(err, ErrReporteRangoInvalido) {
+		t.Errorf("rango demasiado largo: %v", err)
+	}
+	if _, _, err := parseRangoReporte("2026-01-01", "2026-06-30"); err != nil {
+		t.Errorf("semestre: %v", err)
+	}
+}
+
+// reporteRepoFake guarda reportes en memoria y aplica Finalizar solo con la reserva vigente, como el real.
+type reporteRepoFake struct {
+	repositories.ReporteAsistenciaRepository
+	reportes map[uint]models.ReporteAsistencia
+	archivos map[uint][]byte
+}
+
+func (r *reporteRepoFake) FindByID(id uint) (*models.ReporteAsistencia, error) {
+	rep, ok := r.reportes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &rep, nil
+}
+
+func (r *reporteRepoFake) FindArchivo(reporteID uint) (*models.ReporteAsistenciaArchivo, error) {
+	datos, ok := r.archivos[reporteID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.ReporteAsistenciaArchivo{ReporteID: reporteID, Contenido: datos}, nil
+}
+
+func (r *reporteRepoFake) Finalizar(rep *models.ReporteAsistencia, archivo *models.ReporteAsistenciaArchivo) (bool, error) {
+	actual := r.reportes[rep.ID]
+	if actual.Intentos != rep.Intentos || actual.Estado != models.ReporteAsistenciaProcesando {
+		return false, nil
+	}
+	r.reportes[rep.ID] = *rep
+	if archivo != nil {
+		r.archivos[rep.ID] = archivo.Contenido
+	}
+	return true, nil
+}
+
+func reporteEnProceso(intentos int) models.ReporteAsistencia {
+	rep := models.ReporteAsistencia{FichaID: 7, Formato: FormatoReportePDF, Estado: models.ReporteAsistenciaProcesando, Intentos: intentos}
+	rep.ID = 1
+	return rep
+}
+
+// Una réplica que perdió la reserva no pisa el resultado de la que retomó el reporte.
+func TestReporteFinalizar_soloConReservaVigente(t *testing.T) {
+	t.Parallel()
+	repo := &reporteRepoFake{reportes: map[uint]models.ReporteAsistencia{1: reporteEnProceso(2)}, archivos: map[uint][]byte{}}
+	s := &ReporteAsistenciaService{reporteRepo: repo}
+	archivo := &ArchivoReporteAsistencia{Nombre: "r.pdf", Datos: []byte("%PDF")}
+
+	vencida := reporteEnProceso(1)
+	s.finalizar(&vencida, archivo, 3, nil)
+	if repo.reportes[1].Estado != models.ReporteAsistenciaProcesando || len(repo.archivos) != 0 {
+		t.Fatalf("intento vencido aplicado: %+v", repo.reportes[1])
+	}
+	if _, err := s.Archivo(7, 1); !errors.Is(err, ErrReporteNoDisponible) {
+		t.Errorf("Archivo en proceso: err = %v", err)
+	}
+
+	vigente := reporteEnProceso(2)
+	s.finalizar(&vigente, archivo, 3, nil)
+	if got := repo.reportes[1]; got.Estado != models.ReporteAsistenciaCompletado || got.Sesiones != 3 || got.FinalizadoAt == nil {
+		t.Fatalf("reporte = %+v", got)
+	}
+	out, err := s.Archivo(7, 1)
+	if err != nil || string(out.Datos) != "%PDF" || out.Nombre != "r.pdf" {
+		t.Fatalf("Archivo = %+v, %v", out, err)
+	}
+	if _, err := s.Archivo(8, 1); !errors.Is(err, ErrReporteNoEncontrado) {
+		t.Errorf("otra ficha: err = %v", err)
+	}
+}
+
+// Un reporte que interrumpió varias réplicas se da por fallido sin volver a generarlo.
+func TestReporteEjecutar_intentosAgotados(t *testing.T) {
+	t.Parallel()
+	repo := &reporteRepoFake{
+		reportes: map[uint]models.ReporteAsistencia{1: reporteEnProceso(maxIntentosReporteAsistencia + 1)},
+		archivos: map[uint][]byte{},
+	}
+	s := &ReporteAsistenciaService{reporteRepo: repo}
+	s.ejecutar(1)
+	if got := repo.reportes[1]; got.Estado != models.ReporteAsistenciaError || got.Error == "" {
+		t.Fatalf("reporte = %+v", got)
+	}
+}
@@ -3,12 +3,14 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
 )
 
 const (
@@ -54,6 +56,8 @@ type AsistenciaService interface {
 	RegistrarIngreso(req dto.AsistenciaAprendizRequest, instructorFichaIDRegistroIngreso *uint) (*dto.AsistenciaAprendizResponse, error)
 	RegistrarIngresoPorDocumento(req dto.AsistenciaIngresoPorDocumentoRequest, instructorFichaIDRegistroIngreso *uint) (*dto.AsistenciaAprendizResponse, error)
 	RegistrarSalida(asistenciaAprendizID uint, instructorFichaIDRegistroSalida *uint) (*dto.AsistenciaAprendizResponse, error)
+	CodigoQRSesion(asistenciaID uint, instructorFichaID *uint) (*dto.AsistenciaCodigoQRResponse, error)
+	RegistrarPorCodigoQR(personaID uint, codigo string) (*dto.AsistenciaAprendizResponse, error)
 	ActualizarObservaciones(asistenciaAprendizID uint, observaciones string) (*dto.AsistenciaAprendizResponse, error)
 	CrearOActualizarObservaciones(asistenciaID, aprendizID uint, observaciones string, tipoObservacionIDs []uint) (*dto.AsistenciaAprendizResponse, error)
 	ListAprendicesEnSesion(asistenciaID uint) ([]dto.AsistenciaAprendizResponse, error)
@@ -61,28 +65,33 @@ type AsistenciaService interface {
 	CrearTipoObservacionAsistencia(req dto.TipoObservacionAsistenciaCreateRequest) (*dto.TipoObservacionAsistenciaItem, error)
 	ActualizarTipoObservacionAsistencia(id uint, req dto.TipoObservacionAsistenciaUpdateRequest) (*dto.TipoObservacionAsistenciaItem, error)
 	EliminarTipoObservacionAsistencia(id uint) error
-	EliminarRegistroAprendiz(asistenciaAprendizID uint) error
+	EliminarRegistroAprendiz(actor dto.Actor, asistenciaAprendizID uint) error
 	GetDashboard(sedeID *uint, fecha string) (*dto.AsistenciaDashboardResponse, error)
 	GetCasosBienestar(sedeID *uint, dias, minFallas int) (*dto.CasosBienestarResponse, error)
 	GetDetalleInasistenciasAprendiz(fichaNumero string, aprendizID uint, dias int, sedeNombre string) (*dto.CasoBienestarAprendizDetalleResponse, error)
 	GetMisInasistencias(personaID uint, dias int) (*dto.MisInasistenciasResponse, error)
 	GetSesionesSinAsistenciaTomada(userID uint, roles []string, dias int, regionalID, sedeID *uint) (*dto.SesionesSinAsistenciaTomadaResponse, error)
-	AjustarEstadoAprendiz(asistenciaAprendizID uint, estado, motivo string, instructorFichaIDRegistroSalida *uint) (*dto.AsistenciaAprendizResponse, error)
+	AjustarEstadoAprendiz(actor dto.Actor, asistenciaAprendizID uint, estado, motivo string, instructorFichaIDRegistroSalida *uint) (*dto.AsistenciaAprendizResponse, error)
 	ListPendientesRevision(instructorID uint, fecha string) ([]dto.AsistenciaAprendizResponse, error)
-	FinalizarSesionesVencidas()
+	// FinalizarSesionesVencidas devuelve los IDs de las sesiones que cerró.
+	FinalizarSesionesVencidas() ([]uint, error)
 }
 
 type asistenciaService struct {
-	repo          repositories.AsistenciaRepository
-	repoAA        repositories.AsistenciaAprendizRepository
-	tipoObsRepo   repositories.TipoObservacionAsistenciaRepository
-	instFichaRepo repositories.InstructorFichaRepository
-	instRepo      repositories.InstructorRepository
-	personaRepo   repositories.PersonaRepository
-	aprendizRepo  repositories.AprendizRepository
-	evidenciaRepo repositories.EvidenciaRepository
-	fichaRepo     repositories.FichaRepository
-	horarioSvc    *InstructorHorarioService
+	repo           repositories.AsistenciaRepository
+	repoAA         repositories.AsistenciaAprendizRepository
+	tipoObsRepo    repositories.TipoObservacionAsistenciaRepository
+	instFichaRepo  repositories.InstructorFichaRepository
+	instRepo       repositories.InstructorRepository
+	personaRepo    repositories.PersonaRepository
+	aprendizRepo   repositories.AprendizRepository
+	evidenciaRepo  repositories.EvidenciaRepository
+	fichaRepo      repositories.FichaRepository
+	horarioSvc     *InstructorHorarioService
+	qrUsoRepo      repositories.AsistenciaQRUsoRepository
+	userRepo       repositories.UserRepository
+	correos        CorreoService
+	notificaciones NotificacionUsuarioService
 }
 
 // esSesionDeHoy indica si la fecha de la sesión (interpretada en hora local) es el día de hoy.
@@ -111,16 +120,20 @@ func sesionCerradaDentroDePlazoEdicion(fechaSesion time.Time, ahora time.Time, d
 
 func NewAsistenciaService() AsistenciaService {
 	return &asistenciaService{
-		repo:          repositories.NewAsistenciaRepository(),
-		repoAA:        repositories.NewAsistenciaAprendizRepository(),
-		tipoObsRepo:   repositories.NewTipoObservacionAsistenciaRepository(),
-		instFichaRepo: repositories.NewInstructorFichaRepository(),
-		instRepo:      repositories.NewInstructorRepository(),
-		personaRepo:   repositories.NewPersonaRepository(),
-		aprendizRepo:  repositories.NewAprendizRepository(),
-		evidenciaRepo: repositories.NewEvidenciaRepository(),
-		fichaRepo:     repositories.NewFichaRepository(),
-		horarioSvc:    NewInstructorHorarioService(),
+		repo:           repositories.NewAsistenciaRepository(),
+		repoAA:         repositories.NewAsistenciaAprendizRepository(),
+		tipoObsRepo:    repositories.NewTipoObservacionAsistenciaRepository(),
+		instFichaRepo:  repositories.NewInstructorFichaRepository(),
+		instRepo:       repositories.NewInstructorRepository(),
+		personaRepo:    repositories.NewPersonaRepository(),
+		aprendizRepo:   repositories.NewAprendizRepository(),
+		evidenciaRepo:  repositories.NewEvidenciaRepository(),
+		fichaRepo:      repositories.NewFichaRepository(),
+		horarioSvc:     NewInstructorHorarioService(),
+		qrUsoRepo:      repositories.NewAsistenciaQRUsoRepository(),
+		userRepo:       repositories.NewUserRepository(),
+		correos:        NewCorreoService(),
+		notificaciones: NewNotificacionUsuarioService(),
 	}
 }
 
@@ -264,10 +277,15 @@ func (s *asistenciaService) EntrarTomarAsistencia(instructorID uint, fichaID uin
 		return s.asistenciaToResponse(sesionHoy), nil
 	}
 	hoy := now.Format(time.DateOnly)
-	return s.CreateSesion(dto.AsistenciaRequest{
+	resp, err := s.CreateSesion(dto.AsistenciaRequest{
 		InstructorFichaID: ifc.ID,
 		Fecha:             hoy,
 	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Nueva = true
+	return resp, nil
 }
 
 func (s *asistenciaService) GetByID(id uint) (*dto.AsistenciaResponse, error) {
@@ -353,11 +371,17 @@ func (s *asistenciaService) Finalizar(id uint) (*dto.AsistenciaResponse, error)
 	if err := s.repo.Update(a); err != nil {
 		return nil, err
 	}
-	// Generar reporte (asistieron / no asistieron) y guardar en storage/asistencia_pdfs
+	if err := s.qrUsoRepo.DeleteByAsistenciaID(id); err != nil {
+		log.Printf("[asistencia] limpiando códigos QR usados de la sesión %d: %v", id, err)
+	}
+	// Generar reporte (asistieron / no asistieron), guardar en storage/asistencia_pdfs y enviarlo al instructor
 	if a2, errLoad := s.repo.FindByID(id); errLoad == nil && a2.InstructorFicha != nil {
 		fichaID := a2.InstructorFicha.FichaID
 		if aprendices, errAp := CargarAprendicesFichaParaReporte(fichaID); errAp == nil {
-			_, _ = GenerateReporteFinalizacion(a2, aprendices)
+			if ruta, errPDF := GenerateReporteFinalizacion(a2, aprendices); errPDF == nil {
+				s.enviarReporteSesionInstructor(a2, aprendices, ruta)
+			}
+			s.notificarInasistencias(a2, aprendices)
 		}
 	}
 	return s.GetByID(id)
@@ -365,13 +389,15 @@ func (s *asistenciaService) Finalizar(id uint) (*dto.AsistenciaResponse, error)
 
 // FinalizarSesionesVencidas finaliza sesiones no cerradas cuyo horario de jornada (hora_fin + extensión) ya pasó.
 // Se ejecuta de forma periódica (p. ej. cada 5 min). La finalización es automática; los instructores no pueden finalizar manualmente.
-func (s *asistenciaService) FinalizarSesionesVencidas() {
+func (s *asistenciaService) FinalizarSesionesVencidas() ([]uint, error) {
 	now := time.Now()
 	fechaDesde := now.AddDate(0, 0, -1).Format(time.DateOnly)
 	list, err := s.repo.FindSesionesNoFinalizadasDesde(fechaDesde)
-	if err != nil || len(list) == 0 {
-		return
+	if err != nil {
+		return nil, err
 	}
+	var cerradas []uint
+	fallidas := 0
 	for i := range list {
 		a := &list[i]
 		if a.InstructorFicha == nil || a.InstructorFicha.Ficha == nil {
@@ -384,9 +410,17 @@ func (s *asistenciaService) FinalizarSesionesVencidas() {
 		sessionDate := time.Date(localFecha.Year(), localFecha.Month(), localFecha.Day(), 0, 0, 0, 0, now.Location())
 		endEffective := HoraFinEfectivaParaSesion(j, ficha, sessionDate)
 		if now.After(endEffective) {
-			_, _ = s.Finalizar(a.ID)
+			if _, errFin := s.Finalizar(a.ID); errFin != nil {
+				fallidas++
+			} else {
+				cerradas = append(cerradas, a.ID)
+			}
 		}
 	}
+	if fallidas > 0 {
+		return cerradas, fmt.Errorf("%d sesiones vencidas no pudieron finalizarse", fallidas)
+	}
+	return cerradas, nil
 }
 
 func (s *asistenciaService) RegistrarIngreso(req dto.AsistenciaAprendizRequest, instructorFichaIDRegistroIngreso *uint) (*dto.AsistenciaAprendizResponse, error) {
@@ -548,14 +582,82 @@ func (s *asistenciaService) RegistrarIngresoPorDocumento(req dto.AsistenciaIngre
 	if err != nil {
 		return nil, err
 	}
+	return s.registrarIngresoOSalidaAprendiz(asist.ID, fichaID, aprendizID, instructorFichaIDRegistroIngreso)
+}
+
+// registrarIngresoOSalidaAprendiz infiere la acción del escaneo: salida si el aprendiz tiene un tramo abierto hoy en
+// la ficha (respetando el minuto mínimo desde la entrada), ingreso en caso contrario.
+func (s *asistenciaService) registrarIngresoOSalidaAprendiz(
+	asistenciaID, fichaID, aprendizID uint,
+	instructorFichaID *uint,
+) (*dto.AsistenciaAprendizResponse, error) {
 	if sinSalida := s.tramoAbiertoAprendizEnFichaHoy(fichaID, aprendizID); sinSalida != nil {
 		if sinSalida.HoraIngreso != nil && time.Since(*sinSalida.HoraIngreso) < minSegundosEntreIngresoYSalida*time.Second {
 			rest := segundosRestantesParaSalida(*sinSalida.HoraIngreso, time.Now())
 			return s.respuestaIngresoAbierto(sinSalida, mensajeSalidaQRDemasiadoPronto(rest), rest)
 		}
-		return s.registrarSalidaPorDocumento(sinSalida, instructorFichaIDRegistroIngreso)
+		return s.registrarSalidaPorDocumento(sinSalida, instructorFichaID)
 	}
-	return s.registrarIngresoPorDocumentoAccion(req.AsistenciaID, aprendizID, nil, instructorFichaIDRegistroIngreso)
+	return s.registrarIngresoPorDocumentoAccion(asistenciaID, aprendizID, nil, instructorFichaID)
+}
+
+// CodigoQRSesion código rotativo firmado de la sesión abierta, para que los aprendices lo escaneen desde su cuenta.
+func (s *asistenciaService) CodigoQRSesion(asistenciaID uint, instructorFichaID *uint) (*dto.AsistenciaCodigoQRResponse, error) {
+	asist, err := s.sesionActivaPorID(asistenciaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assertSesionPropia(asist, instructorFichaID); err != nil {
+		return nil, err
+	}
+	ahora := utils.Now()
+	codigo, siguiente := generarCodigoQRAsistencia(claveCodigoQRAsistencia(), asist.ID, ahora)
+	svg, err := utils.QRSVG(codigo, tamModuloSVGCodigoQR)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AsistenciaCodigoQRResponse{
+		AsistenciaID:      asist.ID,
+		Codigo:            codigo,
+		SVG:               svg,
+		PeriodoSegundos:   periodoCodigoQRAsistencia,
+		ExpiraAt:          siguiente,
+		SegundosRestantes: int((siguiente.Sub(ahora) + time.Second - 1) / time.Second),
+	}, nil
+}
+
+// RegistrarPorCodigoQR autorregistro del aprendiz autenticado con el código que muestra el instructor. El registro
+// queda a nombre del instructor dueño de la sesión; cada código solo sirve una vez por aprendiz.
+func (s *asistenciaService) RegistrarPorCodigoQR(personaID uint, codigo string) (*dto.AsistenciaAprendizResponse, error) {
+	asistenciaID, ventana, err := verificarCodigoQRAsistencia(claveCodigoQRAsistencia(), codigo, utils.Now())
+	if err != nil {
+		return nil, err
+	}
+	asist, err := s.sesionActivaPorID(asistenciaID)
+	if err != nil {
+		return nil, err
+	}
+	fichaID := fichaIDDesdeAsistencia(asist, s.instFichaRepo)
+	aprendiz, err := s.aprendizRepo.FindByPersonaIDAndFichaID(personaID, fichaID)
+	if err != nil || aprendiz == nil {
+		return nil, errors.New("no está registrado como aprendiz de la ficha de esta sesión")
+	}
+	if err := s.validarAprendizPuedeTomarAsistencia(aprendiz.ID); err != nil {
+		return nil, err
+	}
+	nuevo, err := s.qrUsoRepo.Registrar(&models.AsistenciaQRUso{
+		AsistenciaID: asist.ID,
+		AprendizID:   aprendiz.ID,
+		Ventana:      ventana,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error al registrar el uso del código: %w", err)
+	}
+	if !nuevo {
+		return nil, ErrCodigoQRUsado
+	}
+	instructorFichaID := asist.InstructorFichaID
+	return s.registrarIngresoOSalidaAprendiz(asist.ID, fichaID, aprendiz.ID, &instructorFichaID)
 }
 
 func (s *asistenciaService) RegistrarSalida(asistenciaAprendizID uint, instructorFichaIDRegistroSalida *uint) (*dto.AsistenciaAprendizResponse, error) {
@@ -647,6 +749,14 @@ func (s *asistenciaService) CrearOActualizarObservaciones(asistenciaID, aprendiz
 	return s.GetAsistenciaAprendizByID(aa.ID)
 }
 
+func tipoObservacionAsistenciaItem(t *models.TipoObservacionAsistencia) dto.TipoObservacionAsistenciaItem {
+	item := dto.TipoObservacionAsistenciaItem{ID: t.ID, Codigo: t.Codigo, Nombre: t.Nombre}
+	if t.PesoRiesgo != nil {
+		item.PesoRiesgo = *t.PesoRiesgo
+	}
+	return item
+}
+
 func (s *asistenciaService) ListTiposObservacionAsistencia() ([]dto.TipoObservacionAsistenciaItem, error) {
 	list, err := s.tipoObsRepo.ListActivos()
 	if err != nil {
@@ -654,7 +764,7 @@ func (s *asistenciaService) ListTiposObservacionAsistencia() ([]dto.TipoObservac
 	}
 	out := make([]dto.TipoObservacionAsistenciaItem, len(list))
 	for i := range list {
-		out[i] = dto.TipoObservacionAsistenciaItem{ID: list[i].ID, Codigo: list[i].Codigo, Nombre: list[i].Nombre}
+		out[i] = tipoObservacionAsistenciaItem(&list[i])
 	}
 	return out, nil
 }
@@ -672,17 +782,21 @@ func (s *asistenciaService) CrearTipoObservacionAsistencia(req dto.TipoObservaci
 	if req.Activo != nil {
 		activo = *req.Activo
 	}
+	peso := 0
+	if req.PesoRiesgo != nil {
+		peso = *req.PesoRiesgo
+	}
 	item := &models.TipoObservacionAsistencia{
-		Codigo: strings.ToUpper(codigo),
-		Nombre: nombre,
-		Activo: activo,
+		Codigo:     strings.ToUpper(codigo),
+		Nombre:     nombre,
+		Activo:     activo,
+		PesoRiesgo: &peso,
 	}
 	if err := s.tipoObsRepo.Create(item); err != nil {
 		return nil, err
 	}
-	return &dto.TipoObservacionAsistenciaItem{
-		ID: item.ID, Codigo: item.Codigo, Nombre: item.Nombre,
-	}, nil
+	resp := tipoObservacionAsistenciaItem(item)
+	return &resp, nil
 }
 
 func (s *asistenciaService) ActualizarTipoObservacionAsistencia(id uint, req dto.TipoObservacionAsistenciaUpdateRequest) (*dto.TipoObservacionAsistenciaItem, error) {
@@ -706,12 +820,14 @@ func (s *asistenciaService) ActualizarTipoObservacionAsistencia(id uint, req dto
 	if req.Activo != nil {
 		item.Activo = *req.Activo
 	}
+	if req.PesoRiesgo != nil {
+		item.PesoRiesgo = req.PesoRiesgo
+	}
 	if err := s.tipoObsRepo.Update(item); err != nil {
 		return nil, err
 	}
-	return &dto.TipoObservacionAsistenciaItem{
-		ID: item.ID, Codigo: item.Codigo, Nombre: item.Nombre,
-	}, nil
+	resp := tipoObservacionAsistenciaItem(item)
+	return &resp, nil
 }
 
 func (s *asistenciaService) EliminarTipoObservacionAsistencia(id uint) error {
@@ -726,7 +842,7 @@ func (s *asistenciaService) EliminarTipoObservacionAsistencia(id uint) error {
 	return s.tipoObsRepo.Update(item)
 }
 
-func (s *asistenciaService) EliminarRegistroAprendiz(asistenciaAprendizID uint) error {
+func (s *asistenciaService) EliminarRegistroAprendiz(actor dto.Actor, asistenciaAprendizID uint) error {
 	if asistenciaAprendizID == 0 {
 		return errors.New(errMsgIDInvalido)
 	}
@@ -737,7 +853,12 @@ func (s *asistenciaService) EliminarRegistroAprendiz(asistenciaAprendizID uint)
 	if aa.Asistencia != nil && aa.Asistencia.IsFinished {
 		return errors.New(errMsgSesionYaFinalizada)
 	}
-	return s.repoAA.Delete(asistenciaAprendizID)
+	antes := s.aaToResponse(aa)
+	if err := s.repoAA.Delete(asistenciaAprendizID); err != nil {
+		return err
+	}
+	auditar(actor, AccionAsistenciaEliminarReg, tablaAsistenciaAprendices, asistenciaAprendizID, antes, nil)
+	return nil
 }
 
 func (s *asistenciaService) ListAprendicesEnSesion(asistenciaID uint) ([]dto.AsistenciaAprendizResponse, error) {
@@ -889,6 +1010,7 @@ func (s *asistenciaService) GetDetalleInasistenciasAprendiz(fichaNumero string,
 	}
 	for i := range sinJustificar {
 		resp.Inasistencias[i] = dto.InasistenciaDetalleItem{
+			AsistenciaIDs:    sinJustificar[i].AsistenciaIDs,
 			Fecha:            sinJustificar[i].Fecha,
 			InstructorNombre: sinJustificar[i].InstructorNombre,
 			Observaciones:    sinJustificar[i].Observaciones,
@@ -896,6 +1018,7 @@ func (s *asistenciaService) GetDetalleInasistenciasAprendiz(fichaNumero string,
 	}
 	for i := range justificadas {
 		resp.InasistenciasJustificadas[i] = dto.InasistenciaDetalleItem{
+			AsistenciaIDs:    justificadas[i].AsistenciaIDs,
 			Fecha:            justificadas[i].Fecha,
 			InstructorNombre: justificadas[i].InstructorNombre,
 			Observaciones:    justificadas[i].Observaciones,
@@ -1057,7 +1180,7 @@ func (s *asistenciaService) aaToResponse(aa *models.AsistenciaAprendiz) *dto.Asi
 	if len(aa.TiposObservacion) > 0 {
 		r.TiposObservacion = make([]dto.TipoObservacionAsistenciaItem, len(aa.TiposObservacion))
 		for i := range aa.TiposObservacion {
-			r.TiposObservacion[i] = dto.TipoObservacionAsistenciaItem{ID: aa.TiposObservacion[i].ID, Codigo: aa.TiposObservacion[i].Codigo, Nombre: aa.TiposObservacion[i].Nombre}
+			r.TiposObservacion[i] = tipoObservacionAsistenciaItem(&aa.TiposObservacion[i])
 		}
 	}
 	if aa.Asistencia != nil && aa.Asistencia.InstructorFicha != nil && aa.Asistencia.InstructorFicha.Ficha != nil {
@@ -1079,7 +1202,7 @@ func (s *asistenciaService) aaToResponse(aa *models.AsistenciaAprendiz) *dto.Asi
 
 // AjustarEstadoAprendiz permite clasificar un registro de asistencia de aprendiz
 // como asistencia completa, parcial, abandono de jornada o dejarlo pendiente de revisión.
-func (s *asistenciaService) AjustarEstadoAprendiz(asistenciaAprendizID uint, estado, motivo string, instructorFichaIDRegistroSalida *uint) (*dto.AsistenciaAprendizResponse, error) {
+func (s *asistenciaService) AjustarEstadoAprendiz(actor dto.Actor, asistenciaAprendizID uint, estado, motivo string, instructorFichaIDRegistroSalida *uint) (*dto.AsistenciaAprendizResponse, error) {
 	aa, err := s.repoAA.FindByID(asistenciaAprendizID)
 	if err != nil {
 		return nil, errors.New(errMsgRegistroAsistenciaNoEncontrado)
@@ -1093,6 +1216,7 @@ func (s *asistenciaService) AjustarEstadoAprendiz(asistenciaAprendizID uint, est
 	default:
 		return nil, errors.New("estado de asistencia inválido")
 	}
+	antes := s.aaToResponse(aa)
 	aa.Estado = normalized
 	aa.MotivoAjuste = motivo
 	aa.RequiereRevision = normalized == "REGISTRO_POR_CORREGIR"
@@ -1111,7 +1235,9 @@ func (s *asistenciaService) AjustarEstadoAprendiz(asistenciaAprendizID uint, est
 	if err := s.repoAA.Update(aa); err != nil {
 		return nil, err
 	}
-	return s.aaToResponse(aa), nil
+	resp := s.aaToResponse(aa)
+	auditar(actor, AccionAsistenciaAjustarEstado, tablaAsistenciaAprendices, asistenciaAprendizID, antes, resp)
+	return resp, nil
 }
 
 // ListPendientesRevision devuelve los registros de asistencia de aprendices
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+// Acciones registradas por los servicios en registro_actividades.
+const (
+	AccionFichaCrear              = "FICHA_CREAR"
+	AccionFichaActualizar         = "FICHA_ACTUALIZAR"
+	AccionFichaEliminar           = "FICHA_ELIMINAR"
+	AccionPersonaCrear            = "PERSONA_CREAR"
+	AccionPersonaActualizar       = "PERSONA_ACTUALIZAR"
+	AccionPersonaEliminar         = "PERSONA_ELIMINAR"
+	AccionAsistenciaAjustarEstado = "ASISTENCIA_AJUSTAR_ESTADO"
+	AccionAsistenciaEliminarReg   = "ASISTENCIA_ELIMINAR_REGISTRO"
+	AccionPermisoAsignar          = "PERMISO_ASIGNAR"
+	AccionPermisoQuitar           = "PERMISO_QUITAR"
+	AccionRolesActualizar         = "ROLES_ACTUALIZAR"
+	AccionUsuarioEstado           = "USUARIO_ESTADO"
+	AccionUsuarioRegionales       = "USUARIO_REGIONALES"
+	AccionUsuarioDesbloquearLogin = "USUARIO_DESBLOQUEAR_LOGIN"
+	AccionUsuarioRestablecerPass  = "USUARIO_RESTABLECER_PASSWORD"
+	AccionUsuarioVerificarEmail   = "USUARIO_VERIFICAR_EMAIL"
+	AccionJornadaActualizar       = "JORNADA_ACTUALIZAR"
+	AccionJornadaPropagar         = "JORNADA_PROPAGAR"
+	AccionExcusaRevisar           = "EXCUSA_REVISAR"
+	AccionJuiciosRegistrar        = "JUICIOS_REGISTRAR"
+	AccionCasoBienestarCrear      = "CASO_BIENESTAR_CREAR"
+	AccionCasoBienestarActualizar = "CASO_BIENESTAR_ACTUALIZAR"
+	AccionCorreoReintentar        = "CORREO_REINTENTAR"
+)
+
+// Tablas auditadas (registro_actividades.tabla).
+const (
+	tablaFichas               = "fichas_caracterizacion"
+	tablaPersonas             = "personas"
+	tablaAsistenciaAprendices = "asistencia_aprendices"
+	tablaUsers                = "users"
+	tablaJornadas             = "jornadas"
+	tablaExcusasInasistencia  = "excusas_inasistencia"
+	tablaCasosBienestar       = "casos_bienestar"
+	tablaCorreosSalientes     = "correos_salientes"
+)
+
+// camposAuditoriaIgnorados no se reportan como cambios (los mueve cualquier guardado).
+var camposAuditoriaIgnorados = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+type AuditoriaService interface {
+	List(f repositories.RegistroActividadFiltro) ([]dto.RegistroActividadResponse, int64, error)
+}
+
+type auditoriaService struct {
+	repo repositories.RegistroActividadRepository
+}
+
+func NewAuditoriaService() AuditoriaService {
+	return &auditoriaService{repo: repositories.NewRegistroActividadRepository()}
+}
+
+func (s *auditoriaService) List(f repositories.RegistroActividadFiltro) ([]dto.RegistroActividadResponse, int64, error) {
+	list, total, err := s.repo.List(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]dto.RegistroActividadResponse, len(list))
+	for i := range list {
+		r := &list[i]
+		out[i] = dto.RegistroActividadResponse{
+			ID:          r.ID,
+			UserID:      r.UserID,
+			Accion:      r.Accion,
+			Tabla:       r.Tabla,
+			RegistroID:  r.RegistroID,
+			Detalles:    detallesAuditoria(r.Detalles),
+			FechaAccion: r.FechaAccion,
+			IPAddress:   r.IPAddress,
+		}
+		if r.User != nil {
+			out[i].UserEmail = r.User.Email
+		}
+	}
+	return out, total, nil
+}
+
+// detallesAuditoria devuelve los detalles como JSON si lo son (registros nuevos) o como texto (históricos).
+func detallesAuditoria(raw string) interface{} {
+	if raw == "" {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return raw
+	}
+	return v
+}
+
+// auditar guarda en registro_actividades quién hizo qué, con el estado antes y después y los campos
+// cambiados. Es best-effort: si falla se deja en log y no se revierte la operación auditada.
+func auditar(actor dto.Actor, accion, tabla string, registroID uint, antes, despues interface{}) {
+	if database.GetDB() == nil || actor.UserID == 0 {
+		return
+	}
+	antes, despues = valorAuditoria(antes), valorAuditoria(despues)
+	detalle := dto.AuditoriaDetalle{
+		Antes:   antes,
+		Despues: despues,
+		Cambios: diffAuditoria(antes, despues),
+	}
+	payload, err := json.Marshal(detalle)
+	if err != nil {
+		log.Printf("[auditoria] %s: no se pudo serializar detalle: %v", accion, err)
+		return
+	}
+	reg := &models.RegistroActividades{
+		UserID:      actor.UserID,
+		Accion:      accion,
+		Tabla:       tabla,
+		Detalles:    string(payload),
+		FechaAccion: time.Now(),
+		IPAddress:   actor.IP,
+	}
+	if registroID != 0 {
+		reg.RegistroID = &registroID
+	}
+	if err := repositories.NewRegistroActividadRepository().Create(reg); err != nil {
+		log.Printf("[auditoria] %s %s/%d: %v", accion, tabla, registroID, err)
+	}
+}
+
+// diffAuditoria compara antes y después (por su representación JSON) y devuelve los campos de primer nivel
+// que cambiaron. Si falta alguno de los dos estados (alta o baja) no hay diff.
+func diffAuditoria(antes, despues interface{}) map[string]dto.AuditoriaCambio {
+	a, okA := aMapaJSON(antes)
+	d, okD := aMapaJSON(despues)
+	if !okA || !okD {
+		return nil
+	}
+	cambios := make(map[string]dto.AuditoriaCambio)
+	for k, va := range a {
+		if camposAuditoriaIgnorados[k] {
+			continue
+		}
+		if vd, ok := d[k]; !ok || !reflect.DeepEqual(va, vd) {
+			cambios[k] = dto.AuditoriaCambio{Antes: va, Despues: d[k]}
+		}
+	}
+	for k, vd := range d {
+		if _, ok := a[k]; !ok && !camposAuditoriaIgnorados[k] {
+			cambios[k] = dto.AuditoriaCambio{Antes: nil, Despues: vd}
+		}
+	}
+	if len(cambios) == 0 {
+		return nil
+	}
+	return cambios
+}
+
+// valorAuditoria normaliza punteros nil a nil para que no se serialicen como "null".
+func valorAuditoria(v interface{}) interface{} {
+	if rv := reflect.ValueOf(v); v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil
+	}
+	return v
+}
+
+func aMapaJSON(v interface{}) (map[string]interface{}, bool) {
+	if valorAuditoria(v) == nil {
+		return nil, false
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, false
+	}
+	return m, true
+}
@@ -0,0 +1,41 @@
+package services
+
+import "testing"
+
+func TestDiffAuditoria(t *testing.T) {
+	t.Parallel()
+	type ficha struct {
+		Ficha     string `json:"ficha"`
+		Status    bool   `json:"status"`
+		SedeID    *uint  `json:"sede_id"`
+		UpdatedAt string `json:"updated_at"`
+	}
+	sede := uint(3)
+	antes := ficha{Ficha: "2500123", Status: true, UpdatedAt: "ayer"}
+	despues := ficha{Ficha: "2500123", Status: false, SedeID: &sede, UpdatedAt: "hoy"}
+
+	cambios := diffAuditoria(antes, despues)
+	if len(cambios) != 2 {
+		t.Fatalf("diffAuditoria() = %v, want 2 cambios (status, sede_id)", cambios)
+	}
+	if c := cambios["status"]; c.Antes != true || c.Despues != false {
+		t.Errorf("status = %+v", c)
+	}
+	if c := cambios["sede_id"]; c.Antes != nil || c.Despues != float64(3) {
+		t.Errorf("sede_id = %+v", c)
+	}
+	if _, ok := cambios["updated_at"]; ok {
+		t.Error("updated_at no debe aparecer en el diff")
+	}
+}
+
+func TestDiffAuditoriaSinEstadoPrevio(t *testing.T) {
+	t.Parallel()
+	var nada *struct{}
+	if got := diffAuditoria(nada, map[string]int{"a": 1}); got != nil {
+		t.Errorf("diffAuditoria(nil, x) = %v, want nil", got)
+	}
+	if got := diffAuditoria(map[string]int{"a": 1}, map[string]int{"a": 1}); got != nil {
+		t.Errorf("diffAuditoria(x, x) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"gorm.io/gorm"
+)
+
+// politicaBloqueo a partir de umbral fallos consecutivos se bloquea base, y el bloqueo se duplica con cada
+// fallo adicional hasta max.
+type politicaBloqueo struct {
+	umbral int
+	base   time.Duration
+	max    time.Duration
+}
+
+var (
+	// Por cuenta: frena adivinar la contraseña de un usuario concreto.
+	bloqueoCuenta = politicaBloqueo{umbral: 5, base: time.Minute, max: 30 * time.Minute}
+	// Por IP: frena probar muchas cuentas desde el mismo origen (umbral alto por NAT de sedes).
+	bloqueoIP = politicaBloqueo{umbral: 20, base: time.Minute, max: time.Hour}
+)
+
+// ventanaFallosLogin tiempo sin fallos tras el cual el contador vuelve a cero.
+const ventanaFallosLogin = time.Hour
+
+const (
+	maxAccesosRecientes     = 100
+	retencionHistorialLogin = 180 * 24 * time.Hour
+	maxIdentificadorLogin   = 150
+)
+
+// LoginBloqueadoError el login se rechaza sin verificar la contraseña hasta Hasta.
+type LoginBloqueadoError struct {
+	Hasta time.Time
+}
+
+func (e *LoginBloqueadoError) Error() string {
+	min := int(math.Ceil(time.Until(e.Hasta).Minutes()))
+	if min < 1 {
+		min = 1
+	}
+	return fmt.Sprintf("Demasiados intentos fallidos. Intente de nuevo en %d minuto(s)", min)
+}
+
+// duracionBloqueo bloqueo que corresponde tras fallos consecutivos (0 si no alcanza el umbral).
+func (p politicaBloqueo) duracionBloqueo(fallos int) time.Duration {
+	if fallos < p.umbral {
+		return 0
+	}
+	d := p.base
+	for i := p.umbral; i < fallos && d < p.max; i++ {
+		d *= 2
+	}
+	if d > p.max {
+		d = p.max
+	}
+	return d
+}
+
+func claveBloqueoCuenta(userID uint) string {
+	return "cuenta:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+func claveBloqueoIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	return "ip:" + ip
+}
+
+// verificarBloqueo devuelve LoginBloqueadoError si la clave está bloqueada en ahora.
+func (s *authService) verificarBloqueo(clave string, ahora time.Time) error {
+	if clave == "" {
+		return nil
+	}
+	b, err := s.loginRepo.FindBloqueo(clave)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[auth] consultando bloqueo %s: %v", clave, err)
+		}
+		return nil
+	}
+	if b.Bloqueado(ahora) {
+		return &LoginBloqueadoError{Hasta: *b.BloqueadoHasta}
+	}
+	return nil
+}
+
+// sumarFallo cuenta el fallo y, si corresponde, bloquea la clave según la política.
+func (s *authService) sumarFallo(clave string, p politicaBloqueo, ahora time.Time) {
+	if clave == "" {
+		return
+	}
+	b, err := s.loginRepo.RegistrarFallo(clave, ahora, ahora.Add(-ventanaFallosLogin))
+	if err != nil {
+		log.Printf("[auth] registrando fallo %s: %v", clave, err)
+		return
+	}
+	if d := p.duracionBloqueo(b.Fallos); d > 0 {
+		if err := s.loginRepo.Bloquear(clave, ahora.Add(d)); err != nil {
+			log.Printf("[auth] bloqueando %s: %v", clave, err)
+			return
+		}
+		log.Printf("[auth] %s bloqueado %s tras %d intentos fallidos", clave, d, b.Fallos)
+	}
+}
+
+// registrarFallo deja el intento en el historial y suma el fallo a la IP y, si se conoce, a la cuenta.
+func (s *authService) registrarFallo(user *models.User, identificador string, cliente dto.ClienteInfo, motivo string, ahora time.Time) {
+	s.registrarIntento(user, identificador, cliente, false, motivo, ahora)
+	s.sumarFallo(claveBloqueoIP(cliente.IP), bloqueoIP, ahora)
+	if user != nil {
+		s.sumarFallo(claveBloqueoCuenta(user.ID), bloqueoCuenta, ahora)
+	}
+}
+
+func (s *authService) registrarIntento(user *models.User, identificador string, cliente dto.ClienteInfo, exitoso bool, motivo string, ahora time.Time) {
+	l := &models.Login{
+		Identificador: truncarRunes(identificador, maxIdentificadorLogin),
+		Exitoso:       exitoso,
+		Motivo:        motivo,
+		IPAddress:     cliente.IP,
+		UserAgent:     truncarUserAgent(cliente.UserAgent),
+		FechaLogin:    ahora,
+	}
+	if user != nil {
+		l.UserID = &user.ID
+	}
+	if err := s.loginRepo.Create(l); err != nil {
+		log.Printf("[auth] registrando intento de login: %v", err)
+	}
+}
+
+// DesbloquearCuenta levanta el bloqueo de login de un usuario (acción de administrador).
+func (s *authService) DesbloquearCuenta(actor dto.Actor, userID uint) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return errors.New(errUsuarioNoEncontrado)
+	}
+	clave := claveBloqueoCuenta(userID)
+	antes, _ := s.loginRepo.FindBloqueo(clave)
+	if err := s.loginRepo.DeleteBloqueo(clave); err != nil {
+		return err
+	}
+	auditar(actor, AccionUsuarioDesbloquearLogin, tablaUsers, userID, antes, nil)
+	return nil
+}
+
+// ListAccesos últimos intentos de inicio de sesión sobre la cuenta del usuario (exitosos y fallidos).
+func (s *authService) ListAccesos(userID uint, limit int) ([]dto.LoginAccesoResponse, error) {
+	if limit < 1 || limit > maxAccesosRecientes {
+		limit = 20
+	}
+	list, err := s.loginRepo.ListByUser(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.LoginAccesoResponse, len(list))
+	for i := range list {
+		out[i] = dto.LoginAccesoResponse{
+			ID:         list[i].ID,
+			FechaLogin: list[i].FechaLogin,
+			IP:         list[i].IPAddress,
+			UserAgent:  list[i].UserAgent,
+			Exitoso:    list[i].Exitoso,
+			Motivo:     list[i].Motivo,
+		}
+	}
+	return out, nil
+}
+
+// PurgarHistorialLogin elimina intentos de login de más de 180 días y contadores de bloqueo inactivos.
+func PurgarHistorialLogin() error {
+	repo := repositories.NewLoginRepository()
+	ahora := time.Now()
+	n, err := repo.DeleteAnteriores(ahora.Add(-retencionHistorialLogin))
+	if err != nil {
+		return err
+	}
+	if _, err := repo.DeleteBloqueosInactivos(ahora.Add(-ventanaFallosLogin)); err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("[auth] %d intentos de login antiguos eliminados", n)
+	}
+	return nil
+}
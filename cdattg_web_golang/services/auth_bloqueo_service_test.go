@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPoliticaBloqueoDuracion(t *testing.T) {
+	t.Parallel()
+	p := politicaBloqueo{umbral: 5, base: time.Minute, max: 30 * time.Minute}
+	cases := []struct {
+		fallos int
+		want   time.Duration
+	}{
+		{0, 0},
+		{4, 0},
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{8, 8 * time.Minute},
+		{9, 16 * time.Minute},
+		{10, 30 * time.Minute},
+		{50, 30 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := p.duracionBloqueo(tc.fallos); got != tc.want {
+			t.Errorf("duracionBloqueo(%d) = %s, want %s", tc.fallos, got, tc.want)
+		}
+	}
+}
+
+func TestClaveBloqueoIPVacia(t *testing.T) {
+	t.Parallel()
+	if got := claveBloqueoIP(""); got != "" {
+		t.Errorf("claveBloqueoIP(\"\") = %q, want vacío", got)
+	}
+	if got := claveBloqueoCuenta(7); got != "cuenta:7" {
+		t.Errorf("claveBloqueoCuenta(7) = %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+var (
+	ErrTokenCuentaInvalido  = errors.New("el enlace no es válido, ya se usó o venció; solicite uno nuevo")
+	ErrEmailYaVerificado    = errors.New("el correo ya está verificado")
+	ErrEmailNoVerificable   = errors.New("la cuenta no tiene un correo personal registrado; actualícelo en su perfil")
+	ErrEnvioCorreoReciente  = errors.New("ya se envió un enlace hace poco; revise su correo o intente de nuevo en unos minutos")
+	ErrCorreoNoDisponible   = errors.New("el envío de correos no está habilitado; contacte al administrador")
+	ErrPasswordIgualDefecto = errors.New("la nueva contraseña no puede ser el número de documento")
+)
+
+const (
+	// esperaEntreEnviosToken evita que se use el formulario para inundar el buzón de un usuario.
+	esperaEntreEnviosToken  = 2 * time.Minute
+	retencionTokensUsuario  = 30 * 24 * time.Hour
+	rutaRestablecerPassword = "/restablecer-password"
+	rutaVerificarEmail      = "/verificar-email"
+	// dominioCorreoGenerado correos de login creados a partir del documento cuando la persona no tiene correo.
+	dominioCorreoGenerado = "@sena.local"
+)
+
+// correoEntregable descarta los correos de login generados (doc_…@sena.local), que no tienen buzón.
+func correoEntregable(email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	return strings.Contains(email, "@") && !strings.HasSuffix(email, dominioCorreoGenerado)
+}
+
+// enlaceCuenta URL del frontend que recibe el token en la query.
+func enlaceCuenta(base, ruta, token string) string {
+	return strings.TrimRight(base, "/") + ruta + "?token=" + url.QueryEscape(token)
+}
+
+func vigenciaToken(tipo string) time.Duration {
+	cfg := config.CuentasConfig{RecuperacionTokenMinutos: 30, VerificacionTokenHoras: 48}
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.Cuentas
+	}
+	if tipo == models.TokenUsuarioVerificacionEmail {
+		return time.Duration(cfg.VerificacionTokenHoras) * time.Hour
+	}
+	return time.Duration(cfg.RecuperacionTokenMinutos) * time.Minute
+}
+
+func frontendURL() string {
+	if config.AppConfig == nil {
+		return ""
+	}
+	return config.AppConfig.Cuentas.FrontendURL
+}
+
+func smtpHabilitado() bool {
+	return utils.SMTPHabilitado()
+}
+
+// correoEnlaceCuenta aviso con el enlace de un solo uso; se envía con EnviarEnlace, nunca por la cola.
+func correoEnlaceCuenta(para, asunto, plantilla string, aviso *avisoCorreo) (CorreoNuevo, error) {
+	html, texto, err := renderAviso(aviso)
+	if err != nil {
+		return CorreoNuevo{}, err
+	}
+	return CorreoNuevo{
+		Para: []string{para}, Asunto: asunto, Plantilla: plantilla, HTML: html, Texto: texto,
+	}, nil
+}
+
+// emitirTokenCuenta genera el token (invalida los pendientes del mismo tipo) y devuelve el valor en claro.
+func (s *authService) emitirTokenCuenta(user *models.User, tipo string, ahora time.Time) (string, time.Time, error) {
+	if ultimo, err := s.tokenRepo.UltimoEmitido(user.ID, tipo); err == nil && ahora.Sub(ultimo.CreatedAt) < esperaEntreEnviosToken {
+		return "", time.Time{}, ErrEnvioCorreoReciente
+	}
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expira := ahora.Add(vigenciaToken(tipo))
+	t := &models.TokenUsuario{
+		UserID:    user.ID,
+		Tipo:      tipo,
+		TokenHash: utils.HashRefreshToken(token),
+		Email:     user.Email,
+		ExpiraAt:  expira,
+		CreatedAt: ahora,
+	}
+	if err := s.tokenRepo.Emitir(t); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expira, nil
+}
+
+// validarTokenCuenta busca el token vigente y sin usar; el usuario debe seguir activo y con el mismo correo al que se
+// envió el enlace. No lo consume: eso lo hace consumirTokenCuenta junto con el cambio.
+func (s *authService) validarTokenCuenta(tipo, token string, ahora time.Time) (*models.TokenUsuario, *models.User, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, nil, ErrTokenCuentaInvalido
+	}
+	t, err := s.tokenRepo.FindByTokenHash(tipo, utils.HashRefreshToken(token))
+	if err != nil || !t.Vigente(ahora) {
+		return nil, nil, ErrTokenCuentaInvalido
+	}
+	user, err := s.userRepo.FindByID(t.UserID)
+	if err != nil || !user.Status || !strings.EqualFold(user.Email, t.Email) {
+		return nil, nil, ErrTokenCuentaInvalido
+	}
+	return t, user, nil
+}
+
+// consumirTokenCuenta marca el token como usado y aplica los cambios al usuario en una transacción: si algo falla
+// el enlace sigue sirviendo, y si otra petición lo usó antes no se cambia nada.
+func (s *authService) consumirTokenCuenta(t *models.TokenUsuario, ahora time.Time, cambios map[string]interface{}) error {
+	cambios["updated_at"] = ahora
+	ok, err := s.tokenRepo.Consumir(t.ID, ahora, t.UserID, cambios)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTokenCuentaInvalido
+	}
+	return nil
+}
+
+// SolicitarRecuperacion envía el enlace para restablecer la contraseña solo si la cuenta está activa y su correo fue
+// verificado. No revela si el usuario existe: cualquier otro caso se registra en el log y se responde igual.
+func (s *authService) SolicitarRecuperacion(login string) error {
+	user, err := s.resolveUserFromLogin(login)
+	if err != nil {
+		return nil
+	}
+	if user.EmailVerifiedAt == nil || !correoEntregable(user.Email) {
+		log.Printf("[auth] recuperación de contraseña omitida para el usuario %d: correo sin verificar", user.ID)
+		return nil
+	}
+	ahora := utils.Now()
+	token, expira, err := s.emitirTokenCuenta(user, models.TokenUsuarioRecuperacionPassword, ahora)
+	if err != nil {
+		if !errors.Is(err, ErrEnvioCorreoReciente) {
+			log.Printf("[auth] emitiendo enlace de recuperación para el usuario %d: %v", user.ID, err)
+		}
+		return nil
+	}
+	correo, err := correoEnlaceCuenta(user.Email, "CDATTG Web - Restablecer contraseña", PlantillaCorreoRecuperarPassword,
+		&avisoCorreo{
+			correoBase: correoBase{Titulo: "Restablecer contraseña"},
+			Parrafos:   []string{"Recibimos una solicitud para restablecer la contraseña de su cuenta en CDATTG Web."},
+			Datos:      []datoCorreo{{Etiqueta: "Vence", Valor: expira.Format("02/01/2006 15:04")}},
+			Boton: &botonCorreo{
+				Texto: "Elegir una nueva contraseña", Enlace: enlaceCuenta(frontendURL(), rutaRestablecerPassword, token),
+			},
+			Nota: "El enlace solo se puede usar una vez. Si usted no lo solicitó, ignore este mensaje; su contraseña no cambia.",
+		})
+	if err != nil {
+		log.Printf("[auth] armando enlace de recuperación para el usuario %d: %v", user.ID, err)
+		return nil
+	}
+	// Se envía fuera de la petición: esperar al servidor SMTP solo con las cuentas verificadas delataría cuáles existen.
+	go func() {
+		if err := s.correos.EnviarEnlace(correo); err != nil {
+			log.Printf("[auth] enviando enlace de recuperación al usuario %d: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// RestablecerPassword fija la contraseña con un enlace de recuperación vigente, cierra las sesiones abiertas y
+// levanta el bloqueo de login de la cuenta.
+func (s *authService) RestablecerPassword(req dto.RestablecerPasswordRequest, cliente dto.ClienteInfo) error {
+	ahora := utils.Now()
+	t, user, err := s.validarTokenCuenta(models.TokenUsuarioRecuperacionPassword, req.Token, ahora)
+	if err != nil {
+		return err
+	}
+	if s.esPasswordPorDefecto(user, req.PasswordNueva) {
+		return ErrPasswordIgualDefecto
+	}
+	hash, err := utils.HashPassword(req.PasswordNueva)
+	if err != nil {
+		return errors.New("error al generar la nueva contraseña")
+	}
+	if err := s.consumirTokenCuenta(t, ahora, map[string]interface{}{
+		"password": hash, "debe_cambiar_password": false,
+	}); err != nil {
+		return err
+	}
+	if _, err := s.CerrarTodasLasSesiones(user.ID); err != nil {
+		log.Printf("[auth] cerrando sesiones del usuario %d tras restablecer contraseña: %v", user.ID, err)
+	}
+	if err := s.loginRepo.DeleteBloqueo(claveBloqueoCuenta(user.ID)); err != nil {
+		log.Printf("[auth] reiniciando fallos de la cuenta %d: %v", user.ID, err)
+	}
+	auditar(dto.Actor{UserID: user.ID, IP: cliente.IP}, AccionUsuarioRestablecerPass, tablaUsers, user.ID, nil,
+		map[string]interface{}{"medio": "enlace_correo"})
+	return nil
+}
+
+// SolicitarVerificacionEmail envía al usuario autenticado el enlace para confirmar su correo.
+func (s *authService) SolicitarVerificacionEmail(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New(errUsuarioNoEncontrado)
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailYaVerificado
+	}
+	if !correoEntregable(user.Email) {
+		return ErrEmailNoVerificable
+	}
+	if !smtpHabilitado() {
+		return ErrCorreoNoDisponible
+	}
+	token, expira, err := s.emitirTokenCuenta(user, models.TokenUsuarioVerificacionEmail, utils.Now())
+	if err != nil {
+		return err
+	}
+	correo, err := correoEnlaceCuenta(user.Email, "CDATTG Web - Verificar correo", PlantillaCorreoVerificarEmail,
+		&avisoCorreo{
+			correoBase: correoBase{Titulo: "Verificar correo"},
+			Parrafos:   []string{fmt.Sprintf("Para confirmar que %s es su correo en CDATTG Web abra el siguiente enlace.", user.Email)},
+			Datos:      []datoCorreo{{Etiqueta: "Vence", Valor: expira.Format("02/01/2006 15:04")}},
+			Boton: &botonCorreo{
+				Texto: "Verificar mi correo", Enlace: enlaceCuenta(frontendURL(), rutaVerificarEmail, token),
+			},
+			Nota: "Con el correo verificado podrá recuperar su contraseña sin acudir al administrador.",
+		})
+	if err == nil {
+		err = s.correos.EnviarEnlace(correo)
+	}
+	if err != nil {
+		return fmt.Errorf("no se pudo enviar el correo de verificación: %w", err)
+	}
+	return nil
+}
+
+// VerificarEmail marca el correo del usuario como verificado con un enlace vigente.
+func (s *authService) VerificarEmail(token string, cliente dto.ClienteInfo) error {
+	ahora := utils.Now()
+	t, user, err := s.validarTokenCuenta(models.TokenUsuarioVerificacionEmail, token, ahora)
+	if err != nil {
+		return err
+	}
+	cambios := map[string]interface{}{}
+	if user.EmailVerifiedAt == nil {
+		cambios["email_verified_at"] = ahora
+	}
+	if err := s.consumirTokenCuenta(t, ahora, cambios); err != nil {
+		return err
+	}
+	auditar(dto.Actor{UserID: user.ID, IP: cliente.IP}, AccionUsuarioVerificarEmail, tablaUsers, user.ID, nil,
+		map[string]interface{}{"email": user.Email})
+	return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/models"
+)
+
+func TestCorreoEntregable(t *testing.T) {
+	t.Parallel()
+	cases := map[string]bool{
+		"ana@misena.edu.co":      true,
+		" Ana@Gmail.com ":        true,
+		"doc_123456@sena.local":  false,
+		"persona_9@SENA.LOCAL":   false,
+		"":                       false,
+		"sin-arroba.example.com": false,
+	}
+	for email, want := range cases {
+		if got := correoEntregable(email); got != want {
+			t.Errorf("correoEntregable(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
+
+func TestEnlaceCuenta(t *testing.T) {
+	t.Parallel()
+	got := enlaceCuenta("https://cdattg.example.co/", rutaRestablecerPassword, "a+b/c=")
+	want := "https://cdattg.example.co/restablecer-password?token=a%2Bb%2Fc%3D"
+	if got != want {
+		t.Errorf("enlaceCuenta = %q, want %q", got, want)
+	}
+}
+
+func TestVigenciaToken(t *testing.T) {
+	prev := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prev })
+	config.AppConfig = &config.Config{Cuentas: config.CuentasConfig{RecuperacionTokenMinutos: 15, VerificacionTokenHoras: 24}}
+
+	if got := vigenciaToken(models.TokenUsuarioRecuperacionPassword); got != 15*time.Minute {
+		t.Errorf("recuperación = %s", got)
+	}
+	if got := vigenciaToken(models.TokenUsuarioVerificacionEmail); got != 24*time.Hour {
+		t.Errorf("verificación = %s", got)
+	}
+}
+
+func TestTokenUsuarioVigente(t *testing.T) {
+	t.Parallel()
+	ahora := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	tok := models.TokenUsuario{ExpiraAt: ahora.Add(time.Minute)}
+	if !tok.Vigente(ahora) {
+		t.Error("token sin usar y sin vencer debe estar vigente")
+	}
+	if tok.Vigente(ahora.Add(time.Minute)) {
+		t.Error("token vencido no debe estar vigente")
+	}
+	tok.UsadoAt = &ahora
+	if tok.Vigente(ahora) {
+		t.Error("token usado no debe estar vigente")
+	}
+}
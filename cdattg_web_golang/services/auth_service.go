@@ -2,8 +2,10 @@ package services
 
 import (
 	"errors"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/sena/cdattg-web-golang/authz"
@@ -17,20 +19,42 @@ import (
 const errMsgUsuarioNoEncontradoLogin = "Usuario no encontrado"
 
 type AuthService interface {
-	Login(req dto.LoginRequest) (*dto.LoginResponse, error)
+	Login(req dto.LoginRequest, cliente dto.ClienteInfo) (*dto.LoginResponse, error)
 	GetCurrentUser(userID uint) (*dto.UserResponse, error)
 	ChangePassword(userID uint, req dto.ChangePasswordRequest) error
+
+	Refresh(refreshToken string, cliente dto.ClienteInfo) (*dto.TokenResponse, error)
+	Logout(sesionID uint) error
+	ListSesiones(userID, sesionActualID uint) ([]dto.SesionResponse, error)
+	CerrarSesion(userID, sesionID uint) error
+	CerrarTodasLasSesiones(userID uint) (int64, error)
+
+	ListAccesos(userID uint, limit int) ([]dto.LoginAccesoResponse, error)
+	DesbloquearCuenta(actor dto.Actor, userID uint) error
+
+	SolicitarRecuperacion(login string) error
+	RestablecerPassword(req dto.RestablecerPasswordRequest, cliente dto.ClienteInfo) error
+	SolicitarVerificacionEmail(userID uint) error
+	VerificarEmail(token string, cliente dto.ClienteInfo) error
 }
 
 type authService struct {
 	userRepo    repositories.UserRepository
 	personaRepo repositories.PersonaRepository
+	sesionRepo  repositories.SesionRepository
+	loginRepo   repositories.LoginRepository
+	tokenRepo   repositories.TokenUsuarioRepository
+	correos     CorreoService
 }
 
 func NewAuthService() AuthService {
 	return &authService{
 		userRepo:    repositories.NewUserRepository(),
 		personaRepo: repositories.NewPersonaRepository(),
+		sesionRepo:  repositories.NewSesionRepository(),
+		loginRepo:   repositories.NewLoginRepository(),
+		tokenRepo:   repositories.NewTokenUsuarioRepository(),
+		correos:     NewCorreoService(),
 	}
 }
 
@@ -106,21 +130,40 @@ func (s *authService) tryUserByDocumentoOCelular(normalized, login string) *mode
 	return nil
 }
 
-func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
+// Login registra cada intento en el historial. Con la IP o la cuenta bloqueadas por intentos fallidos
+// se rechaza con LoginBloqueadoError sin llegar a verificar la contraseña.
+func (s *authService) Login(req dto.LoginRequest, cliente dto.ClienteInfo) (*dto.LoginResponse, error) {
+	ahora := time.Now()
+	if err := s.verificarBloqueo(claveBloqueoIP(cliente.IP), ahora); err != nil {
+		s.registrarIntento(nil, req.Email, cliente, false, models.LoginMotivoBloqueado, ahora)
+		return nil, err
+	}
+
 	// req.Email puede ser correo, documento o celular
 	user, err := s.resolveUserFromLogin(req.Email)
 	if err != nil {
+		s.registrarFallo(nil, req.Email, cliente, models.LoginMotivoUsuarioNoEncontrado, ahora)
+		return nil, err
+	}
+
+	if err := s.verificarBloqueo(claveBloqueoCuenta(user.ID), ahora); err != nil {
+		s.registrarIntento(user, req.Email, cliente, false, models.LoginMotivoBloqueado, ahora)
 		return nil, err
 	}
 
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
+		s.registrarFallo(user, req.Email, cliente, models.LoginMotivoPasswordIncorrecta, ahora)
 		return nil, errors.New("Contraseña incorrecta")
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	tokens, err := s.abrirSesion(user, cliente)
 	if err != nil {
 		return nil, err
 	}
+	s.registrarIntento(user, req.Email, cliente, true, "", ahora)
+	if err := s.loginRepo.DeleteBloqueo(claveBloqueoCuenta(user.ID)); err != nil {
+		log.Printf("[auth] reiniciando fallos de la cuenta %d: %v", user.ID, err)
+	}
 
 	db := database.GetDB()
 	sub := strconv.FormatUint(uint64(user.ID), 10)
@@ -131,53 +174,71 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
 		permissions, _ = authz.GetAllPermissionsForUser(e, sub)
 	}
 
-	fullName := ""
-	if user.PersonaID != nil {
-		// Cargar persona si existe
-		var persona models.Persona
-		if err := database.GetDB().First(&persona, *user.PersonaID).Error; err == nil {
-			fullName = persona.GetFullName()
+	persona := s.personaDeUsuario(user)
+	// Cuentas que siguen con la contraseña por defecto (documento) deben cambiarla antes de usar el sistema.
+	if !user.DebeCambiarPassword && persona != nil && req.Password == defaultPasswordForPersona(*persona) {
+		user.DebeCambiarPassword = true
+		if err := s.userRepo.Update(user); err != nil {
+			log.Printf("[auth] marcando cambio de contraseña obligatorio para el usuario %d: %v", user.ID, err)
 		}
 	}
 
 	return &dto.LoginResponse{
-		Token: token,
-		Type:  "Bearer",
-		User: dto.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			FullName:  fullName,
-			Status:    user.Status,
-			PersonaID: user.PersonaID,
-		},
-		Roles:       roles,
-		Permissions: permissions,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		Type:         tokens.Type,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         userResponse(user, persona),
+		Roles:        roles,
+		Permissions:  permissions,
 	}, nil
 }
 
-func (s *authService) GetCurrentUser(userID uint) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return nil, errors.New("usuario no encontrado")
+// personaDeUsuario persona vinculada al usuario (nil si no tiene o no se encuentra).
+func (s *authService) personaDeUsuario(user *models.User) *models.Persona {
+	if user.PersonaID == nil {
+		return nil
 	}
+	var persona models.Persona
+	if err := database.GetDB().First(&persona, *user.PersonaID).Error; err != nil {
+		return nil
+	}
+	return &persona
+}
+
+// esPasswordPorDefecto indica si password es la contraseña con la que se crean o restablecen las cuentas de la persona.
+func (s *authService) esPasswordPorDefecto(user *models.User, password string) bool {
+	persona := s.personaDeUsuario(user)
+	return persona != nil && password == defaultPasswordForPersona(*persona)
+}
 
+func userResponse(user *models.User, persona *models.Persona) dto.UserResponse {
 	fullName := ""
-	if user.PersonaID != nil {
-		var persona models.Persona
-		if err := database.GetDB().First(&persona, *user.PersonaID).Error; err == nil {
-			fullName = persona.GetFullName()
-		}
+	if persona != nil {
+		fullName = persona.GetFullName()
 	}
+	return dto.UserResponse{
+		ID:                  user.ID,
+		Email:               user.Email,
+		FullName:            fullName,
+		Status:              user.Status,
+		PersonaID:           user.PersonaID,
+		EmailVerificado:     user.EmailVerifiedAt != nil,
+		DebeCambiarPassword: user.DebeCambiarPassword,
+	}
+}
 
-	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FullName:  fullName,
-		Status:    user.Status,
-		PersonaID: user.PersonaID,
-	}, nil
+func (s *authService) GetCurrentUser(userID uint) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("usuario no encontrado")
+	}
+	resp := userResponse(user, s.personaDeUsuario(user))
+	return &resp, nil
 }
 
+// ChangePassword exige la contraseña actual; la nueva no puede ser la de por defecto y, al guardarla, deja de ser
+// obligatorio el cambio.
 func (s *authService) ChangePassword(userID uint, req dto.ChangePasswordRequest) error {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -186,10 +247,14 @@ func (s *authService) ChangePassword(userID uint, req dto.ChangePasswordRequest)
 	if !utils.CheckPasswordHash(req.PasswordActual, user.Password) {
 		return errors.New("contraseña actual incorrecta")
 	}
+	if s.esPasswordPorDefecto(user, req.PasswordNueva) {
+		return ErrPasswordIgualDefecto
+	}
 	hash, err := utils.HashPassword(req.PasswordNueva)
 	if err != nil {
 		return errors.New("error al generar la nueva contraseña")
 	}
 	user.Password = hash
+	user.DebeCambiarPassword = false
 	return s.userRepo.Update(user)
 }
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+// refreshReusoGracia margen en el que reutilizar el refresh token recién rotado se trata como carrera
+// entre pestañas (solo se rechaza) y no como robo (que revoca la sesión).
+const refreshReusoGracia = 30 * time.Second
+
+const maxUserAgentSesion = 255
+
+// retencionSesionesCaducadas tiempo que se conservan sesiones expiradas o revocadas antes de purgarlas.
+const retencionSesionesCaducadas = 30 * 24 * time.Hour
+
+var (
+	ErrRefreshTokenInvalido = errors.New("refresh token inválido o expirado")
+	ErrSesionNoEncontrada   = errors.New("sesión no encontrada")
+)
+
+// abrirSesion registra una sesión nueva para el dispositivo y emite su par de tokens.
+func (s *authService) abrirSesion(user *models.User, cliente dto.ClienteInfo) (*dto.TokenResponse, error) {
+	refresh, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ses := &models.Sesion{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashRefreshToken(refresh),
+		UserAgent:        truncarUserAgent(cliente.UserAgent),
+		IP:               cliente.IP,
+		UltimoUsoAt:      now,
+		ExpiraAt:         now.Add(utils.RefreshTokenTTL()),
+	}
+	if err := s.sesionRepo.Create(ses); err != nil {
+		return nil, err
+	}
+	return emitirTokens(user, ses.ID, refresh)
+}
+
+func emitirTokens(user *models.User, sesionID uint, refresh string) (*dto.TokenResponse, error) {
+	token, err := utils.GenerateToken(user.ID, user.Email, sesionID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenResponse{
+		Token:        token,
+		RefreshToken: refresh,
+		Type:         "Bearer",
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+func truncarUserAgent(ua string) string {
+	return truncarRunes(ua, maxUserAgentSesion)
+}
+
+func truncarRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) > max {
+		return string(r[:max])
+	}
+	return s
+}
+
+// Refresh rota el refresh token de la sesión y emite un access token nuevo. Presentar un refresh token
+// ya rotado (fuera del margen de gracia) indica que fue copiado: se revoca la sesión completa.
+func (s *authService) Refresh(refreshToken string, cliente dto.ClienteInfo) (*dto.TokenResponse, error) {
+	hash := utils.HashRefreshToken(refreshToken)
+	now := time.Now()
+	ses, err := s.sesionRepo.FindByRefreshHash(hash)
+	if err != nil {
+		if prev, errPrev := s.sesionRepo.FindByRefreshAnteriorHash(hash); errPrev == nil && now.Sub(prev.UltimoUsoAt) > refreshReusoGracia {
+			log.Printf("[auth] refresh token reutilizado en sesión %d (usuario %d, ip %s); se revoca", prev.ID, prev.UserID, cliente.IP)
+			_ = s.sesionRepo.Revocar(prev.ID, models.SesionMotivoReusoRefresh)
+		}
+		return nil, ErrRefreshTokenInvalido
+	}
+	if !ses.Activa(now) {
+		return nil, ErrRefreshTokenInvalido
+	}
+	user, err := s.userRepo.FindByID(ses.UserID)
+	if err != nil || !user.Status {
+		_ = s.sesionRepo.Revocar(ses.ID, models.SesionMotivoUsuarioInactivo)
+		return nil, ErrRefreshTokenInvalido
+	}
+	nuevo, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.sesionRepo.Rotar(ses.ID, hash, utils.HashRefreshToken(nuevo), now.Add(utils.RefreshTokenTTL()), now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRefreshTokenInvalido
+	}
+	return emitirTokens(user, ses.ID, nuevo)
+}
+
+// Logout revoca la sesión del access token actual.
+func (s *authService) Logout(sesionID uint) error {
+	return s.sesionRepo.Revocar(sesionID, models.SesionMotivoLogout)
+}
+
+func (s *authService) ListSesiones(userID, sesionActualID uint) ([]dto.SesionResponse, error) {
+	list, err := s.sesionRepo.ListActivasByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.SesionResponse, len(list))
+	for i := range list {
+		out[i] = dto.SesionResponse{
+			ID:          list[i].ID,
+			UserAgent:   list[i].UserAgent,
+			IP:          list[i].IP,
+			CreatedAt:   list[i].CreatedAt,
+			UltimoUsoAt: list[i].UltimoUsoAt,
+			ExpiraAt:    list[i].ExpiraAt,
+			Actual:      list[i].ID == sesionActualID,
+		}
+	}
+	return out, nil
+}
+
+// CerrarSesion revoca una sesión (dispositivo) del propio usuario.
+func (s *authService) CerrarSesion(userID, sesionID uint) error {
+	ses, err := s.sesionRepo.FindByID(sesionID)
+	if err != nil || ses.UserID != userID {
+		return ErrSesionNoEncontrada
+	}
+	return s.sesionRepo.Revocar(ses.ID, models.SesionMotivoCerradaUsuario)
+}
+
+// CerrarTodasLasSesiones revoca todas las sesiones del usuario, incluida la actual.
+func (s *authService) CerrarTodasLasSesiones(userID uint) (int64, error) {
+	return s.sesionRepo.RevocarTodasByUser(userID, models.SesionMotivoCerrarTodas, 0)
+}
+
+// PurgarSesionesCaducadas elimina sesiones expiradas o revocadas y enlaces de cuenta vencidos hace más de 30 días
+// (tarea programada).
+func PurgarSesionesCaducadas() error {
+	n, err := repositories.NewSesionRepository().DeleteCaducadas(time.Now().Add(-retencionSesionesCaducadas))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("[auth] %d sesiones caducadas eliminadas", n)
+	}
+	n, err = repositories.NewTokenUsuarioRepository().DeleteVencidos(time.Now().Add(-retencionTokensUsuario))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("[auth] %d enlaces de cuenta vencidos eliminados", n)
+	}
+	return nil
+}
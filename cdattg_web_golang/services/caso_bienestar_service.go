@@ -0,0 +1,754 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/authz"
+	"github.com/sena/cdattg-web-golang/database"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+const (
+	casoBienestarArchivoDir = "storage/casos_bienestar"
+	// diasFuturosIntervencion margen para registrar una gestión con fecha de mañana (diferencias de zona horaria).
+	diasFuturosIntervencion = 1
+)
+
+// rolesProfesionalBienestar roles a los que se puede asignar un caso.
+var rolesProfesionalBienestar = []string{"BIENESTAR AL APRENDIZ", "SUPER ADMINISTRADOR"}
+
+var (
+	ErrCasoBienestarNoEncontrado      = errors.New("caso de bienestar no encontrado")
+	ErrCasoBienestarAdjuntoNoExiste   = errors.New("adjunto no encontrado")
+	ErrCasoBienestarAprendizNoExiste  = errors.New("aprendiz no encontrado")
+	ErrCasoBienestarYaAbierto         = errors.New("el aprendiz ya tiene un caso de bienestar sin cerrar")
+	errCasoBienestarMotivoRequerido   = errors.New("el motivo es obligatorio")
+	errCasoBienestarResponsable       = errors.New("el responsable debe ser un usuario activo de bienestar")
+	errCasoBienestarCerrado           = errors.New("el caso está cerrado; reábralo para registrar nuevas gestiones")
+	errCasoBienestarObservacionCierre = errors.New("indique la observación de cierre")
+	errCasoBienestarFechaInvalida     = errors.New("fecha inválida (use YYYY-MM-DD)")
+	errCasoBienestarFechaFutura       = errors.New("la gestión no puede tener fecha futura")
+	errCasoBienestarDescripcion       = errors.New("la descripción es obligatoria")
+	errCasoBienestarNota              = errors.New("la nota del recordatorio es obligatoria")
+	errCasoBienestarRecordatorio      = errors.New("recordatorio no encontrado o ya completado")
+)
+
+// CasoBienestarService casos de seguimiento de bienestar: se abren desde el cálculo de inasistencias o manualmente,
+// se asignan a un profesional y llevan bitácora de gestiones, adjuntos y recordatorios.
+type CasoBienestarService interface {
+	Crear(actor dto.Actor, req dto.CasoBienestarCreateRequest) (*dto.CasoBienestarResponse, error)
+	AbrirDesdeCalculo(actor dto.Actor, req dto.CasosBienestarAbrirRequest) (*dto.CasosBienestarAbrirResponse, error)
+	List(f repositories.CasoBienestarFiltro) ([]dto.CasoBienestarResponse, error)
+	Get(id uint) (*dto.CasoBienestarDetalleResponse, error)
+	Actualizar(actor dto.Actor, id uint, req dto.CasoBienestarUpdateRequest) (*dto.CasoBienestarResponse, error)
+	RegistrarIntervencion(actor dto.Actor, id uint, req dto.CasoBienestarIntervencionRequest) (*dto.CasoBienestarIntervencionResponse, error)
+	SubirAdjunto(actor dto.Actor, id uint, contenido []byte, nombre string) (*dto.CasoBienestarAdjuntoResponse, error)
+	// Adjunto ruta en disco y nombre original del adjunto.
+	Adjunto(id, adjuntoID uint) (ruta, nombre string, err error)
+	CrearRecordatorio(actor dto.Actor, id uint, req dto.CasoBienestarRecordatorioRequest) (*dto.CasoBienestarRecordatorioResponse, error)
+	CompletarRecordatorio(id, recordatorioID uint) error
+	// MisRecordatorios recordatorios pendientes hasta hoy de los casos asignados al usuario.
+	MisRecordatorios(userID uint) ([]dto.CasoBienestarRecordatorioResponse, error)
+	// HistorialAprendiz casos de la persona del aprendiz en todas sus fichas.
+	HistorialAprendiz(aprendizID uint) ([]dto.CasoBienestarResponse, error)
+	Profesionales() ([]dto.ProfesionalBienestarItem, error)
+	// AnotarCasosAbiertos marca en el listado calculado los aprendices que ya tienen caso sin cerrar.
+	AnotarCasosAbiertos(casos []dto.CasoBienestarItem)
+	// NotificarRecordatorios avisa por correo al responsable de cada recordatorio vencido (tarea programada).
+	NotificarRecordatorios() error
+}
+
+type casoBienestarService struct {
+	repo         repositories.CasoBienestarRepository
+	aprendizRepo repositories.AprendizRepository
+	userRepo     repositories.UserRepository
+	personaRepo  repositories.PersonaRepository
+	// casosCalculados listado de aprendices sobre el umbral (GetCasosBienestar).
+	casosCalculados func(sedeID *uint, dias, minFallas int) (*dto.CasosBienestarResponse, error)
+	// rolesDeUsuario roles Casbin del usuario.
+	rolesDeUsuario func(userID uint) ([]string, error)
+	// usuariosConRol IDs de los usuarios con el rol.
+	usuariosConRol func(rol string) []uint
+	correos        CorreoService
+}
+
+func NewCasoBienestarService() CasoBienestarService {
+	return &casoBienestarService{
+		repo:         repositories.NewCasoBienestarRepository(),
+		aprendizRepo: repositories.NewAprendizRepository(),
+		userRepo:     repositories.NewUserRepository(),
+		personaRepo:  repositories.NewPersonaRepository(),
+		casosCalculados: func(sedeID *uint, dias, minFallas int) (*dto.CasosBienestarResponse, error) {
+			return NewAsistenciaService().GetCasosBienestar(sedeID, dias, minFallas)
+		},
+		rolesDeUsuario: rolesCasbinDeUsuario,
+		usuariosConRol: usuariosCasbinConRol,
+		correos:        NewCorreoService(),
+	}
+}
+
+func rolesCasbinDeUsuario(userID uint) ([]string, error) {
+	e, err := authz.GetEnforcer(database.GetDB())
+	if err != nil {
+		return nil, err
+	}
+	return authz.GetRolesForUser(e, strconv.FormatUint(uint64(userID), 10))
+}
+
+func usuariosCasbinConRol(rol string) []uint {
+	e, err := authz.GetEnforcer(database.GetDB())
+	if err != nil {
+		return nil
+	}
+	var ids []uint
+	for _, s := range authz.GetUserIDsWithRole(e, rol) {
+		if id, err := strconv.ParseUint(s, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// validarResponsable el responsable (si se indica) es un usuario activo con rol de bienestar o super admin.
+func (s *casoBienestarService) validarResponsable(userID *uint) error {
+	if userID == nil {
+		return nil
+	}
+	u, err := s.userRepo.FindByID(*userID)
+	if err != nil || u == nil || !u.Status {
+		return errCasoBienestarResponsable
+	}
+	roles, err := s.rolesDeUsuario(*userID)
+	if err != nil {
+		return err
+	}
+	for _, r := range rolesProfesionalBienestar {
+		if hasRole(roles, r) {
+			return nil
+		}
+	}
+	return errCasoBienestarResponsable
+}
+
+func (s *casoBienestarService) crearCaso(actor dto.Actor, c *models.CasoBienestar) error {
+	if err := s.repo.Create(c); err != nil {
+		if database.IsUniqueViolation(err) {
+			return ErrCasoBienestarYaAbierto
+		}
+		return err
+	}
+	auditar(actor, AccionCasoBienestarCrear, tablaCasosBienestar, c.ID, nil, c)
+	return nil
+}
+
+func (s *casoBienestarService) Crear(actor dto.Actor, req dto.CasoBienestarCreateRequest) (*dto.CasoBienestarResponse, error) {
+	motivo := strings.TrimSpace(req.Motivo)
+	if motivo == "" {
+		return nil, errCasoBienestarMotivoRequerido
+	}
+	aprendiz, err := s.aprendizRepo.FindByID(req.AprendizID)
+	if err != nil || aprendiz == nil {
+		return nil, ErrCasoBienestarAprendizNoExiste
+	}
+	if err := s.validarResponsable(req.ResponsableUserID); err != nil {
+		return nil, err
+	}
+	abiertos, err := s.repo.MapAbiertosPorAprendiz([]uint{aprendiz.ID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := abiertos[aprendiz.ID]; ok {
+		return nil, ErrCasoBienestarYaAbierto
+	}
+	c := &models.CasoBienestar{
+		AprendizID:        aprendiz.ID,
+		PersonaID:         aprendiz.PersonaID,
+		FichaID:           aprendiz.FichaCaracterizacionID,
+		Origen:            models.CasoBienestarOrigenManual,
+		Estado:            models.CasoBienestarEstadoAbierto,
+		Motivo:            motivo,
+		ResponsableUserID: req.ResponsableUserID,
+		CreadoPorUserID:   actor.UserID,
+	}
+	if err := s.crearCaso(actor, c); err != nil {
+		return nil, err
+	}
+	return s.getResponse(c.ID)
+}
+
+// motivoCasoDesdeCalculo texto con el que queda el caso abierto desde el cálculo de inasistencias.
+func motivoCasoDesdeCalculo(item dto.CasoBienestarItem, resp *dto.CasosBienestarResponse) string {
+	return fmt.Sprintf("%d inasistencias sin justificar de %d sesiones entre %s y %s (umbral: %d).",
+		item.Inasistencias, item.TotalSesiones, resp.FechaInicio, resp.FechaFin, resp.MinFallas)
+}
+
+func parseFechaCaso(s string) *time.Time {
+	t, err := time.ParseInLocation(time.DateOnly, s, utils.AppLocation())
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func (s *casoBienestarService) AbrirDesdeCalculo(actor dto.Actor, req dto.CasosBienestarAbrirRequest) (*dto.CasosBienestarAbrirResponse, error) {
+	if err := s.validarResponsable(req.ResponsableUserID); err != nil {
+		return nil, err
+	}
+	dias := 30
+	if req.Dias != nil && *req.Dias >= 0 {
+		dias = *req.Dias
+	}
+	calc, err := s.casosCalculados(req.SedeID, dias, req.MinFallas)
+	if err != nil {
+		return nil, err
+	}
+	porAprendiz := make(map[uint]dto.CasoBienestarItem, len(calc.Casos))
+	for _, item := range calc.Casos {
+		porAprendiz[item.AprendizID] = item
+	}
+	abiertos, err := s.repo.MapAbiertosPorAprendiz(req.AprendizIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dto.CasosBienestarAbrirResponse{Creados: []dto.CasoBienestarResponse{}, Omitidos: []dto.CasoBienestarOmitido{}}
+	vistos := make(map[uint]bool, len(req.AprendizIDs))
+	for _, aprendizID := range req.AprendizIDs {
+		if aprendizID == 0 || vistos[aprendizID] {
+			continue
+		}
+		vistos[aprendizID] = true
+		if abierto, ok := abiertos[aprendizID]; ok {
+			id := abierto.ID
+			out.Omitidos = append(out.Omitidos, dto.CasoBienestarOmitido{AprendizID: aprendizID, Motivo: ErrCasoBienestarYaAbierto.Error(), CasoID: &id})
+			continue
+		}
+		item, ok := porAprendiz[aprendizID]
+		if !ok {
+			out.Omitidos = append(out.Omitidos, dto.CasoBienestarOmitido{AprendizID: aprendizID, Motivo: "no supera el umbral de inasistencias en el período"})
+			continue
+		}
+		aprendiz, err := s.aprendizRepo.FindByID(aprendizID)
+		if err != nil || aprendiz == nil {
+			out.Omitidos = append(out.Omitidos, dto.CasoBienestarOmitido{AprendizID: aprendizID, Motivo: ErrCasoBienestarAprendizNoExiste.Error()})
+			continue
+		}
+		c := &models.CasoBienestar{
+			AprendizID:                aprendiz.ID,
+			PersonaID:                 aprendiz.PersonaID,
+			FichaID:                   aprendiz.FichaCaracterizacionID,
+			Origen:                    models.CasoBienestarOrigenCalculo,
+			Estado:                    models.CasoBienestarEstadoAbierto,
+			Motivo:                    motivoCasoDesdeCalculo(item, calc),
+			ResponsableUserID:         req.ResponsableUserID,
+			PeriodoInicio:             parseFechaCaso(calc.FechaInicio),
+			PeriodoFin:                parseFechaCaso(calc.FechaFin),
+			Inasistencias:             item.Inasistencias,
+			InasistenciasJustificadas: item.InasistenciasJustificadas,
+			TotalSesiones:             item.TotalSesiones,
+			CreadoPorUserID:           actor.UserID,
+		}
+		if err := s.crearCaso(actor, c); err != nil {
+			out.Omitidos = append(out.Omitidos, dto.CasoBienestarOmitido{AprendizID: aprendizID, Motivo: err.Error()})
+			continue
+		}
+		resp, err := s.getResponse(c.ID)
+		if err != nil {
+			return nil, err
+		}
+		out.Creados = append(out.Creados, *resp)
+	}
+	return out, nil
+}
+
+func (s *casoBienestarService) List(f repositories.CasoBienestarFiltro) ([]dto.CasoBienestarResponse, error) {
+	list, err := s.repo.List(f)
+	if err != nil {
+		return nil, err
+	}
+	return casosBienestarToResponse(list), nil
+}
+
+func (s *casoBienestarService) Get(id uint) (*dto.CasoBienestarDetalleResponse, error) {
+	c, err := s.repo.FindDetalle(id)
+	if err != nil {
+		return nil, ErrCasoBienestarNoEncontrado
+	}
+	resp := &dto.CasoBienestarDetalleResponse{
+		CasoBienestarResponse: casoBienestarToResponse(c),
+		Intervenciones:        make([]dto.CasoBienestarIntervencionResponse, len(c.Intervenciones)),
+		Adjuntos:              make([]dto.CasoBienestarAdjuntoResponse, len(c.Adjuntos)),
+		Recordatorios:         make([]dto.CasoBienestarRecordatorioResponse, len(c.Recordatorios)),
+	}
+	for i := range c.Intervenciones {
+		resp.Intervenciones[i] = intervencionToResponse(&c.Intervenciones[i])
+	}
+	for i := range c.Adjuntos {
+		resp.Adjuntos[i] = adjuntoCasoToResponse(&c.Adjuntos[i])
+	}
+	for i := range c.Recordatorios {
+		resp.Recordatorios[i] = recordatorioToResponse(&c.Recordatorios[i])
+	}
+	return resp, nil
+}
+
+// aplicarCambioEstadoCaso valida la transición y actualiza los datos de cierre. Reabrir limpia el cierre anterior.
+func aplicarCambioEstadoCaso(c *models.CasoBienestar, estado, observacionCierre string, userID uint, ahora time.Time) error {
+	observacionCierre = strings.TrimSpace(observacionCierre)
+	if estado == models.CasoBienestarEstadoCerrado {
+		if c.Estado == models.CasoBienestarEstadoCerrado {
+			if observacionCierre != "" {
+				c.ObservacionCierre = observacionCierre
+			}
+			return nil
+		}
+		if observacionCierre == "" {
+			return errCasoBienestarObservacionCierre
+		}
+		c.Estado = estado
+		c.CerradoAt = &ahora
+		c.CerradoPorUserID = &userID
+		c.ObservacionCierre = observacionCierre
+		return nil
+	}
+	c.Estado = estado
+	c.CerradoAt = nil
+	c.CerradoPorUserID = nil
+	c.ObservacionCierre = ""
+	return nil
+}
+
+func (s *casoBienestarService) Actualizar(actor dto.Actor, id uint, req dto.CasoBienestarUpdateRequest) (*dto.CasoBienestarResponse, error) {
+	antes, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrCasoBienestarNoEncontrado
+	}
+	if err := s.validarResponsable(req.ResponsableUserID); err != nil {
+		return nil, err
+	}
+	c := *antes
+	c.Aprendiz, c.Ficha, c.Responsable = nil, nil, nil
+	if err := aplicarCambioEstadoCaso(&c, req.Estado, req.ObservacionCierre, actor.UserID, utils.Now()); err != nil {
+		return nil, err
+	}
+	c.ResponsableUserID = req.ResponsableUserID
+	if err := s.repo.Update(&c); err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrCasoBienestarYaAbierto
+		}
+		return nil, err
+	}
+	despues, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	auditar(actor, AccionCasoBienestarActualizar, tablaCasosBienestar, id, antes, despues)
+	resp := casoBienestarToResponse(despues)
+	return &resp, nil
+}
+
+// casoAbiertoParaGestion el caso existe y no está cerrado.
+func (s *casoBienestarService) casoAbiertoParaGestion(id uint) (*models.CasoBienestar, error) {
+	c, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrCasoBienestarNoEncontrado
+	}
+	if c.Estado == models.CasoBienestarEstadoCerrado {
+		return nil, errCasoBienestarCerrado
+	}
+	return c, nil
+}
+
+// validarFechaIntervencion la gestión ya ocurrió (se admite un día de margen).
+func validarFechaIntervencion(s string, ahora time.Time) (time.Time, error) {
+	loc := utils.AppLocation()
+	fecha, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(s), loc)
+	if err != nil {
+		return time.Time{}, errCasoBienestarFechaInvalida
+	}
+	hoy := ahora.In(loc)
+	limite := time.Date(hoy.Year(), hoy.Month(), hoy.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, diasFuturosIntervencion)
+	if fecha.After(limite) {
+		return time.Time{}, errCasoBienestarFechaFutura
+	}
+	return fecha, nil
+}
+
+func (s *casoBienestarService) RegistrarIntervencion(actor dto.Actor, id uint, req dto.CasoBienestarIntervencionRequest) (*dto.CasoBienestarIntervencionResponse, error) {
+	c, err := s.casoAbiertoParaGestion(id)
+	if err != nil {
+		return nil, err
+	}
+	descripcion := strings.TrimSpace(req.Descripcion)
+	if descripcion == "" {
+		return nil, errCasoBienestarDescripcion
+	}
+	fecha, err := validarFechaIntervencion(req.Fecha, utils.Now())
+	if err != nil {
+		return nil, err
+	}
+	i := &models.CasoBienestarIntervencion{
+		CasoID:      id,
+		Fecha:       fecha,
+		Tipo:        req.Tipo,
+		Descripcion: descripcion,
+		Acuerdos:    strings.TrimSpace(req.Acuerdos),
+		AutorUserID: actor.UserID,
+	}
+	if err := s.repo.CreateIntervencion(i); err != nil {
+		return nil, err
+	}
+	// La primera gestión pasa el caso a seguimiento; también deja updated_at al día para el listado.
+	if c.Estado == models.CasoBienestarEstadoAbierto {
+		c.Estado = models.CasoBienestarEstadoEnSeguimiento
+	}
+	c.Aprendiz, c.Ficha, c.Responsable = nil, nil, nil
+	if err := s.repo.Update(c); err != nil {
+		log.Printf("[casos-bienestar] actualizando caso %d tras la gestión %d: %v", id, i.ID, err)
+	}
+	resp := intervencionToResponse(i)
+	return &resp, nil
+}
+
+func (s *casoBienestarService) SubirAdjunto(actor dto.Actor, id uint, contenido []byte, nombre string) (*dto.CasoBienestarAdjuntoResponse, error) {
+	if _, err := s.casoAbiertoParaGestion(id); err != nil {
+		return nil, err
+	}
+	ext, tipo, err := validarArchivoExcusa(contenido, nombre)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(casoBienestarArchivoDir, 0755); err != nil {
+		return nil, fmt.Errorf("crear directorio de adjuntos: %w", err)
+	}
+	ruta := filepath.Join(casoBienestarArchivoDir, fmt.Sprintf("caso_%d_%d%s", id, time.Now().UnixNano(), ext))
+	if err := os.WriteFile(ruta, contenido, 0644); err != nil {
+		return nil, fmt.Errorf("guardar adjunto: %w", err)
+	}
+	a := &models.CasoBienestarAdjunto{
+		CasoID:          id,
+		ArchivoNombre:   truncateStr(filepath.Base(nombre), 255),
+		ArchivoRuta:     ruta,
+		ArchivoTipo:     tipo,
+		ArchivoTamano:   int64(len(contenido)),
+		SubidoPorUserID: actor.UserID,
+	}
+	if err := s.repo.CreateAdjunto(a); err != nil {
+		_ = os.Remove(ruta)
+		return nil, err
+	}
+	resp := adjuntoCasoToResponse(a)
+	return &resp, nil
+}
+
+func (s *casoBienestarService) Adjunto(id, adjuntoID uint) (string, string, error) {
+	a, err := s.repo.FindAdjunto(id, adjuntoID)
+	if err != nil {
+		return "", "", ErrCasoBienestarAdjuntoNoExiste
+	}
+	return a.ArchivoRuta, a.ArchivoNombre, nil
+}
+
+func (s *casoBienestarService) CrearRecordatorio(actor dto.Actor, id uint, req dto.CasoBienestarRecordatorioRequest) (*dto.CasoBienestarRecordatorioResponse, error) {
+	if _, err := s.casoAbiertoParaGestion(id); err != nil {
+		return nil, err
+	}
+	nota := strings.TrimSpace(req.Nota)
+	if nota == "" {
+		return nil, errCasoBienestarNota
+	}
+	r := &models.CasoBienestarRecordatorio{
+		CasoID:            id,
+		FechaRecordatorio: req.FechaRecordatorio,
+		Nota:              nota,
+		CreadoPorUserID:   actor.UserID,
+	}
+	if err := s.repo.CreateRecordatorio(r); err != nil {
+		return nil, err
+	}
+	resp := recordatorioToResponse(r)
+	return &resp, nil
+}
+
+func (s *casoBienestarService) CompletarRecordatorio(id, recordatorioID uint) error {
+	ok, err := s.repo.CompletarRecordatorio(id, recordatorioID, utils.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errCasoBienestarRecordatorio
+	}
+	return nil
+}
+
+// finDelDia último instante del día de t en la zona de la aplicación.
+func finDelDia(t time.Time) time.Time {
+	loc := utils.AppLocation()
+	d := t.In(loc)
+	return time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, loc)
+}
+
+func (s *casoBienestarService) MisRecordatorios(userID uint) ([]dto.CasoBienestarRecordatorioResponse, error) {
+	list, err := s.repo.ListRecordatoriosPendientes(finDelDia(utils.Now()), &userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.CasoBienestarRecordatorioResponse, len(list))
+	for i := range list {
+		out[i] = recordatorioToResponse(&list[i])
+	}
+	return out, nil
+}
+
+func (s *casoBienestarService) HistorialAprendiz(aprendizID uint) ([]dto.CasoBienestarResponse, error) {
+	aprendiz, err := s.aprendizRepo.FindByID(aprendizID)
+	if err != nil || aprendiz == nil {
+		return nil, ErrCasoBienestarAprendizNoExiste
+	}
+	list, err := s.repo.ListByPersona(aprendiz.PersonaID)
+	if err != nil {
+		return nil, err
+	}
+	return casosBienestarToResponse(list), nil
+}
+
+func (s *casoBienestarService) Profesionales() ([]dto.ProfesionalBienestarItem, error) {
+	vistos := make(map[uint]bool)
+	out := []dto.ProfesionalBienestarItem{}
+	for _, rol := range rolesProfesionalBienestar {
+		for _, id := range s.usuariosConRol(rol) {
+			if vistos[id] {
+				continue
+			}
+			vistos[id] = true
+			u, err := s.userRepo.FindByID(id)
+			if err != nil || u == nil || !u.Status {
+				continue
+			}
+			item := dto.ProfesionalBienestarItem{UserID: u.ID, Nombre: u.Email, Email: u.Email}
+			if u.PersonaID != nil {
+				if p, err := s.personaRepo.FindByID(*u.PersonaID); err == nil && p != nil {
+					item.Nombre = p.GetFullName()
+				}
+			}
+			out = append(out, item)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Nombre < out[j].Nombre })
+	return out, nil
+}
+
+func (s *casoBienestarService) AnotarCasosAbiertos(casos []dto.CasoBienestarItem) {
+	if len(casos) == 0 {
+		return
+	}
+	ids := make([]uint, len(casos))
+	for i := range casos {
+		ids[i] = casos[i].AprendizID
+	}
+	abiertos, err := s.repo.MapAbiertosPorAprendiz(ids)
+	if err != nil {
+		log.Printf("[casos-bienestar] casos abiertos del listado: %v", err)
+		return
+	}
+	for i := range casos {
+		if c, ok := abiertos[casos[i].AprendizID]; ok {
+			id := c.ID
+			casos[i].CasoID = &id
+			casos[i].CasoEstado = c.Estado
+		}
+	}
+}
+
+// correoRecordatorioBienestar destinatario del aviso: el responsable del caso o, sin responsable, quien lo creó.
+func (s *casoBienestarService) correoRecordatorioBienestar(r *models.CasoBienestarRecordatorio) string {
+	if r.Caso != nil && r.Caso.Responsable != nil && r.Caso.Responsable.Status {
+		return strings.TrimSpace(r.Caso.Responsable.Email)
+	}
+	if u, err := s.userRepo.FindByID(r.CreadoPorUserID); err == nil && u != nil && u.Status {
+		return strings.TrimSpace(u.Email)
+	}
+	return ""
+}
+
+func mensajeRecordatorioBienestar(para string, r *models.CasoBienestarRecordatorio) (CorreoNuevo, error) {
+	aviso := &avisoCorreo{
+		correoBase: correoBase{Titulo: fmt.Sprintf("Recordatorio del caso de bienestar #%d", r.CasoID)},
+		Parrafos:   []string{"Tiene un recordatorio de seguimiento vencido:", r.Nota},
+		Boton: &botonCorreo{
+			Texto:  "Ver el caso",
+			Enlace: enlaceResumen(frontendURL(), rutaCasoBienestarResumen+strconv.FormatUint(uint64(r.CasoID), 10)),
+		},
+	}
+	if c := r.Caso; c != nil {
+		if c.Aprendiz != nil && c.Aprendiz.Persona != nil {
+			aviso.Datos = append(aviso.Datos, datoCorreo{
+				Etiqueta: "Aprendiz",
+				Valor:    fmt.Sprintf("%s (%s)", c.Aprendiz.Persona.GetFullName(), c.Aprendiz.Persona.NumeroDocumento),
+			})
+		}
+		if c.Ficha != nil {
+			aviso.Datos = append(aviso.Datos, datoCorreo{Etiqueta: "Ficha", Valor: c.Ficha.Ficha})
+		}
+	}
+	aviso.Datos = append(aviso.Datos, datoCorreo{
+		Etiqueta: "Fecha", Valor: r.FechaRecordatorio.In(utils.AppLocation()).Format("2006-01-02 15:04"),
+	})
+	html, texto, err := renderAviso(aviso)
+	if err != nil {
+		return CorreoNuevo{}, err
+	}
+	return CorreoNuevo{
+		Para: []string{para}, Asunto: fmt.Sprintf("Recordatorio caso de bienestar #%d", r.CasoID),
+		Plantilla: PlantillaCorreoRecordatorioBienestar, HTML: html, Texto: texto,
+	}, nil
+}
+
+func (s *casoBienestarService) NotificarRecordatorios() error {
+	list, err := s.repo.ListRecordatoriosPorNotificar(utils.Now())
+	if err != nil {
+		return fmt.Errorf("recordatorios de bienestar: %w", err)
+	}
+	var fallos int
+	for i := range list {
+		r := &list[i]
+		if email := s.correoRecordatorioBienestar(r); email != "" && smtpHabilitado() {
+			correo, err := mensajeRecordatorioBienestar(email, r)
+			if err == nil {
+				err = s.correos.Encolar(correo)
+			}
+			if err != nil {
+				log.Printf("[casos-bienestar] recordatorio %d: %v", r.ID, err)
+				fallos++
+				continue
+			}
+		}
+		if err := s.repo.MarcarRecordatorioNotificado(r.ID, utils.Now()); err != nil {
+			log.Printf("[casos-bienestar] marcando recordatorio %d: %v", r.ID, err)
+		}
+	}
+	if fallos > 0 {
+		return fmt.Errorf("%d de %d recordatorios no se pudieron enviar", fallos, len(list))
+	}
+	return nil
+}
+
+func (s *casoBienestarService) getResponse(id uint) (*dto.CasoBienestarResponse, error) {
+	c, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	resp := casoBienestarToResponse(c)
+	return &resp, nil
+}
+
+func formatFechaCaso(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func casoBienestarToResponse(c *models.CasoBienestar) dto.CasoBienestarResponse {
+	r := dto.CasoBienestarResponse{
+		ID:                        c.ID,
+		AprendizID:                c.AprendizID,
+		PersonaID:                 c.PersonaID,
+		FichaID:                   c.FichaID,
+		Origen:                    c.Origen,
+		Estado:                    c.Estado,
+		Motivo:                    c.Motivo,
+		ResponsableUserID:         c.ResponsableUserID,
+		PeriodoInicio:             formatFechaCaso(c.PeriodoInicio),
+		PeriodoFin:                formatFechaCaso(c.PeriodoFin),
+		Inasistencias:             c.Inasistencias,
+		InasistenciasJustificadas: c.InasistenciasJustificadas,
+		TotalSesiones:             c.TotalSesiones,
+		CreatedAt:                 c.CreatedAt,
+		UpdatedAt:                 c.UpdatedAt,
+		CerradoAt:                 c.CerradoAt,
+		ObservacionCierre:         c.ObservacionCierre,
+	}
+	if c.Aprendiz != nil && c.Aprendiz.Persona != nil {
+		r.AprendizNombre = c.Aprendiz.Persona.GetFullName()
+		r.NumeroDocumento = c.Aprendiz.Persona.NumeroDocumento
+	}
+	if c.Ficha != nil {
+		r.FichaNumero = c.Ficha.Ficha
+	}
+	if c.Responsable != nil {
+		r.ResponsableNombre = c.Responsable.Email
+		if c.Responsable.Persona != nil {
+			r.ResponsableNombre = c.Responsable.Persona.GetFullName()
+		}
+	}
+	return r
+}
+
+func casosBienestarToResponse(list []models.CasoBienestar) []dto.CasoBienestarResponse {
+	out := make([]dto.CasoBienestarResponse, len(list))
+	for i := range list {
+		out[i] = casoBienestarToResponse(&list[i])
+	}
+	return out
+}
+
+func intervencionToResponse(i *models.CasoBienestarIntervencion) dto.CasoBienestarIntervencionResponse {
+	r := dto.CasoBienestarIntervencionResponse{
+		ID:          i.ID,
+		Fecha:       i.Fecha.Format(time.DateOnly),
+		Tipo:        i.Tipo,
+		Descripcion: i.Descripcion,
+		Acuerdos:    i.Acuerdos,
+		AutorUserID: i.AutorUserID,
+		CreatedAt:   i.CreatedAt,
+	}
+	if i.Autor != nil {
+		r.AutorNombre = i.Autor.Email
+		if i.Autor.Persona != nil {
+			r.AutorNombre = i.Autor.Persona.GetFullName()
+		}
+	}
+	return r
+}
+
+func adjuntoCasoToResponse(a *models.CasoBienestarAdjunto) dto.CasoBienestarAdjuntoResponse {
+	return dto.CasoBienestarAdjuntoResponse{
+		ID:            a.ID,
+		ArchivoNombre: a.ArchivoNombre,
+		ArchivoTipo:   a.ArchivoTipo,
+		ArchivoTamano: a.ArchivoTamano,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+func recordatorioToResponse(r *models.CasoBienestarRecordatorio) dto.CasoBienestarRecordatorioResponse {
+	resp := dto.CasoBienestarRecordatorioResponse{
+		ID:                r.ID,
+		CasoID:            r.CasoID,
+		FechaRecordatorio: r.FechaRecordatorio,
+		Nota:              r.Nota,
+		CompletadoAt:      r.CompletadoAt,
+		NotificadoAt:      r.NotificadoAt,
+	}
+	if c := r.Caso; c != nil {
+		if c.Aprendiz != nil && c.Aprendiz.Persona != nil {
+			resp.AprendizNombre = c.Aprendiz.Persona.GetFullName()
+		}
+		if c.Ficha != nil {
+			resp.FichaNumero = c.Ficha.Ficha
+		}
+	}
+	return resp
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+func TestAplicarCambioEstadoCaso(t *testing.T) {
+	t.Parallel()
+	ahora := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	c := &models.CasoBienestar{Estado: models.CasoBienestarEstadoEnSeguimiento}
+
+	if err := aplicarCambioEstadoCaso(c, models.CasoBienestarEstadoCerrado, "  ", 4, ahora); !errors.Is(err, errCasoBienestarObservacionCierre) {
+		t.Fatalf("cerrar sin observación: err = %v", err)
+	}
+	if err := aplicarCambioEstadoCaso(c, models.CasoBienestarEstadoCerrado, "Compromiso cumplido", 4, ahora); err != nil {
+		t.Fatal(err)
+	}
+	if c.CerradoAt == nil || !c.CerradoAt.Equal(ahora) || c.CerradoPorUserID == nil || *c.CerradoPorUserID != 4 {
+		t.Fatalf("datos de cierre: %+v", c)
+	}
+	if err := aplicarCambioEstadoCaso(c, models.CasoBienestarEstadoEnSeguimiento, "", 5, ahora); err != nil {
+		t.Fatal(err)
+	}
+	if c.Estado != models.CasoBienestarEstadoEnSeguimiento || c.CerradoAt != nil || c.CerradoPorUserID != nil || c.ObservacionCierre != "" {
+		t.Fatalf("reabrir debe limpiar el cierre: %+v", c)
+	}
+}
+
+func TestValidarFechaIntervencion(t *testing.T) {
+	t.Parallel()
+	ahora := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	if _, err := validarFechaIntervencion("2026-03-09", ahora); err != nil {
+		t.Errorf("ayer: %v", err)
+	}
+	if _, err := validarFechaIntervencion("2026-03-20", ahora); !errors.Is(err, errCasoBienestarFechaFutura) {
+		t.Errorf("futura: err = %v", err)
+	}
+	if _, err := validarFechaIntervencion("10/03/2026", ahora); !errors.Is(err, errCasoBienestarFechaInvalida) {
+		t.Errorf("formato: err = %v", err)
+	}
+}
+
+type casoBienestarRepoStub struct {
+	repositories.CasoBienestarRepository
+	abiertos map[uint]models.CasoBienestar
+	creados  []models.CasoBienestar
+}
+
+func (r *casoBienestarRepoStub) MapAbiertosPorAprendiz(ids []uint) (map[uint]models.CasoBienestar, error) {
+	return r.abiertos, nil
+}
+
+func (r *casoBienestarRepoStub) Create(c *models.CasoBienestar) error {
+	c.ID = uint(100 + len(r.creados))
+	r.creados = append(r.creados, *c)
+	return nil
+}
+
+func (r *casoBienestarRepoStub) FindByID(id uint) (*models.CasoBienestar, error) {
+	for i := range r.creados {
+		if r.creados[i].ID == id {
+			return &r.creados[i], nil
+		}
+	}
+	return nil, errors.New("no encontrado")
+}
+
+type aprendizCasoStub struct {
+	repositories.AprendizRepository
+}
+
+func (aprendizCasoStub) FindByID(id uint) (*models.Aprendiz, error) {
+	return &models.Aprendiz{UserAuditModel: models.UserAuditModel{BaseModel: models.BaseModel{ID: id}}, PersonaID: id * 10, FichaCaracterizacionID: 7}, nil
+}
+
+func TestAbrirDesdeCalculoOmiteAbiertosYFueraDeUmbral(t *testing.T) {
+	t.Parallel()
+	repo := &casoBienestarRepoStub{abiertos: map[uint]models.CasoBienestar{2: {BaseModel: models.BaseModel{ID: 50}, AprendizID: 2}}}
+	svc := &casoBienestarService{
+		repo:         repo,
+		aprendizRepo: aprendizCasoStub{},
+		casosCalculados: func(*uint, int, int) (*dto.CasosBienestarResponse, error) {
+			return &dto.CasosBienestarResponse{
+				MinFallas:   3,
+				FechaInicio: "2026-02-01",
+				FechaFin:    "2026-03-01",
+				Casos: []dto.CasoBienestarItem{
+					{AprendizID: 1, Inasistencias: 4, TotalSesiones: 12, InasistenciasJustificadas: 1},
+					{AprendizID: 2, Inasistencias: 5, TotalSesiones: 12},
+				},
+			}, nil
+		},
+	}
+	resp, err := svc.AbrirDesdeCalculo(dto.Actor{UserID: 9}, dto.CasosBienestarAbrirRequest{AprendizIDs: []uint{1, 2, 3, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Creados) != 1 || len(resp.Omitidos) != 2 {
+		t.Fatalf("creados=%d omitidos=%d, want 1 y 2", len(resp.Creados), len(resp.Omitidos))
+	}
+	c := repo.creados[0]
+	if c.AprendizID != 1 || c.PersonaID != 10 || c.FichaID != 7 || c.Origen != models.CasoBienestarOrigenCalculo {
+		t.Fatalf("caso creado: %+v", c)
+	}
+	if c.Inasistencias != 4 || c.TotalSesiones != 12 || c.PeriodoInicio == nil || c.PeriodoInicio.Format(time.DateOnly) != "2026-02-01" {
+		t.Fatalf("foto del cálculo: %+v", c)
+	}
+	if resp.Omitidos[0].AprendizID != 2 || resp.Omitidos[0].CasoID == nil || *resp.Omitidos[0].CasoID != 50 {
+		t.Fatalf("el aprendiz con caso abierto debe remitir a ese caso: %+v", resp.Omitidos[0])
+	}
+	if resp.Omitidos[1].AprendizID != 3 {
+		t.Fatalf("omitido fuera de umbral: %+v", resp.Omitidos[1])
+	}
+}
@@ -349,6 +349,7 @@ func buildInasistenciaDetalleItemDesdeSlot(
 		}
 	}
 	return repositories.InasistenciaDetalleRow{
+		AsistenciaIDs:    slot.AsistenciaIDs,
 		Fecha:            fecha,
 		InstructorNombre: instructorNombre,
 		Observaciones:    observaciones,
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// Plantillas de correo. La versión HTML usa estilos en línea (los clientes de correo ignoran <style>) y va siempre
+// acompañada de la versión en texto plano. Cada plantilla HTML se define sobre el layout común (encabezado y pie
+// con la marca SENA); sus datos embeben correoBase.
+
+// correoBase campos que usa el layout común.
+type correoBase struct {
+	Titulo  string
+	Periodo string // subtítulo opcional bajo el título (p. ej. rango de fechas)
+	Nombre  string // para el saludo; vacío saluda sin nombre
+	// EnlacePreferencias si no está vacío, el pie invita a cancelar el envío desde el perfil.
+	EnlacePreferencias string
+}
+
+// Color institucional SENA.
+const colorCorreoSENA = "#39a900"
+
+const plantillaCorreoLayoutHTML = `{{define "encabezado"}}<!DOCTYPE html>
+<html lang="es"><body style="margin:0;padding:0;background:#f3f4f6;font-family:Arial,Helvetica,sans-serif;color:#111827;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background:#f3f4f6;padding:24px 0;"><tr><td align="center">
+<table role="presentation" width="640" cellpadding="0" cellspacing="0" style="max-width:640px;background:#ffffff;border-radius:8px;overflow:hidden;">
+<tr><td style="background:` + colorCorreoSENA + `;color:#ffffff;padding:20px 24px;">
+<div style="font-size:12px;text-transform:uppercase;letter-spacing:1px;">SENA · CDATTG Web</div>
+<div style="font-size:20px;font-weight:bold;margin-top:4px;">{{.Titulo}}</div>
+{{if .Periodo}}<div style="font-size:13px;margin-top:4px;">{{.Periodo}}</div>{{end}}
+</td></tr>
+<tr><td style="padding:24px;font-size:14px;line-height:1.5;">
+<p style="margin:0 0 16px;">Hola{{if .Nombre}} {{.Nombre}}{{end}},</p>{{end}}
+
+{{define "pie"}}</td></tr>
+<tr><td style="padding:16px 24px;border-top:1px solid #e5e7eb;font-size:12px;color:#6b7280;">
+{{if .EnlacePreferencias}}Recibe este resumen cada semana por su rol en CDATTG Web.
+<a href="{{.EnlacePreferencias}}" style="color:#2563eb;">Cancelar o reactivar los resúmenes</a> desde su perfil.<br>{{end}}
+Mensaje automático del Servicio Nacional de Aprendizaje SENA. Por favor no responda a este correo.
+</td></tr>
+</table></td></tr></table></body></html>{{end}}
+
+{{define "th"}}<th align="left" style="padding:6px 8px;border-bottom:2px solid #e5e7eb;font-size:12px;color:#374151;">{{.}}</th>{{end}}
+{{define "seccion"}}<h2 style="font-size:16px;margin:24px 0 8px;color:#111827;">{{.}}</h2>{{end}}
+{{define "vacio"}}<p style="margin:0;color:#6b7280;">{{.}}</p>{{end}}
+{{define "boton"}}<p style="margin:24px 0 0;"><a href="{{.Enlace}}" style="display:inline-block;background:` + colorCorreoSENA + `;color:#ffffff;padding:10px 16px;border-radius:6px;text-decoration:none;">{{.Texto}}</a></p>{{end}}`
+
+// botonCorreo enlace destacado al final del mensaje.
+type botonCorreo struct {
+	Texto  string
+	Enlace string
+}
+
+// datoCorreo fila etiqueta/valor del bloque de datos de un aviso.
+type datoCorreo struct {
+	Etiqueta string
+	Valor    string
+}
+
+// avisoCorreo mensaje corto: párrafos, datos en tabla, botón opcional y nota final en gris.
+type avisoCorreo struct {
+	correoBase
+	Parrafos []string
+	Datos    []datoCorreo
+	Boton    *botonCorreo
+	Nota     string
+}
+
+const plantillaCorreoAvisoHTML = `{{template "encabezado" .}}
+{{range .Parrafos}}<p style="margin:0 0 12px;">{{.}}</p>
+{{end}}{{if .Datos}}<table role="presentation" cellpadding="0" cellspacing="0" style="margin:8px 0 4px;">
+{{range .Datos}}<tr><td style="padding:4px 16px 4px 0;color:#6b7280;font-size:13px;vertical-align:top;">{{.Etiqueta}}</td>
+<td style="padding:4px 0;font-size:13px;">{{.Valor}}</td></tr>
+{{end}}</table>{{end}}
+{{with .Boton}}{{template "boton" .}}
+<p style="margin:12px 0 0;font-size:12px;color:#6b7280;">Si el botón no funciona, copie este enlace en el navegador:<br>{{.Enlace}}</p>{{end}}
+{{if .Nota}}<p style="margin:16px 0 0;font-size:13px;color:#6b7280;">{{.Nota}}</p>{{end}}
+{{template "pie" .}}`
+
+const plantillaCorreoAvisoTexto = `Hola{{if .Nombre}} {{.Nombre}}{{end}},
+
+{{range .Parrafos}}{{.}}
+
+{{end}}{{range .Datos}}{{.Etiqueta}}: {{.Valor}}
+{{end}}{{if .Datos}}
+{{end}}{{with .Boton}}{{.Texto}}: {{.Enlace}}
+
+{{end}}{{if .Nota}}{{.Nota}}
+
+{{end}}--
+SENA · CDATTG Web. Mensaje automático, por favor no responda.
+`
+
+// plantillaCorreoHTML parsea una plantilla HTML sobre el layout común.
+func plantillaCorreoHTML(nombre, contenido string) *htmltemplate.Template {
+	return htmltemplate.Must(htmltemplate.Must(
+		htmltemplate.New("layout").Parse(plantillaCorreoLayoutHTML)).New(nombre).Parse(contenido))
+}
+
+var (
+	tplCorreoAvisoHTML  = plantillaCorreoHTML("aviso", plantillaCorreoAvisoHTML)
+	tplCorreoAvisoTexto = texttemplate.Must(texttemplate.New("aviso").Parse(plantillaCorreoAvisoTexto))
+)
+
+// renderCorreo ejecuta la plantilla HTML y la de texto con los mismos datos.
+func renderCorreo(html *htmltemplate.Template, texto *texttemplate.Template, data any) (string, string, error) {
+	var h, t bytes.Buffer
+	if err := html.Execute(&h, data); err != nil {
+		return "", "", err
+	}
+	if err := texto.Execute(&t, data); err != nil {
+		return "", "", err
+	}
+	return h.String(), t.String(), nil
+}
+
+// renderAviso arma las dos versiones de un aviso.
+func renderAviso(a *avisoCorreo) (string, string, error) {
+	return renderCorreo(tplCorreoAvisoHTML, tplCorreoAvisoTexto, a)
+}
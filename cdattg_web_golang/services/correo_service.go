@@ -0,0 +1,421 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+	"gorm.io/gorm"
+)
+
+// Plantillas (origen) de los correos de la cola de salida.
+const (
+	PlantillaCorreoRecuperarPassword     = "recuperar-password"
+	PlantillaCorreoVerificarEmail        = "verificar-email"
+	PlantillaCorreoRecordatorioBienestar = "recordatorio-bienestar"
+	PlantillaCorreoAlertaAsistencia      = "alerta-asistencia"
+	PlantillaCorreoResumenCoordinador    = "resumen-coordinador"
+	PlantillaCorreoResumenInstructor     = "resumen-instructor"
+	PlantillaCorreoReporteSesion         = "reporte-sesion"
+	PlantillaCorreoRecordatorioVoto      = "recordatorio-voto"
+)
+
+const (
+	maxIntentosCorreoPorDefecto = 6
+	// esperaBaseReintentoCorreo espera tras el primer fallo; se duplica en cada intento hasta esperaMaxReintentoCorreo.
+	esperaBaseReintentoCorreo = 2 * time.Minute
+	esperaMaxReintentoCorreo  = 4 * time.Hour
+	// reservaEnvioCorreo tiempo que un correo tomado para envío queda fuera de la cola (por si el proceso cae).
+	reservaEnvioCorreo = 10 * time.Minute
+	loteEnvioCorreos   = 50
+	maxLotesPorCorrida = 10
+	// diasRetencionCorreos los enviados y fallidos se eliminan pasado este tiempo.
+	diasRetencionCorreos = 90
+	// maxBytesAdjuntosCorreo tope del total de adjuntos de un correo.
+	maxBytesAdjuntosCorreo = 10 << 20
+)
+
+var (
+	ErrCorreoNoEncontrado   = errors.New("correo no encontrado")
+	ErrCorreoNoReintentable = errors.New("solo se pueden reintentar correos fallidos que no contengan enlaces de un solo uso")
+	ErrCorreoSinDestino     = errors.New("el correo no tiene destinatarios")
+	ErrCorreoAdjuntosGrande = errors.New("los adjuntos superan el tamaño máximo de un correo (10 MB)")
+)
+
+// CorreoNuevo correo a encolar. Texto es obligatorio; HTML y adjuntos son opcionales.
+type CorreoNuevo struct {
+	Para      []string
+	Asunto    string
+	Plantilla string
+	HTML      string
+	Texto     string
+	Adjuntos  []utils.AdjuntoCorreo
+}
+
+// CorreoService cola de salida de correos: se guardan antes de enviarse y se reintentan con espera creciente.
+type CorreoService interface {
+	// Encolar guarda el correo e intenta enviarlo enseguida en segundo plano. Con SMTP deshabilitado devuelve
+	// utils.ErrSMTPDeshabilitado.
+	Encolar(c CorreoNuevo) error
+	// EnviarEnlace envía en el momento un correo con un enlace de un solo uso, sin pasar por la cola: solo queda
+	// registrado el resultado, sin el cuerpo, y un fallo no se reintenta.
+	EnviarEnlace(c CorreoNuevo) error
+	// ProcesarPendientes envía los correos cuyo intento ya venció (tarea programada).
+	ProcesarPendientes(ctx context.Context) error
+	// PurgarAntiguos elimina enviados y fallidos de más de 90 días.
+	PurgarAntiguos() error
+	Resumen() (*dto.CorreosSalientesResumen, error)
+	List(f repositories.CorreoSalienteFiltro) ([]dto.CorreoSalienteResponse, int64, error)
+	GetByID(id uint) (*dto.CorreoSalienteDetalleResponse, error)
+	GetAdjunto(correoID, adjuntoID uint) (*models.CorreoSalienteAdjunto, error)
+	// Reintentar devuelve a la cola un correo fallido.
+	Reintentar(id uint, actor dto.Actor) (*dto.CorreoSalienteResponse, error)
+}
+
+type correoService struct {
+	repo   repositories.CorreoSalienteRepository
+	enviar func(m utils.MensajeCorreo) error
+	// inmediato intenta el envío al encolar; en false solo lo hace la tarea programada.
+	inmediato bool
+}
+
+func NewCorreoService() CorreoService {
+	return &correoService{
+		repo:      repositories.NewCorreoSalienteRepository(),
+		enviar:    utils.EnviarMensaje,
+		inmediato: true,
+	}
+}
+
+func maxIntentosCorreo() int {
+	if config.AppConfig != nil && config.AppConfig.SMTP.MaxIntentos > 0 {
+		return config.AppConfig.SMTP.MaxIntentos
+	}
+	return maxIntentosCorreoPorDefecto
+}
+
+func (s *correoService) Encolar(c CorreoNuevo) error {
+	if !utils.SMTPHabilitado() {
+		return utils.ErrSMTPDeshabilitado
+	}
+	m, err := nuevoCorreoSaliente(c)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Create(m); err != nil {
+		return fmt.Errorf("encolar correo: %w", err)
+	}
+	if s.inmediato {
+		go s.enviarAhora(m.ID)
+	}
+	return nil
+}
+
+// EnviarEnlace el enlace vence en minutos, así que no se guarda en la cola ni se reintenta más tarde: quien lo
+// pidió puede solicitar otro.
+func (s *correoService) EnviarEnlace(c CorreoNuevo) error {
+	m, err := nuevoCorreoSaliente(c)
+	if err != nil {
+		return err
+	}
+	errEnvio := s.enviar(mensajeCorreoSaliente(m))
+	if errors.Is(errEnvio, utils.ErrSMTPDeshabilitado) {
+		return errEnvio
+	}
+	m.Sensible = true
+	registrarIntentoCorreo(m, errEnvio, utils.Now(), 1)
+	if err := s.repo.Create(m); err != nil {
+		log.Printf("[correo] registrando el envío de %q: %v", m.Asunto, err)
+	}
+	return errEnvio
+}
+
+// nuevoCorreoSaliente valida destinatarios y adjuntos y arma el registro pendiente.
+func nuevoCorreoSaliente(c CorreoNuevo) (*models.CorreoSaliente, error) {
+	para := make([]string, 0, len(c.Para))
+	for _, p := range c.Para {
+		if p = strings.TrimSpace(p); p != "" {
+			para = append(para, p)
+		}
+	}
+	if len(para) == 0 {
+		return nil, ErrCorreoSinDestino
+	}
+	var total int64
+	adjuntos := make([]models.CorreoSalienteAdjunto, 0, len(c.Adjuntos))
+	for _, a := range c.Adjuntos {
+		total += int64(len(a.Contenido))
+		ct := a.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		adjuntos = append(adjuntos, models.CorreoSalienteAdjunto{
+			Nombre: a.Nombre, ContentType: ct, Tamano: int64(len(a.Contenido)), Contenido: a.Contenido,
+		})
+	}
+	if total > maxBytesAdjuntosCorreo {
+		return nil, ErrCorreoAdjuntosGrande
+	}
+	ahora := utils.Now()
+	return &models.CorreoSaliente{
+		Destinatarios:    strings.Join(para, ", "),
+		Asunto:           truncarRunes(c.Asunto, 255),
+		Plantilla:        c.Plantilla,
+		CuerpoHTML:       c.HTML,
+		CuerpoTexto:      c.Texto,
+		Estado:           models.CorreoSalienteEstadoPendiente,
+		ProximoIntentoAt: &ahora,
+		Adjuntos:         adjuntos,
+	}, nil
+}
+
+// mensajeCorreoSaliente mensaje SMTP a partir del registro de la cola.
+func mensajeCorreoSaliente(c *models.CorreoSaliente) utils.MensajeCorreo {
+	msg := utils.MensajeCorreo{
+		Para:   strings.Split(c.Destinatarios, ", "),
+		Asunto: c.Asunto,
+		HTML:   c.CuerpoHTML,
+		Texto:  c.CuerpoTexto,
+	}
+	for _, a := range c.Adjuntos {
+		msg.Adjuntos = append(msg.Adjuntos, utils.AdjuntoCorreo{Nombre: a.Nombre, ContentType: a.ContentType, Contenido: a.Contenido})
+	}
+	return msg
+}
+
+// enviarAhora primer intento fuera de la tarea programada; si otra ejecución ya lo tomó, no hace nada.
+func (s *correoService) enviarAhora(id uint) {
+	ahora := utils.Now()
+	ok, err := s.repo.ReclamarPorID(id, ahora, ahora.Add(reservaEnvioCorreo))
+	if err != nil {
+		log.Printf("[correo] tomando correo %d: %v", id, err)
+		return
+	}
+	if ok {
+		_ = s.enviarReclamado(id)
+	}
+}
+
+func (s *correoService) ProcesarPendientes(ctx context.Context) error {
+	if !utils.SMTPHabilitado() {
+		return nil
+	}
+	var enviados, fallos int
+	for lote := 0; lote < maxLotesPorCorrida; lote++ {
+		ahora := utils.Now()
+		ids, err := s.repo.ReclamarPendientes(ahora, ahora.Add(reservaEnvioCorreo), loteEnvioCorreos)
+		if err != nil {
+			return fmt.Errorf("cola de correos: %w", err)
+		}
+		for _, id := range ids {
+			if ctx.Err() != nil {
+				// Los que quedan vuelven a la cola cuando vence la reserva.
+				return ctx.Err()
+			}
+			if s.enviarReclamado(id) {
+				enviados++
+			} else {
+				fallos++
+			}
+		}
+		if len(ids) < loteEnvioCorreos {
+			break
+		}
+	}
+	if enviados+fallos > 0 {
+		log.Printf("[correo] cola procesada: %d enviados, %d con error", enviados, fallos)
+	}
+	if fallos > 0 {
+		return fmt.Errorf("%d de %d correos no se pudieron enviar", fallos, enviados+fallos)
+	}
+	return nil
+}
+
+// enviarReclamado envía un correo ya tomado y registra el resultado. Devuelve true si se envió.
+func (s *correoService) enviarReclamado(id uint) bool {
+	c, err := s.repo.FindParaEnviar(id)
+	if err != nil {
+		log.Printf("[correo] cargando correo %d: %v", id, err)
+		return false
+	}
+	errEnvio := s.enviar(mensajeCorreoSaliente(c))
+	if errors.Is(errEnvio, utils.ErrSMTPDeshabilitado) {
+		// Se deshabilitó SMTP entre la reserva y el envío: no cuenta como intento.
+		return false
+	}
+	registrarIntentoCorreo(c, errEnvio, utils.Now(), maxIntentosCorreo())
+	if err := s.repo.Update(c); err != nil {
+		log.Printf("[correo] guardando resultado del correo %d: %v", id, err)
+	}
+	if errEnvio != nil {
+		log.Printf("[correo] correo %d (intento %d): %v", id, c.Intentos, errEnvio)
+	}
+	return errEnvio == nil
+}
+
+// registrarIntentoCorreo actualiza estado, intentos y próximo intento tras un envío. Los rechazos definitivos del
+// servidor (buzón inexistente...) y el último intento pasan el correo a fallido.
+func registrarIntentoCorreo(c *models.CorreoSaliente, errEnvio error, ahora time.Time, maxIntentos int) {
+	c.Intentos++
+	switch {
+	case errEnvio == nil:
+		c.Estado = models.CorreoSalienteEstadoEnviado
+		c.EnviadoAt = &ahora
+		c.ProximoIntentoAt = nil
+		c.UltimoError = ""
+	case c.Intentos >= maxIntentos || rechazoDefinitivoSMTP(errEnvio):
+		c.Estado = models.CorreoSalienteEstadoFallido
+		c.ProximoIntentoAt = nil
+		c.UltimoError = truncarRunes(errEnvio.Error(), 1000)
+	default:
+		proximo := ahora.Add(esperaReintentoCorreo(c.Intentos))
+		c.ProximoIntentoAt = &proximo
+		c.UltimoError = truncarRunes(errEnvio.Error(), 1000)
+	}
+	if c.Sensible && c.Estado != models.CorreoSalienteEstadoPendiente {
+		c.CuerpoHTML, c.CuerpoTexto = "", ""
+	}
+}
+
+// esperaReintentoCorreo espera antes del siguiente intento tras intentos fallidos: 2, 4, 8... minutos.
+func esperaReintentoCorreo(intentos int) time.Duration {
+	espera := esperaBaseReintentoCorreo
+	for i := 1; i < intentos && espera < esperaMaxReintentoCorreo; i++ {
+		espera *= 2
+	}
+	if espera > esperaMaxReintentoCorreo {
+		return esperaMaxReintentoCorreo
+	}
+	return espera
+}
+
+// rechazoDefinitivoSMTP respuestas 55x del servidor: buzón inexistente, dirección inválida o mensaje rechazado.
+// Reintentar no cambia el resultado.
+func rechazoDefinitivoSMTP(err error) bool {
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr) && tpErr.Code >= 550 && tpErr.Code <= 554
+}
+
+func (s *correoService) PurgarAntiguos() error {
+	n, err := s.repo.DeleteTerminadosAntesDe(utils.Now().AddDate(0, 0, -diasRetencionCorreos))
+	if err != nil {
+		return fmt.Errorf("limpieza de correos: %w", err)
+	}
+	if n > 0 {
+		log.Printf("[correo] correos antiguos eliminados: %d", n)
+	}
+	return nil
+}
+
+func (s *correoService) Resumen() (*dto.CorreosSalientesResumen, error) {
+	conteo, err := s.repo.CountPorEstado()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CorreosSalientesResumen{
+		SMTPHabilitado: utils.SMTPHabilitado(),
+		MaxIntentos:    maxIntentosCorreo(),
+		Pendientes:     conteo[models.CorreoSalienteEstadoPendiente],
+		Enviados:       conteo[models.CorreoSalienteEstadoEnviado],
+		Fallidos:       conteo[models.CorreoSalienteEstadoFallido],
+	}, nil
+}
+
+func (s *correoService) List(f repositories.CorreoSalienteFiltro) ([]dto.CorreoSalienteResponse, int64, error) {
+	list, total, err := s.repo.List(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]dto.CorreoSalienteResponse, len(list))
+	for i := range list {
+		out[i] = correoSalienteToResponse(&list[i])
+	}
+	return out, total, nil
+}
+
+func (s *correoService) GetByID(id uint) (*dto.CorreoSalienteDetalleResponse, error) {
+	c, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCorreoNoEncontrado
+		}
+		return nil, err
+	}
+	out := &dto.CorreoSalienteDetalleResponse{
+		CorreoSalienteResponse: correoSalienteToResponse(c),
+		Adjuntos:               make([]dto.CorreoSalienteAdjuntoResponse, 0, len(c.Adjuntos)),
+	}
+	if !c.Sensible {
+		out.CuerpoHTML, out.CuerpoTexto = c.CuerpoHTML, c.CuerpoTexto
+	}
+	for _, a := range c.Adjuntos {
+		out.Adjuntos = append(out.Adjuntos, dto.CorreoSalienteAdjuntoResponse{
+			ID: a.ID, Nombre: a.Nombre, ContentType: a.ContentType, Tamano: a.Tamano,
+		})
+	}
+	return out, nil
+}
+
+func (s *correoService) GetAdjunto(correoID, adjuntoID uint) (*models.CorreoSalienteAdjunto, error) {
+	a, err := s.repo.FindAdjunto(correoID, adjuntoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCorreoNoEncontrado
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *correoService) Reintentar(id uint, actor dto.Actor) (*dto.CorreoSalienteResponse, error) {
+	c, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCorreoNoEncontrado
+		}
+		return nil, err
+	}
+	if c.Estado != models.CorreoSalienteEstadoFallido || c.Sensible {
+		return nil, ErrCorreoNoReintentable
+	}
+	antes := map[string]interface{}{"estado": c.Estado, "intentos": c.Intentos, "ultimo_error": c.UltimoError}
+	ahora := utils.Now()
+	c.Estado = models.CorreoSalienteEstadoPendiente
+	c.Intentos = 0
+	c.ProximoIntentoAt = &ahora
+	if err := s.repo.Update(c); err != nil {
+		return nil, err
+	}
+	auditar(actor, AccionCorreoReintentar, tablaCorreosSalientes, c.ID, antes,
+		map[string]interface{}{"estado": c.Estado, "intentos": c.Intentos, "ultimo_error": c.UltimoError})
+	if s.inmediato && utils.SMTPHabilitado() {
+		go s.enviarAhora(c.ID)
+	}
+	out := correoSalienteToResponse(c)
+	return &out, nil
+}
+
+func correoSalienteToResponse(c *models.CorreoSaliente) dto.CorreoSalienteResponse {
+	return dto.CorreoSalienteResponse{
+		ID:               c.ID,
+		Destinatarios:    strings.Split(c.Destinatarios, ", "),
+		Asunto:           c.Asunto,
+		Plantilla:        c.Plantilla,
+		Estado:           c.Estado,
+		Intentos:         c.Intentos,
+		ProximoIntentoAt: c.ProximoIntentoAt,
+		UltimoError:      c.UltimoError,
+		EnviadoAt:        c.EnviadoAt,
+		Sensible:         c.Sensible,
+		CreatedAt:        c.CreatedAt,
+	}
+}
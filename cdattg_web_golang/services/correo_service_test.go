@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+func TestEsperaReintentoCorreo(t *testing.T) {
+	casos := map[int]time.Duration{
+		1:  2 * time.Minute,
+		2:  4 * time.Minute,
+		3:  8 * time.Minute,
+		7:  128 * time.Minute,
+		8:  4 * time.Hour,
+		20: 4 * time.Hour,
+	}
+	for intentos, want := range casos {
+		if got := esperaReintentoCorreo(intentos); got != want {
+			t.Errorf("esperaReintentoCorreo(%d) = %v, want %v", intentos, got, want)
+		}
+	}
+}
+
+func TestRegistrarIntentoCorreo(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	nuevo := func(sensible bool) *models.CorreoSaliente {
+		return &models.CorreoSaliente{
+			Estado: models.CorreoSalienteEstadoPendiente, Sensible: sensible,
+			CuerpoHTML: "<p>enlace</p>", CuerpoTexto: "enlace",
+		}
+	}
+
+	t.Run("enviado", func(t *testing.T) {
+		c := nuevo(false)
+		c.UltimoError = "timeout"
+		registrarIntentoCorreo(c, nil, ahora, 3)
+		if c.Estado != models.CorreoSalienteEstadoEnviado || c.Intentos != 1 || c.EnviadoAt == nil || c.ProximoIntentoAt != nil || c.UltimoError != "" {
+			t.Fatalf("correo = %+v", c)
+		}
+		if c.CuerpoTexto == "" {
+			t.Fatal("un correo no sensible conserva el cuerpo")
+		}
+	})
+
+	t.Run("error temporal reintenta con espera", func(t *testing.T) {
+		c := nuevo(false)
+		registrarIntentoCorreo(c, errors.New("dial tcp: connection refused"), ahora, 3)
+		registrarIntentoCorreo(c, &textproto.Error{Code: 421, Msg: "servicio no disponible"}, ahora, 3)
+		if c.Estado != models.CorreoSalienteEstadoPendiente || c.Intentos != 2 {
+			t.Fatalf("correo = %+v", c)
+		}
+		if c.ProximoIntentoAt == nil || !c.ProximoIntentoAt.Equal(ahora.Add(4*time.Minute)) {
+			t.Fatalf("próximo intento = %v", c.ProximoIntentoAt)
+		}
+		if !strings.Contains(c.UltimoError, "421") {
+			t.Fatalf("último error = %q", c.UltimoError)
+		}
+	})
+
+	t.Run("agota los intentos", func(t *testing.T) {
+		c := nuevo(false)
+		for i := 0; i < 3; i++ {
+			registrarIntentoCorreo(c, errors.New("timeout"), ahora, 3)
+		}
+		if c.Estado != models.CorreoSalienteEstadoFallido || c.ProximoIntentoAt != nil {
+			t.Fatalf("correo = %+v", c)
+		}
+	})
+
+	t.Run("rechazo definitivo no se reintenta", func(t *testing.T) {
+		c := nuevo(true)
+		registrarIntentoCorreo(c, &textproto.Error{Code: 550, Msg: "buzón no existe"}, ahora, 6)
+		if c.Estado != models.CorreoSalienteEstadoFallido || c.Intentos != 1 {
+			t.Fatalf("correo = %+v", c)
+		}
+		if c.CuerpoHTML != "" || c.CuerpoTexto != "" {
+			t.Fatal("un correo sensible terminado no debe conservar el cuerpo")
+		}
+	})
+}
+
+// correoRepoFake guarda en memoria los correos; los métodos no usados en las pruebas quedan sin implementar.
+type correoRepoFake struct {
+	repositories.CorreoSalienteRepository
+	correos map[uint]*models.CorreoSaliente
+}
+
+func (r *correoRepoFake) FindParaEnviar(id uint) (*models.CorreoSaliente, error) {
+	c, ok := r.correos[id]
+	if !ok {
+		return nil, errors.New("no existe")
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *correoRepoFake) Update(c *models.CorreoSaliente) error {
+	cp := *c
+	r.correos[c.ID] = &cp
+	return nil
+}
+
+func (r *correoRepoFake) Create(c *models.CorreoSaliente) error {
+	c.ID = uint(len(r.correos) + 1)
+	return r.Update(c)
+}
+
+func TestEnviarReclamado(t *testing.T) {
+	prev := config.AppConfig
+	config.AppConfig = &config.Config{SMTP: config.SMTPConfig{MaxIntentos: 2}}
+	t.Cleanup(func() { config.AppConfig = prev })
+
+	repo := &correoRepoFake{correos: map[uint]*models.CorreoSaliente{
+		1: {ID: 1, Destinatarios: "a@sena.edu.co, b@sena.edu.co", Asunto: "Reporte", CuerpoTexto: "hola",
+			Estado:   models.CorreoSalienteEstadoPendiente,
+			Adjuntos: []models.CorreoSalienteAdjunto{{Nombre: "r.pdf", ContentType: "application/pdf", Contenido: []byte("%PDF")}}},
+	}}
+	var enviados []utils.MensajeCorreo
+	fallar := true
+	svc := &correoService{repo: repo, enviar: func(m utils.MensajeCorreo) error {
+		if fallar {
+			return errors.New("timeout")
+		}
+		enviados = append(enviados, m)
+		return nil
+	}}
+
+	if svc.enviarReclamado(1) {
+		t.Fatal("el primer intento falla")
+	}
+	if c := repo.correos[1]; c.Estado != models.CorreoSalienteEstadoPendiente || c.Intentos != 1 {
+		t.Fatalf("tras el fallo = %+v", c)
+	}
+	fallar = false
+	if !svc.enviarReclamado(1) {
+		t.Fatal("el segundo intento se envía")
+	}
+	if c := repo.correos[1]; c.Estado != models.CorreoSalienteEstadoEnviado || c.Intentos != 2 {
+		t.Fatalf("tras el envío = %+v", c)
+	}
+	if len(enviados) != 1 || len(enviados[0].Para) != 2 || len(enviados[0].Adjuntos) != 1 || enviados[0].Adjuntos[0].Nombre != "r.pdf" {
+		t.Fatalf("mensaje enviado = %+v", enviados)
+	}
+}
+
+func TestEncolar_sinSMTP(t *testing.T) {
+	prev := config.AppConfig
+	config.AppConfig = &config.Config{}
+	t.Cleanup(func() { config.AppConfig = prev })
+
+	repo := &correoRepoFake{correos: map[uint]*models.CorreoSaliente{}}
+	svc := &correoService{repo: repo}
+	err := svc.Encolar(CorreoNuevo{Para: []string{"a@sena.edu.co"}, Asunto: "Resumen", Texto: "hola"})
+	if !errors.Is(err, utils.ErrSMTPDeshabilitado) {
+		t.Fatalf("err = %v, want ErrSMTPDeshabilitado", err)
+	}
+	if len(repo.correos) != 0 {
+		t.Fatal("sin SMTP no se encola nada")
+	}
+}
+
+func TestEnviarEnlace(t *testing.T) {
+	correo := CorreoNuevo{
+		Para: []string{"a@sena.edu.co"}, Asunto: "Restablecer contraseña", Plantilla: PlantillaCorreoRecuperarPassword,
+		HTML: "<p>enlace</p>", Texto: "enlace",
+	}
+
+	t.Run("enviado sin cuerpo guardado", func(t *testing.T) {
+		repo := &correoRepoFake{correos: map[uint]*models.CorreoSaliente{}}
+		var enviados []utils.MensajeCorreo
+		svc := &correoService{repo: repo, enviar: func(m utils.MensajeCorreo) error {
+			enviados = append(enviados, m)
+			return nil
+		}}
+		if err := svc.EnviarEnlace(correo); err != nil {
+			t.Fatal(err)
+		}
+		if len(enviados) != 1 || enviados[0].Texto != "enlace" {
+			t.Fatalf("mensaje enviado = %+v", enviados)
+		}
+		c := repo.correos[1]
+		if c == nil || c.Estado != models.CorreoSalienteEstadoEnviado || !c.Sensible || c.CuerpoHTML != "" || c.CuerpoTexto != "" {
+			t.Fatalf("registro = %+v", c)
+		}
+	})
+
+	t.Run("un fallo no queda para reintentar", func(t *testing.T) {
+		repo := &correoRepoFake{correos: map[uint]*models.CorreoSaliente{}}
+		svc := &correoService{repo: repo, enviar: func(utils.MensajeCorreo) error {
+			return errors.New("dial tcp: connection refused")
+		}}
+		if err := svc.EnviarEnlace(correo); err == nil {
+			t.Fatal("se esperaba el error del envío")
+		}
+		c := repo.correos[1]
+		if c == nil || c.Estado != models.CorreoSalienteEstadoFallido || c.ProximoIntentoAt != nil || c.CuerpoTexto != "" {
+			t.Fatalf("registro = %+v", c)
+		}
+	})
+
+	t.Run("sin SMTP no registra nada", func(t *testing.T) {
+		repo := &correoRepoFake{correos: map[uint]*models.CorreoSaliente{}}
+		svc := &correoService{repo: repo, enviar: func(utils.MensajeCorreo) error { return utils.ErrSMTPDeshabilitado }}
+		if err := svc.EnviarEnlace(correo); !errors.Is(err, utils.ErrSMTPDeshabilitado) {
+			t.Fatalf("err = %v", err)
+		}
+		if len(repo.correos) != 0 {
+			t.Fatal("no debe quedar registro")
+		}
+	})
+}
+
+func TestRenderAviso_escapaContenido(t *testing.T) {
+	html, texto, err := renderAviso(&avisoCorreo{
+		correoBase: correoBase{Titulo: "Recordatorio", Nombre: "Ana <b>Ruiz</b>"},
+		Parrafos:   []string{"Caso de <script>alert(1)</script>"},
+		Datos:      []datoCorreo{{Etiqueta: "Ficha", Valor: "2900001"}},
+		Boton:      &botonCorreo{Texto: "Ver el caso", Enlace: "https://cdattg.sena.edu.co/bienestar/casos/7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") || strings.Contains(html, "<b>Ruiz</b>") {
+		t.Fatal("el HTML debe escapar los datos")
+	}
+	if !strings.Contains(html, `href="https://cdattg.sena.edu.co/bienestar/casos/7"`) {
+		t.Fatal("falta el enlace del botón")
+	}
+	if !strings.Contains(texto, "Hola Ana <b>Ruiz</b>,") || !strings.Contains(texto, "Ficha: 2900001") ||
+		!strings.Contains(texto, "Ver el caso: https://cdattg.sena.edu.co/bienestar/casos/7") {
+		t.Fatalf("texto = %q", texto)
+	}
+}
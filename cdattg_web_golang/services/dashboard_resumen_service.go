@@ -23,6 +23,7 @@ type dashboardResumenService struct {
 	catalogoRepo repositories.CatalogoRepository
 	instructorRepo repositories.InstructorRepository
 	calendario   *CalendarioFormacionService
+	riesgoSvc    *RiesgoDesercionService
 }
 
 func NewDashboardResumenService() DashboardResumenService {
@@ -36,6 +37,7 @@ func NewDashboardResumenService() DashboardResumenService {
 		catalogoRepo:   repositories.NewCatalogoRepository(),
 		instructorRepo: repositories.NewInstructorRepository(),
 		calendario:     calendario,
+		riesgoSvc:      NewRiesgoDesercionService(),
 	}
 }
 
@@ -139,7 +141,11 @@ func (s *dashboardResumenService) fillDashboardResumen(
 	resp.PorFicha = dashboardPorFichaToDTO(porFicha)
 	resp.PorSede, resp.PorJornada, resp.PorRegional = aggregateDashboardDimensions(porFicha, sinSesionDTO, s.catalogoRepo)
 	resp.Institucion = s.buildInstitucionStats(sedeIDs, scope)
-	resp.Riesgo = s.buildRiesgoStats(singleSede, sedeIDs, pendientes)
+	riesgo, errRiesgo := s.buildRiesgoStats(singleSede, sedeIDs, pendientes)
+	if errRiesgo != nil {
+		return nil, errRiesgo
+	}
+	resp.Riesgo = riesgo
 	ultimosDias, errUltimos := buildUltimosDiasFormacion(s.asistRepo, s.fichaRepo, s.aprendizRepo, sedeIDs, fecha)
 	if errUltimos != nil {
 		return nil, errUltimos
@@ -268,11 +274,26 @@ func (s *dashboardResumenService) buildInstitucionStats(sedeIDs []uint, scope *D
 	return stats
 }
 
-func (s *dashboardResumenService) buildRiesgoStats(singleSede *uint, sedeIDs []uint, pendientes int) dto.DashboardRiesgoStats {
+func (s *dashboardResumenService) buildRiesgoStats(singleSede *uint, sedeIDs []uint, pendientes int) (dto.DashboardRiesgoStats, error) {
+	alto, err := countRiesgoDesercionAltoScoped(s.riesgoSvc, singleSede, sedeIDs)
+	if err != nil {
+		return dto.DashboardRiesgoStats{}, err
+	}
 	return dto.DashboardRiesgoStats{
-		CasosBienestar:     countCasosBienestarScoped(s.asistenciaSvc, singleSede, sedeIDs),
-		PendientesRevision: pendientes,
+		CasosBienestar:      countCasosBienestarScoped(s.asistenciaSvc, singleSede, sedeIDs),
+		PendientesRevision:  pendientes,
+		RiesgoDesercionAlto: alto,
+	}, nil
+}
+
+func countRiesgoDesercionAltoScoped(svc *RiesgoDesercionService, singleSede *uint, sedeIDs []uint) (int, error) {
+	if svc == nil {
+		return 0, nil
+	}
+	if len(sedeIDs) <= 1 {
+		return svc.ContarAlto(singleSede)
 	}
+	return svc.ContarAltoSedes(sedeIDs)
 }
 
 func countCasosBienestarScoped(svc AsistenciaService, singleSede *uint, sedeIDs []uint) int {
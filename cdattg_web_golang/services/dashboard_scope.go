@@ -14,6 +14,22 @@ type DashboardScope struct {
 	SedeIDs       []uint
 }
 
+// IncluyeSede indica si la sede entra en el alcance; sin restricción entran todas (también las fichas sin sede).
+func (s *DashboardScope) IncluyeSede(sedeID *uint) bool {
+	if s == nil || !s.Restricted {
+		return true
+	}
+	if s.Empty || sedeID == nil {
+		return false
+	}
+	for _, id := range s.SedeIDs {
+		if id == *sedeID {
+			return true
+		}
+	}
+	return false
+}
+
 // DashboardScopeService resuelve alcance territorial del dashboard.
 type DashboardScopeService interface {
 	Resolve(userID uint, roles []string) (*DashboardScope, error)
@@ -57,3 +57,20 @@ func TestHasRole(t *testing.T) {
 		t.Fatal("expected role match")
 	}
 }
+
+func TestDashboardScopeIncluyeSede(t *testing.T) {
+	s1 := uint(1)
+	s2 := uint(2)
+	coordinador := &DashboardScope{Restricted: true, SedeIDs: []uint{1}}
+	if !coordinador.IncluyeSede(&s1) || coordinador.IncluyeSede(&s2) || coordinador.IncluyeSede(nil) {
+		t.Fatal("coordinador: solo la sede de su regional")
+	}
+	sinRegional := &DashboardScope{Restricted: true, Empty: true}
+	if sinRegional.IncluyeSede(&s1) {
+		t.Fatal("coordinador sin regional no debe recibir nada")
+	}
+	var global *DashboardScope
+	if !global.IncluyeSede(&s2) || !(&DashboardScope{}).IncluyeSede(nil) {
+		t.Fatal("sin restricción incluye todas las sedes")
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
+)
+
+// eleccionActaFormato versión del contenido del acta; cambia si cambia la estructura de eleccionActaContenido.
+const eleccionActaFormato = 2
+
+// eleccionActaContenido lo que certifica el acta. Se guarda como JSON y el hash del acta es el SHA-256 de ese
+// JSON, así que el PDF se imprime siempre desde lo guardado y no desde datos que puedan cambiar después.
+type eleccionActaContenido struct {
+	Formato    int                    `json:"formato"`
+	Proceso    eleccionActaProceso    `json:"proceso"`
+	Planchas   []eleccionActaPlancha  `json:"planchas"`
+	Escrutinio eleccionActaEscrutinio `json:"escrutinio"`
+	Elegibles  int64                  `json:"elegibles"`
+	Desempate  *eleccionActaDesempate `json:"desempate,omitempty"`
+	CerradaAt  time.Time              `json:"cerrada_at"`
+}
+
+type eleccionActaProceso struct {
+	ID                     uint       `json:"id"`
+	NombreCiclo            string     `json:"nombre_ciclo"`
+	Anio                   int        `json:"anio"`
+	Regional               string     `json:"regional"`
+	Cargo                  string     `json:"cargo"` // p. ej. "vocero de la ficha 2567890"; desde el formato 2
+	FechaInscripcionInicio *time.Time `json:"fecha_inscripcion_inicio,omitempty"`
+	FechaInscripcionFin    *time.Time `json:"fecha_inscripcion_fin,omitempty"`
+	FechaVotacionInicio    *time.Time `json:"fecha_votacion_inicio,omitempty"`
+	FechaVotacionFin       *time.Time `json:"fecha_votacion_fin,omitempty"`
+}
+
+type eleccionActaPlancha struct {
+	ID       uint                `json:"id"`
+	Titular  eleccionActaPersona `json:"titular"`
+	Suplente eleccionActaPersona `json:"suplente"`
+}
+
+type eleccionActaPersona struct {
+	Nombre    string `json:"nombre"`
+	Documento string `json:"documento,omitempty"`
+}
+
+// eleccionActaEscrutinio parte del acta que se puede recalcular desde la urna para comprobar que no cambió.
+type eleccionActaEscrutinio struct {
+	Votos             []eleccionActaVotos `json:"votos"`
+	VotosTotales      int                 `json:"votos_totales"`
+	Participaciones   int                 `json:"participaciones"`
+	Papeletas         int                 `json:"papeletas"`
+	PapeletasSHA256   string              `json:"papeletas_sha256"`
+	PlanchaGanadoraID *uint               `json:"plancha_ganadora_id,omitempty"`
+}
+
+type eleccionActaVotos struct {
+	PlanchaID uint `json:"plancha_id"`
+	Votos     int  `json:"votos"`
+}
+
+type eleccionActaDesempate struct {
+	PlanchasEmpatadas []uint     `json:"planchas_empatadas"`
+	Metodo            string     `json:"metodo,omitempty"`
+	Nota              string     `json:"nota,omitempty"`
+	RegistradoAt      *time.Time `json:"registrado_at,omitempty"`
+}
+
+// hashActa SHA-256 en hexadecimal del contenido guardado.
+func hashActa(contenido string) string {
+	sum := sha256.Sum256([]byte(contenido))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashPapeletas SHA-256 de las líneas "recibo_hash:plancha_id" ordenadas por recibo. Se puede recalcular desde
+// la exportación de auditoría (CSV) sin acceso a la base.
+func hashPapeletas(papeletas []models.EleccionPapeleta) string {
+	h := sha256.New()
+	for _, p := range papeletas {
+		fmt.Fprintf(h, "%s:%d\n", p.ReciboHash, p.PlanchaID)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// escrutinioActual recuenta la urna del proceso con el mismo formato que guarda el acta.
+func (s *eleccionService) escrutinioActual(procesoID uint) (*eleccionActaEscrutinio, []models.EleccionPlancha, error) {
+	planchas, err := s.repo.ListPlanchasByProceso(procesoID, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	papeletas, err := s.repo.ListPapeletasByProceso(procesoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	participaciones, err := s.repo.CountParticipacionesByProceso(procesoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	votos := make(map[uint]int, len(planchas))
+	for _, p := range papeletas {
+		votos[p.PlanchaID]++
+	}
+	esc := &eleccionActaEscrutinio{
+		Votos:           make([]eleccionActaVotos, len(planchas)),
+		VotosTotales:    len(papeletas),
+		Participaciones: int(participaciones),
+		Papeletas:       len(papeletas),
+		PapeletasSHA256: hashPapeletas(papeletas),
+	}
+	for i := range planchas {
+		esc.Votos[i] = eleccionActaVotos{PlanchaID: planchas[i].ID, Votos: votos[planchas[i].ID]}
+	}
+	if res, err := s.repo.FindResultadoByProceso(procesoID); err == nil {
+		esc.PlanchaGanadoraID = res.PlanchaGanadoraID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+	return esc, planchas, nil
+}
+
+func actaPersona(a *models.Aprendiz) eleccionActaPersona {
+	out := eleccionActaPersona{Nombre: nombreAprendiz(a)}
+	if a != nil && a.Persona != nil {
+		out.Documento = a.Persona.NumeroDocumento
+	}
+	return out
+}
+
+// construirActa arma el contenido del acta de un proceso cerrado a partir de la urna y el resultado registrado.
+func (s *eleccionService) construirActa(p *models.EleccionProceso) (*eleccionActaContenido, error) {
+	esc, planchas, err := s.escrutinioActual(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.repo.FindResultadoByProceso(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if res.CerradaAt == nil {
+		return nil, fmt.Errorf("el resultado del proceso %d no tiene fecha de cierre", p.ID)
+	}
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return nil, err
+	}
+	elegibles, err := s.repo.CountPadron(ambito)
+	if err != nil {
+		return nil, err
+	}
+	c := &eleccionActaContenido{
+		Formato: eleccionActaFormato,
+		Proceso: eleccionActaProceso{
+			ID:                     p.ID,
+			NombreCiclo:            p.NombreCiclo,
+			Anio:                   p.Anio,
+			Cargo:                  cargoElegido(p),
+			FechaInscripcionInicio: p.FechaInscripcionInicio,
+			FechaInscripcionFin:    p.FechaInscripcionFin,
+			FechaVotacionInicio:    p.FechaVotacionInicio,
+			FechaVotacionFin:       p.FechaVotacionFin,
+		},
+		Planchas:   make([]eleccionActaPlancha, len(planchas)),
+		Escrutinio: *esc,
+		Elegibles:  elegibles,
+		CerradaAt:  res.CerradaAt.UTC(),
+	}
+	if p.Regional != nil {
+		c.Proceso.Regional = p.Regional.Nombre
+	}
+	for i := range planchas {
+		c.Planchas[i] = eleccionActaPlancha{
+			ID:       planchas[i].ID,
+			Titular:  actaPersona(planchas[i].TitularAprendiz),
+			Suplente: actaPersona(planchas[i].SuplenteAprendiz),
+		}
+	}
+	if res.DesempateMetodo != nil || res.NotaDesempate != nil {
+		conteo := make([]dto.EleccionResultadoPlanchaConteo, len(esc.Votos))
+		for i, v := range esc.Votos {
+			conteo[i] = dto.EleccionResultadoPlanchaConteo{PlanchaID: v.PlanchaID, Votos: v.Votos}
+		}
+		// Los desempates registrados antes de guardar el método solo tienen la nota.
+		c.Desempate = &eleccionActaDesempate{PlanchasEmpatadas: planchasEmpatadas(conteo), RegistradoAt: res.DesempateAt}
+		if res.DesempateMetodo != nil {
+			c.Desempate.Metodo = *res.DesempateMetodo
+		}
+		if res.NotaDesempate != nil {
+			c.Desempate.Nota = *res.NotaDesempate
+		}
+	}
+	return c, nil
+}
+
+// asegurarActa devuelve el acta del proceso y la genera si todavía no existe. Una vez generada no cambia. Sin
+// usuario la generó el planificador al cerrar la votación.
+func (s *eleccionService) asegurarActa(userID *uint, p *models.EleccionProceso) (*models.EleccionActa, error) {
+	if p.Estado != models.EleccionEstadoCerrada {
+		return nil, errEleccionActaProcesoAbierto
+	}
+	acta, err := s.repo.FindActaByProceso(p.ID)
+	if err == nil {
+		return acta, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	contenido, err := s.construirActa(p)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(contenido)
+	if err != nil {
+		return nil, err
+	}
+	acta = &models.EleccionActa{
+		ProcesoID:    p.ID,
+		Hash:         hashActa(string(raw)),
+		Contenido:    string(raw),
+		GeneradaAt:   time.Now(),
+		UserGeneroID: userID,
+	}
+	if err := s.repo.CreateActa(acta); err != nil {
+		// Otra petición pudo generarla al mismo tiempo (proceso_id es único).
+		if existente, errF := s.repo.FindActaByProceso(p.ID); errF == nil {
+			return existente, nil
+		}
+		return nil, err
+	}
+	return acta, nil
+}
+
+func decodeActa(acta *models.EleccionActa) (*eleccionActaContenido, error) {
+	var c eleccionActaContenido
+	if err := json.Unmarshal([]byte(acta.Contenido), &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GenerarActaPDF acta oficial de escrutinio de un proceso cerrado, con el hash de integridad en cada página.
+func (s *eleccionService) GenerarActaPDF(userID uint, roles []string, procesoID uint) ([]byte, error) {
+	p, _, err := s.loadProcesoScoped(userID, roles, procesoID)
+	if err != nil {
+		return nil, err
+	}
+	acta, err := s.asegurarActa(&userID, p)
+	if err != nil {
+		return nil, err
+	}
+	contenido, err := decodeActa(acta)
+	if err != nil {
+		return nil, err
+	}
+	return renderActaPDF(contenido, acta)
+}
+
+// VerificarActa consulta pública de un acta por su hash: comprueba que el contenido guardado corresponda al hash
+// y que la urna siga dando el mismo escrutinio.
+func (s *eleccionService) VerificarActa(hash string) (*dto.EleccionActaVerificacionResponse, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if len(hash) != sha256.Size*2 {
+		return nil, ErrEleccionActaNoEncontrada
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return nil, ErrEleccionActaNoEncontrada
+	}
+	acta, err := s.repo.FindActaByHash(hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEleccionActaNoEncontrada
+		}
+		return nil, err
+	}
+	contenido, err := decodeActa(acta)
+	if err != nil {
+		return nil, err
+	}
+	actual, _, err := s.escrutinioActual(acta.ProcesoID)
+	if err != nil {
+		return nil, err
+	}
+	esperado, _ := json.Marshal(contenido.Escrutinio)
+	recontado, _ := json.Marshal(actual)
+	out := &dto.EleccionActaVerificacionResponse{
+		Hash:             acta.Hash,
+		ProcesoID:        acta.ProcesoID,
+		NombreCiclo:      contenido.Proceso.NombreCiclo,
+		Anio:             contenido.Proceso.Anio,
+		Regional:         contenido.Proceso.Regional,
+		GeneradaAt:       acta.GeneradaAt,
+		VotosTotales:     contenido.Escrutinio.VotosTotales,
+		ContenidoIntegro: hashActa(acta.Contenido) == acta.Hash,
+		CoincideConUrna:  string(esperado) == string(recontado),
+	}
+	out.Valida = out.ContenidoIntegro && out.CoincideConUrna
+	if g := contenido.Escrutinio.PlanchaGanadoraID; g != nil {
+		for _, pl := range contenido.Planchas {
+			if pl.ID == *g {
+				out.PlanchaGanadora = pl.Titular.Nombre + " / " + pl.Suplente.Nombre
+			}
+		}
+	}
+	return out, nil
+}
+
+// porcentajeEleccion porcentaje con dos decimales; 0 si no hay base.
+func porcentajeEleccion(parte, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(parte)/float64(total)*10000) / 100
+}
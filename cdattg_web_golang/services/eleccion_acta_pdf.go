@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+var eleccionDesempateMetodoLabel = map[string]string{
+	models.EleccionDesempateSorteo:  "Sorteo",
+	models.EleccionDesempateComite:  "Decisión del comité electoral",
+	models.EleccionDesempateAcuerdo: "Acuerdo entre las planchas empatadas",
+}
+
+// urlVerificacionActa enlace público del frontend para verificar el acta; vacío si no hay FRONTEND_URL.
+func urlVerificacionActa(hash string) string {
+	base := strings.TrimRight(frontendURL(), "/")
+	if base == "" {
+		return ""
+	}
+	return base + "/verificar-acta/" + hash
+}
+
+func fechaActa(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.In(utils.AppLocation()).Format("02/01/2006 15:04")
+}
+
+// renderActaPDF imprime el acta desde su contenido guardado; el mismo contenido da siempre el mismo documento.
+func renderActaPDF(c *eleccionActaContenido, acta *models.EleccionActa) ([]byte, error) {
+	pdf := utils.NewPDF("P")
+	pdf.SetTitle(fmt.Sprintf("Acta de escrutinio - %s", c.Proceso.NombreCiclo), true)
+	pdf.SetCatalogSort(true)
+	pdf.SetCreationDate(acta.GeneradaAt)
+	pdf.SetModificationDate(acta.GeneradaAt)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 22)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-18)
+		pdf.SetFont(utils.PDFFuente, "", 7)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(0, 4, "SHA-256: "+acta.Hash, "", 1, "L", false, 0, "")
+		if url := urlVerificacionActa(acta.Hash); url != "" {
+			pdf.CellFormat(0, 4, "Verificación: "+url, "", 1, "L", false, 0, "")
+		}
+		pdf.CellFormat(0, 4, fmt.Sprintf("Página %d de {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.AddPage()
+
+	pdfActaEncabezado(pdf, c, acta)
+	pdfActaPlanchas(pdf, c)
+	pdfActaTotales(pdf, c)
+	pdfActaDecision(pdf, c)
+	pdfActaFirmas(pdf)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func pdfActaSeccion(pdf *gofpdf.Fpdf, titulo string) {
+	pdf.Ln(3)
+	pdf.SetFont(utils.PDFFuente, "B", 11)
+	pdf.CellFormat(0, 7, titulo, "B", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	pdf.SetFont(utils.PDFFuente, "", 9)
+}
+
+func pdfActaEncabezado(pdf *gofpdf.Fpdf, c *eleccionActaContenido, acta *models.EleccionActa) {
+	pdf.SetFont(utils.PDFFuente, "B", 14)
+	pdf.CellFormat(0, 8, "ACTA DE ESCRUTINIO", "", 1, "C", false, 0, "")
+	pdf.SetFont(utils.PDFFuente, "", 10)
+	cargo := c.Proceso.Cargo
+	if cargo == "" {
+		cargo = "representante de aprendices"
+	}
+	pdf.CellFormat(0, 6, "Elección de "+cargo, "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	pdfActaSeccion(pdf, "Proceso electoral")
+	filas := [][2]string{
+		{"Ciclo", fmt.Sprintf("%s (%d)", c.Proceso.NombreCiclo, c.Proceso.Anio)},
+		{"Regional", c.Proceso.Regional},
+		{"Inscripción de planchas", fechaActa(c.Proceso.FechaInscripcionInicio) + " a " + fechaActa(c.Proceso.FechaInscripcionFin)},
+		{"Votación", fechaActa(c.Proceso.FechaVotacionInicio) + " a " + fechaActa(c.Proceso.FechaVotacionFin)},
+		{"Cierre del escrutinio", fechaActa(&c.CerradaAt)},
+		{"Acta generada", fechaActa(&acta.GeneradaAt)},
+	}
+	for _, f := range filas {
+		pdf.SetFont(utils.PDFFuente, "B", 9)
+		pdf.CellFormat(50, 6, f[0], "", 0, "L", false, 0, "")
+		pdf.SetFont(utils.PDFFuente, "", 9)
+		pdf.CellFormat(0, 6, f[1], "", 1, "L", false, 0, "")
+	}
+}
+
+func pdfActaPlanchas(pdf *gofpdf.Fpdf, c *eleccionActaContenido) {
+	pdfActaSeccion(pdf, "Planchas y votación")
+	const wN, wVotos, wPct = 12.0, 18.0, 18.0
+	wPersona := (180 - wN - wVotos - wPct) / 2
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(wN, 7, "N.°", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(wPersona, 7, "Titular", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(wPersona, 7, "Suplente", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(wVotos, 7, "Votos", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(wPct, 7, "%", "1", 1, "C", true, 0, "")
+
+	votos := make(map[uint]int, len(c.Escrutinio.Votos))
+	for _, v := range c.Escrutinio.Votos {
+		votos[v.PlanchaID] = v.Votos
+	}
+	for i, pl := range c.Planchas {
+		ganadora := c.Escrutinio.PlanchaGanadoraID != nil && *c.Escrutinio.PlanchaGanadoraID == pl.ID
+		if ganadora {
+			pdf.SetFont(utils.PDFFuente, "B", 9)
+		}
+		pdf.CellFormat(wN, 6, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(wPersona, 6, truncateStr(personaActaTexto(pl.Titular), 48), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(wPersona, 6, truncateStr(personaActaTexto(pl.Suplente), 48), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(wVotos, 6, fmt.Sprintf("%d", votos[pl.ID]), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(wPct, 6, fmt.Sprintf("%.2f", porcentajeEleccion(int64(votos[pl.ID]), int64(c.Escrutinio.VotosTotales))), "1", 1, "C", false, 0, "")
+		pdf.SetFont(utils.PDFFuente, "", 9)
+	}
+}
+
+func personaActaTexto(p eleccionActaPersona) string {
+	if p.Documento == "" {
+		return p.Nombre
+	}
+	return p.Nombre + " (" + p.Documento + ")"
+}
+
+func pdfActaTotales(pdf *gofpdf.Fpdf, c *eleccionActaContenido) {
+	pdfActaSeccion(pdf, "Totales")
+	e := c.Escrutinio
+	filas := [][2]string{
+		{"Votos válidos", fmt.Sprintf("%d", e.VotosTotales)},
+		{"Aprendices habilitados", fmt.Sprintf("%d", c.Elegibles)},
+		{"Participación", fmt.Sprintf("%.2f %%", porcentajeEleccion(int64(e.Participaciones), c.Elegibles))},
+		{"Constancias de participación", fmt.Sprintf("%d", e.Participaciones)},
+		{"Papeletas en la urna", fmt.Sprintf("%d", e.Papeletas)},
+		{"Huella de las papeletas", e.PapeletasSHA256},
+	}
+	for _, f := range filas {
+		pdf.SetFont(utils.PDFFuente, "B", 9)
+		pdf.CellFormat(55, 6, f[0], "", 0, "L", false, 0, "")
+		pdf.SetFont(utils.PDFFuente, "", 9)
+		pdf.CellFormat(0, 6, f[1], "", 1, "L", false, 0, "")
+	}
+	if e.Participaciones != e.Papeletas {
+		pdf.SetTextColor(180, 0, 0)
+		pdf.MultiCell(0, 5, "Las constancias de participación y las papeletas no coinciden; revise la auditoría del proceso.", "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+func pdfActaDecision(pdf *gofpdf.Fpdf, c *eleccionActaContenido) {
+	pdfActaSeccion(pdf, "Decisión")
+	var ganadora *eleccionActaPlancha
+	for i := range c.Planchas {
+		if c.Escrutinio.PlanchaGanadoraID != nil && c.Planchas[i].ID == *c.Escrutinio.PlanchaGanadoraID {
+			ganadora = &c.Planchas[i]
+		}
+	}
+	if ganadora == nil {
+		pdf.MultiCell(0, 5, "El proceso se cerró sin plancha ganadora.", "", "L", false)
+	} else {
+		pdf.MultiCell(0, 5, fmt.Sprintf("Se declara elegida la plancha conformada por %s como titular y %s como suplente.",
+			personaActaTexto(ganadora.Titular), personaActaTexto(ganadora.Suplente)), "", "L", false)
+	}
+	d := c.Desempate
+	if d == nil {
+		return
+	}
+	pdf.Ln(2)
+	pdf.SetFont(utils.PDFFuente, "B", 9)
+	pdf.CellFormat(0, 6, "Desempate", "", 1, "L", false, 0, "")
+	pdf.SetFont(utils.PDFFuente, "", 9)
+	if len(d.PlanchasEmpatadas) > 0 {
+		nums := make([]string, 0, len(d.PlanchasEmpatadas))
+		for i, pl := range c.Planchas {
+			if containsUint(d.PlanchasEmpatadas, pl.ID) {
+				nums = append(nums, fmt.Sprintf("%d", i+1))
+			}
+		}
+		pdf.MultiCell(0, 5, "Planchas empatadas: "+strings.Join(nums, ", "), "", "L", false)
+	}
+	if d.Metodo != "" {
+		metodo := eleccionDesempateMetodoLabel[d.Metodo]
+		if metodo == "" {
+			metodo = d.Metodo
+		}
+		pdf.MultiCell(0, 5, "Método: "+metodo, "", "L", false)
+	}
+	if d.RegistradoAt != nil {
+		pdf.MultiCell(0, 5, "Registrado: "+fechaActa(d.RegistradoAt), "", "L", false)
+	}
+	if d.Nota != "" {
+		pdf.MultiCell(0, 5, "Observaciones: "+d.Nota, "", "L", false)
+	}
+}
+
+func pdfActaFirmas(pdf *gofpdf.Fpdf) {
+	pdfActaSeccion(pdf, "Firmas")
+	if pdf.GetY() > 230 {
+		pdf.AddPage()
+	}
+	pdf.Ln(16)
+	firmas := []string{"Responsable del escrutinio", "Delegado(a) de Bienestar al Aprendiz", "Testigo"}
+	const ancho, espacio = 54.0, 9.0
+	y := pdf.GetY()
+	for i, rol := range firmas {
+		x := 15 + float64(i)*(ancho+espacio)
+		pdf.Line(x, y, x+ancho, y)
+		pdf.SetXY(x, y+1)
+		pdf.CellFormat(ancho, 5, rol, "", 2, "C", false, 0, "")
+		pdf.SetX(x)
+		pdf.CellFormat(ancho, 5, "Nombre:", "", 2, "L", false, 0, "")
+		pdf.SetX(x)
+		pdf.CellFormat(ancho, 5, "Documento:", "", 0, "L", false, 0, "")
+	}
+	pdf.SetY(y + 18)
+}
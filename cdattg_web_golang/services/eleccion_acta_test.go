@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"gorm.io/gorm"
+)
+
+// eleccionActaRepoFake urna en memoria de un proceso con dos planchas confirmadas.
+type eleccionActaRepoFake struct {
+	repositories.EleccionRepository
+	papeletas []models.EleccionPapeleta
+	resultado *models.EleccionResultado
+	actas     []models.EleccionActa
+	estado    string // fase del proceso al bloquear su fila
+}
+
+func aprendizActaFake(id uint, nombre, doc string) *models.Aprendiz {
+	a := &models.Aprendiz{Persona: &models.Persona{PrimerNombre: nombre, NumeroDocumento: doc}}
+	a.ID = id
+	return a
+}
+
+func (r *eleccionActaRepoFake) ListPlanchasByProceso(_ uint, _ bool) ([]models.EleccionPlancha, error) {
+	planchas := []models.EleccionPlancha{
+		{TitularAprendiz: aprendizActaFake(1, "Ana", "101"), SuplenteAprendiz: aprendizActaFake(2, "Beto", "102")},
+		{TitularAprendiz: aprendizActaFake(3, "Caro", "103"), SuplenteAprendiz: aprendizActaFake(4, "Dani", "104")},
+	}
+	planchas[0].ID, planchas[1].ID = 10, 20
+	return planchas, nil
+}
+
+func (r *eleccionActaRepoFake) ListPapeletasByProceso(_ uint) ([]models.EleccionPapeleta, error) {
+	return r.papeletas, nil
+}
+
+func (r *eleccionActaRepoFake) CountPapeletasByPlancha(planchaID uint) (int64, error) {
+	var n int64
+	for _, p := range r.papeletas {
+		if p.PlanchaID == planchaID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *eleccionActaRepoFake) CountParticipacionesByProceso(_ uint) (int64, error) {
+	return int64(len(r.papeletas)), nil
+}
+
+func (r *eleccionActaRepoFake) CountPadron(_ repositories.EleccionAmbito) (int64, error) {
+	return 8, nil
+}
+
+func (r *eleccionActaRepoFake) FindResultadoByProceso(_ uint) (*models.EleccionResultado, error) {
+	if r.resultado == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.resultado, nil
+}
+
+func (r *eleccionActaRepoFake) CreateActa(a *models.EleccionActa) error {
+	a.ID = uint(len(r.actas) + 1)
+	r.actas = append(r.actas, *a)
+	return nil
+}
+
+func (r *eleccionActaRepoFake) FindActaByProceso(procesoID uint) (*models.EleccionActa, error) {
+	for i := range r.actas {
+		if r.actas[i].ProcesoID == procesoID {
+			return &r.actas[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *eleccionActaRepoFake) EnProcesoBloqueado(id uint, fn func(repositories.EleccionRepository, *models.EleccionProceso) error) error {
+	p := procesoCerradoFake()
+	p.ID, p.Estado = id, r.estado
+	return fn(r, p)
+}
+
+func (r *eleccionActaRepoFake) FindActaByHash(hash string) (*models.EleccionActa, error) {
+	for i := range r.actas {
+		if r.actas[i].Hash == hash {
+			return &r.actas[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func procesoCerradoFake() *models.EleccionProceso {
+	p := &models.EleccionProceso{NombreCiclo: "Ciclo 2026", Anio: 2026, Estado: models.EleccionEstadoCerrada,
+		Regional: &models.Regional{Nombre: "Guaviare"}}
+	p.ID = 5
+	return p
+}
+
+func TestAsegurarActa_hashDelContenidoYNoSeRegenera(t *testing.T) {
+	ganadora := uint(10)
+	cerrada := time.Date(2026, 10, 16, 18, 5, 0, 0, time.UTC)
+	repo := &eleccionActaRepoFake{
+		papeletas: []models.EleccionPapeleta{{ReciboHash: "a", PlanchaID: 10}, {ReciboHash: "b", PlanchaID: 10}, {ReciboHash: "c", PlanchaID: 20}},
+		resultado: &models.EleccionResultado{ProcesoID: 5, PlanchaGanadoraID: &ganadora, VotosTotales: 3, CerradaAt: &cerrada},
+	}
+	s := &eleccionService{repo: repo}
+	admin, otro := uint(1), uint(2)
+
+	acta, err := s.asegurarActa(&admin, procesoCerradoFake())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acta.Hash != hashActa(acta.Contenido) || len(acta.Hash) != 64 {
+		t.Fatalf("hash %q no corresponde al contenido", acta.Hash)
+	}
+	if c, err := decodeActa(acta); err != nil || !c.CerradaAt.Equal(cerrada) {
+		t.Fatalf("el acta debe tomar la fecha de cierre del resultado: %v", err)
+	}
+	otra, err := s.asegurarActa(&otro, procesoCerradoFake())
+	if err != nil || otra.Hash != acta.Hash || len(repo.actas) != 1 {
+		t.Fatalf("el acta debe generarse una sola vez: %v, %d actas", err, len(repo.actas))
+	}
+
+	abierto := procesoCerradoFake()
+	abierto.Estado = models.EleccionEstadoEmpatePendiente
+	if _, err := s.asegurarActa(&admin, abierto); !errors.Is(err, errEleccionActaProcesoAbierto) {
+		t.Fatalf("proceso sin cerrar: err = %v", err)
+	}
+}
+
+func TestVerificarActa_detectaCambiosEnLaUrna(t *testing.T) {
+	ganadora := uint(10)
+	cerrada := time.Date(2026, 10, 16, 18, 5, 0, 0, time.UTC)
+	repo := &eleccionActaRepoFake{
+		papeletas: []models.EleccionPapeleta{{ReciboHash: "a", PlanchaID: 10}, {ReciboHash: "b", PlanchaID: 20}, {ReciboHash: "c", PlanchaID: 10}},
+		resultado: &models.EleccionResultado{ProcesoID: 5, PlanchaGanadoraID: &ganadora, VotosTotales: 3, CerradaAt: &cerrada},
+	}
+	s := &eleccionService{repo: repo}
+	acta, err := s.asegurarActa(nil, procesoCerradoFake())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.VerificarActa(" " + acta.Hash + " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Valida || res.VotosTotales != 3 || res.PlanchaGanadora != "Ana / Beto" || res.Regional != "Guaviare" {
+		t.Fatalf("verificación inesperada: %+v", res)
+	}
+
+	repo.papeletas[1].PlanchaID = 10
+	res, err = s.VerificarActa(acta.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Valida || res.CoincideConUrna || !res.ContenidoIntegro {
+		t.Fatalf("una papeleta cambiada debe invalidar el acta: %+v", res)
+	}
+
+	for _, h := range []string{"abc", acta.Hash[:63] + "z", "0000000000000000000000000000000000000000000000000000000000000000"} {
+		if _, err := s.VerificarActa(h); !errors.Is(err, ErrEleccionActaNoEncontrada) {
+			t.Errorf("hash %q: err = %v", h, err)
+		}
+	}
+}
+
+func TestRenderActaPDF_conDesempate(t *testing.T) {
+	ganadora := uint(20)
+	metodo := models.EleccionDesempateSorteo
+	nota := "Sorteo con balota ante el comité"
+	registrado := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	repo := &eleccionActaRepoFake{
+		papeletas: []models.EleccionPapeleta{{ReciboHash: "a", PlanchaID: 10}, {ReciboHash: "b", PlanchaID: 20}},
+		resultado: &models.EleccionResultado{ProcesoID: 5, PlanchaGanadoraID: &ganadora, VotosTotales: 2,
+			DesempateMetodo: &metodo, NotaDesempate: &nota, DesempateAt: &registrado, CerradaAt: &registrado},
+	}
+	s := &eleccionService{repo: repo}
+	acta, err := s.asegurarActa(nil, procesoCerradoFake())
+	if err != nil {
+		t.Fatal(err)
+	}
+	contenido, err := decodeActa(acta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := contenido.Desempate; d == nil || len(d.PlanchasEmpatadas) != 2 || d.Metodo != metodo {
+		t.Fatalf("desempate en el acta: %+v", contenido.Desempate)
+	}
+	pdf1, err := renderActaPDF(contenido, acta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf2, _ := renderActaPDF(contenido, acta)
+	if !bytes.HasPrefix(pdf1, []byte("%PDF")) {
+		t.Fatal("no es un PDF")
+	}
+	if !bytes.Equal(pdf1, pdf2) {
+		t.Error("el mismo contenido debe producir el mismo PDF")
+	}
+}
+
+func TestFinalizarConteo_desempateSoloEntreEmpatadas(t *testing.T) {
+	repo := &eleccionActaRepoFake{papeletas: []models.EleccionPapeleta{{ReciboHash: "a", PlanchaID: 10}, {ReciboHash: "b", PlanchaID: 20}},
+		estado: models.EleccionEstadoEmpatePendiente}
+	s := &eleccionService{repo: repo}
+	p := procesoCerradoFake()
+	p.Estado = models.EleccionEstadoEmpatePendiente
+
+	admin := uint(1)
+	_, err := s.finalizarConteo(&admin, p, &dto.EleccionDesempateRequest{PlanchaGanadoraID: 30, Metodo: models.EleccionDesempateSorteo, NotaDesempate: "x"}, "")
+	if !errors.Is(err, errEleccionDesempatePlancha) {
+		t.Fatalf("err = %v, want errEleccionDesempatePlancha", err)
+	}
+
+	// Otra petición ya resolvió el empate mientras esta esperaba el bloqueo.
+	repo.estado = models.EleccionEstadoCerrada
+	_, err = s.finalizarConteo(&admin, p, &dto.EleccionDesempateRequest{PlanchaGanadoraID: 10, Metodo: models.EleccionDesempateSorteo}, "")
+	if !errors.Is(err, errEleccionFaseInvalida) {
+		t.Fatalf("empate ya resuelto: err = %v", err)
+	}
+}
+
+func TestPlanchasEmpatadas(t *testing.T) {
+	conteo := []dto.EleccionResultadoPlanchaConteo{{PlanchaID: 1, Votos: 4}, {PlanchaID: 2, Votos: 4}, {PlanchaID: 3, Votos: 1}}
+	if got := planchasEmpatadas(conteo); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("empatadas = %v", got)
+	}
+	if got := planchasEmpatadas([]dto.EleccionResultadoPlanchaConteo{{PlanchaID: 1}, {PlanchaID: 2}}); got != nil {
+		t.Fatalf("sin votos no hay empate: %v", got)
+	}
+}
@@ -5,11 +5,17 @@ import (
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
 	"gorm.io/gorm"
 )
 
 func (s *eleccionService) GetRepresentantesVigentes(regionalID uint) (*dto.RepresentanteAprendizResponse, error) {
-	rep, err := s.repo.FindRepresentantesVigentesByRegional(regionalID)
+	return s.representantesVigentes(repositories.EleccionAmbito{Tipo: models.EleccionAmbitoRegional, ID: regionalID})
+}
+
+func (s *eleccionService) representantesVigentes(ambito repositories.EleccionAmbito) (*dto.RepresentanteAprendizResponse, error) {
+	rep, err := s.repo.FindRepresentantesVigentes(ambito)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -51,7 +57,7 @@ func buildMiRegionalBase(aprendiz *models.Aprendiz, regionalID uint) *dto.Elecci
 }
 
 func (s *eleccionService) enrichRespuestaProcesoActivo(
-	resp *dto.EleccionMiRegionalResponse,
+	resp *dto.EleccionMiProcesoResponse,
 	proceso *models.EleccionProceso,
 	aprendiz *models.Aprendiz,
 	userID uint,
@@ -61,12 +67,11 @@ func (s *eleccionService) enrichRespuestaProcesoActivo(
 	resp.Proceso = &pr
 	esCandidato, _ := s.repo.ExistsAprendizEnPlancha(proceso.ID, aprendiz.ID)
 	resp.EsCandidato = esCandidato
-	if v, errV := s.repo.FindVotoByProcesoAndUser(proceso.ID, userID); errV == nil {
-		resp.MiVotoPlanchaID = &v.PlanchaID
+	if _, errV := s.repo.FindParticipacion(proceso.ID, userID); errV == nil {
 		resp.YaVoto = true
 	}
 	resp.PuedePostular = s.puedePostularEnProceso(proceso, aprendiz, esCandidato)
-	resp.PuedeVotar = proceso.Estado == models.EleccionEstadoVotacion && !resp.YaVoto
+	resp.PuedeVotar = proceso.Estado == models.EleccionEstadoVotacion && !resp.YaVoto && ventanaVotacion(proceso, utils.Now()) == nil
 	pendientes, _ := s.repo.ListPlanchasByProceso(proceso.ID, false)
 	resp.PlanchasPendientesConfirmar = s.mapPlanchas(filterPendientesConfirmacion(pendientes, aprendiz.ID), personaID, aprendiz.ID)
 }
@@ -87,12 +92,30 @@ func (s *eleccionService) GetMiRegional(userID uint, personaID *uint) (*dto.Elec
 	if rep, err := s.GetRepresentantesVigentes(regionalID); err == nil && rep != nil {
 		resp.RepresentantesVigentes = rep
 	}
-	proceso, err := s.repo.FindProcesoActivoByRegional(regionalID)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	fichaID := aprendiz.FichaCaracterizacionID
+	if rep, err := s.representantesVigentes(repositories.EleccionAmbito{Tipo: models.EleccionAmbitoFicha, ID: fichaID}); err == nil && rep != nil {
+		resp.VocerosVigentes = rep
+	}
+	var sedeID uint
+	if aprendiz.FichaCaracterizacion.SedeID != nil {
+		sedeID = *aprendiz.FichaCaracterizacion.SedeID
+	}
+	procesos, err := s.repo.ListProcesosActivosAprendiz(regionalID, sedeID, fichaID)
+	if err != nil {
 		return nil, err
 	}
-	if proceso != nil {
-		s.enrichRespuestaProcesoActivo(resp, proceso, aprendiz, userID, personaID)
+	resp.OtrosProcesos = []dto.EleccionMiProcesoResponse{}
+	for i := range procesos {
+		p := &procesos[i]
+		if tipoAmbitoProceso(p) == models.EleccionAmbitoRegional {
+			if resp.Proceso == nil {
+				s.enrichRespuestaProcesoActivo(&resp.EleccionMiProcesoResponse, p, aprendiz, userID, personaID)
+			}
+			continue
+		}
+		var otro dto.EleccionMiProcesoResponse
+		s.enrichRespuestaProcesoActivo(&otro, p, aprendiz, userID, personaID)
+		resp.OtrosProcesos = append(resp.OtrosProcesos, otro)
 	}
 	return resp, nil
 }
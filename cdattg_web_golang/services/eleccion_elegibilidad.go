@@ -3,12 +3,40 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
 	"gorm.io/gorm"
 )
 
+// ambitoProceso de dónde salen votantes y candidatos: la ficha, la sede o toda la regional del proceso. Un ciclo
+// de sede o ficha sin su sede o ficha es un error, no un ciclo regional: el padrón sería el de toda la regional.
+func ambitoProceso(p *models.EleccionProceso) (repositories.EleccionAmbito, error) {
+	switch tipoAmbitoProceso(p) {
+	case models.EleccionAmbitoFicha:
+		if p.FichaID == nil {
+			return repositories.EleccionAmbito{}, errEleccionAmbitoIncompleto
+		}
+		return repositories.EleccionAmbito{Tipo: models.EleccionAmbitoFicha, ID: *p.FichaID}, nil
+	case models.EleccionAmbitoSede:
+		if p.SedeID == nil {
+			return repositories.EleccionAmbito{}, errEleccionAmbitoIncompleto
+		}
+		return repositories.EleccionAmbito{Tipo: models.EleccionAmbitoSede, ID: *p.SedeID}, nil
+	}
+	return repositories.EleccionAmbito{Tipo: models.EleccionAmbitoRegional, ID: p.RegionalID}, nil
+}
+
+// tipoAmbitoProceso ámbito declarado del ciclo, para textos y cargos; los ciclos sin ámbito son regionales.
+func tipoAmbitoProceso(p *models.EleccionProceso) string {
+	if p.Ambito == "" {
+		return models.EleccionAmbitoRegional
+	}
+	return p.Ambito
+}
+
 func regionalIDFromAprendiz(a *models.Aprendiz) (uint, error) {
 	if a == nil || a.FichaCaracterizacion == nil || a.FichaCaracterizacion.Sede == nil || a.FichaCaracterizacion.Sede.RegionalID == nil {
 		return 0, errEleccionAprendizRegional
@@ -23,19 +51,51 @@ func regionalNombreFromAprendiz(aprendiz *models.Aprendiz) string {
 	return aprendiz.FichaCaracterizacion.Sede.Regional.Nombre
 }
 
-func (s *eleccionService) aprendizActivoEnRegional(personaID uint, regionalID uint) (*models.Aprendiz, error) {
-	a, err := s.aprendizRepo.FindActivoByPersonaID(personaID)
+// aprendizEnAmbito el aprendiz pertenece a la regional del proceso y, en ciclos de sede o ficha, a esa sede o ficha.
+func aprendizEnAmbito(a *models.Aprendiz, p *models.EleccionProceso) error {
+	rid, err := regionalIDFromAprendiz(a)
+	if err != nil || rid != p.RegionalID {
+		return errEleccionAprendizRegional
+	}
+	ambito, err := ambitoProceso(p)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errEleccionNoElegible
+		return err
+	}
+	switch ambito.Tipo {
+	case models.EleccionAmbitoSede:
+		if a.FichaCaracterizacion.SedeID == nil || *a.FichaCaracterizacion.SedeID != ambito.ID {
+			return errEleccionAprendizAmbito
 		}
+	case models.EleccionAmbitoFicha:
+		if a.FichaCaracterizacionID != ambito.ID {
+			return errEleccionAprendizAmbito
+		}
+	}
+	return nil
+}
+
+// aprendizActivoEnAmbito matrícula activa de la persona en el ámbito del proceso. Si no tiene ninguna ahí, el error
+// dice si no está activa en ninguna ficha o si lo está fuera de la regional o del ámbito.
+func (s *eleccionService) aprendizActivoEnAmbito(personaID uint, p *models.EleccionProceso) (*models.Aprendiz, error) {
+	ambito, err := ambitoProceso(p)
+	if err != nil {
 		return nil, err
 	}
-	rid, err := regionalIDFromAprendiz(a)
-	if err != nil || rid != regionalID {
-		return nil, errEleccionAprendizRegional
+	a, err := s.repo.FindAprendizActivoEnAmbito(personaID, ambito)
+	if err == nil {
+		return a, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
 	}
-	return a, nil
+	otra, err := s.findAprendizElegible(personaID)
+	if err != nil {
+		return nil, err
+	}
+	if err := aprendizEnAmbito(otra, p); err != nil {
+		return nil, err
+	}
+	return nil, errEleccionAprendizAmbito
 }
 
 func (s *eleccionService) findAprendizElegible(personaID uint) (*models.Aprendiz, error) {
@@ -64,13 +124,17 @@ func (s *eleccionService) validarElegibilidadCandidato(a *models.Aprendiz, p *mo
 	if time.Since(a.CreatedAt) < time.Duration(minDias)*24*time.Hour {
 		return fmt.Errorf("%w: requiere al menos %d días matriculado", errEleccionNoElegible, minDias)
 	}
-	exIDs, err := s.repo.FindAprendizIDsExRepresentantes(p.RegionalID, p.Anio)
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return err
+	}
+	exIDs, err := s.repo.FindAprendizIDsExRepresentantes(ambito, p.Anio)
 	if err != nil {
 		return err
 	}
 	for _, id := range exIDs {
 		if id == a.ID {
-			return fmt.Errorf("%w: no puede reelegirse como representante", errEleccionNoElegible)
+			return fmt.Errorf("%w: no puede reelegirse como %s", errEleccionNoElegible, strings.ToLower(cargoTitular(p)))
 		}
 	}
 	return nil
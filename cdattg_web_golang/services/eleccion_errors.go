@@ -8,8 +8,20 @@ var (
 	errEleccionNoElegible            = errors.New("no cumple requisitos para participar en la elección")
 	errEleccionFaseInvalida          = errors.New("operación no permitida en la fase actual del proceso")
 	errEleccionAprendizRegional      = errors.New("el aprendiz no pertenece a la regional del proceso")
+	errEleccionAprendizAmbito        = errors.New("el aprendiz no pertenece a la sede o ficha del proceso")
+	errEleccionAmbitoInvalido        = errors.New("la sede o ficha del ciclo no pertenece a la regional indicada")
+	errEleccionAmbitoIncompleto      = errors.New("el ciclo de sede o ficha no tiene la sede o ficha asignada")
 	errEleccionUsuarioSinPersona     = errors.New("usuario sin persona vinculada")
 	errEleccionVotoYaRegistrado      = errors.New("ya registró su voto en este proceso")
 	errEleccionYaEnPlancha           = errors.New("ya está inscrito en una plancha de este proceso")
-	errEleccionCicloDuplicado        = errors.New("ya existe un ciclo electoral para esta regional en el mismo año")
+	errEleccionCicloDuplicado        = errors.New("ya existe un ciclo electoral para esta regional, sede o ficha en el mismo año")
+	errEleccionReciboInvalido        = errors.New("código de recibo inválido")
+	errEleccionActaProcesoAbierto    = errors.New("el acta de escrutinio solo se genera para procesos cerrados")
+	errEleccionDesempatePlancha      = errors.New("la plancha elegida no está entre las empatadas")
+	errEleccionRecordatorioReciente  = errors.New("ya se envió un recordatorio de voto hace menos de 6 horas")
+	errEleccionVotacionNoIniciada    = errors.New("la votación aún no ha iniciado")
+	errEleccionVotacionCerrada       = errors.New("la votación ya cerró")
 )
+
+// ErrEleccionActaNoEncontrada ningún acta tiene el hash consultado.
+var ErrEleccionActaNoEncontrada = errors.New("acta de escrutinio no encontrada")
@@ -7,6 +7,7 @@ import (
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
 )
 
 func mapProcesoToDTO(p *models.EleccionProceso) dto.EleccionProcesoResponse {
@@ -15,17 +16,30 @@ func mapProcesoToDTO(p *models.EleccionProceso) dto.EleccionProcesoResponse {
 		RegionalID:             p.RegionalID,
 		Anio:                   p.Anio,
 		NombreCiclo:            p.NombreCiclo,
+		Ambito:                 tipoAmbitoProceso(p),
+		SedeID:                 p.SedeID,
+		FichaID:                p.FichaID,
+		CargoTitular:           cargoTitular(p),
+		CargoSuplente:          cargoSuplente(p),
 		Estado:                 p.Estado,
 		FechaInscripcionInicio: p.FechaInscripcionInicio,
 		FechaInscripcionFin:    p.FechaInscripcionFin,
 		FechaVotacionInicio:    p.FechaVotacionInicio,
 		FechaVotacionFin:       p.FechaVotacionFin,
 		MinDiasMatricula:       p.MinDiasMatricula,
+		AutoTransiciones:       p.AutoTransiciones,
+		CierreVotacionAt:       cierreVotacion(p),
 		CreatedAt:              p.CreatedAt,
 	}
 	if p.Regional != nil {
 		resp.RegionalNombre = p.Regional.Nombre
 	}
+	if p.Sede != nil {
+		resp.SedeNombre = p.Sede.Nombre
+	}
+	if p.Ficha != nil {
+		resp.FichaNumero = p.Ficha.Ficha
+	}
 	return resp
 }
 
@@ -69,7 +83,7 @@ func (s *eleccionService) mapPlanchas(list []models.EleccionPlancha, personaID *
 	out := make([]dto.EleccionPlanchaResponse, len(list))
 	for i := range list {
 		pl := &list[i]
-		votos, _ := s.repo.CountVotosByPlancha(pl.ID)
+		votos, _ := s.repo.CountPapeletasByPlancha(pl.ID)
 		out[i] = dto.EleccionPlanchaResponse{
 			ID:                 pl.ID,
 			ProcesoID:          pl.ProcesoID,
@@ -93,6 +107,9 @@ func (s *eleccionService) mapPlanchas(list []models.EleccionPlancha, personaID *
 func mapRepresentanteToDTO(r *models.RepresentanteAprendiz) dto.RepresentanteAprendizResponse {
 	resp := dto.RepresentanteAprendizResponse{
 		RegionalID:     r.RegionalID,
+		Ambito:         r.Ambito,
+		SedeID:         r.SedeID,
+		FichaID:        r.FichaID,
 		ProcesoID:      r.ProcesoID,
 		Titular:        aprendizResumen(r.TitularAprendiz),
 		Suplente:       aprendizResumen(r.SuplenteAprendiz),
@@ -105,6 +122,14 @@ func mapRepresentanteToDTO(r *models.RepresentanteAprendiz) dto.RepresentanteApr
 	if r.Proceso != nil {
 		resp.NombreCiclo = r.Proceso.NombreCiclo
 		resp.Anio = r.Proceso.Anio
+		resp.CargoTitular = cargoTitular(r.Proceso)
+		resp.CargoSuplente = cargoSuplente(r.Proceso)
+		if r.Proceso.Sede != nil {
+			resp.SedeNombre = r.Proceso.Sede.Nombre
+		}
+		if r.Proceso.Ficha != nil {
+			resp.FichaNumero = r.Proceso.Ficha.Ficha
+		}
 	}
 	return resp
 }
@@ -124,14 +149,19 @@ func decodeDetalleJSON(raw string) []dto.EleccionResultadoPlanchaConteo {
 }
 
 func (s *eleccionService) buildConteo(procesoID uint) ([]dto.EleccionResultadoPlanchaConteo, int, error) {
-	planchas, err := s.repo.ListPlanchasByProceso(procesoID, true)
+	return conteoProceso(s.repo, procesoID)
+}
+
+// conteoProceso votos por plancha confirmada; el cierre lo llama con el repositorio de la transacción bloqueada.
+func conteoProceso(repo repositories.EleccionRepository, procesoID uint) ([]dto.EleccionResultadoPlanchaConteo, int, error) {
+	planchas, err := repo.ListPlanchasByProceso(procesoID, true)
 	if err != nil {
 		return nil, 0, err
 	}
 	total := 0
 	out := make([]dto.EleccionResultadoPlanchaConteo, len(planchas))
 	for i := range planchas {
-		n, err := s.repo.CountVotosByPlancha(planchas[i].ID)
+		n, err := repo.CountPapeletasByPlancha(planchas[i].ID)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -145,6 +175,25 @@ func (s *eleccionService) buildConteo(procesoID uint) ([]dto.EleccionResultadoPl
 	return out, total, nil
 }
 
+// planchasEmpatadas planchas con la mayor votación cuando son más de una (con al menos un voto).
+func planchasEmpatadas(conteo []dto.EleccionResultadoPlanchaConteo) []uint {
+	maxVotos := 0
+	var lideres []uint
+	for _, c := range conteo {
+		switch {
+		case c.Votos > maxVotos:
+			maxVotos = c.Votos
+			lideres = []uint{c.PlanchaID}
+		case c.Votos == maxVotos && maxVotos > 0:
+			lideres = append(lideres, c.PlanchaID)
+		}
+	}
+	if len(lideres) < 2 {
+		return nil
+	}
+	return lideres
+}
+
 func detectarEmpate(conteo []dto.EleccionResultadoPlanchaConteo) (bool, *uint) {
 	if len(conteo) == 0 {
 		return false, nil
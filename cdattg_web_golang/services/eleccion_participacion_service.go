@@ -0,0 +1,295 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+const (
+	EleccionCanalCorreo       = "correo"
+	EleccionCanalNotificacion = "notificacion"
+
+	// eleccionRecordatorioEspera tiempo mínimo entre dos recordatorios de votar del mismo proceso.
+	eleccionRecordatorioEspera = 6 * time.Hour
+)
+
+// GetParticipacion participación en vivo desde que abre la votación: elegibles y votantes por sede, programa y
+// ficha, y votos por hora. Se calcula con las constancias de participación, nunca con las papeletas.
+func (s *eleccionService) GetParticipacion(userID uint, roles []string, procesoID uint) (*dto.EleccionParticipacionResponse, error) {
+	p, _, err := s.loadProcesoScoped(userID, roles, procesoID)
+	if err != nil {
+		return nil, err
+	}
+	if !votacionAbierta(p) {
+		return nil, errEleccionFaseInvalida
+	}
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return nil, err
+	}
+	padron, err := s.repo.ListPadronParticipacion(p.ID, ambito)
+	if err != nil {
+		return nil, err
+	}
+	votadoAt, err := s.repo.ListVotadoAtByProceso(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := resumenParticipacion(padron, votadoAt)
+	res.ProcesoID = p.ID
+	res.EstadoProceso = p.Estado
+	res.UltimoRecordatorioAt = p.UltimoRecordatorioAt
+	res.ActualizadoAt = utils.Now()
+	return res, nil
+}
+
+// votacionAbierta la votación ya se abrió (sigue abierta o el proceso pasó a escrutinio).
+func votacionAbierta(p *models.EleccionProceso) bool {
+	switch p.Estado {
+	case models.EleccionEstadoVotacion, models.EleccionEstadoEmpatePendiente, models.EleccionEstadoCerrada:
+		return true
+	}
+	return false
+}
+
+func resumenParticipacion(padron []repositories.EleccionPadronRow, votadoAt []time.Time) *dto.EleccionParticipacionResponse {
+	sedes, programas, fichas := newGruposParticipacion(), newGruposParticipacion(), newGruposParticipacion()
+	votaron := 0
+	for _, a := range padron {
+		sedes.sumar(a.SedeID, a.SedeNombre, a.Voto)
+		programas.sumar(a.ProgramaID, a.ProgramaNombre, a.Voto)
+		fichas.sumar(a.FichaID, a.FichaNumero, a.Voto)
+		if a.Voto {
+			votaron++
+		}
+	}
+	fueraDePadron := len(votadoAt) - votaron
+	if fueraDePadron < 0 {
+		fueraDePadron = 0
+	}
+	return &dto.EleccionParticipacionResponse{
+		Elegibles:             len(padron),
+		Votaron:               votaron,
+		Pendientes:            len(padron) - votaron,
+		ParticipacionPct:      porcentajeEleccion(int64(votaron), int64(len(padron))),
+		VotantesFueraDePadron: fueraDePadron,
+		PorSede:               sedes.lista(),
+		PorPrograma:           programas.lista(),
+		PorFicha:              fichas.lista(),
+		PorHora:               participacionPorHora(votadoAt),
+	}
+}
+
+type gruposParticipacion map[uint]*dto.EleccionParticipacionGrupo
+
+func newGruposParticipacion() gruposParticipacion {
+	return make(gruposParticipacion)
+}
+
+func (g gruposParticipacion) sumar(id uint, nombre string, voto bool) {
+	grupo, ok := g[id]
+	if !ok {
+		grupo = &dto.EleccionParticipacionGrupo{ID: id, Nombre: nombre}
+		g[id] = grupo
+	}
+	grupo.Elegibles++
+	if voto {
+		grupo.Votaron++
+	}
+}
+
+// lista ordenada por nombre, con el porcentaje de cada grupo.
+func (g gruposParticipacion) lista() []dto.EleccionParticipacionGrupo {
+	out := make([]dto.EleccionParticipacionGrupo, 0, len(g))
+	for _, grupo := range g {
+		grupo.ParticipacionPct = porcentajeEleccion(int64(grupo.Votaron), int64(grupo.Elegibles))
+		out = append(out, *grupo)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Nombre != out[j].Nombre {
+			return out[i].Nombre < out[j].Nombre
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// participacionPorHora agrupa los votos por hora local, sin huecos entre la primera y la última hora con votos.
+func participacionPorHora(votadoAt []time.Time) []dto.EleccionParticipacionHora {
+	if len(votadoAt) == 0 {
+		return []dto.EleccionParticipacionHora{}
+	}
+	loc := utils.AppLocation()
+	porHora := make(map[time.Time]int)
+	var primera, ultima time.Time
+	for i, t := range votadoAt {
+		t = t.In(loc)
+		h := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		porHora[h]++
+		if i == 0 || h.Before(primera) {
+			primera = h
+		}
+		if i == 0 || h.After(ultima) {
+			ultima = h
+		}
+	}
+	out := make([]dto.EleccionParticipacionHora, 0, int(ultima.Sub(primera)/time.Hour)+1)
+	acumulado := 0
+	for h := primera; !h.After(ultima); h = h.Add(time.Hour) {
+		acumulado += porHora[h]
+		out = append(out, dto.EleccionParticipacionHora{Hora: h, Votos: porHora[h], Acumulado: acumulado})
+	}
+	return out
+}
+
+// EnviarRecordatorioVoto recuerda votar a los aprendices habilitados que aún no lo hacen, por correo y/o en la
+// aplicación. Solo con la votación abierta y como máximo una vez cada 6 horas por proceso: la marca se reserva en
+// la base antes de enviar, así que dos peticiones simultáneas no envían dos recordatorios.
+func (s *eleccionService) EnviarRecordatorioVoto(userID uint, roles []string, procesoID uint, req dto.EleccionRecordatorioRequest) (*dto.EleccionRecordatorioResponse, error) {
+	p, _, err := s.loadProcesoScoped(userID, roles, procesoID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Estado != models.EleccionEstadoVotacion {
+		return nil, errEleccionFaseInvalida
+	}
+	ahora := utils.Now()
+	if p.UltimoRecordatorioAt != nil {
+		if proximo := p.UltimoRecordatorioAt.Add(eleccionRecordatorioEspera); ahora.Before(proximo) {
+			return nil, fmt.Errorf("%w: el próximo podrá enviarse desde las %s", errEleccionRecordatorioReciente,
+				proximo.In(utils.AppLocation()).Format("15:04"))
+		}
+	}
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return nil, err
+	}
+	filas, err := s.repo.ListPendientesVoto(p.ID, ambito)
+	if err != nil {
+		return nil, err
+	}
+	pendientes := pendientesVotoUnicos(filas)
+	res := &dto.EleccionRecordatorioResponse{Pendientes: len(pendientes), CorreoHabilitado: utils.SMTPHabilitado(), EnviadoAt: ahora}
+	if len(pendientes) == 0 {
+		return res, nil
+	}
+	ok, err := s.repo.ReservarRecordatorio(p.ID, userID, ahora, ahora.Add(-eleccionRecordatorioEspera))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errEleccionRecordatorioReciente
+	}
+	canales := make(map[string]bool, len(req.Canales))
+	for _, c := range req.Canales {
+		canales[c] = true
+	}
+	if canales[EleccionCanalNotificacion] {
+		res.Notificados = s.notificarRecordatorioVoto(p, pendientes)
+	}
+	if canales[EleccionCanalCorreo] {
+		res.Correos, res.SinCorreo = s.enviarCorreosRecordatorioVoto(p, pendientes, res.CorreoHabilitado)
+	}
+	if res.Notificados+res.Correos == 0 {
+		// No llegó ningún aviso: no cuenta para la espera de 6 horas.
+		if err := s.repo.LiberarRecordatorio(p.ID, ahora, p.UltimoRecordatorioAt); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// pendientesVotoUnicos una fila por aprendiz (si la persona tiene varios usuarios activos, el primero con correo).
+func pendientesVotoUnicos(filas []repositories.EleccionPendienteVotoRow) []repositories.EleccionPendienteVotoRow {
+	out := make([]repositories.EleccionPendienteVotoRow, 0, len(filas))
+	idx := make(map[uint]int, len(filas))
+	for _, f := range filas {
+		if i, ok := idx[f.AprendizID]; ok {
+			if out[i].Email == "" && f.Email != "" {
+				out[i] = f
+			}
+			continue
+		}
+		idx[f.AprendizID] = len(out)
+		out = append(out, f)
+	}
+	return out
+}
+
+func (s *eleccionService) notificarRecordatorioVoto(p *models.EleccionProceso, pendientes []repositories.EleccionPendienteVotoRow) int {
+	if s.notificaciones == nil {
+		return 0
+	}
+	ids := make([]uint, 0, len(pendientes))
+	for _, a := range pendientes {
+		if a.TieneUsuario {
+			ids = append(ids, a.AprendizID)
+		}
+	}
+	s.notificaciones.NotificarAprendices(ids, NotificacionNueva{
+		Tipo:    models.NotificacionTipoEleccionVotacion,
+		Titulo:  "Aún no ha votado",
+		Mensaje: fmt.Sprintf("La votación de %s está abierta%s. Elija a su %s: el voto es secreto.", p.NombreCiclo, cierreVotacionTexto(p), cargoElegido(p)),
+		Enlace:  rutaNotificacionEleccion,
+	})
+	return len(ids)
+}
+
+// enviarCorreosRecordatorioVoto devuelve los correos encolados y los pendientes sin correo entregable.
+func (s *eleccionService) enviarCorreosRecordatorioVoto(p *models.EleccionProceso, pendientes []repositories.EleccionPendienteVotoRow, habilitado bool) (encolados, sinCorreo int) {
+	var boton *botonCorreo
+	if base := frontendURL(); base != "" {
+		boton = &botonCorreo{Texto: "Ir a votar", Enlace: strings.TrimRight(base, "/") + rutaNotificacionEleccion}
+	}
+	for _, a := range pendientes {
+		if !correoEntregable(a.Email) {
+			sinCorreo++
+			continue
+		}
+		if !habilitado || s.correos == nil {
+			continue
+		}
+		html, texto, err := renderAviso(&avisoCorreo{
+			correoBase: correoBase{Titulo: "Recuerde votar", Periodo: p.NombreCiclo, Nombre: a.Nombre},
+			Parrafos: []string{
+				fmt.Sprintf("La votación para elegir %s de %s está abierta%s y aún no registramos su voto.", cargoElegido(p), p.NombreCiclo, cierreVotacionTexto(p)),
+				"El voto es secreto: el sistema solo guarda que usted participó, no por quién votó.",
+			},
+			Boton: boton,
+			Nota:  "Si ya votó después de recibir este mensaje, no tiene que hacer nada más.",
+		})
+		if err != nil {
+			log.Printf("[eleccion] armando recordatorio de voto del proceso %d: %v", p.ID, err)
+			return encolados, sinCorreo
+		}
+		err = s.correos.Encolar(CorreoNuevo{
+			Para:      []string{a.Email},
+			Asunto:    fmt.Sprintf("CDATTG Web - Recuerde votar en %s", p.NombreCiclo),
+			Plantilla: PlantillaCorreoRecordatorioVoto,
+			HTML:      html,
+			Texto:     texto,
+		})
+		if err != nil {
+			log.Printf("[eleccion] encolando recordatorio de voto del aprendiz %d: %v", a.AprendizID, err)
+			continue
+		}
+		encolados++
+	}
+	return encolados, sinCorreo
+}
+
+// cierreVotacionTexto " hasta el 02/01/2006 15:04" si el proceso tiene fecha de cierre de votación.
+func cierreVotacionTexto(p *models.EleccionProceso) string {
+	if p.FechaVotacionFin == nil {
+		return ""
+	}
+	return " hasta el " + p.FechaVotacionFin.In(utils.AppLocation()).Format("02/01/2006 15:04")
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+func TestResumenParticipacion(t *testing.T) {
+	padron := []repositories.EleccionPadronRow{
+		{AprendizID: 1, SedeID: 1, SedeNombre: "CENTRO", ProgramaID: 7, ProgramaNombre: "ADSO", FichaID: 70, FichaNumero: "2900001", Voto: true},
+		{AprendizID: 2, SedeID: 1, SedeNombre: "CENTRO", ProgramaID: 7, ProgramaNombre: "ADSO", FichaID: 70, FichaNumero: "2900001"},
+		{AprendizID: 3, SedeID: 2, SedeNombre: "AGROPECUARIA", ProgramaID: 7, ProgramaNombre: "ADSO", FichaID: 71, FichaNumero: "2900002", Voto: true},
+		{AprendizID: 4, SedeID: 2, SedeNombre: "AGROPECUARIA", ProgramaID: 8, ProgramaNombre: "COCINA", FichaID: 80, FichaNumero: "2900003"},
+	}
+	base := time.Date(2026, 10, 16, 8, 10, 0, 0, utils.AppLocation())
+	// tres constancias: una de un aprendiz que ya no está activo en la regional.
+	votadoAt := []time.Time{base, base.Add(5 * time.Minute), base.Add(2*time.Hour + 30*time.Minute)}
+
+	res := resumenParticipacion(padron, votadoAt)
+	if res.Elegibles != 4 || res.Votaron != 2 || res.Pendientes != 2 || res.ParticipacionPct != 50 || res.VotantesFueraDePadron != 1 {
+		t.Fatalf("totales inesperados: %+v", res)
+	}
+	if len(res.PorSede) != 2 || res.PorSede[0].Nombre != "AGROPECUARIA" || res.PorSede[0].Votaron != 1 || res.PorSede[0].Elegibles != 2 {
+		t.Fatalf("por sede: %+v", res.PorSede)
+	}
+	if len(res.PorPrograma) != 2 || res.PorPrograma[0].Nombre != "ADSO" || res.PorPrograma[0].ParticipacionPct != 66.67 {
+		t.Fatalf("por programa: %+v", res.PorPrograma)
+	}
+	if len(res.PorFicha) != 3 {
+		t.Fatalf("por ficha: %+v", res.PorFicha)
+	}
+	// 08:00 (2), 09:00 (0, sin hueco), 10:00 (1).
+	h := res.PorHora
+	if len(h) != 3 || h[0].Votos != 2 || h[1].Votos != 0 || h[2].Votos != 1 || h[2].Acumulado != 3 || h[0].Hora.Minute() != 0 {
+		t.Fatalf("por hora: %+v", h)
+	}
+	if vacio := participacionPorHora(nil); vacio == nil || len(vacio) != 0 {
+		t.Fatalf("sin votos debe ser una lista vacía: %v", vacio)
+	}
+}
+
+type eleccionScopeFake struct{}
+
+func (eleccionScopeFake) Resolve(uint, []string) (*EleccionScope, error) {
+	return &EleccionScope{Unrestricted: true}, nil
+}
+
+func (eleccionScopeFake) CanAccessRegional(*EleccionScope, uint) bool { return true }
+
+type eleccionRecordatorioRepoFake struct {
+	repositories.EleccionRepository
+	proceso    models.EleccionProceso
+	pendientes []repositories.EleccionPendienteVotoRow
+	reservado  bool // otra petición marcó el recordatorio después de leer el proceso
+}
+
+func (r *eleccionRecordatorioRepoFake) FindProcesoByID(uint) (*models.EleccionProceso, error) {
+	p := r.proceso
+	return &p, nil
+}
+
+func (r *eleccionRecordatorioRepoFake) ReservarRecordatorio(_, userID uint, at, desde time.Time) (bool, error) {
+	ultimo := r.proceso.UltimoRecordatorioAt
+	if r.reservado || r.proceso.Estado != models.EleccionEstadoVotacion || (ultimo != nil && !ultimo.Before(desde)) {
+		return false, nil
+	}
+	r.proceso.UltimoRecordatorioAt = &at
+	r.proceso.UserEditID = &userID
+	return true, nil
+}
+
+func (r *eleccionRecordatorioRepoFake) LiberarRecordatorio(_ uint, at time.Time, anterior *time.Time) error {
+	if ultimo := r.proceso.UltimoRecordatorioAt; ultimo != nil && ultimo.Equal(at) {
+		r.proceso.UltimoRecordatorioAt = anterior
+	}
+	return nil
+}
+
+func (r *eleccionRecordatorioRepoFake) ListPendientesVoto(uint, repositories.EleccionAmbito) ([]repositories.EleccionPendienteVotoRow, error) {
+	return r.pendientes, nil
+}
+
+type notificacionesRecordatorioFake struct {
+	NotificacionUsuarioService
+	aprendices []uint
+	tipo       string
+}
+
+func (n *notificacionesRecordatorioFake) NotificarAprendices(ids []uint, nueva NotificacionNueva) {
+	n.aprendices = append(n.aprendices, ids...)
+	n.tipo = nueva.Tipo
+}
+
+type correosRecordatorioFake struct {
+	CorreoService
+	para []string
+}
+
+func (c *correosRecordatorioFake) Encolar(nuevo CorreoNuevo) error {
+	c.para = append(c.para, nuevo.Para...)
+	return nil
+}
+
+func TestEnviarRecordatorioVoto(t *testing.T) {
+	prev := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prev })
+	config.AppConfig = &config.Config{SMTP: config.SMTPConfig{Enabled: true, Host: "smtp.test"}}
+
+	repo := &eleccionRecordatorioRepoFake{
+		proceso: models.EleccionProceso{NombreCiclo: "Ciclo 2026", Estado: models.EleccionEstadoVotacion, RegionalID: 3},
+		pendientes: []repositories.EleccionPendienteVotoRow{
+			{AprendizID: 1, Nombre: "Ana", Email: "ana@misena.edu.co", TieneUsuario: true},
+			{AprendizID: 1, Nombre: "Ana", Email: "", TieneUsuario: true},
+			{AprendizID: 2, Nombre: "Beto", Email: "", TieneUsuario: true},
+			{AprendizID: 3, Nombre: "Caro"},
+		},
+	}
+	notif := &notificacionesRecordatorioFake{}
+	correos := &correosRecordatorioFake{}
+	s := &eleccionService{repo: repo, scopeSvc: eleccionScopeFake{}, notificaciones: notif, correos: correos}
+	req := dto.EleccionRecordatorioRequest{Canales: []string{EleccionCanalCorreo, EleccionCanalNotificacion}}
+
+	res, err := s.EnviarRecordatorioVoto(9, nil, 1, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pendientes != 3 || res.Notificados != 2 || res.Correos != 1 || res.SinCorreo != 2 || !res.CorreoHabilitado {
+		t.Fatalf("respuesta inesperada: %+v", res)
+	}
+	if len(notif.aprendices) != 2 || notif.tipo != models.NotificacionTipoEleccionVotacion {
+		t.Fatalf("notificados = %v (%s)", notif.aprendices, notif.tipo)
+	}
+	if len(correos.para) != 1 || correos.para[0] != "ana@misena.edu.co" {
+		t.Fatalf("correos = %v", correos.para)
+	}
+	if repo.proceso.UltimoRecordatorioAt == nil {
+		t.Fatal("no se registró el recordatorio")
+	}
+
+	if _, err := s.EnviarRecordatorioVoto(9, nil, 1, req); !errors.Is(err, errEleccionRecordatorioReciente) {
+		t.Fatalf("segundo recordatorio: err = %v", err)
+	}
+
+	repo.proceso.UltimoRecordatorioAt = nil
+	repo.reservado = true
+	if _, err := s.EnviarRecordatorioVoto(9, nil, 1, req); !errors.Is(err, errEleccionRecordatorioReciente) {
+		t.Fatalf("recordatorio simultáneo: err = %v", err)
+	}
+	repo.reservado = false
+
+	// Sin avisos entregados la marca se libera.
+	repo.pendientes = repo.pendientes[3:]
+	notif.aprendices = nil
+	res, err = s.EnviarRecordatorioVoto(9, nil, 1, dto.EleccionRecordatorioRequest{Canales: []string{EleccionCanalNotificacion}})
+	if err != nil || res.Notificados != 0 || repo.proceso.UltimoRecordatorioAt != nil {
+		t.Fatalf("sin avisos: res = %+v, err = %v, último = %v", res, err, repo.proceso.UltimoRecordatorioAt)
+	}
+
+	repo.proceso.Estado = models.EleccionEstadoCerrada
+	if _, err := s.EnviarRecordatorioVoto(9, nil, 1, req); !errors.Is(err, errEleccionFaseInvalida) {
+		t.Fatalf("proceso cerrado: err = %v", err)
+	}
+}
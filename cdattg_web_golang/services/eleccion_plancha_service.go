@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -23,9 +24,8 @@ func (s *eleccionService) ListPlanchas(userID uint, roles []string, procesoID ui
 
 func (s *eleccionService) validarCandidatosPlancha(p *models.EleccionProceso, titular, suplente *models.Aprendiz) error {
 	for _, a := range []*models.Aprendiz{titular, suplente} {
-		rid, errR := regionalIDFromAprendiz(a)
-		if errR != nil || rid != p.RegionalID {
-			return errEleccionAprendizRegional
+		if err := aprendizEnAmbito(a, p); err != nil {
+			return err
 		}
 		if err := s.validarElegibilidadCandidato(a, p); err != nil {
 			return err
@@ -96,6 +96,26 @@ func buildPlanchaPropuesta(
 	return plancha
 }
 
+// notificarConfirmacionPlancha pide a los candidatos que aún no confirmaron la plancha que lo hagan.
+func (s *eleccionService) notificarConfirmacionPlancha(p *models.EleccionProceso, plancha *models.EleccionPlancha) {
+	if s.notificaciones == nil || plancha.Estado != models.PlanchaEstadoPendiente {
+		return
+	}
+	pendientes := make([]uint, 0, 2)
+	if plancha.TitularConfirmadoAt == nil {
+		pendientes = append(pendientes, plancha.TitularAprendizID)
+	}
+	if plancha.SuplenteConfirmadoAt == nil {
+		pendientes = append(pendientes, plancha.SuplenteAprendizID)
+	}
+	s.notificaciones.NotificarAprendices(pendientes, NotificacionNueva{
+		Tipo:    models.NotificacionTipoEleccionPlancha,
+		Titulo:  "Confirme su participación en una plancha",
+		Mensaje: fmt.Sprintf("Lo propusieron como candidato en una plancha de %s. La plancha solo se inscribe cuando titular y suplente confirman.", p.NombreCiclo),
+		Enlace:  rutaNotificacionEleccion,
+	})
+}
+
 func filterPendientesConfirmacion(list []models.EleccionPlancha, aprendizID uint) []models.EleccionPlancha {
 	out := make([]models.EleccionPlancha, 0)
 	for i := range list {
@@ -125,7 +145,7 @@ func (s *eleccionService) ProponerPlancha(userID uint, personaID *uint, procesoI
 	if p.Estado != models.EleccionEstadoInscripcion {
 		return nil, errEleccionFaseInvalida
 	}
-	proponente, err := s.aprendizActivoEnRegional(*personaID, p.RegionalID)
+	proponente, err := s.aprendizActivoEnAmbito(*personaID, p)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +174,7 @@ func (s *eleccionService) ProponerPlancha(userID uint, personaID *uint, procesoI
 	if err := s.repo.CreatePlancha(plancha); err != nil {
 		return nil, err
 	}
+	s.notificarConfirmacionPlancha(p, plancha)
 	plancha, _ = s.repo.FindPlanchaByID(plancha.ID)
 	resp := s.mapPlanchas([]models.EleccionPlancha{*plancha}, personaID, proponente.ID)[0]
 	return &resp, nil
@@ -173,7 +194,7 @@ func (s *eleccionService) ConfirmarPlancha(userID uint, personaID *uint, plancha
 	if plancha.Estado != models.PlanchaEstadoPendiente {
 		return nil, errors.New("la plancha no está pendiente de confirmación")
 	}
-	aprendiz, err := s.aprendizActivoEnRegional(*personaID, plancha.Proceso.RegionalID)
+	aprendiz, err := s.aprendizActivoEnAmbito(*personaID, plancha.Proceso)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +248,7 @@ func (s *eleccionService) RetirarPlancha(userID uint, personaID *uint, planchaID
 	if err != nil {
 		return errEleccionPlanchaNoEncontrada
 	}
-	aprendiz, err := s.aprendizActivoEnRegional(*personaID, plancha.Proceso.RegionalID)
+	aprendiz, err := s.aprendizActivoEnAmbito(*personaID, plancha.Proceso)
 	if err != nil {
 		return err
 	}
@@ -247,17 +268,13 @@ func (s *eleccionService) ListPlanchasConfirmadasAprendiz(personaID *uint, proce
 	if err != nil {
 		return nil, errEleccionProcesoNoEncontrado
 	}
-	if _, err := s.aprendizActivoEnRegional(*personaID, p.RegionalID); err != nil {
+	aprendiz, err := s.aprendizActivoEnAmbito(*personaID, p)
+	if err != nil {
 		return nil, err
 	}
 	list, err := s.repo.ListPlanchasByProceso(p.ID, true)
 	if err != nil {
 		return nil, err
 	}
-	aprendiz, _ := s.aprendizRepo.FindActivoByPersonaID(*personaID)
-	miID := uint(0)
-	if aprendiz != nil {
-		miID = aprendiz.ID
-	}
-	return s.mapPlanchas(list, personaID, miID), nil
+	return s.mapPlanchas(list, personaID, aprendiz.ID), nil
 }
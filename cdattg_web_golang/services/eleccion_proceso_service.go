@@ -6,24 +6,124 @@ import (
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
+	"gorm.io/gorm"
 )
 
+// eleccionCargosPorAmbito nombres de titular y suplente cuando el ciclo no los configura.
+var eleccionCargosPorAmbito = map[string][2]string{
+	models.EleccionAmbitoRegional: {"Representante", "Suplente"},
+	models.EleccionAmbitoSede:     {"Representante de sede", "Suplente"},
+	models.EleccionAmbitoFicha:    {"Vocero", "Vocero suplente"},
+}
+
+func cargoTitular(p *models.EleccionProceso) string {
+	if c := strings.TrimSpace(p.CargoTitular); c != "" {
+		return c
+	}
+	return eleccionCargosPorAmbito[tipoAmbitoProceso(p)][0]
+}
+
+func cargoSuplente(p *models.EleccionProceso) string {
+	if c := strings.TrimSpace(p.CargoSuplente); c != "" {
+		return c
+	}
+	return eleccionCargosPorAmbito[tipoAmbitoProceso(p)][1]
+}
+
+// cargoElegido qué se elige, para mensajes y el acta: "representante de aprendices", "vocero de la ficha 2567890".
+func cargoElegido(p *models.EleccionProceso) string {
+	cargo := strings.ToLower(cargoTitular(p))
+	switch tipoAmbitoProceso(p) {
+	case models.EleccionAmbitoFicha:
+		if p.Ficha != nil {
+			return cargo + " de la ficha " + p.Ficha.Ficha
+		}
+		return cargo + " de la ficha"
+	case models.EleccionAmbitoSede:
+		if p.Sede != nil {
+			return cargo + " de la sede " + p.Sede.Nombre
+		}
+		return cargo + " de la sede"
+	}
+	return cargo + " de aprendices"
+}
+
+// aplicarAmbitoProceso fija ámbito, sede, ficha y cargos del ciclo. La sede o ficha debe ser de la regional del
+// ciclo; un ciclo de ficha guarda también la sede de la ficha.
+func (s *eleccionService) aplicarAmbitoProceso(p *models.EleccionProceso, req dto.EleccionProcesoRequest) error {
+	ambito := req.Ambito
+	if ambito == "" {
+		ambito = models.EleccionAmbitoRegional
+	}
+	p.Ambito, p.SedeID, p.FichaID = ambito, nil, nil
+	switch ambito {
+	case models.EleccionAmbitoRegional:
+	case models.EleccionAmbitoSede:
+		if req.SedeID == nil {
+			return errors.New("debe indicar la sede del ciclo")
+		}
+		sede, err := s.repo.FindSedeByID(*req.SedeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errEleccionAmbitoInvalido
+			}
+			return err
+		}
+		if sede.RegionalID == nil || *sede.RegionalID != req.RegionalID {
+			return errEleccionAmbitoInvalido
+		}
+		p.SedeID = &sede.ID
+	case models.EleccionAmbitoFicha:
+		if req.FichaID == nil {
+			return errors.New("debe indicar la ficha del ciclo")
+		}
+		ficha, err := s.repo.FindFichaByID(*req.FichaID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errEleccionAmbitoInvalido
+			}
+			return err
+		}
+		if ficha.Sede == nil || ficha.Sede.RegionalID == nil || *ficha.Sede.RegionalID != req.RegionalID {
+			return errEleccionAmbitoInvalido
+		}
+		p.FichaID, p.SedeID = &ficha.ID, ficha.SedeID
+	default:
+		return errors.New("ámbito no válido")
+	}
+	if p.RegionalID != req.RegionalID {
+		p.Regional = nil
+	}
+	// Sin las relaciones cargadas para que Save no vuelva a escribir los IDs anteriores.
+	p.RegionalID, p.Sede, p.Ficha = req.RegionalID, nil, nil
+	p.CargoTitular = strings.TrimSpace(req.CargoTitular)
+	p.CargoSuplente = strings.TrimSpace(req.CargoSuplente)
+	p.CargoTitular, p.CargoSuplente = cargoTitular(p), cargoSuplente(p)
+	return nil
+}
+
 func (s *eleccionService) enrichProcesoResponse(p *models.EleccionProceso) dto.EleccionProcesoResponse {
 	resp := mapProcesoToDTO(p)
 	if n, err := s.repo.CountPlanchasConfirmadas(p.ID); err == nil {
 		resp.PlanchasConfirmadas = int(n)
 	}
-	if n, err := s.repo.CountVotosByProceso(p.ID); err == nil {
+	if n, err := s.repo.CountParticipacionesByProceso(p.ID); err == nil {
 		resp.VotosRegistrados = int(n)
 	}
-	if n, err := s.repo.CountAprendicesActivosByRegional(p.RegionalID); err == nil {
-		resp.AprendicesElegibles = int(n)
+	if ambito, err := ambitoProceso(p); err == nil {
+		if n, err := s.repo.CountPadron(ambito); err == nil {
+			resp.AprendicesElegibles = int(n)
+		}
 	}
 	return resp
 }
 
-func (s *eleccionService) assertCicloUnicoRegionalAnio(regionalID uint, anio int, excludeID uint) error {
-	exists, err := s.repo.ExistsProcesoByRegionalAnio(regionalID, anio, excludeID)
+func (s *eleccionService) assertCicloUnicoAmbitoAnio(p *models.EleccionProceso, anio int, excludeID uint) error {
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return err
+	}
+	exists, err := s.repo.ExistsProcesoEnAmbitoAnio(ambito, anio, excludeID)
 	if err != nil {
 		return err
 	}
@@ -69,11 +169,7 @@ func (s *eleccionService) CreateProceso(userID uint, roles []string, req dto.Ele
 	if err := assertEleccionScope(s.scopeSvc, scope, req.RegionalID); err != nil {
 		return nil, err
 	}
-	if err := s.assertCicloUnicoRegionalAnio(req.RegionalID, req.Anio, 0); err != nil {
-		return nil, err
-	}
 	p := &models.EleccionProceso{
-		RegionalID:             req.RegionalID,
 		Anio:                   req.Anio,
 		NombreCiclo:            strings.TrimSpace(req.NombreCiclo),
 		Estado:                 models.EleccionEstadoBorrador,
@@ -82,28 +178,46 @@ func (s *eleccionService) CreateProceso(userID uint, roles []string, req dto.Ele
 		FechaVotacionInicio:    req.FechaVotacionInicio,
 		FechaVotacionFin:       req.FechaVotacionFin,
 		MinDiasMatricula:       req.MinDiasMatricula,
+		AutoTransiciones:       req.AutoTransiciones,
 		UserAuditModel:         models.UserAuditModel{UserCreateID: &userID},
 	}
+	if err := s.aplicarAmbitoProceso(p, req); err != nil {
+		return nil, err
+	}
+	if err := s.assertCicloUnicoAmbitoAnio(p, p.Anio, 0); err != nil {
+		return nil, err
+	}
 	if err := s.repo.CreateProceso(p); err != nil {
 		return nil, err
 	}
-	p, _ = s.repo.FindProcesoByID(p.ID)
+	p, err = s.repo.FindProcesoByID(p.ID)
+	if err != nil {
+		return nil, err
+	}
 	resp := s.enrichProcesoResponse(p)
 	return &resp, nil
 }
 
 func (s *eleccionService) UpdateProceso(userID uint, roles []string, id uint, req dto.EleccionProcesoRequest) (*dto.EleccionProcesoResponse, error) {
-	p, _, err := s.loadProcesoScoped(userID, roles, id)
+	p, scope, err := s.loadProcesoScoped(userID, roles, id)
 	if err != nil {
 		return nil, err
 	}
 	if p.Estado == models.EleccionEstadoCerrada {
 		return nil, errors.New("no se puede editar un proceso cerrado")
 	}
-	if err := s.assertCicloUnicoRegionalAnio(req.RegionalID, req.Anio, p.ID); err != nil {
+	if err := assertEleccionScope(s.scopeSvc, scope, req.RegionalID); err != nil {
+		return nil, err
+	}
+	if p.Estado != models.EleccionEstadoBorrador && !mismoAmbito(p, req) {
+		return nil, errors.New("la regional, sede o ficha del ciclo solo se puede cambiar en borrador")
+	}
+	if err := s.aplicarAmbitoProceso(p, req); err != nil {
+		return nil, err
+	}
+	if err := s.assertCicloUnicoAmbitoAnio(p, req.Anio, p.ID); err != nil {
 		return nil, err
 	}
-	p.RegionalID = req.RegionalID
 	p.Anio = req.Anio
 	p.NombreCiclo = strings.TrimSpace(req.NombreCiclo)
 	p.FechaInscripcionInicio = req.FechaInscripcionInicio
@@ -111,29 +225,58 @@ func (s *eleccionService) UpdateProceso(userID uint, roles []string, id uint, re
 	p.FechaVotacionInicio = req.FechaVotacionInicio
 	p.FechaVotacionFin = req.FechaVotacionFin
 	p.MinDiasMatricula = req.MinDiasMatricula
+	p.AutoTransiciones = req.AutoTransiciones
 	p.UserEditID = &userID
 	if err := s.repo.UpdateProceso(p); err != nil {
 		return nil, err
 	}
+	p, err = s.repo.FindProcesoByID(p.ID)
+	if err != nil {
+		return nil, err
+	}
 	resp := s.enrichProcesoResponse(p)
 	return &resp, nil
 }
 
+// mismoAmbito la edición conserva regional, ámbito, sede y ficha del ciclo.
+func mismoAmbito(p *models.EleccionProceso, req dto.EleccionProcesoRequest) bool {
+	ambito := req.Ambito
+	if ambito == "" {
+		ambito = models.EleccionAmbitoRegional
+	}
+	if p.RegionalID != req.RegionalID || tipoAmbitoProceso(p) != ambito {
+		return false
+	}
+	switch ambito {
+	case models.EleccionAmbitoSede:
+		return req.SedeID != nil && p.SedeID != nil && *req.SedeID == *p.SedeID
+	case models.EleccionAmbitoFicha:
+		return req.FichaID != nil && p.FichaID != nil && *req.FichaID == *p.FichaID
+	}
+	return true
+}
+
 func (s *eleccionService) CambiarEstadoProceso(userID uint, roles []string, id uint, estado string) (*dto.EleccionProcesoResponse, error) {
 	p, _, err := s.loadProcesoScoped(userID, roles, id)
 	if err != nil {
 		return nil, err
 	}
+	anterior := p.Estado
 	switch estado {
 	case models.EleccionEstadoBorrador, models.EleccionEstadoInscripcion, models.EleccionEstadoVotacion, models.EleccionEstadoCerrada:
-		p.Estado = estado
 	default:
 		return nil, errors.New("estado no válido")
 	}
-	p.UserEditID = &userID
-	if err := s.repo.UpdateProceso(p); err != nil {
+	ok, err := s.repo.CambiarEstadoProceso(p.ID, anterior, estado, &userID)
+	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		return nil, errEleccionFaseInvalida
+	}
+	p.Estado = estado
+	p.UserEditID = &userID
+	s.registrarEventoProceso(p, anterior, &userID, "")
 	resp := s.enrichProcesoResponse(p)
 	return &resp, nil
 }
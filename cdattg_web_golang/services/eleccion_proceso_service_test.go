@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"gorm.io/gorm"
+)
+
+func ptrUint(v uint) *uint { return &v }
+
+// aprendizAmbitoFake aprendiz activo de la ficha indicada, en una sede de la regional.
+func aprendizAmbitoFake(regionalID, sedeID, fichaID uint) *models.Aprendiz {
+	a := &models.Aprendiz{
+		Estado:                 true,
+		FichaCaracterizacionID: fichaID,
+		FichaCaracterizacion: &models.FichaCaracterizacion{
+			SedeID: ptrUint(sedeID),
+			Sede:   &models.Sede{RegionalID: ptrUint(regionalID)},
+		},
+	}
+	a.FichaCaracterizacion.ID = fichaID
+	return a
+}
+
+func TestAprendizEnAmbito(t *testing.T) {
+	a := aprendizAmbitoFake(3, 30, 300)
+	casos := []struct {
+		nombre string
+		p      models.EleccionProceso
+		want   error
+	}{
+		{"regional propia", models.EleccionProceso{RegionalID: 3}, nil},
+		{"otra regional", models.EleccionProceso{RegionalID: 4}, errEleccionAprendizRegional},
+		{"su sede", models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoSede, SedeID: ptrUint(30)}, nil},
+		{"otra sede", models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoSede, SedeID: ptrUint(31)}, errEleccionAprendizAmbito},
+		{"su ficha", models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoFicha, SedeID: ptrUint(30), FichaID: ptrUint(300)}, nil},
+		{"otra ficha de su sede", models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoFicha, SedeID: ptrUint(30), FichaID: ptrUint(301)}, errEleccionAprendizAmbito},
+	}
+	for _, c := range casos {
+		if err := aprendizEnAmbito(a, &c.p); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.nombre, err, c.want)
+		}
+	}
+}
+
+func TestCargosPorAmbito(t *testing.T) {
+	ficha := &models.EleccionProceso{Ambito: models.EleccionAmbitoFicha, FichaID: ptrUint(300), Ficha: &models.FichaCaracterizacion{Ficha: "2567890"}}
+	if cargoTitular(ficha) != "Vocero" || cargoSuplente(ficha) != "Vocero suplente" {
+		t.Fatalf("cargos de ficha = %q / %q", cargoTitular(ficha), cargoSuplente(ficha))
+	}
+	if got := cargoElegido(ficha); got != "vocero de la ficha 2567890" {
+		t.Fatalf("cargoElegido = %q", got)
+	}
+	regional := &models.EleccionProceso{RegionalID: 3}
+	if cargoTitular(regional) != "Representante" || cargoElegido(regional) != "representante de aprendices" {
+		t.Fatalf("cargos de regional = %q (%q)", cargoTitular(regional), cargoElegido(regional))
+	}
+	// Un ciclo de ficha o sede sin su ficha o sede no se trata como regional: el padrón sería toda la regional.
+	if got, err := ambitoProceso(&models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoFicha}); !errors.Is(err, errEleccionAmbitoIncompleto) {
+		t.Fatalf("ambitoProceso sin ficha = %+v, err = %v", got, err)
+	}
+	if _, err := ambitoProceso(&models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoSede}); !errors.Is(err, errEleccionAmbitoIncompleto) {
+		t.Fatalf("ambitoProceso sin sede: err = %v", err)
+	}
+	if got, err := ambitoProceso(regional); err != nil || got.Tipo != models.EleccionAmbitoRegional || got.ID != 3 {
+		t.Fatalf("ambitoProceso regional = %+v, err = %v", got, err)
+	}
+	configurado := &models.EleccionProceso{CargoTitular: " Delegado ", CargoSuplente: "Delegado suplente"}
+	if cargoTitular(configurado) != "Delegado" || cargoSuplente(configurado) != "Delegado suplente" {
+		t.Fatalf("cargos configurados = %q / %q", cargoTitular(configurado), cargoSuplente(configurado))
+	}
+}
+
+// eleccionAmbitoRepoFake sedes 30 (regional 3) y 40 (regional 4); ficha 300 en la sede 30.
+type eleccionAmbitoRepoFake struct {
+	repositories.EleccionRepository
+}
+
+func (eleccionAmbitoRepoFake) FindSedeByID(id uint) (*models.Sede, error) {
+	regional := map[uint]uint{30: 3, 40: 4}[id]
+	if regional == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	sede := &models.Sede{RegionalID: ptrUint(regional)}
+	sede.ID = id
+	return sede, nil
+}
+
+func (eleccionAmbitoRepoFake) FindFichaByID(id uint) (*models.FichaCaracterizacion, error) {
+	if id != 300 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	ficha := &models.FichaCaracterizacion{Ficha: "2567890", SedeID: ptrUint(30), Sede: &models.Sede{RegionalID: ptrUint(3)}}
+	ficha.ID = id
+	return ficha, nil
+}
+
+func TestAplicarAmbitoProceso(t *testing.T) {
+	s := &eleccionService{repo: eleccionAmbitoRepoFake{}}
+
+	p := &models.EleccionProceso{}
+	req := dto.EleccionProcesoRequest{RegionalID: 3, Ambito: models.EleccionAmbitoFicha, FichaID: ptrUint(300)}
+	if err := s.aplicarAmbitoProceso(p, req); err != nil {
+		t.Fatal(err)
+	}
+	if p.FichaID == nil || *p.FichaID != 300 || p.SedeID == nil || *p.SedeID != 30 || p.CargoTitular != "Vocero" || p.CargoSuplente != "Vocero suplente" {
+		t.Fatalf("ciclo de ficha = %+v", p)
+	}
+	if a, err := ambitoProceso(p); err != nil || a.Tipo != models.EleccionAmbitoFicha || a.ID != 300 {
+		t.Fatalf("ambitoProceso = %+v, err = %v", a, err)
+	}
+
+	// Volver a regional limpia la sede y la ficha.
+	if err := s.aplicarAmbitoProceso(p, dto.EleccionProcesoRequest{RegionalID: 3, CargoTitular: "Representante"}); err != nil {
+		t.Fatal(err)
+	}
+	if p.Ambito != models.EleccionAmbitoRegional || p.SedeID != nil || p.FichaID != nil {
+		t.Fatalf("ciclo regional = %+v", p)
+	}
+
+	rechazos := []dto.EleccionProcesoRequest{
+		{RegionalID: 3, Ambito: models.EleccionAmbitoSede, SedeID: ptrUint(40)},
+		{RegionalID: 4, Ambito: models.EleccionAmbitoFicha, FichaID: ptrUint(300)},
+		{RegionalID: 3, Ambito: models.EleccionAmbitoFicha, FichaID: ptrUint(999)},
+	}
+	for _, r := range rechazos {
+		if err := s.aplicarAmbitoProceso(&models.EleccionProceso{}, r); !errors.Is(err, errEleccionAmbitoInvalido) {
+			t.Errorf("%+v: err = %v", r, err)
+		}
+	}
+	if err := s.aplicarAmbitoProceso(&models.EleccionProceso{}, dto.EleccionProcesoRequest{RegionalID: 3, Ambito: models.EleccionAmbitoSede}); err == nil {
+		t.Error("un ciclo de sede sin sede debe rechazarse")
+	}
+}
+
+// eleccionMatriculasRepoFake matrículas activas de varias personas; filtra por ámbito como el padrón.
+type eleccionMatriculasRepoFake struct {
+	repositories.EleccionRepository
+	activos []models.Aprendiz
+}
+
+func (r eleccionMatriculasRepoFake) FindAprendizActivoEnAmbito(personaID uint, ambito repositories.EleccionAmbito) (*models.Aprendiz, error) {
+	for i := range r.activos {
+		a := &r.activos[i]
+		ficha := a.FichaCaracterizacion
+		var id uint
+		switch ambito.Tipo {
+		case models.EleccionAmbitoFicha:
+			id = a.FichaCaracterizacionID
+		case models.EleccionAmbitoSede:
+			id = *ficha.SedeID
+		default:
+			id = *ficha.Sede.RegionalID
+		}
+		if a.PersonaID == personaID && id == ambito.ID {
+			return a, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// aprendizMatriculasRepoFake como FindActivoByPersonaID real: solo la matrícula activa actualizada más reciente.
+type aprendizMatriculasRepoFake struct {
+	repositories.AprendizRepository
+	reciente map[uint]*models.Aprendiz
+}
+
+func (r aprendizMatriculasRepoFake) FindActivoByPersonaID(personaID uint) (*models.Aprendiz, error) {
+	if a, ok := r.reciente[personaID]; ok {
+		return a, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestAprendizActivoEnAmbito_dosMatriculasActivas(t *testing.T) {
+	enFicha300 := aprendizAmbitoFake(3, 30, 300)
+	enFicha300.ID, enFicha300.PersonaID = 1, 7
+	enFicha301 := aprendizAmbitoFake(3, 30, 301)
+	enFicha301.ID, enFicha301.PersonaID = 2, 7
+	s := &eleccionService{
+		repo:         eleccionMatriculasRepoFake{activos: []models.Aprendiz{*enFicha300, *enFicha301}},
+		aprendizRepo: aprendizMatriculasRepoFake{reciente: map[uint]*models.Aprendiz{7: enFicha301}},
+	}
+
+	// La ficha 301 es la actualizada más reciente, pero en el ciclo de la ficha 300 vota con esa matrícula.
+	p := &models.EleccionProceso{RegionalID: 3, Ambito: models.EleccionAmbitoFicha, SedeID: ptrUint(30), FichaID: ptrUint(300)}
+	a, err := s.aprendizActivoEnAmbito(7, p)
+	if err != nil || a.ID != 1 {
+		t.Fatalf("ciclo de la ficha 300: aprendiz = %+v, err = %v", a, err)
+	}
+	p.FichaID = ptrUint(301)
+	if a, err := s.aprendizActivoEnAmbito(7, p); err != nil || a.ID != 2 {
+		t.Fatalf("ciclo de la ficha 301: aprendiz = %+v, err = %v", a, err)
+	}
+
+	p.FichaID = ptrUint(302)
+	if _, err := s.aprendizActivoEnAmbito(7, p); !errors.Is(err, errEleccionAprendizAmbito) {
+		t.Fatalf("otra ficha: err = %v", err)
+	}
+	if _, err := s.aprendizActivoEnAmbito(7, &models.EleccionProceso{RegionalID: 4}); !errors.Is(err, errEleccionAprendizRegional) {
+		t.Fatalf("otra regional: err = %v", err)
+	}
+	if _, err := s.aprendizActivoEnAmbito(8, &models.EleccionProceso{RegionalID: 3}); !errors.Is(err, errEleccionNoElegible) {
+		t.Fatalf("sin matrícula activa: err = %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// eleccionReciboBytes entropía del código de recibo (80 bits → 16 caracteres base32).
+const eleccionReciboBytes = 10
+
+var eleccionReciboEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generarCodigoRecibo crea el código que se entrega al votante, agrupado en bloques de 4 (XXXX-XXXX-XXXX-XXXX).
+// Solo el votante lo conoce; en BD queda únicamente su hash.
+func generarCodigoRecibo() (string, error) {
+	b := make([]byte, eleccionReciboBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := eleccionReciboEncoding.EncodeToString(b)
+	grupos := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		grupos = append(grupos, raw[i:i+4])
+	}
+	return strings.Join(grupos, "-"), nil
+}
+
+// normalizarCodigoRecibo acepta el código con o sin guiones/espacios y en minúsculas.
+func normalizarCodigoRecibo(codigo string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(codigo) {
+		if (r >= 'A' && r <= 'Z') || (r >= '2' && r <= '7') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// hashCodigoRecibo hash publicable del recibo, ligado al proceso para que el mismo código no valga en otro ciclo.
+func hashCodigoRecibo(procesoID uint, codigo string) string {
+	sum := sha256.Sum256([]byte(strconv.FormatUint(uint64(procesoID), 10) + ":" + normalizarCodigoRecibo(codigo)))
+	return hex.EncodeToString(sum[:])
+}
@@ -11,16 +11,20 @@ const (
 // EleccionReglasDocumentadas resume decisiones de producto implementadas.
 func EleccionReglasDocumentadas() map[string]string {
 	return map[string]string{
-		"alcance":              "Por regional (aprendices activos de sedes de la regional)",
+		"alcance":              "Por ámbito: regional (aprendices activos de sus sedes), sede o ficha (aprendices activos de la ficha)",
 		"antiguedad":           "Configurable por ciclo (default 30 días matriculado)",
 		"sanciones":            "Validación manual: admin puede rechazar planchas",
 		"regional_unica":       "Un aprendiz activo por persona; regional vía ficha→sede",
 		"inscripcion_plancha":  "Solo titular o suplente se postulan a sí mismos; el compañero confirma",
 		"cambio_voto":          "No permitido: un voto por aprendiz, sin modificación",
 		"voto":                 "Todos los aprendices elegibles votan una vez (incluidos candidatos), sin modificación",
-		"no_reeleccion":        "Titular/suplente del ciclo anterior no pueden postular",
+		"voto_secreto":         "La participación (quién votó) y la papeleta (por qué plancha) se guardan por separado; el votante recibe un código para verificar su papeleta",
+		"no_reeleccion":        "Titular/suplente de ciclos anteriores del mismo ámbito no pueden postular",
 		"confirmacion_plancha": "Titular y suplente deben confirmar",
 		"empate":               "Desempate manual registrado por admin (acta/sorteo)",
-		"ciclo_anual_unico":    "Un solo ciclo electoral por regional y año calendario",
+		"ciclo_anual_unico":    "Un solo ciclo electoral por ámbito (regional, sede o ficha) y año calendario",
+		"cargos":               "Configurables por ciclo; por defecto Representante/Suplente (regional), Representante de sede/Suplente y Vocero/Vocero suplente (ficha)",
+		"fases_automaticas":    "Opcional por ciclo: las fases avanzan solas en las fechas configuradas y el resultado se calcula al cerrar la votación",
+		"ventana_votacion":     "Solo se vota entre fecha_votacion_inicio y fecha_votacion_fin más la gracia (ELECCION_GRACIA_MINUTOS)",
 	}
 }
@@ -3,11 +3,13 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
 	"gorm.io/gorm"
 )
 
@@ -19,7 +21,7 @@ func (s *eleccionService) CalcularResultado(userID uint, roles []string, id uint
 	if p.Estado != models.EleccionEstadoVotacion && p.Estado != models.EleccionEstadoEmpatePendiente {
 		return nil, errEleccionFaseInvalida
 	}
-	return s.finalizarConteo(userID, p, nil, "")
+	return s.finalizarConteo(&userID, p, nil, "")
 }
 
 func (s *eleccionService) RegistrarDesempate(userID uint, roles []string, id uint, req dto.EleccionDesempateRequest) (*dto.EleccionResultadoResponse, error) {
@@ -30,51 +32,95 @@ func (s *eleccionService) RegistrarDesempate(userID uint, roles []string, id uin
 	if p.Estado != models.EleccionEstadoEmpatePendiente {
 		return nil, errors.New("el proceso no está pendiente de desempate")
 	}
-	planchaID := req.PlanchaGanadoraID
-	nota := strings.TrimSpace(req.NotaDesempate)
-	return s.finalizarConteo(userID, p, &planchaID, nota)
+	req.NotaDesempate = strings.TrimSpace(req.NotaDesempate)
+	return s.finalizarConteo(&userID, p, &req, "")
 }
 
-func (s *eleccionService) finalizarConteo(userID uint, p *models.EleccionProceso, ganadorForzado *uint, notaDesempate string) (*dto.EleccionResultadoResponse, error) {
-	conteo, total, err := s.buildConteo(p.ID)
+// finalizarConteo registra el resultado. Con desempate, la plancha elegida debe ser una de las empatadas. Sin
+// usuario lo calcula el planificador al cerrar la votación; detalle queda en la bitácora de fases. El conteo y el
+// cambio de fase se hacen con la fila del proceso bloqueada, la misma que bloquea cada voto, y solo si el proceso
+// sigue en la fase con la que se validó la petición.
+func (s *eleccionService) finalizarConteo(userID *uint, p *models.EleccionProceso, desempate *dto.EleccionDesempateRequest, detalle string) (*dto.EleccionResultadoResponse, error) {
+	anterior := p.Estado
+	var (
+		conteo []dto.EleccionResultadoPlanchaConteo
+		total  int
+		res    *models.EleccionResultado
+		estado string
+	)
+	err := s.repo.EnProcesoBloqueado(p.ID, func(repo repositories.EleccionRepository, bloqueado *models.EleccionProceso) error {
+		if bloqueado.Estado != anterior {
+			return errEleccionFaseInvalida
+		}
+		var err error
+		conteo, total, err = conteoProceso(repo, p.ID)
+		if err != nil {
+			return err
+		}
+		res, estado, err = resultadoConteo(p.ID, conteo, total, desempate, userID)
+		if err != nil {
+			return err
+		}
+		if err := repo.SaveResultado(res); err != nil {
+			return err
+		}
+		_, err = repo.CambiarEstadoProceso(p.ID, anterior, estado, userID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	p.Estado = estado
+	p.UserEditID = userID
+	s.registrarEventoProceso(p, anterior, userID, detalle)
+	if p.Estado == models.EleccionEstadoEmpatePendiente && anterior != p.Estado {
+		s.notificarEmpate(p, len(planchasEmpatadas(conteo)), userID)
+	}
+	if p.Estado == models.EleccionEstadoCerrada && res.PlanchaGanadoraID != nil {
+		if err := s.publicarRepresentantes(p, *res.PlanchaGanadoraID); err != nil {
+			return nil, err
+		}
+	}
+	if p.Estado == models.EleccionEstadoCerrada {
+		if _, err := s.asegurarActa(userID, p); err != nil {
+			// El cierre ya quedó registrado; el acta se vuelve a intentar al descargarla.
+			log.Printf("[elecciones] generando acta del proceso %d: %v", p.ID, err)
+		}
+	}
+	return s.buildResultadoResponse(p, res, conteo, total, false)
+}
+
+// resultadoConteo resultado a guardar y la fase en la que queda el proceso: cerrada con ganador (o con la plancha
+// del desempate) o empate_pendiente.
+func resultadoConteo(procesoID uint, conteo []dto.EleccionResultadoPlanchaConteo, total int, desempate *dto.EleccionDesempateRequest, userID *uint) (*models.EleccionResultado, string, error) {
 	empate, ganadorID := detectarEmpate(conteo)
+	if desempate != nil && !containsUint(planchasEmpatadas(conteo), desempate.PlanchaGanadoraID) {
+		return nil, "", errEleccionDesempatePlancha
+	}
 	res := &models.EleccionResultado{
-		ProcesoID:      p.ID,
+		ProcesoID:      procesoID,
 		VotosTotales:   total,
 		DetalleJSON:    encodeDetalleJSON(conteo),
 		Empate:         empate,
-		UserRegistroID: &userID,
+		UserRegistroID: userID,
 	}
-	if ganadorForzado != nil {
+	now := time.Now()
+	switch {
+	case desempate != nil:
 		res.Empate = false
-		res.PlanchaGanadoraID = ganadorForzado
-		if notaDesempate != "" {
-			res.NotaDesempate = &notaDesempate
+		res.PlanchaGanadoraID = &desempate.PlanchaGanadoraID
+		if desempate.NotaDesempate != "" {
+			res.NotaDesempate = &desempate.NotaDesempate
 		}
-		p.Estado = models.EleccionEstadoCerrada
-	} else if empate {
-		res.Empate = true
-		p.Estado = models.EleccionEstadoEmpatePendiente
-	} else {
+		res.DesempateMetodo = &desempate.Metodo
+		res.DesempateAt = &now
+	case empate:
+		return res, models.EleccionEstadoEmpatePendiente, nil
+	default:
 		res.PlanchaGanadoraID = ganadorID
-		p.Estado = models.EleccionEstadoCerrada
-	}
-	if err := s.repo.SaveResultado(res); err != nil {
-		return nil, err
-	}
-	p.UserEditID = &userID
-	if err := s.repo.UpdateProceso(p); err != nil {
-		return nil, err
 	}
-	if p.Estado == models.EleccionEstadoCerrada && res.PlanchaGanadoraID != nil {
-		if err := s.publicarRepresentantes(p, *res.PlanchaGanadoraID); err != nil {
-			return nil, err
-		}
-	}
-	return s.buildResultadoResponse(p, res, conteo, total, false)
+	res.CerradaAt = &now
+	return res, models.EleccionEstadoCerrada, nil
 }
 
 func (s *eleccionService) publicarRepresentantes(p *models.EleccionProceso, planchaID uint) error {
@@ -82,12 +128,19 @@ func (s *eleccionService) publicarRepresentantes(p *models.EleccionProceso, plan
 	if err != nil {
 		return err
 	}
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return err
+	}
 	now := time.Now()
-	if err := s.repo.CerrarVigenciaRepresentantes(p.RegionalID, now); err != nil {
+	if err := s.repo.CerrarVigenciaRepresentantes(ambito, now); err != nil {
 		return err
 	}
 	return s.repo.CreateRepresentante(&models.RepresentanteAprendiz{
 		RegionalID:         p.RegionalID,
+		Ambito:             ambito.Tipo,
+		SedeID:             p.SedeID,
+		FichaID:            p.FichaID,
 		ProcesoID:          p.ID,
 		TitularAprendizID:  plancha.TitularAprendizID,
 		SuplenteAprendizID: plancha.SuplenteAprendizID,
@@ -96,7 +149,14 @@ func (s *eleccionService) publicarRepresentantes(p *models.EleccionProceso, plan
 }
 
 func (s *eleccionService) buildResultadoResponse(p *models.EleccionProceso, res *models.EleccionResultado, conteo []dto.EleccionResultadoPlanchaConteo, total int, incluirVotos bool) (*dto.EleccionResultadoResponse, error) {
-	elegibles, _ := s.repo.CountAprendicesActivosByRegional(p.RegionalID)
+	ambito, err := ambitoProceso(p)
+	if err != nil {
+		return nil, err
+	}
+	elegibles, err := s.repo.CountPadron(ambito)
+	if err != nil {
+		return nil, err
+	}
 	participacion := 0.0
 	if elegibles > 0 {
 		participacion = float64(total) / float64(elegibles) * 100
@@ -109,34 +169,65 @@ func (s *eleccionService) buildResultadoResponse(p *models.EleccionProceso, res
 		ParticipacionPct:  participacion,
 		Empate:            res.Empate,
 		NotaDesempate:     res.NotaDesempate,
+		DesempateMetodo:   res.DesempateMetodo,
+		DesempateAt:       res.DesempateAt,
 		Conteo:            conteo,
 	}
+	if p.Estado == models.EleccionEstadoCerrada {
+		if acta, err := s.repo.FindActaByProceso(p.ID); err == nil {
+			out.ActaHash = &acta.Hash
+		}
+	}
 	if incluirVotos {
-		votos, err := s.repo.ListVotosByProceso(p.ID)
-		if err != nil {
+		if err := s.adjuntarAuditoria(out, p.ID); err != nil {
 			return nil, err
 		}
-		planchas, _ := s.repo.ListPlanchasByProceso(p.ID, false)
-		labelByID := make(map[uint]string, len(planchas))
-		for i := range planchas {
-			labelByID[planchas[i].ID] = planchaLabel(&planchas[i])
+	}
+	return out, nil
+}
+
+// adjuntarAuditoria añade la lista de participantes y la de papeletas por separado: ninguna de las dos
+// permite saber por quién votó cada aprendiz, pero juntas permiten verificar el conteo.
+func (s *eleccionService) adjuntarAuditoria(out *dto.EleccionResultadoResponse, procesoID uint) error {
+	participaciones, err := s.repo.ListParticipacionesByProceso(procesoID)
+	if err != nil {
+		return err
+	}
+	papeletas, err := s.repo.ListPapeletasByProceso(procesoID)
+	if err != nil {
+		return err
+	}
+	planchas, _ := s.repo.ListPlanchasByProceso(procesoID, false)
+	labelByID := make(map[uint]string, len(planchas))
+	for i := range planchas {
+		labelByID[planchas[i].ID] = planchaLabel(&planchas[i])
+	}
+	out.Participantes = make([]dto.EleccionParticipanteItem, len(participaciones))
+	for i := range participaciones {
+		doc := ""
+		if participaciones[i].VotanteAprendiz != nil && participaciones[i].VotanteAprendiz.Persona != nil {
+			doc = participaciones[i].VotanteAprendiz.Persona.NumeroDocumento
+		}
+		out.Participantes[i] = dto.EleccionParticipanteItem{
+			VotanteNombre: nombreAprendiz(participaciones[i].VotanteAprendiz),
+			VotanteDoc:    doc,
+			VotadoAt:      participaciones[i].VotadoAt,
 		}
-		out.Votos = make([]dto.EleccionVotoAuditoriaItem, len(votos))
-		for i := range votos {
-			doc := ""
-			if votos[i].VotanteAprendiz != nil && votos[i].VotanteAprendiz.Persona != nil {
-				doc = votos[i].VotanteAprendiz.Persona.NumeroDocumento
-			}
-			out.Votos[i] = dto.EleccionVotoAuditoriaItem{
-				VotanteNombre: nombreAprendiz(votos[i].VotanteAprendiz),
-				VotanteDoc:    doc,
-				PlanchaID:     votos[i].PlanchaID,
-				PlanchaLabel:  labelByID[votos[i].PlanchaID],
-				VotadoAt:      votos[i].UpdatedAt,
-			}
+	}
+	out.Papeletas = make([]dto.EleccionPapeletaAuditoriaItem, len(papeletas))
+	for i := range papeletas {
+		out.Papeletas[i] = dto.EleccionPapeletaAuditoriaItem{
+			ReciboHash:   papeletas[i].ReciboHash,
+			PlanchaID:    papeletas[i].PlanchaID,
+			PlanchaLabel: labelByID[papeletas[i].PlanchaID],
 		}
 	}
-	return out, nil
+	out.Auditoria = &dto.EleccionAuditoriaResumen{
+		Participaciones: len(participaciones),
+		Papeletas:       len(papeletas),
+		Consistente:     len(participaciones) == len(papeletas),
+	}
+	return nil
 }
 
 func (s *eleccionService) GetResultados(userID uint, roles []string, procesoID uint, incluirVotos bool) (*dto.EleccionResultadoResponse, error) {
@@ -151,7 +242,14 @@ func (s *eleccionService) GetResultados(userID uint, roles []string, procesoID u
 			if errC != nil {
 				return nil, errC
 			}
-			elegibles, _ := s.repo.CountAprendicesActivosByRegional(p.RegionalID)
+			ambito, errA := ambitoProceso(p)
+			if errA != nil {
+				return nil, errA
+			}
+			elegibles, errP := s.repo.CountPadron(ambito)
+			if errP != nil {
+				return nil, errP
+			}
 			participacion := 0.0
 			if elegibles > 0 {
 				participacion = float64(total) / float64(elegibles) * 100
@@ -164,7 +262,6 @@ func (s *eleccionService) GetResultados(userID uint, roles []string, procesoID u
 				ParticipacionPct: participacion,
 				Empate:           empate,
 				Conteo:           conteo,
-				Votos:            nil,
 			}, nil
 		}
 		return nil, err
@@ -176,19 +273,28 @@ func (s *eleccionService) GetResultados(userID uint, roles []string, procesoID u
 	return s.buildResultadoResponse(p, res, conteo, res.VotosTotales, incluirVotos)
 }
 
+// ExportResultadosCSV exporta el escrutinio auditable: papeletas anónimas (hash de recibo + plancha),
+// conteo por plancha y el cuadre participaciones/papeletas. No incluye datos de votantes.
 func (s *eleccionService) ExportResultadosCSV(userID uint, roles []string, procesoID uint) ([]byte, error) {
 	res, err := s.GetResultados(userID, roles, procesoID, true)
 	if err != nil {
 		return nil, err
 	}
+	if res.Auditoria == nil {
+		if err := s.adjuntarAuditoria(res, procesoID); err != nil {
+			return nil, err
+		}
+	}
 	var b strings.Builder
-	b.WriteString("votante_nombre,votante_documento,plancha_id,plancha_label,votado_at\n")
-	for _, v := range res.Votos {
-		b.WriteString(fmt.Sprintf("%q,%q,%d,%q,%s\n", v.VotanteNombre, v.VotanteDoc, v.PlanchaID, v.PlanchaLabel, v.VotadoAt.Format(time.RFC3339)))
+	b.WriteString("recibo_hash,plancha_id,plancha_label\n")
+	for _, p := range res.Papeletas {
+		b.WriteString(fmt.Sprintf("%s,%d,%q\n", p.ReciboHash, p.PlanchaID, p.PlanchaLabel))
 	}
 	b.WriteString("\n# conteo\nplancha_id,label,votos\n")
 	for _, c := range res.Conteo {
 		b.WriteString(fmt.Sprintf("%d,%q,%d\n", c.PlanchaID, c.Label, c.Votos))
 	}
+	b.WriteString("\n# cuadre\nparticipaciones,papeletas,consistente\n")
+	b.WriteString(fmt.Sprintf("%d,%d,%t\n", res.Auditoria.Participaciones, res.Auditoria.Papeletas, res.Auditoria.Consistente))
 	return []byte(b.String()), nil
 }
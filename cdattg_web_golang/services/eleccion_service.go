@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 
 	"github.com/sena/cdattg-web-golang/dto"
@@ -28,8 +29,15 @@ type EleccionService interface {
 	RetirarPlancha(userID uint, personaID *uint, planchaID uint) error
 
 	RegistrarVoto(userID uint, personaID *uint, procesoID uint, req dto.EleccionVotoRequest) (*dto.EleccionVotoResponse, error)
+	VerificarRecibo(procesoID uint, codigo string) (*dto.EleccionReciboResponse, error)
 	GetResultados(userID uint, roles []string, procesoID uint, incluirVotos bool) (*dto.EleccionResultadoResponse, error)
 	ExportResultadosCSV(userID uint, roles []string, procesoID uint) ([]byte, error)
+	GenerarActaPDF(userID uint, roles []string, procesoID uint) ([]byte, error)
+	VerificarActa(hash string) (*dto.EleccionActaVerificacionResponse, error)
+	GetParticipacion(userID uint, roles []string, procesoID uint) (*dto.EleccionParticipacionResponse, error)
+	EnviarRecordatorioVoto(userID uint, roles []string, procesoID uint, req dto.EleccionRecordatorioRequest) (*dto.EleccionRecordatorioResponse, error)
+	ListEventosProceso(userID uint, roles []string, procesoID uint) ([]dto.EleccionProcesoEventoResponse, error)
+	AvanzarFasesAutomaticas(ctx context.Context) error
 
 	GetMiRegional(userID uint, personaID *uint) (*dto.EleccionMiRegionalResponse, error)
 	ListPlanchasConfirmadasAprendiz(personaID *uint, procesoID uint) ([]dto.EleccionPlanchaResponse, error)
@@ -38,16 +46,22 @@ type EleccionService interface {
 }
 
 type eleccionService struct {
-	repo         repositories.EleccionRepository
-	aprendizRepo repositories.AprendizRepository
-	scopeSvc     EleccionScopeService
+	repo           repositories.EleccionRepository
+	aprendizRepo   repositories.AprendizRepository
+	scopeSvc       EleccionScopeService
+	notificaciones NotificacionUsuarioService
+	correos        CorreoService
+	usuariosConRol func(rol string) []uint
 }
 
 func NewEleccionService() EleccionService {
 	return &eleccionService{
-		repo:         repositories.NewEleccionRepository(),
-		aprendizRepo: repositories.NewAprendizRepository(),
-		scopeSvc:     NewEleccionScopeService(),
+		repo:           repositories.NewEleccionRepository(),
+		aprendizRepo:   repositories.NewAprendizRepository(),
+		scopeSvc:       NewEleccionScopeService(),
+		notificaciones: NewNotificacionUsuarioService(),
+		correos:        NewCorreoService(),
+		usuariosConRol: usuariosCasbinConRol,
 	}
 }
 
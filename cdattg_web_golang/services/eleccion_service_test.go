@@ -1,6 +1,7 @@
 package services
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sena/cdattg-web-golang/dto"
@@ -44,3 +45,33 @@ func TestEleccionReglasDocumentadas(t *testing.T) {
 		t.Fatal("cambio de voto debe estar deshabilitado")
 	}
 }
+
+func TestGenerarCodigoReciboFormato(t *testing.T) {
+	a, err := generarCodigoRecibo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := generarCodigoRecibo()
+	if a == b {
+		t.Fatal("dos recibos no deberían coincidir")
+	}
+	if len(a) != 19 || strings.Count(a, "-") != 3 {
+		t.Fatalf("formato inesperado: %q", a)
+	}
+	if normalizarCodigoRecibo(a) != strings.ReplaceAll(a, "-", "") {
+		t.Fatalf("el código generado debe sobrevivir a la normalización: %q", a)
+	}
+}
+
+func TestHashCodigoReciboNormalizaYLigaAlProceso(t *testing.T) {
+	h1 := hashCodigoRecibo(7, "ABCD-EFGH-2345-67AB")
+	if h2 := hashCodigoRecibo(7, " abcd efgh 2345 67ab "); h1 != h2 {
+		t.Fatal("el hash no debe depender de guiones, espacios ni mayúsculas")
+	}
+	if h3 := hashCodigoRecibo(8, "ABCD-EFGH-2345-67AB"); h1 == h3 {
+		t.Fatal("el mismo código en otro proceso debe dar otro hash")
+	}
+	if len(h1) != 64 {
+		t.Fatalf("hash de longitud inesperada: %d", len(h1))
+	}
+}
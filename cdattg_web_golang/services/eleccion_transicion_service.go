@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+// eleccionGraciaMinutosPorDefecto minutos que se siguen aceptando votos tras fecha_votacion_fin si no hay configuración.
+const eleccionGraciaMinutosPorDefecto = 5
+
+// graciaVotacion margen tras fecha_votacion_fin para los votos en curso (ELECCION_GRACIA_MINUTOS).
+func graciaVotacion() time.Duration {
+	minutos := eleccionGraciaMinutosPorDefecto
+	if config.AppConfig != nil {
+		minutos = config.AppConfig.Elecciones.GraciaMinutos
+	}
+	if minutos < 0 {
+		minutos = 0
+	}
+	return time.Duration(minutos) * time.Minute
+}
+
+// cierreVotacion momento desde el que ya no se aceptan votos (fecha_votacion_fin más la gracia); nil sin fecha de fin.
+func cierreVotacion(p *models.EleccionProceso) *time.Time {
+	if p.FechaVotacionFin == nil {
+		return nil
+	}
+	cierre := p.FechaVotacionFin.Add(graciaVotacion())
+	return &cierre
+}
+
+// ventanaVotacion rechaza votos antes de fecha_votacion_inicio o después del cierre, aunque el proceso siga en
+// votación (p. ej. mientras el planificador no ha cerrado la fase).
+func ventanaVotacion(p *models.EleccionProceso, ahora time.Time) error {
+	if p.FechaVotacionInicio != nil && ahora.Before(*p.FechaVotacionInicio) {
+		return fmt.Errorf("%w: abre el %s", errEleccionVotacionNoIniciada, fechaHoraEleccion(*p.FechaVotacionInicio))
+	}
+	if cierre := cierreVotacion(p); cierre != nil && !ahora.Before(*cierre) {
+		return errEleccionVotacionCerrada
+	}
+	return nil
+}
+
+func fechaHoraEleccion(t time.Time) string {
+	return t.In(utils.AppLocation()).Format("02/01/2006 15:04")
+}
+
+// faseAutomatica siguiente fase según las fechas del proceso. Para la votación devuelve cerrada: el escrutinio
+// decide si queda cerrada o en empate_pendiente. Sin fecha configurada la fase no avanza sola.
+func faseAutomatica(p *models.EleccionProceso, ahora time.Time) (estado, detalle string, ok bool) {
+	switch p.Estado {
+	case models.EleccionEstadoBorrador:
+		if p.FechaInscripcionInicio != nil && !ahora.Before(*p.FechaInscripcionInicio) {
+			return models.EleccionEstadoInscripcion, "Inicio de inscripción: " + fechaHoraEleccion(*p.FechaInscripcionInicio), true
+		}
+	case models.EleccionEstadoInscripcion:
+		if p.FechaVotacionInicio != nil {
+			if !ahora.Before(*p.FechaVotacionInicio) {
+				return models.EleccionEstadoVotacion, "Inicio de votación: " + fechaHoraEleccion(*p.FechaVotacionInicio), true
+			}
+		} else if p.FechaInscripcionFin != nil && !ahora.Before(*p.FechaInscripcionFin) {
+			return models.EleccionEstadoVotacion, "Fin de inscripción: " + fechaHoraEleccion(*p.FechaInscripcionFin), true
+		}
+	case models.EleccionEstadoVotacion:
+		if cierre := cierreVotacion(p); cierre != nil && !ahora.Before(*cierre) {
+			return models.EleccionEstadoCerrada, fmt.Sprintf("Fin de votación: %s (gracia de %d min)",
+				fechaHoraEleccion(*p.FechaVotacionFin), int(graciaVotacion()/time.Minute)), true
+		}
+	}
+	return "", "", false
+}
+
+// AvanzarFasesAutomaticas tarea del planificador: avanza los procesos con transiciones automáticas cuyas fechas ya
+// se cumplieron y, al cerrar la votación, calcula el resultado. Un proceso con todas sus fechas vencidas recorre
+// las fases pendientes en la misma ejecución, cada una con su entrada en la bitácora.
+func (s *eleccionService) AvanzarFasesAutomaticas(ctx context.Context) error {
+	procesos, err := s.repo.ListProcesosAutoTransicion()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range procesos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.avanzarProceso(&procesos[i], utils.Now()); err != nil {
+			errs = append(errs, fmt.Errorf("proceso %d: %w", procesos[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *eleccionService) avanzarProceso(p *models.EleccionProceso, ahora time.Time) error {
+	for {
+		estado, detalle, ok := faseAutomatica(p, ahora)
+		if !ok {
+			return nil
+		}
+		if estado == models.EleccionEstadoCerrada {
+			_, err := s.finalizarConteo(nil, p, nil, detalle)
+			return err
+		}
+		// Solo si nadie cambió la fase desde que se leyó el proceso; si no, la próxima ejecución parte del estado nuevo.
+		anterior := p.Estado
+		ok, err := s.repo.CambiarEstadoProceso(p.ID, anterior, estado, nil)
+		if err != nil || !ok {
+			return err
+		}
+		p.Estado = estado
+		s.registrarEventoProceso(p, anterior, nil, detalle)
+	}
+}
+
+// registrarEventoProceso deja en la bitácora el cambio de fase; sin usuario lo hizo el planificador. El cambio ya
+// quedó guardado, así que un fallo aquí solo se registra en el log.
+func (s *eleccionService) registrarEventoProceso(p *models.EleccionProceso, anterior string, userID *uint, detalle string) {
+	if anterior == p.Estado {
+		return
+	}
+	origen := models.EleccionEventoOrigenManual
+	if userID == nil {
+		origen = models.EleccionEventoOrigenAutomatico
+	}
+	err := s.repo.CreateProcesoEvento(&models.EleccionProcesoEvento{
+		ProcesoID:      p.ID,
+		EstadoAnterior: anterior,
+		EstadoNuevo:    p.Estado,
+		Origen:         origen,
+		UserID:         userID,
+		Detalle:        detalle,
+		CreatedAt:      utils.Now(),
+	})
+	if err != nil {
+		log.Printf("[elecciones] registrando cambio de fase del proceso %d (%s → %s): %v", p.ID, anterior, p.Estado, err)
+	}
+}
+
+// ListEventosProceso bitácora de cambios de fase del proceso, del más antiguo al más reciente.
+func (s *eleccionService) ListEventosProceso(userID uint, roles []string, procesoID uint) ([]dto.EleccionProcesoEventoResponse, error) {
+	p, _, err := s.loadProcesoScoped(userID, roles, procesoID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListProcesoEventos(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.EleccionProcesoEventoResponse, len(list))
+	for i := range list {
+		e := &list[i]
+		out[i] = dto.EleccionProcesoEventoResponse{
+			ID:             e.ID,
+			EstadoAnterior: e.EstadoAnterior,
+			EstadoNuevo:    e.EstadoNuevo,
+			Origen:         e.Origen,
+			UserID:         e.UserID,
+			Detalle:        e.Detalle,
+			CreatedAt:      e.CreatedAt,
+		}
+	}
+	return out, nil
+}
+
+// notificarEmpate avisa a administradores y a los coordinadores de la regional que el escrutinio quedó empatado
+// y falta registrar el desempate. No se avisa a quien calculó el resultado.
+func (s *eleccionService) notificarEmpate(p *models.EleccionProceso, empatadas int, userID *uint) {
+	if s.notificaciones == nil || s.usuariosConRol == nil {
+		return
+	}
+	vistos := make(map[uint]bool)
+	if userID != nil {
+		vistos[*userID] = true
+	}
+	var ids []uint
+	agregar := func(id uint) {
+		if !vistos[id] {
+			vistos[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, rol := range []string{"SUPER ADMINISTRADOR", "ADMINISTRADOR"} {
+		for _, id := range s.usuariosConRol(rol) {
+			agregar(id)
+		}
+	}
+	for _, id := range s.usuariosConRol("COORDINADOR") {
+		if vistos[id] {
+			continue
+		}
+		scope, err := s.scopeSvc.Resolve(id, []string{"COORDINADOR"})
+		if err != nil || !s.scopeSvc.CanAccessRegional(scope, p.RegionalID) {
+			continue
+		}
+		agregar(id)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	s.notificaciones.Notificar(ids, NotificacionNueva{
+		Tipo:    models.NotificacionTipoEleccionEmpate,
+		Titulo:  "Empate en la elección de " + cargoElegido(p),
+		Mensaje: fmt.Sprintf("El escrutinio de %s terminó con %d planchas empatadas. Registre el desempate para cerrar el proceso.", p.NombreCiclo, empatadas),
+		Enlace:  fmt.Sprintf("%s%d", rutaNotificacionEleccionAdmin, p.ID),
+	})
+}
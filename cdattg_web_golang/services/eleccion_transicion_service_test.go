@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+func conGraciaEleccion(t *testing.T, minutos int) {
+	t.Helper()
+	prev := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = prev })
+	config.AppConfig = &config.Config{Elecciones: config.EleccionesConfig{GraciaMinutos: minutos}}
+}
+
+func TestFaseAutomatica(t *testing.T) {
+	conGraciaEleccion(t, 5)
+	ahora := time.Date(2026, 10, 20, 12, 0, 0, 0, utils.AppLocation())
+	antes, despues := ahora.Add(-time.Minute), ahora.Add(time.Minute)
+	casos := []struct {
+		nombre string
+		p      models.EleccionProceso
+		want   string
+	}{
+		{"borrador sin fechas", models.EleccionProceso{Estado: models.EleccionEstadoBorrador}, ""},
+		{"borrador antes de inscripción", models.EleccionProceso{Estado: models.EleccionEstadoBorrador, FechaInscripcionInicio: &despues}, ""},
+		{"abre inscripción", models.EleccionProceso{Estado: models.EleccionEstadoBorrador, FechaInscripcionInicio: &antes}, models.EleccionEstadoInscripcion},
+		{"espera inicio de votación aunque cerró la inscripción", models.EleccionProceso{Estado: models.EleccionEstadoInscripcion, FechaInscripcionFin: &antes, FechaVotacionInicio: &despues}, ""},
+		{"abre votación", models.EleccionProceso{Estado: models.EleccionEstadoInscripcion, FechaVotacionInicio: &antes}, models.EleccionEstadoVotacion},
+		{"sin inicio de votación abre al cerrar inscripción", models.EleccionProceso{Estado: models.EleccionEstadoInscripcion, FechaInscripcionFin: &antes}, models.EleccionEstadoVotacion},
+		{"votación dentro de la gracia", models.EleccionProceso{Estado: models.EleccionEstadoVotacion, FechaVotacionFin: &antes}, ""},
+		{"cierra votación", models.EleccionProceso{Estado: models.EleccionEstadoVotacion, FechaVotacionFin: ptrTime(ahora.Add(-5 * time.Minute))}, models.EleccionEstadoCerrada},
+		{"empate no avanza solo", models.EleccionProceso{Estado: models.EleccionEstadoEmpatePendiente, FechaVotacionFin: &antes}, ""},
+	}
+	for _, c := range casos {
+		got, detalle, ok := faseAutomatica(&c.p, ahora)
+		if got != c.want || ok != (c.want != "") || (ok && detalle == "") {
+			t.Errorf("%s: fase = %q, %q, %v; want %q", c.nombre, got, detalle, ok, c.want)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
+
+func TestVentanaVotacion(t *testing.T) {
+	conGraciaEleccion(t, 10)
+	inicio := time.Date(2026, 10, 20, 8, 0, 0, 0, utils.AppLocation())
+	fin := inicio.Add(8 * time.Hour)
+	p := &models.EleccionProceso{Estado: models.EleccionEstadoVotacion, FechaVotacionInicio: &inicio, FechaVotacionFin: &fin}
+
+	if err := ventanaVotacion(p, inicio.Add(-time.Second)); !errors.Is(err, errEleccionVotacionNoIniciada) {
+		t.Fatalf("antes del inicio: err = %v", err)
+	}
+	if err := ventanaVotacion(p, fin.Add(9*time.Minute)); err != nil {
+		t.Fatalf("dentro de la gracia: err = %v", err)
+	}
+	if err := ventanaVotacion(p, fin.Add(10*time.Minute)); !errors.Is(err, errEleccionVotacionCerrada) {
+		t.Fatalf("tras la gracia: err = %v", err)
+	}
+	if err := ventanaVotacion(&models.EleccionProceso{}, fin.Add(time.Hour)); err != nil {
+		t.Fatalf("sin fechas no hay ventana: err = %v", err)
+	}
+}
+
+// eleccionTransicionRepoFake urna empatada (una papeleta por plancha) con bitácora en memoria.
+type eleccionTransicionRepoFake struct {
+	eleccionActaRepoFake
+	proceso    *models.EleccionProceso
+	eventos    []models.EleccionProcesoEvento
+	resultados []models.EleccionResultado
+}
+
+func (r *eleccionTransicionRepoFake) ListProcesosAutoTransicion() ([]models.EleccionProceso, error) {
+	return []models.EleccionProceso{*r.proceso}, nil
+}
+
+func (r *eleccionTransicionRepoFake) CambiarEstadoProceso(_ uint, anterior, nuevo string, _ *uint) (bool, error) {
+	if r.proceso.Estado != anterior {
+		return false, nil
+	}
+	r.proceso.Estado = nuevo
+	return true, nil
+}
+
+func (r *eleccionTransicionRepoFake) EnProcesoBloqueado(_ uint, fn func(repositories.EleccionRepository, *models.EleccionProceso) error) error {
+	p := *r.proceso
+	return fn(r, &p)
+}
+
+func (r *eleccionTransicionRepoFake) CreateProcesoEvento(e *models.EleccionProcesoEvento) error {
+	r.eventos = append(r.eventos, *e)
+	return nil
+}
+
+func (r *eleccionTransicionRepoFake) SaveResultado(res *models.EleccionResultado) error {
+	r.resultados = append(r.resultados, *res)
+	return nil
+}
+
+// eleccionScopeRegionalFake cada coordinador gestiona una sola regional.
+type eleccionScopeRegionalFake map[uint]uint
+
+func (f eleccionScopeRegionalFake) Resolve(userID uint, _ []string) (*EleccionScope, error) {
+	return &EleccionScope{RegionalIDs: []uint{f[userID]}}, nil
+}
+
+func (f eleccionScopeRegionalFake) CanAccessRegional(scope *EleccionScope, regionalID uint) bool {
+	return len(scope.RegionalIDs) == 1 && scope.RegionalIDs[0] == regionalID
+}
+
+type notificacionesEmpateFake struct {
+	NotificacionUsuarioService
+	usuarios []uint
+	nueva    NotificacionNueva
+}
+
+func (n *notificacionesEmpateFake) Notificar(ids []uint, nueva NotificacionNueva) {
+	n.usuarios = append(n.usuarios, ids...)
+	n.nueva = nueva
+}
+
+func TestAvanzarProceso_recorreFasesYEmpataAlCerrar(t *testing.T) {
+	conGraciaEleccion(t, 5)
+	ahora := time.Date(2026, 10, 20, 16, 2, 0, 0, utils.AppLocation())
+	inscripcion, votacion, fin := ahora.Add(-72*time.Hour), ahora.Add(-8*time.Hour), ahora.Add(-2*time.Minute)
+	p := &models.EleccionProceso{NombreCiclo: "Ciclo 2026", RegionalID: 3, Estado: models.EleccionEstadoBorrador, AutoTransiciones: true,
+		FechaInscripcionInicio: &inscripcion, FechaVotacionInicio: &votacion, FechaVotacionFin: &fin}
+	p.ID = 5
+	repo := &eleccionTransicionRepoFake{proceso: p, eleccionActaRepoFake: eleccionActaRepoFake{
+		papeletas: []models.EleccionPapeleta{{ReciboHash: "a", PlanchaID: 10}, {ReciboHash: "b", PlanchaID: 20}},
+	}}
+	notif := &notificacionesEmpateFake{}
+	roles := map[string][]uint{"SUPER ADMINISTRADOR": {1}, "ADMINISTRADOR": {1, 2}, "COORDINADOR": {7, 8}}
+	s := &eleccionService{
+		repo:           repo,
+		scopeSvc:       eleccionScopeRegionalFake{7: 3, 8: 4},
+		notificaciones: notif,
+		usuariosConRol: func(rol string) []uint { return roles[rol] },
+	}
+
+	if err := s.avanzarProceso(p, ahora); err != nil {
+		t.Fatal(err)
+	}
+	if p.Estado != models.EleccionEstadoVotacion || len(repo.eventos) != 2 || len(repo.resultados) != 0 {
+		t.Fatalf("dentro de la gracia debe seguir en votación: estado %s, %d eventos", p.Estado, len(repo.eventos))
+	}
+	if e := repo.eventos[1]; e.EstadoAnterior != models.EleccionEstadoInscripcion || e.EstadoNuevo != models.EleccionEstadoVotacion ||
+		e.Origen != models.EleccionEventoOrigenAutomatico || e.UserID != nil {
+		t.Fatalf("evento inesperado: %+v", e)
+	}
+
+	if err := s.avanzarProceso(p, ahora.Add(3*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if p.Estado != models.EleccionEstadoEmpatePendiente || len(repo.resultados) != 1 || !repo.resultados[0].Empate || repo.resultados[0].UserRegistroID != nil {
+		t.Fatalf("cierre automático: estado %s, resultados %+v", p.Estado, repo.resultados)
+	}
+	if e := repo.eventos[len(repo.eventos)-1]; len(repo.eventos) != 3 || e.EstadoNuevo != models.EleccionEstadoEmpatePendiente || e.Detalle == "" {
+		t.Fatalf("eventos = %+v", repo.eventos)
+	}
+	if len(notif.usuarios) != 3 || notif.usuarios[2] != 7 || notif.nueva.Tipo != models.NotificacionTipoEleccionEmpate || notif.nueva.Enlace != "/administracion/elecciones/5" {
+		t.Fatalf("notificados = %v (%+v)", notif.usuarios, notif.nueva)
+	}
+}
+
+func TestAvanzarProceso_noPisaUnCambioDeFaseConcurrente(t *testing.T) {
+	ahora := time.Date(2026, 10, 20, 16, 2, 0, 0, utils.AppLocation())
+	inscripcion := ahora.Add(-time.Hour)
+	leido := &models.EleccionProceso{Estado: models.EleccionEstadoBorrador, AutoTransiciones: true, FechaInscripcionInicio: &inscripcion}
+	leido.ID = 5
+	// Un coordinador pasó el proceso a votación después de que el planificador lo leyera.
+	actual := *leido
+	actual.Estado = models.EleccionEstadoVotacion
+	repo := &eleccionTransicionRepoFake{proceso: &actual}
+	s := &eleccionService{repo: repo}
+
+	if err := s.avanzarProceso(leido, ahora); err != nil {
+		t.Fatal(err)
+	}
+	if actual.Estado != models.EleccionEstadoVotacion || leido.Estado != models.EleccionEstadoBorrador || len(repo.eventos) != 0 {
+		t.Fatalf("no debe cambiar la fase: actual %s, leído %s, %d eventos", actual.Estado, leido.Estado, len(repo.eventos))
+	}
+}
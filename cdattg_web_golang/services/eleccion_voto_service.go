@@ -2,12 +2,20 @@ package services
 
 import (
 	"errors"
+	"log"
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
 	"gorm.io/gorm"
 )
 
+// RegistrarVoto guarda por separado la participación (quién votó) y la papeleta anónima (por qué plancha).
+// El código de recibo se devuelve una sola vez; en BD solo queda su hash en la papeleta. El voto se guarda con la
+// fila del proceso bloqueada y la fase se vuelve a comprobar ahí: el cierre bloquea la misma fila, así que un voto
+// o entra en el conteo o ve el proceso ya cerrado. Confirmado el voto, las papeletas se reescriben en otra
+// transacción para que la nueva no comparta xmin con su participación.
 func (s *eleccionService) RegistrarVoto(userID uint, personaID *uint, procesoID uint, req dto.EleccionVotoRequest) (*dto.EleccionVotoResponse, error) {
 	if personaID == nil {
 		return nil, errEleccionUsuarioSinPersona
@@ -19,11 +27,15 @@ func (s *eleccionService) RegistrarVoto(userID uint, personaID *uint, procesoID
 	if p.Estado != models.EleccionEstadoVotacion {
 		return nil, errEleccionFaseInvalida
 	}
-	aprendiz, err := s.aprendizActivoEnRegional(*personaID, p.RegionalID)
+	ahora := utils.Now()
+	if err := ventanaVotacion(p, ahora); err != nil {
+		return nil, err
+	}
+	aprendiz, err := s.aprendizActivoEnAmbito(*personaID, p)
 	if err != nil {
 		return nil, err
 	}
-	if _, errV := s.repo.FindVotoByProcesoAndUser(p.ID, userID); errV == nil {
+	if _, errV := s.repo.FindParticipacion(p.ID, userID); errV == nil {
 		return nil, errEleccionVotoYaRegistrado
 	} else if !errors.Is(errV, gorm.ErrRecordNotFound) {
 		return nil, errV
@@ -32,21 +44,62 @@ func (s *eleccionService) RegistrarVoto(userID uint, personaID *uint, procesoID
 	if err != nil || plancha.ProcesoID != p.ID || plancha.Estado != models.PlanchaEstadoConfirmada {
 		return nil, errors.New("plancha no válida para votar")
 	}
-	voto := &models.EleccionVoto{
+	codigo, err := generarCodigoRecibo()
+	if err != nil {
+		return nil, err
+	}
+	participacion := &models.EleccionParticipacion{
 		ProcesoID:         p.ID,
-		PlanchaID:         plancha.ID,
 		VotanteUserID:     userID,
 		VotanteAprendizID: aprendiz.ID,
+		VotadoAt:          ahora,
 	}
-	if err := s.repo.CreateVoto(voto); err != nil {
+	papeleta := &models.EleccionPapeleta{
+		ReciboHash: hashCodigoRecibo(p.ID, codigo),
+		ProcesoID:  p.ID,
+		PlanchaID:  plancha.ID,
+	}
+	err = s.repo.EnProcesoBloqueado(p.ID, func(repo repositories.EleccionRepository, bloqueado *models.EleccionProceso) error {
+		if bloqueado.Estado != models.EleccionEstadoVotacion {
+			return errEleccionFaseInvalida
+		}
+		if err := ventanaVotacion(bloqueado, utils.Now()); err != nil {
+			return err
+		}
+		return repo.RegistrarVotoSecreto(participacion, papeleta)
+	})
+	if err != nil {
 		return nil, err
 	}
-	nombre := nombreAprendiz(aprendiz)
-	v, _ := s.repo.FindVotoByProcesoAndUser(p.ID, userID)
+	if err := s.repo.MezclarPapeletas(p.ID); err != nil {
+		// El voto ya quedó guardado; la siguiente mezcla del proceso también reescribe esta papeleta.
+		log.Printf("[eleccion] mezclando papeletas del proceso %d: %v", p.ID, err)
+	}
 	return &dto.EleccionVotoResponse{
 		ProcesoID:     p.ID,
 		PlanchaID:     plancha.ID,
-		VotanteNombre: nombre,
-		UpdatedAt:     v.UpdatedAt,
+		VotanteNombre: nombreAprendiz(aprendiz),
+		CodigoRecibo:  codigo,
+		VotadoAt:      participacion.VotadoAt,
 	}, nil
 }
+
+// VerificarRecibo permite al votante comprobar que su papeleta está en la urna. No dice por qué plancha:
+// el recibo no debe servir para demostrar a un tercero el sentido del voto.
+func (s *eleccionService) VerificarRecibo(procesoID uint, codigo string) (*dto.EleccionReciboResponse, error) {
+	if normalizarCodigoRecibo(codigo) == "" {
+		return nil, errEleccionReciboInvalido
+	}
+	p, err := s.repo.FindProcesoByID(procesoID)
+	if err != nil {
+		return nil, errEleccionProcesoNoEncontrado
+	}
+	hash := hashCodigoRecibo(p.ID, codigo)
+	if _, err := s.repo.FindPapeletaByRecibo(p.ID, hash); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &dto.EleccionReciboResponse{ProcesoID: p.ID, ReciboHash: hash, Valido: false}, nil
+		}
+		return nil, err
+	}
+	return &dto.EleccionReciboResponse{ProcesoID: p.ID, ReciboHash: hash, Valido: true}, nil
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+const (
+	// diasAlertaCierreEvaluacion antelación con la que se avisa de fichas por finalizar con RAPs pendientes.
+	diasAlertaCierreEvaluacion = 30
+	maxJuiciosPorLote          = 500
+)
+
+var (
+	ErrEvaluacionFichaNoEncontrada = errors.New("ficha no encontrada")
+	ErrEvaluacionSinPermiso        = errors.New("no tiene permiso para evaluar aprendices de esta ficha")
+	errEvaluacionDemasiadosJuicios = fmt.Errorf("se pueden registrar máximo %d juicios por solicitud", maxJuiciosPorLote)
+	errEvaluacionJuicioDuplicado   = errors.New("la solicitud repite el mismo aprendiz y RAP")
+	errEvaluacionJuicioInvalido    = errors.New("juicio inválido: use APROBADO, NO APROBADO o PENDIENTE")
+	errEvaluacionAprendizAjeno     = errors.New("el aprendiz no es un aprendiz activo de la ficha")
+	errEvaluacionRapAjeno          = errors.New("el RAP no pertenece al programa de la ficha")
+	errEvaluacionSinObservacion    = errors.New("indique observaciones para el juicio NO APROBADO")
+)
+
+// EvaluacionService juicios evaluativos por RAP del programa de la ficha y matriz de evaluación.
+type EvaluacionService interface {
+	GetMatrizFicha(personaID *uint, roles []string, fichaID uint) (*dto.EvaluacionFichaResponse, error)
+	RegistrarJuicios(actor dto.Actor, personaID *uint, roles []string, fichaID uint, req dto.JuiciosRegistroRequest) (*dto.EvaluacionFichaResponse, error)
+	// ListAlertasCierre fichas que finalizan en los próximos días con RAPs sin juicio APROBADO / NO APROBADO.
+	ListAlertasCierre(personaID *uint, roles []string) ([]dto.EvaluacionAlertaItem, error)
+}
+
+type evaluacionService struct {
+	repo          repositories.EvaluacionRepository
+	fichaRepo     repositories.FichaRepository
+	aprendizRepo  repositories.AprendizRepository
+	instRepo      repositories.InstructorRepository
+	instFichaRepo repositories.InstructorFichaRepository
+}
+
+func NewEvaluacionService() EvaluacionService {
+	return &evaluacionService{
+		repo:          repositories.NewEvaluacionRepository(),
+		fichaRepo:     repositories.NewFichaRepository(),
+		aprendizRepo:  repositories.NewAprendizRepository(),
+		instRepo:      repositories.NewInstructorRepository(),
+		instFichaRepo: repositories.NewInstructorFichaRepository(),
+	}
+}
+
+// alcanceEvaluacion fichas que el usuario puede evaluar; instructorID solo para instructores.
+type alcanceEvaluacion struct {
+	todas        bool
+	fichaIDs     []uint
+	instructorID *uint
+}
+
+func (a *alcanceEvaluacion) incluye(ficha *models.FichaCaracterizacion) bool {
+	if a.todas {
+		return true
+	}
+	if a.instructorID != nil && ficha.InstructorID != nil && *ficha.InstructorID == *a.instructorID {
+		return true
+	}
+	for _, id := range a.fichaIDs {
+		if id == ficha.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// alcance super admin, administrador y coordinador ven todas las fichas; un instructor, las que lidera o tiene asignadas.
+func (s *evaluacionService) alcance(personaID *uint, roles []string) (*alcanceEvaluacion, error) {
+	if hasRole(roles, "SUPER ADMINISTRADOR") || hasRole(roles, "ADMINISTRADOR") || hasRole(roles, "COORDINADOR") {
+		return &alcanceEvaluacion{todas: true}, nil
+	}
+	if personaID == nil {
+		return nil, ErrEvaluacionSinPermiso
+	}
+	inst, err := s.instRepo.FindByPersonaID(*personaID)
+	if err != nil || inst == nil {
+		return nil, ErrEvaluacionSinPermiso
+	}
+	asignaciones, err := s.instFichaRepo.FindByInstructorID(inst.ID)
+	if err != nil {
+		return nil, err
+	}
+	alc := &alcanceEvaluacion{instructorID: &inst.ID, fichaIDs: make([]uint, 0, len(asignaciones))}
+	for _, a := range asignaciones {
+		alc.fichaIDs = append(alc.fichaIDs, a.FichaID)
+	}
+	return alc, nil
+}
+
+func (s *evaluacionService) fichaEnAlcance(personaID *uint, roles []string, fichaID uint) (*models.FichaCaracterizacion, *alcanceEvaluacion, error) {
+	ficha, err := s.fichaRepo.FindByID(fichaID)
+	if err != nil {
+		return nil, nil, ErrEvaluacionFichaNoEncontrada
+	}
+	alc, err := s.alcance(personaID, roles)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !alc.incluye(ficha) {
+		return nil, nil, ErrEvaluacionSinPermiso
+	}
+	return ficha, alc, nil
+}
+
+func (s *evaluacionService) aprendicesActivos(fichaID uint) ([]models.Aprendiz, error) {
+	list, err := s.aprendizRepo.FindByFichaID(fichaID)
+	if err != nil {
+		return nil, err
+	}
+	out := list[:0]
+	for _, a := range list {
+		if a.Estado {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *evaluacionService) GetMatrizFicha(personaID *uint, roles []string, fichaID uint) (*dto.EvaluacionFichaResponse, error) {
+	ficha, _, err := s.fichaEnAlcance(personaID, roles, fichaID)
+	if err != nil {
+		return nil, err
+	}
+	return s.matriz(ficha)
+}
+
+func (s *evaluacionService) matriz(ficha *models.FichaCaracterizacion) (*dto.EvaluacionFichaResponse, error) {
+	raps, err := s.repo.ListRapsPrograma(ficha.ProgramaFormacionID)
+	if err != nil {
+		return nil, err
+	}
+	aprendices, err := s.aprendicesActivos(ficha.ID)
+	if err != nil {
+		return nil, err
+	}
+	juicios, err := s.repo.ListJuiciosFicha(ficha.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := construirMatrizEvaluacion(raps, aprendices, juicios)
+	resp.FichaID = ficha.ID
+	resp.FichaNumero = ficha.Ficha
+	if ficha.ProgramaFormacion != nil {
+		resp.ProgramaNombre = ficha.ProgramaFormacion.Nombre
+	}
+	if ficha.FechaFin != nil {
+		resp.FechaFin = ficha.FechaFin
+		dias := diasHastaFecha(*ficha.FechaFin, utils.Now())
+		resp.DiasParaFin = &dias
+		resp.AlertaCierre = alertaCierreEvaluacion(dias, resp.Resumen.Pendientes)
+	}
+	return resp, nil
+}
+
+// construirMatrizEvaluacion agrupa los RAPs por competencia y arma una fila por aprendiz con un juicio por RAP
+// (PENDIENTE cuando no hay registro). Un RAP compartido por dos competencias del programa cuenta una sola vez.
+func construirMatrizEvaluacion(raps []repositories.RapProgramaRow, aprendices []models.Aprendiz, juicios []models.JuicioEvaluativo) *dto.EvaluacionFichaResponse {
+	resp := &dto.EvaluacionFichaResponse{
+		Competencias: make([]dto.EvaluacionCompetenciaItem, 0),
+		Aprendices:   make([]dto.EvaluacionAprendizRow, 0, len(aprendices)),
+	}
+	compIdx := make(map[uint]int)
+	rapIDs := make([]uint, 0, len(raps))
+	rapVisto := make(map[uint]struct{}, len(raps))
+	for _, r := range raps {
+		i, ok := compIdx[r.CompetenciaID]
+		if !ok {
+			i = len(resp.Competencias)
+			compIdx[r.CompetenciaID] = i
+			resp.Competencias = append(resp.Competencias, dto.EvaluacionCompetenciaItem{
+				ID: r.CompetenciaID, Codigo: r.CompetenciaCodigo, Nombre: r.CompetenciaNombre,
+				Raps: make([]dto.EvaluacionRapItem, 0),
+			})
+		}
+		resp.Competencias[i].Raps = append(resp.Competencias[i].Raps, dto.EvaluacionRapItem{ID: r.RapID, Codigo: r.RapCodigo, Nombre: r.RapNombre})
+		if _, ok := rapVisto[r.RapID]; !ok {
+			rapVisto[r.RapID] = struct{}{}
+			rapIDs = append(rapIDs, r.RapID)
+		}
+	}
+
+	type clave struct{ aprendizID, rapID uint }
+	porClave := make(map[clave]*models.JuicioEvaluativo, len(juicios))
+	for i := range juicios {
+		porClave[clave{juicios[i].AprendizID, juicios[i].RapID}] = &juicios[i]
+	}
+
+	resp.Resumen.TotalRaps = len(rapIDs)
+	resp.Resumen.TotalAprendices = len(aprendices)
+	for _, a := range aprendices {
+		row := dto.EvaluacionAprendizRow{AprendizID: a.ID, Juicios: make([]dto.JuicioItem, 0, len(rapIDs))}
+		if a.Persona != nil {
+			row.AprendizNombre = a.Persona.GetFullName()
+			row.NumeroDocumento = a.Persona.NumeroDocumento
+		}
+		for _, rapID := range rapIDs {
+			item := dto.JuicioItem{RapID: rapID, Juicio: models.JuicioPendiente}
+			if j, ok := porClave[clave{a.ID, rapID}]; ok {
+				item.Juicio = j.Juicio
+				item.Observaciones = j.Observaciones
+				fecha := j.FechaJuicio
+				item.FechaJuicio = &fecha
+			}
+			switch item.Juicio {
+			case models.JuicioAprobado:
+				row.Aprobados++
+			case models.JuicioNoAprobado:
+				row.NoAprobados++
+			default:
+				row.Pendientes++
+			}
+			row.Juicios = append(row.Juicios, item)
+		}
+		resp.Resumen.Aprobados += row.Aprobados
+		resp.Resumen.NoAprobados += row.NoAprobados
+		resp.Resumen.Pendientes += row.Pendientes
+		resp.Aprendices = append(resp.Aprendices, row)
+	}
+	return resp
+}
+
+// diasHastaFecha días calendario desde hoy hasta fecha (negativo si ya pasó).
+func diasHastaFecha(fecha, hoy time.Time) int {
+	loc := hoy.Location()
+	f := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, loc)
+	h := time.Date(hoy.Year(), hoy.Month(), hoy.Day(), 0, 0, 0, 0, loc)
+	return int(math.Round(f.Sub(h).Hours() / 24))
+}
+
+func alertaCierreEvaluacion(diasParaFin, pendientes int) bool {
+	return pendientes > 0 && diasParaFin >= 0 && diasParaFin <= diasAlertaCierreEvaluacion
+}
+
+func normalizarJuicio(v string) (string, error) {
+	j := strings.ToUpper(strings.Join(strings.Fields(v), " "))
+	switch j {
+	case models.JuicioAprobado, models.JuicioNoAprobado, models.JuicioPendiente:
+		return j, nil
+	}
+	return "", errEvaluacionJuicioInvalido
+}
+
+// validarJuicios comprueba el lote contra los aprendices activos y los RAPs del programa.
+// Devuelve los juicios listos para guardar (sin campos de auditoría).
+func validarJuicios(items []dto.JuicioRegistroItem, aprendices []models.Aprendiz, raps []repositories.RapProgramaRow) ([]models.JuicioEvaluativo, error) {
+	if len(items) > maxJuiciosPorLote {
+		return nil, errEvaluacionDemasiadosJuicios
+	}
+	aprendizOK := make(map[uint]struct{}, len(aprendices))
+	for _, a := range aprendices {
+		aprendizOK[a.ID] = struct{}{}
+	}
+	competenciaDeRap := make(map[uint]uint, len(raps))
+	for _, r := range raps {
+		if _, ok := competenciaDeRap[r.RapID]; !ok {
+			competenciaDeRap[r.RapID] = r.CompetenciaID
+		}
+	}
+	type clave struct{ aprendizID, rapID uint }
+	vistos := make(map[clave]struct{}, len(items))
+	out := make([]models.JuicioEvaluativo, 0, len(items))
+	for _, it := range items {
+		k := clave{it.AprendizID, it.RapID}
+		if _, ok := vistos[k]; ok {
+			return nil, errEvaluacionJuicioDuplicado
+		}
+		vistos[k] = struct{}{}
+		if _, ok := aprendizOK[it.AprendizID]; !ok {
+			return nil, fmt.Errorf("%w (aprendiz %d)", errEvaluacionAprendizAjeno, it.AprendizID)
+		}
+		compID, ok := competenciaDeRap[it.RapID]
+		if !ok {
+			return nil, fmt.Errorf("%w (RAP %d)", errEvaluacionRapAjeno, it.RapID)
+		}
+		juicio, err := normalizarJuicio(it.Juicio)
+		if err != nil {
+			return nil, err
+		}
+		obs := strings.TrimSpace(it.Observaciones)
+		if juicio == models.JuicioNoAprobado && obs == "" {
+			return nil, errEvaluacionSinObservacion
+		}
+		out = append(out, models.JuicioEvaluativo{
+			AprendizID:    it.AprendizID,
+			RapID:         it.RapID,
+			CompetenciaID: compID,
+			Juicio:        juicio,
+			Observaciones: obs,
+		})
+	}
+	return out, nil
+}
+
+func (s *evaluacionService) RegistrarJuicios(actor dto.Actor, personaID *uint, roles []string, fichaID uint, req dto.JuiciosRegistroRequest) (*dto.EvaluacionFichaResponse, error) {
+	ficha, alc, err := s.fichaEnAlcance(personaID, roles, fichaID)
+	if err != nil {
+		return nil, err
+	}
+	raps, err := s.repo.ListRapsPrograma(ficha.ProgramaFormacionID)
+	if err != nil {
+		return nil, err
+	}
+	aprendices, err := s.aprendicesActivos(ficha.ID)
+	if err != nil {
+		return nil, err
+	}
+	nuevos, err := validarJuicios(req.Juicios, aprendices, raps)
+	if err != nil {
+		return nil, err
+	}
+	antes, err := s.repo.ListJuiciosFicha(ficha.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	instructorID := alc.instructorID
+	if instructorID == nil && personaID != nil {
+		if inst, err := s.instRepo.FindByPersonaID(*personaID); err == nil && inst != nil {
+			instructorID = &inst.ID
+		}
+	}
+	ahora := time.Now()
+	for i := range nuevos {
+		nuevos[i].FichaID = ficha.ID
+		nuevos[i].InstructorID = instructorID
+		nuevos[i].FechaJuicio = ahora
+		nuevos[i].UserCreateID = &actor.UserID
+		nuevos[i].UserEditID = &actor.UserID
+	}
+	if err := s.repo.UpsertJuicios(nuevos); err != nil {
+		return nil, err
+	}
+	auditar(actor, AccionJuiciosRegistrar, tablaFichas, ficha.ID,
+		juiciosAuditoria(antes, nuevos), juiciosAuditoria(nuevos, nil))
+	return s.matriz(ficha)
+}
+
+// juiciosAuditoria resume los juicios de list que corresponden a los pares aprendiz/RAP de filtro (todos si filtro es nil).
+func juiciosAuditoria(list, filtro []models.JuicioEvaluativo) []map[string]interface{} {
+	type clave struct{ aprendizID, rapID uint }
+	var incluir map[clave]struct{}
+	if filtro != nil {
+		incluir = make(map[clave]struct{}, len(filtro))
+		for _, j := range filtro {
+			incluir[clave{j.AprendizID, j.RapID}] = struct{}{}
+		}
+	}
+	out := make([]map[string]interface{}, 0, len(list))
+	for _, j := range list {
+		if incluir != nil {
+			if _, ok := incluir[clave{j.AprendizID, j.RapID}]; !ok {
+				continue
+			}
+		}
+		out = append(out, map[string]interface{}{
+			"aprendiz_id":   j.AprendizID,
+			"rap_id":        j.RapID,
+			"juicio":        j.Juicio,
+			"observaciones": j.Observaciones,
+		})
+	}
+	return out
+}
+
+func (s *evaluacionService) ListAlertasCierre(personaID *uint, roles []string) ([]dto.EvaluacionAlertaItem, error) {
+	alc, err := s.alcance(personaID, roles)
+	if err != nil {
+		return nil, err
+	}
+	hoy := utils.Now()
+	desde := time.Date(hoy.Year(), hoy.Month(), hoy.Day(), 0, 0, 0, 0, hoy.Location())
+	hasta := desde.AddDate(0, 0, diasAlertaCierreEvaluacion+1)
+	rows, err := s.repo.ListFichasPorFinalizar(desde, hasta, alc.instructorID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.EvaluacionAlertaItem, 0, len(rows))
+	for _, r := range rows {
+		pendientes := r.Aprendices*r.Raps - r.Juzgados
+		if pendientes <= 0 {
+			continue
+		}
+		out = append(out, dto.EvaluacionAlertaItem{
+			FichaID:        r.FichaID,
+			FichaNumero:    r.FichaNumero,
+			ProgramaNombre: r.ProgramaNombre,
+			FechaFin:       r.FechaFin,
+			DiasParaFin:    diasHastaFecha(r.FechaFin, hoy),
+			Aprendices:     r.Aprendices,
+			Raps:           r.Raps,
+			Pendientes:     pendientes,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+func rapsPrograma() []repositories.RapProgramaRow {
+	return []repositories.RapProgramaRow{
+		{CompetenciaID: 1, CompetenciaCodigo: "C1", RapID: 10, RapCodigo: "R10"},
+		{CompetenciaID: 1, CompetenciaCodigo: "C1", RapID: 11, RapCodigo: "R11"},
+		{CompetenciaID: 2, CompetenciaCodigo: "C2", RapID: 11, RapCodigo: "R11"},
+		{CompetenciaID: 2, CompetenciaCodigo: "C2", RapID: 20, RapCodigo: "R20"},
+	}
+}
+
+func aprendicesEvaluacion(ids ...uint) []models.Aprendiz {
+	out := make([]models.Aprendiz, len(ids))
+	for i, id := range ids {
+		out[i].ID = id
+		out[i].Persona = &models.Persona{PrimerNombre: "Ana", PrimerApellido: "Ruiz"}
+	}
+	return out
+}
+
+func TestConstruirMatrizEvaluacion(t *testing.T) {
+	t.Parallel()
+	juicios := []models.JuicioEvaluativo{
+		{AprendizID: 1, RapID: 10, Juicio: models.JuicioAprobado},
+		{AprendizID: 1, RapID: 11, Juicio: models.JuicioNoAprobado, Observaciones: "falta evidencia"},
+		{AprendizID: 2, RapID: 20, Juicio: models.JuicioPendiente},
+	}
+	m := construirMatrizEvaluacion(rapsPrograma(), aprendicesEvaluacion(1, 2), juicios)
+
+	if len(m.Competencias) != 2 || len(m.Competencias[0].Raps) != 2 || len(m.Competencias[1].Raps) != 2 {
+		t.Fatalf("competencias = %+v", m.Competencias)
+	}
+	if m.Resumen.TotalRaps != 3 {
+		t.Errorf("TotalRaps = %d, want 3 (RAP compartido cuenta una vez)", m.Resumen.TotalRaps)
+	}
+	a1 := m.Aprendices[0]
+	if len(a1.Juicios) != 3 || a1.Aprobados != 1 || a1.NoAprobados != 1 || a1.Pendientes != 1 {
+		t.Errorf("aprendiz 1 = %+v", a1)
+	}
+	if a1.Juicios[1].Observaciones != "falta evidencia" || a1.Juicios[1].FechaJuicio == nil {
+		t.Errorf("juicio RAP 11 = %+v", a1.Juicios[1])
+	}
+	if a2 := m.Aprendices[1]; a2.Pendientes != 3 {
+		t.Errorf("aprendiz 2 pendientes = %d, want 3", a2.Pendientes)
+	}
+	want := dto.EvaluacionResumen{TotalRaps: 3, TotalAprendices: 2, Aprobados: 1, NoAprobados: 1, Pendientes: 4}
+	if m.Resumen != want {
+		t.Errorf("resumen = %+v, want %+v", m.Resumen, want)
+	}
+}
+
+func TestValidarJuicios(t *testing.T) {
+	t.Parallel()
+	aprendices := aprendicesEvaluacion(1, 2)
+	raps := rapsPrograma()
+	item := func(aprendizID, rapID uint, juicio, obs string) dto.JuicioRegistroItem {
+		return dto.JuicioRegistroItem{AprendizID: aprendizID, RapID: rapID, Juicio: juicio, Observaciones: obs}
+	}
+
+	got, err := validarJuicios([]dto.JuicioRegistroItem{
+		item(1, 10, "aprobado", ""),
+		item(2, 20, " no  aprobado ", " incompleto "),
+	}, aprendices, raps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Juicio != models.JuicioAprobado || got[0].CompetenciaID != 1 {
+		t.Errorf("got[0] = %+v", got[0])
+	}
+	if got[1].Juicio != models.JuicioNoAprobado || got[1].Observaciones != "incompleto" || got[1].CompetenciaID != 2 {
+		t.Errorf("got[1] = %+v", got[1])
+	}
+
+	cases := []struct {
+		name  string
+		items []dto.JuicioRegistroItem
+		want  error
+	}{
+		{"aprendiz_ajeno", []dto.JuicioRegistroItem{item(9, 10, models.JuicioAprobado, "")}, errEvaluacionAprendizAjeno},
+		{"rap_ajeno", []dto.JuicioRegistroItem{item(1, 99, models.JuicioAprobado, "")}, errEvaluacionRapAjeno},
+		{"juicio_invalido", []dto.JuicioRegistroItem{item(1, 10, "EXCELENTE", "")}, errEvaluacionJuicioInvalido},
+		{"no_aprobado_sin_obs", []dto.JuicioRegistroItem{item(1, 10, models.JuicioNoAprobado, " ")}, errEvaluacionSinObservacion},
+		{"duplicado", []dto.JuicioRegistroItem{item(1, 10, models.JuicioAprobado, ""), item(1, 10, models.JuicioPendiente, "")}, errEvaluacionJuicioDuplicado},
+		{"lote_grande", make([]dto.JuicioRegistroItem, maxJuiciosPorLote+1), errEvaluacionDemasiadosJuicios},
+	}
+	for _, tc := range cases {
+		if _, err := validarJuicios(tc.items, aprendices, raps); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestAlertaCierreEvaluacion(t *testing.T) {
+	t.Parallel()
+	hoy := time.Date(2026, 5, 1, 18, 30, 0, 0, time.Local)
+	if d := diasHastaFecha(time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC), hoy); d != 30 {
+		t.Errorf("diasHastaFecha = %d, want 30", d)
+	}
+	if d := diasHastaFecha(time.Date(2026, 4, 30, 0, 0, 0, 0, time.Local), hoy); d != -1 {
+		t.Errorf("diasHastaFecha pasada = %d, want -1", d)
+	}
+	cases := []struct {
+		dias, pendientes int
+		want             bool
+	}{
+		{10, 3, true},
+		{0, 1, true},
+		{diasAlertaCierreEvaluacion, 1, true},
+		{diasAlertaCierreEvaluacion + 1, 1, false},
+		{10, 0, false},
+		{-2, 5, false},
+	}
+	for _, tc := range cases {
+		if got := alertaCierreEvaluacion(tc.dias, tc.pendientes); got != tc.want {
+			t.Errorf("alertaCierreEvaluacion(%d, %d) = %v, want %v", tc.dias, tc.pendientes, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+const (
+	excusaArchivoDir        = "storage/excusas"
+	maxTamanoArchivoExcusa  = 5 * 1024 * 1024
+	maxSesionesPorExcusa    = 10
+	maxMotivoExcusa         = 500
+	diasPlazoPresentaExcusa = 5
+)
+
+// Tipos de soporte admitidos: extensión -> tipo de contenido detectado.
+var tiposArchivoExcusa = map[string]string{
+	".pdf":  "application/pdf",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+}
+
+var (
+	ErrExcusaNoEncontrada         = errors.New("excusa no encontrada")
+	errExcusaSinSesiones          = errors.New("debe indicar al menos una sesión")
+	errExcusaDemasiadasSesiones   = fmt.Errorf("una excusa puede cubrir máximo %d sesiones", maxSesionesPorExcusa)
+	errExcusaMotivoRequerido      = errors.New("el motivo es obligatorio")
+	errExcusaMotivoLargo          = fmt.Errorf("el motivo no debe superar %d caracteres", maxMotivoExcusa)
+	errExcusaArchivoRequerido     = errors.New("debe adjuntar el soporte de la excusa")
+	errExcusaArchivoGrande        = errors.New("el soporte no debe superar 5 MB")
+	errExcusaArchivoTipo          = errors.New("el soporte debe ser PDF, JPG o PNG")
+	errExcusaSesionAjena          = errors.New("la sesión no pertenece a su ficha")
+	errExcusaSesionFutura         = errors.New("no se puede presentar excusa para una sesión futura")
+	errExcusaFueraDePlazo         = fmt.Errorf("la excusa solo se puede presentar hasta %d días después de la sesión", diasPlazoPresentaExcusa)
+	errExcusaAsistio              = errors.New("registró asistencia en la sesión; no requiere excusa")
+	errExcusaSesionYaCubierta     = errors.New("ya presentó una excusa para alguna de las sesiones")
+	errExcusaYaRevisada           = errors.New("la excusa ya fue revisada")
+	errExcusaObservacionRechazo   = errors.New("indique el motivo del rechazo")
+	ErrExcusaSinPermisoRevision   = errors.New("no tiene permiso para revisar excusas de esta ficha")
+	ErrExcusaSinPermisoArchivo    = errors.New("no tiene permiso para ver el soporte de esta excusa")
+	ErrExcusaAprendizNoEncontrado = errors.New(errMsgAprendizActivoNoEncontrado)
+)
+
+// ExcusaService excusas de inasistencia: el aprendiz las presenta y el instructor de la ficha o bienestar las revisa.
+// Las aprobadas cuentan como inasistencia justificada en casos de bienestar, mis inasistencias y reportes.
+type ExcusaService interface {
+	Presentar(personaID uint, req dto.ExcusaCreateRequest, archivo []byte, nombreArchivo string) (*dto.ExcusaResponse, error)
+	ListMias(personaID uint) ([]dto.ExcusaResponse, error)
+	ListParaRevision(personaID *uint, roles []string, estado string, fichaID *uint) ([]dto.ExcusaResponse, error)
+	Revisar(actor dto.Actor, personaID *uint, roles []string, id uint, req dto.ExcusaRevisionRequest) (*dto.ExcusaResponse, error)
+	// Archivo ruta en disco y nombre original del soporte, si el usuario puede verlo.
+	Archivo(personaID *uint, roles []string, id uint) (ruta, nombre string, err error)
+}
+
+type excusaService struct {
+	repo          repositories.ExcusaRepository
+	asistRepo     repositories.AsistenciaRepository
+	aprendizRepo  repositories.AprendizRepository
+	instRepo      repositories.InstructorRepository
+	instFichaRepo repositories.InstructorFichaRepository
+}
+
+func NewExcusaService() ExcusaService {
+	return &excusaService{
+		repo:          repositories.NewExcusaRepository(),
+		asistRepo:     repositories.NewAsistenciaRepository(),
+		aprendizRepo:  repositories.NewAprendizRepository(),
+		instRepo:      repositories.NewInstructorRepository(),
+		instFichaRepo: repositories.NewInstructorFichaRepository(),
+	}
+}
+
+// validarArchivoExcusa comprueba tamaño, extensión y que el contenido corresponda a la extensión.
+// Devuelve la extensión normalizada y el tipo de contenido.
+func validarArchivoExcusa(contenido []byte, nombre string) (string, string, error) {
+	if len(contenido) == 0 {
+		return "", "", errExcusaArchivoRequerido
+	}
+	if len(contenido) > maxTamanoArchivoExcusa {
+		return "", "", errExcusaArchivoGrande
+	}
+	ext := strings.ToLower(filepath.Ext(nombre))
+	tipo, ok := tiposArchivoExcusa[ext]
+	if !ok {
+		return "", "", errExcusaArchivoTipo
+	}
+	if !strings.HasPrefix(http.DetectContentType(contenido), tipo) {
+		return "", "", errExcusaArchivoTipo
+	}
+	return ext, tipo, nil
+}
+
+// normalizarSesionesExcusa quita ceros y duplicados conservando el orden.
+func normalizarSesionesExcusa(ids []uint) ([]uint, error) {
+	seen := make(map[uint]struct{}, len(ids))
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	if len(out) == 0 {
+		return nil, errExcusaSinSesiones
+	}
+	if len(out) > maxSesionesPorExcusa {
+		return nil, errExcusaDemasiadasSesiones
+	}
+	return out, nil
+}
+
+func aprendizAsistioEnSesion(asist *models.Asistencia, aprendizID uint) bool {
+	for i := range asist.AsistenciaAprendices {
+		aa := &asist.AsistenciaAprendices[i]
+		if aa.AprendizFichaID != aprendizID || aa.HoraIngreso == nil {
+			continue
+		}
+		switch aa.Estado {
+		case "", "ASISTENCIA_COMPLETA", "ASISTENCIA_PARCIAL":
+			return true
+		}
+	}
+	return false
+}
+
+// validarSesionExcusa la sesión es de la ficha del aprendiz, ya ocurrió, está en plazo y el aprendiz no asistió.
+func validarSesionExcusa(asist *models.Asistencia, aprendiz *models.Aprendiz, ahora time.Time) error {
+	if asist.InstructorFicha == nil || asist.InstructorFicha.FichaID != aprendiz.FichaCaracterizacionID {
+		return errExcusaSesionAjena
+	}
+	if !sesionCerradaDentroDePlazoEdicion(asist.Fecha, ahora, diasPlazoPresentaExcusa) {
+		loc := ahora.Location()
+		if asist.Fecha.In(loc).After(ahora) {
+			return errExcusaSesionFutura
+		}
+		return errExcusaFueraDePlazo
+	}
+	if aprendizAsistioEnSesion(asist, aprendiz.ID) {
+		return errExcusaAsistio
+	}
+	return nil
+}
+
+func (s *excusaService) aprendizActivo(personaID uint) (*models.Aprendiz, error) {
+	if personaID == 0 {
+		return nil, ErrExcusaAprendizNoEncontrado
+	}
+	aprendiz, err := s.aprendizRepo.FindActivoByPersonaID(personaID)
+	if err != nil || aprendiz == nil {
+		return nil, ErrExcusaAprendizNoEncontrado
+	}
+	return aprendiz, nil
+}
+
+func (s *excusaService) Presentar(personaID uint, req dto.ExcusaCreateRequest, archivo []byte, nombreArchivo string) (*dto.ExcusaResponse, error) {
+	aprendiz, err := s.aprendizActivo(personaID)
+	if err != nil {
+		return nil, err
+	}
+	motivo := strings.TrimSpace(req.Motivo)
+	if motivo == "" {
+		return nil, errExcusaMotivoRequerido
+	}
+	if len([]rune(motivo)) > maxMotivoExcusa {
+		return nil, errExcusaMotivoLargo
+	}
+	ids, err := normalizarSesionesExcusa(req.AsistenciaIDs)
+	if err != nil {
+		return nil, err
+	}
+	ext, tipo, err := validarArchivoExcusa(archivo, nombreArchivo)
+	if err != nil {
+		return nil, err
+	}
+
+	ahora := time.Now()
+	for _, id := range ids {
+		asist, errA := s.asistRepo.FindByID(id)
+		if errA != nil {
+			return nil, errors.New(errMsgSesionAsistenciaNoEncontrada)
+		}
+		if err := validarSesionExcusa(asist, aprendiz, ahora); err != nil {
+			return nil, fmt.Errorf("sesión del %s: %w", asist.Fecha.Format(time.DateOnly), err)
+		}
+	}
+	cubiertas, err := s.repo.ListAsistenciaIDsCubiertas(aprendiz.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(cubiertas) > 0 {
+		return nil, errExcusaSesionYaCubierta
+	}
+
+	if err := os.MkdirAll(excusaArchivoDir, 0755); err != nil {
+		return nil, fmt.Errorf("crear directorio de excusas: %w", err)
+	}
+	ruta := filepath.Join(excusaArchivoDir, fmt.Sprintf("excusa_%d_%d%s", aprendiz.ID, ahora.UnixNano(), ext))
+	if err := os.WriteFile(ruta, archivo, 0644); err != nil {
+		return nil, fmt.Errorf("guardar soporte: %w", err)
+	}
+
+	e := &models.ExcusaInasistencia{
+		AprendizID:    aprendiz.ID,
+		FichaID:       aprendiz.FichaCaracterizacionID,
+		Motivo:        motivo,
+		Estado:        models.ExcusaEstadoPendiente,
+		ArchivoNombre: truncateStr(filepath.Base(nombreArchivo), 255),
+		ArchivoRuta:   ruta,
+		ArchivoTipo:   tipo,
+		ArchivoTamano: int64(len(archivo)),
+		Sesiones:      make([]models.ExcusaInasistenciaSesion, len(ids)),
+	}
+	for i, id := range ids {
+		e.Sesiones[i] = models.ExcusaInasistenciaSesion{AsistenciaID: id}
+	}
+	if err := s.repo.Create(e); err != nil {
+		_ = os.Remove(ruta)
+		return nil, err
+	}
+	return s.getResponse(e.ID)
+}
+
+func (s *excusaService) ListMias(personaID uint) ([]dto.ExcusaResponse, error) {
+	aprendiz, err := s.aprendizActivo(personaID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListByAprendiz(aprendiz.ID)
+	if err != nil {
+		return nil, err
+	}
+	return excusasToResponse(list), nil
+}
+
+// alcanceRevisionExcusas fichas cuyas excusas puede revisar el usuario (todas: super admin y bienestar).
+type alcanceRevisionExcusas struct {
+	todas    bool
+	fichaIDs []uint
+}
+
+func (a *alcanceRevisionExcusas) incluye(fichaID uint) bool {
+	if a.todas {
+		return true
+	}
+	for _, id := range a.fichaIDs {
+		if id == fichaID {
+			return true
+		}
+	}
+	return false
+}
+
+// alcanceRevision super admin y bienestar revisan todas las excusas; un instructor, las de sus fichas.
+func (s *excusaService) alcanceRevision(personaID *uint, roles []string) (*alcanceRevisionExcusas, error) {
+	if hasRole(roles, "SUPER ADMINISTRADOR") || hasRole(roles, "BIENESTAR AL APRENDIZ") {
+		return &alcanceRevisionExcusas{todas: true}, nil
+	}
+	if personaID == nil {
+		return nil, ErrExcusaSinPermisoRevision
+	}
+	inst, err := s.instRepo.FindByPersonaID(*personaID)
+	if err != nil || inst == nil {
+		return nil, ErrExcusaSinPermisoRevision
+	}
+	asignaciones, err := s.instFichaRepo.FindByInstructorID(inst.ID)
+	if err != nil {
+		return nil, err
+	}
+	alc := &alcanceRevisionExcusas{fichaIDs: make([]uint, 0, len(asignaciones))}
+	for _, a := range asignaciones {
+		alc.fichaIDs = append(alc.fichaIDs, a.FichaID)
+	}
+	return alc, nil
+}
+
+func (s *excusaService) ListParaRevision(personaID *uint, roles []string, estado string, fichaID *uint) ([]dto.ExcusaResponse, error) {
+	alc, err := s.alcanceRevision(personaID, roles)
+	if err != nil {
+		return nil, err
+	}
+	f := repositories.ExcusaFiltro{Estado: estado, FichaID: fichaID}
+	if !alc.todas {
+		f.FichaIDs = alc.fichaIDs
+	}
+	list, err := s.repo.List(f)
+	if err != nil {
+		return nil, err
+	}
+	return excusasToResponse(list), nil
+}
+
+func (s *excusaService) Revisar(actor dto.Actor, personaID *uint, roles []string, id uint, req dto.ExcusaRevisionRequest) (*dto.ExcusaResponse, error) {
+	e, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrExcusaNoEncontrada
+	}
+	alc, err := s.alcanceRevision(personaID, roles)
+	if err != nil {
+		return nil, err
+	}
+	if !alc.incluye(e.FichaID) {
+		return nil, ErrExcusaSinPermisoRevision
+	}
+	if e.Estado != models.ExcusaEstadoPendiente {
+		return nil, errExcusaYaRevisada
+	}
+	observacion := strings.TrimSpace(req.Observacion)
+	if req.Estado == models.ExcusaEstadoRechazada && observacion == "" {
+		return nil, errExcusaObservacionRechazo
+	}
+	ok, err := s.repo.Revisar(id, req.Estado, actor.UserID, time.Now(), observacion)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errExcusaYaRevisada
+	}
+	despues, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	auditar(actor, AccionExcusaRevisar, tablaExcusasInasistencia, id, e, despues)
+
+	if req.Estado == models.ExcusaEstadoAprobada {
+		for _, ses := range despues.Sesiones {
+			if err := RegenerarReporteSesion(ses.AsistenciaID); err != nil {
+				log.Printf("[excusas] regenerando reporte de la sesión %d: %v", ses.AsistenciaID, err)
+			}
+		}
+	}
+	resp := excusaToResponse(despues)
+	return &resp, nil
+}
+
+func (s *excusaService) Archivo(personaID *uint, roles []string, id uint) (string, string, error) {
+	e, err := s.repo.FindByID(id)
+	if err != nil {
+		return "", "", ErrExcusaNoEncontrada
+	}
+	if personaID != nil && e.Aprendiz != nil && e.Aprendiz.PersonaID == *personaID {
+		return e.ArchivoRuta, e.ArchivoNombre, nil
+	}
+	alc, err := s.alcanceRevision(personaID, roles)
+	if err != nil || !alc.incluye(e.FichaID) {
+		return "", "", ErrExcusaSinPermisoArchivo
+	}
+	return e.ArchivoRuta, e.ArchivoNombre, nil
+}
+
+func (s *excusaService) getResponse(id uint) (*dto.ExcusaResponse, error) {
+	e, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	resp := excusaToResponse(e)
+	return &resp, nil
+}
+
+func excusaToResponse(e *models.ExcusaInasistencia) dto.ExcusaResponse {
+	r := dto.ExcusaResponse{
+		ID:                  e.ID,
+		AprendizID:          e.AprendizID,
+		FichaID:             e.FichaID,
+		Motivo:              e.Motivo,
+		Estado:              e.Estado,
+		ArchivoNombre:       e.ArchivoNombre,
+		ArchivoTipo:         e.ArchivoTipo,
+		ArchivoTamano:       e.ArchivoTamano,
+		CreatedAt:           e.CreatedAt,
+		RevisadoAt:          e.RevisadoAt,
+		ObservacionRevision: e.ObservacionRevision,
+		Sesiones:            make([]dto.ExcusaSesionItem, 0, len(e.Sesiones)),
+	}
+	if e.Aprendiz != nil && e.Aprendiz.Persona != nil {
+		r.AprendizNombre = e.Aprendiz.Persona.GetFullName()
+		r.NumeroDocumento = e.Aprendiz.Persona.NumeroDocumento
+	}
+	if e.Ficha != nil {
+		r.FichaNumero = e.Ficha.Ficha
+	}
+	for _, ses := range e.Sesiones {
+		item := dto.ExcusaSesionItem{AsistenciaID: ses.AsistenciaID}
+		if a := ses.Asistencia; a != nil {
+			item.Fecha = a.Fecha.Format(time.DateOnly)
+			if a.InstructorFicha != nil && a.InstructorFicha.Instructor != nil && a.InstructorFicha.Instructor.Persona != nil {
+				item.InstructorNombre = a.InstructorFicha.Instructor.Persona.GetFullName()
+			}
+		}
+		r.Sesiones = append(r.Sesiones, item)
+	}
+	return r
+}
+
+func excusasToResponse(list []models.ExcusaInasistencia) []dto.ExcusaResponse {
+	out := make([]dto.ExcusaResponse, len(list))
+	for i := range list {
+		out[i] = excusaToResponse(&list[i])
+	}
+	return out
+}
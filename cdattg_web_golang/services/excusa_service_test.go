@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/models"
+)
+
+var pdfMinimo = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+
+func TestValidarArchivoExcusa(t *testing.T) {
+	t.Parallel()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	cases := []struct {
+		name      string
+		contenido []byte
+		nombre    string
+		wantErr   error
+		wantTipo  string
+	}{
+		{"pdf", pdfMinimo, "incapacidad.PDF", nil, "application/pdf"},
+		{"png", png, "soporte.png", nil, "image/png"},
+		{"vacio", nil, "a.pdf", errExcusaArchivoRequerido, ""},
+		{"extension_no_admitida", pdfMinimo, "a.docx", errExcusaArchivoTipo, ""},
+		{"contenido_no_coincide", []byte("hola mundo"), "a.pdf", errExcusaArchivoTipo, ""},
+		{"grande", make([]byte, maxTamanoArchivoExcusa+1), "a.pdf", errExcusaArchivoGrande, ""},
+	}
+	for _, tc := range cases {
+		_, tipo, err := validarArchivoExcusa(tc.contenido, tc.nombre)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if tipo != tc.wantTipo {
+			t.Errorf("%s: tipo = %q, want %q", tc.name, tipo, tc.wantTipo)
+		}
+	}
+}
+
+func TestNormalizarSesionesExcusa(t *testing.T) {
+	t.Parallel()
+	got, err := normalizarSesionesExcusa([]uint{3, 0, 3, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != 3 || got[1] != 1 {
+		t.Errorf("got %v, want [3 1]", got)
+	}
+	if _, err := normalizarSesionesExcusa([]uint{0}); !errors.Is(err, errExcusaSinSesiones) {
+		t.Errorf("sin sesiones: err = %v", err)
+	}
+	muchas := make([]uint, maxSesionesPorExcusa+1)
+	for i := range muchas {
+		muchas[i] = uint(i + 1)
+	}
+	if _, err := normalizarSesionesExcusa(muchas); !errors.Is(err, errExcusaDemasiadasSesiones) {
+		t.Errorf("demasiadas: err = %v", err)
+	}
+}
+
+func TestValidarSesionExcusa(t *testing.T) {
+	t.Parallel()
+	ahora := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	aprendiz := &models.Aprendiz{FichaCaracterizacionID: 7}
+	aprendiz.ID = 42
+	ingreso := ahora.Add(-30 * time.Hour)
+	sesion := func(fichaID uint, fecha time.Time, registros ...models.AsistenciaAprendiz) *models.Asistencia {
+		return &models.Asistencia{
+			Fecha:                fecha,
+			InstructorFicha:      &models.InstructorFichaCaracterizacion{FichaID: fichaID},
+			AsistenciaAprendices: registros,
+		}
+	}
+	ayer := time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)
+	cases := []struct {
+		name  string
+		asist *models.Asistencia
+		want  error
+	}{
+		{"valida", sesion(7, ayer), nil},
+		{"otra_ficha", sesion(8, ayer), errExcusaSesionAjena},
+		{"futura", sesion(7, ayer.AddDate(0, 0, 3)), errExcusaSesionFutura},
+		{"fuera_de_plazo", sesion(7, ayer.AddDate(0, 0, -10)), errExcusaFueraDePlazo},
+		{"asistio", sesion(7, ayer, models.AsistenciaAprendiz{AprendizFichaID: 42, HoraIngreso: &ingreso}), errExcusaAsistio},
+		{"abandono_admite_excusa", sesion(7, ayer, models.AsistenciaAprendiz{AprendizFichaID: 42, HoraIngreso: &ingreso, Estado: "ABANDONO_JORNADA"}), nil},
+	}
+	for _, tc := range cases {
+		if err := validarSesionExcusa(tc.asist, aprendiz, ahora); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"bytes"
-	"encoding/base64"
 	"fmt"
 	"strings"
 
@@ -25,41 +24,28 @@ const (
 	fichaColCorreo    = 5
 )
 
-type FichaImportService interface {
-	ImportFromExcel(fileBytes []byte, filename string) (*FichaImportResult, error)
-}
-
 type fichaImportService struct {
-	personaRepo  repositories.PersonaRepository
-	programaRepo repositories.ProgramaFormacionRepository
-	fichaRepo    repositories.FichaRepository
-	aprendizRepo repositories.AprendizRepository
-	catalogoRepo repositories.CatalogoRepository
-	personaSvc   PersonaService
+	personaRepo    repositories.PersonaRepository
+	programaRepo   repositories.ProgramaFormacionRepository
+	fichaRepo      repositories.FichaRepository
+	aprendizRepo   repositories.AprendizRepository
+	catalogoRepo   repositories.CatalogoRepository
+	personaSvc     PersonaService
+	notificaciones NotificacionUsuarioService
 }
 
-func NewFichaImportService() FichaImportService {
+func newFichaImportService(notificaciones NotificacionUsuarioService) *fichaImportService {
 	return &fichaImportService{
-		personaRepo:  repositories.NewPersonaRepository(),
-		programaRepo: repositories.NewProgramaFormacionRepository(),
-		fichaRepo:    repositories.NewFichaRepository(),
-		aprendizRepo: repositories.NewAprendizRepository(),
-		catalogoRepo: repositories.NewCatalogoRepository(),
-		personaSvc:   NewPersonaService(),
+		personaRepo:    repositories.NewPersonaRepository(),
+		programaRepo:   repositories.NewProgramaFormacionRepository(),
+		fichaRepo:      repositories.NewFichaRepository(),
+		aprendizRepo:   repositories.NewAprendizRepository(),
+		catalogoRepo:   repositories.NewCatalogoRepository(),
+		personaSvc:     NewPersonaService(),
+		notificaciones: notificaciones,
 	}
 }
 
-type FichaImportResult struct {
-	ProcessedCount       int    `json:"processed_count"`
-	UpdatedCount         int    `json:"updated_count"`
-	CreatedCount         int    `json:"created_count"`
-	DuplicatesCount      int    `json:"duplicates_count"`
-	ErrorCount           int    `json:"error_count"`
-	FichaCreated         bool   `json:"ficha_created"`
-	Status               string `json:"status"`
-	IncidentReportBase64 string `json:"incident_report_base64,omitempty"`
-}
-
 // readExcelRows devuelve todas las filas de la primera hoja. Soporta .xlsx (excelize) y .xls (extrame/xls).
 func (s *fichaImportService) readExcelRows(fileBytes []byte, filename string) ([][]string, error) {
 	lower := strings.ToLower(filename)
@@ -181,69 +167,8 @@ func (s *fichaImportService) getOrCreateFicha(fichaCode string, programa *models
 	return ficha, false, nil
 }
 
-// fichaImportIncident una fila del reporte de incidencias (evita funciones con demasiados parámetros).
-type fichaImportIncident struct {
-	Tipo            string
-	NumeroDocumento string
-	Nombre          string
-	Apellidos       string
-	CorreoOriginal  string
-	CelularOriginal string
-	FichaOrigen     string
-	FichaDestino    string
-	Detalle         string
-}
-
-type fichaImportIncidentWriter struct {
-	file    *excelize.File
-	sheet   string
-	nextRow int
-	has     bool
-}
-
-func newFichaImportIncidentWriter() *fichaImportIncidentWriter {
-	f := excelize.NewFile()
-	sheet := "Incidencias"
-	defaultSheet := f.GetSheetName(0)
-	if defaultSheet != "" {
-		_ = f.SetSheetName(defaultSheet, sheet)
-	}
-	headers := []string{"tipo_incidente", "numero_documento", "nombre", "apellidos", "correo_original", "celular_original", "ficha_origen", "ficha_destino", "detalle"}
-	for i, h := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		_ = f.SetCellValue(sheet, cell, h)
-	}
-	return &fichaImportIncidentWriter{file: f, sheet: sheet, nextRow: 2}
-}
-
-func (w *fichaImportIncidentWriter) add(inc fichaImportIncident) {
-	w.has = true
-	values := []string{
-		inc.Tipo, inc.NumeroDocumento, inc.Nombre, inc.Apellidos,
-		inc.CorreoOriginal, inc.CelularOriginal, inc.FichaOrigen, inc.FichaDestino, inc.Detalle,
-	}
-	for i, v := range values {
-		cell, _ := excelize.CoordinatesToCellName(i+1, w.nextRow)
-		_ = w.file.SetCellValue(w.sheet, cell, v)
-	}
-	w.nextRow++
-}
-
-func (w *fichaImportIncidentWriter) toBase64() string {
-	if !w.has {
-		return ""
-	}
-	var buf bytes.Buffer
-	if err := w.file.Write(&buf); err != nil {
-		return ""
-	}
-	return base64.StdEncoding.EncodeToString(buf.Bytes())
-}
-
-type fichaImportCounters struct {
-	processed, updated, created, duplicates, errCount int
-}
-
+// fichaImportRunner procesa las filas de aprendices. seenEmail y seenCelular solo cubren las filas procesadas en
+// este intento: al reanudar se pierden y los duplicados con filas anteriores se detectan contra la base de datos.
 type fichaImportRunner struct {
 	s           *fichaImportService
 	ficha       *models.FichaCaracterizacion
@@ -251,8 +176,9 @@ type fichaImportRunner struct {
 	tipoByCode  map[string]uint
 	seenEmail   map[string]uint
 	seenCelular map[string]uint
-	incidents   *fichaImportIncidentWriter
-	c           *fichaImportCounters
+	// fila del Excel (base 1) en proceso y avance donde se acumula su resultado.
+	fila int
+	av   *avanceImportacion
 }
 
 type parsedFichaRow struct {
@@ -305,11 +231,7 @@ func (r *fichaImportRunner) applyEmailDuplicateRules(p *parsedFichaRow) {
 	if p.correo == "" || (!emailDuplicadoBD && !emailDuplicadoEnImport) {
 		return
 	}
-	r.incidents.add(fichaImportIncident{
-		Tipo: "EMAIL_DUPLICADO", NumeroDocumento: p.numeroDoc, Nombre: p.nombreStr, Apellidos: p.apellidosStr,
-		CorreoOriginal: p.correoOriginal, CelularOriginal: p.celularOriginal, FichaOrigen: "", FichaDestino: r.fichaCode,
-		Detalle: "Correo ya registrado en otra persona",
-	})
+	r.incidencia(p, "EMAIL_DUPLICADO", "", "Correo ya registrado en otra persona")
 	if emailDuplicadoEnImport && firstPersonaEmailID != 0 {
 		if persona, err := r.s.personaRepo.FindByID(firstPersonaEmailID); err == nil && persona != nil && persona.Email != "" {
 			persona.Email = ""
@@ -331,11 +253,7 @@ func (r *fichaImportRunner) applyCelularDuplicateRules(p *parsedFichaRow) {
 	if p.celular == "" || (!celDuplicadoBD && !celDuplicadoEnImport) {
 		return
 	}
-	r.incidents.add(fichaImportIncident{
-		Tipo: "CELULAR_DUPLICADO", NumeroDocumento: p.numeroDoc, Nombre: p.nombreStr, Apellidos: p.apellidosStr,
-		CorreoOriginal: p.correoOriginal, CelularOriginal: p.celularOriginal, FichaOrigen: "", FichaDestino: r.fichaCode,
-		Detalle: "Celular ya registrado en otra persona",
-	})
+	r.incidencia(p, "CELULAR_DUPLICADO", "", "Celular ya registrado en otra persona")
 	if celDuplicadoEnImport && firstPersonaCelID != 0 {
 		if persona, err := r.s.personaRepo.FindByID(firstPersonaCelID); err == nil && persona != nil && persona.Celular != "" {
 			persona.Celular = ""
@@ -360,21 +278,24 @@ func (r *fichaImportRunner) upsertPersona(p *parsedFichaRow) (personaID uint, is
 		req.TipoDocumento = &p.tipoID
 	}
 
+	// Sin actor: la importación queda auditada como una sola operación (middleware), no fila por fila.
 	persona, errFind := r.s.personaRepo.FindByNumeroDocumento(p.numeroDoc)
 	if errFind != nil || persona == nil {
-		createdResp, errCreate := r.s.personaSvc.Create(req)
+		createdResp, errCreate := r.s.personaSvc.Create(dto.Actor{}, req)
 		if errCreate != nil {
-			r.c.errCount++
+			r.av.errores++
+			r.incidencia(p, "ERROR", "", errCreate.Error())
 			return 0, false, false
 		}
-		r.c.created++
+		r.av.creados++
 		return createdResp.ID, true, true
 	}
-	if _, errUpdate := r.s.personaSvc.Update(persona.ID, req); errUpdate != nil {
-		r.c.errCount++
+	if _, errUpdate := r.s.personaSvc.Update(dto.Actor{}, persona.ID, req); errUpdate != nil {
+		r.av.errores++
+		r.incidencia(p, "ERROR", "", errUpdate.Error())
 		return 0, false, false
 	}
-	r.c.updated++
+	r.av.actualizados++
 	return persona.ID, false, true
 }
 
@@ -393,24 +314,20 @@ func (r *fichaImportRunner) recordSeenForNewPersona(p *parsedFichaRow, personaID
 func (r *fichaImportRunner) tryEnrollAprendiz(p *parsedFichaRow, personaID uint) {
 	if existingAprendiz, errAprendiz := r.s.aprendizRepo.FindByPersonaID(personaID); errAprendiz == nil && existingAprendiz != nil {
 		if existingAprendiz.FichaCaracterizacionID == r.ficha.ID {
-			r.c.duplicates++
+			r.av.duplicados++
 			return
 		}
-		r.c.duplicates++
+		r.av.duplicados++
 		fichaOrigenCodigo := ""
 		if fichaOrigen, errFicha := r.s.fichaRepo.FindByID(existingAprendiz.FichaCaracterizacionID); errFicha == nil && fichaOrigen != nil {
 			fichaOrigenCodigo = fichaOrigen.Ficha
 		}
-		r.incidents.add(fichaImportIncident{
-			Tipo: "YA_EN_OTRA_FICHA", NumeroDocumento: p.numeroDoc, Nombre: p.nombreStr, Apellidos: p.apellidosStr,
-			CorreoOriginal: p.correoOriginal, CelularOriginal: p.celularOriginal, FichaOrigen: fichaOrigenCodigo, FichaDestino: r.fichaCode,
-			Detalle: "Persona ya inscrita en otra ficha de formación",
-		})
+		r.incidencia(p, "YA_EN_OTRA_FICHA", fichaOrigenCodigo, "Persona ya inscrita en otra ficha de formación")
 		return
 	}
 
 	if _, errAprendiz := r.s.aprendizRepo.FindByPersonaIDAndFichaID(personaID, r.ficha.ID); errAprendiz == nil {
-		r.c.duplicates++
+		r.av.duplicados++
 		return
 	}
 	aprendiz := models.Aprendiz{
@@ -419,11 +336,26 @@ func (r *fichaImportRunner) tryEnrollAprendiz(p *parsedFichaRow, personaID uint)
 		Estado:                 true,
 	}
 	if err := r.s.aprendizRepo.Create(&aprendiz); err != nil {
-		r.c.errCount++
+		r.av.errores++
+		r.incidencia(p, "ERROR", "", err.Error())
 		return
 	}
 	_ = EnsureAprendizRoleForPersona(personaID)
-	r.c.processed++
+	r.av.procesados++
+}
+
+// incidencia registra la fila en el Excel de incidencias con el correo y celular tal como venían.
+func (r *fichaImportRunner) incidencia(p *parsedFichaRow, tipo, fichaOrigen, detalle string) {
+	r.av.incidencia(models.ImportacionIncidencia{
+		Fila:        r.fila,
+		Tipo:        tipo,
+		Documento:   p.numeroDoc,
+		Nombre:      truncarRunes(strings.TrimSpace(p.nombreStr+" "+p.apellidosStr), 255),
+		Correo:      truncarRunes(p.correoOriginal, 255),
+		Celular:     truncarRunes(p.celularOriginal, 50),
+		FichaOrigen: fichaOrigen,
+		Detalle:     truncarRunes(detalle, 1000),
+	})
 }
 
 func (r *fichaImportRunner) processRow(row []string) {
@@ -441,8 +373,18 @@ func (r *fichaImportRunner) processRow(row []string) {
 	r.tryEnrollAprendiz(&p, personaID)
 }
 
-func (s *fichaImportService) ImportFromExcel(fileBytes []byte, filename string) (*FichaImportResult, error) {
-	rows, err := s.readExcelRows(fileBytes, filename)
+// fichaImportacion importación del reporte de aprendices de una ficha, fila por fila desde la cabecera.
+type fichaImportacion struct {
+	s            *fichaImportService
+	rows         [][]string
+	dataStartRow int
+	fichaCode    string
+	programa     *models.ProgramaFormacion
+	runner       *fichaImportRunner
+}
+
+func (s *fichaImportService) prepararImportacion(t *models.ImportacionTrabajo, fileBytes []byte) (importadorFilas, error) {
+	rows, err := s.readExcelRows(fileBytes, t.Filename)
 	if err != nil {
 		return nil, err
 	}
@@ -465,40 +407,49 @@ func (s *fichaImportService) ImportFromExcel(fileBytes []byte, filename string)
 		return nil, fmt.Errorf("programa de formación no encontrado por nombre: %q", programName)
 	}
 
-	ficha, fichaCreated, err := s.getOrCreateFicha(fichaCode, programa)
-	if err != nil {
-		return nil, err
-	}
+	return &fichaImportacion{
+		s:            s,
+		rows:         rows,
+		dataStartRow: dataStartRow,
+		fichaCode:    fichaCode,
+		programa:     programa,
+		runner: &fichaImportRunner{
+			s:           s,
+			fichaCode:   fichaCode,
+			tipoByCode:  s.buildTipoDocumentoCodeMap(),
+			seenEmail:   make(map[string]uint),
+			seenCelular: make(map[string]uint),
+		},
+	}, nil
+}
 
-	tipoByCode := s.buildTipoDocumentoCodeMap()
-	incidentW := newFichaImportIncidentWriter()
+func (p *fichaImportacion) total() int { return len(p.rows) - p.dataStartRow }
 
-	counters := &fichaImportCounters{}
-	runner := &fichaImportRunner{
-		s:           s,
-		ficha:       ficha,
-		fichaCode:   fichaCode,
-		tipoByCode:  tipoByCode,
-		seenEmail:   make(map[string]uint),
-		seenCelular: make(map[string]uint),
-		incidents:   incidentW,
-		c:           counters,
+// iniciar busca o crea la ficha; al reanudar ya existe y se conserva si la creó el primer intento.
+func (p *fichaImportacion) iniciar(t *models.ImportacionTrabajo) error {
+	ficha, fichaCreated, err := p.s.getOrCreateFicha(p.fichaCode, p.programa)
+	if err != nil {
+		return err
 	}
-
-	for i := dataStartRow; i < len(rows); i++ {
-		runner.processRow(rows[i])
+	p.runner.ficha = ficha
+	t.FichaID = &ficha.ID
+	if fichaCreated {
+		t.FichaCreada = true
 	}
+	return nil
+}
 
-	return &FichaImportResult{
-		ProcessedCount:       counters.processed,
-		UpdatedCount:         counters.updated,
-		CreatedCount:         counters.created,
-		DuplicatesCount:      counters.duplicates,
-		ErrorCount:           counters.errCount,
-		FichaCreated:         fichaCreated,
-		Status:               "completado",
-		IncidentReportBase64: incidentW.toBase64(),
-	}, nil
+func (p *fichaImportacion) procesar(i int, av *avanceImportacion) {
+	p.runner.fila = p.dataStartRow + i + 1
+	p.runner.av = av
+	p.runner.processRow(p.rows[p.dataStartRow+i])
+}
+
+func (p *fichaImportacion) confirmar() error { return nil }
+
+func (p *fichaImportacion) finalizar(t *models.ImportacionTrabajo) {
+	notificarImportacionTerminada(p.s.notificaciones, t.UserID, fmt.Sprintf("Importación de la ficha %s terminada", p.fichaCode),
+		fmt.Sprintf("%s%d", rutaNotificacionFicha, p.runner.ficha.ID), t.Filename, t.Procesados, t.Duplicados, t.Errores)
 }
 
 func splitNombre(s string) (first, rest string) {
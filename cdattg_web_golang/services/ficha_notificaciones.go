@@ -0,0 +1,60 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+)
+
+// notificarAsignacionFicha avisa a los instructores que se asignaron por primera vez a la ficha (asignado=true) o
+// que se retiraron de ella.
+func (s *fichaService) notificarAsignacionFicha(f *models.FichaCaracterizacion, instructorIDs []uint, asignado bool) {
+	if s.notificaciones == nil || f == nil || len(instructorIDs) == 0 {
+		return
+	}
+	n := NotificacionNueva{
+		Tipo:    models.NotificacionTipoAsignacionFicha,
+		Titulo:  fmt.Sprintf("Asignado a la ficha %s", f.Ficha),
+		Mensaje: fmt.Sprintf("Coordinación lo asignó como instructor de la ficha %s. Revise sus días de formación en Mis fichas.", f.Ficha),
+		Enlace:  rutaNotificacionFichasInstructor,
+	}
+	if !asignado {
+		n.Titulo = fmt.Sprintf("Retirado de la ficha %s", f.Ficha)
+		n.Mensaje = fmt.Sprintf("Coordinación lo retiró como instructor de la ficha %s.", f.Ficha)
+	}
+	s.notificaciones.NotificarInstructores(instructorIDs, n)
+}
+
+// notificarTrasladoDia avisa a los dos instructores del traslado con el motivo y, si es por fechas, las fechas.
+func (s *fichaService) notificarTrasladoDia(fichaID uint, req dto.TrasladarDiaRequest) {
+	if s.notificaciones == nil {
+		return
+	}
+	codigo := fmt.Sprintf("%d", fichaID)
+	if f, err := s.fichaRepo.FindByID(fichaID); err == nil && f != nil {
+		codigo = f.Ficha
+	}
+	s.notificaciones.NotificarInstructores([]uint{req.InstructorOrigenID, req.InstructorDestinoID}, NotificacionNueva{
+		Tipo:    models.NotificacionTipoTrasladoDia,
+		Titulo:  fmt.Sprintf("Traslado de día en la ficha %s", codigo),
+		Mensaje: mensajeTrasladoDia(req),
+		Enlace:  rutaNotificacionFichasInstructor,
+	})
+}
+
+func mensajeTrasladoDia(req dto.TrasladarDiaRequest) string {
+	var b strings.Builder
+	if normalizarModoTraslado(req.Modo) == TrasladoModoFechas {
+		b.WriteString("Coordinación trasladó fechas de formación entre instructores:")
+		for _, p := range req.ParesFechas {
+			fmt.Fprintf(&b, " %s → %s;", p.FechaOrigen, p.FechaDestino)
+		}
+	} else {
+		fmt.Fprintf(&b, "Coordinación trasladó de forma permanente el %s del instructor de origen al de destino, a cambio del %s.",
+			nombreDia(req.DiaOrigenID), nombreDia(req.DiaDestinoID))
+	}
+	fmt.Fprintf(&b, " Motivo: %s", strings.TrimSpace(req.Motivo))
+	return b.String()
+}
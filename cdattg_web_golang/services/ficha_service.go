@@ -26,9 +26,9 @@ type FichaService interface {
 	FindByID(id uint) (*dto.FichaCaracterizacionResponse, error)
 	FindByIDWithDetail(id uint) (*dto.FichaCaracterizacionResponse, error)
 	GetCodigo(id uint) (string, error)
-	Create(req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error)
-	Update(id uint, req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error)
-	Delete(id uint) error
+	Create(actor dto.Actor, req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error)
+	Update(actor dto.Actor, id uint, req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error)
+	Delete(actor dto.Actor, id uint) error
 	ListInstructores(fichaID uint) ([]dto.InstructorFichaResponse, error)
 	AsignarInstructores(fichaID uint, req dto.AsignarInstructoresRequest) error
 	TrasladarDiaInstructor(fichaID, actorUserID uint, req dto.TrasladarDiaRequest) error
@@ -48,6 +48,8 @@ type fichaService struct {
 	aprendizRepo      repositories.AprendizRepository
 	fichaDiasRepo     repositories.FichaDiasRepository
 	horarioSvc        *InstructorHorarioService
+	ambienteSvc       *AmbienteOcupacionService
+	notificaciones    NotificacionUsuarioService
 }
 
 func NewFichaService() FichaService {
@@ -60,6 +62,8 @@ func NewFichaService() FichaService {
 		aprendizRepo:      repositories.NewAprendizRepository(),
 		fichaDiasRepo:     repositories.NewFichaDiasRepository(),
 		horarioSvc:        NewInstructorHorarioService(),
+		ambienteSvc:       NewAmbienteOcupacionService(),
+		notificaciones:    NewNotificacionUsuarioService(),
 	}
 }
 
@@ -205,7 +209,7 @@ func (s *fichaService) GetCodigo(id uint) (string, error) {
 	return f.Ficha, nil
 }
 
-func (s *fichaService) Create(req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error) {
+func (s *fichaService) Create(actor dto.Actor, req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error) {
 	if s.fichaRepo.ExistsByFicha(req.Ficha) {
 		return nil, errors.New("ya existe una ficha con ese número")
 	}
@@ -222,6 +226,9 @@ func (s *fichaService) Create(req dto.FichaCaracterizacionRequest) (*dto.FichaCa
 	} else {
 		f.Status = true
 	}
+	if err := s.validarAmbienteFicha(0, f.Status, req); err != nil {
+		return nil, err
+	}
 	if err := s.fichaRepo.Create(&f); err != nil {
 		return nil, fmt.Errorf("error al crear ficha: %w", err)
 	}
@@ -243,17 +250,29 @@ func (s *fichaService) Create(req dto.FichaCaracterizacionRequest) (*dto.FichaCa
 			_ = s.fichaRepo.Update(&f)
 		}
 	}
-	return s.FindByID(f.ID)
+	resp, err := s.FindByID(f.ID)
+	if err == nil {
+		auditar(actor, AccionFichaCrear, tablaFichas, f.ID, nil, resp)
+	}
+	return resp, err
 }
 
-func (s *fichaService) Update(id uint, req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error) {
+func (s *fichaService) Update(actor dto.Actor, id uint, req dto.FichaCaracterizacionRequest) (*dto.FichaCaracterizacionResponse, error) {
 	f, err := s.fichaRepo.FindByID(id)
 	if err != nil {
 		return nil, errors.New(msgFichaNoEncontrada)
 	}
+	antes, _ := s.FindByID(id)
 	if s.fichaRepo.ExistsByFichaExcludingID(req.Ficha, id) {
 		return nil, errors.New("ya existe otra ficha con ese número")
 	}
+	status := f.Status
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if err := s.validarAmbienteFicha(id, status, req); err != nil {
+		return nil, err
+	}
 	f.ProgramaFormacionID = req.ProgramaFormacionID
 	f.Ficha = req.Ficha
 	f.InstructorID = req.InstructorID
@@ -292,14 +311,23 @@ func (s *fichaService) Update(id uint, req dto.FichaCaracterizacionRequest) (*dt
 			}
 		}
 	}
-	return s.FindByID(id)
+	resp, err := s.FindByID(id)
+	if err == nil {
+		auditar(actor, AccionFichaActualizar, tablaFichas, id, antes, resp)
+	}
+	return resp, err
 }
 
-func (s *fichaService) Delete(id uint) error {
-	if _, err := s.fichaRepo.FindByID(id); err != nil {
+func (s *fichaService) Delete(actor dto.Actor, id uint) error {
+	antes, err := s.FindByID(id)
+	if err != nil {
 		return errors.New(msgFichaNoEncontrada)
 	}
-	return s.fichaRepo.Delete(id)
+	if err := s.fichaRepo.Delete(id); err != nil {
+		return err
+	}
+	auditar(actor, AccionFichaEliminar, tablaFichas, id, antes, nil)
+	return nil
 }
 
 func (s *fichaService) ListInstructores(fichaID uint) ([]dto.InstructorFichaResponse, error) {
@@ -358,11 +386,17 @@ func (s *fichaService) AsignarInstructores(fichaID uint, req dto.AsignarInstruct
 	}
 	// Crear o actualizar asignaciones
 	fichaDiasDefault := s.diasFormacionFicha(fichaID)
+	nuevos := make([]uint, 0, len(req.Instructores))
 	for _, it := range req.Instructores {
-		if err := s.persistirAsignacionInstructor(fichaID, it, fichaDiasDefault); err != nil {
+		nuevo, err := s.persistirAsignacionInstructor(fichaID, it, fichaDiasDefault)
+		if err != nil {
 			return err
 		}
+		if nuevo {
+			nuevos = append(nuevos, it.InstructorID)
+		}
 	}
+	s.notificarAsignacionFicha(f, nuevos, true)
 	return nil
 }
 
@@ -370,7 +404,7 @@ func (s *fichaService) persistirAsignacionInstructor(
 	fichaID uint,
 	it dto.InstructorFichaItem,
 	fichaDiasDefault []uint,
-) error {
+) (bool, error) {
 	fechaInicio := it.FechaInicio.Time
 	fechaFin := it.FechaFin.Time
 	diasIDs := it.DiasFormacionIDs
@@ -380,10 +414,10 @@ func (s *fichaService) persistirAsignacionInstructor(
 	// Sin días: permitido mientras coordinación no cargue programación (requerimiento dirección).
 	if len(diasIDs) > 0 {
 		if err := s.horarioSvc.ValidarDiasSubsetFicha(fichaID, diasIDs); err != nil {
-			return fmt.Errorf("instructor %d: %w", it.InstructorID, err)
+			return false, fmt.Errorf("instructor %d: %w", it.InstructorID, err)
 		}
 		if err := s.horarioSvc.ValidarColisionAlAsignar(it.InstructorID, fichaID, diasIDs, fechaInicio, fechaFin, true); err != nil {
-			return err
+			return false, err
 		}
 	}
 	ex, err := s.instFichaRepo.FindByFichaIDAndInstructorID(fichaID, it.InstructorID)
@@ -393,9 +427,9 @@ func (s *fichaService) persistirAsignacionInstructor(
 		ex.FechaFin = &fechaFin
 		ex.TotalHorasInstructor = it.TotalHorasInstructor
 		if errUp := s.instFichaRepo.Update(ex); errUp != nil {
-			return errUp
+			return false, errUp
 		}
-		return s.guardarDiasInstructor(it.InstructorID, fichaID, diasIDs)
+		return false, s.guardarDiasInstructor(it.InstructorID, fichaID, diasIDs)
 	}
 	m := models.InstructorFichaCaracterizacion{
 		InstructorID:         it.InstructorID,
@@ -406,9 +440,9 @@ func (s *fichaService) persistirAsignacionInstructor(
 		TotalHorasInstructor: it.TotalHorasInstructor,
 	}
 	if err := s.instFichaRepo.Create(&m); err != nil {
-		return fmt.Errorf("error al asignar instructor: %w", err)
+		return false, fmt.Errorf("error al asignar instructor: %w", err)
 	}
-	return s.guardarDiasInstructor(it.InstructorID, fichaID, diasIDs)
+	return true, s.guardarDiasInstructor(it.InstructorID, fichaID, diasIDs)
 }
 
 func (s *fichaService) guardarDiasInstructor(instructorID, fichaID uint, diasIDs []uint) error {
@@ -420,7 +454,13 @@ func (s *fichaService) guardarDiasInstructor(instructorID, fichaID uint, diasIDs
 
 func (s *fichaService) DesasignarInstructor(fichaID, instructorID uint) error {
 	_ = s.instFichaDiasRepo.DeleteByInstructorAndFicha(instructorID, fichaID)
-	return s.instFichaRepo.DeleteByFichaIDAndInstructorID(fichaID, instructorID)
+	if err := s.instFichaRepo.DeleteByFichaIDAndInstructorID(fichaID, instructorID); err != nil {
+		return err
+	}
+	if f, err := s.fichaRepo.FindByID(fichaID); err == nil {
+		s.notificarAsignacionFicha(f, []uint{instructorID}, false)
+	}
+	return nil
 }
 
 type trasladoAuditDetalle struct {
@@ -453,7 +493,11 @@ func (s *fichaService) TrasladarDiaInstructor(fichaID, actorUserID uint, req dto
 	if err := s.validarTrasladoConHorario(fichaID, req, ctx); err != nil {
 		return err
 	}
-	return s.persistirTrasladoPermanenteConAuditoria(fichaID, actorUserID, req, ctx)
+	if err := s.persistirTrasladoPermanenteConAuditoria(fichaID, actorUserID, req, ctx); err != nil {
+		return err
+	}
+	s.notificarTrasladoDia(fichaID, req)
+	return nil
 }
 
 type trasladoContexto struct {
@@ -579,12 +623,17 @@ func (s *fichaService) trasladarDiaPorFechas(fichaID, actorUserID uint, req dto.
 			return fmt.Errorf("colisión en fecha destino %s: %w", par.FechaDestino.Format(time.DateOnly), err)
 		}
 	}
-	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
 		if err := s.trasladoFechaRepo.CreateBatch(tx, pares); err != nil {
 			return err
 		}
 		return crearLogTraslado(tx, actorUserID, fichaID, req, nil, pares)
 	})
+	if err != nil {
+		return err
+	}
+	s.notificarTrasladoDia(fichaID, req)
+	return nil
 }
 
 func crearLogTraslado(
@@ -982,6 +1031,35 @@ func derivarJornadaPrincipalID(req dto.FichaCaracterizacionRequest) *uint {
 	return &bestID
 }
 
+// validarAmbienteFicha rechaza el ambiente de la solicitud si otra ficha lo ocupa en bloques que se solapan.
+// Sin programación horaria en la solicitud se toman los días ya guardados de la ficha (fichaID > 0).
+func (s *fichaService) validarAmbienteFicha(fichaID uint, status bool, req dto.FichaCaracterizacionRequest) error {
+	if req.AmbienteID == nil || *req.AmbienteID == 0 {
+		return nil
+	}
+	cand := s.fichaRequestToModel(req)
+	cand.Status = status
+	if req.Horarios != nil || req.DiasFormacionIDs != nil || req.DiasFormacion != nil {
+		for _, in := range horariosToRepoInputs(resolveHorariosFromRequest(req)) {
+			cand.FichaDiasFormacion = append(cand.FichaDiasFormacion, models.FichaDiasFormacion{
+				DiaFormacionID: in.DiaFormacionID,
+				HoraInicio:     in.HoraInicio,
+				HoraFin:        in.HoraFin,
+				Orden:          in.Orden,
+				JornadaID:      in.JornadaID,
+			})
+		}
+		cand.JornadaID = derivarJornadaPrincipalID(req)
+	} else if fichaID > 0 {
+		dias, err := s.fichaDiasRepo.FindByFichaID(fichaID)
+		if err != nil {
+			return err
+		}
+		cand.FichaDiasFormacion = dias
+	}
+	return s.ambienteSvc.ValidarAmbienteDisponible(*req.AmbienteID, fichaID, &cand)
+}
+
 func (s *fichaService) guardarProgramacionHoraria(fichaID uint, req dto.FichaCaracterizacionRequest) error {
 	items := resolveHorariosFromRequest(req)
 	if len(items) == 0 {
@@ -0,0 +1,541 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxTrabajosImportacionSimultaneos trabajos que procesa a la vez cada réplica; el resto espera turno.
+	maxTrabajosImportacionSimultaneos = 2
+	// reservaTrabajoImportacion tiempo que un trabajo tomado queda fuera de la cola; cada guardado de avance la
+	// renueva. Si la réplica cae, al vencer otra lo retoma desde la última fila guardada.
+	reservaTrabajoImportacion = 5 * time.Minute
+	// filasPorGuardado e intervaloGuardado: el avance se guarda cada tantas filas o cada tanto tiempo, lo primero.
+	filasPorGuardado                 = 25
+	intervaloGuardado                = 2 * time.Second
+	loteReclamables                  = 20
+	diasRetencionTrabajosImportacion = 30
+)
+
+var (
+	ErrImportacionNoEncontrada   = errors.New("importación no encontrada")
+	ErrImportacionTipoInvalido   = errors.New("tipo de importación no soportado")
+	ErrImportacionNoReanudable   = errors.New("solo se pueden reanudar importaciones fallidas")
+	ErrImportacionSinIncidencias = errors.New("la importación no tiene incidencias")
+)
+
+// ImportacionNueva archivo subido para importar en segundo plano.
+type ImportacionNueva struct {
+	Tipo     string
+	UserID   uint
+	Filename string
+	Archivo  []byte
+	// RegionalID regional de los instructores nuevos (solo tipo instructores).
+	RegionalID *uint
+}
+
+// ImportacionTrabajoService importaciones de Excel como trabajos en segundo plano: el archivo se valida y guarda al
+// subirlo, las filas se procesan aparte y el avance queda consultable y reanudable.
+type ImportacionTrabajoService interface {
+	// Encolar valida encabezados y formato, guarda el trabajo con el archivo y empieza a procesarlo.
+	Encolar(req ImportacionNueva) (*dto.ImportacionTrabajoResponse, error)
+	// Get trabajo del usuario (el super administrador ve todos).
+	Get(id, userID uint, roles []string) (*dto.ImportacionTrabajoResponse, error)
+	// List trabajos del usuario, más recientes primero.
+	List(f repositories.ImportacionTrabajoFiltro) ([]dto.ImportacionTrabajoResponse, int64, error)
+	// ExcelIncidencias Excel con las filas duplicadas, con error o importadas con ajustes.
+	ExcelIncidencias(id, userID uint, roles []string) ([]byte, string, error)
+	// Reanudar devuelve a la cola un trabajo fallido; sigue desde la última fila guardada.
+	Reanudar(id, userID uint, roles []string) (*dto.ImportacionTrabajoResponse, error)
+	// ProcesarPendientes retoma los trabajos pendientes y los que quedaron a medias en una réplica caída (tarea programada).
+	ProcesarPendientes(ctx context.Context) error
+	// PurgarAntiguos elimina los trabajos terminados de más de 30 días con su archivo e incidencias.
+	PurgarAntiguos() error
+}
+
+// importadorFilas importación de un archivo ya leído, unidad por unidad (fila o código de programa), para poder
+// guardar avance y reanudar.
+type importadorFilas interface {
+	// total unidades a procesar.
+	total() int
+	// iniciar prepara lo necesario antes de la primera unidad (p. ej. la ficha). Se repite al reanudar, así que
+	// debe ser idempotente.
+	iniciar(t *models.ImportacionTrabajo) error
+	// procesar importa la unidad i (base 0) y acumula el resultado en av.
+	procesar(i int, av *avanceImportacion)
+	// confirmar completa lo diferido de las unidades ya procesadas (p. ej. crear usuarios) antes de guardar avance.
+	confirmar() error
+	// finalizar registra el historial y avisa al usuario cuando el trabajo termina.
+	finalizar(t *models.ImportacionTrabajo)
+}
+
+// preparadorImportacion lee y valida el archivo; no modifica datos.
+type preparadorImportacion func(t *models.ImportacionTrabajo, archivo []byte) (importadorFilas, error)
+
+// avanceImportacion resultado de las unidades procesadas desde el último guardado.
+type avanceImportacion struct {
+	filas                               int
+	procesados, duplicados, errores     int
+	actualizados, creados, redesCreadas int
+	incidencias                         []models.ImportacionIncidencia
+}
+
+func (a *avanceImportacion) incidencia(inc models.ImportacionIncidencia) {
+	a.incidencias = append(a.incidencias, inc)
+}
+
+// aplicar suma el avance a una copia del trabajo.
+func (a *avanceImportacion) aplicar(t models.ImportacionTrabajo) models.ImportacionTrabajo {
+	t.FilaActual += a.filas
+	t.Procesados += a.procesados
+	t.Duplicados += a.duplicados
+	t.Errores += a.errores
+	t.Actualizados += a.actualizados
+	t.Creados += a.creados
+	t.RedesCreadas += a.redesCreadas
+	t.Incidencias += len(a.incidencias)
+	return t
+}
+
+// ejecutorTrabajos limita los trabajos en segundo plano simultáneos de la réplica y evita lanzar dos veces el mismo.
+type ejecutorTrabajos struct {
+	mu      sync.Mutex
+	enCurso map[uint]bool
+	cupos   chan struct{}
+}
+
+func newEjecutorTrabajos(max int) *ejecutorTrabajos {
+	return &ejecutorTrabajos{enCurso: make(map[uint]bool), cupos: make(chan struct{}, max)}
+}
+
+// lanzar ejecuta fn en segundo plano cuando haya cupo. Si el trabajo ya está en curso en la réplica, no hace nada.
+func (e *ejecutorTrabajos) lanzar(id uint, fn func()) {
+	e.mu.Lock()
+	if e.enCurso[id] {
+		e.mu.Unlock()
+		return
+	}
+	e.enCurso[id] = true
+	e.mu.Unlock()
+	go func() {
+		defer func() {
+			e.mu.Lock()
+			delete(e.enCurso, id)
+			e.mu.Unlock()
+		}()
+		e.cupos <- struct{}{}
+		defer func() { <-e.cupos }()
+		fn()
+	}()
+}
+
+var ejecutorImportacionesGlobal = newEjecutorTrabajos(maxTrabajosImportacionSimultaneos)
+
+type importacionTrabajoService struct {
+	repo           repositories.ImportacionTrabajoRepository
+	preparadores   map[string]preparadorImportacion
+	notificaciones NotificacionUsuarioService
+	ejecutor       *ejecutorTrabajos
+}
+
+func NewImportacionTrabajoService() ImportacionTrabajoService {
+	notificaciones := NewNotificacionUsuarioService()
+	return &importacionTrabajoService{
+		repo: repositories.NewImportacionTrabajoRepository(),
+		preparadores: map[string]preparadorImportacion{
+			models.ImportacionTipoPersonas:     newPersonaImportService(NewPersonaService(), notificaciones).prepararImportacion,
+			models.ImportacionTipoInstructores: newInstructorImportService(notificaciones).prepararImportacion,
+			models.ImportacionTipoFicha:        newFichaImportService(notificaciones).prepararImportacion,
+			models.ImportacionTipoProgramas:    newProgramaFormacionImportService(notificaciones).prepararImportacion,
+		},
+		notificaciones: notificaciones,
+		ejecutor:       ejecutorImportacionesGlobal,
+	}
+}
+
+func contentTypeExcel(filename string) string {
+	lower := strings.ToLower(filename)
+	if strings.HasSuffix(lower, ".xls") {
+		return "application/vnd.ms-excel"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (s *importacionTrabajoService) Encolar(req ImportacionNueva) (*dto.ImportacionTrabajoResponse, error) {
+	preparar, ok := s.preparadores[req.Tipo]
+	if !ok {
+		return nil, ErrImportacionTipoInvalido
+	}
+	t := &models.ImportacionTrabajo{
+		Tipo:       req.Tipo,
+		Estado:     models.ImportacionEstadoPendiente,
+		UserID:     req.UserID,
+		Filename:   truncarRunes(req.Filename, 255),
+		RegionalID: req.RegionalID,
+	}
+	// Encabezados y formato se validan al subir para responder el error de inmediato, como antes de la cola.
+	imp, err := preparar(t, req.Archivo)
+	if err != nil {
+		return nil, err
+	}
+	t.TotalFilas = imp.total()
+	t.Archivo = &models.ImportacionTrabajoArchivo{
+		ContentType: contentTypeExcel(req.Filename),
+		Tamano:      int64(len(req.Archivo)),
+		Contenido:   req.Archivo,
+	}
+	if err := s.repo.Create(t); err != nil {
+		return nil, fmt.Errorf("encolar importación: %w", err)
+	}
+	s.lanzar(t.ID)
+	out := importacionTrabajoToResponse(t)
+	return &out, nil
+}
+
+// lanzar procesa el trabajo en segundo plano cuando haya cupo en la réplica. Si otra réplica lo tomó antes, no hace nada.
+func (s *importacionTrabajoService) lanzar(id uint) {
+	s.ejecutor.lanzar(id, func() {
+		ahora := utils.Now()
+		ok, err := s.repo.ReclamarPorID(id, ahora, ahora.Add(reservaTrabajoImportacion))
+		if err != nil {
+			log.Printf("[importaciones] tomando trabajo %d: %v", id, err)
+			return
+		}
+		if ok {
+			s.ejecutar(id)
+		}
+	})
+}
+
+// ejecutar procesa un trabajo ya tomado desde su fila actual hasta el final.
+func (s *importacionTrabajoService) ejecutar(id uint) {
+	t, err := s.repo.FindByID(id)
+	if err != nil {
+		log.Printf("[importaciones] cargando trabajo %d: %v", id, err)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[importaciones] trabajo %d: panic: %v", id, r)
+			s.marcarFallido(t, fmt.Errorf("error inesperado: %v", r))
+		}
+	}()
+	imp, err := s.preparar(t)
+	if err != nil {
+		s.marcarFallido(t, err)
+		return
+	}
+	if err := imp.iniciar(t); err != nil {
+		s.marcarFallido(t, err)
+		return
+	}
+	t.TotalFilas = imp.total()
+	av := &avanceImportacion{}
+	if !s.guardarAvance(t, imp, av, false) {
+		return
+	}
+	ultimoGuardado := utils.Now()
+	for t.FilaActual+av.filas < t.TotalFilas {
+		imp.procesar(t.FilaActual+av.filas, av)
+		av.filas++
+		if av.filas >= filasPorGuardado || utils.Now().Sub(ultimoGuardado) >= intervaloGuardado {
+			if !s.guardarAvance(t, imp, av, false) {
+				return
+			}
+			ultimoGuardado = utils.Now()
+		}
+	}
+	if !s.guardarAvance(t, imp, av, true) {
+		return
+	}
+	log.Printf("[importaciones] trabajo %d (%s) completado: %d importados, %d duplicados, %d con error",
+		t.ID, t.Tipo, t.Procesados, t.Duplicados, t.Errores)
+	imp.finalizar(t)
+}
+
+func (s *importacionTrabajoService) preparar(t *models.ImportacionTrabajo) (importadorFilas, error) {
+	preparar, ok := s.preparadores[t.Tipo]
+	if !ok {
+		return nil, ErrImportacionTipoInvalido
+	}
+	archivo, err := s.repo.FindArchivo(t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("archivo de la importación: %w", err)
+	}
+	return preparar(t, archivo.Contenido)
+}
+
+// guardarAvance confirma lo diferido de las filas procesadas, guarda el avance con sus incidencias y renueva la
+// reserva. Con final marca el trabajo completado. Devuelve false si el trabajo no debe seguir (falló o lo tomó otra
+// réplica); en ese caso t conserva el último avance guardado.
+func (s *importacionTrabajoService) guardarAvance(t *models.ImportacionTrabajo, imp importadorFilas, av *avanceImportacion, final bool) bool {
+	if err := imp.confirmar(); err != nil {
+		s.marcarFallido(t, err)
+		return false
+	}
+	ahora := utils.Now()
+	nuevo := av.aplicar(*t)
+	nuevo.UpdatedAt = ahora
+	hasta := ahora.Add(reservaTrabajoImportacion)
+	nuevo.ReclamadoHasta = &hasta
+	if final {
+		nuevo.Estado = models.ImportacionEstadoCompletado
+		nuevo.ReclamadoHasta = nil
+		nuevo.FinalizadoAt = &ahora
+	}
+	vigente, err := s.repo.GuardarAvance(&nuevo, av.incidencias)
+	if err != nil {
+		s.marcarFallido(t, fmt.Errorf("guardando avance: %w", err))
+		return false
+	}
+	if !vigente {
+		log.Printf("[importaciones] trabajo %d: otra réplica lo tomó; se detiene este intento", t.ID)
+		return false
+	}
+	*t = nuevo
+	*av = avanceImportacion{}
+	return true
+}
+
+// marcarFallido deja el trabajo fallido en la última fila guardada y avisa al usuario. Si no se puede guardar,
+// la reserva vence y la tarea de recuperación lo retoma.
+func (s *importacionTrabajoService) marcarFallido(t *models.ImportacionTrabajo, causa error) {
+	log.Printf("[importaciones] trabajo %d (%s) falló en la fila %d: %v", t.ID, t.Tipo, t.FilaActual, causa)
+	ahora := utils.Now()
+	fallido := *t
+	fallido.Estado = models.ImportacionEstadoFallido
+	fallido.UltimoError = truncarRunes(causa.Error(), 1000)
+	fallido.ReclamadoHasta = nil
+	fallido.FinalizadoAt = &ahora
+	fallido.UpdatedAt = ahora
+	vigente, err := s.repo.GuardarAvance(&fallido, nil)
+	if err != nil {
+		log.Printf("[importaciones] trabajo %d: no se pudo marcar fallido: %v", t.ID, err)
+		return
+	}
+	if !vigente {
+		return
+	}
+	*t = fallido
+	if s.notificaciones == nil {
+		return
+	}
+	s.notificaciones.Notificar([]uint{t.UserID}, NotificacionNueva{
+		Tipo:   models.NotificacionTipoImportacion,
+		Titulo: "Importación detenida por un error",
+		Mensaje: fmt.Sprintf("El archivo %s se detuvo en la fila %d de %d: %s. Puede reanudarla desde donde quedó.",
+			t.Filename, t.FilaActual, t.TotalFilas, fallido.UltimoError),
+		Enlace: rutaImportacionTrabajo(t),
+	})
+}
+
+// rutaImportacionTrabajo página de la importación según el tipo, con el trabajo para mostrar su avance.
+func rutaImportacionTrabajo(t *models.ImportacionTrabajo) string {
+	ruta := rutaNotificacionImportPersonas
+	switch t.Tipo {
+	case models.ImportacionTipoInstructores:
+		ruta = rutaNotificacionImportInstructor
+	case models.ImportacionTipoProgramas:
+		ruta = rutaNotificacionImportProgramas
+	case models.ImportacionTipoFicha:
+		ruta = rutaNotificacionImportFichas
+	}
+	return fmt.Sprintf("%s?trabajo=%d", ruta, t.ID)
+}
+
+func (s *importacionTrabajoService) ProcesarPendientes(ctx context.Context) error {
+	ids, err := s.repo.ListReclamables(utils.Now(), loteReclamables)
+	if err != nil {
+		return fmt.Errorf("cola de importaciones: %w", err)
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.lanzar(id)
+	}
+	if len(ids) > 0 {
+		log.Printf("[importaciones] %d trabajos pendientes o interrumpidos en cola", len(ids))
+	}
+	return nil
+}
+
+func (s *importacionTrabajoService) PurgarAntiguos() error {
+	n, err := s.repo.DeleteTerminadosAntesDe(utils.Now().AddDate(0, 0, -diasRetencionTrabajosImportacion))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("[importaciones] eliminados %d trabajos terminados hace más de %d días", n, diasRetencionTrabajosImportacion)
+	}
+	return nil
+}
+
+// trabajoVisible trabajo del usuario o, para el super administrador, de cualquiera.
+func (s *importacionTrabajoService) trabajoVisible(id, userID uint, roles []string) (*models.ImportacionTrabajo, error) {
+	t, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImportacionNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.UserID != userID && !hasRole(roles, "SUPER ADMINISTRADOR") {
+		return nil, ErrImportacionNoEncontrada
+	}
+	return t, nil
+}
+
+func (s *importacionTrabajoService) Get(id, userID uint, roles []string) (*dto.ImportacionTrabajoResponse, error) {
+	t, err := s.trabajoVisible(id, userID, roles)
+	if err != nil {
+		return nil, err
+	}
+	out := importacionTrabajoToResponse(t)
+	return &out, nil
+}
+
+func (s *importacionTrabajoService) List(f repositories.ImportacionTrabajoFiltro) ([]dto.ImportacionTrabajoResponse, int64, error) {
+	list, total, err := s.repo.List(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]dto.ImportacionTrabajoResponse, len(list))
+	for i := range list {
+		out[i] = importacionTrabajoToResponse(&list[i])
+	}
+	return out, total, nil
+}
+
+func (s *importacionTrabajoService) Reanudar(id, userID uint, roles []string) (*dto.ImportacionTrabajoResponse, error) {
+	t, err := s.trabajoVisible(id, userID, roles)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.MarcarPendiente(t.ID, utils.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrImportacionNoReanudable
+	}
+	s.lanzar(t.ID)
+	return s.Get(id, userID, roles)
+}
+
+// encabezadosIncidencias columnas del Excel de incidencias; la importación de ficha conserva las de su reporte.
+func encabezadosIncidencias(tipo string) []string {
+	if tipo == models.ImportacionTipoFicha {
+		return []string{"fila", "tipo_incidente", "numero_documento", "nombre", "correo_original", "celular_original", "ficha_origen", "detalle"}
+	}
+	return []string{"fila", "tipo_incidente", "numero_documento", "nombre", "detalle"}
+}
+
+func valoresIncidencia(tipo string, inc models.ImportacionIncidencia) []interface{} {
+	if tipo == models.ImportacionTipoFicha {
+		return []interface{}{inc.Fila, inc.Tipo, inc.Documento, inc.Nombre, inc.Correo, inc.Celular, inc.FichaOrigen, inc.Detalle}
+	}
+	return []interface{}{inc.Fila, inc.Tipo, inc.Documento, inc.Nombre, inc.Detalle}
+}
+
+func (s *importacionTrabajoService) ExcelIncidencias(id, userID uint, roles []string) ([]byte, string, error) {
+	t, err := s.trabajoVisible(id, userID, roles)
+	if err != nil {
+		return nil, "", err
+	}
+	list, err := s.repo.ListIncidencias(t.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(list) == 0 {
+		return nil, "", ErrImportacionSinIncidencias
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := "Incidencias"
+	_ = f.SetSheetName(f.GetSheetName(0), sheet)
+	for i, h := range encabezadosIncidencias(t.Tipo) {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		_ = f.SetCellValue(sheet, cell, h)
+	}
+	for r, inc := range list {
+		for i, v := range valoresIncidencia(t.Tipo, inc) {
+			cell, _ := excelize.CoordinatesToCellName(i+1, r+2)
+			_ = f.SetCellValue(sheet, cell, v)
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), fmt.Sprintf("incidencias_importacion_%d.xlsx", t.ID), nil
+}
+
+func importacionTrabajoToResponse(t *models.ImportacionTrabajo) dto.ImportacionTrabajoResponse {
+	out := dto.ImportacionTrabajoResponse{
+		ID:           t.ID,
+		Tipo:         t.Tipo,
+		Estado:       t.Estado,
+		Filename:     t.Filename,
+		RegionalID:   t.RegionalID,
+		TotalFilas:   t.TotalFilas,
+		FilaActual:   t.FilaActual,
+		Procesados:   t.Procesados,
+		Duplicados:   t.Duplicados,
+		Errores:      t.Errores,
+		Actualizados: t.Actualizados,
+		Creados:      t.Creados,
+		RedesCreadas: t.RedesCreadas,
+		FichaID:      t.FichaID,
+		FichaCreada:  t.FichaCreada,
+		Incidencias:  t.Incidencias,
+		Intentos:     t.Intentos,
+		UltimoError:  t.UltimoError,
+		Reanudable:   t.Estado == models.ImportacionEstadoFallido,
+		IniciadoAt:   t.IniciadoAt,
+		FinalizadoAt: t.FinalizadoAt,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+	switch {
+	case t.Estado == models.ImportacionEstadoCompletado:
+		out.Porcentaje = 100
+	case t.TotalFilas > 0:
+		out.Porcentaje = t.FilaActual * 100 / t.TotalFilas
+	}
+	return out
+}
+
+// leerFilasExcel filas de la primera hoja de un .xlsx con encabezados y al menos una fila de datos.
+func leerFilasExcel(fileBytes []byte) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(fileBytes))
+	if err != nil {
+		return nil, fmt.Errorf("archivo Excel inválido: %w", err)
+	}
+	defer f.Close()
+
+	sheetName := f.GetSheetName(0)
+	if sheetName == "" {
+		return nil, fmt.Errorf("el archivo no contiene hojas")
+	}
+
+	rows, err := f.GetRows(sheetName)
+	if err != nil || len(rows) < 2 {
+		return nil, fmt.Errorf("el archivo debe tener al menos encabezados y una fila de datos")
+	}
+	return rows, nil
+}
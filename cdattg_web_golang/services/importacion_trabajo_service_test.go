@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"gorm.io/gorm"
+)
+
+// importacionRepoFake guarda un trabajo en memoria y aplica GuardarAvance solo con la reserva vigente, como el real.
+type importacionRepoFake struct {
+	repositories.ImportacionTrabajoRepository
+	trabajos    map[uint]models.ImportacionTrabajo
+	incidencias []models.ImportacionIncidencia
+	guardados   []int // FilaActual de cada guardado aplicado
+}
+
+func (r *importacionRepoFake) FindByID(id uint) (*models.ImportacionTrabajo, error) {
+	t, ok := r.trabajos[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &t, nil
+}
+
+func (r *importacionRepoFake) FindArchivo(trabajoID uint) (*models.ImportacionTrabajoArchivo, error) {
+	return &models.ImportacionTrabajoArchivo{TrabajoID: trabajoID, Contenido: []byte("xlsx")}, nil
+}
+
+func (r *importacionRepoFake) GuardarAvance(t *models.ImportacionTrabajo, incidencias []models.ImportacionIncidencia) (bool, error) {
+	actual := r.trabajos[t.ID]
+	if actual.Intentos != t.Intentos || actual.Estado != models.ImportacionEstadoProcesando {
+		return false, nil
+	}
+	r.trabajos[t.ID] = *t
+	r.incidencias = append(r.incidencias, incidencias...)
+	r.guardados = append(r.guardados, t.FilaActual)
+	return true, nil
+}
+
+func (r *importacionRepoFake) MarcarPendiente(id uint, _ time.Time) (bool, error) {
+	t := r.trabajos[id]
+	if t.Estado != models.ImportacionEstadoFallido {
+		return false, nil
+	}
+	t.Estado = models.ImportacionEstadoPendiente
+	r.trabajos[id] = t
+	return true, nil
+}
+
+// importadorFake marca duplicada cada décima fila y puede fallar al confirmar a partir de cierta fila.
+type importadorFake struct {
+	n           int
+	procesadas  []int
+	falloDesde  int // confirmar falla si ya se procesó esta fila (0 = nunca)
+	finalizados int
+}
+
+func (f *importadorFake) total() int                                 { return f.n }
+func (f *importadorFake) iniciar(_ *models.ImportacionTrabajo) error { return nil }
+
+func (f *importadorFake) procesar(i int, av *avanceImportacion) {
+	f.procesadas = append(f.procesadas, i)
+	if i%10 == 9 {
+		av.duplicados++
+		av.incidencia(models.ImportacionIncidencia{Fila: i + 2, Tipo: "DUPLICADO"})
+		return
+	}
+	av.procesados++
+}
+
+func (f *importadorFake) confirmar() error {
+	if f.falloDesde > 0 && len(f.procesadas) > 0 && f.procesadas[len(f.procesadas)-1] >= f.falloDesde {
+		return errors.New("sin conexión")
+	}
+	return nil
+}
+
+func (f *importadorFake) finalizar(_ *models.ImportacionTrabajo) { f.finalizados++ }
+
+func newImportacionServiceFake(t models.ImportacionTrabajo, imp *importadorFake) (*importacionTrabajoService, *importacionRepoFake) {
+	repo := &importacionRepoFake{trabajos: map[uint]models.ImportacionTrabajo{t.ID: t}}
+	svc := &importacionTrabajoService{
+		repo: repo,
+		preparadores: map[string]preparadorImportacion{
+			models.ImportacionTipoPersonas: func(_ *models.ImportacionTrabajo, _ []byte) (importadorFilas, error) { return imp, nil },
+		},
+	}
+	return svc, repo
+}
+
+func trabajoEnProceso(filaActual, procesados int) models.ImportacionTrabajo {
+	return models.ImportacionTrabajo{
+		ID: 7, Tipo: models.ImportacionTipoPersonas, Estado: models.ImportacionEstadoProcesando,
+		UserID: 3, Intentos: 1, FilaActual: filaActual, Procesados: procesados,
+	}
+}
+
+func TestImportacionEjecutar_guardaAvancePorTramosYCompleta(t *testing.T) {
+	imp := &importadorFake{n: 60}
+	svc, repo := newImportacionServiceFake(trabajoEnProceso(0, 0), imp)
+
+	svc.ejecutar(7)
+
+	got := repo.trabajos[7]
+	if got.Estado != models.ImportacionEstadoCompletado || got.FilaActual != 60 || got.TotalFilas != 60 {
+		t.Fatalf("trabajo = %+v", got)
+	}
+	if got.Procesados != 54 || got.Duplicados != 6 || got.Incidencias != 6 || len(repo.incidencias) != 6 {
+		t.Fatalf("contadores = %d/%d/%d, incidencias guardadas %d", got.Procesados, got.Duplicados, got.Incidencias, len(repo.incidencias))
+	}
+	if want := []int{0, 25, 50, 60}; len(repo.guardados) != len(want) || repo.guardados[1] != 25 || repo.guardados[2] != 50 {
+		t.Fatalf("guardados = %v, se esperaba %v", repo.guardados, want)
+	}
+	if got.ReclamadoHasta != nil || got.FinalizadoAt == nil || imp.finalizados != 1 {
+		t.Fatalf("reserva %v, finalizado %v, finalizar llamado %d veces", got.ReclamadoHasta, got.FinalizadoAt, imp.finalizados)
+	}
+}
+
+func TestImportacionEjecutar_reanudaDesdeFilaActual(t *testing.T) {
+	imp := &importadorFake{n: 60}
+	svc, repo := newImportacionServiceFake(trabajoEnProceso(50, 45), imp)
+
+	svc.ejecutar(7)
+
+	if len(imp.procesadas) != 10 || imp.procesadas[0] != 50 {
+		t.Fatalf("procesadas = %v, solo debían procesarse las filas 50..59", imp.procesadas)
+	}
+	if got := repo.trabajos[7]; got.Estado != models.ImportacionEstadoCompletado || got.Procesados != 54 {
+		t.Fatalf("trabajo = %+v", got)
+	}
+}
+
+func TestImportacionEjecutar_fallaYConservaUltimoGuardado(t *testing.T) {
+	imp := &importadorFake{n: 60, falloDesde: 30}
+	svc, repo := newImportacionServiceFake(trabajoEnProceso(0, 0), imp)
+
+	svc.ejecutar(7)
+
+	got := repo.trabajos[7]
+	if got.Estado != models.ImportacionEstadoFallido || got.UltimoError != "sin conexión" {
+		t.Fatalf("trabajo = %+v", got)
+	}
+	// El tramo 25..49 no se confirmó: al reanudar se repite desde la fila 25.
+	if got.FilaActual != 25 || got.Procesados != 23 || imp.finalizados != 0 {
+		t.Fatalf("fila actual %d, procesados %d, finalizar %d", got.FilaActual, got.Procesados, imp.finalizados)
+	}
+}
+
+func TestImportacionEjecutar_otraReplicaTomoElTrabajo(t *testing.T) {
+	imp := &importadorFake{n: 60}
+	svc, repo := newImportacionServiceFake(trabajoEnProceso(0, 0), imp)
+	trabajo := repo.trabajos[7]
+	av := &avanceImportacion{filas: 25, procesados: 25}
+	trabajo.Intentos = 0 // reserva de un intento anterior
+	if svc.guardarAvance(&trabajo, imp, av, false) {
+		t.Fatal("con la reserva vencida no debe guardar avance")
+	}
+	if repo.trabajos[7].FilaActual != 0 || trabajo.FilaActual != 0 {
+		t.Fatalf("no debía aplicar el avance: %+v", repo.trabajos[7])
+	}
+}
+
+func TestImportacionReanudar(t *testing.T) {
+	completado := trabajoEnProceso(60, 60)
+	completado.Estado = models.ImportacionEstadoCompletado
+	svc, _ := newImportacionServiceFake(completado, &importadorFake{})
+
+	if _, err := svc.Reanudar(7, 3, nil); !errors.Is(err, ErrImportacionNoReanudable) {
+		t.Fatalf("err = %v, se esperaba ErrImportacionNoReanudable", err)
+	}
+	if _, err := svc.Reanudar(7, 4, []string{"COORDINADOR"}); !errors.Is(err, ErrImportacionNoEncontrada) {
+		t.Fatalf("err = %v, se esperaba ErrImportacionNoEncontrada para otro usuario", err)
+	}
+	if _, err := svc.Get(7, 4, []string{"SUPER ADMINISTRADOR"}); err != nil {
+		t.Fatalf("el super administrador debe ver el trabajo: %v", err)
+	}
+}
+
+func TestImportacionTrabajoToResponse_porcentaje(t *testing.T) {
+	tr := trabajoEnProceso(30, 30)
+	tr.TotalFilas = 120
+	if out := importacionTrabajoToResponse(&tr); out.Porcentaje != 25 || out.Reanudable {
+		t.Fatalf("porcentaje %d, reanudable %v", out.Porcentaje, out.Reanudable)
+	}
+	tr.Estado = models.ImportacionEstadoFallido
+	if out := importacionTrabajoToResponse(&tr); !out.Reanudable {
+		t.Fatal("un trabajo fallido debe ser reanudable")
+	}
+}
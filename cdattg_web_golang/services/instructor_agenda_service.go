@@ -52,9 +52,10 @@ func (s *InstructorAgendaService) AgendaInstructor(instructorID uint, desde, has
 	if err != nil {
 		return nil, err
 	}
+	cal := nuevoCalendarioAgenda(d0, d1)
 	var eventos []dto.InstructorAgendaEvent
 	for _, asg := range assignments {
-		evs, err := s.expandirAsignacion(asg, d0, d1, nil)
+		evs, err := s.expandirAsignacion(asg, d0, d1, nil, cal)
 		if err != nil {
 			return nil, err
 		}
@@ -77,9 +78,10 @@ func (s *InstructorAgendaService) AgendaFicha(fichaID uint, desde, hasta string)
 	if err != nil {
 		return nil, err
 	}
+	cal := nuevoCalendarioAgenda(d0, d1)
 	var eventos []dto.InstructorAgendaEvent
 	for _, asg := range assignments {
-		evs, err := s.expandirAsignacion(asg, d0, d1, &fichaID)
+		evs, err := s.expandirAsignacion(asg, d0, d1, &fichaID, cal)
 		if err != nil {
 			return nil, err
 		}
@@ -88,10 +90,58 @@ func (s *InstructorAgendaService) AgendaFicha(fichaID uint, desde, hasta string)
 	return &dto.InstructorAgendaResponse{Desde: desde, Hasta: hasta, Eventos: eventos}, nil
 }
 
+// calendarioAgenda festivos y días sin formación por sede para marcar sesiones canceladas; se crea por solicitud
+// para que un día sin formación recién registrado se refleje en la siguiente consulta (o sincronización del .ics).
+type calendarioAgenda struct {
+	cal         *CalendarioFormacionService
+	desde       time.Time
+	hasta       time.Time
+	sedesCargas map[uint]bool
+}
+
+func nuevoCalendarioAgenda(desde, hasta time.Time) *calendarioAgenda {
+	cal := NewCalendarioFormacionService()
+	_ = cal.PrecargarFestivosEnRango(desde, hasta)
+	return &calendarioAgenda{cal: cal, desde: desde, hasta: hasta, sedesCargas: make(map[uint]bool)}
+}
+
+// motivoCancelacion motivo por el que no hay formación en la fecha ("" si la sesión se mantiene).
+func (c *calendarioAgenda) motivoCancelacion(sedeID uint, fecha string) string {
+	d, err := parseFechaLocal(fecha)
+	if err != nil {
+		return ""
+	}
+	if c.cal.EsDiaFestivoColombia(d) {
+		return "Festivo"
+	}
+	if sedeID == 0 {
+		return ""
+	}
+	if !c.sedesCargas[sedeID] {
+		_ = c.cal.PrecargarSinFormacionSede(sedeID, c.desde, c.hasta)
+		c.sedesCargas[sedeID] = true
+	}
+	if ok, motivo := c.cal.MotivoDiaSinFormacionSede(sedeID, d); ok {
+		return motivo
+	}
+	return ""
+}
+
+// marcarCancelacionesAgenda marca como canceladas las sesiones para las que motivo devuelve un texto.
+func marcarCancelacionesAgenda(eventos []dto.InstructorAgendaEvent, motivo func(fecha string) string) {
+	for i := range eventos {
+		if m := motivo(eventos[i].Fecha); m != "" {
+			eventos[i].Cancelado = true
+			eventos[i].MotivoCancelacion = m
+		}
+	}
+}
+
 type agendaContexto struct {
 	progNombre     string
 	sedeNombre     string
 	ambienteNombre string
+	jornadaNombre  string
 	instNombre     string
 	instDoc        string
 	vigInicio      *time.Time
@@ -128,6 +178,9 @@ func (s *InstructorAgendaService) cargarContextoAgenda(
 	if ficha.Sede != nil {
 		ctx.sedeNombre = ficha.Sede.Nombre
 	}
+	if ficha.Jornada != nil {
+		ctx.jornadaNombre = ficha.Jornada.Nombre
+	}
 	ctx.ambienteNombre = formatAmbienteRuta(ficha.Ambiente)
 	return ctx
 }
@@ -160,6 +213,7 @@ func (s *InstructorAgendaService) expandirAsignacion(
 	asg models.InstructorFichaCaracterizacion,
 	desde, hasta time.Time,
 	fichaFilter *uint,
+	cal *calendarioAgenda,
 ) ([]dto.InstructorAgendaEvent, error) {
 	ficha, err := s.fichaRepo.FindByID(asg.FichaID)
 	if err != nil || ficha == nil || !ficha.Status {
@@ -185,5 +239,15 @@ func (s *InstructorAgendaService) expandirAsignacion(
 	if err != nil {
 		return nil, err
 	}
-	return s.eventosEnRango(ficha, asg, desde, hasta, ctx, traslados), nil
+	eventos := s.eventosEnRango(ficha, asg, desde, hasta, ctx, traslados)
+	if cal != nil {
+		var sedeID uint
+		if ficha.SedeID != nil {
+			sedeID = *ficha.SedeID
+		}
+		marcarCancelacionesAgenda(eventos, func(fecha string) string {
+			return cal.motivoCancelacion(sedeID, fecha)
+		})
+	}
+	return eventos, nil
 }
@@ -319,13 +319,14 @@ func intersectarVigenciaFin(fichaFin, asgFin *time.Time) *time.Time {
 	return fichaFin
 }
 
-func colisionaConBloquesExistentes(
+// bloqueQueSolapa devuelve el primer bloque existente del mismo día cuyo horario y vigencia se cruzan.
+func bloqueQueSolapa(
 	diaID uint, hi, hf string,
 	vigInicio, vigFin *time.Time,
 	existing []bloqueSemanal,
-	fichaNum string,
-) error {
-	for _, ex := range existing {
+) *bloqueSemanal {
+	for i := range existing {
+		ex := &existing[i]
 		if ex.diaFormacionID != diaID {
 			continue
 		}
@@ -335,6 +336,18 @@ func colisionaConBloquesExistentes(
 		if !fechasVigenciaSeSolapan(vigInicio, vigFin, ex.vigenciaInicio, ex.vigenciaFin) {
 			continue
 		}
+		return ex
+	}
+	return nil
+}
+
+func colisionaConBloquesExistentes(
+	diaID uint, hi, hf string,
+	vigInicio, vigFin *time.Time,
+	existing []bloqueSemanal,
+	fichaNum string,
+) error {
+	if ex := bloqueQueSolapa(diaID, hi, hf, vigInicio, vigFin, existing); ex != nil {
 		return fmt.Errorf("%s: el %s %s–%s en ficha %s solapa con ficha %s (%s–%s)",
 			errMsgColisionHorarioInstructor, nombreDia(diaID), hi, hf, fichaNum, ex.fichaNum, ex.horaInicio, ex.horaFin)
 	}
@@ -34,7 +34,8 @@ func (s *stubInstFichaRepo) Delete(uint) error
 func (s *stubInstFichaRepo) DeleteByFichaIDAndInstructorID(_, _ uint) error      { return nil }
 
 type stubFichaRepoHorario struct {
-	ficha *models.FichaCaracterizacion
+	ficha       *models.FichaCaracterizacion
+	porAmbiente []models.FichaCaracterizacion
 }
 
 func (s *stubFichaRepoHorario) FindByID(id uint) (*models.FichaCaracterizacion, error) {
@@ -60,6 +61,9 @@ func (s *stubFichaRepoHorario) FindActivasParaHoyConJornada(time.Time) ([]models
 func (s *stubFichaRepoHorario) FindActivasSolapandoRango(time.Time, time.Time, []uint, string, bool) ([]models.FichaCaracterizacion, error) {
 	return nil, nil
 }
+func (s *stubFichaRepoHorario) FindActivasByAmbienteID(uint) ([]models.FichaCaracterizacion, error) {
+	return s.porAmbiente, nil
+}
 func (s *stubFichaRepoHorario) Search(string) ([]models.FichaCaracterizacion, error) { return nil, nil }
 func (s *stubFichaRepoHorario) Create(*models.FichaCaracterizacion) error           { return nil }
 func (s *stubFichaRepoHorario) Update(*models.FichaCaracterizacion) error           { return nil }
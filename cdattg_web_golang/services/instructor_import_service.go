@@ -1,8 +1,8 @@
 package services
 
 import (
-	"bytes"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -10,7 +10,6 @@ import (
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
-	"github.com/xuri/excelize/v2"
 )
 
 // instructorImportHeaders mapea nombres de columnas del Excel (normalizados) al canonónico.
@@ -47,9 +46,9 @@ var tipoDocNombreExcelANombreBD = map[string]string{
 	"sin identificacion":       "SIN IDENTIFICACIÓN",
 }
 
-// InstructorImportService define la importación masiva de instructores desde Excel.
+// InstructorImportService historial de importaciones masivas de instructores. La importación corre como trabajo en
+// segundo plano (ImportacionTrabajoService).
 type InstructorImportService interface {
-	ImportFromExcel(fileBytes []byte, filename string, userID uint, regionalIDDefault *uint) (*ImportResult, error)
 	ListImports(limit int) ([]InstructorImportLogItem, error)
 }
 
@@ -60,6 +59,7 @@ type instructorImportService struct {
 	instructorSvc  InstructorService
 	catalogoRepo   repositories.CatalogoRepository
 	logRepo        repositories.InstructorImportLogRepository
+	notificaciones NotificacionUsuarioService
 }
 
 // InstructorImportLogItem es un ítem del historial de importaciones de instructores.
@@ -76,6 +76,10 @@ type InstructorImportLogItem struct {
 
 // NewInstructorImportService crea el servicio de importación de instructores.
 func NewInstructorImportService() InstructorImportService {
+	return newInstructorImportService(NewNotificacionUsuarioService())
+}
+
+func newInstructorImportService(notificaciones NotificacionUsuarioService) *instructorImportService {
 	return &instructorImportService{
 		personaRepo:    repositories.NewPersonaRepository(),
 		personaService: NewPersonaService(),
@@ -83,6 +87,7 @@ func NewInstructorImportService() InstructorImportService {
 		instructorSvc:  NewInstructorService(),
 		catalogoRepo:   repositories.NewCatalogoRepository(),
 		logRepo:        repositories.NewInstructorImportLogRepository(),
+		notificaciones: notificaciones,
 	}
 }
 
@@ -91,6 +96,8 @@ type instructorImportRowResult struct {
 	duplicate    bool
 	processed    bool
 	newPersonaID uint
+	// detalle motivo del error o duplicado, para el Excel de incidencias.
+	detalle string
 }
 
 func isPersonaDuplicadaErr(err error) bool {
@@ -107,11 +114,13 @@ func (s *instructorImportService) tryImportInstructorRow(row []string, colIndex
 	req := s.rowToPersonaRequest(row, colIndex, tipoByKey, generoByKey)
 	if req == nil {
 		r.err = true
+		r.detalle = "Faltan la identificación o los nombres y apellidos"
 		return r
 	}
 	numeroDoc := strings.TrimSpace(req.NumeroDocumento)
 	if numeroDoc == "" {
 		r.err = true
+		r.detalle = "Falta la identificación"
 		return r
 	}
 
@@ -125,6 +134,7 @@ func (s *instructorImportService) tryImportInstructorRow(row []string, colIndex
 			} else {
 				r.err = true
 			}
+			r.detalle = createErr.Error()
 			return r
 		}
 		personaID = resp.ID
@@ -136,6 +146,7 @@ func (s *instructorImportService) tryImportInstructorRow(row []string, colIndex
 	existingInst, _ := s.instructorRepo.FindByPersonaID(personaID)
 	if existingInst != nil {
 		r.duplicate = true
+		r.detalle = "La persona ya está registrada como instructor"
 		return r
 	}
 
@@ -145,29 +156,29 @@ func (s *instructorImportService) tryImportInstructorRow(row []string, colIndex
 	})
 	if createErr != nil {
 		r.err = true
+		r.detalle = createErr.Error()
 		return r
 	}
 	r.processed = true
 	return r
 }
 
-// ImportFromExcel procesa un archivo Excel y crea/vincula personas e instructores.
-// regionalIDDefault se asigna a cada instructor nuevo (ej. regional GUAVIARE); si es nil se deja null.
-func (s *instructorImportService) ImportFromExcel(fileBytes []byte, filename string, userID uint, regionalIDDefault *uint) (*ImportResult, error) {
-	f, err := excelize.OpenReader(bytes.NewReader(fileBytes))
-	if err != nil {
-		return nil, fmt.Errorf("archivo Excel inválido: %w", err)
-	}
-	defer f.Close()
-
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return nil, fmt.Errorf("el archivo no contiene hojas")
-	}
+// instructorImportacion importación de instructores fila por fila. regionalID se asigna a cada instructor nuevo
+// (ej. regional GUAVIARE); si es nil se deja null.
+type instructorImportacion struct {
+	s                 *instructorImportService
+	rows              [][]string
+	colIndex          map[string]int
+	tipoByKey         map[string]uint
+	generoByKey       map[string]uint
+	regionalID        *uint
+	pendientesUsuario []uint
+}
 
-	rows, err := f.GetRows(sheetName)
-	if err != nil || len(rows) < 2 {
-		return nil, fmt.Errorf("el archivo debe tener al menos encabezados y una fila de datos")
+func (s *instructorImportService) prepararImportacion(t *models.ImportacionTrabajo, fileBytes []byte) (importadorFilas, error) {
+	rows, err := leerFilasExcel(fileBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	colIndex, err := s.buildColumnIndex(rows[0])
@@ -183,46 +194,77 @@ func (s *instructorImportService) ImportFromExcel(fileBytes []byte, filename str
 	if err != nil {
 		return nil, err
 	}
+	return &instructorImportacion{
+		s:           s,
+		rows:        rows,
+		colIndex:    colIndex,
+		tipoByKey:   tipoByKey,
+		generoByKey: generoByKey,
+		regionalID:  t.RegionalID,
+	}, nil
+}
+
+func (p *instructorImportacion) total() int { return len(p.rows) - 1 }
 
-	var processed, duplicates, errorsCount int
-	var createdPersonaIDs []uint
+func (p *instructorImportacion) iniciar(*models.ImportacionTrabajo) error { return nil }
 
-	for i := 1; i < len(rows); i++ {
-		res := s.tryImportInstructorRow(rows[i], colIndex, tipoByKey, generoByKey, regionalIDDefault)
-		switch {
-		case res.err:
-			errorsCount++
-		case res.duplicate:
-			duplicates++
-		case res.processed:
-			processed++
+func (p *instructorImportacion) procesar(i int, av *avanceImportacion) {
+	row := p.rows[i+1]
+	res := p.s.tryImportInstructorRow(row, p.colIndex, p.tipoByKey, p.generoByKey, p.regionalID)
+	switch {
+	case res.err:
+		av.errores++
+	case res.duplicate:
+		av.duplicados++
+	case res.processed:
+		av.procesados++
+	}
+	if res.newPersonaID != 0 {
+		p.pendientesUsuario = append(p.pendientesUsuario, res.newPersonaID)
+	}
+	if res.err || res.duplicate {
+		inc := models.ImportacionIncidencia{Fila: i + 2, Tipo: "ERROR", Detalle: truncarRunes(res.detalle, 1000)}
+		if res.duplicate {
+			inc.Tipo = "DUPLICADO"
 		}
-		if res.newPersonaID != 0 {
-			createdPersonaIDs = append(createdPersonaIDs, res.newPersonaID)
+		get := func(key string) string {
+			if idx, ok := p.colIndex[key]; ok && idx < len(row) {
+				return strings.TrimSpace(row[idx])
+			}
+			return ""
 		}
+		inc.Documento = get("identificacion")
+		inc.Nombre = truncarRunes(get("nombres_completo"), 255)
+		inc.Correo = get("correo")
+		inc.Celular = get("numero_telefono")
+		av.incidencia(inc)
 	}
+}
 
-	if len(createdPersonaIDs) > 0 {
-		_ = s.personaService.EnsureUsersForPersonas(createdPersonaIDs)
+func (p *instructorImportacion) confirmar() error {
+	if len(p.pendientesUsuario) == 0 {
+		return nil
+	}
+	if err := p.s.personaService.EnsureUsersForPersonas(p.pendientesUsuario); err != nil {
+		log.Printf("[importaciones] usuarios de instructores importados: %v", err)
 	}
+	p.pendientesUsuario = nil
+	return nil
+}
 
+func (p *instructorImportacion) finalizar(t *models.ImportacionTrabajo) {
 	logEntry := &models.InstructorImportLog{
-		Filename:        filename,
-		UserID:          userID,
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errorsCount,
+		Filename:        t.Filename,
+		UserID:          t.UserID,
+		ProcessedCount:  t.Procesados,
+		DuplicatesCount: t.Duplicados,
+		ErrorCount:      t.Errores,
 		Status:          "completado",
 		CreatedAt:       time.Now(),
 	}
-	_ = s.logRepo.Create(logEntry)
-
-	return &ImportResult{
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errorsCount,
-		Status:          "completado",
-	}, nil
+	_ = p.s.logRepo.Create(logEntry)
+	notificarImportacionTerminada(p.s.notificaciones, t.UserID, "Importación de instructores terminada", rutaImportacionTrabajo(t),
+		t.Filename, t.Procesados, t.Duplicados, t.Errores)
 }
 
 func (s *instructorImportService) ListImports(limit int) ([]InstructorImportLogItem, error) {
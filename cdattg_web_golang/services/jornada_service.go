@@ -72,11 +72,15 @@ func (s *JornadaService) Create(req dto.JornadaCreateRequest) (*dto.JornadaAdmin
 	return s.toAdminItem(j)
 }
 
-func (s *JornadaService) Update(id uint, req dto.JornadaUpdateRequest) (*dto.JornadaUpdateResponse, error) {
+func (s *JornadaService) Update(actor dto.Actor, id uint, req dto.JornadaUpdateRequest) (*dto.JornadaUpdateResponse, error) {
 	j, err := s.jornadaRepo.FindByID(id)
 	if err != nil {
 		return nil, errors.New("jornada no encontrada")
 	}
+	antes, err := s.toAdminItem(j)
+	if err != nil {
+		return nil, err
+	}
 	oldPlantilla, err := s.bloqueRepo.FindByJornadaID(id)
 	if err != nil {
 		return nil, err
@@ -110,9 +114,10 @@ func (s *JornadaService) Update(id uint, req dto.JornadaUpdateRequest) (*dto.Jor
 	if err != nil {
 		return nil, err
 	}
+	auditar(actor, AccionJornadaActualizar, tablaJornadas, id, antes, item)
 	resp := &dto.JornadaUpdateResponse{JornadaAdminItem: *item}
 	if req.PropagarFichas == nil || *req.PropagarFichas {
-		prop, propErr := s.propagarPlantilla(id, oldPlantilla)
+		prop, propErr := s.propagarPlantilla(actor, id, oldPlantilla)
 		if propErr != nil {
 			return nil, propErr
 		}
@@ -121,7 +126,7 @@ func (s *JornadaService) Update(id uint, req dto.JornadaUpdateRequest) (*dto.Jor
 	return resp, nil
 }
 
-func (s *JornadaService) Propagar(id uint) (*dto.JornadaPropagateResult, error) {
+func (s *JornadaService) Propagar(actor dto.Actor, id uint) (*dto.JornadaPropagateResult, error) {
 	if _, err := s.jornadaRepo.FindByID(id); err != nil {
 		return nil, errors.New("jornada no encontrada")
 	}
@@ -132,14 +137,14 @@ func (s *JornadaService) Propagar(id uint) (*dto.JornadaPropagateResult, error)
 	if len(plantilla) == 0 {
 		return nil, errors.New("la jornada no tiene bloques definidos")
 	}
-	prop, err := s.propagarPlantilla(id, plantilla)
+	prop, err := s.propagarPlantilla(actor, id, plantilla)
 	if err != nil {
 		return nil, err
 	}
 	return &prop, nil
 }
 
-func (s *JornadaService) propagarPlantilla(jornadaID uint, oldPlantilla []models.JornadaBloque) (dto.JornadaPropagateResult, error) {
+func (s *JornadaService) propagarPlantilla(actor dto.Actor, jornadaID uint, oldPlantilla []models.JornadaBloque) (dto.JornadaPropagateResult, error) {
 	newPlantilla, err := s.bloqueRepo.FindByJornadaID(jornadaID)
 	if err != nil {
 		return dto.JornadaPropagateResult{}, err
@@ -151,7 +156,12 @@ func (s *JornadaService) propagarPlantilla(jornadaID uint, oldPlantilla []models
 	if len(fichaIDs) == 0 {
 		return dto.JornadaPropagateResult{}, nil
 	}
-	return PropagarPlantillaAFichas(s.fichaDiasRepo, fichaIDs, jornadaID, newPlantilla, oldPlantilla), nil
+	result := PropagarPlantillaAFichas(s.fichaDiasRepo, fichaIDs, jornadaID, newPlantilla, oldPlantilla)
+	auditar(actor, AccionJornadaPropagar, tablaJornadas, jornadaID, nil, map[string]interface{}{
+		"fichas_evaluadas": fichaIDs,
+		"resultado":        result,
+	})
+	return result, nil
 }
 
 func (s *JornadaService) Delete(id uint) error {
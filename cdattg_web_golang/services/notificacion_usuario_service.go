@@ -0,0 +1,375 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+const (
+	// diasRetencionNotificacionesLeidas las leídas se eliminan pasado este tiempo; las no leídas se conservan.
+	diasRetencionNotificacionesLeidas = 90
+
+	EventoNotificacionNueva  = "nueva"
+	EventoNotificacionLeidas = "leidas"
+
+	rolInstructorNotificacion = "INSTRUCTOR"
+
+	// Rutas del frontend a las que apunta cada notificación.
+	rutaNotificacionFichasInstructor = "/asistencia/fichas"
+	rutaNotificacionEleccion         = "/eleccion-aprendices"
+	rutaNotificacionEleccionAdmin    = "/administracion/elecciones/"
+	rutaNotificacionInasistencias    = "/mis-inasistencias"
+	rutaNotificacionImportPersonas   = "/personas/importar"
+	rutaNotificacionImportInstructor = "/instructores/importar"
+	rutaNotificacionImportProgramas  = "/programas/importar"
+	rutaNotificacionImportFichas     = "/fichas"
+	rutaNotificacionFicha            = "/fichas/"
+)
+
+var (
+	ErrNotificacionNoEncontrada = errors.New("notificación no encontrada o ya leída")
+	ErrNotificacionTipoNoAplica = errors.New("el tipo de notificación no aplica a los roles del usuario")
+)
+
+// tipoNotificacion descripción de cada tipo para las preferencias. Sin roles aplica a todos.
+type tipoNotificacion struct {
+	tipo        string
+	roles       []string
+	nombre      string
+	descripcion string
+}
+
+var tiposNotificacion = []tipoNotificacion{
+	{
+		tipo:        models.NotificacionTipoAsignacionFicha,
+		roles:       []string{rolInstructorNotificacion},
+		nombre:      "Asignación a fichas",
+		descripcion: "Cuando lo asignan a una ficha o lo retiran de ella.",
+	},
+	{
+		tipo:        models.NotificacionTipoTrasladoDia,
+		roles:       []string{rolInstructorNotificacion},
+		nombre:      "Traslados de día",
+		descripcion: "Cuando coordinación traslada un día de formación suyo a otro instructor o desde otro instructor.",
+	},
+	{
+		tipo:        models.NotificacionTipoEleccionPlancha,
+		roles:       []string{rolAprendizCasbin},
+		nombre:      "Planchas de elección",
+		descripcion: "Cuando un compañero lo propone en una plancha y falta su confirmación.",
+	},
+	{
+		tipo:        models.NotificacionTipoEleccionVotacion,
+		roles:       []string{rolAprendizCasbin},
+		nombre:      "Recordatorios de votación",
+		descripcion: "Cuando la votación de representante está abierta y usted aún no ha votado.",
+	},
+	{
+		tipo:        models.NotificacionTipoEleccionEmpate,
+		roles:       []string{"SUPER ADMINISTRADOR", "ADMINISTRADOR", "COORDINADOR"},
+		nombre:      "Empates electorales",
+		descripcion: "Cuando el escrutinio de una elección de su regional termina empatado y falta el desempate.",
+	},
+	{
+		tipo:        models.NotificacionTipoInasistencia,
+		roles:       []string{rolAprendizCasbin},
+		nombre:      "Inasistencias",
+		descripcion: "Cuando se cierra una sesión de asistencia sin registro de su ingreso.",
+	},
+	{
+		tipo:        models.NotificacionTipoImportacion,
+		roles:       []string{"SUPER ADMINISTRADOR", "ADMINISTRADOR", "COORDINADOR"},
+		nombre:      "Importaciones",
+		descripcion: "Cuando termina una importación de Excel que usted inició.",
+	},
+}
+
+// NotificacionNueva aviso a crear para uno o varios usuarios.
+type NotificacionNueva struct {
+	Tipo    string
+	Titulo  string
+	Mensaje string
+	Enlace  string
+}
+
+// EntregaNotificaciones reparte en tiempo real un evento al usuario (canal WebSocket).
+type EntregaNotificaciones func(userID uint, ev dto.NotificacionEvento)
+
+var (
+	entregaNotificacionesMu sync.RWMutex
+	entregaNotificaciones   EntregaNotificaciones
+)
+
+// RegistrarEntregaNotificaciones conecta el canal en tiempo real; lo hace el paquete handlers al arrancar. Sin
+// entrega las notificaciones solo se ven al consultar la API.
+func RegistrarEntregaNotificaciones(f EntregaNotificaciones) {
+	entregaNotificacionesMu.Lock()
+	defer entregaNotificacionesMu.Unlock()
+	entregaNotificaciones = f
+}
+
+func entregarNotificacion(userID uint, ev dto.NotificacionEvento) {
+	entregaNotificacionesMu.RLock()
+	f := entregaNotificaciones
+	entregaNotificacionesMu.RUnlock()
+	if f != nil {
+		f(userID, ev)
+	}
+}
+
+// NotificacionUsuarioService centro de notificaciones en la aplicación.
+type NotificacionUsuarioService interface {
+	// Notificar crea la notificación para cada usuario que no desactivó el tipo y la entrega en tiempo real. Los
+	// errores solo se registran: notificar nunca hace fallar la operación que lo origina.
+	Notificar(userIDs []uint, n NotificacionNueva)
+	NotificarInstructores(instructorIDs []uint, n NotificacionNueva)
+	NotificarAprendices(aprendizIDs []uint, n NotificacionNueva)
+	List(userID uint, soloNoLeidas bool, page, pageSize int) ([]dto.NotificacionUsuarioResponse, int64, error)
+	CountNoLeidas(userID uint) (int64, error)
+	MarcarLeida(userID, id uint) (int64, error)
+	MarcarTodasLeidas(userID uint) (int64, error)
+	Preferencias(userID uint) ([]dto.NotificacionPreferenciaItem, error)
+	ActualizarPreferencia(userID uint, req dto.ActualizarNotificacionPreferenciaRequest) ([]dto.NotificacionPreferenciaItem, error)
+	// PurgarLeidasAntiguas elimina las notificaciones leídas hace más de 90 días.
+	PurgarLeidasAntiguas() error
+}
+
+type notificacionUsuarioService struct {
+	repo repositories.NotificacionUsuarioRepository
+	// rolesDeUsuario roles Casbin del usuario.
+	rolesDeUsuario func(userID uint) ([]string, error)
+}
+
+func NewNotificacionUsuarioService() NotificacionUsuarioService {
+	return &notificacionUsuarioService{
+		repo:           repositories.NewNotificacionUsuarioRepository(),
+		rolesDeUsuario: rolesCasbinDeUsuario,
+	}
+}
+
+func (s *notificacionUsuarioService) Notificar(userIDs []uint, n NotificacionNueva) {
+	destino := uniqueUints(userIDs)
+	if len(destino) == 0 {
+		return
+	}
+	desactivados, err := s.repo.MapDesactivados(n.Tipo, destino)
+	if err != nil {
+		log.Printf("[notificaciones] preferencias de %s: %v", n.Tipo, err)
+		return
+	}
+	ahora := utils.Now()
+	list := make([]models.NotificacionUsuario, 0, len(destino))
+	for _, uid := range destino {
+		if desactivados[uid] {
+			continue
+		}
+		list = append(list, models.NotificacionUsuario{
+			UserID:    uid,
+			Tipo:      n.Tipo,
+			Titulo:    truncarRunes(n.Titulo, 255),
+			Mensaje:   n.Mensaje,
+			Enlace:    truncarRunes(n.Enlace, 500),
+			CreatedAt: ahora,
+		})
+	}
+	if err := s.repo.CreateBatch(list); err != nil {
+		log.Printf("[notificaciones] guardando %s: %v", n.Tipo, err)
+		return
+	}
+	for i := range list {
+		resp := notificacionToResponse(&list[i])
+		s.entregar(list[i].UserID, dto.NotificacionEvento{Type: EventoNotificacionNueva, Notificacion: &resp})
+	}
+}
+
+// entregar completa el conteo de no leídas y envía el evento por el canal en tiempo real.
+func (s *notificacionUsuarioService) entregar(userID uint, ev dto.NotificacionEvento) {
+	n, err := s.repo.CountNoLeidas(userID)
+	if err != nil {
+		log.Printf("[notificaciones] conteo de no leídas del usuario %d: %v", userID, err)
+		return
+	}
+	ev.NoLeidas = n
+	entregarNotificacion(userID, ev)
+}
+
+func (s *notificacionUsuarioService) NotificarInstructores(instructorIDs []uint, n NotificacionNueva) {
+	userIDs, err := s.repo.UserIDsPorInstructores(uniqueUints(instructorIDs))
+	if err != nil {
+		log.Printf("[notificaciones] usuarios de instructores (%s): %v", n.Tipo, err)
+		return
+	}
+	s.Notificar(userIDs, n)
+}
+
+func (s *notificacionUsuarioService) NotificarAprendices(aprendizIDs []uint, n NotificacionNueva) {
+	userIDs, err := s.repo.UserIDsPorAprendices(uniqueUints(aprendizIDs))
+	if err != nil {
+		log.Printf("[notificaciones] usuarios de aprendices (%s): %v", n.Tipo, err)
+		return
+	}
+	s.Notificar(userIDs, n)
+}
+
+func (s *notificacionUsuarioService) List(userID uint, soloNoLeidas bool, page, pageSize int) ([]dto.NotificacionUsuarioResponse, int64, error) {
+	list, total, err := s.repo.List(userID, soloNoLeidas, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]dto.NotificacionUsuarioResponse, len(list))
+	for i := range list {
+		out[i] = notificacionToResponse(&list[i])
+	}
+	return out, total, nil
+}
+
+func (s *notificacionUsuarioService) CountNoLeidas(userID uint) (int64, error) {
+	return s.repo.CountNoLeidas(userID)
+}
+
+func (s *notificacionUsuarioService) MarcarLeida(userID, id uint) (int64, error) {
+	ok, err := s.repo.MarcarLeida(id, userID, utils.Now())
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrNotificacionNoEncontrada
+	}
+	n, err := s.repo.CountNoLeidas(userID)
+	if err != nil {
+		return 0, err
+	}
+	entregarNotificacion(userID, dto.NotificacionEvento{Type: EventoNotificacionLeidas, IDs: []uint{id}, NoLeidas: n})
+	return n, nil
+}
+
+func (s *notificacionUsuarioService) MarcarTodasLeidas(userID uint) (int64, error) {
+	marcadas, err := s.repo.MarcarTodasLeidas(userID, utils.Now())
+	if err != nil {
+		return 0, err
+	}
+	if marcadas > 0 {
+		entregarNotificacion(userID, dto.NotificacionEvento{Type: EventoNotificacionLeidas, NoLeidas: 0})
+	}
+	return s.repo.CountNoLeidas(userID)
+}
+
+func (s *notificacionUsuarioService) Preferencias(userID uint) ([]dto.NotificacionPreferenciaItem, error) {
+	roles, err := s.rolesDeUsuario(userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.repo.ListPreferencias(userID)
+	if err != nil {
+		return nil, err
+	}
+	activo := make(map[string]bool, len(prefs))
+	for _, p := range prefs {
+		activo[p.Tipo] = p.Activo
+	}
+	out := []dto.NotificacionPreferenciaItem{}
+	for _, t := range tiposNotificacion {
+		if !aplicaTipoNotificacion(t, roles) {
+			continue
+		}
+		a, ok := activo[t.tipo]
+		out = append(out, dto.NotificacionPreferenciaItem{
+			Tipo:        t.tipo,
+			Nombre:      t.nombre,
+			Descripcion: t.descripcion,
+			Activo:      !ok || a,
+		})
+	}
+	return out, nil
+}
+
+func (s *notificacionUsuarioService) ActualizarPreferencia(userID uint, req dto.ActualizarNotificacionPreferenciaRequest) ([]dto.NotificacionPreferenciaItem, error) {
+	roles, err := s.rolesDeUsuario(userID)
+	if err != nil {
+		return nil, err
+	}
+	aplica := false
+	for _, t := range tiposNotificacion {
+		if t.tipo == req.Tipo && aplicaTipoNotificacion(t, roles) {
+			aplica = true
+			break
+		}
+	}
+	if !aplica {
+		return nil, ErrNotificacionTipoNoAplica
+	}
+	pref := &models.NotificacionPreferencia{UserID: userID, Tipo: req.Tipo, Activo: *req.Activo, UpdatedAt: utils.Now()}
+	if err := s.repo.GuardarPreferencia(pref); err != nil {
+		return nil, err
+	}
+	return s.Preferencias(userID)
+}
+
+func (s *notificacionUsuarioService) PurgarLeidasAntiguas() error {
+	n, err := s.repo.DeleteLeidasAntesDe(utils.Now().AddDate(0, 0, -diasRetencionNotificacionesLeidas))
+	if err != nil {
+		return fmt.Errorf("limpieza de notificaciones: %w", err)
+	}
+	if n > 0 {
+		log.Printf("[notificaciones] notificaciones leídas eliminadas: %d", n)
+	}
+	return nil
+}
+
+func aplicaTipoNotificacion(t tipoNotificacion, roles []string) bool {
+	if len(t.roles) == 0 {
+		return true
+	}
+	for _, r := range t.roles {
+		if hasRole(roles, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func notificacionToResponse(n *models.NotificacionUsuario) dto.NotificacionUsuarioResponse {
+	return dto.NotificacionUsuarioResponse{
+		ID:        n.ID,
+		Tipo:      n.Tipo,
+		Titulo:    n.Titulo,
+		Mensaje:   n.Mensaje,
+		Enlace:    n.Enlace,
+		LeidaEn:   n.LeidaEn,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func uniqueUints(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// notificarImportacionTerminada avisa al usuario que inició una importación de Excel el resultado final.
+func notificarImportacionTerminada(svc NotificacionUsuarioService, userID uint, titulo, enlace, filename string, procesados, duplicados, errores int) {
+	if svc == nil || userID == 0 {
+		return
+	}
+	svc.Notificar([]uint{userID}, NotificacionNueva{
+		Tipo:   models.NotificacionTipoImportacion,
+		Titulo: titulo,
+		Mensaje: fmt.Sprintf("El archivo %s terminó de procesarse: %d registros importados, %d duplicados y %d con error.",
+			filename, procesados, duplicados, errores),
+		Enlace: enlace,
+	})
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+// notificacionRepoFake guarda en memoria lo necesario para Notificar y las preferencias.
+type notificacionRepoFake struct {
+	repositories.NotificacionUsuarioRepository
+	creadas      []models.NotificacionUsuario
+	desactivados map[uint]bool
+	prefs        []models.NotificacionPreferencia
+}
+
+func (r *notificacionRepoFake) CreateBatch(list []models.NotificacionUsuario) error {
+	for i := range list {
+		list[i].ID = uint(len(r.creadas) + 1)
+		r.creadas = append(r.creadas, list[i])
+	}
+	return nil
+}
+
+func (r *notificacionRepoFake) MapDesactivados(_ string, _ []uint) (map[uint]bool, error) {
+	return r.desactivados, nil
+}
+
+func (r *notificacionRepoFake) CountNoLeidas(userID uint) (int64, error) {
+	var n int64
+	for _, c := range r.creadas {
+		if c.UserID == userID && c.LeidaEn == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *notificacionRepoFake) ListPreferencias(_ uint) ([]models.NotificacionPreferencia, error) {
+	return r.prefs, nil
+}
+
+func (r *notificacionRepoFake) GuardarPreferencia(p *models.NotificacionPreferencia) error {
+	r.prefs = append(r.prefs, *p)
+	return nil
+}
+
+func TestNotificar_respetaPreferenciasYEntregaEnVivo(t *testing.T) {
+	repo := &notificacionRepoFake{desactivados: map[uint]bool{3: true}}
+	svc := &notificacionUsuarioService{repo: repo}
+	entregados := map[uint]dto.NotificacionEvento{}
+	RegistrarEntregaNotificaciones(func(userID uint, ev dto.NotificacionEvento) { entregados[userID] = ev })
+	defer RegistrarEntregaNotificaciones(nil)
+
+	svc.Notificar([]uint{2, 3, 2, 0, 5}, NotificacionNueva{
+		Tipo:   models.NotificacionTipoAsignacionFicha,
+		Titulo: "Asignado a la ficha 2500001",
+		Enlace: rutaNotificacionFichasInstructor,
+	})
+
+	if len(repo.creadas) != 2 || repo.creadas[0].UserID != 2 || repo.creadas[1].UserID != 5 {
+		t.Fatalf("creadas = %+v", repo.creadas)
+	}
+	if _, ok := entregados[3]; ok {
+		t.Fatal("el usuario 3 desactivó el tipo y no debe recibir el evento")
+	}
+	ev, ok := entregados[5]
+	if !ok || ev.Type != EventoNotificacionNueva || ev.Notificacion == nil || ev.NoLeidas != 1 {
+		t.Fatalf("evento del usuario 5 = %+v", ev)
+	}
+	if ev.Notificacion.Enlace != rutaNotificacionFichasInstructor {
+		t.Fatalf("enlace = %q", ev.Notificacion.Enlace)
+	}
+}
+
+func TestPreferenciasNotificacion_segunRol(t *testing.T) {
+	repo := &notificacionRepoFake{prefs: []models.NotificacionPreferencia{
+		{UserID: 7, Tipo: models.NotificacionTipoInasistencia, Activo: false},
+	}}
+	svc := &notificacionUsuarioService{
+		repo:           repo,
+		rolesDeUsuario: func(uint) ([]string, error) { return []string{rolAprendizCasbin}, nil },
+	}
+
+	prefs, err := svc.Preferencias(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	activo := map[string]bool{}
+	for _, p := range prefs {
+		activo[p.Tipo] = p.Activo
+	}
+	if len(activo) != 3 || !activo[models.NotificacionTipoEleccionPlancha] || !activo[models.NotificacionTipoEleccionVotacion] || activo[models.NotificacionTipoInasistencia] {
+		t.Fatalf("preferencias del aprendiz = %+v", prefs)
+	}
+
+	no := false
+	_, err = svc.ActualizarPreferencia(7, dto.ActualizarNotificacionPreferenciaRequest{Tipo: models.NotificacionTipoTrasladoDia, Activo: &no})
+	if !errors.Is(err, ErrNotificacionTipoNoAplica) {
+		t.Fatalf("err = %v, want ErrNotificacionTipoNoAplica", err)
+	}
+}
+
+func TestMensajeTrasladoDia(t *testing.T) {
+	permanente := mensajeTrasladoDia(dto.TrasladarDiaRequest{
+		Modo: TrasladoModoPermanente, DiaOrigenID: 1, DiaDestinoID: 3, Motivo: " cruce de horario ",
+	})
+	if !strings.Contains(permanente, "lunes") || !strings.Contains(permanente, "miércoles") || !strings.HasSuffix(permanente, "Motivo: cruce de horario") {
+		t.Fatalf("mensaje permanente = %q", permanente)
+	}
+	fechas := mensajeTrasladoDia(dto.TrasladarDiaRequest{
+		Modo:        TrasladoModoFechas,
+		ParesFechas: []dto.TrasladoParFecha{{FechaOrigen: "2026-03-02", FechaDestino: "2026-03-04"}},
+		Motivo:      "capacitación",
+	})
+	if !strings.Contains(fechas, "2026-03-02 → 2026-03-04") {
+		t.Fatalf("mensaje por fechas = %q", fechas)
+	}
+}
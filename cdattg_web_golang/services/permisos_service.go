@@ -16,19 +16,20 @@ const errUsuarioNoEncontrado = "usuario no encontrado"
 type PermisosService interface {
 	ListUsuarios(offset, limit int, search string) ([]dto.UsuarioListItem, int64, error)
 	GetPermisosByUserID(userID uint) (*dto.UsuarioPermisosResponse, error)
-	AsignarPermisoDirecto(userID uint, obj, act string) error
-	QuitarPermisoDirecto(userID uint, obj, act string) error
-	SetRoles(userID uint, roles []string) error
-	ToggleEstado(userID uint) error
+	AsignarPermisoDirecto(actor dto.Actor, userID uint, obj, act string) error
+	QuitarPermisoDirecto(actor dto.Actor, userID uint, obj, act string) error
+	SetRoles(actor dto.Actor, userID uint, roles []string) error
+	ToggleEstado(actor dto.Actor, userID uint) error
 	Definiciones() dto.DefinicionesPermisosResponse
 	GetUsuarioRegionales(userID uint) (*dto.UsuarioRegionalesResponse, error)
-	SetUsuarioRegionales(userID uint, regionalIDs []uint) error
+	SetUsuarioRegionales(actor dto.Actor, userID uint, regionalIDs []uint) error
 }
 
 type permisosService struct {
 	userRepo            repositories.UserRepository
 	usuarioRegionalRepo repositories.UsuarioRegionalRepository
 	catalogoRepo        repositories.CatalogoRepository
+	sesionRepo          repositories.SesionRepository
 }
 
 func NewPermisosService() PermisosService {
@@ -36,6 +37,7 @@ func NewPermisosService() PermisosService {
 		userRepo:            repositories.NewUserRepository(),
 		usuarioRegionalRepo: repositories.NewUsuarioRegionalRepository(),
 		catalogoRepo:        repositories.NewCatalogoRepository(),
+		sesionRepo:          repositories.NewSesionRepository(),
 	}
 }
 
@@ -105,7 +107,7 @@ func (s *permisosService) GetPermisosByUserID(userID uint) (*dto.UsuarioPermisos
 	return resp, nil
 }
 
-func (s *permisosService) AsignarPermisoDirecto(userID uint, obj, act string) error {
+func (s *permisosService) AsignarPermisoDirecto(actor dto.Actor, userID uint, obj, act string) error {
 	if _, err := s.userRepo.FindByID(userID); err != nil {
 		return errors.New(errUsuarioNoEncontrado)
 	}
@@ -119,10 +121,14 @@ func (s *permisosService) AsignarPermisoDirecto(userID uint, obj, act string) er
 	if err != nil {
 		return err
 	}
-	return e.SavePolicy()
+	if err := e.SavePolicy(); err != nil {
+		return err
+	}
+	auditar(actor, AccionPermisoAsignar, tablaUsers, userID, nil, map[string]string{"obj": obj, "act": act})
+	return nil
 }
 
-func (s *permisosService) QuitarPermisoDirecto(userID uint, obj, act string) error {
+func (s *permisosService) QuitarPermisoDirecto(actor dto.Actor, userID uint, obj, act string) error {
 	if _, err := s.userRepo.FindByID(userID); err != nil {
 		return errors.New(errUsuarioNoEncontrado)
 	}
@@ -136,10 +142,14 @@ func (s *permisosService) QuitarPermisoDirecto(userID uint, obj, act string) err
 	if err != nil {
 		return err
 	}
-	return e.SavePolicy()
+	if err := e.SavePolicy(); err != nil {
+		return err
+	}
+	auditar(actor, AccionPermisoQuitar, tablaUsers, userID, map[string]string{"obj": obj, "act": act}, nil)
+	return nil
 }
 
-func (s *permisosService) SetRoles(userID uint, roles []string) error {
+func (s *permisosService) SetRoles(actor dto.Actor, userID uint, roles []string) error {
 	if _, err := s.userRepo.FindByID(userID); err != nil {
 		return errors.New(errUsuarioNoEncontrado)
 	}
@@ -149,6 +159,7 @@ func (s *permisosService) SetRoles(userID uint, roles []string) error {
 		return err
 	}
 	sub := strconv.FormatUint(uint64(userID), 10)
+	rolesAntes, _ := authz.GetRolesForUser(e, sub)
 	_, _ = authz.DeleteRolesForUser(e, sub)
 	for _, r := range roles {
 		r = normRoleName(r)
@@ -157,7 +168,13 @@ func (s *permisosService) SetRoles(userID uint, roles []string) error {
 		}
 		_, _ = authz.AddRoleForUser(e, sub, r)
 	}
-	return e.SavePolicy()
+	if err := e.SavePolicy(); err != nil {
+		return err
+	}
+	rolesDespues, _ := authz.GetRolesForUser(e, sub)
+	auditar(actor, AccionRolesActualizar, tablaUsers, userID,
+		map[string][]string{"roles": rolesAntes}, map[string][]string{"roles": rolesDespues})
+	return nil
 }
 
 func normRoleName(s string) string {
@@ -173,13 +190,24 @@ func normRoleName(s string) string {
 	return string(b)
 }
 
-func (s *permisosService) ToggleEstado(userID uint) error {
+func (s *permisosService) ToggleEstado(actor dto.Actor, userID uint) error {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return errors.New(errUsuarioNoEncontrado)
 	}
 	user.Status = !user.Status
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if !user.Status {
+		// Al desactivar, cerrar todas sus sesiones: los refresh tokens dejan de servir de inmediato.
+		if _, err := s.sesionRepo.RevocarTodasByUser(user.ID, models.SesionMotivoUsuarioInactivo, 0); err != nil {
+			return err
+		}
+	}
+	auditar(actor, AccionUsuarioEstado, tablaUsers, userID,
+		map[string]bool{"status": !user.Status}, map[string]bool{"status": user.Status})
+	return nil
 }
 
 func (s *permisosService) Definiciones() dto.DefinicionesPermisosResponse {
@@ -221,7 +249,7 @@ func (s *permisosService) GetUsuarioRegionales(userID uint) (*dto.UsuarioRegiona
 	}, nil
 }
 
-func (s *permisosService) SetUsuarioRegionales(userID uint, regionalIDs []uint) error {
+func (s *permisosService) SetUsuarioRegionales(actor dto.Actor, userID uint, regionalIDs []uint) error {
 	if _, err := s.userRepo.FindByID(userID); err != nil {
 		return errors.New(errUsuarioNoEncontrado)
 	}
@@ -238,7 +266,13 @@ func (s *permisosService) SetUsuarioRegionales(userID uint, regionalIDs []uint)
 			return errors.New("regional no válida")
 		}
 	}
-	return s.usuarioRegionalRepo.ReplaceForUser(userID, regionalIDs)
+	antes, _ := s.usuarioRegionalRepo.FindRegionalIDsByUserID(userID)
+	if err := s.usuarioRegionalRepo.ReplaceForUser(userID, regionalIDs); err != nil {
+		return err
+	}
+	auditar(actor, AccionUsuarioRegionales, tablaUsers, userID,
+		map[string][]uint{"regional_ids": antes}, map[string][]uint{"regional_ids": regionalIDs})
+	return nil
 }
 
 var _ PermisosService = (*permisosService)(nil)
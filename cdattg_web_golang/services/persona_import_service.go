@@ -1,27 +1,16 @@
 package services
 
 import (
-	"bytes"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
-	"github.com/xuri/excelize/v2"
 )
 
-// ImportProgress se envía durante la importación por streaming (estadísticas en tiempo real).
-type ImportProgress struct {
-	Total     int    `json:"total"`
-	Current   int    `json:"current"`
-	Processed int    `json:"processed"`
-	Duplicates int   `json:"duplicates"`
-	Errors    int    `json:"errors"`
-	Type      string `json:"type,omitempty"` // "progress" | "done"
-}
-
 // Códigos cortos de tipo de documento (como vienen en el Excel) -> nombre en BD
 var tipoDocCodigoANombre = map[string]string{
 	"CC":  "CÉDULA DE CIUDADANÍA",
@@ -52,9 +41,9 @@ var importHeaders = map[string]string{
 	"celular":          "celular",
 }
 
+// PersonaImportService historial de importaciones de personas. La importación corre como trabajo en segundo plano
+// (ImportacionTrabajoService).
 type PersonaImportService interface {
-	ImportFromExcel(fileBytes []byte, filename string, userID uint) (*ImportResult, error)
-	ImportFromExcelWithProgress(fileBytes []byte, filename string, userID uint, onProgress func(ImportProgress)) (*ImportResult, error)
 	ListImports(limit int) ([]ImportLogItem, error)
 }
 
@@ -62,23 +51,22 @@ type personaImportService struct {
 	personaService PersonaService
 	logRepo        repositories.PersonaImportLogRepository
 	catalogoRepo   repositories.CatalogoRepository
+	notificaciones NotificacionUsuarioService
 }
 
 func NewPersonaImportService(personaService PersonaService) PersonaImportService {
+	return newPersonaImportService(personaService, NewNotificacionUsuarioService())
+}
+
+func newPersonaImportService(personaService PersonaService, notificaciones NotificacionUsuarioService) *personaImportService {
 	return &personaImportService{
 		personaService: personaService,
 		logRepo:        repositories.NewPersonaImportLogRepository(),
 		catalogoRepo:   repositories.NewCatalogoRepository(),
+		notificaciones: notificaciones,
 	}
 }
 
-type ImportResult struct {
-	ProcessedCount  int    `json:"processed_count"`
-	DuplicatesCount int    `json:"duplicates_count"`
-	ErrorCount      int    `json:"error_count"`
-	Status          string `json:"status"`
-}
-
 type ImportLogItem struct {
 	ID              uint      `json:"id"`
 	Filename        string    `json:"filename"`
@@ -113,21 +101,20 @@ func (s *personaImportService) ListImports(limit int) ([]ImportLogItem, error) {
 	return items, nil
 }
 
-func (s *personaImportService) ImportFromExcel(fileBytes []byte, filename string, userID uint) (*ImportResult, error) {
-	f, err := excelize.OpenReader(bytes.NewReader(fileBytes))
-	if err != nil {
-		return nil, fmt.Errorf("archivo Excel inválido: %w", err)
-	}
-	defer f.Close()
-
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return nil, fmt.Errorf("el archivo no contiene hojas")
-	}
+// personaImportacion importación de personas fila por fila; los usuarios de las personas creadas se crean al
+// guardar avance.
+type personaImportacion struct {
+	s                 *personaImportService
+	rows              [][]string
+	colIndex          map[string]int
+	tipoByName        map[string]uint
+	pendientesUsuario []uint
+}
 
-	rows, err := f.GetRows(sheetName)
-	if err != nil || len(rows) < 2 {
-		return nil, fmt.Errorf("el archivo debe tener al menos encabezados y una fila de datos")
+func (s *personaImportService) prepararImportacion(_ *models.ImportacionTrabajo, fileBytes []byte) (importadorFilas, error) {
+	rows, err := leerFilasExcel(fileBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	headerRow := rows[0]
@@ -161,159 +148,62 @@ func (s *personaImportService) ImportFromExcel(fileBytes []byte, filename string
 			tipoByName[strings.ToLower(codigo)] = id
 		}
 	}
-
-	var processed, duplicates, errors int
-	var createdPersonaIDs []uint
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		req := s.rowToPersonaRequest(row, colIndex, tipoByName)
-		if req == nil {
-			continue
-		}
-		resp, err := s.personaService.CreateWithoutUser(*req)
-		if err != nil {
-			msg := err.Error()
-			if strings.Contains(msg, "ya está registrado") || strings.Contains(msg, "ya registrado") {
-				duplicates++
-			} else {
-				errors++
-			}
-			continue
-		}
-		processed++
-		createdPersonaIDs = append(createdPersonaIDs, resp.ID)
-	}
-
-	if len(createdPersonaIDs) > 0 {
-		_ = s.personaService.EnsureUsersForPersonas(createdPersonaIDs)
-	}
-
-	logEntry := &models.PersonaImportLog{
-		Filename:        filename,
-		UserID:          userID,
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errors,
-		Status:          "completado",
-		CreatedAt:       time.Now(),
-	}
-	_ = s.logRepo.Create(logEntry)
-
-	return &ImportResult{
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errors,
-		Status:          "completado",
-	}, nil
+	return &personaImportacion{s: s, rows: rows, colIndex: colIndex, tipoByName: tipoByName}, nil
 }
 
-// ImportFromExcelWithProgress igual que ImportFromExcel pero invoca onProgress tras cada fila (para streaming).
-func (s *personaImportService) ImportFromExcelWithProgress(fileBytes []byte, filename string, userID uint, onProgress func(ImportProgress)) (*ImportResult, error) {
-	f, err := excelize.OpenReader(bytes.NewReader(fileBytes))
-	if err != nil {
-		return nil, fmt.Errorf("archivo Excel inválido: %w", err)
-	}
-	defer f.Close()
+func (p *personaImportacion) total() int { return len(p.rows) - 1 }
 
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return nil, fmt.Errorf("el archivo no contiene hojas")
-	}
+func (p *personaImportacion) iniciar(*models.ImportacionTrabajo) error { return nil }
 
-	rows, err := f.GetRows(sheetName)
-	if err != nil || len(rows) < 2 {
-		return nil, fmt.Errorf("el archivo debe tener al menos encabezados y una fila de datos")
+func (p *personaImportacion) procesar(i int, av *avanceImportacion) {
+	req := p.s.rowToPersonaRequest(p.rows[i+1], p.colIndex, p.tipoByName)
+	if req == nil {
+		return
 	}
-
-	headerRow := rows[0]
-	colIndex := make(map[string]int)
-	for i, cell := range headerRow {
-		key := strings.TrimSpace(strings.ToLower(cell))
-		if canonical, ok := importHeaders[key]; ok {
-			colIndex[canonical] = i
-		}
-	}
-	if _, hasDoc := colIndex["numero_documento"]; !hasDoc {
-		return nil, fmt.Errorf("falta la columna 'numero_documento' (o 'número de documento')")
-	}
-	if _, hasPrimerNombre := colIndex["primer_nombre"]; !hasPrimerNombre {
-		return nil, fmt.Errorf("falta la columna 'primer_nombre' (o 'primer nombre')")
-	}
-	if _, hasPrimerApellido := colIndex["primer_apellido"]; !hasPrimerApellido {
-		return nil, fmt.Errorf("falta la columna 'primer_apellido' (o 'primer apellido')")
-	}
-
-	tiposDoc, _ := s.catalogoRepo.FindTiposDocumento()
-	tipoByName := make(map[string]uint)
-	for _, t := range tiposDoc {
-		nombreNorm := strings.TrimSpace(strings.ToLower(t.Nombre))
-		tipoByName[nombreNorm] = t.ID
-	}
-	for codigo, nombreCompleto := range tipoDocCodigoANombre {
-		nombreNorm := strings.TrimSpace(strings.ToLower(nombreCompleto))
-		if id, has := tipoByName[nombreNorm]; has {
-			tipoByName[strings.ToLower(codigo)] = id
-		}
-	}
-
-	total := len(rows) - 1
-	if onProgress != nil {
-		onProgress(ImportProgress{Total: total, Type: "progress"})
-	}
-
-	var processed, duplicates, errors int
-	var createdPersonaIDs []uint
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		req := s.rowToPersonaRequest(row, colIndex, tipoByName)
-		if req == nil {
-			if onProgress != nil {
-				onProgress(ImportProgress{Total: total, Current: i, Processed: processed, Duplicates: duplicates, Errors: errors, Type: "progress"})
-			}
-			continue
+	resp, err := p.s.personaService.CreateWithoutUser(*req)
+	if err != nil {
+		inc := models.ImportacionIncidencia{
+			Fila: i + 2, Tipo: "ERROR", Documento: req.NumeroDocumento,
+			Nombre: strings.TrimSpace(req.PrimerNombre + " " + req.PrimerApellido), Correo: req.Email, Celular: req.Celular,
+			Detalle: err.Error(),
 		}
-		resp, err := s.personaService.CreateWithoutUser(*req)
-		if err != nil {
-			msg := err.Error()
-			if strings.Contains(msg, "ya está registrado") || strings.Contains(msg, "ya registrado") {
-				duplicates++
-			} else {
-				errors++
-			}
+		if isPersonaDuplicadaErr(err) {
+			inc.Tipo = "DUPLICADO"
+			av.duplicados++
 		} else {
-			processed++
-			createdPersonaIDs = append(createdPersonaIDs, resp.ID)
-		}
-		if onProgress != nil {
-			onProgress(ImportProgress{Total: total, Current: i, Processed: processed, Duplicates: duplicates, Errors: errors, Type: "progress"})
+			av.errores++
 		}
+		av.incidencia(inc)
+		return
 	}
+	av.procesados++
+	p.pendientesUsuario = append(p.pendientesUsuario, resp.ID)
+}
 
-	if len(createdPersonaIDs) > 0 {
-		_ = s.personaService.EnsureUsersForPersonas(createdPersonaIDs)
+func (p *personaImportacion) confirmar() error {
+	if len(p.pendientesUsuario) == 0 {
+		return nil
 	}
+	if err := p.s.personaService.EnsureUsersForPersonas(p.pendientesUsuario); err != nil {
+		log.Printf("[importaciones] usuarios de personas importadas: %v", err)
+	}
+	p.pendientesUsuario = nil
+	return nil
+}
 
+func (p *personaImportacion) finalizar(t *models.ImportacionTrabajo) {
 	logEntry := &models.PersonaImportLog{
-		Filename:        filename,
-		UserID:          userID,
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errors,
+		Filename:        t.Filename,
+		UserID:          t.UserID,
+		ProcessedCount:  t.Procesados,
+		DuplicatesCount: t.Duplicados,
+		ErrorCount:      t.Errores,
 		Status:          "completado",
 		CreatedAt:       time.Now(),
 	}
-	_ = s.logRepo.Create(logEntry)
-
-	result := &ImportResult{
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errors,
-		Status:          "completado",
-	}
-	if onProgress != nil {
-		onProgress(ImportProgress{Total: total, Current: total, Processed: processed, Duplicates: duplicates, Errors: errors, Type: "done"})
-	}
-	return result, nil
+	_ = p.s.logRepo.Create(logEntry)
+	notificarImportacionTerminada(p.s.notificaciones, t.UserID, "Importación de personas terminada", rutaImportacionTrabajo(t),
+		t.Filename, t.Procesados, t.Duplicados, t.Errores)
 }
 
 func (s *personaImportService) rowToPersonaRequest(row []string, colIndex map[string]int, tipoByName map[string]uint) *dto.PersonaRequest {
@@ -11,12 +11,12 @@ type PersonaService interface {
 	FindAll(page, pageSize int, search string) ([]dto.PersonaResponse, int64, error)
 	FindByID(id uint) (*dto.PersonaResponse, error)
 	FindByNumeroDocumento(numeroDocumento string) (*dto.PersonaResponse, error)
-	Create(req dto.PersonaRequest) (*dto.PersonaResponse, error)
+	Create(actor dto.Actor, req dto.PersonaRequest) (*dto.PersonaResponse, error)
 	CreateWithoutUser(req dto.PersonaRequest) (*dto.PersonaResponse, error)
 	EnsureUsersForPersonas(personaIDs []uint) error
-	Update(id uint, req dto.PersonaRequest) (*dto.PersonaResponse, error)
+	Update(actor dto.Actor, id uint, req dto.PersonaRequest) (*dto.PersonaResponse, error)
 	UpdateSelf(personaID uint, req dto.PersonaSelfUpdateRequest) (*dto.PersonaResponse, error)
-	Delete(id uint) error
+	Delete(actor dto.Actor, id uint) error
 	ResetPassword(personaID uint) error
 }
 
@@ -67,7 +67,7 @@ func (s *personaService) FindByNumeroDocumento(numeroDocumento string) (*dto.Per
 	return &response, nil
 }
 
-func (s *personaService) Create(req dto.PersonaRequest) (*dto.PersonaResponse, error) {
+func (s *personaService) Create(actor dto.Actor, req dto.PersonaRequest) (*dto.PersonaResponse, error) {
 	if err := validatePersonaCreate(s.personaRepo, req); err != nil {
 		return nil, err
 	}
@@ -83,6 +83,7 @@ func (s *personaService) Create(req dto.PersonaRequest) (*dto.PersonaResponse, e
 	_ = s.accounts.CreateForPersona(persona)
 
 	response := mapPersonaToResponse(persona)
+	auditar(actor, AccionPersonaCrear, tablaPersonas, persona.ID, nil, response)
 	return &response, nil
 }
 
@@ -108,11 +109,12 @@ func (s *personaService) EnsureUsersForPersonas(personaIDs []uint) error {
 	return s.accounts.EnsureForPersonas(personaIDs)
 }
 
-func (s *personaService) Update(id uint, req dto.PersonaRequest) (*dto.PersonaResponse, error) {
+func (s *personaService) Update(actor dto.Actor, id uint, req dto.PersonaRequest) (*dto.PersonaResponse, error) {
 	persona, err := s.personaRepo.FindByID(id)
 	if err != nil {
 		return nil, errPersonaNoEncontrada
 	}
+	antes := mapPersonaToResponse(*persona)
 
 	if err := validatePersonaUpdate(s.personaRepo, id, req); err != nil {
 		return nil, err
@@ -128,6 +130,7 @@ func (s *personaService) Update(id uint, req dto.PersonaRequest) (*dto.PersonaRe
 	}
 
 	response := mapPersonaToResponse(*persona)
+	auditar(actor, AccionPersonaActualizar, tablaPersonas, id, antes, response)
 	return &response, nil
 }
 
@@ -157,8 +160,17 @@ func (s *personaService) UpdateSelf(personaID uint, req dto.PersonaSelfUpdateReq
 	return &response, nil
 }
 
-func (s *personaService) Delete(id uint) error {
-	return s.personaRepo.Delete(id)
+func (s *personaService) Delete(actor dto.Actor, id uint) error {
+	var antes *dto.PersonaResponse
+	if persona, err := s.personaRepo.FindByID(id); err == nil {
+		r := mapPersonaToResponse(*persona)
+		antes = &r
+	}
+	if err := s.personaRepo.Delete(id); err != nil {
+		return err
+	}
+	auditar(actor, AccionPersonaEliminar, tablaPersonas, id, antes, nil)
+	return nil
 }
 
 func (s *personaService) ResetPassword(personaID uint) error {
@@ -52,10 +52,11 @@ func (s *personaUserAccountService) CreateForPersona(persona models.Persona) err
 
 	personaID := persona.ID
 	user := models.User{
-		Email:     email,
-		Password:  hash,
-		Status:    true,
-		PersonaID: &personaID,
+		Email:               email,
+		Password:            hash,
+		Status:              true,
+		PersonaID:           &personaID,
+		DebeCambiarPassword: true,
 	}
 	if err := s.userRepo.Create(&user); err != nil {
 		return err
@@ -166,6 +167,8 @@ func (s *personaUserAccountService) SyncEmail(personaID uint, email string) erro
 		return errors.New("el email ya está registrado en otro usuario")
 	}
 	user.Email = email
+	// El correo nuevo debe verificarse antes de recibir enlaces de recuperación.
+	user.EmailVerifiedAt = nil
 	return s.userRepo.Update(user)
 }
 
@@ -182,6 +185,7 @@ func (s *personaUserAccountService) ResetPassword(personaID uint, numeroDocument
 		return fmt.Errorf("error al hashear contraseña: %w", err)
 	}
 	user.Password = hash
+	user.DebeCambiarPassword = true
 	return s.userRepo.Update(user)
 }
 
@@ -247,10 +251,11 @@ func buildUsersForPersonaChunk(personas []models.Persona, existingEmails map[str
 		}
 		pid := p.ID
 		users = append(users, models.User{
-			Email:     email,
-			Password:  hash,
-			Status:    true,
-			PersonaID: &pid,
+			Email:               email,
+			Password:            hash,
+			Status:              true,
+			PersonaID:           &pid,
+			DebeCambiarPassword: true,
 		})
 	}
 	return users, nil
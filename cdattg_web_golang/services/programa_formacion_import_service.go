@@ -1,8 +1,6 @@
 package services
 
 import (
-	"bytes"
-	"fmt"
 	"sort"
 	"strconv"
 	"strings"
@@ -10,7 +8,6 @@ import (
 	"github.com/sena/cdattg-web-golang/dto"
 	"github.com/sena/cdattg-web-golang/models"
 	"github.com/sena/cdattg-web-golang/repositories"
-	"github.com/xuri/excelize/v2"
 )
 
 // Nombres de columnas del Excel catálogo (hoja "Programas en Ejecución")
@@ -26,52 +23,48 @@ const (
 	colRedConoc  = 21 // Red de Conocimiento
 )
 
-type ProgramaFormacionImportService interface {
-	ImportFromCatalogoExcel(fileBytes []byte, filename string) (*ProgramaImportResult, error)
-}
-
 type programaFormacionImportService struct {
 	programaRepo   repositories.ProgramaFormacionRepository
 	redRepo        repositories.RedConocimientoRepository
 	catalogoRepo   repositories.CatalogoRepository
+	notificaciones NotificacionUsuarioService
 }
 
-func NewProgramaFormacionImportService() ProgramaFormacionImportService {
+func newProgramaFormacionImportService(notificaciones NotificacionUsuarioService) *programaFormacionImportService {
 	return &programaFormacionImportService{
-		programaRepo: repositories.NewProgramaFormacionRepository(),
-		redRepo:      repositories.NewRedConocimientoRepository(),
-		catalogoRepo: repositories.NewCatalogoRepository(),
+		programaRepo:   repositories.NewProgramaFormacionRepository(),
+		redRepo:        repositories.NewRedConocimientoRepository(),
+		catalogoRepo:   repositories.NewCatalogoRepository(),
+		notificaciones: notificaciones,
 	}
 }
 
-type ProgramaImportResult struct {
-	ProcessedCount  int `json:"processed_count"`
-	DuplicatesCount int `json:"duplicates_count"`
-	ErrorCount      int `json:"error_count"`
-	RedesCreated    int `json:"redes_created"`
-	Status          string `json:"status"`
+// programaCatalogoItem fila de versión máxima de un código de programa.
+type programaCatalogoItem struct {
+	version int
+	fila    int // fila del Excel (base 1)
+	row     []string
 }
 
-func (s *programaFormacionImportService) ImportFromCatalogoExcel(fileBytes []byte, filename string) (*ProgramaImportResult, error) {
-	f, err := excelize.OpenReader(bytes.NewReader(fileBytes))
-	if err != nil {
-		return nil, fmt.Errorf("archivo Excel inválido: %w", err)
-	}
-	defer f.Close()
-
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return nil, fmt.Errorf("el archivo no contiene hojas")
-	}
+// programaImportacion importación del catálogo código por código (orden alfabético), para reanudar por posición.
+type programaImportacion struct {
+	s                *programaFormacionImportService
+	byCodigo         map[string]programaCatalogoItem
+	codigosOrdenados []string
+	nivelByName      map[string]uint
+	tipoTituladaID   *uint
+	redByName        map[string]uint
+}
 
-	rows, err := f.GetRows(sheetName)
-	if err != nil || len(rows) < 2 {
-		return nil, fmt.Errorf("el archivo debe tener al menos encabezados y una fila de datos")
+func (s *programaFormacionImportService) prepararImportacion(_ *models.ImportacionTrabajo, fileBytes []byte) (importadorFilas, error) {
+	rows, err := leerFilasExcel(fileBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	// Filtrar solo niveles de formación relevantes:
-	// TÉCNICO, TECNÓLOGO, OPERARIO y AUXILIAR
-	var candidatos [][]string
+	// TÉCNICO, TECNÓLOGO, OPERARIO y AUXILIAR; agrupar por código y quedarse con la fila de versión máxima
+	byCodigo := make(map[string]programaCatalogoItem)
 	for i := 1; i < len(rows); i++ {
 		row := rows[i]
 		if colNivel >= len(row) { continue }
@@ -79,22 +72,12 @@ func (s *programaFormacionImportService) ImportFromCatalogoExcel(fileBytes []byt
 		if nivel != "TECNICO" && nivel != "TECNOLOGO" && nivel != "OPERARIO" && nivel != "AUXILIAR" {
 			continue
 		}
-		candidatos = append(candidatos, row)
-	}
-
-	// Agrupar por código y quedarse con la fila de versión máxima
-	type item struct {
-		version int
-		row     []string
-	}
-	byCodigo := make(map[string]item)
-	for _, row := range candidatos {
 		if colCodigo >= len(row) || colVersion >= len(row) { continue }
 		codigo := strings.TrimSpace(strings.ToUpper(row[colCodigo]))
 		if codigo == "" { continue }
 		ver, _ := strconv.Atoi(strings.TrimSpace(row[colVersion]))
 		if cur, ok := byCodigo[codigo]; !ok || ver > cur.version {
-			byCodigo[codigo] = item{version: ver, row: row}
+			byCodigo[codigo] = programaCatalogoItem{version: ver, fila: i + 1, row: row}
 		}
 	}
 
@@ -121,43 +104,66 @@ func (s *programaFormacionImportService) ImportFromCatalogoExcel(fileBytes []byt
 		redByName[key] = r.ID
 	}
 
-	var processed, duplicates, errors, redesCreated int
 	codigosOrdenados := make([]string, 0, len(byCodigo))
 	for c := range byCodigo { codigosOrdenados = append(codigosOrdenados, c) }
 	sort.Strings(codigosOrdenados)
 
-	for _, codigo := range codigosOrdenados {
-		it := byCodigo[codigo]
-		row := it.row
-		req, errReq := s.rowToProgramaRequest(row, nivelByName, tipoTituladaID, redByName, &redesCreated, s.redRepo)
-		if errReq != nil {
-			errors++
-			continue
-		}
-		if req == nil {
-			continue
-		}
-		req.Codigo = codigo
-		if s.programaRepo.ExistsByCodigo(codigo) {
-			duplicates++
-			continue
-		}
-		p := s.toModel(*req)
-		p.Codigo = codigo
-		if err := s.programaRepo.Create(&p); err != nil {
-			errors++
-			continue
-		}
-		processed++
+	return &programaImportacion{
+		s:                s,
+		byCodigo:         byCodigo,
+		codigosOrdenados: codigosOrdenados,
+		nivelByName:      nivelByName,
+		tipoTituladaID:   tipoTituladaID,
+		redByName:        redByName,
+	}, nil
+}
+
+func (p *programaImportacion) total() int { return len(p.codigosOrdenados) }
+
+func (p *programaImportacion) iniciar(*models.ImportacionTrabajo) error { return nil }
+
+func (p *programaImportacion) procesar(i int, av *avanceImportacion) {
+	codigo := p.codigosOrdenados[i]
+	it := p.byCodigo[codigo]
+	row := it.row
+	inc := models.ImportacionIncidencia{Fila: it.fila, Tipo: "ERROR", Documento: codigo}
+	if colNombre < len(row) {
+		inc.Nombre = truncarRunes(strings.TrimSpace(row[colNombre]), 255)
 	}
+	req, errReq := p.s.rowToProgramaRequest(row, p.nivelByName, p.tipoTituladaID, p.redByName, &av.redesCreadas, p.s.redRepo)
+	if errReq != nil {
+		av.errores++
+		inc.Detalle = truncarRunes(errReq.Error(), 1000)
+		av.incidencia(inc)
+		return
+	}
+	if req == nil {
+		return
+	}
+	req.Codigo = codigo
+	if p.s.programaRepo.ExistsByCodigo(codigo) {
+		av.duplicados++
+		inc.Tipo = "DUPLICADO"
+		inc.Detalle = "Ya existe un programa con este código"
+		av.incidencia(inc)
+		return
+	}
+	m := p.s.toModel(*req)
+	m.Codigo = codigo
+	if err := p.s.programaRepo.Create(&m); err != nil {
+		av.errores++
+		inc.Detalle = truncarRunes(err.Error(), 1000)
+		av.incidencia(inc)
+		return
+	}
+	av.procesados++
+}
 
-	return &ProgramaImportResult{
-		ProcessedCount:  processed,
-		DuplicatesCount: duplicates,
-		ErrorCount:      errors,
-		RedesCreated:    redesCreated,
-		Status:          "completado",
-	}, nil
+func (p *programaImportacion) confirmar() error { return nil }
+
+func (p *programaImportacion) finalizar(t *models.ImportacionTrabajo) {
+	notificarImportacionTerminada(p.s.notificaciones, t.UserID, "Importación de programas terminada", rutaImportacionTrabajo(t),
+		t.Filename, t.Procesados, t.Duplicados, t.Errores)
 }
 
 func (s *programaFormacionImportService) rowToProgramaRequest(
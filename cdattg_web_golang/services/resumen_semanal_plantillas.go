@@ -0,0 +1,109 @@
+package services
+
+import texttemplate "text/template"
+
+// Plantillas de los resúmenes semanales, sobre el layout común de correo (correo_plantillas.go).
+
+const estiloCeldaResumen = `padding:6px 8px;border-bottom:1px solid #f3f4f6;font-size:13px;`
+
+const plantillaResumenCoordinadorHTML = `{{template "encabezado" .}}
+{{if .Regionales}}<p style="margin:0 0 8px;color:#374151;">Alcance: {{.Regionales}}.</p>{{end}}
+
+{{template "seccion" "Sesiones sin asistencia tomada por instructor"}}
+{{if .SinAsistencia}}<p style="margin:0 0 8px;">{{.TotalSinAsistencia}} sesiones programadas quedaron sin asistencia registrada.</p>
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0">
+<tr>{{template "th" "Instructor"}}{{template "th" "Sesiones"}}{{template "th" "Fichas"}}</tr>
+{{range .SinAsistencia}}<tr><td style="` + estiloCeldaResumen + `">{{.Instructor}}{{if .Documento}}<br><span style="color:#6b7280;font-size:12px;">{{.Documento}}</span>{{end}}</td>
+<td style="` + estiloCeldaResumen + `">{{.Sesiones}}</td><td style="` + estiloCeldaResumen + `">{{.Fichas}}</td></tr>
+{{end}}</table>
+<p style="margin:8px 0 0;"><a href="{{.EnlaceSinAsistencia}}" style="color:#2563eb;">Ver el detalle de sesiones sin asistencia</a></p>
+{{else}}{{template "vacio" "Todas las sesiones programadas tienen asistencia registrada."}}{{end}}
+
+{{template "seccion" (printf "Fichas con cobertura de asistencia menor al %d%%" .UmbralCobertura)}}
+{{if .FichasBajaCobertura}}<table role="presentation" width="100%" cellpadding="0" cellspacing="0">
+<tr>{{template "th" "Ficha"}}{{template "th" "Sede"}}{{template "th" "Sesiones con asistencia"}}{{template "th" "Cobertura"}}</tr>
+{{range .FichasBajaCobertura}}<tr><td style="` + estiloCeldaResumen + `">{{.Ficha}}{{if .Programa}}<br><span style="color:#6b7280;font-size:12px;">{{.Programa}}</span>{{end}}</td>
+<td style="` + estiloCeldaResumen + `">{{.Sede}}</td><td style="` + estiloCeldaResumen + `">{{.Tomadas}} de {{.Esperadas}}</td>
+<td style="` + estiloCeldaResumen + `color:#b91c1c;font-weight:bold;">{{.Pct}}%</td></tr>
+{{end}}</table>
+{{else}}{{template "vacio" "Ninguna ficha quedó por debajo del umbral."}}{{end}}
+
+{{template "seccion" "Casos nuevos de bienestar"}}
+{{if .CasosNuevos}}<table role="presentation" width="100%" cellpadding="0" cellspacing="0">
+<tr>{{template "th" "Caso"}}{{template "th" "Aprendiz"}}{{template "th" "Ficha"}}{{template "th" "Abierto"}}</tr>
+{{range .CasosNuevos}}<tr><td style="` + estiloCeldaResumen + `"><a href="{{.Enlace}}" style="color:#2563eb;">#{{.ID}}</a></td>
+<td style="` + estiloCeldaResumen + `">{{.Aprendiz}}</td><td style="` + estiloCeldaResumen + `">{{.Ficha}}{{if .Sede}}<br><span style="color:#6b7280;font-size:12px;">{{.Sede}}</span>{{end}}</td>
+<td style="` + estiloCeldaResumen + `">{{.Fecha}}</td></tr>
+{{end}}</table>
+{{else}}{{template "vacio" "No se abrieron casos de bienestar en la semana."}}{{end}}
+
+{{template "boton" .BotonPanel}}
+{{template "pie" .}}`
+
+const plantillaResumenInstructorHTML = `{{template "encabezado" .}}
+{{template "seccion" "Aprendices con asistencia pendiente de revisión"}}
+{{if .Pendientes}}<p style="margin:0 0 8px;">Tiene {{.TotalPendientes}} registros marcados para revisión (por ejemplo, ingresos sin salida).</p>
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0">
+<tr>{{template "th" "Fecha"}}{{template "th" "Ficha"}}{{template "th" "Aprendiz"}}</tr>
+{{range .Pendientes}}<tr><td style="` + estiloCeldaResumen + `">{{.Fecha}}</td><td style="` + estiloCeldaResumen + `">{{.Ficha}}</td>
+<td style="` + estiloCeldaResumen + `">{{.Aprendiz}}{{if .Documento}}<br><span style="color:#6b7280;font-size:12px;">{{.Documento}}</span>{{end}}</td></tr>
+{{end}}</table>
+{{else}}{{template "vacio" "No tiene registros pendientes de revisión."}}{{end}}
+
+{{template "seccion" "Traslados de sesión próximos"}}
+{{if .Traslados}}<table role="presentation" width="100%" cellpadding="0" cellspacing="0">
+<tr>{{template "th" "Ficha"}}{{template "th" "Cambio"}}{{template "th" "Con"}}{{template "th" "Motivo"}}</tr>
+{{range .Traslados}}<tr><td style="` + estiloCeldaResumen + `">{{.Ficha}}</td><td style="` + estiloCeldaResumen + `">{{.Detalle}}</td>
+<td style="` + estiloCeldaResumen + `">{{.OtroInstructor}}</td><td style="` + estiloCeldaResumen + `">{{.Motivo}}</td></tr>
+{{end}}</table>
+{{else}}{{template "vacio" "No tiene traslados de sesión en los próximos días."}}{{end}}
+
+{{template "boton" .BotonAsistencia}}
+{{template "pie" .}}`
+
+const plantillaResumenCoordinadorTexto = `Hola{{if .Nombre}} {{.Nombre}}{{end}},
+
+{{.Titulo}} ({{.Periodo}}){{if .Regionales}}
+Alcance: {{.Regionales}}{{end}}
+
+SESIONES SIN ASISTENCIA TOMADA POR INSTRUCTOR
+{{if .SinAsistencia}}{{range .SinAsistencia}}- {{.Instructor}}: {{.Sesiones}} (fichas {{.Fichas}})
+{{end}}Detalle: {{.EnlaceSinAsistencia}}
+{{else}}Todas las sesiones programadas tienen asistencia registrada.
+{{end}}
+FICHAS CON COBERTURA MENOR AL {{.UmbralCobertura}}%
+{{if .FichasBajaCobertura}}{{range .FichasBajaCobertura}}- Ficha {{.Ficha}} ({{.Sede}}): {{.Tomadas}} de {{.Esperadas}} sesiones, {{.Pct}}%
+{{end}}{{else}}Ninguna ficha quedó por debajo del umbral.
+{{end}}
+CASOS NUEVOS DE BIENESTAR
+{{if .CasosNuevos}}{{range .CasosNuevos}}- #{{.ID}} {{.Aprendiz}}, ficha {{.Ficha}} ({{.Fecha}}): {{.Enlace}}
+{{end}}{{else}}No se abrieron casos de bienestar en la semana.
+{{end}}
+Panel: {{.BotonPanel.Enlace}}
+
+Para cancelar este resumen ingrese a {{.EnlacePreferencias}}
+`
+
+const plantillaResumenInstructorTexto = `Hola{{if .Nombre}} {{.Nombre}}{{end}},
+
+{{.Titulo}} ({{.Periodo}})
+
+APRENDICES CON ASISTENCIA PENDIENTE DE REVISIÓN
+{{if .Pendientes}}{{range .Pendientes}}- {{.Fecha}} ficha {{.Ficha}}: {{.Aprendiz}} ({{.Documento}})
+{{end}}{{else}}No tiene registros pendientes de revisión.
+{{end}}
+TRASLADOS DE SESIÓN PRÓXIMOS
+{{if .Traslados}}{{range .Traslados}}- Ficha {{.Ficha}}: {{.Detalle}}{{if .OtroInstructor}} con {{.OtroInstructor}}{{end}}{{if .Motivo}} ({{.Motivo}}){{end}}
+{{end}}{{else}}No tiene traslados de sesión en los próximos días.
+{{end}}
+Asistencia: {{.BotonAsistencia.Enlace}}
+
+Para cancelar este resumen ingrese a {{.EnlacePreferencias}}
+`
+
+var (
+	tplResumenCoordinadorHTML  = plantillaCorreoHTML("coordinador", plantillaResumenCoordinadorHTML)
+	tplResumenInstructorHTML   = plantillaCorreoHTML("instructor", plantillaResumenInstructorHTML)
+	tplResumenCoordinadorTexto = texttemplate.Must(texttemplate.New("coordinador").Parse(plantillaResumenCoordinadorTexto))
+	tplResumenInstructorTexto  = texttemplate.Must(texttemplate.New("instructor").Parse(plantillaResumenInstructorTexto))
+)
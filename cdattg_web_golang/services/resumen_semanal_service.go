@@ -0,0 +1,577 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+const (
+	// diasResumenSemanal días analizados hacia atrás (terminando ayer) y días de traslados próximos hacia adelante.
+	diasResumenSemanal = 7
+	// umbralCoberturaResumen fichas con menor porcentaje de sesiones con asistencia aparecen en el resumen.
+	umbralCoberturaResumen = 80
+	// maxFilasResumen tope por sección para que el correo no crezca sin límite; el detalle completo está en la app.
+	maxFilasResumen = 25
+
+	rutaPanelResumen          = "/dashboard"
+	rutaSinAsistenciaResumen  = "/asistencia/sesiones-sin-asistencia-tomada"
+	rutaAsistenciaResumen     = "/asistencia"
+	rutaPreferenciasResumen   = "/perfil"
+	rutaCasoBienestarResumen  = "/bienestar/seguimiento/"
+	rolResumenSemanalCoord    = "COORDINADOR"
+	rolResumenSemanalInstruct = "INSTRUCTOR"
+)
+
+var ErrResumenSemanalNoAplica = errors.New("el resumen semanal no aplica a los roles del usuario")
+
+// tipoResumenSemanal descripción de cada resumen y el rol que lo recibe.
+type tipoResumenSemanal struct {
+	tipo        string
+	rol         string
+	nombre      string
+	descripcion string
+}
+
+var tiposResumenSemanal = []tipoResumenSemanal{
+	{
+		tipo:        models.ResumenSemanalCoordinador,
+		rol:         rolResumenSemanalCoord,
+		nombre:      "Resumen semanal de coordinación",
+		descripcion: "Sesiones sin asistencia por instructor, fichas con baja cobertura y casos nuevos de bienestar de sus regionales.",
+	},
+	{
+		tipo:        models.ResumenSemanalInstructor,
+		rol:         rolResumenSemanalInstruct,
+		nombre:      "Resumen semanal del instructor",
+		descripcion: "Aprendices con asistencia pendiente de revisión y traslados de sesión de los próximos días.",
+	},
+}
+
+// ResumenSemanalService resúmenes semanales por correo (HTML) para coordinadores e instructores, con cancelación
+// por tipo de resumen desde el perfil.
+type ResumenSemanalService interface {
+	Preferencias(userID uint) (*dto.ResumenesSemanalesResponse, error)
+	ActualizarPreferencia(userID uint, req dto.ActualizarResumenSemanalRequest) (*dto.ResumenesSemanalesResponse, error)
+	// EnviarResumenes arma y envía los resúmenes de la semana (tarea programada). Los resúmenes sin contenido no se envían.
+	EnviarResumenes() error
+}
+
+type resumenSemanalService struct {
+	repo        repositories.ResumenSemanalRepository
+	userRepo    repositories.UserRepository
+	personaRepo repositories.PersonaRepository
+	instRepo    repositories.InstructorRepository
+	scopeSvc    DashboardScopeService
+	calculator  *CasosBienestarCalculator
+	// rolesDeUsuario roles Casbin del usuario.
+	rolesDeUsuario func(userID uint) ([]string, error)
+	// usuariosConRol IDs de los usuarios con el rol.
+	usuariosConRol func(rol string) []uint
+	correos        CorreoService
+}
+
+func NewResumenSemanalService() ResumenSemanalService {
+	return &resumenSemanalService{
+		repo:           repositories.NewResumenSemanalRepository(),
+		userRepo:       repositories.NewUserRepository(),
+		personaRepo:    repositories.NewPersonaRepository(),
+		instRepo:       repositories.NewInstructorRepository(),
+		scopeSvc:       NewDashboardScopeService(),
+		calculator:     NewCasosBienestarCalculator(),
+		rolesDeUsuario: rolesCasbinDeUsuario,
+		usuariosConRol: usuariosCasbinConRol,
+		correos:        NewCorreoService(),
+	}
+}
+
+func (s *resumenSemanalService) Preferencias(userID uint) (*dto.ResumenesSemanalesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New(errUsuarioNoEncontrado)
+	}
+	roles, err := s.rolesDeUsuario(userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.repo.ListPreferencias(userID)
+	if err != nil {
+		return nil, err
+	}
+	activo := make(map[string]bool, len(prefs))
+	for _, p := range prefs {
+		activo[p.Tipo] = p.Activo
+	}
+	out := &dto.ResumenesSemanalesResponse{
+		CorreoDisponible: smtpHabilitado() && correoEntregable(user.Email),
+		Email:            user.Email,
+		Preferencias:     []dto.ResumenSemanalPreferenciaItem{},
+	}
+	for _, t := range tiposResumenSemanal {
+		if !hasRole(roles, t.rol) {
+			continue
+		}
+		a, ok := activo[t.tipo]
+		out.Preferencias = append(out.Preferencias, dto.ResumenSemanalPreferenciaItem{
+			Tipo:        t.tipo,
+			Nombre:      t.nombre,
+			Descripcion: t.descripcion,
+			Activo:      !ok || a,
+		})
+	}
+	return out, nil
+}
+
+func (s *resumenSemanalService) ActualizarPreferencia(userID uint, req dto.ActualizarResumenSemanalRequest) (*dto.ResumenesSemanalesResponse, error) {
+	roles, err := s.rolesDeUsuario(userID)
+	if err != nil {
+		return nil, err
+	}
+	aplica := false
+	for _, t := range tiposResumenSemanal {
+		if t.tipo == req.Tipo && hasRole(roles, t.rol) {
+			aplica = true
+		}
+	}
+	if !aplica {
+		return nil, ErrResumenSemanalNoAplica
+	}
+	pref := &models.ResumenSemanalPreferencia{UserID: userID, Tipo: req.Tipo, Activo: *req.Activo, UpdatedAt: utils.Now()}
+	if err := s.repo.GuardarPreferencia(pref); err != nil {
+		return nil, err
+	}
+	return s.Preferencias(userID)
+}
+
+// destinatarioResumen usuario activo con correo entregable que no canceló el tipo de resumen.
+type destinatarioResumen struct {
+	user   *models.User
+	nombre string
+}
+
+func (s *resumenSemanalService) destinatarios(tipo, rol string) ([]destinatarioResumen, error) {
+	desuscritos, err := s.repo.MapDesuscritos(tipo)
+	if err != nil {
+		return nil, err
+	}
+	var out []destinatarioResumen
+	for _, id := range s.usuariosConRol(rol) {
+		if desuscritos[id] {
+			continue
+		}
+		u, err := s.userRepo.FindByID(id)
+		if err != nil || u == nil || !u.Status || !correoEntregable(u.Email) {
+			continue
+		}
+		d := destinatarioResumen{user: u}
+		if u.PersonaID != nil {
+			if p, err := s.personaRepo.FindByID(*u.PersonaID); err == nil && p != nil {
+				d.nombre = p.GetFullName()
+			}
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// periodoResumenSemanal los 7 días que terminan ayer (fechas sin hora, como el calendario de sesiones).
+func periodoResumenSemanal(ahora time.Time) (inicio, fin time.Time) {
+	hoy := time.Date(ahora.Year(), ahora.Month(), ahora.Day(), 0, 0, 0, 0, time.UTC)
+	return hoy.AddDate(0, 0, -diasResumenSemanal), hoy.AddDate(0, 0, -1)
+}
+
+func etiquetaPeriodoResumen(inicio, fin time.Time) string {
+	return fmt.Sprintf("Del %s al %s", inicio.Format("02/01/2006"), fin.Format("02/01/2006"))
+}
+
+func (s *resumenSemanalService) EnviarResumenes() error {
+	if !smtpHabilitado() {
+		return nil
+	}
+	var fallos []string
+	if err := s.enviarResumenesCoordinador(); err != nil {
+		fallos = append(fallos, err.Error())
+	}
+	if err := s.enviarResumenesInstructor(); err != nil {
+		fallos = append(fallos, err.Error())
+	}
+	if len(fallos) > 0 {
+		return errors.New(strings.Join(fallos, "; "))
+	}
+	return nil
+}
+
+// ---- Coordinadores ----
+
+type filaSinAsistenciaResumen struct {
+	Instructor string
+	Documento  string
+	Sesiones   int
+	Fichas     string
+}
+
+type filaCoberturaResumen struct {
+	Ficha     string
+	Programa  string
+	Sede      string
+	Tomadas   int
+	Esperadas int
+	Pct       int
+}
+
+type filaCasoNuevoResumen struct {
+	ID       uint
+	Aprendiz string
+	Ficha    string
+	Sede     string
+	Fecha    string
+	Enlace   string
+}
+
+type resumenCoordinadorData struct {
+	correoBase
+	Regionales          string
+	SinAsistencia       []filaSinAsistenciaResumen
+	TotalSinAsistencia  int
+	FichasBajaCobertura []filaCoberturaResumen
+	UmbralCobertura     int
+	CasosNuevos         []filaCasoNuevoResumen
+	EnlaceSinAsistencia string
+	BotonPanel          botonCorreo
+}
+
+func (d *resumenCoordinadorData) vacio() bool {
+	return len(d.SinAsistencia) == 0 && len(d.FichasBajaCobertura) == 0 && len(d.CasosNuevos) == 0
+}
+
+// agruparSinAsistenciaPorInstructor cuenta las sesiones sin asistencia de cada instructor (mayor primero).
+func agruparSinAsistenciaPorInstructor(rows []repositories.SesionSinAsistenciaTomadaRow) []filaSinAsistenciaResumen {
+	type acumulado struct {
+		fila   filaSinAsistenciaResumen
+		fichas []string
+		vistas map[string]bool
+	}
+	por := make(map[uint]*acumulado)
+	var orden []uint
+	for _, r := range rows {
+		a, ok := por[r.InstructorID]
+		if !ok {
+			a = &acumulado{
+				fila:   filaSinAsistenciaResumen{Instructor: r.InstructorNombre, Documento: r.NumeroDocumento},
+				vistas: make(map[string]bool),
+			}
+			por[r.InstructorID] = a
+			orden = append(orden, r.InstructorID)
+		}
+		a.fila.Sesiones++
+		if !a.vistas[r.FichaNumero] {
+			a.vistas[r.FichaNumero] = true
+			a.fichas = append(a.fichas, r.FichaNumero)
+		}
+	}
+	out := make([]filaSinAsistenciaResumen, 0, len(orden))
+	for _, id := range orden {
+		a := por[id]
+		sort.Strings(a.fichas)
+		a.fila.Fichas = strings.Join(a.fichas, ", ")
+		out = append(out, a.fila)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Sesiones != out[j].Sesiones {
+			return out[i].Sesiones > out[j].Sesiones
+		}
+		return out[i].Instructor < out[j].Instructor
+	})
+	return out
+}
+
+// fichasBajoCobertura porcentaje de sesiones programadas con asistencia por ficha: tomadas / (tomadas + incumplidas).
+// Solo aparecen fichas con algún incumplimiento en el periodo, las demás tienen cobertura completa.
+func fichasBajoCobertura(rows []repositories.SesionSinAsistenciaTomadaRow, tomadas map[uint]int, umbral int) []filaCoberturaResumen {
+	type clave struct {
+		instructorID uint
+		fecha        string
+	}
+	incumplidas := make(map[uint]map[clave]bool)
+	meta := make(map[uint]repositories.SesionSinAsistenciaTomadaRow)
+	for _, r := range rows {
+		if incumplidas[r.FichaID] == nil {
+			incumplidas[r.FichaID] = make(map[clave]bool)
+			meta[r.FichaID] = r
+		}
+		incumplidas[r.FichaID][clave{r.InstructorID, r.Fecha.Format(time.DateOnly)}] = true
+	}
+	var out []filaCoberturaResumen
+	for fichaID, sin := range incumplidas {
+		t := tomadas[fichaID]
+		esperadas := t + len(sin)
+		pct := int(math.Floor(float64(t) * 100 / float64(esperadas)))
+		if pct >= umbral {
+			continue
+		}
+		m := meta[fichaID]
+		out = append(out, filaCoberturaResumen{
+			Ficha:     m.FichaNumero,
+			Programa:  m.ProgramaNombre,
+			Sede:      m.SedeNombre,
+			Tomadas:   t,
+			Esperadas: esperadas,
+			Pct:       pct,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Pct != out[j].Pct {
+			return out[i].Pct < out[j].Pct
+		}
+		return out[i].Ficha < out[j].Ficha
+	})
+	return out
+}
+
+func claveSedesResumen(sedeIDs []uint) string {
+	ids := append([]uint(nil), sedeIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// datosCoordinadorSedes contenido del resumen para un conjunto de sedes (coordinadores de las mismas regionales lo comparten).
+func (s *resumenSemanalService) datosCoordinadorSedes(sedeIDs []uint, inicio, fin time.Time) (*resumenCoordinadorData, error) {
+	fechaInicio, fechaFin := inicio.Format(time.DateOnly), fin.Format(time.DateOnly)
+	rows, err := s.calculator.ListSesionesSinAsistenciaTomada(sedeIDs, fechaInicio, fechaFin)
+	if err != nil {
+		return nil, err
+	}
+	fichaIDs := make([]uint, 0)
+	vistas := make(map[uint]bool)
+	for _, r := range rows {
+		if !vistas[r.FichaID] {
+			vistas[r.FichaID] = true
+			fichaIDs = append(fichaIDs, r.FichaID)
+		}
+	}
+	tomadas, err := s.repo.CountSesionesTomadasPorFicha(fichaIDs, fechaInicio, fechaFin)
+	if err != nil {
+		return nil, err
+	}
+	desde := time.Date(inicio.Year(), inicio.Month(), inicio.Day(), 0, 0, 0, 0, utils.AppLocation())
+	casos, err := s.repo.ListCasosBienestarNuevos(sedeIDs, desde)
+	if err != nil {
+		return nil, err
+	}
+
+	base := frontendURL()
+	d := &resumenCoordinadorData{
+		correoBase: correoBase{
+			Titulo:             "Resumen semanal de coordinación",
+			Periodo:            etiquetaPeriodoResumen(inicio, fin),
+			EnlacePreferencias: enlaceResumen(base, rutaPreferenciasResumen),
+		},
+		SinAsistencia:       agruparSinAsistenciaPorInstructor(rows),
+		TotalSinAsistencia:  len(rows),
+		FichasBajaCobertura: fichasBajoCobertura(rows, tomadas, umbralCoberturaResumen),
+		UmbralCobertura:     umbralCoberturaResumen,
+		EnlaceSinAsistencia: enlaceResumen(base, rutaSinAsistenciaResumen),
+		BotonPanel:          botonCorreo{Texto: "Abrir el panel", Enlace: enlaceResumen(base, rutaPanelResumen)},
+	}
+	for _, c := range casos {
+		d.CasosNuevos = append(d.CasosNuevos, filaCasoNuevoResumen{
+			ID:       c.CasoID,
+			Aprendiz: c.PersonaNombre,
+			Ficha:    c.FichaNumero,
+			Sede:     c.SedeNombre,
+			Fecha:    c.CreatedAt.In(utils.AppLocation()).Format("02/01/2006"),
+			Enlace:   enlaceResumen(base, rutaCasoBienestarResumen+strconv.FormatUint(uint64(c.CasoID), 10)),
+		})
+	}
+	d.SinAsistencia = recortarFilasResumen(d.SinAsistencia)
+	d.FichasBajaCobertura = recortarFilasResumen(d.FichasBajaCobertura)
+	d.CasosNuevos = recortarFilasResumen(d.CasosNuevos)
+	return d, nil
+}
+
+func recortarFilasResumen[T any](filas []T) []T {
+	if len(filas) > maxFilasResumen {
+		return filas[:maxFilasResumen]
+	}
+	return filas
+}
+
+func enlaceResumen(base, ruta string) string {
+	return strings.TrimRight(base, "/") + ruta
+}
+
+func (s *resumenSemanalService) enviarResumenesCoordinador() error {
+	dest, err := s.destinatarios(models.ResumenSemanalCoordinador, rolResumenSemanalCoord)
+	if err != nil {
+		return fmt.Errorf("resumen de coordinación: %w", err)
+	}
+	inicio, fin := periodoResumenSemanal(utils.Now())
+	porSedes := make(map[string]*resumenCoordinadorData)
+	var enviados, fallos int
+	for _, d := range dest {
+		scope, err := s.scopeSvc.Resolve(d.user.ID, []string{rolResumenSemanalCoord})
+		if err != nil || scope.Empty || len(scope.SedeIDs) == 0 {
+			continue
+		}
+		clave := claveSedesResumen(scope.SedeIDs)
+		datos, ok := porSedes[clave]
+		if !ok {
+			datos, err = s.datosCoordinadorSedes(scope.SedeIDs, inicio, fin)
+			if err != nil {
+				return fmt.Errorf("resumen de coordinación: %w", err)
+			}
+			porSedes[clave] = datos
+		}
+		if datos.vacio() {
+			continue
+		}
+		personal := *datos
+		personal.Nombre = d.nombre
+		personal.Regionales = strings.Join(scope.RegionalNames, ", ")
+		html, texto, err := renderCorreo(tplResumenCoordinadorHTML, tplResumenCoordinadorTexto, &personal)
+		if err != nil {
+			return fmt.Errorf("resumen de coordinación: %w", err)
+		}
+		correo := CorreoNuevo{
+			Para: []string{d.user.Email}, Asunto: "CDATTG Web - " + personal.Titulo,
+			Plantilla: PlantillaCorreoResumenCoordinador, HTML: html, Texto: texto,
+		}
+		if err := s.correos.Encolar(correo); err != nil {
+			log.Printf("[resumen-semanal] coordinador %d: %v", d.user.ID, err)
+			fallos++
+			continue
+		}
+		enviados++
+	}
+	log.Printf("[resumen-semanal] resúmenes de coordinación encolados: %d", enviados)
+	if fallos > 0 {
+		return fmt.Errorf("%d resúmenes de coordinación no se pudieron encolar", fallos)
+	}
+	return nil
+}
+
+// ---- Instructores ----
+
+type filaPendienteResumen struct {
+	Fecha     string
+	Ficha     string
+	Aprendiz  string
+	Documento string
+}
+
+type filaTrasladoResumen struct {
+	Ficha          string
+	Detalle        string
+	OtroInstructor string
+	Motivo         string
+}
+
+type resumenInstructorData struct {
+	correoBase
+	Pendientes      []filaPendienteResumen
+	TotalPendientes int
+	Traslados       []filaTrasladoResumen
+	BotonAsistencia botonCorreo
+}
+
+// filasTrasladoInstructor describe cada traslado desde el punto de vista del instructor (cede o recibe la sesión).
+func filasTrasladoInstructor(instructorID uint, rows []repositories.TrasladoProximoRaw) []filaTrasladoResumen {
+	out := make([]filaTrasladoResumen, 0, len(rows))
+	for _, r := range rows {
+		origen, destino := r.FechaOrigen.Format("02/01/2006"), r.FechaDestino.Format("02/01/2006")
+		f := filaTrasladoResumen{Ficha: r.FichaNumero, Motivo: r.Motivo}
+		if r.InstructorOrigenID == instructorID {
+			f.Detalle = fmt.Sprintf("Cede la sesión del %s y dicta la del %s", origen, destino)
+			f.OtroInstructor = r.InstructorDestinoNombre
+		} else {
+			f.Detalle = fmt.Sprintf("Dicta la sesión del %s y cede la del %s", origen, destino)
+			f.OtroInstructor = r.InstructorOrigenNombre
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func (s *resumenSemanalService) enviarResumenesInstructor() error {
+	dest, err := s.destinatarios(models.ResumenSemanalInstructor, rolResumenSemanalInstruct)
+	if err != nil {
+		return fmt.Errorf("resumen de instructores: %w", err)
+	}
+	ahora := utils.Now()
+	hoy := time.Date(ahora.Year(), ahora.Month(), ahora.Day(), 0, 0, 0, 0, time.UTC)
+	hasta := hoy.AddDate(0, 0, diasResumenSemanal-1)
+	base := frontendURL()
+	var enviados, fallos int
+	for _, d := range dest {
+		if d.user.PersonaID == nil {
+			continue
+		}
+		inst, err := s.instRepo.FindByPersonaID(*d.user.PersonaID)
+		if err != nil || inst == nil {
+			continue
+		}
+		pendientes, err := s.repo.ListPendientesRevisionInstructor(inst.ID)
+		if err != nil {
+			return fmt.Errorf("resumen de instructores: %w", err)
+		}
+		traslados, err := s.repo.ListTrasladosProximos(inst.ID, hoy, hasta)
+		if err != nil {
+			return fmt.Errorf("resumen de instructores: %w", err)
+		}
+		if len(pendientes) == 0 && len(traslados) == 0 {
+			continue
+		}
+		datos := &resumenInstructorData{
+			correoBase: correoBase{
+				Titulo:             "Resumen semanal del instructor",
+				Periodo:            fmt.Sprintf("Semana del %s", hoy.Format("02/01/2006")),
+				Nombre:             d.nombre,
+				EnlacePreferencias: enlaceResumen(base, rutaPreferenciasResumen),
+			},
+			TotalPendientes: len(pendientes),
+			Traslados:       recortarFilasResumen(filasTrasladoInstructor(inst.ID, traslados)),
+			BotonAsistencia: botonCorreo{Texto: "Ir a asistencia", Enlace: enlaceResumen(base, rutaAsistenciaResumen)},
+		}
+		for _, p := range recortarFilasResumen(pendientes) {
+			datos.Pendientes = append(datos.Pendientes, filaPendienteResumen{
+				Fecha:     p.Fecha.Format("02/01/2006"),
+				Ficha:     p.FichaNumero,
+				Aprendiz:  p.PersonaNombre,
+				Documento: p.NumeroDocumento,
+			})
+		}
+		html, texto, err := renderCorreo(tplResumenInstructorHTML, tplResumenInstructorTexto, datos)
+		if err != nil {
+			return fmt.Errorf("resumen de instructores: %w", err)
+		}
+		correo := CorreoNuevo{
+			Para: []string{d.user.Email}, Asunto: "CDATTG Web - " + datos.Titulo,
+			Plantilla: PlantillaCorreoResumenInstructor, HTML: html, Texto: texto,
+		}
+		if err := s.correos.Encolar(correo); err != nil {
+			log.Printf("[resumen-semanal] instructor %d: %v", d.user.ID, err)
+			fallos++
+			continue
+		}
+		enviados++
+	}
+	log.Printf("[resumen-semanal] resúmenes de instructores encolados: %d", enviados)
+	if fallos > 0 {
+		return fmt.Errorf("%d resúmenes de instructores no se pudieron encolar", fallos)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+)
+
+func filaSinAsistencia(instructorID, fichaID uint, ficha string, dia int) repositories.SesionSinAsistenciaTomadaRow {
+	return repositories.SesionSinAsistenciaTomadaRow{
+		InstructorID:     instructorID,
+		InstructorNombre: map[uint]string{1: "Ana Ruiz", 2: "Luis Gómez"}[instructorID],
+		FichaID:          fichaID,
+		FichaNumero:      ficha,
+		SedeNombre:       "Sede Centro",
+		Fecha:            time.Date(2026, 3, dia, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestAgruparSinAsistenciaPorInstructor(t *testing.T) {
+	rows := []repositories.SesionSinAsistenciaTomadaRow{
+		filaSinAsistencia(1, 10, "2900001", 2),
+		filaSinAsistencia(2, 11, "2900002", 2),
+		filaSinAsistencia(2, 12, "2900003", 3),
+		filaSinAsistencia(2, 11, "2900002", 4),
+	}
+	got := agruparSinAsistenciaPorInstructor(rows)
+	if len(got) != 2 {
+		t.Fatalf("got %d instructores", len(got))
+	}
+	if got[0].Instructor != "Luis Gómez" || got[0].Sesiones != 3 || got[0].Fichas != "2900002, 2900003" {
+		t.Fatalf("primero = %+v", got[0])
+	}
+	if got[1].Instructor != "Ana Ruiz" || got[1].Sesiones != 1 {
+		t.Fatalf("segundo = %+v", got[1])
+	}
+}
+
+func TestFichasBajoCobertura(t *testing.T) {
+	rows := []repositories.SesionSinAsistenciaTomadaRow{
+		// Ficha 10: 1 incumplida de 5 -> 80 %, no entra con umbral 80.
+		filaSinAsistencia(1, 10, "2900001", 2),
+		// Ficha 11: misma sesión reportada dos veces (sesión vacía) cuenta una; 2 incumplidas y 1 tomada -> 33 %.
+		filaSinAsistencia(2, 11, "2900002", 2),
+		filaSinAsistencia(2, 11, "2900002", 2),
+		filaSinAsistencia(2, 11, "2900002", 3),
+		// Ficha 12: ninguna tomada -> 0 %.
+		filaSinAsistencia(2, 12, "2900003", 3),
+	}
+	got := fichasBajoCobertura(rows, map[uint]int{10: 4, 11: 1}, 80)
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	if got[0].Ficha != "2900003" || got[0].Pct != 0 || got[0].Esperadas != 1 {
+		t.Fatalf("primera = %+v", got[0])
+	}
+	if got[1].Ficha != "2900002" || got[1].Pct != 33 || got[1].Tomadas != 1 || got[1].Esperadas != 3 {
+		t.Fatalf("segunda = %+v", got[1])
+	}
+}
+
+func TestFilasTrasladoInstructor(t *testing.T) {
+	rows := []repositories.TrasladoProximoRaw{{
+		FichaNumero:             "2900001",
+		InstructorOrigenID:      5,
+		InstructorOrigenNombre:  "Ana Ruiz",
+		InstructorDestinoNombre: "Luis Gómez",
+		FechaOrigen:             time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		FechaDestino:            time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+	}}
+	origen := filasTrasladoInstructor(5, rows)[0]
+	if origen.OtroInstructor != "Luis Gómez" || !strings.HasPrefix(origen.Detalle, "Cede la sesión del 09/03/2026") {
+		t.Fatalf("origen = %+v", origen)
+	}
+	destino := filasTrasladoInstructor(6, rows)[0]
+	if destino.OtroInstructor != "Ana Ruiz" || !strings.HasPrefix(destino.Detalle, "Dicta la sesión del 09/03/2026") {
+		t.Fatalf("destino = %+v", destino)
+	}
+}
+
+func TestRenderResumenCoordinador_escapaYArmaAmbasVersiones(t *testing.T) {
+	datos := &resumenCoordinadorData{
+		correoBase: correoBase{
+			Titulo:             "Resumen semanal de coordinación",
+			Periodo:            "Del 02/03/2026 al 08/03/2026",
+			Nombre:             "Coordinadora <Prueba>",
+			EnlacePreferencias: "http://app/perfil",
+		},
+		SinAsistencia:      []filaSinAsistenciaResumen{{Instructor: "Ana Ruiz", Sesiones: 2, Fichas: "2900001"}},
+		TotalSinAsistencia: 2,
+		UmbralCobertura:    80,
+		CasosNuevos:        []filaCasoNuevoResumen{{ID: 7, Aprendiz: "Pedro", Ficha: "2900001", Enlace: "http://app/bienestar/seguimiento/7"}},
+	}
+	html, texto, err := renderCorreo(tplResumenCoordinadorHTML, tplResumenCoordinadorTexto, datos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<Prueba>") || !strings.Contains(html, "&lt;Prueba&gt;") {
+		t.Fatal("el nombre no se escapó en el HTML")
+	}
+	for _, want := range []string{"Ana Ruiz", "menor al 80%", "http://app/bienestar/seguimiento/7", "Ninguna ficha quedó por debajo del umbral."} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML sin %q", want)
+		}
+	}
+	if !strings.Contains(texto, "- Ana Ruiz: 2 (fichas 2900001)") || !strings.Contains(texto, "http://app/perfil") {
+		t.Fatalf("texto:\n%s", texto)
+	}
+}
+
+func TestActualizarPreferencia_tipoQueNoCorrespondeAlRol(t *testing.T) {
+	s := &resumenSemanalService{
+		rolesDeUsuario: func(uint) ([]string, error) { return []string{"INSTRUCTOR"}, nil },
+	}
+	activo := false
+	_, err := s.ActualizarPreferencia(3, dto.ActualizarResumenSemanalRequest{Tipo: models.ResumenSemanalCoordinador, Activo: &activo})
+	if !errors.Is(err, ErrResumenSemanalNoAplica) {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+const (
+	diasRiesgoDesercionDefault = 30
+	// Minutos después de la hora de inicio de la jornada a partir de los cuales el ingreso cuenta como retardo.
+	toleranciaRetardoMinutos = 15
+
+	NivelRiesgoDesercionBajo  = "bajo"
+	NivelRiesgoDesercionMedio = "medio"
+	NivelRiesgoDesercionAlto  = "alto"
+
+	umbralRiesgoDesercionMedio = 35
+	umbralRiesgoDesercionAlto  = 60
+
+	// Puntos máximos por factor (suman 100).
+	maxAporteInasistencia  = 35
+	maxAporteRacha         = 20
+	maxAporteTendencia     = 10
+	maxAporteRetardos      = 10
+	maxAporteSalidas       = 10
+	maxAporteObservaciones = 10
+	maxAporteRaps          = 5
+
+	// Vigencia del conteo de riesgo alto del dashboard: el puntaje cambia con la asistencia del día, no en cada petición.
+	vigenciaConteoRiesgoAlto = 10 * time.Minute
+)
+
+var ErrRiesgoDesercionFichaNoExiste = errors.New("ficha no encontrada")
+
+type conteoRiesgoAlto struct {
+	n      int
+	expira time.Time
+}
+
+var (
+	// conteoRiesgoAltoCache por sede (0 = todas las sedes).
+	conteoRiesgoAltoCache = map[uint]conteoRiesgoAlto{}
+	conteoRiesgoAltoMu    sync.Mutex
+)
+
+// RiesgoDesercionService puntaje de riesgo de deserción por aprendiz. Parte del mismo calendario de sesiones válidas que
+// CasosBienestarCalculator y suma retardos, salidas parciales, observaciones con peso de riesgo y RAPs no aprobados.
+type RiesgoDesercionService struct {
+	calculator *CasosBienestarCalculator
+	repo       repositories.RiesgoDesercionRepository
+	fichaRepo  repositories.FichaRepository
+}
+
+func NewRiesgoDesercionService() *RiesgoDesercionService {
+	return &RiesgoDesercionService{
+		calculator: NewCasosBienestarCalculator(),
+		repo:       repositories.NewRiesgoDesercionRepository(),
+		fichaRepo:  repositories.NewFichaRepository(),
+	}
+}
+
+// Calcular ranking de aprendices activos con puntaje mayor que cero. sedeID y fichaID son opcionales.
+func (s *RiesgoDesercionService) Calcular(sedeID, fichaID *uint, dias int) (*dto.RiesgoDesercionResponse, error) {
+	if dias <= 0 {
+		dias = diasRiesgoDesercionDefault
+	}
+	if fichaID != nil && sedeID == nil {
+		ficha, err := s.fichaRepo.FindByID(*fichaID)
+		if err != nil || ficha == nil {
+			return nil, ErrRiesgoDesercionFichaNoExiste
+		}
+		sedeID = ficha.SedeID
+	}
+
+	hoy := utils.Now()
+	fin := time.Date(hoy.Year(), hoy.Month(), hoy.Day(), 0, 0, 0, 0, time.UTC)
+	inicio := fin.AddDate(0, 0, -dias)
+	finAnterior := inicio.AddDate(0, 0, -1)
+	inicioAnterior := finAnterior.AddDate(0, 0, -dias)
+	resp := &dto.RiesgoDesercionResponse{
+		DiasAnalizados:      dias,
+		FechaInicio:         inicio.Format(time.DateOnly),
+		FechaFin:            fin.Format(time.DateOnly),
+		FechaInicioAnterior: inicioAnterior.Format(time.DateOnly),
+		FechaFinAnterior:    finAnterior.Format(time.DateOnly),
+		Aprendices:          []dto.RiesgoDesercionItem{},
+	}
+
+	actual, err := s.calculator.prepararRango(sedeID, resp.FechaInicio, resp.FechaFin)
+	if err != nil {
+		return nil, err
+	}
+	anterior, err := s.calculator.prepararRango(sedeID, resp.FechaInicioAnterior, resp.FechaFinAnterior)
+	if err != nil {
+		return nil, err
+	}
+	aprendices, err := s.calculator.repo.ListAprendicesActivosCasosBienestar(sedeID)
+	if err != nil {
+		return nil, err
+	}
+	if fichaID != nil {
+		filtrados := aprendices[:0]
+		for _, ap := range aprendices {
+			if ap.FichaID == *fichaID {
+				filtrados = append(filtrados, ap)
+			}
+		}
+		aprendices = filtrados
+	}
+	if len(aprendices) == 0 {
+		return resp, nil
+	}
+
+	asistenciaIDs := make([]uint, 0, len(actual.validaPorID))
+	for id := range actual.validaPorID {
+		asistenciaIDs = append(asistenciaIDs, id)
+	}
+	registros, err := s.repo.ListRegistrosRiesgo(asistenciaIDs)
+	if err != nil {
+		return nil, err
+	}
+	registrosPorAprendiz := make(map[uint][]repositories.RegistroRiesgoDesercionRaw)
+	for _, r := range registros {
+		registrosPorAprendiz[r.AprendizID] = append(registrosPorAprendiz[r.AprendizID], r)
+	}
+	aprendizIDs := make([]uint, len(aprendices))
+	for i, ap := range aprendices {
+		aprendizIDs[i] = ap.AprendizID
+	}
+	raps, err := s.repo.CountRapsNoAprobados(aprendizIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ap := range aprendices {
+		f := dto.RiesgoDesercionFactores{RapsNoAprobados: raps[ap.AprendizID]}
+		f.TotalSesiones, f.Inasistencias, f.InasistenciasJustificadas, f.FallasConsecutivas =
+			resumenSlotsAprendiz(ap.AprendizID, actual.slotsPorFicha[ap.FichaID], actual.asistio, actual.justificada)
+		f.PctInasistencia = pctInasistenciaRiesgo(f.TotalSesiones, f.Inasistencias, f.InasistenciasJustificadas)
+		if total, fallas, justificadas, _ := resumenSlotsAprendiz(
+			ap.AprendizID, anterior.slotsPorFicha[ap.FichaID], anterior.asistio, anterior.justificada,
+		); total > 0 {
+			pct := pctInasistenciaRiesgo(total, fallas, justificadas)
+			f.PctInasistenciaAnterior = &pct
+		}
+		asistidas := acumularRegistrosRiesgo(&f, registrosPorAprendiz[ap.AprendizID])
+
+		puntaje, aportes := puntajeRiesgoDesercion(f, asistidas)
+		if puntaje == 0 {
+			continue
+		}
+		resp.Aprendices = append(resp.Aprendices, dto.RiesgoDesercionItem{
+			AprendizID:      ap.AprendizID,
+			PersonaNombre:   ap.PersonaNombre,
+			NumeroDocumento: ap.NumeroDocumento,
+			FichaID:         ap.FichaID,
+			FichaNumero:     ap.FichaNumero,
+			ProgramaNombre:  ap.ProgramaNombre,
+			SedeNombre:      ap.SedeNombre,
+			JornadaNombre:   ap.JornadaNombre,
+			Puntaje:         puntaje,
+			Nivel:           nivelRiesgoDesercion(puntaje),
+			Factores:        f,
+			Aportes:         aportes,
+		})
+	}
+	sort.SliceStable(resp.Aprendices, func(i, j int) bool {
+		a, b := resp.Aprendices[i], resp.Aprendices[j]
+		if a.Puntaje != b.Puntaje {
+			return a.Puntaje > b.Puntaje
+		}
+		if a.Factores.Inasistencias != b.Factores.Inasistencias {
+			return a.Factores.Inasistencias > b.Factores.Inasistencias
+		}
+		return a.AprendizID < b.AprendizID
+	})
+	return resp, nil
+}
+
+// ContarAlto aprendices con nivel de riesgo alto en la ventana por defecto (para el resumen del dashboard).
+// El resultado se guarda por sede durante vigenciaConteoRiesgoAlto para no recalcular el ranking en cada petición.
+func (s *RiesgoDesercionService) ContarAlto(sedeID *uint) (int, error) {
+	var clave uint
+	if sedeID != nil {
+		clave = *sedeID
+	}
+	return conteoRiesgoAltoCacheado(clave, utils.Now(), func() (int, error) {
+		resp, err := s.Calcular(sedeID, nil, diasRiesgoDesercionDefault)
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for i := range resp.Aprendices {
+			if resp.Aprendices[i].Nivel == NivelRiesgoDesercionAlto {
+				n++
+			}
+		}
+		return n, nil
+	})
+}
+
+// ContarAltoSedes suma ContarAlto por sede (un aprendiz pertenece a la sede de su ficha, no hay duplicados).
+func (s *RiesgoDesercionService) ContarAltoSedes(sedeIDs []uint) (int, error) {
+	total := 0
+	for _, sid := range sedeIDs {
+		id := sid
+		n, err := s.ContarAlto(&id)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func conteoRiesgoAltoCacheado(clave uint, ahora time.Time, calcular func() (int, error)) (int, error) {
+	conteoRiesgoAltoMu.Lock()
+	c, ok := conteoRiesgoAltoCache[clave]
+	conteoRiesgoAltoMu.Unlock()
+	if ok && ahora.Before(c.expira) {
+		return c.n, nil
+	}
+	n, err := calcular()
+	if err != nil {
+		return 0, err
+	}
+	conteoRiesgoAltoMu.Lock()
+	conteoRiesgoAltoCache[clave] = conteoRiesgoAlto{n: n, expira: ahora.Add(vigenciaConteoRiesgoAlto)}
+	conteoRiesgoAltoMu.Unlock()
+	return n, nil
+}
+
+// resumenSlotsAprendiz recorre los días de formación en orden: sesiones, fallas sin justificar, justificadas y
+// la racha de fallas sin justificar al final del periodo (una justificada no corta ni alarga la racha).
+func resumenSlotsAprendiz(
+	aprendizID uint,
+	slots []sesionDiaBienestar,
+	asistio, justificada map[uint]map[uint]bool,
+) (total, fallas, justificadas, racha int) {
+	for _, slot := range slots {
+		total++
+		switch {
+		case aprendizAsistioEnSlot(aprendizID, slot, asistio):
+			racha = 0
+		case aprendizJustificadoEnSlot(aprendizID, slot, justificada, nil):
+			justificadas++
+		default:
+			fallas++
+			racha++
+		}
+	}
+	return total, fallas, justificadas, racha
+}
+
+// pctInasistenciaRiesgo fallas sin justificar sobre las sesiones que cuentan (sin las justificadas), con un decimal.
+func pctInasistenciaRiesgo(total, fallas, justificadas int) float64 {
+	base := total - justificadas
+	if base <= 0 {
+		return 0
+	}
+	return math.Round(float64(fallas)*1000/float64(base)) / 10
+}
+
+// acumularRegistrosRiesgo suma retardos, salidas parciales y peso de observaciones; devuelve las sesiones con ingreso.
+func acumularRegistrosRiesgo(f *dto.RiesgoDesercionFactores, registros []repositories.RegistroRiesgoDesercionRaw) int {
+	asistidas := 0
+	for _, r := range registros {
+		f.PesoObservaciones += r.PesoObservaciones
+		if r.HoraIngreso == nil {
+			continue
+		}
+		asistidas++
+		if esRetardoJornada(*r.HoraIngreso, r.JornadaHoraInicio) {
+			f.Retardos++
+		}
+		if r.Estado == "ASISTENCIA_PARCIAL" || r.Estado == "ABANDONO_JORNADA" {
+			f.SalidasParciales++
+		}
+	}
+	return asistidas
+}
+
+// esRetardoJornada ingreso posterior a la hora de inicio de la jornada más la tolerancia. Sin jornada no hay retardo.
+func esRetardoJornada(ingreso time.Time, jornadaHoraInicio string) bool {
+	inicio, err := parseHora(jornadaHoraInicio)
+	if err != nil {
+		return false
+	}
+	local := ingreso.In(utils.AppLocation())
+	return local.Hour()*60+local.Minute() > inicio.Hour()*60+inicio.Minute()+toleranciaRetardoMinutos
+}
+
+func topeRiesgo(v, max int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// proporcionRiesgo puntos por la proporción n/base: la mitad de las sesiones ya da el máximo.
+func proporcionRiesgo(n, base, max int) int {
+	if base <= 0 || n <= 0 {
+		return 0
+	}
+	return topeRiesgo(int(math.Round(float64(n)*float64(2*max)/float64(base))), max)
+}
+
+// puntajeRiesgoDesercion combina los factores en un puntaje de 0 a 100. asistidas: sesiones con ingreso registrado.
+func puntajeRiesgoDesercion(f dto.RiesgoDesercionFactores, asistidas int) (int, dto.RiesgoDesercionAportes) {
+	a := dto.RiesgoDesercionAportes{
+		Inasistencia:       topeRiesgo(int(math.Round(f.PctInasistencia*maxAporteInasistencia/100)), maxAporteInasistencia),
+		FallasConsecutivas: topeRiesgo(f.FallasConsecutivas*5, maxAporteRacha),
+		Retardos:           proporcionRiesgo(f.Retardos, asistidas, maxAporteRetardos),
+		SalidasParciales:   proporcionRiesgo(f.SalidasParciales, asistidas, maxAporteSalidas),
+		Observaciones:      topeRiesgo(f.PesoObservaciones*2, maxAporteObservaciones),
+		RapsNoAprobados:    topeRiesgo(f.RapsNoAprobados*2, maxAporteRaps),
+	}
+	// Tendencia: cada 4 puntos porcentuales de aumento frente al periodo anterior suman 1 punto.
+	if f.PctInasistenciaAnterior != nil && f.PctInasistencia > *f.PctInasistenciaAnterior {
+		a.Tendencia = topeRiesgo(int(math.Round((f.PctInasistencia-*f.PctInasistenciaAnterior)/4)), maxAporteTendencia)
+	}
+	total := a.Inasistencia + a.FallasConsecutivas + a.Tendencia + a.Retardos + a.SalidasParciales + a.Observaciones + a.RapsNoAprobados
+	return topeRiesgo(total, 100), a
+}
+
+func nivelRiesgoDesercion(puntaje int) string {
+	switch {
+	case puntaje >= umbralRiesgoDesercionAlto:
+		return NivelRiesgoDesercionAlto
+	case puntaje >= umbralRiesgoDesercionMedio:
+		return NivelRiesgoDesercionMedio
+	default:
+		return NivelRiesgoDesercionBajo
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/utils"
+)
+
+func slotsRiesgo(ids ...uint) []sesionDiaBienestar {
+	d := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	out := make([]sesionDiaBienestar, len(ids))
+	for i, id := range ids {
+		out[i] = sesionDiaBienestar{Fecha: d.AddDate(0, 0, i), InstructorID: 1, AsistenciaIDs: []uint{id}}
+	}
+	return out
+}
+
+func TestResumenSlotsAprendiz_RachaFinal(t *testing.T) {
+	// Sesiones 1..6: asiste 1 y 3, falla 2, falla 4, justificada 5, falla 6 -> racha final 2 (la justificada no la corta).
+	asistio := map[uint]map[uint]bool{9: {1: true, 3: true}}
+	justificada := map[uint]map[uint]bool{9: {5: true}}
+	total, fallas, justificadas, racha := resumenSlotsAprendiz(9, slotsRiesgo(1, 2, 3, 4, 5, 6), asistio, justificada)
+	if total != 6 || fallas != 3 || justificadas != 1 || racha != 2 {
+		t.Fatalf("got total=%d fallas=%d justificadas=%d racha=%d", total, fallas, justificadas, racha)
+	}
+	if pct := pctInasistenciaRiesgo(total, fallas, justificadas); pct != 60 {
+		t.Fatalf("pct = %v, want 60", pct)
+	}
+}
+
+func TestEsRetardoJornada(t *testing.T) {
+	loc := utils.AppLocation()
+	cases := []struct {
+		hora, minuto int
+		jornada      string
+		want         bool
+	}{
+		{7, 15, "07:00", false},
+		{7, 16, "07:00", true},
+		{6, 50, "07:00", false},
+		{9, 0, "", false},
+	}
+	for _, tc := range cases {
+		ingreso := time.Date(2026, 3, 2, tc.hora, tc.minuto, 0, 0, loc)
+		if got := esRetardoJornada(ingreso, tc.jornada); got != tc.want {
+			t.Errorf("%02d:%02d vs %q: got %v, want %v", tc.hora, tc.minuto, tc.jornada, got, tc.want)
+		}
+	}
+}
+
+func TestAcumularRegistrosRiesgo(t *testing.T) {
+	loc := utils.AppLocation()
+	tarde := time.Date(2026, 3, 2, 7, 40, 0, 0, loc)
+	aTiempo := time.Date(2026, 3, 3, 7, 0, 0, 0, loc)
+	var f dto.RiesgoDesercionFactores
+	asistidas := acumularRegistrosRiesgo(&f, []repositories.RegistroRiesgoDesercionRaw{
+		{HoraIngreso: &tarde, Estado: "ASISTENCIA_PARCIAL", JornadaHoraInicio: "07:00", PesoObservaciones: 1},
+		{HoraIngreso: &aTiempo, Estado: "ASISTENCIA_COMPLETA", JornadaHoraInicio: "07:00"},
+		{Estado: "", JornadaHoraInicio: "07:00", PesoObservaciones: 3},
+	})
+	if asistidas != 2 || f.Retardos != 1 || f.SalidasParciales != 1 || f.PesoObservaciones != 4 {
+		t.Fatalf("asistidas=%d factores=%+v", asistidas, f)
+	}
+}
+
+func TestPuntajeRiesgoDesercion(t *testing.T) {
+	anterior := 10.0
+	f := dto.RiesgoDesercionFactores{
+		PctInasistencia:         50,
+		PctInasistenciaAnterior: &anterior,
+		FallasConsecutivas:      3,
+		Retardos:                1,
+		SalidasParciales:        0,
+		PesoObservaciones:       3,
+		RapsNoAprobados:         1,
+	}
+	puntaje, a := puntajeRiesgoDesercion(f, 4)
+	want := dto.RiesgoDesercionAportes{
+		Inasistencia:       18, // 50% de 35
+		FallasConsecutivas: 15,
+		Tendencia:          10, // +40 puntos porcentuales
+		Retardos:           5,  // 1 de 4 sesiones
+		Observaciones:      6,
+		RapsNoAprobados:    2,
+	}
+	if a != want {
+		t.Fatalf("aportes = %+v, want %+v", a, want)
+	}
+	if puntaje != 56 || nivelRiesgoDesercion(puntaje) != NivelRiesgoDesercionMedio {
+		t.Fatalf("puntaje = %d (%s)", puntaje, nivelRiesgoDesercion(puntaje))
+	}
+
+	sinRiesgo, _ := puntajeRiesgoDesercion(dto.RiesgoDesercionFactores{}, 10)
+	if sinRiesgo != 0 || nivelRiesgoDesercion(sinRiesgo) != NivelRiesgoDesercionBajo {
+		t.Fatalf("sin factores: puntaje = %d", sinRiesgo)
+	}
+
+	maximo, _ := puntajeRiesgoDesercion(dto.RiesgoDesercionFactores{
+		PctInasistencia: 100, FallasConsecutivas: 10, Retardos: 5, SalidasParciales: 5, PesoObservaciones: 10, RapsNoAprobados: 5,
+	}, 5)
+	if maximo != 90 || nivelRiesgoDesercion(maximo) != NivelRiesgoDesercionAlto {
+		t.Fatalf("máximo sin tendencia = %d", maximo)
+	}
+}
+
+func TestConteoRiesgoAltoCacheado(t *testing.T) {
+	const clave = 999001
+	t.Cleanup(func() {
+		conteoRiesgoAltoMu.Lock()
+		delete(conteoRiesgoAltoCache, clave)
+		conteoRiesgoAltoMu.Unlock()
+	})
+	llamadas := 0
+	calcular := func() (int, error) {
+		llamadas++
+		return llamadas, nil
+	}
+	ahora := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	if n, _ := conteoRiesgoAltoCacheado(clave, ahora, calcular); n != 1 {
+		t.Fatalf("primer conteo = %d", n)
+	}
+	if n, _ := conteoRiesgoAltoCacheado(clave, ahora.Add(vigenciaConteoRiesgoAlto-time.Second), calcular); n != 1 || llamadas != 1 {
+		t.Fatalf("dentro de la vigencia: n = %d, llamadas = %d", n, llamadas)
+	}
+	if n, _ := conteoRiesgoAltoCacheado(clave, ahora.Add(vigenciaConteoRiesgoAlto), calcular); n != 2 {
+		t.Fatalf("vencido: n = %d", n)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sena/cdattg-web-golang/dto"
+	"github.com/sena/cdattg-web-golang/models"
+	"github.com/sena/cdattg-web-golang/repositories"
+	"github.com/sena/cdattg-web-golang/scheduler"
+)
+
+// TareaProgramadaService expone el planificador y su historial a la administración.
+type TareaProgramadaService interface {
+	List() (*dto.TareasProgramadasResponse, error)
+	ListEjecuciones(nombre string, limit int) ([]dto.TareaProgramadaEjecucionResponse, error)
+	Ejecutar(ctx context.Context, nombre string, userID uint) (*dto.TareaProgramadaEjecucionResponse, error)
+}
+
+type tareaProgramadaService struct {
+	sched *scheduler.Scheduler
+	repo  repositories.TareaProgramadaRepository
+}
+
+func NewTareaProgramadaService() TareaProgramadaService {
+	return &tareaProgramadaService{
+		sched: scheduler.Default(),
+		repo:  repositories.NewTareaProgramadaRepository(),
+	}
+}
+
+// ExpresionAutoCierreAsistencia expresión del auto-cierre según el intervalo configurado en configuracion_asistencia.
+func ExpresionAutoCierreAsistencia() string {
+	return fmt.Sprintf("@every %dm", IntervaloAutoCierreMinutos())
+}
+
+func tareaEjecucionToDTO(e *models.TareaProgramadaEjecucion) dto.TareaProgramadaEjecucionResponse {
+	return dto.TareaProgramadaEjecucionResponse{
+		ID:           e.ID,
+		Tarea:        e.Tarea,
+		Origen:       e.Origen,
+		Instancia:    e.Instancia,
+		UserID:       e.UserID,
+		IniciadaAt:   e.IniciadaAt,
+		FinalizadaAt: e.FinalizadaAt,
+		DuracionMs:   e.DuracionMs,
+		Exitosa:      e.Exitosa,
+		Error:        e.Error,
+	}
+}
+
+func (s *tareaProgramadaService) List() (*dto.TareasProgramadasResponse, error) {
+	ultimas, err := s.repo.FindUltimasPorTarea()
+	if err != nil {
+		return nil, err
+	}
+	tareas := s.sched.Tareas()
+	out := &dto.TareasProgramadasResponse{
+		InstanciaLider: s.sched.EsLider(),
+		Tareas:         make([]dto.TareaProgramadaResponse, len(tareas)),
+	}
+	for i, t := range tareas {
+		item := dto.TareaProgramadaResponse{
+			Nombre:           t.Nombre,
+			Descripcion:      t.Descripcion,
+			Expresion:        t.Expresion,
+			ProximaEjecucion: t.ProximaEjecucion,
+			EnCurso:          t.EnCurso,
+		}
+		if u, ok := ultimas[t.Nombre]; ok {
+			ult := tareaEjecucionToDTO(&u)
+			item.UltimaEjecucion = &ult
+		}
+		out.Tareas[i] = item
+	}
+	return out, nil
+}
+
+func (s *tareaProgramadaService) ListEjecuciones(nombre string, limit int) ([]dto.TareaProgramadaEjecucionResponse, error) {
+	list, err := s.repo.ListEjecuciones(nombre, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.TareaProgramadaEjecucionResponse, len(list))
+	for i := range list {
+		out[i] = tareaEjecucionToDTO(&list[i])
+	}
+	return out, nil
+}
+
+func (s *tareaProgramadaService) Ejecutar(ctx context.Context, nombre string, userID uint) (*dto.TareaProgramadaEjecucionResponse, error) {
+	ej, err := s.sched.Trigger(ctx, nombre, &userID)
+	if err != nil {
+		return nil, err
+	}
+	out := tareaEjecucionToDTO(ej)
+	return &out, nil
+}
@@ -105,6 +105,7 @@ func appendEventosDiaFicha(
 			ProgramaNombre:      ctx.progNombre,
 			SedeNombre:          ctx.sedeNombre,
 			AmbienteNombre:      ctx.ambienteNombre,
+			JornadaNombre:       ctx.jornadaNombre,
 			InstructorID:        asg.InstructorID,
 			InstructorNombre:    ctx.instNombre,
 			InstructorDocumento: ctx.instDoc,
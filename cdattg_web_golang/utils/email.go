@@ -2,46 +2,200 @@ package utils
 
 import (
 	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
 	"net/smtp"
+	"net/textproto"
 	"strings"
+	"time"
 
 	"github.com/sena/cdattg-web-golang/config"
 )
 
+// ErrSMTPDeshabilitado SMTP sin host o con SMTP_ENABLED=false.
+var ErrSMTPDeshabilitado = errors.New("SMTP no configurado o deshabilitado")
+
+// AdjuntoCorreo archivo adjunto de un mensaje (p. ej. el PDF de una sesión de asistencia).
+type AdjuntoCorreo struct {
+	Nombre      string
+	ContentType string
+	Contenido   []byte
+}
+
+// MensajeCorreo correo a enviar. Texto es obligatorio; si hay HTML se envía como multipart/alternative.
+type MensajeCorreo struct {
+	Para     []string
+	Asunto   string
+	HTML     string
+	Texto    string
+	Adjuntos []AdjuntoCorreo
+}
+
+// SMTPHabilitado indica si hay servidor SMTP configurado y activo.
+func SMTPHabilitado() bool {
+	return config.AppConfig != nil && config.AppConfig.SMTP.Enabled && config.AppConfig.SMTP.Host != ""
+}
+
 // SendMail envía un correo a los destinatarios usando la config SMTP.
 // Si SMTP no está habilitado, no hace nada y devuelve nil.
 func SendMail(to []string, subject, bodyPlain string) error {
-	cfg := config.AppConfig.SMTP
-	if !cfg.Enabled || cfg.Host == "" {
+	return enviarSiHabilitado(MensajeCorreo{Para: to, Asunto: subject, Texto: bodyPlain})
+}
+
+// SendMailHTML envía un correo multipart/alternative con versión HTML y versión en texto plano
+// (la que muestran los clientes que no renderizan HTML). Misma config SMTP que SendMail.
+func SendMailHTML(to []string, subject, bodyHTML, bodyPlain string) error {
+	return enviarSiHabilitado(MensajeCorreo{Para: to, Asunto: subject, HTML: bodyHTML, Texto: bodyPlain})
+}
+
+func enviarSiHabilitado(m MensajeCorreo) error {
+	err := EnviarMensaje(m)
+	if errors.Is(err, ErrSMTPDeshabilitado) {
 		log.Println("SMTP no configurado o deshabilitado; no se envía correo")
 		return nil
 	}
-	if len(to) == 0 {
+	return err
+}
+
+// EnviarMensaje envía el mensaje por SMTP de inmediato. A diferencia de SendMail, devuelve ErrSMTPDeshabilitado
+// si no hay servidor configurado (la cola de salida lo usa para no contar el intento).
+func EnviarMensaje(m MensajeCorreo) error {
+	if !SMTPHabilitado() {
+		return ErrSMTPDeshabilitado
+	}
+	if len(m.Para) == 0 {
 		return nil
 	}
-
+	cfg := config.AppConfig.SMTP
 	from := cfg.From
 	if from == "" {
 		from = cfg.User
 	}
-
-	msg := bytes.NewBuffer(nil)
-	fmt.Fprintf(msg, "From: %s\r\n", from)
-	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(to, ", "))
-	fmt.Fprintf(msg, "Subject: %s\r\n", subject)
-	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
-	msg.WriteString("\r\n")
-	msg.WriteString(bodyPlain)
-
+	raw, err := ConstruirMensajeMIME(from, m, time.Now())
+	if err != nil {
+		return err
+	}
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 	var auth smtp.Auth
 	if cfg.User != "" && cfg.Password != "" {
 		auth = smtp.PlainAuth("", cfg.User, cfg.Password, cfg.Host)
 	}
-	if err := smtp.SendMail(addr, auth, from, to, msg.Bytes()); err != nil {
+	if err := smtp.SendMail(addr, auth, from, m.Para, raw); err != nil {
 		return fmt.Errorf("enviar correo: %w", err)
 	}
 	return nil
 }
+
+// ConstruirMensajeMIME arma el mensaje completo (encabezados y cuerpo):
+// text/plain solo, multipart/alternative con HTML, y multipart/mixed cuando hay adjuntos.
+func ConstruirMensajeMIME(from string, m MensajeCorreo, fecha time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(m.Para, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", m.Asunto))
+	fmt.Fprintf(&buf, "Date: %s\r\n", fecha.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(m.Adjuntos) == 0 {
+		if err := escribirCuerpoCorreo(&buf, nil, m); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mixed.Boundary())
+	if err := escribirCuerpoCorreo(&buf, mixed, m); err != nil {
+		return nil, err
+	}
+	for _, a := range m.Adjuntos {
+		ct := a.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", mime.FormatMediaType(ct, map[string]string{"name": a.Nombre}))
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Nombre}))
+		h.Set("Content-Transfer-Encoding", "base64")
+		w, err := mixed.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if err := escribirBase64Lineas(w, a.Contenido); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escribirCuerpoCorreo escribe el texto (y el HTML si lo hay) como parte de padre o, sin padre, como cuerpo en dst.
+func escribirCuerpoCorreo(dst io.Writer, padre *multipart.Writer, m MensajeCorreo) error {
+	if m.HTML == "" {
+		return escribirParteTexto(dst, padre, "text/plain; charset=UTF-8", m.Texto)
+	}
+	limite := multipart.NewWriter(io.Discard).Boundary()
+	contentType := fmt.Sprintf("multipart/alternative; boundary=%q", limite)
+	if padre == nil {
+		fmt.Fprintf(dst, "Content-Type: %s\r\n\r\n", contentType)
+	} else {
+		w, err := padre.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+		if err != nil {
+			return err
+		}
+		dst = w
+	}
+	alt := multipart.NewWriter(dst)
+	if err := alt.SetBoundary(limite); err != nil {
+		return err
+	}
+	if err := escribirParteTexto(nil, alt, "text/plain; charset=UTF-8", m.Texto); err != nil {
+		return err
+	}
+	if err := escribirParteTexto(nil, alt, "text/html; charset=UTF-8", m.HTML); err != nil {
+		return err
+	}
+	return alt.Close()
+}
+
+// escribirParteTexto escribe contenido quoted-printable como parte de padre o, sin padre, como cuerpo en dst.
+func escribirParteTexto(dst io.Writer, padre *multipart.Writer, contentType, contenido string) error {
+	if padre == nil {
+		fmt.Fprintf(dst, "Content-Type: %s\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", contentType)
+	} else {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", contentType)
+		h.Set("Content-Transfer-Encoding", "quoted-printable")
+		w, err := padre.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		dst = w
+	}
+	qp := quotedprintable.NewWriter(dst)
+	if _, err := qp.Write([]byte(contenido)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// escribirBase64Lineas codifica en base64 con líneas de 76 caracteres (RFC 2045).
+func escribirBase64Lineas(w io.Writer, data []byte) error {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		if _, err := fmt.Fprintf(w, "%s\r\n", enc[:76]); err != nil {
+			return err
+		}
+		enc = enc[76:]
+	}
+	_, err := fmt.Fprintf(w, "%s\r\n", enc)
+	return err
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/sena/cdattg-web-golang/config"
+)
+
+// smtpDePrueba servidor SMTP mínimo en 127.0.0.1 que acepta todo y guarda el DATA de cada mensaje.
+type smtpDePrueba struct {
+	ln       net.Listener
+	mensajes chan []byte
+	// rechazarRcpt responde 550 a RCPT TO (simula un buzón inexistente).
+	rechazarRcpt bool
+}
+
+func nuevoSMTPDePrueba(t *testing.T, rechazarRcpt bool) *smtpDePrueba {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpDePrueba{ln: ln, mensajes: make(chan []byte, 4), rechazarRcpt: rechazarRcpt}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.atender()
+	return s
+}
+
+func (s *smtpDePrueba) puerto() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *smtpDePrueba) atender() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.sesion(conn)
+	}
+}
+
+func (s *smtpDePrueba) sesion(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	responder := func(linea string) { fmt.Fprintf(conn, "%s\r\n", linea) }
+	responder("220 prueba ESMTP")
+	for {
+		linea, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(linea))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			responder("250 prueba")
+		case strings.HasPrefix(cmd, "RCPT") && s.rechazarRcpt:
+			responder("550 buzón no existe")
+		case strings.HasPrefix(cmd, "DATA"):
+			responder("354 fin con <CRLF>.<CRLF>")
+			var data bytes.Buffer
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.mensajes <- data.Bytes()
+			responder("250 encolado")
+		case strings.HasPrefix(cmd, "QUIT"):
+			responder("221 adiós")
+			return
+		default:
+			responder("250 ok")
+		}
+	}
+}
+
+func withSMTPConfig(t *testing.T, puerto int) {
+	t.Helper()
+	prev := config.AppConfig
+	config.AppConfig = &config.Config{SMTP: config.SMTPConfig{Host: "127.0.0.1", Port: puerto, From: "noreply@sena.edu.co", Enabled: true}}
+	t.Cleanup(func() { config.AppConfig = prev })
+}
+
+func TestEnviarMensaje_htmlConAdjunto(t *testing.T) {
+	srv := nuevoSMTPDePrueba(t, false)
+	withSMTPConfig(t, srv.puerto())
+
+	pdf := bytes.Repeat([]byte("%PDF-1.4 contenido "), 20)
+	err := EnviarMensaje(MensajeCorreo{
+		Para:     []string{"instructor@sena.edu.co"},
+		Asunto:   "Reporte de asistencia – ficha 2900001",
+		HTML:     "<p>Sesión finalizada</p>",
+		Texto:    "Sesión finalizada",
+		Adjuntos: []AdjuntoCorreo{{Nombre: "asistencia.pdf", ContentType: "application/pdf", Contenido: pdf}},
+	})
+	if err != nil {
+		t.Fatalf("EnviarMensaje: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(<-srv.mensajes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	asunto, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if asunto != "Reporte de asistencia – ficha 2900001" {
+		t.Fatalf("asunto = %q", asunto)
+	}
+	mt, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mt != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q", mt)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+
+	cuerpo, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt, altParams, _ := mime.ParseMediaType(cuerpo.Header.Get("Content-Type"))
+	if mt != "multipart/alternative" {
+		t.Fatalf("primera parte = %q", mt)
+	}
+	alt := multipart.NewReader(cuerpo, altParams["boundary"])
+	var tipos []string
+	for {
+		p, err := alt.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(p) // NextPart decodifica quoted-printable
+		tipos = append(tipos, p.Header.Get("Content-Type")+"|"+string(b))
+	}
+	if len(tipos) != 2 || !strings.HasPrefix(tipos[0], "text/plain") || !strings.Contains(tipos[1], "<p>Sesión finalizada</p>") {
+		t.Fatalf("alternativas = %q", tipos)
+	}
+
+	adj, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adj.FileName() != "asistencia.pdf" {
+		t.Fatalf("adjunto = %q", adj.FileName())
+	}
+	raw, _ := io.ReadAll(adj)
+	dec := make([]byte, len(raw))
+	n, err := base64.StdEncoding.Decode(dec, raw)
+	if err != nil || !bytes.Equal(dec[:n], pdf) {
+		t.Fatalf("contenido del adjunto distinto (err=%v)", err)
+	}
+}
+
+func TestEnviarMensaje_errorDelServidor(t *testing.T) {
+	srv := nuevoSMTPDePrueba(t, true)
+	withSMTPConfig(t, srv.puerto())
+
+	err := EnviarMensaje(MensajeCorreo{Para: []string{"nadie@sena.edu.co"}, Asunto: "x", Texto: "x"})
+	if err == nil || !strings.Contains(err.Error(), "550") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestEnviarMensaje_smtpDeshabilitado(t *testing.T) {
+	prev := config.AppConfig
+	config.AppConfig = &config.Config{}
+	t.Cleanup(func() { config.AppConfig = prev })
+
+	if err := EnviarMensaje(MensajeCorreo{Para: []string{"a@b.co"}, Texto: "x"}); err != ErrSMTPDeshabilitado {
+		t.Fatalf("EnviarMensaje err = %v", err)
+	}
+	if err := SendMail([]string{"a@b.co"}, "x", "x"); err != nil {
+		t.Fatalf("SendMail debe ignorar SMTP deshabilitado: %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -9,20 +13,32 @@ import (
 )
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	SessionID uint   `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, email string) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(config.AppConfig.JWT.ExpirationHours) * time.Hour)
+// AccessTokenTTL vida de los access tokens (JWT_ACCESS_TOKEN_MINUTES).
+func AccessTokenTTL() time.Duration {
+	return time.Duration(config.AppConfig.JWT.AccessTokenMinutes) * time.Minute
+}
+
+// RefreshTokenTTL vida de los refresh tokens (JWT_REFRESH_TOKEN_DAYS).
+func RefreshTokenTTL() time.Duration {
+	return time.Duration(config.AppConfig.JWT.RefreshTokenDays) * 24 * time.Hour
+}
 
+// GenerateToken emite un access token de vida corta ligado a la sesión (dispositivo) sessionID.
+func GenerateToken(userID uint, email string, sessionID uint) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -49,3 +65,18 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// GenerateRefreshToken crea un refresh token opaco (256 bits aleatorios, base64 URL).
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken hash con el que se guarda el refresh token en BD.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sena/cdattg-web-golang/config"
+)
+
+func withJWTConfig(t *testing.T) {
+	t.Helper()
+	prev := config.AppConfig
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{Secret: "test-secret", AccessTokenMinutes: 15, RefreshTokenDays: 30}}
+	t.Cleanup(func() { config.AppConfig = prev })
+}
+
+func TestGenerateTokenIncluyeSesionYVidaCorta(t *testing.T) {
+	withJWTConfig(t)
+	tok, err := GenerateToken(7, "a@b.co", 42)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	claims, err := ValidateToken(tok)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.UserID != 7 || claims.SessionID != 42 {
+		t.Fatalf("claims inesperados: %+v", claims)
+	}
+	if vida := claims.ExpiresAt.Sub(claims.IssuedAt.Time); vida != 15*time.Minute {
+		t.Fatalf("vida del access token: got %v, want 15m", vida)
+	}
+}
+
+func TestRefreshTokenAleatorioYHashEstable(t *testing.T) {
+	a, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateRefreshToken()
+	if a == b || len(a) < 40 {
+		t.Fatalf("refresh tokens no aleatorios o cortos: %q %q", a, b)
+	}
+	if HashRefreshToken(a) != HashRefreshToken(a) || HashRefreshToken(a) == HashRefreshToken(b) {
+		t.Fatal("el hash debe ser determinista y distinto por token")
+	}
+	if len(HashRefreshToken(a)) != 64 {
+		t.Fatal("el hash debe caber en refresh_token_hash (64)")
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	_ "embed"
+
+	"github.com/jung-kurt/gofpdf/v2"
+)
+
+// PDFFuente familia TrueType (DejaVu Sans Condensed) registrada por NewPDF, con estilos "" y "B".
+const PDFFuente = "DejaVu"
+
+//go:embed fonts/DejaVuSansCondensed.ttf
+var fuenteDejaVu []byte
+
+//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+var fuenteDejaVuBold []byte
+
+// NewPDF documento A4 en mm con la fuente UTF-8 embebida, para imprimir tildes y ñ sin normalizar el texto.
+// orientacion: "P" (vertical) o "L" (horizontal).
+func NewPDF(orientacion string) *gofpdf.Fpdf {
+	pdf := gofpdf.New(orientacion, "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(PDFFuente, "", fuenteDejaVu)
+	pdf.AddUTF8FontFromBytes(PDFFuente, "B", fuenteDejaVuBold)
+	return pdf
+}
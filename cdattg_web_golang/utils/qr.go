@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// margenQR módulos en blanco alrededor del código (zona de silencio que exige ISO/IEC 18004).
+const margenQR = 4
+
+// QRSVG SVG del código QR (corrección de errores nivel M) con un margen de 4 módulos; cada módulo mide tamModulo
+// unidades del viewBox.
+func QRSVG(texto string, tamModulo int) (string, error) {
+	q, err := qrcode.New(texto, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	q.DisableBorder = true
+	m := q.Bitmap()
+	if tamModulo < 1 {
+		tamModulo = 1
+	}
+	total := (len(m) + 2*margenQR) * tamModulo
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, total, total)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, total, total)
+	for y, fila := range m {
+		for x, oscuro := range fila {
+			if oscuro {
+				fmt.Fprintf(&b, "M%d %dh%dv%dh-%dz", (x+margenQR)*tamModulo, (y+margenQR)*tamModulo, tamModulo, tamModulo, tamModulo)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.String(), nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestQRSVG(t *testing.T) {
+	t.Parallel()
+	svg, err := QRSVG("ASIST-12345-abcdef", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Versión 2 con nivel M para 18 bytes: 25 módulos más el margen de 4 por lado.
+	viewBox := fmt.Sprintf(`viewBox="0 0 %d %d"`, (25+2*margenQR)*4, (25+2*margenQR)*4)
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, viewBox) || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatalf("svg = %.200s", svg)
+	}
+	// El patrón de posición superior izquierdo empieza en la esquina del margen.
+	if !strings.Contains(svg, fmt.Sprintf("M%d %dh4v4h-4z", margenQR*4, margenQR*4)) {
+		t.Error("falta el patrón de posición en la esquina superior izquierda")
+	}
+}